@@ -45,7 +45,8 @@ func InitApp() (*App, error) {
 	}
 	serviceService := service.NewService(attributeRepository, attributeGroupRepository, fieldSecureAttrChangeEventProducer, iFieldDeleteEventProducer)
 	crypto := ioc.InitCrypto()
-	service6 := service2.NewService(resourceRepository, serviceService, crypto)
+	searchIndex := ioc.InitSearchIndex(resourceDAO)
+	service6 := service2.NewService(resourceRepository, serviceService, crypto, searchIndex)
 	relationModelDAO := dao.NewRelationModelDAO(db)
 	relationModelRepository := repository.NewRelationModelRepository(relationModelDAO)
 	relationResourceDAO := dao.NewRelationResourceDAO(db)
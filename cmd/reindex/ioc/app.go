@@ -0,0 +1,9 @@
+package ioc
+
+import (
+	resourceSvc "github.com/Duke1616/ecmdb/internal/service/resource"
+)
+
+type App struct {
+	ResourceSvc resourceSvc.Service
+}
@@ -0,0 +1,18 @@
+//go:build wireinject
+
+package ioc
+
+import (
+	"github.com/Duke1616/ecmdb/ioc"
+	"github.com/google/wire"
+)
+
+func InitApp() (*App, error) {
+	wire.Build(
+		wire.Struct(new(App), "*"),
+		ioc.BaseSet,
+		ioc.AttributeSet,
+		ioc.ResourceSet,
+	)
+	return new(App), nil
+}
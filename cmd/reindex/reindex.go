@@ -0,0 +1,34 @@
+package reindex
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/cmd/reindex/ioc"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "重建资产检索索引",
+	Long: "清空并全量重建资产全局检索索引，适用于索引文件损坏或切换检索后端后的初始化\n" +
+		"本地索引由服务进程持有，请在对应实例停止时执行，重建完成后服务启动会从重建位点继续增量同步",
+	RunE: runReindex,
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	app, err := ioc.InitApp()
+	if err != nil {
+		return fmt.Errorf("初始化服务失败: %w", err)
+	}
+
+	start := time.Now()
+	count, err := app.ResourceSvc.RebuildSearchIndex(context.Background())
+	if err != nil {
+		return fmt.Errorf("重建检索索引失败: %w", err)
+	}
+
+	fmt.Printf("重建检索索引完成，共写入 %d 个资产，耗时 %s\n", count, time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,124 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	attribute "github.com/Duke1616/ecmdb/internal/service/attribute"
+	model "github.com/Duke1616/ecmdb/internal/service/model"
+	"github.com/samber/lo"
+)
+
+// orphanAttributeGroupName 模型下没有任何分组时，为孤儿属性兜底创建的分组名称
+const orphanAttributeGroupName = "基础属性"
+
+// OrphanAttributeRepairer 孤儿属性修复器
+// NOTE: 属性所属的分组已被删除时，属性在前端无法展示，统一迁移到模型的第一个分组下
+type OrphanAttributeRepairer struct {
+	modelSvc model.Service
+	attrSvc  attribute.Service
+	dryRun   bool
+}
+
+// NewOrphanAttributeRepairer 创建孤儿属性修复器
+func NewOrphanAttributeRepairer(modelSvc model.Service, attrSvc attribute.Service, dryRun bool) *OrphanAttributeRepairer {
+	return &OrphanAttributeRepairer{
+		modelSvc: modelSvc,
+		attrSvc:  attrSvc,
+		dryRun:   dryRun,
+	}
+}
+
+// Name 实现 Repairer 接口
+func (r *OrphanAttributeRepairer) Name() string {
+	return "orphan-attribute-group"
+}
+
+// Description 实现 Repairer 接口
+func (r *OrphanAttributeRepairer) Description() string {
+	return "将所属分组已删除的属性迁移到模型的第一个分组"
+}
+
+// Repair 执行修复
+func (r *OrphanAttributeRepairer) Repair(ctx context.Context) (*StatsRepair, error) {
+	stats := &StatsRepair{}
+
+	models, err := r.modelSvc.ListAll(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("获取模型列表失败: %w", err)
+	}
+
+	for _, m := range models {
+		modelStats, err := r.processModel(ctx, m.UID)
+		if err != nil {
+			fmt.Printf("❌ 处理模型 %s 失败: %v\n", m.UID, err)
+			continue
+		}
+		stats.Add(modelStats)
+	}
+
+	return stats, nil
+}
+
+// processModel 处理单个模型下的孤儿属性
+func (r *OrphanAttributeRepairer) processModel(ctx context.Context, modelUid string) (*StatsRepair, error) {
+	attrs, _, err := r.attrSvc.ListAttributes(ctx, modelUid)
+	if err != nil {
+		return nil, fmt.Errorf("获取属性列表失败: %w", err)
+	}
+	groups, err := r.attrSvc.ListAttributeGroup(ctx, modelUid)
+	if err != nil {
+		return nil, fmt.Errorf("获取属性分组失败: %w", err)
+	}
+
+	stats := &StatsRepair{Processed: len(attrs)}
+	orphans := orphanAttributes(attrs, groups)
+	if len(orphans) == 0 {
+		return stats, nil
+	}
+
+	for _, attr := range orphans {
+		fmt.Printf("🔍 模型 %s 属性 %s（ID %d）所属分组 %d 不存在\n", modelUid, attr.FieldUid, attr.ID, attr.GroupId)
+	}
+	if r.dryRun {
+		stats.Updated = len(orphans)
+		return stats, nil
+	}
+
+	// 模型下没有任何分组时，兜底创建一个分组承载孤儿属性
+	var targetGroupId int64
+	if len(groups) > 0 {
+		targetGroupId = groups[0].ID
+	} else {
+		targetGroupId, err = r.attrSvc.CreateAttributeGroup(ctx, domain.AttributeGroup{
+			Name:     orphanAttributeGroupName,
+			ModelUid: modelUid,
+		})
+		if err != nil {
+			return stats, fmt.Errorf("创建属性分组失败: %w", err)
+		}
+	}
+
+	for _, attr := range orphans {
+		// NOTE: 目标位置取最大值，由排序器追加到分组末尾
+		if err = r.attrSvc.Sort(ctx, attr.ID, targetGroupId, math.MaxInt32); err != nil {
+			fmt.Printf("⚠️  迁移属性 %s 失败: %v\n", attr.FieldUid, err)
+			continue
+		}
+		stats.Updated++
+	}
+	return stats, nil
+}
+
+// orphanAttributes 筛选分组已不存在的属性
+func orphanAttributes(attrs []domain.Attribute, groups []domain.AttributeGroup) []domain.Attribute {
+	groupIds := lo.SliceToMap(groups, func(g domain.AttributeGroup) (int64, struct{}) {
+		return g.ID, struct{}{}
+	})
+	return lo.Filter(attrs, func(attr domain.Attribute, _ int) bool {
+		_, ok := groupIds[attr.GroupId]
+		return !ok
+	})
+}
@@ -1,13 +1,20 @@
 package ioc
 
 import (
+	"github.com/Duke1616/ecmdb/internal/repository"
 	attrSvc "github.com/Duke1616/ecmdb/internal/service/attribute"
 	modelSvc "github.com/Duke1616/ecmdb/internal/service/model"
 	resourceSvc "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
 )
 
 type App struct {
-	ModelSvc    modelSvc.Service
-	AttrSvc     attrSvc.Service
-	ResourceSvc resourceSvc.EncryptedSvc
+	DB                   *mongox.DB
+	ModelSvc             modelSvc.Service
+	AttrSvc              attrSvc.Service
+	ResourceSvc          resourceSvc.EncryptedSvc
+	ResourceRepo         repository.ResourceRepository
+	RelationModelRepo    repository.RelationModelRepository
+	RelationResourceRepo repository.RelationResourceRepository
+	PluginRepo           repository.PluginRepository
 }
@@ -3,6 +3,8 @@
 package ioc
 
 import (
+	"github.com/Duke1616/ecmdb/internal/repository"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
 	attrSvc "github.com/Duke1616/ecmdb/internal/service/attribute"
 	modelSvc "github.com/Duke1616/ecmdb/internal/service/model"
 	"github.com/Duke1616/ecmdb/ioc"
@@ -17,6 +19,8 @@ func InitApp() (*App, error) {
 		ioc.RelationSet,
 		ioc.ModelSet,
 		ioc.ResourceSet,
+		dao.NewPluginDAO,
+		repository.NewPluginRepository,
 		ioc.InitDeleteModelDependencyCheckers,
 		wire.Bind(new(modelSvc.IDefaultAttributeCreator), new(attrSvc.Service)),
 	)
@@ -40,7 +40,8 @@ func InitApp() (*App, error) {
 	}
 	serviceService := service.NewService(attributeRepository, attributeGroupRepository, fieldSecureAttrChangeEventProducer, iFieldDeleteEventProducer)
 	crypto := ioc.InitCrypto()
-	service5 := service2.NewService(resourceRepository, serviceService, crypto)
+	searchIndex := ioc.InitSearchIndex(resourceDAO)
+	service5 := service2.NewService(resourceRepository, serviceService, crypto, searchIndex)
 	relationModelDAO := dao.NewRelationModelDAO(db)
 	relationModelRepository := repository.NewRelationModelRepository(relationModelDAO)
 	relationResourceDAO := dao.NewRelationResourceDAO(db)
@@ -48,10 +49,17 @@ func InitApp() (*App, error) {
 	relationModelService := service3.NewRelationModelService(relationModelRepository, relationResourceRepository)
 	v := ioc.InitDeleteModelDependencyCheckers(service5, relationModelService)
 	service6 := service4.NewModelService(modelRepository, v, serviceService)
+	pluginDAO := dao.NewPluginDAO(db)
+	pluginRepository := repository.NewPluginRepository(pluginDAO)
 	app := &App{
-		ModelSvc:    service6,
-		AttrSvc:     serviceService,
-		ResourceSvc: service5,
+		DB:                   db,
+		ModelSvc:             service6,
+		AttrSvc:              serviceService,
+		ResourceSvc:          service5,
+		ResourceRepo:         resourceRepository,
+		RelationModelRepo:    relationModelRepository,
+		RelationResourceRepo: relationResourceRepository,
+		PluginRepo:           pluginRepository,
 	}
 	return app, nil
 }
@@ -0,0 +1,152 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository"
+	attribute "github.com/Duke1616/ecmdb/internal/service/attribute"
+	model "github.com/Duke1616/ecmdb/internal/service/model"
+	"github.com/samber/lo"
+)
+
+// PluginBindingRepairer 失效插件绑定修复器
+// NOTE: 插件绑定通过 UID 引用模型和字段，模型或字段被删除后绑定仍处于启用状态，统一禁用而不是删除，便于人工修正
+type PluginBindingRepairer struct {
+	pluginRepo repository.PluginRepository
+	modelSvc   model.Service
+	attrSvc    attribute.Service
+	dryRun     bool
+}
+
+// NewPluginBindingRepairer 创建失效插件绑定修复器
+func NewPluginBindingRepairer(
+	pluginRepo repository.PluginRepository,
+	modelSvc model.Service,
+	attrSvc attribute.Service,
+	dryRun bool,
+) *PluginBindingRepairer {
+	return &PluginBindingRepairer{
+		pluginRepo: pluginRepo,
+		modelSvc:   modelSvc,
+		attrSvc:    attrSvc,
+		dryRun:     dryRun,
+	}
+}
+
+// Name 实现 Repairer 接口
+func (r *PluginBindingRepairer) Name() string {
+	return "plugin-binding"
+}
+
+// Description 实现 Repairer 接口
+func (r *PluginBindingRepairer) Description() string {
+	return "禁用引用了不存在模型或字段的插件绑定"
+}
+
+// Repair 执行修复
+func (r *PluginBindingRepairer) Repair(ctx context.Context) (*StatsRepair, error) {
+	stats := &StatsRepair{}
+
+	plugins, err := r.pluginRepo.ListPlugins(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("获取插件列表失败: %w", err)
+	}
+	if len(plugins) == 0 {
+		return stats, nil
+	}
+
+	bindings, err := r.pluginRepo.ListBindingsByPluginIDs(ctx, lo.Map(plugins, func(p domain.Plugin, _ int) string {
+		return p.UID
+	}))
+	if err != nil {
+		return stats, fmt.Errorf("获取插件绑定失败: %w", err)
+	}
+
+	schema, err := r.loadSchema(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, binding := range bindings {
+		stats.Processed++
+		problems := bindingProblems(binding, schema)
+		if len(problems) == 0 {
+			continue
+		}
+
+		fmt.Printf("🔍 插件 %s 绑定 %s（模型 %s）失效: %s\n",
+			binding.PluginID, binding.UID, binding.ModelUID, strings.Join(problems, "；"))
+		// 已禁用的绑定无需再次处理
+		if !binding.Enabled {
+			continue
+		}
+
+		if r.dryRun {
+			stats.Updated++
+			continue
+		}
+		if err = r.pluginRepo.UpdateBindingEnabled(ctx, binding.UID, false); err != nil {
+			fmt.Printf("⚠️  禁用插件绑定 %s 失败: %v\n", binding.UID, err)
+			continue
+		}
+		stats.Updated++
+	}
+
+	return stats, nil
+}
+
+// loadSchema 加载当前租户下所有模型及其字段，模型 UID -> 字段集合
+func (r *PluginBindingRepairer) loadSchema(ctx context.Context) (map[string]map[string]struct{}, error) {
+	models, err := r.modelSvc.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取模型列表失败: %w", err)
+	}
+
+	schema := make(map[string]map[string]struct{}, len(models))
+	for _, m := range models {
+		fields, err := r.attrSvc.SearchAllAttributeFieldsByModelUid(ctx, m.UID)
+		if err != nil {
+			return nil, fmt.Errorf("获取模型 %s 字段失败: %w", m.UID, err)
+		}
+		schema[m.UID] = lo.SliceToMap(fields, func(field string) (string, struct{}) {
+			return field, struct{}{}
+		})
+	}
+	return schema, nil
+}
+
+// bindingProblems 检查插件绑定引用的模型、字段是否存在，返回问题描述
+func bindingProblems(binding domain.PluginBinding, schema map[string]map[string]struct{}) []string {
+	var problems []string
+	if _, ok := schema[binding.ModelUID]; !ok {
+		problems = append(problems, fmt.Sprintf("模型 %s 不存在", binding.ModelUID))
+	}
+	if binding.Graph == nil {
+		return problems
+	}
+
+	for _, node := range binding.Graph.Nodes {
+		fields, ok := schema[node.ModelUID]
+		if !ok {
+			if node.ModelUID != binding.ModelUID {
+				problems = append(problems, fmt.Sprintf("节点 %s 模型 %s 不存在", node.ID, node.ModelUID))
+			}
+			continue
+		}
+
+		for _, mapping := range node.FieldMappings {
+			if _, ok = fields[mapping.ResourceField]; mapping.ResourceField != "" && !ok {
+				problems = append(problems, fmt.Sprintf("节点 %s 字段 %s.%s 不存在", node.ID, node.ModelUID, mapping.ResourceField))
+			}
+		}
+		for _, filter := range node.Filters {
+			if _, ok = fields[filter.Field]; filter.Field != "" && !ok {
+				problems = append(problems, fmt.Sprintf("节点 %s 过滤字段 %s.%s 不存在", node.ID, node.ModelUID, filter.Field))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,187 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository"
+	"github.com/samber/lo"
+)
+
+// DanglingRelationRepairer 悬空资源关联修复器
+// NOTE: 删除资产时不会清理关联关系，源端或目标端资产已不存在的资源关联需要移除
+type DanglingRelationRepairer struct {
+	relationRepo repository.RelationResourceRepository
+	resourceRepo repository.ResourceRepository
+	dryRun       bool
+}
+
+// NewDanglingRelationRepairer 创建悬空资源关联修复器
+func NewDanglingRelationRepairer(
+	relationRepo repository.RelationResourceRepository,
+	resourceRepo repository.ResourceRepository,
+	dryRun bool,
+) *DanglingRelationRepairer {
+	return &DanglingRelationRepairer{
+		relationRepo: relationRepo,
+		resourceRepo: resourceRepo,
+		dryRun:       dryRun,
+	}
+}
+
+// Name 实现 Repairer 接口
+func (r *DanglingRelationRepairer) Name() string {
+	return "dangling-relation"
+}
+
+// Description 实现 Repairer 接口
+func (r *DanglingRelationRepairer) Description() string {
+	return "移除端点资产已不存在的资源关联"
+}
+
+// Repair 执行修复
+func (r *DanglingRelationRepairer) Repair(ctx context.Context) (*StatsRepair, error) {
+	const batchSize = 500
+	stats := &StatsRepair{}
+	afterID := int64(0)
+
+	for {
+		rrs, err := r.relationRepo.ListAfterID(ctx, afterID, batchSize)
+		if err != nil {
+			return stats, fmt.Errorf("获取资源关联失败: %w", err)
+		}
+		if len(rrs) == 0 {
+			break
+		}
+		afterID = rrs[len(rrs)-1].ID
+
+		dangling, err := r.findDangling(ctx, rrs)
+		if err != nil {
+			return stats, err
+		}
+		stats.Processed += len(rrs)
+
+		for _, rr := range dangling {
+			fmt.Printf("🔍 资源关联 ID %d [%s] %s:%d -> %s:%d 端点资产不存在\n", rr.ID, rr.RelationName,
+				rr.SourceModelUID, rr.SourceResourceID, rr.TargetModelUID, rr.TargetResourceID)
+		}
+
+		if r.dryRun {
+			stats.Updated += len(dangling)
+		} else if len(dangling) > 0 {
+			deleted, err := r.relationRepo.DeleteByIds(ctx, lo.Map(dangling, func(rr domain.ResourceRelation, _ int) int64 {
+				return rr.ID
+			}))
+			if err != nil {
+				return stats, fmt.Errorf("删除悬空资源关联失败: %w", err)
+			}
+			stats.Updated += int(deleted)
+		}
+
+		if len(rrs) < batchSize {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+// findDangling 找出端点资产不存在或模型不匹配的资源关联
+func (r *DanglingRelationRepairer) findDangling(ctx context.Context, rrs []domain.ResourceRelation) ([]domain.ResourceRelation, error) {
+	ids := lo.Uniq(lo.FlatMap(rrs, func(rr domain.ResourceRelation, _ int) []int64 {
+		return []int64{rr.SourceResourceID, rr.TargetResourceID}
+	}))
+
+	resources, err := r.resourceRepo.ListResourcesByIds(ctx, nil, ids)
+	if err != nil {
+		return nil, fmt.Errorf("获取关联资产失败: %w", err)
+	}
+
+	return danglingRelations(rrs, lo.SliceToMap(resources, func(res domain.Resource) (int64, string) {
+		return res.ID, res.ModelUID
+	})), nil
+}
+
+// danglingRelations 根据现存资产（ID -> 模型 UID）筛选悬空资源关联
+func danglingRelations(rrs []domain.ResourceRelation, existing map[int64]string) []domain.ResourceRelation {
+	return lo.Filter(rrs, func(rr domain.ResourceRelation, _ int) bool {
+		src, srcOk := existing[rr.SourceResourceID]
+		dst, dstOk := existing[rr.TargetResourceID]
+		return !srcOk || !dstOk || src != rr.SourceModelUID || dst != rr.TargetModelUID
+	})
+}
+
+// OrphanRelationNameRepairer 失效关联名称修复器
+// NOTE: 模型关联被删除或重命名后，残留的资源关联 RelationName 已无对应的拓扑定义
+type OrphanRelationNameRepairer struct {
+	relationRepo repository.RelationResourceRepository
+	modelRepo    repository.RelationModelRepository
+	dryRun       bool
+}
+
+// NewOrphanRelationNameRepairer 创建失效关联名称修复器
+func NewOrphanRelationNameRepairer(
+	relationRepo repository.RelationResourceRepository,
+	modelRepo repository.RelationModelRepository,
+	dryRun bool,
+) *OrphanRelationNameRepairer {
+	return &OrphanRelationNameRepairer{
+		relationRepo: relationRepo,
+		modelRepo:    modelRepo,
+		dryRun:       dryRun,
+	}
+}
+
+// Name 实现 Repairer 接口
+func (r *OrphanRelationNameRepairer) Name() string {
+	return "orphan-relation-name"
+}
+
+// Description 实现 Repairer 接口
+func (r *OrphanRelationNameRepairer) Description() string {
+	return "移除关联名称已无对应模型关联的资源关联"
+}
+
+// Repair 执行修复
+func (r *OrphanRelationNameRepairer) Repair(ctx context.Context) (*StatsRepair, error) {
+	stats := &StatsRepair{}
+
+	names, err := r.relationRepo.ListRelationNames(ctx)
+	if err != nil {
+		return stats, err
+	}
+	if len(names) == 0 {
+		return stats, nil
+	}
+
+	mrs, err := r.modelRepo.GetByRelationNames(ctx, names)
+	if err != nil {
+		return stats, fmt.Errorf("获取模型关联失败: %w", err)
+	}
+	orphans, _ := lo.Difference(names, lo.Map(mrs, func(mr domain.ModelRelation, _ int) string {
+		return mr.RelationName
+	}))
+
+	for _, name := range orphans {
+		count, err := r.relationRepo.CountByRelationName(ctx, name)
+		if err != nil {
+			return stats, err
+		}
+		stats.Processed += int(count)
+		fmt.Printf("🔍 关联名称 %s 未注册模型关联，涉及 %d 条资源关联\n", name, count)
+
+		if r.dryRun {
+			stats.Updated += int(count)
+			continue
+		}
+
+		deleted, err := r.relationRepo.DeleteByRelationName(ctx, name)
+		if err != nil {
+			return stats, fmt.Errorf("删除关联名称 %s 的资源关联失败: %w", name, err)
+		}
+		stats.Updated += int(deleted)
+	}
+
+	return stats, nil
+}
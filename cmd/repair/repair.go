@@ -3,7 +3,7 @@ package repair
 import (
 	"context"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/Duke1616/ecmdb/cmd/repair/ioc"
 	"github.com/Duke1616/ecmdb/internal/domain"
@@ -15,20 +15,25 @@ import (
 )
 
 var (
-	dryRun  bool
-	execute bool
+	dryRun    bool
+	execute   bool
+	tenantID  int64
+	repairers []string
 )
 
 var Cmd = &cobra.Command{
 	Use:   "repair",
-	Short: "修复数据加密字段",
-	Long:  "对历史数据进行字段加密处理，将未加密但需要加密的字段进行加密",
+	Short: "修复历史数据",
+	Long:  "对历史数据进行修复：字段加密、悬空资源关联、失效关联名称、孤儿属性、失效插件绑定",
 	RunE:  runRepair,
 }
 
 func init() {
 	Cmd.Flags().BoolVar(&dryRun, "dry-run", true, "干跑模式，不实际修改数据 (默认开启)")
 	Cmd.Flags().BoolVar(&execute, "execute", false, "实际执行修复，会修改数据")
+	Cmd.Flags().Int64Var(&tenantID, "tenant", 0, "仅修复指定租户，默认修复所有租户")
+	Cmd.Flags().StringSliceVar(&repairers, "repairers", nil,
+		fmt.Sprintf("仅执行指定的修复器，多个以逗号分隔，可选: %s", strings.Join(RepairerNames(), ",")))
 }
 
 // runRepair 执行修复命令
@@ -45,11 +50,23 @@ func runRepair(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("初始化服务失败: %w", err)
 	}
 
-	// 创建修复器
-	repairer := NewFieldEncryptionRepairer(app.ModelSvc, app.AttrSvc, app.ResourceSvc, actualDryRun)
+	// 按参数筛选修复器
+	selected, err := SelectRepairers(NewRepairers(app, actualDryRun), repairers)
+	if err != nil {
+		return err
+	}
+
+	// 确定需要修复的租户
+	tenants := []int64{tenantID}
+	if tenantID <= 0 {
+		if tenants, err = listTenantIDs(ctx, app.DB); err != nil {
+			return fmt.Errorf("获取租户列表失败: %w", err)
+		}
+	}
 
 	// 执行修复
-	return repairer.Repair(ctx)
+	runner := NewRunner(selected, actualDryRun)
+	return runner.Run(ctx, tenants)
 }
 
 // FieldEncryptionRepairer 字段加密修复器
@@ -75,28 +92,27 @@ func NewFieldEncryptionRepairer(
 	}
 }
 
-// Repair 执行修复
-func (r *FieldEncryptionRepairer) Repair(ctx context.Context) error {
-	fmt.Println("🔧 开始执行字段加密修复...")
-	if r.dryRun {
-		fmt.Println("🔍 运行在干跑模式，不会实际修改数据")
-	}
+// Name 实现 Repairer 接口
+func (r *FieldEncryptionRepairer) Name() string {
+	return "field-encryption"
+}
 
-	startTime := time.Now()
-	defer func() {
-		duration := time.Since(startTime)
-		fmt.Printf("✅ 字段加密修复完成，耗时: %v\n", duration)
-	}()
+// Description 实现 Repairer 接口
+func (r *FieldEncryptionRepairer) Description() string {
+	return "字段加密修复"
+}
 
+// Repair 执行修复
+func (r *FieldEncryptionRepairer) Repair(ctx context.Context) (*StatsRepair, error) {
 	// 获取需要处理的模型
 	models, err := r.getModelsWithSecureFields(ctx)
 	if err != nil {
-		return fmt.Errorf("获取模型信息失败: %w", err)
+		return nil, fmt.Errorf("获取模型信息失败: %w", err)
 	}
 
 	if len(models) == 0 {
 		fmt.Println("ℹ️  没有找到需要处理的模型，跳过修复")
-		return nil
+		return &StatsRepair{}, nil
 	}
 
 	// 处理每个模型
@@ -110,9 +126,7 @@ func (r *FieldEncryptionRepairer) Repair(ctx context.Context) error {
 		stats.Add(modelStats)
 	}
 
-	// 输出统计信息
-	stats.PrintSummary()
-	return nil
+	return stats, nil
 }
 
 // getModelsWithSecureFields 获取需要处理的模型
@@ -271,6 +285,6 @@ func (s *StatsRepair) Add(other *StatsRepair) {
 }
 
 // PrintSummary 打印统计摘要
-func (s *StatsRepair) PrintSummary() {
-	fmt.Printf("\n🎉 修复完成! 总计处理 %d 条资源，更新 %d 条\n", s.Processed, s.Updated)
+func (s *StatsRepair) PrintSummary(name string) {
+	fmt.Printf("📊 %-24s 处理 %d 条，修复 %d 条\n", name, s.Processed, s.Updated)
 }
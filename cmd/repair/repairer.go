@@ -0,0 +1,147 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Duke1616/ecmdb/cmd/repair/ioc"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Repairer 可插拔的数据修复器
+// NOTE: 修复器在租户上下文内执行，干跑模式由构造时注入，Repair 内部自行判断是否落库
+type Repairer interface {
+	// Name 修复器唯一标识，用于 --repairers 参数筛选
+	Name() string
+
+	// Description 修复器说明，用于输出展示
+	Description() string
+
+	// Repair 执行修复并返回统计信息
+	Repair(ctx context.Context) (*StatsRepair, error)
+}
+
+// NewRepairers 按执行顺序创建所有修复器
+func NewRepairers(app *ioc.App, dryRun bool) []Repairer {
+	return []Repairer{
+		NewFieldEncryptionRepairer(app.ModelSvc, app.AttrSvc, app.ResourceSvc, dryRun),
+		NewDanglingRelationRepairer(app.RelationResourceRepo, app.ResourceRepo, dryRun),
+		NewOrphanRelationNameRepairer(app.RelationResourceRepo, app.RelationModelRepo, dryRun),
+		NewOrphanAttributeRepairer(app.ModelSvc, app.AttrSvc, dryRun),
+		NewPluginBindingRepairer(app.PluginRepo, app.ModelSvc, app.AttrSvc, dryRun),
+	}
+}
+
+// RepairerNames 返回所有修复器的唯一标识
+func RepairerNames() []string {
+	return lo.Map(NewRepairers(&ioc.App{}, true), func(r Repairer, _ int) string {
+		return r.Name()
+	})
+}
+
+// SelectRepairers 根据名称筛选修复器，names 为空时返回全部
+func SelectRepairers(all []Repairer, names []string) ([]Repairer, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	index := lo.KeyBy(all, func(r Repairer) string {
+		return r.Name()
+	})
+	selected := make([]Repairer, 0, len(names))
+	for _, name := range lo.Uniq(names) {
+		r, ok := index[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("未知的修复器: %s，可选: %s", name, strings.Join(lo.Keys(index), ","))
+		}
+		selected = append(selected, r)
+	}
+	return selected, nil
+}
+
+// Runner 按租户依次执行修复器，并汇总每个修复器的统计信息
+type Runner struct {
+	repairers []Repairer
+	dryRun    bool
+}
+
+// NewRunner 创建修复执行器
+func NewRunner(repairers []Repairer, dryRun bool) *Runner {
+	return &Runner{
+		repairers: repairers,
+		dryRun:    dryRun,
+	}
+}
+
+// Run 执行修复
+func (r *Runner) Run(ctx context.Context, tenants []int64) error {
+	fmt.Println("🔧 开始执行数据修复...")
+	if r.dryRun {
+		fmt.Println("🔍 运行在干跑模式，不会实际修改数据")
+	}
+
+	startTime := time.Now()
+	summary := make(map[string]*StatsRepair, len(r.repairers))
+	failures := make(map[string]int, len(r.repairers))
+
+	for _, tenant := range tenants {
+		fmt.Printf("\n🏢 正在处理租户: %d\n", tenant)
+		tenantCtx := ctxutil.WithTenantID(ctx, tenant)
+
+		for _, repairer := range r.repairers {
+			fmt.Printf("\n🔄 [%s] %s\n", repairer.Name(), repairer.Description())
+			stats, err := repairer.Repair(tenantCtx)
+			if err != nil {
+				// NOTE: 单个修复器失败不影响其他修复器执行
+				fmt.Printf("❌ [%s] 租户 %d 修复失败: %v\n", repairer.Name(), tenant, err)
+				failures[repairer.Name()]++
+				continue
+			}
+
+			if _, ok := summary[repairer.Name()]; !ok {
+				summary[repairer.Name()] = &StatsRepair{}
+			}
+			summary[repairer.Name()].Add(stats)
+		}
+	}
+
+	fmt.Printf("\n🎉 修复完成! 共处理 %d 个租户，耗时: %v\n", len(tenants), time.Since(startTime))
+	for _, repairer := range r.repairers {
+		stats, ok := summary[repairer.Name()]
+		if !ok {
+			stats = &StatsRepair{}
+		}
+		stats.PrintSummary(repairer.Name())
+		if failures[repairer.Name()] > 0 {
+			fmt.Printf("   ⚠️  其中 %d 个租户执行失败\n", failures[repairer.Name()])
+		}
+	}
+	return nil
+}
+
+// listTenantIDs 从模型集合中汇总所有存在数据的租户
+func listTenantIDs(ctx context.Context, db *mongox.DB) ([]int64, error) {
+	coll := mongox.NewCollection[dao.Model](db, dao.ModelCollection)
+	values, err := coll.Distinct(plugin.IgnoreTenantContext(ctx), "tenant_id", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Uniq(lo.FilterMap(values, func(v interface{}, _ int) (int64, bool) {
+		switch id := v.(type) {
+		case int64:
+			return id, id > 0
+		case int32:
+			return int64(id), id > 0
+		default:
+			return 0, false
+		}
+	})), nil
+}
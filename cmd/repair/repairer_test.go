@@ -0,0 +1,106 @@
+package repair
+
+import (
+	"testing"
+
+	"github.com/Duke1616/ecmdb/cmd/repair/ioc"
+	"github.com/Duke1616/ecmdb/internal/domain"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectRepairers(t *testing.T) {
+	all := NewRepairers(&ioc.App{}, true)
+
+	selected, err := SelectRepairers(all, nil)
+	require.NoError(t, err)
+	assert.Len(t, selected, len(all))
+
+	selected, err = SelectRepairers(all, []string{"plugin-binding", "dangling-relation"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "plugin-binding", selected[0].Name())
+	assert.Equal(t, "dangling-relation", selected[1].Name())
+
+	_, err = SelectRepairers(all, []string{"unknown"})
+	assert.Error(t, err)
+}
+
+func TestDanglingRelations(t *testing.T) {
+	rrs := []domain.ResourceRelation{
+		{ID: 1, SourceModelUID: "host", SourceResourceID: 10, TargetModelUID: "app", TargetResourceID: 20},
+		{ID: 2, SourceModelUID: "host", SourceResourceID: 11, TargetModelUID: "app", TargetResourceID: 20},
+		{ID: 3, SourceModelUID: "host", SourceResourceID: 10, TargetModelUID: "app", TargetResourceID: 21},
+		{ID: 4, SourceModelUID: "host", SourceResourceID: 10, TargetModelUID: "app", TargetResourceID: 22},
+	}
+	existing := map[int64]string{10: "host", 20: "app", 22: "mysql"}
+
+	dangling := danglingRelations(rrs, existing)
+	ids := make([]int64, 0, len(dangling))
+	for _, rr := range dangling {
+		ids = append(ids, rr.ID)
+	}
+	// 2: 源端不存在；3: 目标端不存在；4: 目标端模型不匹配
+	assert.Equal(t, []int64{2, 3, 4}, ids)
+}
+
+func TestOrphanAttributes(t *testing.T) {
+	attrs := []domain.Attribute{
+		{ID: 1, GroupId: 100, FieldUid: "name"},
+		{ID: 2, GroupId: 200, FieldUid: "ip"},
+	}
+	groups := []domain.AttributeGroup{{ID: 100}}
+
+	orphans := orphanAttributes(attrs, groups)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, "ip", orphans[0].FieldUid)
+}
+
+func TestBindingProblems(t *testing.T) {
+	schema := map[string]map[string]struct{}{
+		"host": {"name": {}, "ip": {}},
+	}
+
+	testCases := []struct {
+		name    string
+		binding domain.PluginBinding
+		want    int
+	}{
+		{
+			name: "绑定有效",
+			binding: domain.PluginBinding{
+				ModelUID: "host",
+				Graph: &pluginx.BindingGraph{Nodes: []pluginx.BindingGraphNode{{
+					ID:            "root",
+					ModelUID:      "host",
+					FieldMappings: []pluginx.FieldMapping{{Input: "address", ResourceField: "ip"}},
+				}}},
+			},
+		},
+		{
+			name:    "模型不存在",
+			binding: domain.PluginBinding{ModelUID: "switch"},
+			want:    1,
+		},
+		{
+			name: "字段及过滤字段不存在",
+			binding: domain.PluginBinding{
+				ModelUID: "host",
+				Graph: &pluginx.BindingGraph{Nodes: []pluginx.BindingGraphNode{{
+					ID:            "root",
+					ModelUID:      "host",
+					FieldMappings: []pluginx.FieldMapping{{Input: "password", ResourceField: "password"}},
+					Filters:       []pluginx.Filter{{Field: "os", Operator: "eq", Value: "linux"}},
+				}}},
+			},
+			want: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Len(t, bindingProblems(tc.binding, schema), tc.want)
+		})
+	}
+}
@@ -7,21 +7,22 @@ import (
 )
 
 type Attribute struct {
-	ID        int64
-	GroupId   int64
-	ModelUid  string
-	FieldUid  string
-	FieldName string
-	FieldType string
-	Required  bool
-	Display   bool
-	Secure    bool
-	Link      bool
-	Index     int64
-	SortKey   int64 // 拖拽排序键（稀疏索引）
-	Option    interface{}
-	Version   int64
-	Builtin   bool
+	ID          int64
+	GroupId     int64
+	ModelUid    string
+	FieldUid    string
+	FieldName   string
+	FieldType   string
+	Description string // 字段说明，导入模板中作为表头批注
+	Required    bool
+	Display     bool
+	Secure      bool
+	Link        bool
+	Index       int64
+	SortKey     int64 // 拖拽排序键（稀疏索引）
+	Option      interface{}
+	Version     int64
+	Builtin     bool
 }
 
 func (a Attribute) ValidateForCreate() error {
@@ -94,6 +95,21 @@ func (a *Attribute) NeedsValidation() bool {
 	return a.IsSelectType() && len(a.GetOptionStrings()) > 0
 }
 
+// CellType 字段在 Excel 中对应的单元格数据类型
+// NOTE: number 为数字，date 为日期，datetime 为日期时间，其余类型按文本处理
+func (a *Attribute) CellType() CellType {
+	switch a.FieldType {
+	case "number":
+		return CellNumber
+	case "date":
+		return CellDate
+	case "datetime":
+		return CellDateTime
+	default:
+		return CellText
+	}
+}
+
 // ToExcelRow 转换为 Excel 行数据
 func (a *Attribute) ToExcelRow() []interface{} {
 	return []interface{}{
@@ -17,6 +17,10 @@ type Builder struct {
 	rows          [][]interface{}
 	styles        *StyleSet
 	autoWidth     bool // 是否自动调整列宽
+	lookup        *lookupSheet
+	required      map[int]bool   // 必填列，高亮名称表头
+	comments      map[int]string // 名称表头的批注
+	err           error
 }
 
 // NewBuilder 创建 Excel 构建器
@@ -35,6 +39,9 @@ func NewBuilder(sheetName string) *Builder {
 		rows:      make([][]interface{}, 0),
 		styles:    createStyleSet(file),
 		autoWidth: true, // 默认启用自动列宽
+		lookup:    &lookupSheet{file: file},
+		required:  make(map[int]bool),
+		comments:  make(map[int]string),
 	}
 }
 
@@ -81,22 +88,63 @@ func (b *Builder) WithColumnWidths(widths map[int]float64) *Builder {
 	return b
 }
 
-// WithValidation 添加数据验证
+// WithValidation 添加数据验证(下拉列表)，选项过长时引用隐藏的下拉选项工作表
 func (b *Builder) WithValidation(colIdx int, options []string, startRow, endRow int) *Builder {
-	if len(options) == 0 {
+	if len(options) == 0 || b.err != nil {
 		return b
 	}
 
-	col, _ := excelize.ColumnNumberToName(colIdx + 1)
-	dv := excelize.NewDataValidation(true)
-	dv.Sqref = fmt.Sprintf("%s%d:%s%d", col, startRow, col, endRow)
-	dv.SetDropList(options)
-	b.file.AddDataValidation(b.sheetName, dv)
+	dv, err := b.lookup.dropListValidation(columnSqref(colIdx, startRow, endRow), options)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.err = b.file.AddDataValidation(b.sheetName, dv)
+	return b
+}
+
+// WithCellType 按数据类型添加数字或日期验证，并设置单元格格式，文本类型不做处理
+// NOTE: 单元格格式作用于 startRow 至 endRow 的空白单元格，写入的数据行仍使用斑马纹样式
+func (b *Builder) WithCellType(colIdx int, typ CellType, startRow, endRow int) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	dv, err := typedValidation(columnSqref(colIdx, startRow, endRow), typ)
+	if err != nil || dv == nil {
+		b.err = err
+		return b
+	}
+	if b.err = b.file.AddDataValidation(b.sheetName, dv); b.err != nil {
+		return b
+	}
+
+	start, _ := excelize.CoordinatesToCellName(colIdx+1, startRow)
+	end, _ := excelize.CoordinatesToCellName(colIdx+1, endRow)
+	b.err = b.file.SetCellStyle(b.sheetName, start, end, b.styles.CellStyle(typ))
+	return b
+}
+
+// WithRequired 高亮必填列的名称表头
+func (b *Builder) WithRequired(colIdx int) *Builder {
+	b.required[colIdx] = true
+	return b
+}
+
+// WithComment 为列的名称表头添加批注，鼠标悬停时显示
+func (b *Builder) WithComment(colIdx int, text string) *Builder {
+	if text != "" {
+		b.comments[colIdx] = text
+	}
 	return b
 }
 
 // Build 构建 Excel 文件
 func (b *Builder) Build() error {
+	if b.err != nil {
+		return b.err
+	}
+
 	// 1. 写入表头
 	if b.use3RowHeader {
 		if err := b.write3RowHeaders(); err != nil {
@@ -125,7 +173,8 @@ func (b *Builder) Build() error {
 		return err
 	}
 
-	return nil
+	// 5. 名称表头批注
+	return b.addComments()
 }
 
 // ToBytes 导出为字节数组
@@ -154,7 +203,11 @@ func (b *Builder) writeHeaders() error {
 		if err := b.file.SetCellValue(b.sheetName, cell, header); err != nil {
 			return err
 		}
-		if err := b.file.SetCellStyle(b.sheetName, cell, cell, b.styles.Header); err != nil {
+		style := b.styles.Header
+		if b.required[colIdx] {
+			style = b.styles.RequiredHeader
+		}
+		if err := b.file.SetCellStyle(b.sheetName, cell, cell, style); err != nil {
 			return err
 		}
 	}
@@ -178,8 +231,12 @@ func (b *Builder) write3RowHeaders() error {
 			if err := b.file.SetCellValue(b.sheetName, cell, value); err != nil {
 				return err
 			}
-			// 使用对应行的样式
-			if err := b.file.SetCellStyle(b.sheetName, cell, cell, styles[rowIdx]); err != nil {
+			// 使用对应行的样式，必填列的名称表头高亮
+			style := styles[rowIdx]
+			if rowIdx+1 == b.nameRow() && b.required[colIdx] {
+				style = b.styles.RequiredHeader
+			}
+			if err := b.file.SetCellStyle(b.sheetName, cell, cell, style); err != nil {
 				return err
 			}
 		}
@@ -195,6 +252,28 @@ func (b *Builder) write3RowHeaders() error {
 	return nil
 }
 
+// nameRow 字段名称所在的表头行
+func (b *Builder) nameRow() int {
+	if b.use3RowHeader {
+		return 3
+	}
+	return 1
+}
+
+// addComments 为名称表头添加批注
+func (b *Builder) addComments() error {
+	for colIdx, text := range b.comments {
+		cell, _ := excelize.CoordinatesToCellName(colIdx+1, b.nameRow())
+		if err := b.file.AddComment(b.sheetName, excelize.Comment{
+			Cell:      cell,
+			Paragraph: []excelize.RichTextRun{{Text: text}},
+		}); err != nil {
+			return fmt.Errorf("添加表头批注失败: %w", err)
+		}
+	}
+	return nil
+}
+
 // writeRows 写入数据行
 func (b *Builder) writeRows() error {
 	// 计算数据起始行(单行表头从第 2 行开始,3 行表头从第 4 行开始)
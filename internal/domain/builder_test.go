@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBuilder_Validations(t *testing.T) {
+	long := make([]string, 100)
+	for i := range long {
+		long[i] = fmt.Sprintf("option-%03d", i)
+	}
+
+	builder := NewBuilder("host").
+		With3RowHeaders([]string{"", "", "", ""}, []string{"env", "region", "cpu", "online"},
+			[]string{"环境", "区域", "CPU", "上线日期"}).
+		WithValidation(0, []string{"prod", "test"}, 4, 100).
+		WithValidation(1, long, 4, 100).
+		WithCellType(2, CellNumber, 4, 100).
+		WithCellType(3, CellDate, 4, 100).
+		WithRequired(0).
+		WithComment(2, "逻辑核数")
+	defer builder.Close()
+
+	data, err := builder.ToBytes()
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer f.Close()
+
+	// 选项超过 255 个字符时写入隐藏工作表
+	assert.Equal(t, []string{"host", LookupSheetName}, f.GetSheetList())
+	visible, err := f.GetSheetVisible(LookupSheetName)
+	require.NoError(t, err)
+	assert.False(t, visible)
+	cols, err := f.GetCols(LookupSheetName)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{long}, cols)
+
+	dvs, err := f.GetDataValidations("host")
+	require.NoError(t, err)
+	require.Len(t, dvs, 4)
+	assert.Equal(t, `"prod,test"`, dvs[0].Formula1)
+	assert.Equal(t, fmt.Sprintf("'%s'!$A$1:$A$100", LookupSheetName), dvs[1].Formula1)
+	assert.Equal(t, "C4:C100", dvs[2].Sqref)
+	assert.Equal(t, "decimal", dvs[2].Type)
+	assert.Equal(t, "date", dvs[3].Type)
+
+	style, err := f.GetCellStyle("host", "A3")
+	require.NoError(t, err)
+	assert.Equal(t, builder.styles.RequiredHeader, style)
+
+	comments, err := f.GetComments("host")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "C3", comments[0].Cell)
+	require.Len(t, comments[0].Paragraph, 1)
+	assert.Equal(t, "逻辑核数", comments[0].Paragraph[0].Text)
+}
+
+func TestStreamBuilder_LookupValidation(t *testing.T) {
+	long := make([]string, 100)
+	for i := range long {
+		long[i] = fmt.Sprintf("option-%03d", i)
+	}
+
+	builder := NewStreamBuilder("host", []string{""}, []string{"region"}, []string{"区域"})
+	defer builder.Close()
+	builder.WithValidation(0, long, 4, 0)
+	require.NoError(t, builder.AddRows([][]interface{}{{"option-001"}}))
+
+	var buf bytes.Buffer
+	_, err := builder.WriteTo(&buf)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+
+	dvs, err := f.GetDataValidations("host")
+	require.NoError(t, err)
+	require.Len(t, dvs, 1)
+	assert.Equal(t, fmt.Sprintf("'%s'!$A$1:$A$100", LookupSheetName), dvs[0].Formula1)
+}
@@ -0,0 +1,49 @@
+package domain
+
+// ExportJobStatus 导出任务状态
+type ExportJobStatus string
+
+const (
+	ExportJobPending   ExportJobStatus = "pending"
+	ExportJobRunning   ExportJobStatus = "running"
+	ExportJobSucceeded ExportJobStatus = "succeeded"
+	ExportJobFailed    ExportJobStatus = "failed"
+)
+
+// ExportRelation 关联资产导出配置
+type ExportRelation struct {
+	RelationName string
+	Fields       []string
+	Mode         string // join 或 explode
+	Separator    string
+}
+
+// ExportJob 异步导出任务
+// NOTE: 导出文件以流式方式分片上传到对象存储，完成后通过预签名链接下载
+type ExportJob struct {
+	ID           int64
+	TenantID     int64
+	ModelUID     string
+	ResourceIDs  []int64
+	FilterGroups []FilterGroup
+	Fields       []string
+	Sorts        []ResourceSort
+	Relations    []ExportRelation
+	FileName     string
+	FormatOptions
+	Status    ExportJobStatus
+	Processed int    // 已导出的资产数量
+	Rows      int    // 已写入的数据行数，关联资产展开时可能多于资产数量
+	FileKey   string // 导出文件在对象存储中的 key，任务成功后生成
+	Size      int64  // 导出文件大小 (字节)
+	Message   string // 任务失败原因
+	CreatorID int64
+	Ctime     int64
+	Utime     int64
+	Ftime     int64 // 完成时间
+}
+
+// Finished 任务是否已结束
+func (j ExportJob) Finished() bool {
+	return j.Status == ExportJobSucceeded || j.Status == ExportJobFailed
+}
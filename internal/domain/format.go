@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// FileFormat 导入导出文件格式
+type FileFormat string
+
+const (
+	FileFormatExcel  FileFormat = "xlsx"
+	FileFormatCSV    FileFormat = "csv"
+	FileFormatNDJSON FileFormat = "ndjson" // 每行一个 JSON 对象
+	FileFormatYAML   FileFormat = "yaml"   // 对象数组
+)
+
+// 文本编码，仅 CSV 生效，JSON 及 YAML 固定为 UTF-8
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom" // 带 BOM 的 UTF-8，Windows Excel 可直接识别
+	EncodingGBK     = "gbk"
+)
+
+var fileFormatMeta = map[FileFormat]struct {
+	ext         string
+	contentType string
+}{
+	FileFormatExcel:  {".xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	FileFormatCSV:    {".csv", "text/csv"},
+	FileFormatNDJSON: {".ndjson", "application/x-ndjson"},
+	FileFormatYAML:   {".yaml", "application/yaml"},
+}
+
+func (f FileFormat) Valid() bool {
+	_, ok := fileFormatMeta[f]
+	return ok
+}
+
+// Ext 文件后缀
+func (f FileFormat) Ext() string {
+	return fileFormatMeta[f].ext
+}
+
+func (f FileFormat) ContentType() string {
+	return fileFormatMeta[f].contentType
+}
+
+// FormatOptions 文件格式及 CSV 的分隔符、编码配置
+type FormatOptions struct {
+	Format    FileFormat
+	Delimiter string // CSV 分隔符，默认为 ","
+	Encoding  string // CSV 编码，默认为 utf-8
+}
+
+// Normalize 填充默认值并校验配置，未指定格式时为 Excel
+func (o FormatOptions) Normalize() (FormatOptions, error) {
+	if o.Format == "" {
+		o.Format = FileFormatExcel
+	}
+	if !o.Format.Valid() {
+		return o, fmt.Errorf("不支持的文件格式: %s", o.Format)
+	}
+	if o.Format != FileFormatCSV {
+		return FormatOptions{Format: o.Format}, nil
+	}
+
+	if o.Delimiter == "" {
+		o.Delimiter = ","
+	}
+	if r, size := utf8.DecodeRuneInString(o.Delimiter); size != len(o.Delimiter) || r == '"' || r == '\r' || r == '\n' {
+		return o, fmt.Errorf("CSV 分隔符必须为单个字符且不能为引号或换行: %q", o.Delimiter)
+	}
+
+	if o.Encoding == "" {
+		o.Encoding = EncodingUTF8
+	}
+	switch o.Encoding {
+	case EncodingUTF8, EncodingUTF8BOM, EncodingGBK:
+	default:
+		return o, fmt.Errorf("不支持的 CSV 编码: %s", o.Encoding)
+	}
+	return o, nil
+}
+
+// Comma CSV 分隔符
+func (o FormatOptions) Comma() rune {
+	r, _ := utf8.DecodeRuneInString(o.Delimiter)
+	return r
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/samber/lo"
+)
+
+// ChangeAction 变更日志动作
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "create"
+	ChangeActionUpdate ChangeAction = "update"
+	ChangeActionDelete ChangeAction = "delete"
+	// ChangeActionUnset 模型级字段抹除，作用于模型下的全部资产
+	ChangeActionUnset ChangeAction = "unset"
+)
+
+// ResourceChange 资产变更日志
+// NOTE: create / update 记录变更后的完整数据，回放时直接覆盖，不依赖前序变更的完整性
+type ResourceChange struct {
+	TenantID   int64
+	ResourceID int64 // unset 为模型级变更，ResourceID 为 0
+	ModelUID   string
+	Action     ChangeAction
+	Data       mongox.MapStr
+	Fields     []string // unset 抹除的字段
+	Ctime      int64
+}
+
+// ResourceSnapshot 资产周期快照，作为回放变更日志的基线
+type ResourceSnapshot struct {
+	ResourceID   int64
+	ModelUID     string
+	Data         mongox.MapStr
+	SnapshotTime int64
+}
+
+// ReplayResource 以快照为基线按时间顺序回放变更日志，还原资产在 asOf 时刻的状态
+// changes 需按 Ctime 升序排列；返回 false 表示该时刻资产不存在，或历史记录开始之前无从还原
+func ReplayResource(snapshot *ResourceSnapshot, changes []ResourceChange, asOf int64) (Resource, bool) {
+	var (
+		state  Resource
+		exists bool
+		since  int64
+	)
+	if snapshot != nil && snapshot.SnapshotTime <= asOf {
+		state = Resource{ID: snapshot.ResourceID, ModelUID: snapshot.ModelUID, Data: cloneMapStr(snapshot.Data)}
+		exists, since = true, snapshot.SnapshotTime
+	}
+
+	// NOTE: 与快照同一毫秒的变更无法判断先后，重复回放完整数据是幂等的，因此只跳过严格早于快照的变更
+	for _, c := range changes {
+		if c.Ctime < since || c.Ctime > asOf {
+			continue
+		}
+
+		switch c.Action {
+		case ChangeActionCreate, ChangeActionUpdate:
+			state = Resource{ID: c.ResourceID, ModelUID: c.ModelUID, Data: cloneMapStr(c.Data)}
+			exists = true
+		case ChangeActionDelete:
+			exists = false
+		case ChangeActionUnset:
+			if exists && c.ModelUID == state.ModelUID {
+				for _, field := range c.Fields {
+					delete(state.Data, field)
+				}
+			}
+		}
+	}
+
+	if !exists {
+		return Resource{}, false
+	}
+	state.Name, _ = state.Data["name"].(string)
+	return state, true
+}
+
+// Topology 资产子图在某一时刻的状态
+type Topology struct {
+	Resources []Resource
+	Relations []ResourceRelation
+}
+
+// FieldDiff 字段变更前后的值
+type FieldDiff struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// ResourceDiff 同一资产在两个时间点之间的字段差异
+type ResourceDiff struct {
+	ResourceID int64
+	ModelUID   string
+	Name       string
+	Fields     []FieldDiff
+}
+
+// TopologyDiff 资产子图在两个时间点之间的差异
+type TopologyDiff struct {
+	AddedResources   []Resource
+	RemovedResources []Resource
+	ChangedResources []ResourceDiff
+	AddedRelations   []ResourceRelation
+	RemovedRelations []ResourceRelation
+}
+
+// DiffTopology 比较同一资产子图在两个时间点的状态，结果均按 ID 升序排列
+func DiffTopology(before, after Topology) TopologyDiff {
+	var diff TopologyDiff
+
+	beforeRs := lo.KeyBy(before.Resources, func(r Resource) int64 { return r.ID })
+	afterRs := lo.KeyBy(after.Resources, func(r Resource) int64 { return r.ID })
+	for id, r := range afterRs {
+		old, ok := beforeRs[id]
+		if !ok {
+			diff.AddedResources = append(diff.AddedResources, r)
+			continue
+		}
+		if fields := diffFields(old.Data, r.Data); len(fields) > 0 {
+			diff.ChangedResources = append(diff.ChangedResources, ResourceDiff{
+				ResourceID: id,
+				ModelUID:   r.ModelUID,
+				Name:       r.Name,
+				Fields:     fields,
+			})
+		}
+	}
+	for id, r := range beforeRs {
+		if _, ok := afterRs[id]; !ok {
+			diff.RemovedResources = append(diff.RemovedResources, r)
+		}
+	}
+
+	beforeRrs := lo.KeyBy(before.Relations, func(rr ResourceRelation) int64 { return rr.ID })
+	afterRrs := lo.KeyBy(after.Relations, func(rr ResourceRelation) int64 { return rr.ID })
+	for id, rr := range afterRrs {
+		if _, ok := beforeRrs[id]; !ok {
+			diff.AddedRelations = append(diff.AddedRelations, rr)
+		}
+	}
+	for id, rr := range beforeRrs {
+		if _, ok := afterRrs[id]; !ok {
+			diff.RemovedRelations = append(diff.RemovedRelations, rr)
+		}
+	}
+
+	sortResources := func(rs []Resource) {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].ID < rs[j].ID })
+	}
+	sortRelations := func(rrs []ResourceRelation) {
+		sort.Slice(rrs, func(i, j int) bool { return rrs[i].ID < rrs[j].ID })
+	}
+	sortResources(diff.AddedResources)
+	sortResources(diff.RemovedResources)
+	sortRelations(diff.AddedRelations)
+	sortRelations(diff.RemovedRelations)
+	sort.Slice(diff.ChangedResources, func(i, j int) bool {
+		return diff.ChangedResources[i].ResourceID < diff.ChangedResources[j].ResourceID
+	})
+
+	return diff
+}
+
+func diffFields(before, after mongox.MapStr) []FieldDiff {
+	keys := lo.Uniq(append(lo.Keys(before), lo.Keys(after)...))
+	sort.Strings(keys)
+
+	return lo.FilterMap(keys, func(key string, _ int) (FieldDiff, bool) {
+		b, a := before[key], after[key]
+		return FieldDiff{Field: key, Before: b, After: a}, !reflect.DeepEqual(b, a)
+	})
+}
+
+func cloneMapStr(src mongox.MapStr) mongox.MapStr {
+	dst := make(mongox.MapStr, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayResource(t *testing.T) {
+	snapshot := &ResourceSnapshot{
+		ResourceID:   1,
+		ModelUID:     "host",
+		Data:         mongox.MapStr{"name": "host-1", "ip": "10.0.0.1", "env": "prod"},
+		SnapshotTime: 100,
+	}
+	changes := []ResourceChange{
+		{ResourceID: 1, ModelUID: "host", Action: ChangeActionUpdate, Ctime: 50,
+			Data: mongox.MapStr{"name": "stale"}},
+		{ResourceID: 1, ModelUID: "host", Action: ChangeActionUpdate, Ctime: 200,
+			Data: mongox.MapStr{"name": "host-1", "ip": "10.0.0.2", "env": "prod"}},
+		{ModelUID: "host", Action: ChangeActionUnset, Ctime: 300, Fields: []string{"env"}},
+		{ModelUID: "app", Action: ChangeActionUnset, Ctime: 350, Fields: []string{"ip"}},
+		{ResourceID: 1, ModelUID: "host", Action: ChangeActionDelete, Ctime: 400},
+	}
+
+	tests := []struct {
+		name       string
+		snapshot   *ResourceSnapshot
+		changes    []ResourceChange
+		asOf       int64
+		wantExists bool
+		wantData   mongox.MapStr
+	}{
+		{name: "before any history", snapshot: snapshot, changes: changes, asOf: 40},
+		{name: "change before snapshot", snapshot: snapshot, changes: changes, asOf: 99, wantExists: true,
+			wantData: mongox.MapStr{"name": "stale"}},
+		{name: "snapshot only", snapshot: snapshot, changes: changes, asOf: 150, wantExists: true,
+			wantData: mongox.MapStr{"name": "host-1", "ip": "10.0.0.1", "env": "prod"}},
+		{name: "update after snapshot", snapshot: snapshot, changes: changes, asOf: 250, wantExists: true,
+			wantData: mongox.MapStr{"name": "host-1", "ip": "10.0.0.2", "env": "prod"}},
+		{name: "model level unset", snapshot: snapshot, changes: changes, asOf: 380, wantExists: true,
+			wantData: mongox.MapStr{"name": "host-1", "ip": "10.0.0.2"}},
+		{name: "deleted", snapshot: snapshot, changes: changes, asOf: 400},
+		{name: "created without snapshot", asOf: 20, wantExists: true,
+			changes: []ResourceChange{
+				{ResourceID: 2, ModelUID: "host", Action: ChangeActionCreate, Ctime: 10,
+					Data: mongox.MapStr{"name": "host-2"}},
+			},
+			wantData: mongox.MapStr{"name": "host-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ReplayResource(tt.snapshot, tt.changes, tt.asOf)
+			assert.Equal(t, tt.wantExists, ok)
+			if !tt.wantExists {
+				return
+			}
+			assert.Equal(t, tt.wantData, got.Data)
+			assert.Equal(t, tt.wantData["name"], got.Name)
+		})
+	}
+
+	// 回放不能修改快照本身
+	assert.Equal(t, "prod", snapshot.Data["env"])
+}
+
+func TestDiffTopology(t *testing.T) {
+	before := Topology{
+		Resources: []Resource{
+			{ID: 1, Name: "host-1", ModelUID: "host", Data: mongox.MapStr{"name": "host-1", "ip": "10.0.0.1"}},
+			{ID: 2, Name: "app-1", ModelUID: "app", Data: mongox.MapStr{"name": "app-1"}},
+			{ID: 3, Name: "db-1", ModelUID: "db", Data: mongox.MapStr{"name": "db-1"}},
+		},
+		Relations: []ResourceRelation{
+			{ID: 10, SourceResourceID: 1, TargetResourceID: 2},
+			{ID: 11, SourceResourceID: 2, TargetResourceID: 3},
+		},
+	}
+	after := Topology{
+		Resources: []Resource{
+			{ID: 1, Name: "host-1", ModelUID: "host", Data: mongox.MapStr{"name": "host-1", "ip": "10.0.0.2"}},
+			{ID: 2, Name: "app-1", ModelUID: "app", Data: mongox.MapStr{"name": "app-1"}},
+			{ID: 4, Name: "cache-1", ModelUID: "cache", Data: mongox.MapStr{"name": "cache-1"}},
+		},
+		Relations: []ResourceRelation{
+			{ID: 10, SourceResourceID: 1, TargetResourceID: 2},
+			{ID: 12, SourceResourceID: 2, TargetResourceID: 4},
+		},
+	}
+
+	diff := DiffTopology(before, after)
+	assert.Equal(t, []Resource{after.Resources[2]}, diff.AddedResources)
+	assert.Equal(t, []Resource{before.Resources[2]}, diff.RemovedResources)
+	assert.Equal(t, []ResourceDiff{{
+		ResourceID: 1,
+		ModelUID:   "host",
+		Name:       "host-1",
+		Fields:     []FieldDiff{{Field: "ip", Before: "10.0.0.1", After: "10.0.0.2"}},
+	}}, diff.ChangedResources)
+	assert.Equal(t, []ResourceRelation{after.Relations[1]}, diff.AddedRelations)
+	assert.Equal(t, []ResourceRelation{before.Relations[1]}, diff.RemovedRelations)
+}
@@ -0,0 +1,87 @@
+package domain
+
+// ImportMode 导入模式，以模型唯一索引 name 判断资产是否已存在
+type ImportMode string
+
+const (
+	// ImportModeCreate 只新增，资产已存在时该行报错
+	ImportModeCreate ImportMode = "create"
+	// ImportModeUpdate 只修改，资产不存在时该行报错
+	ImportModeUpdate ImportMode = "update"
+	// ImportModeUpsert 存在则修改，不存在则新增
+	ImportModeUpsert ImportMode = "upsert"
+)
+
+func (m ImportMode) Valid() bool {
+	return m == ImportModeCreate || m == ImportModeUpdate || m == ImportModeUpsert
+}
+
+// ImportJobStatus 导入任务状态
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "pending"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobSucceeded ImportJobStatus = "succeeded"
+	ImportJobFailed    ImportJobStatus = "failed"
+)
+
+// ImportRowError 行级导入错误，Cell 为出错单元格坐标（如 C5），无法定位到单元格时为空
+// 多模型工作簿导入时 Sheet 为出错行所在的工作表名称
+type ImportRowError struct {
+	Sheet    string
+	Row      int
+	Cell     string
+	FieldUid string
+	Message  string
+}
+
+// ImportSheetResult 多模型工作簿中单个工作表的导入统计
+type ImportSheetResult struct {
+	Sheet    string
+	ModelUID string // 资产关联工作表为空
+	Total    int
+	Inserted int
+	Updated  int
+	Failed   int
+}
+
+// ImportJob 异步导入任务
+// NOTE: 预演（DryRun）只校验并统计新增、修改及错误行数，不写入资产
+// 多模型工作簿（Workbook）按工作表索引或工作表名称识别模型，ModelUID 为空，各工作表的统计见 Sheets
+type ImportJob struct {
+	ID       int64
+	TenantID int64
+	ModelUID string
+	FileKey  string
+	FileName string
+	FormatOptions
+	Workbook bool
+	// Mapping 提交时的列映射快照，为空时按导入模板第二行的字段 UID 解析
+	Mapping   *ImportMapping
+	Mode      ImportMode
+	DryRun    bool
+	Status    ImportJobStatus
+	Total     int // 有效数据行数（不含空行）
+	Processed int
+	Inserted  int
+	Updated   int
+	Failed    int
+	Sheets    []ImportSheetResult // 按导入顺序排列，单模型导入时为空
+	// Errors 行级错误，最多保留前 MaxImportJobErrors 条，完整错误见 ReportKey 对应的批注文件
+	Errors    []ImportRowError
+	ReportKey string // 标注错误后的 Excel 文件，无错误时为空
+	Message   string // 任务级错误信息，如文件无法解析
+	CreatorID int64
+	Ctime     int64
+	Utime     int64
+	Ftime     int64 // 完成时间
+}
+
+// MaxImportJobErrors 任务记录中保留的行级错误数量上限
+const MaxImportJobErrors = 200
+
+// Finished 任务是否已结束
+func (j ImportJob) Finished() bool {
+	return j.Status == ImportJobSucceeded || j.Status == ImportJobFailed
+}
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TransformType 导入列转换类型
+type TransformType string
+
+const (
+	// TransformTrim 去除首尾空白
+	TransformTrim TransformType = "trim"
+	// TransformSplit 按分隔符拆分后取第 Index 段，Index 为负数时从末尾计数
+	TransformSplit TransformType = "split"
+	// TransformRegex 正则提取，有捕获组时取第一个捕获组，否则取整个匹配
+	TransformRegex TransformType = "regex"
+	// TransformValueMap 值映射，常用于将文件中的取值转换为下拉选项，未配置的取值保持不变
+	TransformValueMap TransformType = "value_map"
+	// TransformDate 按 Layout 解析日期并按 Output 输出
+	TransformDate TransformType = "date"
+)
+
+// defaultDateOutput 日期转换默认的输出格式
+const defaultDateOutput = "YYYY-MM-DD"
+
+// dateLayoutReplacer 将 YYYY-MM-DD HH:mm:ss 形式的日期格式转换为 Go 的时间格式
+var dateLayoutReplacer = strings.NewReplacer(
+	"YYYY", "2006", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "ss", "05",
+)
+
+// ColumnTransform 导入列转换规则，按配置顺序依次执行
+type ColumnTransform struct {
+	Type      TransformType
+	Separator string            // split 分隔符
+	Index     int               // split 取值段，从 0 开始
+	Pattern   string            // regex 正则表达式
+	Mapping   map[string]string // value_map 原始值 → 导入值
+	Layout    string            // date 输入格式，如 YYYY/MM/DD
+	Output    string            // date 输出格式，默认 YYYY-MM-DD
+}
+
+// ValueTransformer 编译后的列转换函数
+type ValueTransformer func(value string) (string, error)
+
+// compile 校验转换规则并编译为转换函数
+func (t ColumnTransform) compile() (ValueTransformer, error) {
+	switch t.Type {
+	case TransformTrim:
+		return func(value string) (string, error) {
+			return strings.TrimSpace(value), nil
+		}, nil
+	case TransformSplit:
+		if t.Separator == "" {
+			return nil, fmt.Errorf("拆分转换的分隔符不能为空")
+		}
+		return func(value string) (string, error) {
+			parts := strings.Split(value, t.Separator)
+			idx := t.Index
+			if idx < 0 {
+				idx += len(parts)
+			}
+			if idx < 0 || idx >= len(parts) {
+				return "", nil
+			}
+			return parts[idx], nil
+		}, nil
+	case TransformRegex:
+		re, err := regexp.Compile(t.Pattern)
+		if err != nil || t.Pattern == "" {
+			return nil, fmt.Errorf("正则表达式 %q 不合法", t.Pattern)
+		}
+		return func(value string) (string, error) {
+			match := re.FindStringSubmatch(value)
+			switch {
+			case match == nil:
+				return "", nil
+			case len(match) > 1:
+				return match[1], nil
+			default:
+				return match[0], nil
+			}
+		}, nil
+	case TransformValueMap:
+		if len(t.Mapping) == 0 {
+			return nil, fmt.Errorf("值映射转换至少需要一个映射")
+		}
+		return func(value string) (string, error) {
+			if mapped, ok := t.Mapping[value]; ok {
+				return mapped, nil
+			}
+			return value, nil
+		}, nil
+	case TransformDate:
+		if t.Layout == "" {
+			return nil, fmt.Errorf("日期转换的输入格式不能为空")
+		}
+		layout := dateLayoutReplacer.Replace(t.Layout)
+		output := dateLayoutReplacer.Replace(defaultDateOutput)
+		if t.Output != "" {
+			output = dateLayoutReplacer.Replace(t.Output)
+		}
+		return func(value string) (string, error) {
+			if value == "" {
+				return "", nil
+			}
+			date, err := time.Parse(layout, value)
+			if err != nil {
+				return "", fmt.Errorf("日期 %s 不符合格式 %s", value, t.Layout)
+			}
+			return date.Format(output), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的转换类型: %s", t.Type)
+	}
+}
+
+// ColumnMapping 文件列到模型字段的映射
+type ColumnMapping struct {
+	Source     string // 文件中的列名（表头文本），NDJSON 及 YAML 为对象的键
+	FieldUid   string
+	Transforms []ColumnTransform
+}
+
+// Transformer 校验并编译列的转换规则，没有转换规则时原样返回
+func (c ColumnMapping) Transformer() (ValueTransformer, error) {
+	transformers := make([]ValueTransformer, 0, len(c.Transforms))
+	for _, t := range c.Transforms {
+		fn, err := t.compile()
+		if err != nil {
+			return nil, fmt.Errorf("列 %s: %w", c.Source, err)
+		}
+		transformers = append(transformers, fn)
+	}
+
+	return func(value string) (string, error) {
+		var err error
+		for _, fn := range transformers {
+			if value, err = fn(value); err != nil {
+				return "", err
+			}
+		}
+		return value, nil
+	}, nil
+}
+
+// ImportMapping 导入列映射方案
+// NOTE: 供应商提供的表格与导入模板的字段 UID 不一致时，按列名映射到模型字段并在校验前转换取值；
+// 保存后可在同一模型的导入中复用，导入任务中保存提交时的映射快照
+type ImportMapping struct {
+	ID        int64
+	ModelUID  string
+	Name      string
+	HeaderRow int // 表头所在行（从 1 开始），数据从下一行开始，仅 Excel 及 CSV 生效，默认为 1
+	Columns   []ColumnMapping
+	CreatorID int64
+	Ctime     int64
+	Utime     int64
+}
+
+// Validate 校验映射配置：列名及字段不能为空，同一字段只能映射一次，转换规则合法
+func (m ImportMapping) Validate() error {
+	if m.HeaderRow < 0 {
+		return fmt.Errorf("表头行号不能为负数")
+	}
+	if len(m.Columns) == 0 {
+		return fmt.Errorf("至少需要映射一列")
+	}
+
+	fields := make(map[string]string, len(m.Columns))
+	for _, col := range m.Columns {
+		if strings.TrimSpace(col.Source) == "" || col.FieldUid == "" {
+			return fmt.Errorf("映射的列名及字段不能为空")
+		}
+		if source, ok := fields[col.FieldUid]; ok {
+			return fmt.Errorf("列 %s 与 %s 映射到同一字段 %s", col.Source, source, col.FieldUid)
+		}
+		fields[col.FieldUid] = col.Source
+
+		if _, err := col.Transformer(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DataStartRow 数据起始行号（从 1 开始）
+func (m ImportMapping) DataStartRow() int {
+	return max(m.HeaderRow, 1) + 1
+}
+
+// ColumnSuggestion 文件列的映射建议，Score 为列名与字段的相似度 (0~1)，没有匹配字段时 FieldUid 为空
+type ColumnSuggestion struct {
+	Source    string
+	FieldUid  string
+	FieldName string
+	Score     float64
+}
+
+// ImportMappingPreview 导入文件的表头识别结果及映射建议
+type ImportMappingPreview struct {
+	HeaderRow   int // 识别出的表头行，NDJSON 及 YAML 为 0
+	Headers     []string
+	Samples     [][]string // 表头之后的前几行数据，与 Headers 一一对应
+	Suggestions []ColumnSuggestion
+}
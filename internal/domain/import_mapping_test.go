@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnMapping_Transformer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		transforms []ColumnTransform
+		input      string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "去除空白后拆分取最后一段",
+			transforms: []ColumnTransform{{Type: TransformTrim}, {Type: TransformSplit, Separator: "/", Index: -1}},
+			input:      "  idc-a/rack-01 ",
+			want:       "rack-01",
+		},
+		{
+			name:       "拆分段不存在时为空",
+			transforms: []ColumnTransform{{Type: TransformSplit, Separator: ",", Index: 3}},
+			input:      "a,b",
+			want:       "",
+		},
+		{
+			name:       "正则取第一个捕获组",
+			transforms: []ColumnTransform{{Type: TransformRegex, Pattern: `ip=(\d+\.\d+\.\d+\.\d+)`}},
+			input:      "host ip=10.0.0.1 up",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "值映射未配置的取值保持不变",
+			transforms: []ColumnTransform{{Type: TransformValueMap, Mapping: map[string]string{"生产": "prod"}}},
+			input:      "test",
+			want:       "test",
+		},
+		{
+			name:       "值映射",
+			transforms: []ColumnTransform{{Type: TransformValueMap, Mapping: map[string]string{"生产": "prod"}}},
+			input:      "生产",
+			want:       "prod",
+		},
+		{
+			name:       "日期转换",
+			transforms: []ColumnTransform{{Type: TransformDate, Layout: "DD/MM/YYYY"}},
+			input:      "09/03/2024",
+			want:       "2024-03-09",
+		},
+		{
+			name:       "日期格式不符",
+			transforms: []ColumnTransform{{Type: TransformDate, Layout: "DD/MM/YYYY"}},
+			input:      "2024-03-09",
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fn, err := ColumnMapping{Source: "col", FieldUid: "f", Transforms: tc.transforms}.Transformer()
+			require.NoError(t, err)
+
+			got, err := fn(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImportMapping_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		columns []ColumnMapping
+		wantErr bool
+	}{
+		{
+			name:    "合法",
+			columns: []ColumnMapping{{Source: "主机名", FieldUid: "name"}, {Source: "IP", FieldUid: "ip"}},
+		},
+		{
+			name:    "没有映射列",
+			wantErr: true,
+		},
+		{
+			name:    "同一字段映射多次",
+			columns: []ColumnMapping{{Source: "主机名", FieldUid: "name"}, {Source: "名称", FieldUid: "name"}},
+			wantErr: true,
+		},
+		{
+			name: "正则不合法",
+			columns: []ColumnMapping{{Source: "IP", FieldUid: "ip",
+				Transforms: []ColumnTransform{{Type: TransformRegex, Pattern: "("}}}},
+			wantErr: true,
+		},
+		{
+			name: "不支持的转换类型",
+			columns: []ColumnMapping{{Source: "IP", FieldUid: "ip",
+				Transforms: []ColumnTransform{{Type: "upper"}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ImportMapping{Columns: tc.columns}.Validate()
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
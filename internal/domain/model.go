@@ -2,7 +2,9 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type Model struct {
@@ -30,16 +32,27 @@ func (m *Model) EnsureDeletable() error {
 	return nil
 }
 
+// maxSheetNameLength Excel 工作表名称的最大字符数
+const maxSheetNameLength = 31
+
+// sheetNameReplacer 替换 Excel 工作表名称中不允许出现的字符
+var sheetNameReplacer = strings.NewReplacer(":", "_", "\\", "_", "/", "_", "?", "_", "*", "_", "[", "_", "]", "_")
+
+// SheetName 导出时的工作表名称，格式为 name(uid)
+// NOTE: 超长时优先按字符截断模型名称，唯一标识本身超长时整体截断，名称不再可解析，
+// 工作簿导入时通过 SheetIndexName 工作表或当前模型的工作表名称识别对应的模型
 func (m *Model) SheetName() string {
-	name := fmt.Sprintf("%s(%s)", m.Name, m.UID)
-	if len(name) > 31 {
-		// 如果超长,优先保留 model_name
-		maxNameLen := 31 - len(m.UID) - 2 // 减去括号和 UID 的长度
-		if maxNameLen > 0 {
-			name = fmt.Sprintf("%s(%s)", m.Name[:maxNameLen], m.UID)
-		} else {
-			name = name[:31]
-		}
+	return TruncateSheetName(sheetNameReplacer.Replace(m.Name), "("+m.UID+")")
+}
+
+// TruncateSheetName 按字符截断 name 使 name+suffix 不超过 Excel 工作表名称的最大长度，suffix 本身超长时整体截断
+func TruncateSheetName(name, suffix string) string {
+	full := []rune(name + suffix)
+	if len(full) <= maxSheetNameLength {
+		return string(full)
+	}
+	if keep := maxSheetNameLength - utf8.RuneCountInString(suffix); keep >= 0 {
+		return string([]rune(name)[:keep]) + suffix
 	}
-	return name
+	return string(full[:maxSheetNameLength])
 }
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestModelSheetName(t *testing.T) {
+	tests := []struct {
+		name  string
+		model Model
+		want  string
+	}{
+		{name: "未超长", model: Model{Name: "主机", UID: "host"}, want: "主机(host)"},
+		{name: "非法字符", model: Model{Name: "网络/安全[设备]", UID: "net"}, want: "网络_安全_设备_(net)"},
+		{
+			name:  "超长时按字符截断名称",
+			model: Model{Name: "这是一个名称非常非常非常非常非常非常长的模型", UID: "long_model"},
+			want:  "这是一个名称非常非常非常非常非常非常长(long_model)",
+		},
+		{
+			name:  "唯一标识超长时整体截断",
+			model: Model{Name: "主机", UID: "a_very_long_model_unique_identifier"},
+			want:  "主机(a_very_long_model_unique_ide",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.model.SheetName()
+			if got != tt.want {
+				t.Fatalf("SheetName() = %s, want %s", got, tt.want)
+			}
+			if utf8.RuneCountInString(got) > maxSheetNameLength {
+				t.Fatalf("SheetName() 超过 %d 个字符: %s", maxSheetNameLength, got)
+			}
+		})
+	}
+}
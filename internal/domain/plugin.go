@@ -6,6 +6,7 @@ type Plugin = plugin.Plugin
 type PluginActionSpec = plugin.ActionSpec
 type PluginFilter = plugin.Filter
 type PluginBinding = plugin.Binding
+type PluginCredential = plugin.Credential
 type PluginResourceSpec = plugin.ResourceSpec
 type PluginResourceAction = plugin.ResourceAction
 type PluginResolvedInput = plugin.ResolvedInput
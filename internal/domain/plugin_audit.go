@@ -0,0 +1,43 @@
+package domain
+
+const (
+	PluginAuditSourceHTTP     = "http"
+	PluginAuditSourceGRPC     = "grpc"
+	PluginAuditSourceProxy    = "proxy"
+	PluginAuditSourceInternal = "internal"
+)
+
+// PluginAuditLog 插件动作审计日志，记录动作上下文解析与插件运行时代理请求
+type PluginAuditLog struct {
+	ID          int64   `json:"id"`
+	PluginID    string  `json:"plugin_id"`
+	Action      string  `json:"action"`
+	ResourceIDs []int64 `json:"resource_ids"`
+	UserID      int64   `json:"user_id"`
+	TenantID    int64   `json:"tenant_id"`
+	Source      string  `json:"source"` // 调用来源：http / grpc / proxy / internal
+	SourceIP    string  `json:"source_ip"`
+	RequestID   string  `json:"request_id,omitempty"`
+	Method      string  `json:"method,omitempty"` // 代理请求方法
+	Path        string  `json:"path,omitempty"`   // 代理请求路径
+	Success     bool    `json:"success"`
+	StatusCode  int     `json:"status_code,omitempty"` // 代理请求的插件响应状态码
+	Error       string  `json:"error,omitempty"`
+	DurationMs  int64   `json:"duration_ms"`
+	Ctime       int64   `json:"ctime"`
+	ExpireAt    int64   `json:"-"` // 过期时间，Unix 毫秒，到期后由 Mongo TTL 索引清理
+}
+
+// PluginAuditQuery 审计日志查询条件，零值字段不参与过滤
+type PluginAuditQuery struct {
+	PluginID   string
+	Action     string
+	UserID     int64
+	ResourceID int64
+	Source     string
+	Success    *bool
+	StartTime  int64 // Unix 毫秒
+	EndTime    int64 // Unix 毫秒
+	Offset     int64
+	Limit      int64
+}
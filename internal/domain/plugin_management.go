@@ -12,6 +12,7 @@ type PluginListItem struct {
 	BindingCount int                  `json:"binding_count"`
 	BoundModels  []PluginBoundModel   `json:"bound_models"`
 	Actions      []pluginx.ActionSpec `json:"actions"`
+	Health       *pluginx.Health      `json:"health,omitempty"`
 	UpdatedAt    int64                `json:"updated_at"`
 }
 
@@ -67,3 +68,13 @@ type SavePluginBindings struct {
 	PluginID string
 	Bindings []pluginx.Binding
 }
+
+// BatchResolvePluginAction 批量解析插件动作，资源 ID 与模型过滤条件二选一
+type BatchResolvePluginAction struct {
+	PluginID     string
+	Action       string
+	ResourceIDs  []int64
+	ModelUID     string
+	FilterGroups []FilterGroup
+	Params       map[string]any
+}
@@ -0,0 +1,51 @@
+package domain
+
+import pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+
+// 插件导入 Schema 时可能新建的对象类型
+const (
+	PluginSchemaKindModel         = "model"
+	PluginSchemaKindAttribute     = "attribute"
+	PluginSchemaKindRelationType  = "relation_type"
+	PluginSchemaKindModelRelation = "model_relation"
+)
+
+// PluginSchemaOwnership 插件导入 Schema 时新建的对象，卸载插件时只清理这些对象
+// NOTE: 导入前已存在的模型、字段、关联类型不记录归属，卸载时不会被删除
+type PluginSchemaOwnership struct {
+	ID       int64  `json:"id"`
+	TenantID int64  `json:"tenant_id"`
+	PluginID string `json:"plugin_id"`
+	Kind     string `json:"kind"`
+	ModelUID string `json:"model_uid,omitempty"` // 字段所属模型、模型关联的源模型
+	UID      string `json:"uid"`                 // 模型 UID、字段 UID、关联类型 UID 或模型关联名称
+	Ctime    int64  `json:"ctime"`
+}
+
+// UninstallPlugin 卸载插件请求
+type UninstallPlugin struct {
+	PluginID      string
+	CleanupSchema bool // 同时删除插件导入时新建的模型、字段、关联类型
+	Force         bool // 模型仍有资产数据时一并删除资产
+}
+
+// PluginSchemaRetained 卸载时未能清理的对象
+type PluginSchemaRetained struct {
+	TenantID int64  `json:"tenant_id"`
+	Kind     string `json:"kind"`
+	ModelUID string `json:"model_uid,omitempty"`
+	UID      string `json:"uid"`
+	Reason   string `json:"reason"`
+}
+
+// PluginUninstallResult 插件卸载结果
+type PluginUninstallResult struct {
+	PluginID              string                 `json:"plugin_id"`
+	DeletedBindings       int64                  `json:"deleted_bindings"`
+	RemovedModelRelations []string               `json:"removed_model_relations,omitempty"`
+	RemovedRelationTypes  []string               `json:"removed_relation_types,omitempty"`
+	RemovedAttributes     []pluginx.FieldRef     `json:"removed_attributes,omitempty"`
+	RemovedModels         []string               `json:"removed_models,omitempty"`
+	DeletedResources      int64                  `json:"deleted_resources"`
+	Retained              []PluginSchemaRetained `json:"retained,omitempty"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+
+const (
+	PluginVersionSourceImport   = "import"
+	PluginVersionSourceRollback = "rollback"
+)
+
+// PluginVersion 插件定义的不可变版本快照
+type PluginVersion struct {
+	ID         int64              `json:"id"`
+	PluginID   string             `json:"plugin_id"`
+	Version    string             `json:"version"`
+	Revision   int64              `json:"revision"` // 插件内自增的修订号
+	Checksum   string             `json:"checksum"`
+	Source     string             `json:"source"` // 快照来源：import / rollback
+	Definition pluginx.Definition `json:"definition"`
+	Ctime      int64              `json:"ctime"`
+}
+
+// PluginFieldUsage 升级将删除的字段及其使用情况
+type PluginFieldUsage struct {
+	pluginx.FieldRef
+	Resources int64    `json:"resources"` // 字段有值的资源数量
+	Bindings  []string `json:"bindings"`  // 映射了该字段的绑定 UID
+}
+
+func (u PluginFieldUsage) InUse() bool {
+	return u.Resources > 0 || len(u.Bindings) > 0
+}
+
+// PluginUpgradePlan 插件升级预览，Blocked 不为空时升级会被拒绝
+type PluginUpgradePlan struct {
+	PluginID     string                 `json:"plugin_id"`
+	FromRevision int64                  `json:"from_revision"`
+	Diff         pluginx.DefinitionDiff `json:"diff"`
+	Blocked      []PluginFieldUsage     `json:"blocked,omitempty"`
+}
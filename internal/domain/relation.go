@@ -93,3 +93,17 @@ func (r *RelationType) Validate() error {
 	}
 	return nil
 }
+
+// RelationImpact 删除模型关联前的影响评估
+type RelationImpact struct {
+	ModelRelation ModelRelation
+	Total         int64              // 受影响的资源关联数量
+	Samples       []ResourceRelation // 受影响的资源关联样例，用于前端展示
+}
+
+// RelationTypeImpact 删除关联类型前的影响评估
+type RelationTypeImpact struct {
+	RelationType   RelationType
+	ModelRelations []RelationImpact
+	Total          int64 // 所有模型关联下受影响的资源关联总数
+}
@@ -46,3 +46,55 @@ type ResourceDiagram struct {
 	SRC []ResourceRelation
 	DST []ResourceRelation
 }
+
+// RelationMigration 资源关联迁移计划
+type RelationMigration struct {
+	MoveIDs      []int64 // 需要改挂到目标关联定义下的资源关联
+	DuplicateIDs []int64 // 目标关联定义下已存在相同端点，迁移时直接移除
+}
+
+// PlanRelationMigration 计算将 moving 迁移到目标关联定义 to 下的执行计划
+// NOTE: existing 为目标关联定义下已存在的资源关联，迁移后的整体数据必须满足 to 的映射约束
+func PlanRelationMigration(to ModelRelation, moving, existing []ResourceRelation) (RelationMigration, error) {
+	type pair struct {
+		src int64
+		dst int64
+	}
+
+	pairs := make(map[pair]struct{}, len(existing)+len(moving))
+	srcCount := make(map[int64]int, len(existing))
+	dstCount := make(map[int64]int, len(existing))
+	for _, rr := range existing {
+		pairs[pair{rr.SourceResourceID, rr.TargetResourceID}] = struct{}{}
+		srcCount[rr.SourceResourceID]++
+		dstCount[rr.TargetResourceID]++
+	}
+
+	var plan RelationMigration
+	for _, rr := range moving {
+		key := pair{rr.SourceResourceID, rr.TargetResourceID}
+		if _, ok := pairs[key]; ok {
+			plan.DuplicateIDs = append(plan.DuplicateIDs, rr.ID)
+			continue
+		}
+		pairs[key] = struct{}{}
+		srcCount[rr.SourceResourceID]++
+		dstCount[rr.TargetResourceID]++
+		plan.MoveIDs = append(plan.MoveIDs, rr.ID)
+	}
+
+	checkSrc := to.Mapping == MappingOneToOne
+	checkDst := to.Mapping == MappingOneToOne || to.Mapping == MappingOneToMany
+	for _, rr := range moving {
+		if checkSrc && srcCount[rr.SourceResourceID] > 1 {
+			return RelationMigration{}, fmt.Errorf("迁移后源端资源（模型：%s，ID：%d）存在多条关联，违反 %s 映射约束",
+				rr.SourceModelUID, rr.SourceResourceID, to.Mapping)
+		}
+		if checkDst && dstCount[rr.TargetResourceID] > 1 {
+			return RelationMigration{}, fmt.Errorf("迁移后目标端资源（模型：%s，ID：%d）存在多条关联，违反 %s 映射约束",
+				rr.TargetModelUID, rr.TargetResourceID, to.Mapping)
+		}
+	}
+
+	return plan, nil
+}
@@ -30,3 +30,76 @@ func TestModelRelationValidateMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanRelationMigration(t *testing.T) {
+	edge := func(id, src, dst int64) ResourceRelation {
+		return ResourceRelation{ID: id, SourceModelUID: "host", TargetModelUID: "app",
+			SourceResourceID: src, TargetResourceID: dst}
+	}
+
+	tests := []struct {
+		name          string
+		mapping       string
+		moving        []ResourceRelation
+		existing      []ResourceRelation
+		wantMove      []int64
+		wantDuplicate []int64
+		wantErr       bool
+	}{
+		{
+			name:     "多对多直接迁移",
+			mapping:  MappingManyToMany,
+			moving:   []ResourceRelation{edge(1, 10, 20), edge(2, 10, 21)},
+			wantMove: []int64{1, 2},
+		},
+		{
+			name:          "目标已存在相同端点",
+			mapping:       MappingManyToMany,
+			moving:        []ResourceRelation{edge(1, 10, 20), edge(2, 11, 20)},
+			existing:      []ResourceRelation{edge(9, 10, 20)},
+			wantMove:      []int64{2},
+			wantDuplicate: []int64{1},
+		},
+		{
+			name:     "一对多目标端冲突",
+			mapping:  MappingOneToMany,
+			moving:   []ResourceRelation{edge(1, 11, 20)},
+			existing: []ResourceRelation{edge(9, 10, 20)},
+			wantErr:  true,
+		},
+		{
+			name:    "一对一源端冲突",
+			mapping: MappingOneToOne,
+			moving:  []ResourceRelation{edge(1, 10, 20), edge(2, 10, 21)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := ModelRelation{SourceModelUID: "host", TargetModelUID: "app", RelationTypeUID: "run", Mapping: tt.mapping}
+			plan, err := PlanRelationMigration(to, tt.moving, tt.existing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanRelationMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !equalIDs(plan.MoveIDs, tt.wantMove) || !equalIDs(plan.DuplicateIDs, tt.wantDuplicate) {
+				t.Fatalf("plan = %+v, want move %v duplicate %v", plan, tt.wantMove, tt.wantDuplicate)
+			}
+		})
+	}
+}
+
+func equalIDs(got, want []int64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
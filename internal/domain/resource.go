@@ -11,6 +11,7 @@ const (
 	OperatorContains Operator = "contains"
 	OperatorGt       Operator = "gt"
 	OperatorLt       Operator = "lt"
+	OperatorIn       Operator = "in"
 )
 
 type Resource struct {
@@ -20,12 +21,6 @@ type Resource struct {
 	Data     mongox.MapStr `json:"data"`
 }
 
-type SearchResource struct {
-	ModelUid string
-	Total    int `json:"total"`
-	Data     []mongox.MapStr
-}
-
 type Condition struct {
 	Name      string `json:"name"`      // 过滤名称
 	Condition string `json:"condition"` // 过滤条件
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser 标准五段式 cron 表达式（分 时 日 月 周），同时支持 @daily、@every 1h 等描述符
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ScheduledExportModel 定时导出的模型及筛选条件
+type ScheduledExportModel struct {
+	ModelUID     string
+	FilterGroups []FilterGroup
+	Fields       []string // 导出字段，为空时导出全部字段
+}
+
+// ScheduledExport 定时导出配置
+// NOTE: 由后台调度器按 cron 表达式执行，每个模型导出为单独的文件写入对象存储，执行记录见 ScheduledExportRun
+type ScheduledExport struct {
+	ID       int64
+	TenantID int64
+	Name     string
+	CronExpr string // 按服务所在时区计算执行时间
+	Models   []ScheduledExportModel
+	FormatOptions
+	RetentionDays int  // 导出文件保留天数，为 0 时不清理
+	Enabled       bool // 停用后不再调度，保留历史记录
+	NextRunAt     int64
+	LastRunAt     int64
+	CreatorID     int64
+	Ctime         int64
+	Utime         int64
+}
+
+// Validate 校验名称、cron 表达式、模型列表及保留天数
+func (s ScheduledExport) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("定时导出名称不能为空")
+	}
+	if _, err := s.NextRun(time.Now()); err != nil {
+		return err
+	}
+	if len(s.Models) == 0 {
+		return fmt.Errorf("至少需要导出一个模型")
+	}
+
+	seen := make(map[string]struct{}, len(s.Models))
+	for _, m := range s.Models {
+		if m.ModelUID == "" {
+			return fmt.Errorf("模型唯一标识不能为空")
+		}
+		if _, ok := seen[m.ModelUID]; ok {
+			return fmt.Errorf("模型 %s 重复", m.ModelUID)
+		}
+		seen[m.ModelUID] = struct{}{}
+	}
+
+	if s.RetentionDays < 0 {
+		return fmt.Errorf("保留天数不能为负数")
+	}
+	return nil
+}
+
+// NextRun 计算 after 之后的下一次执行时间
+func (s ScheduledExport) NextRun(after time.Time) (time.Time, error) {
+	schedule, err := cronParser.Parse(s.CronExpr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cron 表达式 %q 不合法: %w", s.CronExpr, err)
+	}
+	return schedule.Next(after), nil
+}
+
+// RetentionBefore 早于该时间的执行记录需要清理，不清理时返回 0
+func (s ScheduledExport) RetentionBefore(now time.Time) int64 {
+	if s.RetentionDays <= 0 {
+		return 0
+	}
+	return now.AddDate(0, 0, -s.RetentionDays).UnixMilli()
+}
+
+// ScheduledExportRunStatus 定时导出执行状态
+type ScheduledExportRunStatus string
+
+const (
+	ScheduledExportRunning   ScheduledExportRunStatus = "running"
+	ScheduledExportSucceeded ScheduledExportRunStatus = "succeeded"
+	// ScheduledExportFailed 任一模型导出失败，其余模型的文件仍然保留
+	ScheduledExportFailed ScheduledExportRunStatus = "failed"
+)
+
+// ScheduledExportFile 单个模型的导出文件
+type ScheduledExportFile struct {
+	ModelUID string
+	FileKey  string // 导出失败时为空
+	Rows     int
+	Size     int64
+	Message  string // 导出失败原因
+}
+
+// ScheduledExportRun 定时导出执行记录
+type ScheduledExportRun struct {
+	ID         int64
+	TenantID   int64
+	ScheduleID int64
+	Status     ScheduledExportRunStatus
+	Files      []ScheduledExportFile
+	Message    string
+	Pruned     bool // 超过保留天数，导出文件已删除
+	Ctime      int64
+	Utime      int64
+	Ftime      int64
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledExport_NextRun(t *testing.T) {
+	after := time.Date(2024, 3, 9, 1, 30, 0, 0, time.Local)
+	testCases := []struct {
+		name     string
+		cronExpr string
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "每天凌晨两点",
+			cronExpr: "0 2 * * *",
+			want:     time.Date(2024, 3, 9, 2, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "描述符",
+			cronExpr: "@daily",
+			want:     time.Date(2024, 3, 10, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "不支持秒级表达式",
+			cronExpr: "0 0 2 * * *",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := ScheduledExport{CronExpr: tc.cronExpr}.NextRun(after)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, next)
+		})
+	}
+}
+
+func TestScheduledExport_Validate(t *testing.T) {
+	valid := ScheduledExport{Name: "nightly", CronExpr: "0 2 * * *", Models: []ScheduledExportModel{{ModelUID: "host"}}}
+
+	testCases := []struct {
+		name    string
+		modify  func(se *ScheduledExport)
+		wantErr bool
+	}{
+		{name: "合法", modify: func(se *ScheduledExport) {}},
+		{name: "名称为空", modify: func(se *ScheduledExport) { se.Name = " " }, wantErr: true},
+		{name: "cron 不合法", modify: func(se *ScheduledExport) { se.CronExpr = "nightly" }, wantErr: true},
+		{name: "没有模型", modify: func(se *ScheduledExport) { se.Models = nil }, wantErr: true},
+		{
+			name: "模型重复",
+			modify: func(se *ScheduledExport) {
+				se.Models = []ScheduledExportModel{{ModelUID: "host"}, {ModelUID: "host"}}
+			},
+			wantErr: true,
+		},
+		{name: "保留天数为负数", modify: func(se *ScheduledExport) { se.RetentionDays = -1 }, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			se := valid
+			tc.modify(&se)
+			err := se.Validate()
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestScheduledExport_RetentionBefore(t *testing.T) {
+	now := time.Date(2024, 3, 9, 2, 0, 0, 0, time.Local)
+	assert.Zero(t, ScheduledExport{}.RetentionBefore(now), "未配置保留天数时不清理")
+	assert.Equal(t, time.Date(2024, 3, 2, 2, 0, 0, 0, time.Local).UnixMilli(),
+		ScheduledExport{RetentionDays: 7}.RetentionBefore(now))
+}
@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/samber/lo"
+)
+
+const (
+	// highlightContext 高亮片段中命中位置前后保留的字符数
+	highlightContext = 20
+	// nameFieldBoost 名称字段命中时的权重倍数
+	nameFieldBoost = 2
+)
+
+// SearchTerm 检索词，Field 为空时匹配资产的任意字段
+// Value 中的 * 为通配符，不包含通配符时按子串匹配，例如 10.1.* 为前缀匹配，*.prod 为后缀匹配
+type SearchTerm struct {
+	Field string
+	Value string
+}
+
+// SearchQuery 全局检索条件
+type SearchQuery struct {
+	Terms    []SearchTerm
+	ModelUid string // 指定模型时只返回该模型分组，用于分组内翻页
+	Offset   int64  // 每个模型分组内的偏移量
+	Limit    int64  // 每个模型分组返回的数量
+}
+
+// SearchHighlight 命中字段的高亮片段，命中部分使用 <em></em> 包裹
+type SearchHighlight struct {
+	Field   string
+	Snippet string
+}
+
+// SearchHit 单个命中的资产
+type SearchHit struct {
+	Resource   Resource
+	Score      float64
+	Highlights []SearchHighlight
+}
+
+// SearchDocument 检索索引中的资产文档，Data 中不包含安全字段
+type SearchDocument struct {
+	TenantID int64
+	ID       int64
+	ModelUID string
+	Data     mongox.MapStr
+}
+
+// SearchGroup 按模型分组的检索结果，Total 为分组命中总数
+// Truncated 为 true 时候选资产达到召回上限，Total 只是下限，需要收窄检索词
+type SearchGroup struct {
+	ModelUid  string
+	Total     int
+	Truncated bool
+	Hits      []SearchHit
+}
+
+var (
+	searchFieldRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// hexGroupRegexp IPv6 地址的首段，例如 fe80::1 不应被解析为 fe80 字段
+	hexGroupRegexp = regexp.MustCompile(`^[0-9A-Fa-f]{1,4}$`)
+)
+
+// ParseSearchText 解析检索文本，空白分隔多个检索词，双引号包裹的内容作为一个整体
+// field:value 形式的检索词只匹配指定字段
+func ParseSearchText(text string) []SearchTerm {
+	var (
+		terms   []SearchTerm
+		buf     strings.Builder
+		inQuote bool
+	)
+
+	flush := func() {
+		token := buf.String()
+		buf.Reset()
+		if token == "" {
+			return
+		}
+
+		term := SearchTerm{Value: token}
+		if field, value, ok := strings.Cut(token, ":"); ok && isSearchField(field, value) {
+			term = SearchTerm{Field: field, Value: value}
+		}
+		if strings.Trim(term.Value, "*") == "" {
+			return
+		}
+		terms = append(terms, term)
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	flush()
+
+	return terms
+}
+
+func isSearchField(field, value string) bool {
+	if value == "" || !searchFieldRegexp.MatchString(field) {
+		return false
+	}
+	return !hexGroupRegexp.MatchString(field) || !strings.Contains(value, ":")
+}
+
+// Pattern 将检索词转换为不区分大小写的正则表达式
+func (t SearchTerm) Pattern() string {
+	if !strings.Contains(t.Value, "*") {
+		return regexp.QuoteMeta(t.Value)
+	}
+
+	parts := strings.Split(t.Value, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, ".*") + "$"
+}
+
+// Literals 检索词中去除通配符后的字面片段，命中的文本中必定包含这些片段
+func (t SearchTerm) Literals() []string {
+	return lo.Compact(strings.Split(t.Value, "*"))
+}
+
+// MatchResource 计算资产与检索词的匹配结果，所有检索词都命中时才视为命中
+// excluded 中的字段（例如安全字段）不参与匹配，也不会出现在高亮片段中
+func MatchResource(r Resource, terms []SearchTerm, excluded []string) (SearchHit, bool) {
+	if len(terms) == 0 {
+		return SearchHit{}, false
+	}
+
+	skip := make(map[string]struct{}, len(excluded))
+	for _, field := range excluded {
+		skip[field] = struct{}{}
+	}
+
+	fields := make([]string, 0, len(r.Data))
+	for field := range r.Data {
+		if _, ok := skip[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	hit := SearchHit{Resource: r}
+	highlighted := make(map[string]struct{})
+	for _, term := range terms {
+		re := regexp.MustCompile("(?i)" + term.Pattern())
+
+		best := 0.0
+		for _, field := range fields {
+			if term.Field != "" && term.Field != field {
+				continue
+			}
+
+			value, ok := SearchableValue(r.Data[field])
+			if !ok {
+				continue
+			}
+			loc := re.FindStringIndex(value)
+			if loc == nil {
+				continue
+			}
+
+			score := matchScore(term, value, loc)
+			if field == "name" {
+				score *= nameFieldBoost
+			}
+			best = max(best, score)
+
+			if _, ok = highlighted[field]; !ok {
+				highlighted[field] = struct{}{}
+				hit.Highlights = append(hit.Highlights, SearchHighlight{
+					Field:   field,
+					Snippet: highlightSnippet(value, loc),
+				})
+			}
+		}
+
+		if best == 0 {
+			return SearchHit{}, false
+		}
+		hit.Score += best
+	}
+
+	return hit, true
+}
+
+// matchScore 完全匹配得分最高，其次为前缀匹配，其余子串匹配得分最低
+func matchScore(term SearchTerm, value string, loc []int) float64 {
+	switch {
+	case strings.EqualFold(strings.Trim(term.Value, "*"), value):
+		return 3
+	case loc[0] == 0 && !strings.HasPrefix(term.Value, "*"):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SearchableValue 只有标量值参与检索
+func SearchableValue(v any) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return val, val != ""
+	case bool, int, int32, int64, float32, float64:
+		return fmt.Sprint(val), true
+	default:
+		return "", false
+	}
+}
+
+// highlightSnippet 截取命中位置前后的文本并包裹高亮标签，其余内容做 HTML 转义
+func highlightSnippet(value string, loc []int) string {
+	start, end := loc[0], loc[1]
+
+	prefix, suffix := value[:start], value[end:]
+	ellipsisBefore, ellipsisAfter := "", ""
+	if utf8.RuneCountInString(prefix) > highlightContext {
+		r := []rune(prefix)
+		prefix, ellipsisBefore = string(r[len(r)-highlightContext:]), "…"
+	}
+	if utf8.RuneCountInString(suffix) > highlightContext {
+		r := []rune(suffix)
+		suffix, ellipsisAfter = string(r[:highlightContext]), "…"
+	}
+
+	return ellipsisBefore + html.EscapeString(prefix) +
+		"<em>" + html.EscapeString(value[start:end]) + "</em>" +
+		html.EscapeString(suffix) + ellipsisAfter
+}
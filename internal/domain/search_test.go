@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []SearchTerm
+	}{
+		{name: "empty", text: "   "},
+		{name: "free terms", text: "web01  prod", want: []SearchTerm{{Value: "web01"}, {Value: "prod"}}},
+		{name: "field scoped", text: "ip:10.1.* name:web", want: []SearchTerm{
+			{Field: "ip", Value: "10.1.*"}, {Field: "name", Value: "web"}}},
+		{name: "quoted phrase", text: `desc:"core switch" rack`, want: []SearchTerm{
+			{Field: "desc", Value: "core switch"}, {Value: "rack"}}},
+		{name: "ipv6 is not field scoped", text: "fe80::1 fe80:0:0::1", want: []SearchTerm{
+			{Value: "fe80::1"}, {Value: "fe80:0:0::1"}}},
+		{name: "ipv6 value in field", text: "ip:fe80::1", want: []SearchTerm{{Field: "ip", Value: "fe80::1"}}},
+		{name: "empty value keeps colon", text: "ip:", want: []SearchTerm{{Value: "ip:"}}},
+		{name: "bare wildcard ignored", text: "* ip:*", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseSearchText(tt.text))
+		})
+	}
+}
+
+func TestSearchTermPattern(t *testing.T) {
+	assert.Equal(t, `10\.1\.2`, SearchTerm{Value: "10.1.2"}.Pattern())
+	assert.Equal(t, `^10\.1\..*$`, SearchTerm{Value: "10.1.*"}.Pattern())
+	assert.Equal(t, `^.*\.prod$`, SearchTerm{Value: "*.prod"}.Pattern())
+}
+
+func TestSearchTermLiterals(t *testing.T) {
+	assert.Equal(t, []string{"10.1.2"}, SearchTerm{Value: "10.1.2"}.Literals())
+	assert.Equal(t, []string{"web", "prod"}, SearchTerm{Value: "*web*prod"}.Literals())
+}
+
+func TestMatchResource(t *testing.T) {
+	r := Resource{
+		ID:       1,
+		ModelUID: "host",
+		Data: mongox.MapStr{
+			"name":     "web01",
+			"ip":       "10.1.2.3",
+			"hostname": "web01.prod.example.com",
+			"password": "web01-secret",
+			"cpu":      8,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		terms          []SearchTerm
+		wantOK         bool
+		wantScore      float64
+		wantHighlights []SearchHighlight
+	}{
+		{
+			name:      "exact name match is boosted",
+			terms:     []SearchTerm{{Field: "name", Value: "web01"}},
+			wantOK:    true,
+			wantScore: 6,
+			wantHighlights: []SearchHighlight{
+				{Field: "name", Snippet: "<em>web01</em>"},
+			},
+		},
+		{
+			name:      "ip prefix",
+			terms:     []SearchTerm{{Field: "ip", Value: "10.1.*"}},
+			wantOK:    true,
+			wantScore: 2,
+			wantHighlights: []SearchHighlight{
+				{Field: "ip", Snippet: "<em>10.1.2.3</em>"},
+			},
+		},
+		{
+			name:      "partial match across fields",
+			terms:     []SearchTerm{{Value: "prod"}},
+			wantOK:    true,
+			wantScore: 1,
+			wantHighlights: []SearchHighlight{
+				{Field: "hostname", Snippet: "web01.<em>prod</em>.example.com"},
+			},
+		},
+		{
+			name:      "numeric field",
+			terms:     []SearchTerm{{Field: "cpu", Value: "8"}},
+			wantOK:    true,
+			wantScore: 3,
+			wantHighlights: []SearchHighlight{
+				{Field: "cpu", Snippet: "<em>8</em>"},
+			},
+		},
+		{
+			name:   "all terms must match",
+			terms:  []SearchTerm{{Value: "web01"}, {Value: "staging"}},
+			wantOK: false,
+		},
+		{
+			name:   "secure field never matches",
+			terms:  []SearchTerm{{Value: "secret"}},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := MatchResource(r, tt.terms, []string{"password"})
+			assert.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantScore, hit.Score)
+			assert.Equal(t, tt.wantHighlights, hit.Highlights)
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	value := "aaaaaaaaaaaaaaaaaaaaaaaaa<b>match</b>zzzzzzzzzzzzzzzzzzzzzzzzz"
+	loc := []int{28, 33}
+
+	assert.Equal(t, "…aaaaaaaaaaaaaaaaa&lt;b&gt;<em>match</em>&lt;/b&gt;zzzzzzzzzzzzzzzz…",
+		highlightSnippet(value, loc))
+}
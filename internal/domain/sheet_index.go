@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SheetIndexName 记录工作表与模型对应关系的隐藏工作表
+// NOTE: 工作表名称受 Excel 长度限制可能被截断，工作簿导入时以该工作表为准识别模型
+const SheetIndexName = "_sheets"
+
+// SheetRef 工作表名称与模型唯一标识的对应关系
+type SheetRef struct {
+	Sheet    string
+	ModelUID string
+}
+
+// writeSheetIndex 写入隐藏的工作表索引，每行依次为工作表名称、模型唯一标识
+func writeSheetIndex(file *excelize.File, refs []SheetRef) error {
+	if _, err := file.NewSheet(SheetIndexName); err != nil {
+		return fmt.Errorf("创建工作表索引失败: %w", err)
+	}
+	if err := file.SetSheetVisible(SheetIndexName, false); err != nil {
+		return err
+	}
+
+	for i, ref := range refs {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := file.SetSheetRow(SheetIndexName, cell, &[]interface{}{ref.Sheet, ref.ModelUID}); err != nil {
+			return fmt.Errorf("写入工作表索引失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadSheetIndex 读取工作表索引，返回工作表名称到模型唯一标识的映射，没有索引时返回 nil
+func ReadSheetIndex(file *excelize.File) (map[string]string, error) {
+	if idx, _ := file.GetSheetIndex(SheetIndexName); idx < 0 {
+		return nil, nil
+	}
+
+	rows, err := file.GetRows(SheetIndexName)
+	if err != nil {
+		return nil, fmt.Errorf("读取工作表索引失败: %w", err)
+	}
+	index := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if len(row) >= 2 && row[0] != "" && row[1] != "" {
+			index[row[0]] = row[1]
+		}
+	}
+	return index, nil
+}
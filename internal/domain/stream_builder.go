@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// StreamBuilder 流式 Excel 构建器
+// NOTE: 基于 excelize StreamWriter 按行写入，数据超过 16MB 时暂存到磁盘临时文件，适用于大数据量导出
+// 列宽、冻结窗格及表头在首次写入数据时确定，因此 WithValidation 需要在 AddRows 之前调用
+type StreamBuilder struct {
+	file       *excelize.File
+	sw         *excelize.StreamWriter
+	sheetName  string
+	headerRows [][]string // 3 行表头数据
+	styles     *StyleSet
+	nextRow    int // 下一行数据的行号
+	lookup     *lookupSheet
+	err        error
+}
+
+// NewStreamBuilder 创建流式 Excel 构建器，使用与 Builder 相同的 3 行表头及样式
+// row1: 字段约束, row2: 字段 UID, row3: 字段名称
+func NewStreamBuilder(sheetName string, row1, row2, row3 []string) *StreamBuilder {
+	file := excelize.NewFile()
+
+	// 重命名默认的 Sheet1 为自定义名称
+	defaultSheet := file.GetSheetName(0)
+	if defaultSheet != sheetName {
+		file.SetSheetName(defaultSheet, sheetName)
+	}
+
+	return &StreamBuilder{
+		file:       file,
+		sheetName:  sheetName,
+		headerRows: [][]string{row1, row2, row3},
+		styles:     createStyleSet(file),
+		nextRow:    4,
+		lookup:     &lookupSheet{file: file},
+	}
+}
+
+// NextSheet 结束当前工作表并创建新的工作表，后续的 WithValidation 及 AddRows 作用于新工作表
+// NOTE: excelize 同一时间只能有一个工作表处于流式写入，因此需要先刷新当前工作表
+func (b *StreamBuilder) NextSheet(sheetName string, row1, row2, row3 []string) error {
+	if err := b.start(nil); err != nil {
+		return err
+	}
+	if err := b.sw.Flush(); err != nil {
+		return fmt.Errorf("生成 Excel 工作表失败: %w", err)
+	}
+	if _, err := b.file.NewSheet(sheetName); err != nil {
+		return fmt.Errorf("创建 Excel 工作表失败: %w", err)
+	}
+
+	b.sw = nil
+	b.sheetName = sheetName
+	b.headerRows = [][]string{row1, row2, row3}
+	b.nextRow = 4
+	return nil
+}
+
+// WithValidation 添加数据验证(下拉列表)，endRow 为 0 时覆盖到工作表最后一行
+// NOTE: 选项过长时引用隐藏的下拉选项工作表
+func (b *StreamBuilder) WithValidation(colIdx int, options []string, startRow, endRow int) *StreamBuilder {
+	if len(options) == 0 || !b.beforeRows() {
+		return b
+	}
+
+	dv, err := b.lookup.dropListValidation(columnSqref(colIdx, startRow, b.endRow(endRow)), options)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.err = b.file.AddDataValidation(b.sheetName, dv)
+	return b
+}
+
+// WithCellType 按数据类型添加数字或日期验证，endRow 为 0 时覆盖到工作表最后一行
+func (b *StreamBuilder) WithCellType(colIdx int, typ CellType, startRow, endRow int) *StreamBuilder {
+	if !b.beforeRows() {
+		return b
+	}
+
+	dv, err := typedValidation(columnSqref(colIdx, startRow, b.endRow(endRow)), typ)
+	if err != nil || dv == nil {
+		b.err = err
+		return b
+	}
+	b.err = b.file.AddDataValidation(b.sheetName, dv)
+	return b
+}
+
+// beforeRows 数据验证需要在写入数据行之前设置
+func (b *StreamBuilder) beforeRows() bool {
+	if b.err != nil {
+		return false
+	}
+	if b.sw != nil {
+		b.err = fmt.Errorf("数据验证需要在写入数据行之前设置")
+		return false
+	}
+	return true
+}
+
+func (b *StreamBuilder) endRow(endRow int) int {
+	if endRow == 0 {
+		return excelize.TotalRows
+	}
+	return endRow
+}
+
+// AddRows 批量写入数据行，首次调用时根据表头及本批数据计算列宽并写入表头
+func (b *StreamBuilder) AddRows(rows [][]interface{}) error {
+	if err := b.start(rows); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		// 选择样式(斑马纹)
+		style := b.styles.OddRow
+		if (b.nextRow-4)%2 == 1 {
+			style = b.styles.EvenRow
+		}
+
+		cells := make([]interface{}, len(row))
+		for i, value := range row {
+			cells[i] = excelize.Cell{StyleID: style, Value: value}
+		}
+
+		cell, _ := excelize.CoordinatesToCellName(1, b.nextRow)
+		if err := b.sw.SetRow(cell, cells); err != nil {
+			return fmt.Errorf("写入 Excel 数据行失败: %w", err)
+		}
+		b.nextRow++
+	}
+	return nil
+}
+
+// WithSheetIndex 写入隐藏的工作表索引，需要在 WriteTo 之前调用
+func (b *StreamBuilder) WithSheetIndex(refs []SheetRef) *StreamBuilder {
+	if b.err == nil {
+		b.err = writeSheetIndex(b.file, refs)
+	}
+	return b
+}
+
+// WriteTo 结束写入并将 Excel 文件输出到 w
+func (b *StreamBuilder) WriteTo(w io.Writer) (int64, error) {
+	if err := b.start(nil); err != nil {
+		return 0, err
+	}
+	if err := b.sw.Flush(); err != nil {
+		return 0, fmt.Errorf("生成 Excel 文件失败: %w", err)
+	}
+
+	n, err := b.file.WriteTo(w)
+	if err != nil {
+		return n, fmt.Errorf("生成 Excel 文件失败: %w", err)
+	}
+	return n, nil
+}
+
+// Close 关闭文件并清理临时文件
+func (b *StreamBuilder) Close() error {
+	return b.file.Close()
+}
+
+// start 创建 StreamWriter 并写入列宽、冻结窗格及表头
+// NOTE: StreamWriter 要求列宽及窗格在写入任何行之前设置
+func (b *StreamBuilder) start(sample [][]interface{}) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.sw != nil {
+		return nil
+	}
+
+	sw, err := b.file.NewStreamWriter(b.sheetName)
+	if err != nil {
+		return fmt.Errorf("创建 Excel 流式写入失败: %w", err)
+	}
+	b.sw = sw
+
+	for colIdx := range b.headerRows[0] {
+		if err = sw.SetColWidth(colIdx+1, colIdx+1, b.columnWidth(colIdx, sample)); err != nil {
+			return err
+		}
+	}
+
+	// 3 行表头时冻结前 3 行
+	if err = sw.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      3,
+		TopLeftCell: "A4",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+
+	// 为每行选择不同的样式
+	styles := []int{b.styles.Header, b.styles.Header2, b.styles.Header3}
+	for rowIdx, rowData := range b.headerRows {
+		cells := make([]interface{}, len(rowData))
+		for i, value := range rowData {
+			cells[i] = excelize.Cell{StyleID: styles[rowIdx], Value: value}
+		}
+
+		cell, _ := excelize.CoordinatesToCellName(1, rowIdx+1)
+		if err = sw.SetRow(cell, cells, excelize.RowOpts{Height: 30}); err != nil {
+			return fmt.Errorf("写入 Excel 表头失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// columnWidth 根据表头及首批数据(最多 100 行)计算列宽，规则与 Builder 一致
+func (b *StreamBuilder) columnWidth(colIdx int, sample [][]interface{}) float64 {
+	maxWidth := 10.0 // 最小宽度
+	for _, row := range b.headerRows {
+		if colIdx < len(row) {
+			maxWidth = max(maxWidth, calculateStringWidth(row[colIdx]))
+		}
+	}
+
+	for _, row := range sample[:min(len(sample), 100)] {
+		if colIdx < len(row) {
+			maxWidth = max(maxWidth, calculateStringWidth(fmt.Sprintf("%v", row[colIdx])))
+		}
+	}
+
+	// 最大不超过 50，并添加一些边距
+	return min(maxWidth, 50) + 2
+}
@@ -9,6 +9,25 @@ type StyleSet struct {
 	Header3 int // 第三行表头样式 (名称)
 	OddRow  int // 奇数行样式
 	EvenRow int // 偶数行样式
+
+	RequiredHeader int // 必填字段名称表头样式
+	Number         int // 数字类型单元格样式
+	Date           int // 日期类型单元格样式
+	DateTime       int // 日期时间类型单元格样式
+}
+
+// CellStyle 数据类型对应的单元格样式，文本类型返回 0
+func (s *StyleSet) CellStyle(typ CellType) int {
+	switch typ {
+	case CellNumber:
+		return s.Number
+	case CellDate:
+		return s.Date
+	case CellDateTime:
+		return s.DateTime
+	default:
+		return 0
+	}
 }
 
 // createStyleSet 创建样式集
@@ -20,6 +39,11 @@ func createStyleSet(f *excelize.File) *StyleSet {
 		Header3: createHeaderRow3Style(f), // 名称
 		OddRow:  createOddRowStyle(f),
 		EvenRow: createEvenRowStyle(f),
+
+		RequiredHeader: createRequiredHeaderStyle(f),
+		Number:         createFormatCellStyle(f, "General"),
+		Date:           createFormatCellStyle(f, "yyyy-mm-dd"),
+		DateTime:       createFormatCellStyle(f, "yyyy-mm-dd hh:mm:ss"),
 	}
 }
 
@@ -163,3 +187,57 @@ func createEvenRowStyle(f *excelize.File) int {
 
 	return style
 }
+
+// createRequiredHeaderStyle 创建必填字段名称表头样式 (红色加粗字体)
+func createRequiredHeaderStyle(f *excelize.File) int {
+	style, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold:   true,
+			Color:  "F56C6C",
+			Size:   11,
+			Family: "Arial",
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"FEF0F0"}, // 淡红色背景
+			Pattern: 1,
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+			WrapText:   true,
+		},
+		Border: []excelize.Border{
+			{Type: "left", Color: "EBEEF5", Style: 1},
+			{Type: "right", Color: "EBEEF5", Style: 1},
+			{Type: "top", Color: "EBEEF5", Style: 1},
+			{Type: "bottom", Color: "EBEEF5", Style: 1},
+		},
+	})
+
+	return style
+}
+
+// createFormatCellStyle 创建带数字格式的数据单元格样式
+func createFormatCellStyle(f *excelize.File, numFmt string) int {
+	style, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Size:   10,
+			Color:  "606266",
+			Family: "Arial",
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+		Border: []excelize.Border{
+			{Type: "left", Color: "EBEEF5", Style: 1},
+			{Type: "right", Color: "EBEEF5", Style: 1},
+			{Type: "top", Color: "EBEEF5", Style: 1},
+			{Type: "bottom", Color: "EBEEF5", Style: 1},
+		},
+		CustomNumFmt: &numFmt,
+	})
+
+	return style
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// CellType 单元格数据类型，决定导入模板中数据验证的规则及单元格格式
+type CellType int
+
+const (
+	CellText CellType = iota
+	CellNumber
+	CellDate
+	CellDateTime
+)
+
+// LookupSheetName 存放下拉选项的隐藏工作表
+// NOTE: 不在工作表索引中，工作簿导入时会被跳过
+const LookupSheetName = "_lookup"
+
+// excelMaxDateSerial 9999-12-31 对应的 Excel 日期序列号
+const excelMaxDateSerial = 2958466
+
+// lookupSheet 隐藏的下拉选项工作表，每个下拉列表占用一列，首次使用时创建
+type lookupSheet struct {
+	file *excelize.File
+	cols int
+}
+
+// dropListValidation 创建下拉列表验证
+// NOTE: excelize 直接写入的选项合计不能超过 255 个字符，超出时将选项写入隐藏工作表并引用该区域
+func (l *lookupSheet) dropListValidation(sqref string, options []string) (*excelize.DataValidation, error) {
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = sqref
+	err := dv.SetDropList(options)
+	if !errors.Is(err, excelize.ErrDataValidationFormulaLength) {
+		return dv, err
+	}
+
+	if l.cols == 0 {
+		if _, err = l.file.NewSheet(LookupSheetName); err != nil {
+			return nil, fmt.Errorf("创建下拉选项工作表失败: %w", err)
+		}
+		if err = l.file.SetSheetVisible(LookupSheetName, false); err != nil {
+			return nil, err
+		}
+	}
+	l.cols++
+
+	col, _ := excelize.ColumnNumberToName(l.cols)
+	values := make([]interface{}, len(options))
+	for i, option := range options {
+		values[i] = option
+	}
+	if err = l.file.SetSheetCol(LookupSheetName, col+"1", &values); err != nil {
+		return nil, fmt.Errorf("写入下拉选项失败: %w", err)
+	}
+
+	dv.SetSqrefDropList(fmt.Sprintf("'%s'!$%s$1:$%s$%d", LookupSheetName, col, col, len(options)))
+	return dv, nil
+}
+
+// typedValidation 创建数字、日期类型的数据验证，文本类型不需要验证时返回 nil
+func typedValidation(sqref string, typ CellType) (*excelize.DataValidation, error) {
+	var (
+		dv  = excelize.NewDataValidation(true)
+		err error
+	)
+	dv.Sqref = sqref
+
+	switch typ {
+	case CellNumber:
+		err = dv.SetRange(-math.MaxFloat32, math.MaxFloat32, excelize.DataValidationTypeDecimal,
+			excelize.DataValidationOperatorBetween)
+		dv.SetError(excelize.DataValidationErrorStyleStop, "格式错误", "请输入数字")
+	case CellDate:
+		err = dv.SetRange(1, excelMaxDateSerial, excelize.DataValidationTypeDate,
+			excelize.DataValidationOperatorBetween)
+		dv.SetError(excelize.DataValidationErrorStyleStop, "格式错误", "请输入日期，例如 2006-01-02")
+	case CellDateTime:
+		// NOTE: 日期时间的序列号带有小数部分，按数值范围校验
+		err = dv.SetRange(1, excelMaxDateSerial, excelize.DataValidationTypeDecimal,
+			excelize.DataValidationOperatorBetween)
+		dv.SetError(excelize.DataValidationErrorStyleStop, "格式错误", "请输入日期时间，例如 2006-01-02 15:04:05")
+	default:
+		return nil, nil
+	}
+	return dv, err
+}
+
+// columnSqref 列在 startRow 至 endRow 之间的单元格区域
+func columnSqref(colIdx int, startRow, endRow int) string {
+	col, _ := excelize.ColumnNumberToName(colIdx + 1)
+	return fmt.Sprintf("%s%d:%s%d", col, startRow, col, endRow)
+}
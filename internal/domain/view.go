@@ -0,0 +1,77 @@
+package domain
+
+// ViewScope 视图可见范围
+type ViewScope string
+
+const (
+	// ViewScopePrivate 仅创建人可见
+	ViewScopePrivate ViewScope = "private"
+	// ViewScopeShared 租户内共享，所有人可见，仅创建人可修改
+	ViewScopeShared ViewScope = "shared"
+)
+
+func (s ViewScope) Valid() bool {
+	return s == ViewScopePrivate || s == ViewScopeShared
+}
+
+// ResourceSort 资产列表排序字段
+type ResourceSort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// ResourceQuery 资产列表查询条件，Sorts 为空时按创建时间倒序
+type ResourceQuery struct {
+	ModelUID     string
+	IDs          []int64
+	FilterGroups []FilterGroup
+	Sorts        []ResourceSort
+}
+
+// SavedView 保存的资产视图：筛选条件、展示字段与排序的命名组合
+type SavedView struct {
+	ID           int64
+	ModelUID     string
+	Name         string
+	OwnerID      int64
+	Scope        ViewScope
+	FilterGroups []FilterGroup
+	Fields       []string // 展示字段，为空时展示模型全部字段
+	Sorts        []ResourceSort
+	IsDefault    bool // 是否为当前用户在该模型下的默认视图，查询时填充
+	Ctime        int64
+	Utime        int64
+}
+
+// VisibleTo 共享视图所有人可见，私有视图仅创建人可见
+func (v SavedView) VisibleTo(userID int64) bool {
+	return v.Scope == ViewScopeShared || v.OwnerID == userID
+}
+
+// EditableBy 视图只允许创建人修改和删除
+func (v SavedView) EditableBy(userID int64) bool {
+	return v.OwnerID == userID
+}
+
+// Query 将视图转换为资产查询条件
+func (v SavedView) Query() ResourceQuery {
+	return ResourceQuery{
+		ModelUID:     v.ModelUID,
+		FilterGroups: v.FilterGroups,
+		Sorts:        v.Sorts,
+	}
+}
+
+// ReferencedFields 视图中引用的全部字段，用于校验字段是否属于模型
+func (v SavedView) ReferencedFields() []string {
+	fields := append([]string{}, v.Fields...)
+	for _, group := range v.FilterGroups {
+		for _, filter := range group.Filters {
+			fields = append(fields, filter.FieldUID)
+		}
+	}
+	for _, sort := range v.Sorts {
+		fields = append(fields, sort.Field)
+	}
+	return fields
+}
@@ -0,0 +1,7 @@
+package errs
+
+var (
+	PluginActionPermissionDenied = ErrorCode{Code: 505001, Msg: "无权执行该插件动作"}
+	PluginUpgradeBlocked         = ErrorCode{Code: 505002, Msg: "插件升级会删除仍在使用的字段"}
+	PluginUninstallBlocked       = ErrorCode{Code: 505003, Msg: "插件模型仍有资产数据"}
+)
@@ -0,0 +1,5 @@
+package errs
+
+var (
+	ViewPermissionDenied = ErrorCode{Code: 504001, Msg: "无权访问该视图"}
+)
@@ -0,0 +1,55 @@
+package dataio
+
+import (
+	"context"
+	"time"
+
+	dataioservice "github.com/Duke1616/ecmdb/internal/service/dataio"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// ExportJobTask 异步导出任务执行器，按固定间隔领取并执行待处理的导出任务
+// NOTE: 多实例部署时通过状态条件更新领取任务，同一任务只会被一个实例执行
+type ExportJobTask struct {
+	svc      dataioservice.IDataIOService
+	interval time.Duration
+	logger   *elog.Component
+}
+
+// NewExportJobTask 构造异步导出任务执行器
+func NewExportJobTask(svc dataioservice.IDataIOService, interval time.Duration) *ExportJobTask {
+	return &ExportJobTask{
+		svc:      svc,
+		interval: interval,
+		logger:   elog.DefaultLogger,
+	}
+}
+
+// Start 启动后台轮询协程
+func (t *ExportJobTask) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.run(ctx)
+			}
+		}
+	}()
+}
+
+func (t *ExportJobTask) run(ctx context.Context) {
+	count, err := t.svc.RunExportJobs(ctx)
+	if err != nil {
+		t.logger.Error("执行导出任务失败", elog.FieldErr(err), elog.Int("已执行数量", count))
+		return
+	}
+
+	if count > 0 {
+		t.logger.Info("执行导出任务成功", elog.Int("count", count))
+	}
+}
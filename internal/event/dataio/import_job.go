@@ -0,0 +1,55 @@
+package dataio
+
+import (
+	"context"
+	"time"
+
+	dataioservice "github.com/Duke1616/ecmdb/internal/service/dataio"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// ImportJobTask 异步导入任务执行器，按固定间隔领取并执行待处理的导入任务
+// NOTE: 多实例部署时通过状态条件更新领取任务，同一任务只会被一个实例执行
+type ImportJobTask struct {
+	svc      dataioservice.IDataIOService
+	interval time.Duration
+	logger   *elog.Component
+}
+
+// NewImportJobTask 构造异步导入任务执行器
+func NewImportJobTask(svc dataioservice.IDataIOService, interval time.Duration) *ImportJobTask {
+	return &ImportJobTask{
+		svc:      svc,
+		interval: interval,
+		logger:   elog.DefaultLogger,
+	}
+}
+
+// Start 启动后台轮询协程
+func (t *ImportJobTask) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.run(ctx)
+			}
+		}
+	}()
+}
+
+func (t *ImportJobTask) run(ctx context.Context) {
+	count, err := t.svc.RunImportJobs(ctx)
+	if err != nil {
+		t.logger.Error("执行导入任务失败", elog.FieldErr(err), elog.Int("已执行数量", count))
+		return
+	}
+
+	if count > 0 {
+		t.logger.Info("执行导入任务成功", elog.Int("count", count))
+	}
+}
@@ -0,0 +1,69 @@
+package dataio
+
+import (
+	"context"
+	"time"
+
+	dataioservice "github.com/Duke1616/ecmdb/internal/service/dataio"
+	"github.com/Duke1616/ecmdb/pkg/electionx"
+	"github.com/gotomicro/ego/core/elog"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// scheduledExportElection 定时导出调度器的选主前缀
+const scheduledExportElection = "/ecmdb/dataio/scheduled_export/leader"
+
+// ScheduledExportTask 定时导出调度器，通过 etcd 选主保证同一时间只有一个实例执行调度
+// NOTE: 租约失效时执行中的导出不会中断，重复调度由推进执行时间的条件更新拦截
+type ScheduledExportTask struct {
+	svc      dataioservice.IDataIOService
+	election *electionx.Election
+	interval time.Duration
+	logger   *elog.Component
+}
+
+// NewScheduledExportTask 构造定时导出调度器
+func NewScheduledExportTask(svc dataioservice.IDataIOService, client *clientv3.Client,
+	interval time.Duration) *ScheduledExportTask {
+	return &ScheduledExportTask{
+		svc:      svc,
+		election: electionx.NewElection(client, scheduledExportElection, "定时导出调度器"),
+		interval: interval,
+		logger:   elog.DefaultLogger,
+	}
+}
+
+// Start 启动后台选主协程，成为主节点后按固定间隔检查到期的定时导出
+func (t *ScheduledExportTask) Start(ctx context.Context) {
+	go t.election.Run(ctx, t.lead)
+}
+
+// lead 在任期内按固定间隔执行调度，ctx 结束或租约失效时返回
+func (t *ScheduledExportTask) lead(ctx context.Context, expired <-chan struct{}) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		t.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			t.logger.Warn("定时导出调度器租约失效，重新选主")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *ScheduledExportTask) run(ctx context.Context) {
+	count, err := t.svc.RunScheduledExports(ctx)
+	if err != nil {
+		t.logger.Error("执行定时导出失败", elog.FieldErr(err), elog.Int("已执行数量", count))
+		return
+	}
+
+	if count > 0 {
+		t.logger.Info("执行定时导出成功", elog.Int("count", count))
+	}
+}
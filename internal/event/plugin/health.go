@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"context"
+	"time"
+
+	pluginservice "github.com/Duke1616/ecmdb/internal/service/plugin"
+	"github.com/Duke1616/ecmdb/pkg/electionx"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/gotomicro/ego/core/elog"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// healthCheckElection 插件健康检查选主键前缀
+const healthCheckElection = "/ecmdb/plugin/health/leader"
+
+// HealthCheckTask 插件健康检查任务，按固定间隔探测外部服务插件的 health_path 并记录结果
+// NOTE: 连续失败次数基于上一次探测结果累加，多实例并发探测会相互覆盖，因此只由主节点探测
+type HealthCheckTask struct {
+	svc      pluginservice.Service
+	interval time.Duration
+	election *electionx.Election
+	logger   *elog.Component
+}
+
+// NewHealthCheckTask 构造插件健康检查任务
+func NewHealthCheckTask(svc pluginservice.Service, client *clientv3.Client, interval time.Duration) *HealthCheckTask {
+	return &HealthCheckTask{
+		svc:      svc,
+		interval: interval,
+		election: electionx.NewElection(client, healthCheckElection, "插件健康检查"),
+		logger:   elog.DefaultLogger,
+	}
+}
+
+// Start 启动后台轮询协程，成为主节点时立即探测一次
+func (t *HealthCheckTask) Start(ctx context.Context) {
+	// 插件统一注册在系统租户空间
+	ctx = ctxutil.WithTenantID(ctx, ctxutil.SystemTenantID)
+
+	go t.election.Run(ctx, t.lead)
+}
+
+func (t *HealthCheckTask) lead(ctx context.Context, expired <-chan struct{}) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			t.logger.Warn("插件健康检查租约失效，重新选主")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *HealthCheckTask) run(ctx context.Context) {
+	if _, err := t.svc.CheckPluginsHealth(ctx); err != nil {
+		t.logger.Error("插件健康检查失败", elog.FieldErr(err))
+	}
+}
@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"context"
+	"time"
+
+	pluginservice "github.com/Duke1616/ecmdb/internal/service/plugin"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// permissionSyncRetryInterval 登记失败（例如权限中心尚未就绪）后的重试间隔
+const permissionSyncRetryInterval = 10 * time.Second
+
+// PermissionSyncTask 启动时按已落库的插件定义重新登记动作权限
+// NOTE: 权限登记保存在各实例内存中并由实例维持租约，因此每个实例都需要执行，不参与选主
+type PermissionSyncTask struct {
+	svc    pluginservice.Service
+	logger *elog.Component
+}
+
+// NewPermissionSyncTask 构造插件权限登记任务
+func NewPermissionSyncTask(svc pluginservice.Service) *PermissionSyncTask {
+	return &PermissionSyncTask{
+		svc:    svc,
+		logger: elog.DefaultLogger,
+	}
+}
+
+// Start 启动时登记一次，失败时按固定间隔重试直至成功
+func (t *PermissionSyncTask) Start(ctx context.Context) {
+	go func() {
+		for {
+			count, err := t.svc.SyncPermissions(ctx)
+			if err == nil {
+				t.logger.Info("登记插件动作权限成功", elog.Int("count", count))
+				return
+			}
+			t.logger.Error("登记插件动作权限失败", elog.FieldErr(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(permissionSyncRetryInterval):
+			}
+		}
+	}()
+}
@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"context"
+	"time"
+
+	resourceservice "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// SearchIndexSyncTask 检索索引同步任务，按固定间隔将资产变更日志增量同步到检索索引
+// NOTE: 每个服务实例各自追踪变更日志，多实例部署时各自的本地索引都能收敛到一致状态
+type SearchIndexSyncTask struct {
+	svc      resourceservice.Service
+	interval time.Duration
+	// reconcileInterval 以资产存储为准校准索引的间隔，补齐变更日志写入失败遗漏的变更
+	reconcileInterval time.Duration
+	reconciledAt      time.Time
+	logger            *elog.Component
+}
+
+// NewSearchIndexSyncTask 构造检索索引同步任务
+func NewSearchIndexSyncTask(svc resourceservice.Service, interval, reconcileInterval time.Duration) *SearchIndexSyncTask {
+	return &SearchIndexSyncTask{
+		svc:               svc,
+		interval:          interval,
+		reconcileInterval: reconcileInterval,
+		logger:            elog.DefaultLogger,
+	}
+}
+
+// Start 启动后台同步协程，启动时立即同步一次，索引尚未建立时会先执行全量重建
+func (t *SearchIndexSyncTask) Start(ctx context.Context) {
+	t.reconciledAt = time.Now()
+	go func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			t.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (t *SearchIndexSyncTask) run(ctx context.Context) {
+	count, err := t.svc.SyncSearchIndex(ctx)
+	if err != nil {
+		t.logger.Error("同步检索索引失败", elog.FieldErr(err), elog.Int("已处理数量", count))
+		return
+	}
+
+	if count > 0 {
+		t.logger.Debug("同步检索索引成功", elog.Int("count", count))
+	}
+
+	if time.Since(t.reconciledAt) < t.reconcileInterval {
+		return
+	}
+	t.reconciledAt = time.Now()
+	if count, err = t.svc.ReconcileSearchIndex(ctx); err != nil {
+		t.logger.Error("校准检索索引失败", elog.FieldErr(err), elog.Int("已处理数量", count))
+		return
+	}
+	t.logger.Info("校准检索索引成功", elog.Int("count", count))
+}
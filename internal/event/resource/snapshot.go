@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"context"
+	"time"
+
+	resourceservice "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/Duke1616/ecmdb/pkg/electionx"
+	"github.com/gotomicro/ego/core/elog"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// snapshotElection 资产快照任务选主键前缀
+const snapshotElection = "/ecmdb/resource/snapshot/leader"
+
+// SnapshotTask 资产快照定时任务，为历史回溯提供回放基线，并清理超出保留期的历史数据
+type SnapshotTask struct {
+	svc       resourceservice.Service
+	interval  time.Duration
+	retention time.Duration
+	election  *electionx.Election
+	logger    *elog.Component
+}
+
+// NewSnapshotTask 构造资产快照定时任务，retention 为 0 时不清理历史数据
+func NewSnapshotTask(svc resourceservice.Service, client *clientv3.Client, interval,
+	retention time.Duration) *SnapshotTask {
+	return &SnapshotTask{
+		svc:       svc,
+		interval:  interval,
+		retention: retention,
+		election:  electionx.NewElection(client, snapshotElection, "资产快照任务"),
+		logger:    elog.DefaultLogger,
+	}
+}
+
+// Start 启动后台快照协程，只有主节点执行快照
+func (t *SnapshotTask) Start(ctx context.Context) {
+	go t.election.Run(ctx, t.lead)
+}
+
+func (t *SnapshotTask) lead(ctx context.Context, expired <-chan struct{}) {
+	// NOTE: 按最近一轮快照的时间续上周期，避免每次重启或切主都额外生成一轮快照
+	timer := time.NewTimer(t.firstDelay(ctx))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			t.logger.Warn("资产快照任务租约失效，重新选主")
+			return
+		case <-timer.C:
+			t.run(ctx)
+			timer.Reset(t.interval)
+		}
+	}
+}
+
+func (t *SnapshotTask) firstDelay(ctx context.Context) time.Duration {
+	last, err := t.svc.LastSnapshotTime(ctx)
+	if err != nil {
+		t.logger.Error("查询最近一轮资产快照失败", elog.FieldErr(err))
+		return 0
+	}
+	if last == 0 {
+		return 0
+	}
+
+	delay := t.interval - time.Since(time.UnixMilli(last))
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+func (t *SnapshotTask) run(ctx context.Context) {
+	count, err := t.svc.TakeSnapshot(ctx)
+	if err != nil {
+		t.logger.Error("生成资产快照失败", elog.FieldErr(err), elog.Int64("已生成数量", count))
+		return
+	}
+	t.logger.Info("生成资产快照成功", elog.Int64("count", count))
+
+	if t.retention <= 0 {
+		return
+	}
+	pruned, err := t.svc.PruneHistory(ctx, time.Now().Add(-t.retention).UnixMilli())
+	if err != nil {
+		t.logger.Error("清理资产历史数据失败", elog.FieldErr(err), elog.Int64("已清理数量", pruned))
+		return
+	}
+	t.logger.Info("清理资产历史数据成功", elog.Int64("count", pruned))
+}
@@ -3,4 +3,5 @@ package event
 const (
 	FieldSecureAttrChangeName = "field_secure_attr_change"
 	FIELD_DELETE_EVENT_NAME   = "field_delete_event"
+	PluginActionAuditName     = "plugin_action_audit"
 )
@@ -3,12 +3,19 @@ package plugin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 
 	pluginv1 "github.com/Duke1616/ecmdb/api/proto/gen/ecmdb/plugin/v1"
+	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/internal/service/plugin"
 	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
 	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -22,12 +29,39 @@ func NewServer(svc plugin.Service) *Server {
 
 // ResolveActionContext 暴露给插件端通过 gRPC 远程调用，解析具体的上下文
 func (s *Server) ResolveActionContext(ctx context.Context, req *pluginv1.ResolveActionContextRequest) (*pluginv1.ResolveActionContextResponse, error) {
+	pluginID, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 插件只能解析自身声明的动作，避免借助其他插件的绑定读取资源数据
+	if req.PluginId != pluginID {
+		return nil, status.Errorf(codes.PermissionDenied, "插件 %s 无权解析插件 %s 的动作", pluginID, req.PluginId)
+	}
+
+	var params map[string]any
+	if len(req.ParamsJson) > 0 {
+		if err = json.Unmarshal(req.ParamsJson, &params); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "params_json 解析失败: %v", err)
+		}
+	}
+
+	claims, err := s.actionClaims(ctx, pluginID, req)
+	if err != nil {
+		return nil, err
+	}
+
 	resolveReq := pluginx.ResolveRequest{
-		PluginID:   req.PluginId,
-		Action:     req.Action,
-		ResourceID: req.ResourceId,
+		PluginID:    req.PluginId,
+		Action:      req.Action,
+		ResourceID:  req.ResourceId,
+		ResourceIDs: claims.ResourceIDs,
+		ModelUID:    claims.ModelUID,
+		Params:      params,
 	}
 
+	// 以动作令牌中的用户身份解析并鉴权，插件凭证只证明调用方是哪个插件
+	ctx = ctxutil.WithUserID(ctxutil.WithTenantID(ctx, claims.TenantID), claims.UserID)
+	ctx = plugin.WithAuditSource(ctx, domain.PluginAuditSourceGRPC, peerIP(ctx))
 	actionCtx, err := s.svc.ResolveActionContext(ctx, resolveReq)
 	if err != nil {
 		return nil, err
@@ -46,6 +80,10 @@ func (s *Server) ResolveActionContext(ctx context.Context, req *pluginv1.Resolve
 
 // RegisterPlugin 插件端在启动后通过此 gRPC 接口将自己注册给主站，主站反向拉取自描述信息并自动配置导入
 func (s *Server) RegisterPlugin(ctx context.Context, req *pluginv1.RegisterPluginRequest) (*pluginv1.RegisterPluginResponse, error) {
+	pluginID, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
 	if req.Upstream == "" {
 		return nil, fmt.Errorf("upstream 地址不能为空")
 	}
@@ -54,6 +92,10 @@ func (s *Server) RegisterPlugin(ctx context.Context, req *pluginv1.RegisterPlugi
 	if err != nil {
 		return nil, fmt.Errorf("读取插件自描述 Definition 失败: %w", err)
 	}
+	// 凭证只对签发时的插件 UID 有效，upstream 返回的定义必须与之一致
+	if def.Plugin.UID != pluginID {
+		return nil, status.Errorf(codes.PermissionDenied, "插件凭证 %s 与自描述插件 %s 不一致", pluginID, def.Plugin.UID)
+	}
 
 	// 保证落库的 upstream 地址采用插件注册时传入的地址
 	spec, ok := def.Plugin.Runtime()
@@ -77,3 +119,51 @@ func (s *Server) RegisterPlugin(ctx context.Context, req *pluginv1.RegisterPlugi
 		Success: true,
 	}, nil
 }
+
+// authenticate 校验插件通过 gRPC 元数据携带的凭证，返回调用方插件 UID
+func (s *Server) authenticate(ctx context.Context) (string, error) {
+	pluginID, secret, ok := pluginx.CredentialFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "缺少插件凭证")
+	}
+	if err := s.svc.VerifyCredential(ctx, pluginID, secret); err != nil {
+		if errors.Is(err, plugin.ErrInvalidCredential) {
+			return "", status.Error(codes.Unauthenticated, err.Error())
+		}
+		return "", err
+	}
+	return pluginID, nil
+}
+
+// actionClaims 校验插件通过 gRPC 元数据转发的动作令牌，令牌必须由请求的动作签发且覆盖请求的资源
+func (s *Server) actionClaims(ctx context.Context, pluginID string,
+	req *pluginv1.ResolveActionContextRequest) (pluginx.ActionTokenClaims, error) {
+	token, ok := pluginx.ActionTokenFromContext(ctx)
+	if !ok {
+		return pluginx.ActionTokenClaims{}, status.Error(codes.Unauthenticated, "缺少插件动作令牌")
+	}
+	claims, err := s.svc.VerifyActionToken(pluginID, token)
+	if err != nil {
+		return pluginx.ActionTokenClaims{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if claims.Action != req.Action {
+		return pluginx.ActionTokenClaims{}, status.Errorf(codes.PermissionDenied, "动作令牌不适用于动作 %s", req.Action)
+	}
+	if req.ResourceId != 0 && !lo.Contains(claims.Resources(), req.ResourceId) {
+		return pluginx.ActionTokenClaims{}, status.Errorf(codes.PermissionDenied, "动作令牌不适用于资源 %d", req.ResourceId)
+	}
+	return claims, nil
+}
+
+// peerIP 读取调用方地址，用于审计日志记录来源 IP
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,63 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pluginv1 "github.com/Duke1616/ecmdb/api/proto/gen/ecmdb/plugin/v1"
+	"github.com/Duke1616/ecmdb/internal/service/plugin"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestResolveActionContextUsesTokenModelUID(t *testing.T) {
+	signer := pluginx.NewTokenSigner("secret", time.Minute)
+	svc := &stubService{tokens: signer}
+	server := NewServer(svc)
+
+	token, _, err := signer.Sign(pluginx.ActionTokenClaims{
+		PluginID: "builtin.ssh",
+		Action:   "sync",
+		ModelUID: "host",
+		UserID:   3,
+		TenantID: 7,
+	})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		pluginx.MetadataPluginID, "builtin.ssh",
+		pluginx.MetadataPluginSecret, "secret",
+		pluginx.MetadataActionToken, token,
+	))
+
+	if _, err = server.ResolveActionContext(ctx, &pluginv1.ResolveActionContextRequest{
+		PluginId: "builtin.ssh",
+		Action:   "sync",
+	}); err != nil {
+		t.Fatalf("ResolveActionContext() error = %v", err)
+	}
+	if svc.resolved.ModelUID != "host" || svc.resolved.Action != "sync" {
+		t.Fatalf("expected model uid taken from action token, got %#v", svc.resolved)
+	}
+}
+
+type stubService struct {
+	plugin.Service
+	tokens   *pluginx.TokenSigner
+	resolved pluginx.ResolveRequest
+}
+
+func (s *stubService) VerifyCredential(ctx context.Context, pluginID, secret string) error {
+	return nil
+}
+
+func (s *stubService) VerifyActionToken(pluginID, token string) (pluginx.ActionTokenClaims, error) {
+	return s.tokens.Verify(token)
+}
+
+func (s *stubService) ResolveActionContext(ctx context.Context, req pluginx.ResolveRequest) (pluginx.ActionContext, error) {
+	s.resolved = req
+	return pluginx.ActionContext{}, nil
+}
@@ -100,6 +100,22 @@ func (mr *MockResourceRepositoryMockRecorder) CreateResource(ctx, req any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResource", reflect.TypeOf((*MockResourceRepository)(nil).CreateResource), ctx, req)
 }
 
+// CreateSnapshots mocks base method.
+func (m *MockResourceRepository) CreateSnapshots(ctx context.Context, afterID, limit, snapshotTime int64) (int64, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshots", ctx, afterID, limit, snapshotTime)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateSnapshots indicates an expected call of CreateSnapshots.
+func (mr *MockResourceRepositoryMockRecorder) CreateSnapshots(ctx, afterID, limit, snapshotTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshots", reflect.TypeOf((*MockResourceRepository)(nil).CreateSnapshots), ctx, afterID, limit, snapshotTime)
+}
+
 // DeleteResource mocks base method.
 func (m *MockResourceRepository) DeleteResource(ctx context.Context, id int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +131,21 @@ func (mr *MockResourceRepositoryMockRecorder) DeleteResource(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResource", reflect.TypeOf((*MockResourceRepository)(nil).DeleteResource), ctx, id)
 }
 
+// EarliestSnapshotTime mocks base method.
+func (m *MockResourceRepository) EarliestSnapshotTime(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EarliestSnapshotTime", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EarliestSnapshotTime indicates an expected call of EarliestSnapshotTime.
+func (mr *MockResourceRepositoryMockRecorder) EarliestSnapshotTime(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EarliestSnapshotTime", reflect.TypeOf((*MockResourceRepository)(nil).EarliestSnapshotTime), ctx)
+}
+
 // FindResourceById mocks base method.
 func (m *MockResourceRepository) FindResourceById(ctx context.Context, fields []string, id int64) (domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +176,35 @@ func (mr *MockResourceRepositoryMockRecorder) FindSecureData(ctx, id, fieldUid a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSecureData", reflect.TypeOf((*MockResourceRepository)(nil).FindSecureData), ctx, id, fieldUid)
 }
 
+// IterateResourcesByQuery mocks base method.
+func (m *MockResourceRepository) IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, batchSize int, fn func([]domain.Resource) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateResourcesByQuery", ctx, fields, query, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateResourcesByQuery indicates an expected call of IterateResourcesByQuery.
+func (mr *MockResourceRepositoryMockRecorder) IterateResourcesByQuery(ctx, fields, query, batchSize, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateResourcesByQuery", reflect.TypeOf((*MockResourceRepository)(nil).IterateResourcesByQuery), ctx, fields, query, batchSize, fn)
+}
+
+// LatestSnapshotTime mocks base method.
+func (m *MockResourceRepository) LatestSnapshotTime(ctx context.Context, before int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestSnapshotTime", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestSnapshotTime indicates an expected call of LatestSnapshotTime.
+func (mr *MockResourceRepositoryMockRecorder) LatestSnapshotTime(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSnapshotTime", reflect.TypeOf((*MockResourceRepository)(nil).LatestSnapshotTime), ctx, before)
+}
+
 // ListBeforeUtime mocks base method.
 func (m *MockResourceRepository) ListBeforeUtime(ctx context.Context, utime int64, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +220,21 @@ func (mr *MockResourceRepositoryMockRecorder) ListBeforeUtime(ctx, utime, fields
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBeforeUtime", reflect.TypeOf((*MockResourceRepository)(nil).ListBeforeUtime), ctx, utime, fields, modelUid, offset, limit)
 }
 
+// ListChangesSince mocks base method.
+func (m *MockResourceRepository) ListChangesSince(ctx context.Context, since, until, limit int64) ([]domain.ResourceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangesSince", ctx, since, until, limit)
+	ret0, _ := ret[0].([]domain.ResourceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangesSince indicates an expected call of ListChangesSince.
+func (mr *MockResourceRepositoryMockRecorder) ListChangesSince(ctx, since, until, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangesSince", reflect.TypeOf((*MockResourceRepository)(nil).ListChangesSince), ctx, since, until, limit)
+}
+
 // ListExcludeAndFilterResourceByIds mocks base method.
 func (m *MockResourceRepository) ListExcludeAndFilterResourceByIds(ctx context.Context, fields []string, modelUid string, offset, limit int64, ids []int64, filter domain.Condition) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +250,52 @@ func (mr *MockResourceRepositoryMockRecorder) ListExcludeAndFilterResourceByIds(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExcludeAndFilterResourceByIds", reflect.TypeOf((*MockResourceRepository)(nil).ListExcludeAndFilterResourceByIds), ctx, fields, modelUid, offset, limit, ids, filter)
 }
 
+// ListExistingNames mocks base method.
+func (m *MockResourceRepository) ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExistingNames", ctx, modelUid, names)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExistingNames indicates an expected call of ListExistingNames.
+func (mr *MockResourceRepositoryMockRecorder) ListExistingNames(ctx, modelUid, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExistingNames", reflect.TypeOf((*MockResourceRepository)(nil).ListExistingNames), ctx, modelUid, names)
+}
+
+// ListFullResourcesByIds mocks base method.
+func (m *MockResourceRepository) ListFullResourcesByIds(ctx context.Context, ids []int64) ([]domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFullResourcesByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFullResourcesByIds indicates an expected call of ListFullResourcesByIds.
+func (mr *MockResourceRepositoryMockRecorder) ListFullResourcesByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFullResourcesByIds", reflect.TypeOf((*MockResourceRepository)(nil).ListFullResourcesByIds), ctx, ids)
+}
+
+// ListModelResourcesAsOf mocks base method.
+func (m *MockResourceRepository) ListModelResourcesAsOf(ctx context.Context, modelUid string, asOf, offset, limit int64) ([]domain.Resource, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModelResourcesAsOf", ctx, modelUid, asOf, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListModelResourcesAsOf indicates an expected call of ListModelResourcesAsOf.
+func (mr *MockResourceRepositoryMockRecorder) ListModelResourcesAsOf(ctx, modelUid, asOf, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModelResourcesAsOf", reflect.TypeOf((*MockResourceRepository)(nil).ListModelResourcesAsOf), ctx, modelUid, asOf, offset, limit)
+}
+
 // ListResource mocks base method.
 func (m *MockResourceRepository) ListResource(ctx context.Context, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -190,6 +311,21 @@ func (mr *MockResourceRepositoryMockRecorder) ListResource(ctx, fields, modelUid
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResource", reflect.TypeOf((*MockResourceRepository)(nil).ListResource), ctx, fields, modelUid, offset, limit)
 }
 
+// ListResourcesAsOf mocks base method.
+func (m *MockResourceRepository) ListResourcesAsOf(ctx context.Context, ids []int64, asOf int64) ([]domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourcesAsOf", ctx, ids, asOf)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourcesAsOf indicates an expected call of ListResourcesAsOf.
+func (mr *MockResourceRepositoryMockRecorder) ListResourcesAsOf(ctx, ids, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcesAsOf", reflect.TypeOf((*MockResourceRepository)(nil).ListResourcesAsOf), ctx, ids, asOf)
+}
+
 // ListResourcesByIds mocks base method.
 func (m *MockResourceRepository) ListResourcesByIds(ctx context.Context, fields []string, ids []int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +341,21 @@ func (mr *MockResourceRepositoryMockRecorder) ListResourcesByIds(ctx, fields, id
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcesByIds", reflect.TypeOf((*MockResourceRepository)(nil).ListResourcesByIds), ctx, fields, ids)
 }
 
+// ListResourcesByQuery mocks base method.
+func (m *MockResourceRepository) ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, offset, limit int64) ([]domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourcesByQuery", ctx, fields, query, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourcesByQuery indicates an expected call of ListResourcesByQuery.
+func (mr *MockResourceRepositoryMockRecorder) ListResourcesByQuery(ctx, fields, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcesByQuery", reflect.TypeOf((*MockResourceRepository)(nil).ListResourcesByQuery), ctx, fields, query, offset, limit)
+}
+
 // ListResourcesWithFilters mocks base method.
 func (m *MockResourceRepository) ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64, filterGroups []domain.FilterGroup) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -220,19 +371,34 @@ func (mr *MockResourceRepositoryMockRecorder) ListResourcesWithFilters(ctx, fiel
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcesWithFilters", reflect.TypeOf((*MockResourceRepository)(nil).ListResourcesWithFilters), ctx, fields, modelUid, ids, offset, limit, filterGroups)
 }
 
-// Search mocks base method.
-func (m *MockResourceRepository) Search(ctx context.Context, text string) ([]domain.SearchResource, error) {
+// ListSearchDocuments mocks base method.
+func (m *MockResourceRepository) ListSearchDocuments(ctx context.Context, afterID, limit int64) ([]domain.SearchDocument, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", ctx, text)
-	ret0, _ := ret[0].([]domain.SearchResource)
+	ret := m.ctrl.Call(m, "ListSearchDocuments", ctx, afterID, limit)
+	ret0, _ := ret[0].([]domain.SearchDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSearchDocuments indicates an expected call of ListSearchDocuments.
+func (mr *MockResourceRepositoryMockRecorder) ListSearchDocuments(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSearchDocuments", reflect.TypeOf((*MockResourceRepository)(nil).ListSearchDocuments), ctx, afterID, limit)
+}
+
+// PruneHistory mocks base method.
+func (m *MockResourceRepository) PruneHistory(ctx context.Context, before int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneHistory", ctx, before)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Search indicates an expected call of Search.
-func (mr *MockResourceRepositoryMockRecorder) Search(ctx, text any) *gomock.Call {
+// PruneHistory indicates an expected call of PruneHistory.
+func (mr *MockResourceRepositoryMockRecorder) PruneHistory(ctx, before any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockResourceRepository)(nil).Search), ctx, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneHistory", reflect.TypeOf((*MockResourceRepository)(nil).PruneHistory), ctx, before)
 }
 
 // SetCustomField mocks base method.
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Duke1616/ecmdb/internal/repository (interfaces: SearchIndex)
+//
+// Generated by this command:
+//
+//	mockgen -package=repositorymocks -destination=internal/mocks/repositorymocks/search_index.mock.go github.com/Duke1616/ecmdb/internal/repository SearchIndex
+//
+
+// Package repositorymocks is a generated GoMock package.
+package repositorymocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/Duke1616/ecmdb/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSearchIndex is a mock of SearchIndex interface.
+type MockSearchIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchIndexMockRecorder
+	isgomock struct{}
+}
+
+// MockSearchIndexMockRecorder is the mock recorder for MockSearchIndex.
+type MockSearchIndexMockRecorder struct {
+	mock *MockSearchIndex
+}
+
+// NewMockSearchIndex creates a new mock instance.
+func NewMockSearchIndex(ctrl *gomock.Controller) *MockSearchIndex {
+	mock := &MockSearchIndex{ctrl: ctrl}
+	mock.recorder = &MockSearchIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchIndex) EXPECT() *MockSearchIndexMockRecorder {
+	return m.recorder
+}
+
+// Checkpoint mocks base method.
+func (m *MockSearchIndex) Checkpoint(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockSearchIndexMockRecorder) Checkpoint(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockSearchIndex)(nil).Checkpoint), ctx)
+}
+
+// Commit mocks base method.
+func (m *MockSearchIndex) Commit(ctx context.Context, checkpoint int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", ctx, checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockSearchIndexMockRecorder) Commit(ctx, checkpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockSearchIndex)(nil).Commit), ctx, checkpoint)
+}
+
+// Delete mocks base method.
+func (m *MockSearchIndex) Delete(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSearchIndexMockRecorder) Delete(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSearchIndex)(nil).Delete), ctx, ids)
+}
+
+// NeedSync mocks base method.
+func (m *MockSearchIndex) NeedSync() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedSync")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedSync indicates an expected call of NeedSync.
+func (mr *MockSearchIndexMockRecorder) NeedSync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedSync", reflect.TypeOf((*MockSearchIndex)(nil).NeedSync))
+}
+
+// Put mocks base method.
+func (m *MockSearchIndex) Put(ctx context.Context, docs []domain.SearchDocument) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, docs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockSearchIndexMockRecorder) Put(ctx, docs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockSearchIndex)(nil).Put), ctx, docs)
+}
+
+// Reset mocks base method.
+func (m *MockSearchIndex) Reset(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockSearchIndexMockRecorder) Reset(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockSearchIndex)(nil).Reset), ctx)
+}
+
+// Search mocks base method.
+func (m *MockSearchIndex) Search(ctx context.Context, modelUid string, terms []domain.SearchTerm, limit int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, modelUid, terms, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchIndexMockRecorder) Search(ctx, modelUid, terms, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchIndex)(nil).Search), ctx, modelUid, terms, limit)
+}
+
+// UnsetFields mocks base method.
+func (m *MockSearchIndex) UnsetFields(ctx context.Context, tenantID int64, modelUid string, fields []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetFields", ctx, tenantID, modelUid, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetFields indicates an expected call of UnsetFields.
+func (mr *MockSearchIndexMockRecorder) UnsetFields(ctx, tenantID, modelUid, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetFields", reflect.TypeOf((*MockSearchIndex)(nil).UnsetFields), ctx, tenantID, modelUid, fields)
+}
@@ -351,6 +351,45 @@ func (c *MockEncryptedSvcFindResourceByIdCall) DoAndReturn(f func(context.Contex
 	return c
 }
 
+// FindResourceByIdAsOf mocks base method.
+func (m *MockEncryptedSvc) FindResourceByIdAsOf(ctx context.Context, fields []string, id, asOf int64) (domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindResourceByIdAsOf", ctx, fields, id, asOf)
+	ret0, _ := ret[0].(domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindResourceByIdAsOf indicates an expected call of FindResourceByIdAsOf.
+func (mr *MockEncryptedSvcMockRecorder) FindResourceByIdAsOf(ctx, fields, id, asOf any) *MockEncryptedSvcFindResourceByIdAsOfCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResourceByIdAsOf", reflect.TypeOf((*MockEncryptedSvc)(nil).FindResourceByIdAsOf), ctx, fields, id, asOf)
+	return &MockEncryptedSvcFindResourceByIdAsOfCall{Call: call}
+}
+
+// MockEncryptedSvcFindResourceByIdAsOfCall wrap *gomock.Call
+type MockEncryptedSvcFindResourceByIdAsOfCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcFindResourceByIdAsOfCall) Return(arg0 domain.Resource, arg1 error) *MockEncryptedSvcFindResourceByIdAsOfCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcFindResourceByIdAsOfCall) Do(f func(context.Context, []string, int64, int64) (domain.Resource, error)) *MockEncryptedSvcFindResourceByIdAsOfCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcFindResourceByIdAsOfCall) DoAndReturn(f func(context.Context, []string, int64, int64) (domain.Resource, error)) *MockEncryptedSvcFindResourceByIdAsOfCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // FindSecureData mocks base method.
 func (m *MockEncryptedSvc) FindSecureData(ctx context.Context, id int64, fieldUid string) (string, error) {
 	m.ctrl.T.Helper()
@@ -390,6 +429,83 @@ func (c *MockEncryptedSvcFindSecureDataCall) DoAndReturn(f func(context.Context,
 	return c
 }
 
+// IterateResourcesByQuery mocks base method.
+func (m *MockEncryptedSvc) IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, batchSize int, fn func([]domain.Resource) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateResourcesByQuery", ctx, fields, query, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateResourcesByQuery indicates an expected call of IterateResourcesByQuery.
+func (mr *MockEncryptedSvcMockRecorder) IterateResourcesByQuery(ctx, fields, query, batchSize, fn any) *MockEncryptedSvcIterateResourcesByQueryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateResourcesByQuery", reflect.TypeOf((*MockEncryptedSvc)(nil).IterateResourcesByQuery), ctx, fields, query, batchSize, fn)
+	return &MockEncryptedSvcIterateResourcesByQueryCall{Call: call}
+}
+
+// MockEncryptedSvcIterateResourcesByQueryCall wrap *gomock.Call
+type MockEncryptedSvcIterateResourcesByQueryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcIterateResourcesByQueryCall) Return(arg0 error) *MockEncryptedSvcIterateResourcesByQueryCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcIterateResourcesByQueryCall) Do(f func(context.Context, []string, domain.ResourceQuery, int, func([]domain.Resource) error) error) *MockEncryptedSvcIterateResourcesByQueryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcIterateResourcesByQueryCall) DoAndReturn(f func(context.Context, []string, domain.ResourceQuery, int, func([]domain.Resource) error) error) *MockEncryptedSvcIterateResourcesByQueryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// LastSnapshotTime mocks base method.
+func (m *MockEncryptedSvc) LastSnapshotTime(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSnapshotTime", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSnapshotTime indicates an expected call of LastSnapshotTime.
+func (mr *MockEncryptedSvcMockRecorder) LastSnapshotTime(ctx any) *MockEncryptedSvcLastSnapshotTimeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSnapshotTime", reflect.TypeOf((*MockEncryptedSvc)(nil).LastSnapshotTime), ctx)
+	return &MockEncryptedSvcLastSnapshotTimeCall{Call: call}
+}
+
+// MockEncryptedSvcLastSnapshotTimeCall wrap *gomock.Call
+type MockEncryptedSvcLastSnapshotTimeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcLastSnapshotTimeCall) Return(arg0 int64, arg1 error) *MockEncryptedSvcLastSnapshotTimeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcLastSnapshotTimeCall) Do(f func(context.Context) (int64, error)) *MockEncryptedSvcLastSnapshotTimeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcLastSnapshotTimeCall) DoAndReturn(f func(context.Context) (int64, error)) *MockEncryptedSvcLastSnapshotTimeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListAndDecryptBeforeUtime mocks base method.
 func (m *MockEncryptedSvc) ListAndDecryptBeforeUtime(ctx context.Context, utime int64, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -508,6 +624,45 @@ func (c *MockEncryptedSvcListExcludeAndFilterResourceByIdsCall) DoAndReturn(f fu
 	return c
 }
 
+// ListExistingNames mocks base method.
+func (m *MockEncryptedSvc) ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExistingNames", ctx, modelUid, names)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExistingNames indicates an expected call of ListExistingNames.
+func (mr *MockEncryptedSvcMockRecorder) ListExistingNames(ctx, modelUid, names any) *MockEncryptedSvcListExistingNamesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExistingNames", reflect.TypeOf((*MockEncryptedSvc)(nil).ListExistingNames), ctx, modelUid, names)
+	return &MockEncryptedSvcListExistingNamesCall{Call: call}
+}
+
+// MockEncryptedSvcListExistingNamesCall wrap *gomock.Call
+type MockEncryptedSvcListExistingNamesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcListExistingNamesCall) Return(arg0 []string, arg1 error) *MockEncryptedSvcListExistingNamesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcListExistingNamesCall) Do(f func(context.Context, string, []string) ([]string, error)) *MockEncryptedSvcListExistingNamesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcListExistingNamesCall) DoAndReturn(f func(context.Context, string, []string) ([]string, error)) *MockEncryptedSvcListExistingNamesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListResource mocks base method.
 func (m *MockEncryptedSvc) ListResource(ctx context.Context, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, int64, error) {
 	m.ctrl.T.Helper()
@@ -548,6 +703,46 @@ func (c *MockEncryptedSvcListResourceCall) DoAndReturn(f func(context.Context, [
 	return c
 }
 
+// ListResourceAsOf mocks base method.
+func (m *MockEncryptedSvc) ListResourceAsOf(ctx context.Context, fields []string, modelUid string, asOf, offset, limit int64) ([]domain.Resource, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceAsOf", ctx, fields, modelUid, asOf, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourceAsOf indicates an expected call of ListResourceAsOf.
+func (mr *MockEncryptedSvcMockRecorder) ListResourceAsOf(ctx, fields, modelUid, asOf, offset, limit any) *MockEncryptedSvcListResourceAsOfCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceAsOf", reflect.TypeOf((*MockEncryptedSvc)(nil).ListResourceAsOf), ctx, fields, modelUid, asOf, offset, limit)
+	return &MockEncryptedSvcListResourceAsOfCall{Call: call}
+}
+
+// MockEncryptedSvcListResourceAsOfCall wrap *gomock.Call
+type MockEncryptedSvcListResourceAsOfCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcListResourceAsOfCall) Return(arg0 []domain.Resource, arg1 int64, arg2 error) *MockEncryptedSvcListResourceAsOfCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcListResourceAsOfCall) Do(f func(context.Context, []string, string, int64, int64, int64) ([]domain.Resource, int64, error)) *MockEncryptedSvcListResourceAsOfCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcListResourceAsOfCall) DoAndReturn(f func(context.Context, []string, string, int64, int64, int64) ([]domain.Resource, int64, error)) *MockEncryptedSvcListResourceAsOfCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListResourceByIds mocks base method.
 func (m *MockEncryptedSvc) ListResourceByIds(ctx context.Context, fields []string, ids []int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -587,6 +782,85 @@ func (c *MockEncryptedSvcListResourceByIdsCall) DoAndReturn(f func(context.Conte
 	return c
 }
 
+// ListResourceByIdsAsOf mocks base method.
+func (m *MockEncryptedSvc) ListResourceByIdsAsOf(ctx context.Context, fields []string, ids []int64, asOf int64) ([]domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceByIdsAsOf", ctx, fields, ids, asOf)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceByIdsAsOf indicates an expected call of ListResourceByIdsAsOf.
+func (mr *MockEncryptedSvcMockRecorder) ListResourceByIdsAsOf(ctx, fields, ids, asOf any) *MockEncryptedSvcListResourceByIdsAsOfCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceByIdsAsOf", reflect.TypeOf((*MockEncryptedSvc)(nil).ListResourceByIdsAsOf), ctx, fields, ids, asOf)
+	return &MockEncryptedSvcListResourceByIdsAsOfCall{Call: call}
+}
+
+// MockEncryptedSvcListResourceByIdsAsOfCall wrap *gomock.Call
+type MockEncryptedSvcListResourceByIdsAsOfCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcListResourceByIdsAsOfCall) Return(arg0 []domain.Resource, arg1 error) *MockEncryptedSvcListResourceByIdsAsOfCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcListResourceByIdsAsOfCall) Do(f func(context.Context, []string, []int64, int64) ([]domain.Resource, error)) *MockEncryptedSvcListResourceByIdsAsOfCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcListResourceByIdsAsOfCall) DoAndReturn(f func(context.Context, []string, []int64, int64) ([]domain.Resource, error)) *MockEncryptedSvcListResourceByIdsAsOfCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListResourcesByQuery mocks base method.
+func (m *MockEncryptedSvc) ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, offset, limit int64) ([]domain.Resource, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourcesByQuery", ctx, fields, query, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourcesByQuery indicates an expected call of ListResourcesByQuery.
+func (mr *MockEncryptedSvcMockRecorder) ListResourcesByQuery(ctx, fields, query, offset, limit any) *MockEncryptedSvcListResourcesByQueryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcesByQuery", reflect.TypeOf((*MockEncryptedSvc)(nil).ListResourcesByQuery), ctx, fields, query, offset, limit)
+	return &MockEncryptedSvcListResourcesByQueryCall{Call: call}
+}
+
+// MockEncryptedSvcListResourcesByQueryCall wrap *gomock.Call
+type MockEncryptedSvcListResourcesByQueryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcListResourcesByQueryCall) Return(arg0 []domain.Resource, arg1 int64, arg2 error) *MockEncryptedSvcListResourcesByQueryCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcListResourcesByQueryCall) Do(f func(context.Context, []string, domain.ResourceQuery, int64, int64) ([]domain.Resource, int64, error)) *MockEncryptedSvcListResourcesByQueryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcListResourcesByQueryCall) DoAndReturn(f func(context.Context, []string, domain.ResourceQuery, int64, int64) ([]domain.Resource, int64, error)) *MockEncryptedSvcListResourcesByQueryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListResourcesWithFilters mocks base method.
 func (m *MockEncryptedSvc) ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64, filterGroups []domain.FilterGroup) ([]domain.Resource, int64, error) {
 	m.ctrl.T.Helper()
@@ -627,19 +901,136 @@ func (c *MockEncryptedSvcListResourcesWithFiltersCall) DoAndReturn(f func(contex
 	return c
 }
 
+// PruneHistory mocks base method.
+func (m *MockEncryptedSvc) PruneHistory(ctx context.Context, before int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneHistory", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneHistory indicates an expected call of PruneHistory.
+func (mr *MockEncryptedSvcMockRecorder) PruneHistory(ctx, before any) *MockEncryptedSvcPruneHistoryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneHistory", reflect.TypeOf((*MockEncryptedSvc)(nil).PruneHistory), ctx, before)
+	return &MockEncryptedSvcPruneHistoryCall{Call: call}
+}
+
+// MockEncryptedSvcPruneHistoryCall wrap *gomock.Call
+type MockEncryptedSvcPruneHistoryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcPruneHistoryCall) Return(arg0 int64, arg1 error) *MockEncryptedSvcPruneHistoryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcPruneHistoryCall) Do(f func(context.Context, int64) (int64, error)) *MockEncryptedSvcPruneHistoryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcPruneHistoryCall) DoAndReturn(f func(context.Context, int64) (int64, error)) *MockEncryptedSvcPruneHistoryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RebuildSearchIndex mocks base method.
+func (m *MockEncryptedSvc) RebuildSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildSearchIndex indicates an expected call of RebuildSearchIndex.
+func (mr *MockEncryptedSvcMockRecorder) RebuildSearchIndex(ctx any) *MockEncryptedSvcRebuildSearchIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildSearchIndex", reflect.TypeOf((*MockEncryptedSvc)(nil).RebuildSearchIndex), ctx)
+	return &MockEncryptedSvcRebuildSearchIndexCall{Call: call}
+}
+
+// MockEncryptedSvcRebuildSearchIndexCall wrap *gomock.Call
+type MockEncryptedSvcRebuildSearchIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcRebuildSearchIndexCall) Return(arg0 int, arg1 error) *MockEncryptedSvcRebuildSearchIndexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcRebuildSearchIndexCall) Do(f func(context.Context) (int, error)) *MockEncryptedSvcRebuildSearchIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcRebuildSearchIndexCall) DoAndReturn(f func(context.Context) (int, error)) *MockEncryptedSvcRebuildSearchIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ReconcileSearchIndex mocks base method.
+func (m *MockEncryptedSvc) ReconcileSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileSearchIndex indicates an expected call of ReconcileSearchIndex.
+func (mr *MockEncryptedSvcMockRecorder) ReconcileSearchIndex(ctx any) *MockEncryptedSvcReconcileSearchIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileSearchIndex", reflect.TypeOf((*MockEncryptedSvc)(nil).ReconcileSearchIndex), ctx)
+	return &MockEncryptedSvcReconcileSearchIndexCall{Call: call}
+}
+
+// MockEncryptedSvcReconcileSearchIndexCall wrap *gomock.Call
+type MockEncryptedSvcReconcileSearchIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcReconcileSearchIndexCall) Return(arg0 int, arg1 error) *MockEncryptedSvcReconcileSearchIndexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcReconcileSearchIndexCall) Do(f func(context.Context) (int, error)) *MockEncryptedSvcReconcileSearchIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcReconcileSearchIndexCall) DoAndReturn(f func(context.Context) (int, error)) *MockEncryptedSvcReconcileSearchIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Search mocks base method.
-func (m *MockEncryptedSvc) Search(ctx context.Context, text string) ([]domain.SearchResource, error) {
+func (m *MockEncryptedSvc) Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", ctx, text)
-	ret0, _ := ret[0].([]domain.SearchResource)
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].([]domain.SearchGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockEncryptedSvcMockRecorder) Search(ctx, text any) *MockEncryptedSvcSearchCall {
+func (mr *MockEncryptedSvcMockRecorder) Search(ctx, query any) *MockEncryptedSvcSearchCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockEncryptedSvc)(nil).Search), ctx, text)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockEncryptedSvc)(nil).Search), ctx, query)
 	return &MockEncryptedSvcSearchCall{Call: call}
 }
 
@@ -649,19 +1040,19 @@ type MockEncryptedSvcSearchCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockEncryptedSvcSearchCall) Return(arg0 []domain.SearchResource, arg1 error) *MockEncryptedSvcSearchCall {
+func (c *MockEncryptedSvcSearchCall) Return(arg0 []domain.SearchGroup, arg1 error) *MockEncryptedSvcSearchCall {
 	c.Call = c.Call.Return(arg0, arg1)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockEncryptedSvcSearchCall) Do(f func(context.Context, string) ([]domain.SearchResource, error)) *MockEncryptedSvcSearchCall {
+func (c *MockEncryptedSvcSearchCall) Do(f func(context.Context, domain.SearchQuery) ([]domain.SearchGroup, error)) *MockEncryptedSvcSearchCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockEncryptedSvcSearchCall) DoAndReturn(f func(context.Context, string) ([]domain.SearchResource, error)) *MockEncryptedSvcSearchCall {
+func (c *MockEncryptedSvcSearchCall) DoAndReturn(f func(context.Context, domain.SearchQuery) ([]domain.SearchGroup, error)) *MockEncryptedSvcSearchCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -705,6 +1096,84 @@ func (c *MockEncryptedSvcSetCustomFieldCall) DoAndReturn(f func(context.Context,
 	return c
 }
 
+// SyncSearchIndex mocks base method.
+func (m *MockEncryptedSvc) SyncSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncSearchIndex indicates an expected call of SyncSearchIndex.
+func (mr *MockEncryptedSvcMockRecorder) SyncSearchIndex(ctx any) *MockEncryptedSvcSyncSearchIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSearchIndex", reflect.TypeOf((*MockEncryptedSvc)(nil).SyncSearchIndex), ctx)
+	return &MockEncryptedSvcSyncSearchIndexCall{Call: call}
+}
+
+// MockEncryptedSvcSyncSearchIndexCall wrap *gomock.Call
+type MockEncryptedSvcSyncSearchIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcSyncSearchIndexCall) Return(arg0 int, arg1 error) *MockEncryptedSvcSyncSearchIndexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcSyncSearchIndexCall) Do(f func(context.Context) (int, error)) *MockEncryptedSvcSyncSearchIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcSyncSearchIndexCall) DoAndReturn(f func(context.Context) (int, error)) *MockEncryptedSvcSyncSearchIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// TakeSnapshot mocks base method.
+func (m *MockEncryptedSvc) TakeSnapshot(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeSnapshot", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeSnapshot indicates an expected call of TakeSnapshot.
+func (mr *MockEncryptedSvcMockRecorder) TakeSnapshot(ctx any) *MockEncryptedSvcTakeSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshot", reflect.TypeOf((*MockEncryptedSvc)(nil).TakeSnapshot), ctx)
+	return &MockEncryptedSvcTakeSnapshotCall{Call: call}
+}
+
+// MockEncryptedSvcTakeSnapshotCall wrap *gomock.Call
+type MockEncryptedSvcTakeSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockEncryptedSvcTakeSnapshotCall) Return(arg0 int64, arg1 error) *MockEncryptedSvcTakeSnapshotCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockEncryptedSvcTakeSnapshotCall) Do(f func(context.Context) (int64, error)) *MockEncryptedSvcTakeSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockEncryptedSvcTakeSnapshotCall) DoAndReturn(f func(context.Context) (int64, error)) *MockEncryptedSvcTakeSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // UnsetCustomField mocks base method.
 func (m *MockEncryptedSvc) UnsetCustomField(ctx context.Context, modelUid, fieldUid string) (int64, error) {
 	m.ctrl.T.Helper()
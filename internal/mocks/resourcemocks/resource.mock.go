@@ -351,6 +351,45 @@ func (c *MockServiceFindResourceByIdCall) DoAndReturn(f func(context.Context, []
 	return c
 }
 
+// FindResourceByIdAsOf mocks base method.
+func (m *MockService) FindResourceByIdAsOf(ctx context.Context, fields []string, id, asOf int64) (domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindResourceByIdAsOf", ctx, fields, id, asOf)
+	ret0, _ := ret[0].(domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindResourceByIdAsOf indicates an expected call of FindResourceByIdAsOf.
+func (mr *MockServiceMockRecorder) FindResourceByIdAsOf(ctx, fields, id, asOf any) *MockServiceFindResourceByIdAsOfCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResourceByIdAsOf", reflect.TypeOf((*MockService)(nil).FindResourceByIdAsOf), ctx, fields, id, asOf)
+	return &MockServiceFindResourceByIdAsOfCall{Call: call}
+}
+
+// MockServiceFindResourceByIdAsOfCall wrap *gomock.Call
+type MockServiceFindResourceByIdAsOfCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceFindResourceByIdAsOfCall) Return(arg0 domain.Resource, arg1 error) *MockServiceFindResourceByIdAsOfCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceFindResourceByIdAsOfCall) Do(f func(context.Context, []string, int64, int64) (domain.Resource, error)) *MockServiceFindResourceByIdAsOfCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceFindResourceByIdAsOfCall) DoAndReturn(f func(context.Context, []string, int64, int64) (domain.Resource, error)) *MockServiceFindResourceByIdAsOfCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // FindSecureData mocks base method.
 func (m *MockService) FindSecureData(ctx context.Context, id int64, fieldUid string) (string, error) {
 	m.ctrl.T.Helper()
@@ -390,6 +429,122 @@ func (c *MockServiceFindSecureDataCall) DoAndReturn(f func(context.Context, int6
 	return c
 }
 
+// IterateResourcesByQuery mocks base method.
+func (m *MockService) IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, batchSize int, fn func([]domain.Resource) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateResourcesByQuery", ctx, fields, query, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateResourcesByQuery indicates an expected call of IterateResourcesByQuery.
+func (mr *MockServiceMockRecorder) IterateResourcesByQuery(ctx, fields, query, batchSize, fn any) *MockServiceIterateResourcesByQueryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateResourcesByQuery", reflect.TypeOf((*MockService)(nil).IterateResourcesByQuery), ctx, fields, query, batchSize, fn)
+	return &MockServiceIterateResourcesByQueryCall{Call: call}
+}
+
+// MockServiceIterateResourcesByQueryCall wrap *gomock.Call
+type MockServiceIterateResourcesByQueryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceIterateResourcesByQueryCall) Return(arg0 error) *MockServiceIterateResourcesByQueryCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceIterateResourcesByQueryCall) Do(f func(context.Context, []string, domain.ResourceQuery, int, func([]domain.Resource) error) error) *MockServiceIterateResourcesByQueryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceIterateResourcesByQueryCall) DoAndReturn(f func(context.Context, []string, domain.ResourceQuery, int, func([]domain.Resource) error) error) *MockServiceIterateResourcesByQueryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// LastSnapshotTime mocks base method.
+func (m *MockService) LastSnapshotTime(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSnapshotTime", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSnapshotTime indicates an expected call of LastSnapshotTime.
+func (mr *MockServiceMockRecorder) LastSnapshotTime(ctx any) *MockServiceLastSnapshotTimeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSnapshotTime", reflect.TypeOf((*MockService)(nil).LastSnapshotTime), ctx)
+	return &MockServiceLastSnapshotTimeCall{Call: call}
+}
+
+// MockServiceLastSnapshotTimeCall wrap *gomock.Call
+type MockServiceLastSnapshotTimeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceLastSnapshotTimeCall) Return(arg0 int64, arg1 error) *MockServiceLastSnapshotTimeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceLastSnapshotTimeCall) Do(f func(context.Context) (int64, error)) *MockServiceLastSnapshotTimeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceLastSnapshotTimeCall) DoAndReturn(f func(context.Context) (int64, error)) *MockServiceLastSnapshotTimeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListAndDecryptBeforeUtime mocks base method.
+func (m *MockService) ListAndDecryptBeforeUtime(ctx context.Context, utime int64, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAndDecryptBeforeUtime", ctx, utime, fields, modelUid, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAndDecryptBeforeUtime indicates an expected call of ListAndDecryptBeforeUtime.
+func (mr *MockServiceMockRecorder) ListAndDecryptBeforeUtime(ctx, utime, fields, modelUid, offset, limit any) *MockServiceListAndDecryptBeforeUtimeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAndDecryptBeforeUtime", reflect.TypeOf((*MockService)(nil).ListAndDecryptBeforeUtime), ctx, utime, fields, modelUid, offset, limit)
+	return &MockServiceListAndDecryptBeforeUtimeCall{Call: call}
+}
+
+// MockServiceListAndDecryptBeforeUtimeCall wrap *gomock.Call
+type MockServiceListAndDecryptBeforeUtimeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListAndDecryptBeforeUtimeCall) Return(arg0 []domain.Resource, arg1 error) *MockServiceListAndDecryptBeforeUtimeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListAndDecryptBeforeUtimeCall) Do(f func(context.Context, int64, []string, string, int64, int64) ([]domain.Resource, error)) *MockServiceListAndDecryptBeforeUtimeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListAndDecryptBeforeUtimeCall) DoAndReturn(f func(context.Context, int64, []string, string, int64, int64) ([]domain.Resource, error)) *MockServiceListAndDecryptBeforeUtimeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListBeforeUtime mocks base method.
 func (m *MockService) ListBeforeUtime(ctx context.Context, utime int64, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -469,6 +624,45 @@ func (c *MockServiceListExcludeAndFilterResourceByIdsCall) DoAndReturn(f func(co
 	return c
 }
 
+// ListExistingNames mocks base method.
+func (m *MockService) ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExistingNames", ctx, modelUid, names)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExistingNames indicates an expected call of ListExistingNames.
+func (mr *MockServiceMockRecorder) ListExistingNames(ctx, modelUid, names any) *MockServiceListExistingNamesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExistingNames", reflect.TypeOf((*MockService)(nil).ListExistingNames), ctx, modelUid, names)
+	return &MockServiceListExistingNamesCall{Call: call}
+}
+
+// MockServiceListExistingNamesCall wrap *gomock.Call
+type MockServiceListExistingNamesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListExistingNamesCall) Return(arg0 []string, arg1 error) *MockServiceListExistingNamesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListExistingNamesCall) Do(f func(context.Context, string, []string) ([]string, error)) *MockServiceListExistingNamesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListExistingNamesCall) DoAndReturn(f func(context.Context, string, []string) ([]string, error)) *MockServiceListExistingNamesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListResource mocks base method.
 func (m *MockService) ListResource(ctx context.Context, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, int64, error) {
 	m.ctrl.T.Helper()
@@ -509,6 +703,46 @@ func (c *MockServiceListResourceCall) DoAndReturn(f func(context.Context, []stri
 	return c
 }
 
+// ListResourceAsOf mocks base method.
+func (m *MockService) ListResourceAsOf(ctx context.Context, fields []string, modelUid string, asOf, offset, limit int64) ([]domain.Resource, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceAsOf", ctx, fields, modelUid, asOf, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourceAsOf indicates an expected call of ListResourceAsOf.
+func (mr *MockServiceMockRecorder) ListResourceAsOf(ctx, fields, modelUid, asOf, offset, limit any) *MockServiceListResourceAsOfCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceAsOf", reflect.TypeOf((*MockService)(nil).ListResourceAsOf), ctx, fields, modelUid, asOf, offset, limit)
+	return &MockServiceListResourceAsOfCall{Call: call}
+}
+
+// MockServiceListResourceAsOfCall wrap *gomock.Call
+type MockServiceListResourceAsOfCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListResourceAsOfCall) Return(arg0 []domain.Resource, arg1 int64, arg2 error) *MockServiceListResourceAsOfCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListResourceAsOfCall) Do(f func(context.Context, []string, string, int64, int64, int64) ([]domain.Resource, int64, error)) *MockServiceListResourceAsOfCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListResourceAsOfCall) DoAndReturn(f func(context.Context, []string, string, int64, int64, int64) ([]domain.Resource, int64, error)) *MockServiceListResourceAsOfCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListResourceByIds mocks base method.
 func (m *MockService) ListResourceByIds(ctx context.Context, fields []string, ids []int64) ([]domain.Resource, error) {
 	m.ctrl.T.Helper()
@@ -548,6 +782,85 @@ func (c *MockServiceListResourceByIdsCall) DoAndReturn(f func(context.Context, [
 	return c
 }
 
+// ListResourceByIdsAsOf mocks base method.
+func (m *MockService) ListResourceByIdsAsOf(ctx context.Context, fields []string, ids []int64, asOf int64) ([]domain.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceByIdsAsOf", ctx, fields, ids, asOf)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceByIdsAsOf indicates an expected call of ListResourceByIdsAsOf.
+func (mr *MockServiceMockRecorder) ListResourceByIdsAsOf(ctx, fields, ids, asOf any) *MockServiceListResourceByIdsAsOfCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceByIdsAsOf", reflect.TypeOf((*MockService)(nil).ListResourceByIdsAsOf), ctx, fields, ids, asOf)
+	return &MockServiceListResourceByIdsAsOfCall{Call: call}
+}
+
+// MockServiceListResourceByIdsAsOfCall wrap *gomock.Call
+type MockServiceListResourceByIdsAsOfCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListResourceByIdsAsOfCall) Return(arg0 []domain.Resource, arg1 error) *MockServiceListResourceByIdsAsOfCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListResourceByIdsAsOfCall) Do(f func(context.Context, []string, []int64, int64) ([]domain.Resource, error)) *MockServiceListResourceByIdsAsOfCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListResourceByIdsAsOfCall) DoAndReturn(f func(context.Context, []string, []int64, int64) ([]domain.Resource, error)) *MockServiceListResourceByIdsAsOfCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListResourcesByQuery mocks base method.
+func (m *MockService) ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, offset, limit int64) ([]domain.Resource, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourcesByQuery", ctx, fields, query, offset, limit)
+	ret0, _ := ret[0].([]domain.Resource)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourcesByQuery indicates an expected call of ListResourcesByQuery.
+func (mr *MockServiceMockRecorder) ListResourcesByQuery(ctx, fields, query, offset, limit any) *MockServiceListResourcesByQueryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcesByQuery", reflect.TypeOf((*MockService)(nil).ListResourcesByQuery), ctx, fields, query, offset, limit)
+	return &MockServiceListResourcesByQueryCall{Call: call}
+}
+
+// MockServiceListResourcesByQueryCall wrap *gomock.Call
+type MockServiceListResourcesByQueryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListResourcesByQueryCall) Return(arg0 []domain.Resource, arg1 int64, arg2 error) *MockServiceListResourcesByQueryCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListResourcesByQueryCall) Do(f func(context.Context, []string, domain.ResourceQuery, int64, int64) ([]domain.Resource, int64, error)) *MockServiceListResourcesByQueryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListResourcesByQueryCall) DoAndReturn(f func(context.Context, []string, domain.ResourceQuery, int64, int64) ([]domain.Resource, int64, error)) *MockServiceListResourcesByQueryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListResourcesWithFilters mocks base method.
 func (m *MockService) ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64, filterGroups []domain.FilterGroup) ([]domain.Resource, int64, error) {
 	m.ctrl.T.Helper()
@@ -588,19 +901,136 @@ func (c *MockServiceListResourcesWithFiltersCall) DoAndReturn(f func(context.Con
 	return c
 }
 
+// PruneHistory mocks base method.
+func (m *MockService) PruneHistory(ctx context.Context, before int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneHistory", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneHistory indicates an expected call of PruneHistory.
+func (mr *MockServiceMockRecorder) PruneHistory(ctx, before any) *MockServicePruneHistoryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneHistory", reflect.TypeOf((*MockService)(nil).PruneHistory), ctx, before)
+	return &MockServicePruneHistoryCall{Call: call}
+}
+
+// MockServicePruneHistoryCall wrap *gomock.Call
+type MockServicePruneHistoryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServicePruneHistoryCall) Return(arg0 int64, arg1 error) *MockServicePruneHistoryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServicePruneHistoryCall) Do(f func(context.Context, int64) (int64, error)) *MockServicePruneHistoryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServicePruneHistoryCall) DoAndReturn(f func(context.Context, int64) (int64, error)) *MockServicePruneHistoryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RebuildSearchIndex mocks base method.
+func (m *MockService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildSearchIndex indicates an expected call of RebuildSearchIndex.
+func (mr *MockServiceMockRecorder) RebuildSearchIndex(ctx any) *MockServiceRebuildSearchIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildSearchIndex", reflect.TypeOf((*MockService)(nil).RebuildSearchIndex), ctx)
+	return &MockServiceRebuildSearchIndexCall{Call: call}
+}
+
+// MockServiceRebuildSearchIndexCall wrap *gomock.Call
+type MockServiceRebuildSearchIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceRebuildSearchIndexCall) Return(arg0 int, arg1 error) *MockServiceRebuildSearchIndexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceRebuildSearchIndexCall) Do(f func(context.Context) (int, error)) *MockServiceRebuildSearchIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceRebuildSearchIndexCall) DoAndReturn(f func(context.Context) (int, error)) *MockServiceRebuildSearchIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ReconcileSearchIndex mocks base method.
+func (m *MockService) ReconcileSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileSearchIndex indicates an expected call of ReconcileSearchIndex.
+func (mr *MockServiceMockRecorder) ReconcileSearchIndex(ctx any) *MockServiceReconcileSearchIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileSearchIndex", reflect.TypeOf((*MockService)(nil).ReconcileSearchIndex), ctx)
+	return &MockServiceReconcileSearchIndexCall{Call: call}
+}
+
+// MockServiceReconcileSearchIndexCall wrap *gomock.Call
+type MockServiceReconcileSearchIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceReconcileSearchIndexCall) Return(arg0 int, arg1 error) *MockServiceReconcileSearchIndexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceReconcileSearchIndexCall) Do(f func(context.Context) (int, error)) *MockServiceReconcileSearchIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceReconcileSearchIndexCall) DoAndReturn(f func(context.Context) (int, error)) *MockServiceReconcileSearchIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Search mocks base method.
-func (m *MockService) Search(ctx context.Context, text string) ([]domain.SearchResource, error) {
+func (m *MockService) Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", ctx, text)
-	ret0, _ := ret[0].([]domain.SearchResource)
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].([]domain.SearchGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockServiceMockRecorder) Search(ctx, text any) *MockServiceSearchCall {
+func (mr *MockServiceMockRecorder) Search(ctx, query any) *MockServiceSearchCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockService)(nil).Search), ctx, text)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockService)(nil).Search), ctx, query)
 	return &MockServiceSearchCall{Call: call}
 }
 
@@ -610,19 +1040,19 @@ type MockServiceSearchCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockServiceSearchCall) Return(arg0 []domain.SearchResource, arg1 error) *MockServiceSearchCall {
+func (c *MockServiceSearchCall) Return(arg0 []domain.SearchGroup, arg1 error) *MockServiceSearchCall {
 	c.Call = c.Call.Return(arg0, arg1)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockServiceSearchCall) Do(f func(context.Context, string) ([]domain.SearchResource, error)) *MockServiceSearchCall {
+func (c *MockServiceSearchCall) Do(f func(context.Context, domain.SearchQuery) ([]domain.SearchGroup, error)) *MockServiceSearchCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockServiceSearchCall) DoAndReturn(f func(context.Context, string) ([]domain.SearchResource, error)) *MockServiceSearchCall {
+func (c *MockServiceSearchCall) DoAndReturn(f func(context.Context, domain.SearchQuery) ([]domain.SearchGroup, error)) *MockServiceSearchCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -666,6 +1096,84 @@ func (c *MockServiceSetCustomFieldCall) DoAndReturn(f func(context.Context, int6
 	return c
 }
 
+// SyncSearchIndex mocks base method.
+func (m *MockService) SyncSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncSearchIndex indicates an expected call of SyncSearchIndex.
+func (mr *MockServiceMockRecorder) SyncSearchIndex(ctx any) *MockServiceSyncSearchIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSearchIndex", reflect.TypeOf((*MockService)(nil).SyncSearchIndex), ctx)
+	return &MockServiceSyncSearchIndexCall{Call: call}
+}
+
+// MockServiceSyncSearchIndexCall wrap *gomock.Call
+type MockServiceSyncSearchIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceSyncSearchIndexCall) Return(arg0 int, arg1 error) *MockServiceSyncSearchIndexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceSyncSearchIndexCall) Do(f func(context.Context) (int, error)) *MockServiceSyncSearchIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceSyncSearchIndexCall) DoAndReturn(f func(context.Context) (int, error)) *MockServiceSyncSearchIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// TakeSnapshot mocks base method.
+func (m *MockService) TakeSnapshot(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeSnapshot", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeSnapshot indicates an expected call of TakeSnapshot.
+func (mr *MockServiceMockRecorder) TakeSnapshot(ctx any) *MockServiceTakeSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshot", reflect.TypeOf((*MockService)(nil).TakeSnapshot), ctx)
+	return &MockServiceTakeSnapshotCall{Call: call}
+}
+
+// MockServiceTakeSnapshotCall wrap *gomock.Call
+type MockServiceTakeSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceTakeSnapshotCall) Return(arg0 int64, arg1 error) *MockServiceTakeSnapshotCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceTakeSnapshotCall) Do(f func(context.Context) (int64, error)) *MockServiceTakeSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceTakeSnapshotCall) DoAndReturn(f func(context.Context) (int64, error)) *MockServiceTakeSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // UnsetCustomField mocks base method.
 func (m *MockService) UnsetCustomField(ctx context.Context, modelUid, fieldUid string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -149,41 +149,43 @@ func (repo *attributeRepository) SearchAttributeFieldsBySecure(ctx context.Conte
 
 func (repo *attributeRepository) toEntity(req domain.Attribute) dao.Attribute {
 	return dao.Attribute{
-		Id:        req.ID,
-		ModelUID:  req.ModelUid,
-		FieldUid:  req.FieldUid,
-		FieldName: req.FieldName,
-		FieldType: req.FieldType,
-		GroupId:   req.GroupId,
-		Required:  req.Required,
-		Secure:    req.Secure,
-		Builtin:   req.Builtin,
-		Link:      req.Link,
-		Index:     req.Index,
-		SortKey:   req.SortKey,
-		Option:    req.Option,
-		Version:   req.Version,
-		Display:   req.Display,
+		Id:          req.ID,
+		ModelUID:    req.ModelUid,
+		FieldUid:    req.FieldUid,
+		FieldName:   req.FieldName,
+		FieldType:   req.FieldType,
+		Description: req.Description,
+		GroupId:     req.GroupId,
+		Required:    req.Required,
+		Secure:      req.Secure,
+		Builtin:     req.Builtin,
+		Link:        req.Link,
+		Index:       req.Index,
+		SortKey:     req.SortKey,
+		Option:      req.Option,
+		Version:     req.Version,
+		Display:     req.Display,
 	}
 }
 
 func (repo *attributeRepository) toDomain(attr dao.Attribute) domain.Attribute {
 	return domain.Attribute{
-		ID:        attr.Id,
-		FieldUid:  attr.FieldUid,
-		FieldName: attr.FieldName,
-		FieldType: attr.FieldType,
-		ModelUid:  attr.ModelUID,
-		Secure:    attr.Secure,
-		Link:      attr.Link,
-		Builtin:   attr.Builtin,
-		Required:  attr.Required,
-		Option:    attr.Option,
-		Display:   attr.Display,
-		Index:     attr.Index,
-		Version:   attr.Version,
-		SortKey:   attr.SortKey,
-		GroupId:   attr.GroupId,
+		ID:          attr.Id,
+		FieldUid:    attr.FieldUid,
+		FieldName:   attr.FieldName,
+		FieldType:   attr.FieldType,
+		Description: attr.Description,
+		ModelUid:    attr.ModelUID,
+		Secure:      attr.Secure,
+		Link:        attr.Link,
+		Builtin:     attr.Builtin,
+		Required:    attr.Required,
+		Option:      attr.Option,
+		Display:     attr.Display,
+		Index:       attr.Index,
+		Version:     attr.Version,
+		SortKey:     attr.SortKey,
+		GroupId:     attr.GroupId,
 	}
 }
 
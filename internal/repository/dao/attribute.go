@@ -116,13 +116,14 @@ func (dao *attributeDAO) UpdateAttribute(ctx context.Context, attr Attribute) (i
 
 	updateDoc := bson.M{
 		"$set": bson.M{
-			"field_name": attr.FieldName,
-			"field_type": attr.FieldType,
-			"required":   attr.Required,
-			"secure":     attr.Secure,
-			"link":       attr.Link,
-			"option":     attr.Option,
-			"utime":      now,
+			"field_name":  attr.FieldName,
+			"field_type":  attr.FieldType,
+			"description": attr.Description,
+			"required":    attr.Required,
+			"secure":      attr.Secure,
+			"link":        attr.Link,
+			"option":      attr.Option,
+			"utime":       now,
 		},
 		"$inc": bson.M{
 			"version": 1,
@@ -314,24 +315,25 @@ func (dao *attributeDAO) SearchAttributeFieldsBySecure(ctx context.Context, mode
 }
 
 type Attribute struct {
-	TenantID  int64       `bson:"tenant_id"`
-	Id        int64       `bson:"id"`
-	GroupId   int64       `bson:"group_id"`   // 属性所属分组
-	ModelUID  string      `bson:"model_uid"`  // 模型唯一标识
-	FieldUid  string      `bson:"field_uid"`  // 字段唯一标识
-	FieldName string      `bson:"field_name"` // 字段名称
-	FieldType string      `bson:"field_type"` // 字段类型
-	Required  bool        `bson:"required"`   // 是否为必传
-	Display   bool        `bson:"display"`    // 是否前端展示
-	Index     int64       `bson:"index"`      // 字段前端展示顺序
-	SortKey   int64       `bson:"sort_key"`   // 拖拽排序键（稀疏索引）
-	Secure    bool        `bson:"secure"`     // 是否字段安全、脱敏、加密
-	Link      bool        `bson:"link"`       // 是否外链
-	Builtin   bool        `bson:"builtin"`    // 是否内置属性
-	Version   int64       `bson:"version"`    // CAS 操作
-	Option    interface{} `bson:"option"`     // TODO: 为了后续扩展，不同类型的 option 可能不同
-	Ctime     int64       `bson:"ctime"`
-	Utime     int64       `bson:"utime"`
+	TenantID    int64       `bson:"tenant_id"`
+	Id          int64       `bson:"id"`
+	GroupId     int64       `bson:"group_id"`    // 属性所属分组
+	ModelUID    string      `bson:"model_uid"`   // 模型唯一标识
+	FieldUid    string      `bson:"field_uid"`   // 字段唯一标识
+	FieldName   string      `bson:"field_name"`  // 字段名称
+	FieldType   string      `bson:"field_type"`  // 字段类型
+	Description string      `bson:"description"` // 字段说明
+	Required    bool        `bson:"required"`    // 是否为必传
+	Display     bool        `bson:"display"`     // 是否前端展示
+	Index       int64       `bson:"index"`       // 字段前端展示顺序
+	SortKey     int64       `bson:"sort_key"`    // 拖拽排序键（稀疏索引）
+	Secure      bool        `bson:"secure"`      // 是否字段安全、脱敏、加密
+	Link        bool        `bson:"link"`        // 是否外链
+	Builtin     bool        `bson:"builtin"`     // 是否内置属性
+	Version     int64       `bson:"version"`     // CAS 操作
+	Option      interface{} `bson:"option"`      // TODO: 为了后续扩展，不同类型的 option 可能不同
+	Ctime       int64       `bson:"ctime"`
+	Utime       int64       `bson:"utime"`
 }
 
 type AttributePipeline struct {
@@ -0,0 +1,215 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ExportJobCollection = "c_export_job"
+
+type ExportJobDAO interface {
+	// Create 创建导出任务
+	Create(ctx context.Context, job ExportJob) (int64, error)
+
+	// FindById 根据 ID 查询导出任务
+	FindById(ctx context.Context, id int64) (ExportJob, error)
+
+	// ListByModelUid 按创建时间倒序查询模型下的导出任务
+	ListByModelUid(ctx context.Context, modelUid string, offset, limit int64) ([]ExportJob, error)
+
+	// CountByModelUid 统计模型下的导出任务数量
+	CountByModelUid(ctx context.Context, modelUid string) (int64, error)
+
+	// ListPending 跨租户按创建时间顺序查询待执行的导出任务
+	ListPending(ctx context.Context, limit int64) ([]ExportJob, error)
+
+	// Claim 将待执行任务标记为执行中，返回 false 表示已被其他实例领取
+	Claim(ctx context.Context, id int64) (bool, error)
+
+	// UpdateProgress 更新任务进度，同时刷新 utime 作为心跳
+	UpdateProgress(ctx context.Context, job ExportJob) error
+
+	// Finish 记录任务结果
+	Finish(ctx context.Context, job ExportJob) error
+
+	// FailStale 跨租户将心跳超时的执行中任务标记为失败，返回处理数量
+	FailStale(ctx context.Context, before int64, message string) (int64, error)
+}
+
+func NewExportJobDAO(db *mongox.DB) ExportJobDAO {
+	return &exportJobDAO{
+		coll: mongox.NewCollection[ExportJob](db, ExportJobCollection),
+	}
+}
+
+type exportJobDAO struct {
+	coll *mongox.Collection[ExportJob]
+}
+
+func (dao *exportJobDAO) Create(ctx context.Context, job ExportJob) (int64, error) {
+	now := time.Now().UnixMilli()
+	job.Ctime, job.Utime = now, now
+
+	if _, err := dao.coll.InsertOne(ctx, &job); err != nil {
+		return 0, fmt.Errorf("插入数据错误: %w", err)
+	}
+
+	return job.Id, nil
+}
+
+func (dao *exportJobDAO) FindById(ctx context.Context, id int64) (ExportJob, error) {
+	job, err := dao.coll.FindOne(ctx, bson.M{"id": id})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return ExportJob{}, fmt.Errorf("导出任务查询: %w", errs.ErrNotFound)
+		}
+		return ExportJob{}, fmt.Errorf("解码错误: %w", err)
+	}
+
+	return *job, nil
+}
+
+func (dao *exportJobDAO) ListByModelUid(ctx context.Context, modelUid string, offset, limit int64) ([]ExportJob, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: -1}},
+		Skip:  &offset,
+		Limit: &limit,
+		// NOTE: 列表不返回导出条件明细
+		Projection: bson.M{"resource_ids": 0, "filter_groups": 0},
+	}
+
+	return dao.coll.Find(ctx, bson.M{"model_uid": modelUid}, opts)
+}
+
+func (dao *exportJobDAO) CountByModelUid(ctx context.Context, modelUid string) (int64, error) {
+	count, err := dao.coll.CountDocuments(ctx, bson.M{"model_uid": modelUid})
+	if err != nil {
+		return 0, fmt.Errorf("文档计数错误: %w", err)
+	}
+
+	return count, nil
+}
+
+func (dao *exportJobDAO) ListPending(ctx context.Context, limit int64) ([]ExportJob, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: 1}},
+		Limit: &limit,
+	}
+
+	return dao.coll.Find(plugin.IgnoreTenantContext(ctx), bson.M{"status": "pending"}, opts)
+}
+
+func (dao *exportJobDAO) Claim(ctx context.Context, id int64) (bool, error) {
+	result, err := dao.coll.UpdateOne(ctx, bson.M{"id": id, "status": "pending"}, bson.M{
+		"$set": bson.M{
+			"status": "running",
+			"utime":  time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("领取导出任务错误: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (dao *exportJobDAO) UpdateProgress(ctx context.Context, job ExportJob) error {
+	_, err := dao.coll.UpdateOne(ctx, bson.M{"id": job.Id}, bson.M{
+		"$set": bson.M{
+			"processed": job.Processed,
+			"rows":      job.Rows,
+			"utime":     time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("更新导出进度错误: %w", err)
+	}
+
+	return nil
+}
+
+func (dao *exportJobDAO) Finish(ctx context.Context, job ExportJob) error {
+	now := time.Now().UnixMilli()
+	_, err := dao.coll.UpdateOne(ctx, bson.M{"id": job.Id}, bson.M{
+		"$set": bson.M{
+			"status":    job.Status,
+			"processed": job.Processed,
+			"rows":      job.Rows,
+			"file_key":  job.FileKey,
+			"size":      job.Size,
+			"message":   job.Message,
+			"utime":     now,
+			"ftime":     now,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("记录导出结果错误: %w", err)
+	}
+
+	return nil
+}
+
+func (dao *exportJobDAO) FailStale(ctx context.Context, before int64, message string) (int64, error) {
+	now := time.Now().UnixMilli()
+	result, err := dao.coll.UpdateMany(plugin.IgnoreTenantContext(ctx),
+		bson.M{"status": "running", "utime": bson.M{"$lt": before}},
+		bson.M{"$set": bson.M{
+			"status":  "failed",
+			"message": message,
+			"utime":   now,
+			"ftime":   now,
+		}})
+	if err != nil {
+		return 0, fmt.Errorf("处理超时导出任务错误: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ExportJob 异步导出任务，筛选条件及排序与保存视图使用相同的存储结构
+type ExportJob struct {
+	TenantID     int64                  `bson:"tenant_id"`
+	Id           int64                  `bson:"id"`
+	ModelUID     string                 `bson:"model_uid"`
+	ResourceIDs  []int64                `bson:"resource_ids"`
+	FilterGroups []SavedViewFilterGroup `bson:"filter_groups"`
+	Fields       []string               `bson:"fields"`
+	Sorts        []SavedViewSort        `bson:"sorts"`
+	Relations    []ExportRelation       `bson:"relations"`
+	FileName     string                 `bson:"file_name"`
+	Format       string                 `bson:"format"`
+	Delimiter    string                 `bson:"delimiter"`
+	Encoding     string                 `bson:"encoding"`
+	Status       string                 `bson:"status"`
+	Processed    int                    `bson:"processed"`
+	Rows         int                    `bson:"rows"`
+	FileKey      string                 `bson:"file_key"`
+	Size         int64                  `bson:"size"`
+	Message      string                 `bson:"message"`
+	CreatorID    int64                  `bson:"creator_id"`
+	Ctime        int64                  `bson:"ctime"`
+	Utime        int64                  `bson:"utime"`
+	Ftime        int64                  `bson:"ftime"`
+}
+
+func (j *ExportJob) SetID(id int64) {
+	j.Id = id
+}
+
+func (j *ExportJob) GetID() int64 {
+	return j.Id
+}
+
+type ExportRelation struct {
+	RelationName string   `bson:"relation_name"`
+	Fields       []string `bson:"fields"`
+	Mode         string   `bson:"mode"`
+	Separator    string   `bson:"separator"`
+}
@@ -0,0 +1,235 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ImportJobCollection = "c_import_job"
+
+type ImportJobDAO interface {
+	// Create 创建导入任务
+	Create(ctx context.Context, job ImportJob) (int64, error)
+
+	// FindById 根据 ID 查询导入任务
+	FindById(ctx context.Context, id int64) (ImportJob, error)
+
+	// ListByModelUid 按创建时间倒序查询模型下的导入任务
+	ListByModelUid(ctx context.Context, modelUid string, offset, limit int64) ([]ImportJob, error)
+
+	// CountByModelUid 统计模型下的导入任务数量
+	CountByModelUid(ctx context.Context, modelUid string) (int64, error)
+
+	// ListPending 跨租户按创建时间顺序查询待执行的导入任务
+	ListPending(ctx context.Context, limit int64) ([]ImportJob, error)
+
+	// Claim 将待执行任务标记为执行中，返回 false 表示已被其他实例领取
+	Claim(ctx context.Context, id int64) (bool, error)
+
+	// UpdateProgress 更新任务进度，同时刷新 utime 作为心跳
+	UpdateProgress(ctx context.Context, job ImportJob) error
+
+	// Finish 记录任务结果
+	Finish(ctx context.Context, job ImportJob) error
+
+	// FailStale 跨租户将心跳超时的执行中任务标记为失败，返回处理数量
+	FailStale(ctx context.Context, before int64, message string) (int64, error)
+}
+
+func NewImportJobDAO(db *mongox.DB) ImportJobDAO {
+	return &importJobDAO{
+		coll: mongox.NewCollection[ImportJob](db, ImportJobCollection),
+	}
+}
+
+type importJobDAO struct {
+	coll *mongox.Collection[ImportJob]
+}
+
+func (dao *importJobDAO) Create(ctx context.Context, job ImportJob) (int64, error) {
+	now := time.Now().UnixMilli()
+	job.Ctime, job.Utime = now, now
+
+	if _, err := dao.coll.InsertOne(ctx, &job); err != nil {
+		return 0, fmt.Errorf("插入数据错误: %w", err)
+	}
+
+	return job.Id, nil
+}
+
+func (dao *importJobDAO) FindById(ctx context.Context, id int64) (ImportJob, error) {
+	job, err := dao.coll.FindOne(ctx, bson.M{"id": id})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return ImportJob{}, fmt.Errorf("导入任务查询: %w", errs.ErrNotFound)
+		}
+		return ImportJob{}, fmt.Errorf("解码错误: %w", err)
+	}
+
+	return *job, nil
+}
+
+func (dao *importJobDAO) ListByModelUid(ctx context.Context, modelUid string, offset, limit int64) ([]ImportJob, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: -1}},
+		Skip:  &offset,
+		Limit: &limit,
+		// NOTE: 列表不返回行级错误明细
+		Projection: bson.M{"errors": 0},
+	}
+
+	return dao.coll.Find(ctx, bson.M{"model_uid": modelUid}, opts)
+}
+
+func (dao *importJobDAO) CountByModelUid(ctx context.Context, modelUid string) (int64, error) {
+	count, err := dao.coll.CountDocuments(ctx, bson.M{"model_uid": modelUid})
+	if err != nil {
+		return 0, fmt.Errorf("文档计数错误: %w", err)
+	}
+
+	return count, nil
+}
+
+func (dao *importJobDAO) ListPending(ctx context.Context, limit int64) ([]ImportJob, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: 1}},
+		Limit: &limit,
+	}
+
+	return dao.coll.Find(plugin.IgnoreTenantContext(ctx), bson.M{"status": "pending"}, opts)
+}
+
+func (dao *importJobDAO) Claim(ctx context.Context, id int64) (bool, error) {
+	result, err := dao.coll.UpdateOne(ctx, bson.M{"id": id, "status": "pending"}, bson.M{
+		"$set": bson.M{
+			"status": "running",
+			"utime":  time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("领取导入任务错误: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (dao *importJobDAO) UpdateProgress(ctx context.Context, job ImportJob) error {
+	_, err := dao.coll.UpdateOne(ctx, bson.M{"id": job.Id}, bson.M{
+		"$set": bson.M{
+			"total":     job.Total,
+			"processed": job.Processed,
+			"inserted":  job.Inserted,
+			"updated":   job.Updated,
+			"failed":    job.Failed,
+			"utime":     time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("更新导入进度错误: %w", err)
+	}
+
+	return nil
+}
+
+func (dao *importJobDAO) Finish(ctx context.Context, job ImportJob) error {
+	now := time.Now().UnixMilli()
+	_, err := dao.coll.UpdateOne(ctx, bson.M{"id": job.Id}, bson.M{
+		"$set": bson.M{
+			"status":     job.Status,
+			"total":      job.Total,
+			"processed":  job.Processed,
+			"inserted":   job.Inserted,
+			"updated":    job.Updated,
+			"failed":     job.Failed,
+			"sheets":     job.Sheets,
+			"errors":     job.Errors,
+			"report_key": job.ReportKey,
+			"message":    job.Message,
+			"utime":      now,
+			"ftime":      now,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("记录导入结果错误: %w", err)
+	}
+
+	return nil
+}
+
+func (dao *importJobDAO) FailStale(ctx context.Context, before int64, message string) (int64, error) {
+	now := time.Now().UnixMilli()
+	result, err := dao.coll.UpdateMany(plugin.IgnoreTenantContext(ctx),
+		bson.M{"status": "running", "utime": bson.M{"$lt": before}},
+		bson.M{"$set": bson.M{
+			"status":  "failed",
+			"message": message,
+			"utime":   now,
+			"ftime":   now,
+		}})
+	if err != nil {
+		return 0, fmt.Errorf("处理超时导入任务错误: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+type ImportJob struct {
+	TenantID  int64            `bson:"tenant_id"`
+	Id        int64            `bson:"id"`
+	ModelUID  string           `bson:"model_uid"`
+	FileKey   string           `bson:"file_key"`
+	FileName  string           `bson:"file_name"`
+	Format    string           `bson:"format"`
+	Delimiter string           `bson:"delimiter"`
+	Encoding  string           `bson:"encoding"`
+	Workbook  bool             `bson:"workbook"`
+	Mapping   *ImportMapping   `bson:"mapping,omitempty"`
+	Mode      string           `bson:"mode"`
+	DryRun    bool             `bson:"dry_run"`
+	Status    string           `bson:"status"`
+	Total     int              `bson:"total"`
+	Processed int              `bson:"processed"`
+	Inserted  int              `bson:"inserted"`
+	Updated   int              `bson:"updated"`
+	Failed    int              `bson:"failed"`
+	Sheets    []ImportSheet    `bson:"sheets"`
+	Errors    []ImportRowError `bson:"errors"`
+	ReportKey string           `bson:"report_key"`
+	Message   string           `bson:"message"`
+	CreatorID int64            `bson:"creator_id"`
+	Ctime     int64            `bson:"ctime"`
+	Utime     int64            `bson:"utime"`
+	Ftime     int64            `bson:"ftime"`
+}
+
+func (j *ImportJob) SetID(id int64) {
+	j.Id = id
+}
+
+func (j *ImportJob) GetID() int64 {
+	return j.Id
+}
+
+type ImportSheet struct {
+	Sheet    string `bson:"sheet"`
+	ModelUID string `bson:"model_uid"`
+	Total    int    `bson:"total"`
+	Inserted int    `bson:"inserted"`
+	Updated  int    `bson:"updated"`
+	Failed   int    `bson:"failed"`
+}
+
+type ImportRowError struct {
+	Sheet    string `bson:"sheet"`
+	Row      int    `bson:"row"`
+	Cell     string `bson:"cell"`
+	FieldUid string `bson:"field_uid"`
+	Message  string `bson:"message"`
+}
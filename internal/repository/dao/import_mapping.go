@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ImportMappingCollection = "c_import_mapping"
+
+type ImportMappingDAO interface {
+	// Create 创建映射方案
+	Create(ctx context.Context, mapping ImportMapping) (int64, error)
+
+	// Update 修改映射方案
+	Update(ctx context.Context, mapping ImportMapping) (int64, error)
+
+	// Delete 删除映射方案
+	Delete(ctx context.Context, id int64) (int64, error)
+
+	// FindById 根据 ID 查询映射方案
+	FindById(ctx context.Context, id int64) (ImportMapping, error)
+
+	// ListByModelUid 按名称查询模型下的映射方案
+	ListByModelUid(ctx context.Context, modelUid string) ([]ImportMapping, error)
+}
+
+func NewImportMappingDAO(db *mongox.DB) ImportMappingDAO {
+	return &importMappingDAO{
+		coll: mongox.NewCollection[ImportMapping](db, ImportMappingCollection),
+	}
+}
+
+type importMappingDAO struct {
+	coll *mongox.Collection[ImportMapping]
+}
+
+func (dao *importMappingDAO) Create(ctx context.Context, mapping ImportMapping) (int64, error) {
+	now := time.Now().UnixMilli()
+	mapping.Ctime, mapping.Utime = now, now
+
+	if _, err := dao.coll.InsertOne(ctx, &mapping); err != nil {
+		if mongox.IsUniqueConstraintError(err) {
+			return 0, fmt.Errorf("映射方案插入: %w", errs.ErrUniqueDuplicate)
+		}
+		return 0, fmt.Errorf("插入数据错误: %w", err)
+	}
+
+	return mapping.Id, nil
+}
+
+func (dao *importMappingDAO) Update(ctx context.Context, mapping ImportMapping) (int64, error) {
+	result, err := dao.coll.UpdateOne(ctx, bson.M{"id": mapping.Id}, bson.M{
+		"$set": bson.M{
+			"name":       mapping.Name,
+			"header_row": mapping.HeaderRow,
+			"columns":    mapping.Columns,
+			"utime":      time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		if mongox.IsUniqueConstraintError(err) {
+			return 0, fmt.Errorf("映射方案修改: %w", errs.ErrUniqueDuplicate)
+		}
+		return 0, fmt.Errorf("修改文档操作: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (dao *importMappingDAO) Delete(ctx context.Context, id int64) (int64, error) {
+	result, err := dao.coll.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return 0, fmt.Errorf("删除文档错误: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (dao *importMappingDAO) FindById(ctx context.Context, id int64) (ImportMapping, error) {
+	mapping, err := dao.coll.FindOne(ctx, bson.M{"id": id})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return ImportMapping{}, fmt.Errorf("映射方案查询: %w", errs.ErrNotFound)
+		}
+		return ImportMapping{}, fmt.Errorf("解码错误: %w", err)
+	}
+
+	return *mapping, nil
+}
+
+func (dao *importMappingDAO) ListByModelUid(ctx context.Context, modelUid string) ([]ImportMapping, error) {
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "name", Value: 1}},
+	}
+
+	return dao.coll.Find(ctx, bson.M{"model_uid": modelUid}, opts)
+}
+
+type ImportMapping struct {
+	TenantID  int64                 `bson:"tenant_id"`
+	Id        int64                 `bson:"id"`
+	ModelUID  string                `bson:"model_uid"`
+	Name      string                `bson:"name"`
+	HeaderRow int                   `bson:"header_row"`
+	Columns   []ImportColumnMapping `bson:"columns"`
+	CreatorID int64                 `bson:"creator_id"`
+	Ctime     int64                 `bson:"ctime"`
+	Utime     int64                 `bson:"utime"`
+}
+
+func (m *ImportMapping) SetID(id int64) {
+	m.Id = id
+}
+
+func (m *ImportMapping) GetID() int64 {
+	return m.Id
+}
+
+type ImportColumnMapping struct {
+	Source     string                  `bson:"source"`
+	FieldUid   string                  `bson:"field_uid"`
+	Transforms []ImportColumnTransform `bson:"transforms"`
+}
+
+type ImportColumnTransform struct {
+	Type      string            `bson:"type"`
+	Separator string            `bson:"separator,omitempty"`
+	Index     int               `bson:"index,omitempty"`
+	Pattern   string            `bson:"pattern,omitempty"`
+	Mapping   map[string]string `bson:"mapping,omitempty"`
+	Layout    string            `bson:"layout,omitempty"`
+	Output    string            `bson:"output,omitempty"`
+}
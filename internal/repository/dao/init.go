@@ -30,6 +30,34 @@ func InitIndexes(db *mongox.DB) error {
 	if err := initResourceIndexes(db); err != nil {
 		return err
 	}
+	if err := initHistoryIndexes(db); err != nil {
+		return err
+	}
+
+	// SavedView 索引
+	if err := initSavedViewIndexes(db); err != nil {
+		return err
+	}
+
+	// ImportJob 索引
+	if err := initImportJobIndexes(db); err != nil {
+		return err
+	}
+
+	// ImportMapping 索引
+	if err := initImportMappingIndexes(db); err != nil {
+		return err
+	}
+
+	// ExportJob 索引
+	if err := initExportJobIndexes(db); err != nil {
+		return err
+	}
+
+	// ScheduledExport 索引
+	if err := initScheduledExportIndexes(db); err != nil {
+		return err
+	}
 
 	// Relation 索引
 	if err := initRTIndex(db); err != nil {
@@ -86,7 +114,7 @@ func initPluginIndexes(db *mongox.DB) error {
 		return err
 	}
 
-	return mongox.SyncIndexes(ctx, db.Database().Collection(PluginBindingCollection), []mongo.IndexModel{
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(PluginBindingCollection), []mongo.IndexModel{
 		{
 			Keys: bson.D{
 				{Key: "tenant_id", Value: 1},
@@ -116,6 +144,65 @@ func initPluginIndexes(db *mongox.DB) error {
 				{Key: "enabled", Value: 1},
 			},
 		},
+	}); err != nil {
+		return err
+	}
+
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(PluginCredentialCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "plugin_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(PluginVersionCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "plugin_id", Value: 1},
+				{Key: "revision", Value: -1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(PluginOwnershipCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "plugin_id", Value: 1},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return mongox.SyncIndexes(ctx, db.Database().Collection(PluginAuditLogCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "ctime", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "plugin_id", Value: 1},
+				{Key: "ctime", Value: -1},
+			},
+		},
+		{
+			// NOTE: 每条日志写入时按保留时长计算 expire_at，保留时长调整后无需重建索引
+			Keys:    bson.D{{Key: "expire_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
 	})
 }
 
@@ -136,6 +223,143 @@ func initModelGroupIndex(db *mongox.DB) error {
 	return mongox.SyncIndexes(ctx, col, indexes)
 }
 
+// initSavedViewIndexes 保存视图及默认视图的索引
+func initSavedViewIndexes(db *mongox.DB) error {
+	ctx := context.Background()
+
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(SavedViewCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "model_uid", Value: 1},
+				{Key: "owner_id", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	return mongox.SyncIndexes(ctx, db.Database().Collection(SavedViewDefaultCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "user_id", Value: 1},
+				{Key: "model_uid", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "view_id", Value: 1}},
+		},
+	})
+}
+
+// initImportJobIndexes 异步导入任务的索引
+func initImportJobIndexes(db *mongox.DB) error {
+	col := db.Database().Collection(ImportJobCollection)
+	ctx := context.Background()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "model_uid", Value: 1},
+				{Key: "ctime", Value: -1},
+			},
+		},
+		{
+			// 后台任务跨租户领取待执行任务及清理超时任务
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "ctime", Value: 1},
+			},
+		},
+	}
+
+	return mongox.SyncIndexes(ctx, col, indexes)
+}
+
+// initImportMappingIndexes 导入映射方案的索引，同一模型下方案名称唯一
+func initImportMappingIndexes(db *mongox.DB) error {
+	return mongox.SyncIndexes(context.Background(), db.Database().Collection(ImportMappingCollection),
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "tenant_id", Value: 1},
+					{Key: "model_uid", Value: 1},
+					{Key: "name", Value: 1},
+				},
+				Options: options.Index().SetUnique(true),
+			},
+		})
+}
+
+// initExportJobIndexes 异步导出任务的索引
+func initExportJobIndexes(db *mongox.DB) error {
+	col := db.Database().Collection(ExportJobCollection)
+	ctx := context.Background()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "model_uid", Value: 1},
+				{Key: "ctime", Value: -1},
+			},
+		},
+		{
+			// 后台任务跨租户领取待执行任务及清理超时任务
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "ctime", Value: 1},
+			},
+		},
+	}
+
+	return mongox.SyncIndexes(ctx, col, indexes)
+}
+
+func initScheduledExportIndexes(db *mongox.DB) error {
+	ctx := context.Background()
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(ScheduledExportCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// 调度器跨租户查询已到执行时间的定时导出
+			Keys: bson.D{
+				{Key: "enabled", Value: 1},
+				{Key: "next_run_at", Value: 1},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return mongox.SyncIndexes(ctx, db.Database().Collection(ScheduledExportRunCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "schedule_id", Value: 1},
+				{Key: "ctime", Value: -1},
+			},
+		},
+		{
+			// 跨租户清理超时的执行记录
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "utime", Value: 1},
+			},
+		},
+	})
+}
+
 func initAttrIndex(db *mongox.DB) error {
 	col := mongox.NewCollection[Attribute](db, AttributeCollection)
 	ctx := context.Background()
@@ -206,6 +430,78 @@ func initResourceIndexes(db *mongox.DB) error {
 	return mongox.SyncIndexes(ctx, col, indexes)
 }
 
+// initHistoryIndexes 历史回溯相关集合的索引
+func initHistoryIndexes(db *mongox.DB) error {
+	ctx := context.Background()
+
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(ResourceChangeCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "resource_id", Value: 1},
+				{Key: "ctime", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "model_uid", Value: 1},
+				{Key: "ctime", Value: 1},
+			},
+		},
+		{
+			// 检索索引跨租户按时间顺序同步变更日志
+			Keys: bson.D{{Key: "ctime", Value: 1}},
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := mongox.SyncIndexes(ctx, db.Database().Collection(ResourceSnapshotCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "resource_id", Value: 1},
+				{Key: "snapshot_time", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "model_uid", Value: 1},
+				{Key: "snapshot_time", Value: -1},
+			},
+		},
+		{
+			// 跨租户查询最近一轮快照及按保留期清理
+			Keys: bson.D{{Key: "snapshot_time", Value: 1}},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return mongox.SyncIndexes(ctx, db.Database().Collection(ResourceRelationDeletedCollection), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "source_resource_id", Value: 1},
+				{Key: "dtime", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "target_resource_id", Value: 1},
+				{Key: "dtime", Value: 1},
+			},
+		},
+		{
+			// 按保留期清理
+			Keys: bson.D{{Key: "dtime", Value: 1}},
+		},
+	})
+}
+
 func initRTIndex(db *mongox.DB) error {
 	col := mongox.NewCollection[RelationType](db, RelationTypeCollection)
 	ctx := context.Background()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/internal/errs"
 	"github.com/Duke1616/ecmdb/pkg/mongox"
 	"github.com/Duke1616/ecmdb/pkg/plugin"
@@ -13,8 +14,12 @@ import (
 )
 
 const (
-	PluginCollection        = "c_plugins"
-	PluginBindingCollection = "c_plugin_bindings"
+	PluginCollection           = "c_plugins"
+	PluginBindingCollection    = "c_plugin_bindings"
+	PluginCredentialCollection = "c_plugin_credentials"
+	PluginAuditLogCollection   = "c_plugin_audit_logs"
+	PluginVersionCollection    = "c_plugin_versions"
+	PluginOwnershipCollection  = "c_plugin_schema_ownerships"
 )
 
 type Plugin struct {
@@ -26,6 +31,7 @@ type Plugin struct {
 	Version  string              `bson:"version"`
 	Actions  []plugin.ActionSpec `bson:"actions"`
 	Meta     map[string]any      `bson:"meta,omitempty"`
+	Health   *plugin.Health      `bson:"health,omitempty"` // 由健康检查写入，插件注册时不覆盖
 	Ctime    int64               `bson:"ctime"`
 	Utime    int64               `bson:"utime"`
 }
@@ -42,6 +48,18 @@ type PluginBinding struct {
 	Utime    int64                `bson:"utime"`
 }
 
+// PluginCredential 插件凭证，只保存共享密钥摘要
+type PluginCredential struct {
+	TenantID   int64  `bson:"tenant_id" eiam:"private"`
+	Id         int64  `bson:"id"`
+	PluginID   string `bson:"plugin_id"`
+	SecretHash string `bson:"secret_hash"`
+	// IdentityKey 经服务端密钥加密的身份签名密钥
+	IdentityKey string `bson:"identity_key"`
+	Ctime       int64  `bson:"ctime"`
+	Utime       int64  `bson:"utime"`
+}
+
 func (p *Plugin) SetID(id int64) {
 	p.Id = id
 }
@@ -58,6 +76,14 @@ func (b *PluginBinding) GetID() int64 {
 	return b.Id
 }
 
+func (c *PluginCredential) SetID(id int64) {
+	c.Id = id
+}
+
+func (c *PluginCredential) GetID() int64 {
+	return c.Id
+}
+
 type PluginDAO interface {
 	// UpsertPlugin 按 UID 创建或更新插件存储记录。
 	UpsertPlugin(ctx context.Context, p Plugin) error
@@ -71,12 +97,21 @@ type PluginDAO interface {
 	// DeleteBinding 删除指定绑定记录。
 	DeleteBinding(ctx context.Context, uid string) error
 
+	// DeleteBindingsByPluginID 删除指定插件的全部绑定，返回删除的数量。
+	DeleteBindingsByPluginID(ctx context.Context, pluginID string) (int64, error)
+
+	// DeletePlugin 删除插件存储记录。
+	DeletePlugin(ctx context.Context, uid string) error
+
 	// GetBinding 根据 UID 查询绑定记录。
 	GetBinding(ctx context.Context, uid string) (PluginBinding, error)
 
 	// GetPlugin 根据 UID 查询插件存储记录。
 	GetPlugin(ctx context.Context, uid string) (Plugin, error)
 
+	// UpdateHealth 更新插件健康检查结果。
+	UpdateHealth(ctx context.Context, uid string, health plugin.Health) error
+
 	// ListPlugins 查询全部插件记录。
 	ListPlugins(ctx context.Context) ([]Plugin, error)
 
@@ -91,17 +126,64 @@ type PluginDAO interface {
 
 	// ListEnabledBindingsByModelUIDs 批量查询指定模型启用中的插件绑定记录。
 	ListEnabledBindingsByModelUIDs(ctx context.Context, modelUIDs []string) ([]PluginBinding, error)
+
+	// UpsertCredential 按插件 UID 创建或替换插件凭证。
+	UpsertCredential(ctx context.Context, c PluginCredential) error
+
+	// GetCredential 根据插件 UID 查询插件凭证。
+	GetCredential(ctx context.Context, pluginID string) (PluginCredential, error)
+
+	// DeleteCredential 删除插件凭证。
+	DeleteCredential(ctx context.Context, pluginID string) error
+
+	// CreateSchemaOwnerships 批量记录插件导入 Schema 时新建的对象。
+	CreateSchemaOwnerships(ctx context.Context, ownerships []PluginSchemaOwnership) error
+
+	// ListSchemaOwnerships 查询插件新建对象的归属记录，按创建时间正序。
+	ListSchemaOwnerships(ctx context.Context, pluginID string) ([]PluginSchemaOwnership, error)
+
+	// DeleteSchemaOwnership 删除单条归属记录。
+	DeleteSchemaOwnership(ctx context.Context, id int64) error
+
+	// CreateVersion 追加插件定义版本快照。
+	CreateVersion(ctx context.Context, v PluginVersion) error
+
+	// GetLatestVersion 查询插件最新的定义版本。
+	GetLatestVersion(ctx context.Context, pluginID string) (PluginVersion, error)
+
+	// GetVersion 按修订号查询插件定义版本。
+	GetVersion(ctx context.Context, pluginID string, revision int64) (PluginVersion, error)
+
+	// ListVersions 查询插件全部定义版本，按修订号倒序，不包含定义原文。
+	ListVersions(ctx context.Context, pluginID string) ([]PluginVersion, error)
+
+	// CreateAuditLog 写入插件动作审计日志。
+	CreateAuditLog(ctx context.Context, log PluginAuditLog) error
+
+	// ListAuditLogs 按条件分页查询审计日志，按时间倒序。
+	ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]PluginAuditLog, error)
+
+	// CountAuditLogs 统计符合条件的审计日志数量。
+	CountAuditLogs(ctx context.Context, query domain.PluginAuditQuery) (int64, error)
 }
 
 type pluginDAO struct {
-	pluginColl  *mongox.Collection[Plugin]
-	bindingColl *mongox.Collection[PluginBinding]
+	pluginColl     *mongox.Collection[Plugin]
+	bindingColl    *mongox.Collection[PluginBinding]
+	credentialColl *mongox.Collection[PluginCredential]
+	auditColl      *mongox.Collection[PluginAuditLog]
+	versionColl    *mongox.Collection[PluginVersion]
+	ownershipColl  *mongox.Collection[PluginSchemaOwnership]
 }
 
 func NewPluginDAO(db *mongox.DB) PluginDAO {
 	return &pluginDAO{
-		pluginColl:  mongox.NewCollection[Plugin](db, PluginCollection),
-		bindingColl: mongox.NewCollection[PluginBinding](db, PluginBindingCollection),
+		pluginColl:     mongox.NewCollection[Plugin](db, PluginCollection),
+		bindingColl:    mongox.NewCollection[PluginBinding](db, PluginBindingCollection),
+		credentialColl: mongox.NewCollection[PluginCredential](db, PluginCredentialCollection),
+		auditColl:      mongox.NewCollection[PluginAuditLog](db, PluginAuditLogCollection),
+		versionColl:    mongox.NewCollection[PluginVersion](db, PluginVersionCollection),
+		ownershipColl:  mongox.NewCollection[PluginSchemaOwnership](db, PluginOwnershipCollection),
 	}
 }
 
@@ -236,6 +318,24 @@ func (dao *pluginDAO) GetPlugin(ctx context.Context, uid string) (Plugin, error)
 	return *p, nil
 }
 
+func (dao *pluginDAO) UpdateHealth(ctx context.Context, uid string, health plugin.Health) error {
+	res, err := dao.pluginColl.UpdateOne(ctx,
+		bson.M{"uid": uid},
+		bson.M{
+			"$set": bson.M{
+				"health": health,
+			},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("更新插件健康状态失败: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("插件查询: %w", errs.ErrNotFound)
+	}
+	return nil
+}
+
 func (dao *pluginDAO) ListPlugins(ctx context.Context) ([]Plugin, error) {
 	opts := &options.FindOptions{
 		Sort: bson.D{{Key: "utime", Value: -1}},
@@ -299,3 +399,49 @@ func (dao *pluginDAO) ListEnabledBindingsByModelUIDs(ctx context.Context, modelU
 	}
 	return bindings, nil
 }
+
+func (dao *pluginDAO) UpsertCredential(ctx context.Context, c PluginCredential) error {
+	now := time.Now().UnixMilli()
+	c.Utime = now
+	if c.Ctime == 0 {
+		c.Ctime = now
+	}
+
+	_, err := dao.credentialColl.FindOne(ctx, bson.M{"plugin_id": c.PluginID})
+	if err != nil {
+		if !mongox.IsNotFoundError(err) {
+			return fmt.Errorf("查询插件凭证失败: %w", err)
+		}
+		_, err = dao.credentialColl.InsertOne(ctx, &c)
+		if err != nil {
+			return fmt.Errorf("创建插件凭证失败: %w", err)
+		}
+		return nil
+	}
+
+	_, err = dao.credentialColl.UpdateOne(ctx,
+		bson.M{"plugin_id": c.PluginID},
+		bson.M{
+			"$set": bson.M{
+				"secret_hash":  c.SecretHash,
+				"identity_key": c.IdentityKey,
+				"utime":        c.Utime,
+			},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("更新插件凭证失败: %w", err)
+	}
+	return nil
+}
+
+func (dao *pluginDAO) GetCredential(ctx context.Context, pluginID string) (PluginCredential, error) {
+	c, err := dao.credentialColl.FindOne(ctx, bson.M{"plugin_id": pluginID})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return PluginCredential{}, fmt.Errorf("插件凭证查询: %w", errs.ErrNotFound)
+		}
+		return PluginCredential{}, fmt.Errorf("插件凭证查询失败: %w", err)
+	}
+	return *c, nil
+}
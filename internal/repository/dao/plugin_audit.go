@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PluginAuditLog 插件动作审计日志，写入调用方所在租户
+type PluginAuditLog struct {
+	TenantID    int64     `bson:"tenant_id" eiam:"private"`
+	Id          int64     `bson:"id"`
+	PluginID    string    `bson:"plugin_id"`
+	Action      string    `bson:"action"`
+	ResourceIDs []int64   `bson:"resource_ids"`
+	UserID      int64     `bson:"user_id"`
+	Source      string    `bson:"source"`
+	SourceIP    string    `bson:"source_ip"`
+	RequestID   string    `bson:"request_id,omitempty"`
+	Method      string    `bson:"method,omitempty"`
+	Path        string    `bson:"path,omitempty"`
+	Success     bool      `bson:"success"`
+	StatusCode  int       `bson:"status_code,omitempty"`
+	Error       string    `bson:"error,omitempty"`
+	DurationMs  int64     `bson:"duration_ms"`
+	Ctime       int64     `bson:"ctime"`
+	ExpireAt    time.Time `bson:"expire_at"` // TTL 索引字段，到期后由 Mongo 自动删除
+}
+
+func (l *PluginAuditLog) SetID(id int64) {
+	l.Id = id
+}
+
+func (l *PluginAuditLog) GetID() int64 {
+	return l.Id
+}
+
+func (dao *pluginDAO) CreateAuditLog(ctx context.Context, log PluginAuditLog) error {
+	if log.Ctime == 0 {
+		log.Ctime = time.Now().UnixMilli()
+	}
+
+	if _, err := dao.auditColl.InsertOne(ctx, &log); err != nil {
+		return fmt.Errorf("写入插件审计日志失败: %w", err)
+	}
+	return nil
+}
+
+func (dao *pluginDAO) ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]PluginAuditLog, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: -1}},
+		Skip:  &query.Offset,
+		Limit: &query.Limit,
+	}
+
+	logs, err := dao.auditColl.Find(ctx, auditLogFilter(query), opts)
+	if err != nil {
+		return nil, fmt.Errorf("插件审计日志查询失败: %w", err)
+	}
+	return logs, nil
+}
+
+func (dao *pluginDAO) CountAuditLogs(ctx context.Context, query domain.PluginAuditQuery) (int64, error) {
+	count, err := dao.auditColl.CountDocuments(ctx, auditLogFilter(query))
+	if err != nil {
+		return 0, fmt.Errorf("文档计数错误: %w", err)
+	}
+	return count, nil
+}
+
+func auditLogFilter(query domain.PluginAuditQuery) bson.M {
+	filter := bson.M{}
+	if query.PluginID != "" {
+		filter["plugin_id"] = query.PluginID
+	}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.UserID > 0 {
+		filter["user_id"] = query.UserID
+	}
+	if query.ResourceID > 0 {
+		filter["resource_ids"] = query.ResourceID
+	}
+	if query.Source != "" {
+		filter["source"] = query.Source
+	}
+	if query.Success != nil {
+		filter["success"] = *query.Success
+	}
+
+	ctime := bson.M{}
+	if query.StartTime > 0 {
+		ctime["$gte"] = query.StartTime
+	}
+	if query.EndTime > 0 {
+		ctime["$lte"] = query.EndTime
+	}
+	if len(ctime) > 0 {
+		filter["ctime"] = ctime
+	}
+	return filter
+}
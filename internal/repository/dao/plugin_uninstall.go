@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PluginSchemaOwnership 插件导入 Schema 时新建对象的归属记录，写入导入时所在租户
+type PluginSchemaOwnership struct {
+	TenantID int64  `bson:"tenant_id" eiam:"private"`
+	Id       int64  `bson:"id"`
+	PluginID string `bson:"plugin_id"`
+	Kind     string `bson:"kind"`
+	ModelUID string `bson:"model_uid"`
+	UID      string `bson:"uid"`
+	Ctime    int64  `bson:"ctime"`
+}
+
+func (o *PluginSchemaOwnership) SetID(id int64) {
+	o.Id = id
+}
+
+func (o *PluginSchemaOwnership) GetID() int64 {
+	return o.Id
+}
+
+func (dao *pluginDAO) CreateSchemaOwnerships(ctx context.Context, ownerships []PluginSchemaOwnership) error {
+	if len(ownerships) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	docs := make([]*PluginSchemaOwnership, 0, len(ownerships))
+	for i := range ownerships {
+		ownerships[i].Ctime = now
+		docs = append(docs, &ownerships[i])
+	}
+	if _, err := dao.ownershipColl.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("记录插件 Schema 归属失败: %w", err)
+	}
+	return nil
+}
+
+func (dao *pluginDAO) ListSchemaOwnerships(ctx context.Context, pluginID string) ([]PluginSchemaOwnership, error) {
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "ctime", Value: 1}},
+	}
+
+	ownerships, err := dao.ownershipColl.Find(ctx, bson.M{"plugin_id": pluginID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("插件 Schema 归属查询失败: %w", err)
+	}
+	return ownerships, nil
+}
+
+func (dao *pluginDAO) DeleteSchemaOwnership(ctx context.Context, id int64) error {
+	if _, err := dao.ownershipColl.DeleteOne(ctx, bson.M{"id": id}); err != nil {
+		return fmt.Errorf("删除插件 Schema 归属失败: %w", err)
+	}
+	return nil
+}
+
+func (dao *pluginDAO) DeleteBindingsByPluginID(ctx context.Context, pluginID string) (int64, error) {
+	res, err := dao.bindingColl.DeleteMany(ctx, bson.M{"plugin_id": pluginID})
+	if err != nil {
+		return 0, fmt.Errorf("删除插件绑定失败: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+func (dao *pluginDAO) DeletePlugin(ctx context.Context, uid string) error {
+	res, err := dao.pluginColl.DeleteOne(ctx, bson.M{"uid": uid})
+	if err != nil {
+		return fmt.Errorf("删除插件失败: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("插件删除: %w", errs.ErrNotFound)
+	}
+	return nil
+}
+
+func (dao *pluginDAO) DeleteCredential(ctx context.Context, pluginID string) error {
+	if _, err := dao.credentialColl.DeleteOne(ctx, bson.M{"plugin_id": pluginID}); err != nil {
+		return fmt.Errorf("删除插件凭证失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PluginVersion 插件定义的不可变快照，每次导入内容变化时追加一条
+type PluginVersion struct {
+	TenantID   int64  `bson:"tenant_id" eiam:"private"`
+	Id         int64  `bson:"id"`
+	PluginID   string `bson:"plugin_id"`
+	Version    string `bson:"version"`
+	Revision   int64  `bson:"revision"`
+	Checksum   string `bson:"checksum"`
+	Definition string `bson:"definition"` // 定义 JSON 原文，避免 bson 往返改变动态字段类型
+	Source     string `bson:"source"`
+	Ctime      int64  `bson:"ctime"`
+}
+
+func (v *PluginVersion) SetID(id int64) {
+	v.Id = id
+}
+
+func (v *PluginVersion) GetID() int64 {
+	return v.Id
+}
+
+func (dao *pluginDAO) CreateVersion(ctx context.Context, v PluginVersion) error {
+	v.Ctime = time.Now().UnixMilli()
+
+	if _, err := dao.versionColl.InsertOne(ctx, &v); err != nil {
+		return fmt.Errorf("创建插件版本失败: %w", err)
+	}
+	return nil
+}
+
+func (dao *pluginDAO) GetLatestVersion(ctx context.Context, pluginID string) (PluginVersion, error) {
+	v, err := dao.versionColl.FindOne(ctx, bson.M{"plugin_id": pluginID},
+		options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}}))
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return PluginVersion{}, fmt.Errorf("插件版本查询: %w", errs.ErrNotFound)
+		}
+		return PluginVersion{}, fmt.Errorf("插件版本查询失败: %w", err)
+	}
+	return *v, nil
+}
+
+func (dao *pluginDAO) GetVersion(ctx context.Context, pluginID string, revision int64) (PluginVersion, error) {
+	v, err := dao.versionColl.FindOne(ctx, bson.M{"plugin_id": pluginID, "revision": revision})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return PluginVersion{}, fmt.Errorf("插件版本查询: %w", errs.ErrNotFound)
+		}
+		return PluginVersion{}, fmt.Errorf("插件版本查询失败: %w", err)
+	}
+	return *v, nil
+}
+
+func (dao *pluginDAO) ListVersions(ctx context.Context, pluginID string) ([]PluginVersion, error) {
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "revision", Value: -1}},
+		// NOTE: 列表不返回定义原文
+		Projection: bson.M{"definition": 0},
+	}
+
+	versions, err := dao.versionColl.Find(ctx, bson.M{"plugin_id": pluginID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("插件版本查询失败: %w", err)
+	}
+	return versions, nil
+}
@@ -0,0 +1,89 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ResourceRelationDeletedCollection = "c_relation_resource_deleted"
+
+func (dao *resourceRelationDAO) ListRelationsAsOf(ctx context.Context, isSource bool, ids []int64,
+	asOf int64) ([]ResourceRelation, error) {
+	if len(ids) == 0 {
+		return []ResourceRelation{}, nil
+	}
+
+	field := "target_resource_id"
+	if isSource {
+		field = "source_resource_id"
+	}
+
+	// 1. 仍然存在且在 asOf 之前建立的关联
+	current, err := dao.coll.Find(ctx, bson.M{
+		field:   bson.M{"$in": ids},
+		"ctime": bson.M{"$lte": asOf},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询资源关联错误: %w", err)
+	}
+
+	// 2. 在 asOf 之前建立、之后才被删除的关联
+	deleted, err := dao.deleted.Find(ctx, bson.M{
+		field:   bson.M{"$in": ids},
+		"ctime": bson.M{"$lte": asOf},
+		"dtime": bson.M{"$gt": asOf},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询已删除资源关联错误: %w", err)
+	}
+
+	return append(current, lo.Map(deleted, func(src DeletedResourceRelation, _ int) ResourceRelation {
+		return src.ResourceRelation
+	})...), nil
+}
+
+// deleteAndRecord 删除匹配的资源关联并保留删除记录，limit 为 0 时删除全部匹配项
+// NOTE: 关联的当前状态保存在主集合中，历史回溯只需要额外补齐已被删除的关联
+func (dao *resourceRelationDAO) deleteAndRecord(ctx context.Context, filter bson.M, limit int64) (int64, error) {
+	opts := &options.FindOptions{}
+	if limit > 0 {
+		opts.Limit = &limit
+	}
+	rrs, err := dao.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("查询文档错误: %w", err)
+	}
+	if len(rrs) == 0 {
+		return 0, nil
+	}
+
+	result, err := dao.coll.DeleteMany(ctx, bson.M{"id": bson.M{"$in": lo.Map(rrs, func(rr ResourceRelation, _ int) int64 {
+		return rr.Id
+	})}})
+	if err != nil {
+		return 0, fmt.Errorf("删除文档错误: %w", err)
+	}
+
+	// 删除记录只服务于历史回溯，写入失败不影响主流程
+	dtime := time.Now().UnixMilli()
+	docs := lo.Map(rrs, func(rr ResourceRelation, _ int) *DeletedResourceRelation {
+		return &DeletedResourceRelation{ResourceRelation: rr, Dtime: dtime}
+	})
+	if _, err = dao.deleted.InsertMany(ctx, docs); err != nil {
+		dao.logger.Error("记录资源关联删除失败", elog.FieldErr(err))
+	}
+
+	return result.DeletedCount, nil
+}
+
+// DeletedResourceRelation 已删除的资源关联，Dtime 为删除时间
+type DeletedResourceRelation struct {
+	ResourceRelation `bson:",inline"`
+	Dtime            int64 `bson:"dtime"`
+}
@@ -40,6 +40,12 @@ type RelationModelDAO interface {
 	// CountByRelationTypeUid 根据关联类型 UID 获取数量
 	CountByRelationTypeUid(ctx context.Context, uid string) (int64, error)
 
+	// ListByRelationTypeUid 根据关联类型 UID 获取所有模型关联
+	ListByRelationTypeUid(ctx context.Context, uid string) ([]ModelRelation, error)
+
+	// DeleteByRelationTypeUid 根据关联类型 UID 批量删除模型关联
+	DeleteByRelationTypeUid(ctx context.Context, uid string) (int64, error)
+
 	// GetByID 根据 ID 获取数据
 	GetByID(ctx context.Context, id int64) (ModelRelation, error)
 
@@ -171,6 +177,26 @@ func (dao *modelRelationDAO) CountByRelationTypeUid(ctx context.Context, uid str
 	return count, nil
 }
 
+func (dao *modelRelationDAO) ListByRelationTypeUid(ctx context.Context, uid string) ([]ModelRelation, error) {
+	filter := bson.M{"relation_type_uid": uid}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "id", Value: 1}},
+	}
+
+	return dao.coll.Find(ctx, filter, opts)
+}
+
+func (dao *modelRelationDAO) DeleteByRelationTypeUid(ctx context.Context, uid string) (int64, error) {
+	filter := bson.M{"relation_type_uid": uid}
+
+	result, err := dao.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("删除文档错误: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
 func (dao *modelRelationDAO) GetByID(ctx context.Context, id int64) (ModelRelation, error) {
 	filter := bson.M{"id": id}
 	res, err := dao.coll.FindOne(ctx, filter)
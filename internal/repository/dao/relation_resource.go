@@ -8,6 +8,7 @@ import (
 	"github.com/Duke1616/ecmdb/internal/errs"
 	"github.com/Duke1616/ecmdb/pkg/mongox"
 	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/gotomicro/ego/core/elog"
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -29,6 +30,9 @@ type RelationResourceDAO interface {
 	ListSrcAggregated(ctx context.Context, modelUid string, id int64) ([]ResourceAggregatedAsset, error)
 	ListDstAggregated(ctx context.Context, modelUid string, id int64) ([]ResourceAggregatedAsset, error)
 
+	// ListRelatedIdsBatch 批量聚合查询资源在指定关联下的对端资源 ID，isSource 表示 ids 位于关联的源端
+	ListRelatedIdsBatch(ctx context.Context, relationName string, isSource bool, ids []int64) ([]ResourceRelatedIds, error)
+
 	ListSrcRelated(ctx context.Context, modelUid, relationName string, id int64) ([]int64, error)
 	ListDstRelated(ctx context.Context, modelUid, relationName string, id int64) ([]int64, error)
 
@@ -42,20 +46,48 @@ type RelationResourceDAO interface {
 	// CountByRelationName 根据关联名称获取数量
 	CountByRelationName(ctx context.Context, name string) (int64, error)
 
+	// ListByRelationName 根据关联名称查询资源关联，limit 为 0 时不限制数量
+	ListByRelationName(ctx context.Context, name string, limit int64) ([]ResourceRelation, error)
+
+	// DeleteByRelationName 根据关联名称批量删除资源关联
+	DeleteByRelationName(ctx context.Context, name string) (int64, error)
+
+	// DeleteByRelationTypeUid 根据关联类型 UID 批量删除资源关联
+	DeleteByRelationTypeUid(ctx context.Context, uid string) (int64, error)
+
+	// DeleteByIds 根据 ID 批量删除资源关联
+	DeleteByIds(ctx context.Context, ids []int64) (int64, error)
+
+	// MigrateRelationName 将指定的资源关联改挂到新的关联定义下
+	MigrateRelationName(ctx context.Context, ids []int64, relationTypeUid, relationName string) (int64, error)
+
+	// ListAfterID 按 ID 游标分页查询资源关联，用于全量扫描
+	ListAfterID(ctx context.Context, afterID int64, limit int64) ([]ResourceRelation, error)
+
+	// ListRelationNames 查询资源关联中出现过的所有关联名称
+	ListRelationNames(ctx context.Context) ([]string, error)
+
 	ListRecursiveSrc(ctx context.Context, modelUid string, id int64, maxDepth int) ([]ResourceRelation, error)
 	ListRecursiveDst(ctx context.Context, modelUid string, id int64, maxDepth int) ([]ResourceRelation, error)
+
+	// ListRelationsAsOf 查询 asOf 时刻存在的资源关联，isSource 表示 ids 位于关联的源端
+	ListRelationsAsOf(ctx context.Context, isSource bool, ids []int64, asOf int64) ([]ResourceRelation, error)
 }
 
 func NewRelationResourceDAO(db *mongox.DB) RelationResourceDAO {
 	return &resourceRelationDAO{
-		db:   db,
-		coll: mongox.NewCollection[ResourceRelation](db, ResourceRelationCollection),
+		db:      db,
+		coll:    mongox.NewCollection[ResourceRelation](db, ResourceRelationCollection),
+		deleted: mongox.NewCollection[DeletedResourceRelation](db, ResourceRelationDeletedCollection),
+		logger:  elog.DefaultLogger,
 	}
 }
 
 type resourceRelationDAO struct {
-	db   *mongox.DB
-	coll *mongox.Collection[ResourceRelation]
+	db      *mongox.DB
+	coll    *mongox.Collection[ResourceRelation]
+	deleted *mongox.Collection[DeletedResourceRelation]
+	logger  *elog.Component
 }
 
 func (dao *resourceRelationDAO) CreateResourceRelation(ctx context.Context, rr ResourceRelation) (int64, error) {
@@ -191,6 +223,38 @@ func (dao *resourceRelationDAO) ListDstAggregated(ctx context.Context, modelUid
 	return result, nil
 }
 
+func (dao *resourceRelationDAO) ListRelatedIdsBatch(ctx context.Context, relationName string, isSource bool,
+	ids []int64) ([]ResourceRelatedIds, error) {
+	selfKey, peerKey := "target_resource_id", "source_resource_id"
+	if isSource {
+		selfKey, peerKey = "source_resource_id", "target_resource_id"
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"relation_name": relationName,
+			selfKey:         bson.M{"$in": ids},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + selfKey},
+			{Key: "resource_ids", Value: bson.D{{Key: "$push", Value: "$" + peerKey}}},
+		}}},
+	}
+
+	cursor, err := dao.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("查询错误, %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result []ResourceRelatedIds
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, fmt.Errorf("解码错误: %w", err)
+	}
+	return result, nil
+}
+
 func (dao *resourceRelationDAO) ListSrcRelated(ctx context.Context, modelUid, relationName string, id int64) ([]int64, error) {
 	filter := bson.M{
 		"source_model_uid":   modelUid,
@@ -237,12 +301,7 @@ func (dao *resourceRelationDAO) ListDstRelated(ctx context.Context, modelUid, re
 func (dao *resourceRelationDAO) DeleteResourceRelation(ctx context.Context, id int64) (int64, error) {
 	filter := bson.M{"id": id}
 
-	result, err := dao.coll.DeleteOne(ctx, filter)
-	if err != nil {
-		return 0, fmt.Errorf("删除文档错误: %w", err)
-	}
-
-	return result.DeletedCount, nil
+	return dao.deleteAndRecord(ctx, filter, 1)
 }
 
 func (dao *resourceRelationDAO) DeleteSrcRelation(ctx context.Context, resourceId int64, modelUid, relationName string) (int64, error) {
@@ -252,12 +311,7 @@ func (dao *resourceRelationDAO) DeleteSrcRelation(ctx context.Context, resourceI
 		"relation_name":      relationName,
 	}
 
-	result, err := dao.coll.DeleteOne(ctx, filter)
-	if err != nil {
-		return 0, fmt.Errorf("删除文档错误: %w", err)
-	}
-
-	return result.DeletedCount, nil
+	return dao.deleteAndRecord(ctx, filter, 1)
 }
 
 func (dao *resourceRelationDAO) DeleteDstRelation(ctx context.Context, resourceId int64, modelUid, relationName string) (int64, error) {
@@ -267,12 +321,7 @@ func (dao *resourceRelationDAO) DeleteDstRelation(ctx context.Context, resourceI
 		"relation_name":      relationName,
 	}
 
-	result, err := dao.coll.DeleteOne(ctx, filter)
-	if err != nil {
-		return 0, fmt.Errorf("删除文档错误: %w", err)
-	}
-
-	return result.DeletedCount, nil
+	return dao.deleteAndRecord(ctx, filter, 1)
 }
 
 func (dao *resourceRelationDAO) CountByRelationTypeUid(ctx context.Context, uid string) (int64, error) {
@@ -293,6 +342,90 @@ func (dao *resourceRelationDAO) CountByRelationName(ctx context.Context, name st
 	return count, nil
 }
 
+func (dao *resourceRelationDAO) ListByRelationName(ctx context.Context, name string, limit int64) ([]ResourceRelation, error) {
+	filter := bson.M{"relation_name": name}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "id", Value: 1}},
+	}
+	if limit > 0 {
+		opts.Limit = &limit
+	}
+
+	rrs, err := dao.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("查询资源关联错误: %w", err)
+	}
+	return rrs, nil
+}
+
+func (dao *resourceRelationDAO) DeleteByRelationName(ctx context.Context, name string) (int64, error) {
+	filter := bson.M{"relation_name": name}
+
+	return dao.deleteAndRecord(ctx, filter, 0)
+}
+
+func (dao *resourceRelationDAO) DeleteByRelationTypeUid(ctx context.Context, uid string) (int64, error) {
+	filter := bson.M{"relation_type_uid": uid}
+
+	return dao.deleteAndRecord(ctx, filter, 0)
+}
+
+func (dao *resourceRelationDAO) DeleteByIds(ctx context.Context, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	filter := bson.M{"id": bson.M{"$in": ids}}
+
+	return dao.deleteAndRecord(ctx, filter, 0)
+}
+
+func (dao *resourceRelationDAO) MigrateRelationName(ctx context.Context, ids []int64, relationTypeUid, relationName string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	filter := bson.M{"id": bson.M{"$in": ids}}
+	update := bson.M{
+		"$set": bson.M{
+			"relation_type_uid": relationTypeUid,
+			"relation_name":     relationName,
+			"utime":             time.Now().UnixMilli(),
+		},
+	}
+
+	result, err := dao.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("迁移资源关联错误: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (dao *resourceRelationDAO) ListAfterID(ctx context.Context, afterID int64, limit int64) ([]ResourceRelation, error) {
+	filter := bson.M{"id": bson.M{"$gt": afterID}}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "id", Value: 1}},
+		Limit: &limit,
+	}
+
+	rrs, err := dao.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("查询资源关联错误: %w", err)
+	}
+	return rrs, nil
+}
+
+func (dao *resourceRelationDAO) ListRelationNames(ctx context.Context) ([]string, error) {
+	values, err := dao.coll.Distinct(ctx, "relation_name", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("查询关联名称错误: %w", err)
+	}
+
+	return lo.FilterMap(values, func(v interface{}, _ int) (string, bool) {
+		name, ok := v.(string)
+		return name, ok && name != ""
+	}), nil
+}
+
 func (dao *resourceRelationDAO) ListRecursiveSrc(ctx context.Context, modelUid string, id int64, maxDepth int) ([]ResourceRelation, error) {
 	tenantID := ctxutil.GetTenantID(ctx).Int64()
 
@@ -431,6 +564,12 @@ func (a *ResourceRelation) GetID() int64 {
 	return a.Id
 }
 
+// ResourceRelatedIds 批量聚合查询对端资源返回数据
+type ResourceRelatedIds struct {
+	ResourceId  int64   `bson:"_id"`
+	ResourceIds []int64 `bson:"resource_ids"`
+}
+
 // ResourceAggregatedAsset 聚合查询返回数据
 type ResourceAggregatedAsset struct {
 	RelationName string  `bson:"_id"`
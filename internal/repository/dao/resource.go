@@ -7,6 +7,8 @@ import (
 
 	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	"github.com/gotomicro/ego/core/elog"
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -34,6 +36,9 @@ type ResourceDAO interface {
 	// ListResourcesByIds 根据 ID 列表批量查询资产
 	ListResourcesByIds(ctx context.Context, fields []string, ids []int64) ([]Resource, error)
 
+	// ListFullResourcesByIds 根据 ID 列表批量查询资产的完整数据
+	ListFullResourcesByIds(ctx context.Context, ids []int64) ([]Resource, error)
+
 	// DeleteResource 删除指定资产
 	DeleteResource(ctx context.Context, id int64) (int64, error)
 
@@ -45,12 +50,18 @@ type ResourceDAO interface {
 	TotalExcludeAndFilterResourceByIds(ctx context.Context, modelUid string, ids []int64,
 		filter domain.Condition) (int64, error)
 
-	// Search 全局搜索资产
-	Search(ctx context.Context, text string) ([]SearchResource, error)
+	// SearchResources 根据检索词查询候选资产，按 ID 倒序返回，limit 为候选数量上限
+	SearchResources(ctx context.Context, modelUid string, terms []domain.SearchTerm, limit int64) ([]Resource, error)
+
+	// ListAllAfterID 跨租户按 ID 游标升序读取资产，用于重建检索索引
+	ListAllAfterID(ctx context.Context, afterID int64, limit int64) ([]Resource, error)
 
 	// FindSecureData 查找指定资产的加密字段数据
 	FindSecureData(ctx context.Context, id int64, fieldUid string) (string, error)
 
+	// ListExistingNames 查询模型下已存在的资产名称，用于导入时区分新增与修改
+	ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error)
+
 	// UnsetCustomField 抹除指定模型下所有资产的自定义字段（平铺键）
 	UnsetCustomField(ctx context.Context, modelUid string, fieldUid string) (int64, error)
 
@@ -74,19 +85,60 @@ type ResourceDAO interface {
 	ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64,
 		filterGroups []domain.FilterGroup) ([]Resource, error)
 
+	// ListResourcesByQuery 根据筛选条件及排序字段获取资产列表
+	ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, offset, limit int64) ([]Resource, error)
+
+	// IterateResourcesByQuery 以游标方式按批遍历符合条件的资产，用于大数据量导出
+	IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, batchSize int,
+		fn func(batch []Resource) error) error
+
 	// TotalResourcesWithFilters 根据复杂筛选条件统计资产数量
 	TotalResourcesWithFilters(ctx context.Context, modelUid string, ids []int64, filterGroups []domain.FilterGroup) (int64, error)
+
+	// ListLatestSnapshots 查询指定资产在 asOf 之前最近的一次快照
+	ListLatestSnapshots(ctx context.Context, ids []int64, asOf int64) ([]ResourceSnapshot, error)
+
+	// ListModelSnapshotIDs 查询指定模型在 asOf 之前最近一轮快照的时间及其包含的资产 ID，没有快照时时间为 0
+	ListModelSnapshotIDs(ctx context.Context, modelUid string, asOf int64) (int64, []int64, error)
+
+	// LatestSnapshotTime 跨租户查询 before 之前（含）最近一轮快照的时间，没有快照时返回 0
+	LatestSnapshotTime(ctx context.Context, before int64) (int64, error)
+
+	// EarliestSnapshotTime 跨租户查询最早一轮快照的时间，没有快照时返回 0
+	EarliestSnapshotTime(ctx context.Context) (int64, error)
+
+	// PruneHistory 跨租户删除 before 之前的快照、变更日志与已删除资源关联，返回删除的记录数量
+	PruneHistory(ctx context.Context, before int64) (int64, error)
+
+	// ListChanges 查询时间区间内的资产变更日志，按时间升序排列，并包含区间内的模型级字段抹除
+	ListChanges(ctx context.Context, query ResourceChangeQuery) ([]ResourceChange, error)
+
+	// ListChangesSince 跨租户查询 (since, until] 区间内的资产变更日志，按时间升序排列，limit 为 0 时不限制数量
+	ListChangesSince(ctx context.Context, since, until int64, limit int64) ([]ResourceChange, error)
+
+	// CreateSnapshots 按 ID 游标为一批资产生成快照，返回本批最后一个资产 ID 与快照数量
+	CreateSnapshots(ctx context.Context, afterID int64, limit int64, snapshotTime int64) (int64, int, error)
 }
 
 type resourceDAO struct {
-	db   *mongox.DB
-	coll *mongox.Collection[Resource]
+	db        *mongox.DB
+	coll      *mongox.Collection[Resource]
+	changes   *mongox.Collection[ResourceChange]
+	snapshots *mongox.Collection[ResourceSnapshot]
+	// deletedRelations 已删除的资源关联，与快照、变更日志一同按保留期清理
+	deletedRelations *mongox.Collection[DeletedResourceRelation]
+	logger           *elog.Component
 }
 
 func NewResourceDAO(db *mongox.DB) ResourceDAO {
 	return &resourceDAO{
-		db:   db,
-		coll: mongox.NewCollection[Resource](db, ResourceCollection),
+		db:        db,
+		coll:      mongox.NewCollection[Resource](db, ResourceCollection),
+		changes:   mongox.NewCollection[ResourceChange](db, ResourceChangeCollection),
+		snapshots: mongox.NewCollection[ResourceSnapshot](db, ResourceSnapshotCollection),
+		deletedRelations: mongox.NewCollection[DeletedResourceRelation](db,
+			ResourceRelationDeletedCollection),
+		logger: elog.DefaultLogger,
 	}
 }
 
@@ -123,6 +175,12 @@ func (dao *resourceDAO) BatchUpdateResources(ctx context.Context, resources []Re
 		return 0, fmt.Errorf("批量更新文档操作: %w", err)
 	}
 
+	if result.ModifiedCount > 0 {
+		dao.recordChangesByFilter(ctx, domain.ChangeActionUpdate, bson.M{"id": bson.M{"$in": lo.Map(resources,
+			func(r Resource, _ int) int64 {
+				return r.ID
+			})}})
+	}
 	return result.ModifiedCount, nil
 }
 
@@ -140,6 +198,9 @@ func (dao *resourceDAO) SetCustomField(ctx context.Context, id int64, field stri
 		return 0, fmt.Errorf("修改文档操作: %w", err)
 	}
 
+	if count.ModifiedCount > 0 {
+		dao.recordChangesByFilter(ctx, domain.ChangeActionUpdate, filter)
+	}
 	return count.ModifiedCount, nil
 }
 
@@ -154,6 +215,9 @@ func (dao *resourceDAO) UpdateAttribute(ctx context.Context, resource Resource)
 		return 0, fmt.Errorf("修改文档操作: %w", err)
 	}
 
+	if count.ModifiedCount > 0 {
+		dao.recordChangesByFilter(ctx, domain.ChangeActionUpdate, filter)
+	}
 	return count.ModifiedCount, nil
 }
 
@@ -178,6 +242,22 @@ func (dao *resourceDAO) FindSecureData(ctx context.Context, id int64, fieldUid s
 	return fieldValue, nil
 }
 
+func (dao *resourceDAO) ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	values, err := dao.coll.Distinct(ctx, "name", bson.M{"model_uid": modelUid, "name": bson.M{"$in": names}})
+	if err != nil {
+		return nil, fmt.Errorf("查询已存在资产名称错误: %w", err)
+	}
+
+	return lo.FilterMap(values, func(v interface{}, _ int) (string, bool) {
+		name, ok := v.(string)
+		return name, ok
+	}), nil
+}
+
 func (dao *resourceDAO) CreateResource(ctx context.Context, r Resource) (int64, error) {
 	now := time.Now()
 	r.Ctime, r.Utime = now.UnixMilli(), now.UnixMilli()
@@ -188,6 +268,7 @@ func (dao *resourceDAO) CreateResource(ctx context.Context, r Resource) (int64,
 		return 0, fmt.Errorf("插入数据错误: %w", err)
 	}
 
+	dao.recordChanges(ctx, domain.ChangeActionCreate, []Resource{r})
 	return r.ID, nil
 }
 
@@ -238,13 +319,30 @@ func (dao *resourceDAO) ListResourcesByIds(ctx context.Context, fields []string,
 	return dao.coll.Find(ctx, filter, opts)
 }
 
+func (dao *resourceDAO) ListFullResourcesByIds(ctx context.Context, ids []int64) ([]Resource, error) {
+	return dao.coll.Find(ctx, bson.M{"id": bson.M{"$in": ids}}, &options.FindOptions{
+		Projection: bson.M{"_id": 0},
+	})
+}
+
 func (dao *resourceDAO) DeleteResource(ctx context.Context, id int64) (int64, error) {
 	filter := bson.M{"id": id}
+	// 删除前读取模型标识，供按模型回溯历史时识别已删除的资产
+	deleted, err := dao.coll.Find(ctx, filter, &options.FindOptions{
+		Projection: bson.M{"id": 1, "model_uid": 1},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("查询文档错误: %w", err)
+	}
+
 	result, err := dao.coll.DeleteOne(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("删除文档错误: %w", err)
 	}
 
+	if result.DeletedCount > 0 {
+		dao.recordChanges(ctx, domain.ChangeActionDelete, deleted)
+	}
 	return result.DeletedCount, nil
 }
 
@@ -291,39 +389,32 @@ func (dao *resourceDAO) CountByModelUids(ctx context.Context, modelUids []string
 	return modelCountMap, nil
 }
 
-func (dao *resourceDAO) Search(ctx context.Context, text string) ([]SearchResource, error) {
-	filter := bson.M{"$text": bson.M{"$search": text}}
-
-	groupStage := bson.D{
-		{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$model_uid"},
-			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
-			{Key: "data", Value: bson.D{{Key: "$push", Value: "$$ROOT"}}},
-		}},
-	}
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: filter}},
-		{{Key: "$limit", Value: 1000}}, // NOTE: 极限防御：限制匹配上限，阻断全文检索匹配数万文档触发 16MB 崩溃与内存溢出灾难
-		groupStage,
-		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}}}},
+func (dao *resourceDAO) SearchResources(ctx context.Context, modelUid string, terms []domain.SearchTerm,
+	limit int64) ([]Resource, error) {
+	opts := &options.FindOptions{
+		Projection: bson.M{"_id": 0},
+		Sort:       bson.D{{Key: "id", Value: -1}},
+		Limit:      &limit,
 	}
 
-	cursor, err := dao.coll.Aggregate(ctx, pipeline)
+	rs, err := dao.coll.Find(ctx, buildSearchFilter(modelUid, terms), opts)
 	if err != nil {
-		return nil, fmt.Errorf("查询错误, %w", err)
+		return nil, fmt.Errorf("检索资产错误: %w", err)
 	}
-	defer cursor.Close(ctx)
+	return rs, nil
+}
 
-	var result []SearchResource
-	if err = cursor.All(ctx, &result); err != nil {
-		return nil, fmt.Errorf("解码错误: %w", err)
-	}
-	if err = cursor.Err(); err != nil {
-		return nil, fmt.Errorf("游标遍历错误: %w", err)
+func (dao *resourceDAO) ListAllAfterID(ctx context.Context, afterID int64, limit int64) ([]Resource, error) {
+	rs, err := dao.coll.Find(plugin.IgnoreTenantContext(ctx), bson.M{"id": bson.M{"$gt": afterID}},
+		&options.FindOptions{
+			Projection: bson.M{"_id": 0},
+			Sort:       bson.D{{Key: "id", Value: 1}},
+			Limit:      &limit,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("查询资产错误: %w", err)
 	}
-
-	return result, nil
+	return rs, nil
 }
 
 func (dao *resourceDAO) ListExcludeAndFilterResourceByIds(ctx context.Context, fields []string, modelUid string,
@@ -373,39 +464,54 @@ func (dao *resourceDAO) BatchCreateOrUpdate(ctx context.Context, resources []Res
 		return err
 	}
 
+	dao.recordChangesByFilter(ctx, domain.ChangeActionUpdate, bson.M{"$or": lo.Map(keys,
+		func(key resourceUniqueKey, _ int) interface{} {
+			return key.filter()
+		})})
 	return nil
 }
 
 func (dao *resourceDAO) ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64, filterGroups []domain.FilterGroup) ([]Resource, error) {
-	baseFilter := bson.M{"model_uid": modelUid}
-	if len(ids) > 0 {
-		baseFilter["id"] = bson.M{"$in": ids}
-	}
+	return dao.ListResourcesByQuery(ctx, fields, domain.ResourceQuery{
+		ModelUID:     modelUid,
+		IDs:          ids,
+		FilterGroups: filterGroups,
+	}, offset, limit)
+}
 
-	if len(filterGroups) == 0 {
-		projection := buildProjection(fields)
-		opts := &options.FindOptions{
-			Projection: projection,
-			Limit:      &limit,
-			Skip:       &offset,
-			Sort:       bson.D{{Key: "ctime", Value: -1}},
-		}
-		return dao.coll.Find(ctx, baseFilter, opts)
+func (dao *resourceDAO) ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery,
+	offset, limit int64) ([]Resource, error) {
+	opts := &options.FindOptions{
+		Projection: buildProjection(fields),
+		Limit:      &limit,
+		Skip:       &offset,
+		Sort:       buildSort(query.Sorts),
 	}
 
-	// NOTE: 统一调用内部提炼的 buildFilterConditions 辅助拼装器，消灭 18 行冗余 Duplicate 条件树生成逻辑
-	orConditions := buildFilterConditions(filterGroups)
-	finalFilter := dao.combineFilters(baseFilter, orConditions)
+	return dao.coll.Find(ctx, dao.buildQueryFilter(query), opts)
+}
 
-	projection := buildProjection(fields)
+func (dao *resourceDAO) IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery,
+	batchSize int, fn func(batch []Resource) error) error {
+	// NOTE: 游标遍历无需 Skip，避免深分页随偏移量增长的扫描开销
 	opts := &options.FindOptions{
-		Projection: projection,
-		Limit:      &limit,
-		Skip:       &offset,
-		Sort:       bson.D{{Key: "ctime", Value: -1}},
+		Projection: buildProjection(fields),
+		Sort:       buildSort(query.Sorts),
 	}
 
-	return dao.coll.Find(ctx, finalFilter, opts)
+	return dao.coll.FindEach(ctx, dao.buildQueryFilter(query), batchSize, fn, opts)
+}
+
+// buildQueryFilter 构建 ResourceQuery 对应的查询条件
+func (dao *resourceDAO) buildQueryFilter(query domain.ResourceQuery) interface{} {
+	baseFilter := bson.M{"model_uid": query.ModelUID}
+	if len(query.IDs) > 0 {
+		baseFilter["id"] = bson.M{"$in": query.IDs}
+	}
+
+	// NOTE: 统一调用内部提炼的 buildFilterConditions 辅助拼装器，消灭 18 行冗余 Duplicate 条件树生成逻辑
+	orConditions := buildFilterConditions(query.FilterGroups)
+	return dao.combineFilters(baseFilter, orConditions)
 }
 
 func (dao *resourceDAO) TotalResourcesWithFilters(ctx context.Context, modelUid string, ids []int64, filterGroups []domain.FilterGroup) (int64, error) {
@@ -455,6 +561,9 @@ func (dao *resourceDAO) UnsetCustomField(ctx context.Context, modelUid string, f
 		return 0, fmt.Errorf("批量抹除平铺字段错误: %w", err)
 	}
 
+	if result.ModifiedCount > 0 {
+		dao.recordUnset(ctx, modelUid, []string{fieldUid})
+	}
 	return result.ModifiedCount, nil
 }
 
@@ -462,9 +571,3 @@ type Pipeline struct {
 	ModelUid string `bson:"_id"`
 	Total    int    `bson:"total"`
 }
-
-type SearchResource struct {
-	ModelUid string          `bson:"_id"`
-	Total    int             `bson:"total"`
-	Data     []mongox.MapStr `bson:"data"`
-}
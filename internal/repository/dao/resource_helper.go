@@ -157,6 +157,46 @@ func (dao *resourceDAO) buildExcludeAndFilterBson(modelUid string, ids []int64,
 	return filters
 }
 
+// searchSystemFields 任意字段检索时不参与匹配的系统字段
+var searchSystemFields = []string{"_id", "id", "tenant_id", "model_uid", "ctime", "utime"}
+
+// buildSearchFilter 将检索词转换为查询条件，检索词之间为 AND 关系
+func buildSearchFilter(modelUid string, terms []domain.SearchTerm) bson.M {
+	filter := bson.M{}
+	if modelUid != "" {
+		filter["model_uid"] = modelUid
+	}
+
+	conditions := lo.Map(terms, func(term domain.SearchTerm, _ int) bson.M {
+		if term.Field != "" {
+			return bson.M{term.Field: bson.M{"$regex": primitive.Regex{Pattern: term.Pattern(), Options: "i"}}}
+		}
+
+		// NOTE: 不指定字段时遍历文档全部键值做正则匹配，规避 $text 分词对 IP、主机名的切割
+		// 非标量值转换为字符串失败时按空串处理
+		// $expr 无法使用索引，未指定模型时会扫描整个资产集合，数据量较大时应使用内置检索索引
+		return bson.M{"$expr": bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": bson.M{"$objectToArray": "$$ROOT"},
+				"as":    "kv",
+				"cond":  bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$kv.k", searchSystemFields}}}},
+			}},
+			"as": "kv",
+			"in": bson.M{"$regexMatch": bson.M{
+				"input": bson.M{"$convert": bson.M{
+					"input": "$$kv.v", "to": "string", "onError": "", "onNull": "",
+				}},
+				"regex":   term.Pattern(),
+				"options": "i",
+			}},
+		}}}}}
+	})
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	return filter
+}
+
 // combineFilters 合并基础模型条件与多组 AND/OR 过滤条件，避免查询树组装逻辑冗余
 func (dao *resourceDAO) combineFilters(baseFilter bson.M, orConditions []bson.M) interface{} {
 	if len(orConditions) == 0 {
@@ -213,6 +253,21 @@ func buildBsonCondition(f domain.FilterCondition) bson.M {
 	}
 }
 
+// buildSort 构建排序条件，未指定时按创建时间倒序，并以 ID 兜底保证分页顺序稳定
+func buildSort(sorts []domain.ResourceSort) bson.D {
+	if len(sorts) == 0 {
+		return bson.D{{Key: "ctime", Value: -1}}
+	}
+
+	sort := lo.Map(sorts, func(src domain.ResourceSort, _ int) bson.E {
+		return bson.E{Key: src.Field, Value: lo.Ternary(src.Desc, -1, 1)}
+	})
+	if !lo.ContainsBy(sorts, func(src domain.ResourceSort) bool { return src.Field == "id" }) {
+		sort = append(sort, bson.E{Key: "id", Value: -1})
+	}
+	return sort
+}
+
 func buildProjection(fields []string) map[string]int {
 	// NOTE: 借助 lo.Associate 简化投影初始化，消除显式循环
 	projection := lo.Associate(lo.FilterMap(fields, func(v string, _ int) (string, bool) {
@@ -6,6 +6,7 @@ import (
 	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestBuildExcludeAndFilterBsonIgnoresEmptyFilterName(t *testing.T) {
@@ -39,3 +40,54 @@ func TestBuildProjectionIgnoresEmptyFields(t *testing.T) {
 	assert.Equal(t, 1, projection["ip"])
 	assert.NotContains(t, projection, "")
 }
+
+func TestBuildSearchFilter(t *testing.T) {
+	filter := buildSearchFilter("host", []domain.SearchTerm{
+		{Field: "ip", Value: "10.1.*"},
+		{Value: "web"},
+	})
+
+	assert.Equal(t, "host", filter["model_uid"])
+
+	conditions, ok := filter["$and"].([]bson.M)
+	assert.True(t, ok)
+	assert.Len(t, conditions, 2)
+	assert.Equal(t, bson.M{"ip": bson.M{"$regex": primitive.Regex{Pattern: `^10\.1\..*$`, Options: "i"}}},
+		conditions[0])
+	assert.Contains(t, conditions[1], "$expr")
+}
+
+func TestBuildSearchFilterWithoutModel(t *testing.T) {
+	filter := buildSearchFilter("", nil)
+
+	assert.Empty(t, filter)
+}
+
+func TestBuildSort(t *testing.T) {
+	testCases := []struct {
+		name  string
+		sorts []domain.ResourceSort
+		want  bson.D
+	}{
+		{
+			name: "默认按创建时间倒序",
+			want: bson.D{{Key: "ctime", Value: -1}},
+		},
+		{
+			name:  "追加 id 保证分页稳定",
+			sorts: []domain.ResourceSort{{Field: "name"}, {Field: "utime", Desc: true}},
+			want:  bson.D{{Key: "name", Value: 1}, {Key: "utime", Value: -1}, {Key: "id", Value: -1}},
+		},
+		{
+			name:  "已包含 id 时不重复追加",
+			sorts: []domain.ResourceSort{{Field: "id"}},
+			want:  bson.D{{Key: "id", Value: 1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, buildSort(tc.sorts))
+		})
+	}
+}
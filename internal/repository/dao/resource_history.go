@@ -0,0 +1,282 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ResourceChangeCollection   = "c_resource_changes"
+	ResourceSnapshotCollection = "c_resource_snapshots"
+)
+
+// ResourceChangeQuery 资产变更日志查询条件，ResourceIDs 与 ModelUID 二选一
+type ResourceChangeQuery struct {
+	ResourceIDs []int64
+	ModelUID    string
+	Since       int64
+	Until       int64
+	WithoutData bool // 只判断资产是否存在时不加载变更数据
+}
+
+func (dao *resourceDAO) ListLatestSnapshots(ctx context.Context, ids []int64, asOf int64) ([]ResourceSnapshot, error) {
+	if len(ids) == 0 {
+		return []ResourceSnapshot{}, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"resource_id":   bson.M{"$in": ids},
+			"snapshot_time": bson.M{"$lte": asOf},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "snapshot_time", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$resource_id",
+			"snapshot": bson.M{"$first": "$$ROOT"},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$snapshot"}}},
+	}
+
+	cursor, err := dao.snapshots.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("查询资产快照错误: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	result := make([]ResourceSnapshot, 0, len(ids))
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, fmt.Errorf("资产快照解码错误: %w", err)
+	}
+	return result, nil
+}
+
+func (dao *resourceDAO) ListModelSnapshotIDs(ctx context.Context, modelUid string, asOf int64) (int64, []int64, error) {
+	filter := bson.M{
+		"model_uid":     modelUid,
+		"snapshot_time": bson.M{"$lte": asOf},
+	}
+	latest, err := dao.snapshots.FindOne(ctx, filter, &options.FindOneOptions{
+		Sort:       bson.D{{Key: "snapshot_time", Value: -1}},
+		Projection: bson.M{"snapshot_time": 1},
+	})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return 0, []int64{}, nil
+		}
+		return 0, nil, fmt.Errorf("查询资产快照错误: %w", err)
+	}
+
+	// NOTE: 每轮快照覆盖当时存在的全部资产，取最近一轮即可作为模型的完整基线
+	snapshots, err := dao.snapshots.Find(ctx, bson.M{
+		"model_uid":     modelUid,
+		"snapshot_time": latest.SnapshotTime,
+	}, &options.FindOptions{
+		Projection: bson.M{"resource_id": 1},
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("查询资产快照错误: %w", err)
+	}
+	return latest.SnapshotTime, lo.Map(snapshots, func(src ResourceSnapshot, _ int) int64 {
+		return src.ResourceID
+	}), nil
+}
+
+func (dao *resourceDAO) LatestSnapshotTime(ctx context.Context, before int64) (int64, error) {
+	latest, err := dao.snapshots.FindOne(plugin.IgnoreTenantContext(ctx), bson.M{
+		"snapshot_time": bson.M{"$lte": before},
+	}, &options.FindOneOptions{
+		Sort:       bson.D{{Key: "snapshot_time", Value: -1}},
+		Projection: bson.M{"snapshot_time": 1},
+	})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("查询资产快照错误: %w", err)
+	}
+	return latest.SnapshotTime, nil
+}
+
+func (dao *resourceDAO) EarliestSnapshotTime(ctx context.Context) (int64, error) {
+	earliest, err := dao.snapshots.FindOne(plugin.IgnoreTenantContext(ctx), bson.M{}, &options.FindOneOptions{
+		Sort:       bson.D{{Key: "snapshot_time", Value: 1}},
+		Projection: bson.M{"snapshot_time": 1},
+	})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("查询资产快照错误: %w", err)
+	}
+	return earliest.SnapshotTime, nil
+}
+
+func (dao *resourceDAO) PruneHistory(ctx context.Context, before int64) (int64, error) {
+	// NOTE: 历史数据由后台任务统一清理，需要跨租户删除
+	ctx = plugin.IgnoreTenantContext(ctx)
+
+	snapshots, err := dao.snapshots.DeleteMany(ctx, bson.M{"snapshot_time": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("清理资产快照错误: %w", err)
+	}
+	changes, err := dao.changes.DeleteMany(ctx, bson.M{"ctime": bson.M{"$lt": before}})
+	if err != nil {
+		return snapshots.DeletedCount, fmt.Errorf("清理资产变更日志错误: %w", err)
+	}
+	relations, err := dao.deletedRelations.DeleteMany(ctx, bson.M{"dtime": bson.M{"$lt": before}})
+	if err != nil {
+		return snapshots.DeletedCount + changes.DeletedCount, fmt.Errorf("清理已删除资源关联错误: %w", err)
+	}
+	return snapshots.DeletedCount + changes.DeletedCount + relations.DeletedCount, nil
+}
+
+func (dao *resourceDAO) ListChanges(ctx context.Context, query ResourceChangeQuery) ([]ResourceChange, error) {
+	filter := bson.M{
+		"ctime": bson.M{"$gte": query.Since, "$lte": query.Until},
+	}
+	if query.ModelUID != "" {
+		filter["model_uid"] = query.ModelUID
+	} else {
+		filter["$or"] = []bson.M{
+			{"resource_id": bson.M{"$in": query.ResourceIDs}},
+			{"action": string(domain.ChangeActionUnset)},
+		}
+	}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "ctime", Value: 1}},
+	}
+	if query.WithoutData {
+		opts.Projection = bson.M{"data": 0}
+	}
+
+	changes, err := dao.changes.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("查询资产变更日志错误: %w", err)
+	}
+	return changes, nil
+}
+
+func (dao *resourceDAO) ListChangesSince(ctx context.Context, since, until int64, limit int64) ([]ResourceChange, error) {
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "ctime", Value: 1}, {Key: "_id", Value: 1}},
+	}
+	if limit > 0 {
+		opts.Limit = &limit
+	}
+
+	changes, err := dao.changes.Find(plugin.IgnoreTenantContext(ctx), bson.M{
+		"ctime": bson.M{"$gt": since, "$lte": until},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("查询资产变更日志错误: %w", err)
+	}
+	return changes, nil
+}
+
+func (dao *resourceDAO) CreateSnapshots(ctx context.Context, afterID int64, limit int64, snapshotTime int64) (int64, int, error) {
+	// NOTE: 快照由后台任务统一生成，需要跨租户扫描并保留资产原有的租户归属
+	ctx = plugin.IgnoreTenantContext(ctx)
+	rs, err := dao.coll.Find(ctx, bson.M{"id": bson.M{"$gt": afterID}}, &options.FindOptions{
+		Sort:  bson.D{{Key: "id", Value: 1}},
+		Limit: &limit,
+	})
+	if err != nil {
+		return afterID, 0, fmt.Errorf("查询资产错误: %w", err)
+	}
+	if len(rs) == 0 {
+		return afterID, 0, nil
+	}
+
+	docs := lo.Map(rs, func(r Resource, _ int) *ResourceSnapshot {
+		return &ResourceSnapshot{
+			TenantID:     r.TenantID,
+			ResourceID:   r.ID,
+			ModelUID:     r.ModelUID,
+			Data:         historyData(r.Data),
+			SnapshotTime: snapshotTime,
+		}
+	})
+	if _, err = dao.snapshots.InsertMany(ctx, docs); err != nil {
+		return afterID, 0, fmt.Errorf("写入资产快照错误: %w", err)
+	}
+
+	return rs[len(rs)-1].ID, len(rs), nil
+}
+
+// recordChanges 记录资产变更日志
+// NOTE: 写入失败不影响主流程，历史回溯在下一轮周期快照时重新校准，检索索引由周期校准任务补齐
+func (dao *resourceDAO) recordChanges(ctx context.Context, action domain.ChangeAction, rs []Resource) {
+	if len(rs) == 0 {
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	docs := lo.Map(rs, func(r Resource, _ int) *ResourceChange {
+		return &ResourceChange{
+			ResourceID: r.ID,
+			ModelUID:   r.ModelUID,
+			Action:     string(action),
+			Data:       historyData(r.Data),
+			Ctime:      now,
+		}
+	})
+	if _, err := dao.changes.InsertMany(ctx, docs); err != nil {
+		dao.logger.Error("记录资产变更日志失败", elog.FieldErr(err), elog.String("action", string(action)))
+	}
+}
+
+// recordChangesByFilter 重新读取变更后的完整资产并记录变更日志
+func (dao *resourceDAO) recordChangesByFilter(ctx context.Context, action domain.ChangeAction, filter bson.M) {
+	rs, err := dao.coll.Find(ctx, filter)
+	if err != nil {
+		dao.logger.Error("读取变更后的资产失败", elog.FieldErr(err), elog.String("action", string(action)))
+		return
+	}
+	dao.recordChanges(ctx, action, rs)
+}
+
+// recordUnset 记录模型级字段抹除
+func (dao *resourceDAO) recordUnset(ctx context.Context, modelUid string, fields []string) {
+	doc := &ResourceChange{
+		ModelUID: modelUid,
+		Action:   string(domain.ChangeActionUnset),
+		Fields:   fields,
+		Ctime:    time.Now().UnixMilli(),
+	}
+	if _, err := dao.changes.InsertOne(ctx, doc); err != nil {
+		dao.logger.Error("记录资产变更日志失败", elog.FieldErr(err), elog.String("action", doc.Action))
+	}
+}
+
+// historyData 剔除数据库内部字段，只保留资产属性
+func historyData(data mongox.MapStr) mongox.MapStr {
+	return lo.OmitByKeys(data, []string{"_id"})
+}
+
+type ResourceChange struct {
+	TenantID   int64         `bson:"tenant_id"`
+	ResourceID int64         `bson:"resource_id"`
+	ModelUID   string        `bson:"model_uid"`
+	Action     string        `bson:"action"`
+	Data       mongox.MapStr `bson:"data,omitempty"`
+	Fields     []string      `bson:"fields,omitempty"`
+	Ctime      int64         `bson:"ctime"`
+}
+
+type ResourceSnapshot struct {
+	TenantID     int64         `bson:"tenant_id"`
+	ResourceID   int64         `bson:"resource_id"`
+	ModelUID     string        `bson:"model_uid"`
+	Data         mongox.MapStr `bson:"data"`
+	SnapshotTime int64         `bson:"snapshot_time"`
+}
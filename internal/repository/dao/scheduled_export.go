@@ -0,0 +1,352 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ScheduledExportCollection    = "c_scheduled_export"
+	ScheduledExportRunCollection = "c_scheduled_export_run"
+)
+
+type ScheduledExportDAO interface {
+	// Create 创建定时导出
+	Create(ctx context.Context, se ScheduledExport) (int64, error)
+
+	// Update 修改定时导出配置及下一次执行时间
+	Update(ctx context.Context, se ScheduledExport) (int64, error)
+
+	// Delete 删除定时导出
+	Delete(ctx context.Context, id int64) (int64, error)
+
+	// FindById 根据 ID 查询定时导出
+	FindById(ctx context.Context, id int64) (ScheduledExport, error)
+
+	// List 按创建时间倒序查询定时导出
+	List(ctx context.Context, offset, limit int64) ([]ScheduledExport, error)
+
+	// Count 统计定时导出数量
+	Count(ctx context.Context) (int64, error)
+
+	// ListDue 跨租户查询已到执行时间的定时导出
+	ListDue(ctx context.Context, now int64, limit int64) ([]ScheduledExport, error)
+
+	// Advance 将下一次执行时间由 from 推进到 next，返回 false 表示该次执行已被领取或配置已修改
+	Advance(ctx context.Context, id int64, from, next int64) (bool, error)
+
+	// CreateRun 创建执行记录
+	CreateRun(ctx context.Context, run ScheduledExportRun) (int64, error)
+
+	// UpdateRun 更新执行记录的文件及状态，同时刷新 utime 作为心跳
+	UpdateRun(ctx context.Context, run ScheduledExportRun) error
+
+	// FindRunById 根据 ID 查询执行记录
+	FindRunById(ctx context.Context, id int64) (ScheduledExportRun, error)
+
+	// ListRuns 按创建时间倒序查询定时导出的执行记录
+	ListRuns(ctx context.Context, scheduleId int64, offset, limit int64) ([]ScheduledExportRun, error)
+
+	// CountRuns 统计定时导出的执行记录数量
+	CountRuns(ctx context.Context, scheduleId int64) (int64, error)
+
+	// ListExpiredRuns 查询早于 before 且尚未清理文件的执行记录
+	ListExpiredRuns(ctx context.Context, scheduleId int64, before int64) ([]ScheduledExportRun, error)
+
+	// MarkRunsPruned 标记执行记录的导出文件已清理
+	MarkRunsPruned(ctx context.Context, ids []int64) error
+
+	// FailStaleRuns 跨租户将心跳超时的执行记录标记为失败，返回处理数量
+	FailStaleRuns(ctx context.Context, before int64, message string) (int64, error)
+}
+
+func NewScheduledExportDAO(db *mongox.DB) ScheduledExportDAO {
+	return &scheduledExportDAO{
+		coll:    mongox.NewCollection[ScheduledExport](db, ScheduledExportCollection),
+		runColl: mongox.NewCollection[ScheduledExportRun](db, ScheduledExportRunCollection),
+	}
+}
+
+type scheduledExportDAO struct {
+	coll    *mongox.Collection[ScheduledExport]
+	runColl *mongox.Collection[ScheduledExportRun]
+}
+
+func (dao *scheduledExportDAO) Create(ctx context.Context, se ScheduledExport) (int64, error) {
+	now := time.Now().UnixMilli()
+	se.Ctime, se.Utime = now, now
+
+	if _, err := dao.coll.InsertOne(ctx, &se); err != nil {
+		if mongox.IsUniqueConstraintError(err) {
+			return 0, fmt.Errorf("定时导出插入: %w", errs.ErrUniqueDuplicate)
+		}
+		return 0, fmt.Errorf("插入数据错误: %w", err)
+	}
+
+	return se.Id, nil
+}
+
+func (dao *scheduledExportDAO) Update(ctx context.Context, se ScheduledExport) (int64, error) {
+	result, err := dao.coll.UpdateOne(ctx, bson.M{"id": se.Id}, bson.M{
+		"$set": bson.M{
+			"name":           se.Name,
+			"cron_expr":      se.CronExpr,
+			"models":         se.Models,
+			"format":         se.Format,
+			"delimiter":      se.Delimiter,
+			"encoding":       se.Encoding,
+			"retention_days": se.RetentionDays,
+			"enabled":        se.Enabled,
+			"next_run_at":    se.NextRunAt,
+			"utime":          time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		if mongox.IsUniqueConstraintError(err) {
+			return 0, fmt.Errorf("定时导出修改: %w", errs.ErrUniqueDuplicate)
+		}
+		return 0, fmt.Errorf("修改文档操作: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (dao *scheduledExportDAO) Delete(ctx context.Context, id int64) (int64, error) {
+	result, err := dao.coll.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return 0, fmt.Errorf("删除文档错误: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (dao *scheduledExportDAO) FindById(ctx context.Context, id int64) (ScheduledExport, error) {
+	se, err := dao.coll.FindOne(ctx, bson.M{"id": id})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return ScheduledExport{}, fmt.Errorf("定时导出查询: %w", errs.ErrNotFound)
+		}
+		return ScheduledExport{}, fmt.Errorf("解码错误: %w", err)
+	}
+
+	return *se, nil
+}
+
+func (dao *scheduledExportDAO) List(ctx context.Context, offset, limit int64) ([]ScheduledExport, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: -1}},
+		Skip:  &offset,
+		Limit: &limit,
+	}
+
+	return dao.coll.Find(ctx, bson.M{}, opts)
+}
+
+func (dao *scheduledExportDAO) Count(ctx context.Context) (int64, error) {
+	count, err := dao.coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("文档计数错误: %w", err)
+	}
+
+	return count, nil
+}
+
+func (dao *scheduledExportDAO) ListDue(ctx context.Context, now int64, limit int64) ([]ScheduledExport, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "next_run_at", Value: 1}},
+		Limit: &limit,
+	}
+
+	return dao.coll.Find(plugin.IgnoreTenantContext(ctx), bson.M{
+		"enabled":     true,
+		"next_run_at": bson.M{"$lte": now},
+	}, opts)
+}
+
+func (dao *scheduledExportDAO) Advance(ctx context.Context, id int64, from, next int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	result, err := dao.coll.UpdateOne(ctx, bson.M{"id": id, "next_run_at": from}, bson.M{
+		"$set": bson.M{
+			"next_run_at": next,
+			"last_run_at": now,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("推进定时导出执行时间错误: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (dao *scheduledExportDAO) CreateRun(ctx context.Context, run ScheduledExportRun) (int64, error) {
+	now := time.Now().UnixMilli()
+	run.Ctime, run.Utime = now, now
+
+	if _, err := dao.runColl.InsertOne(ctx, &run); err != nil {
+		return 0, fmt.Errorf("插入数据错误: %w", err)
+	}
+
+	return run.Id, nil
+}
+
+func (dao *scheduledExportDAO) UpdateRun(ctx context.Context, run ScheduledExportRun) error {
+	now := time.Now().UnixMilli()
+	set := bson.M{
+		"status":  run.Status,
+		"files":   run.Files,
+		"message": run.Message,
+		"utime":   now,
+	}
+	if run.Status != "running" {
+		set["ftime"] = now
+	}
+
+	if _, err := dao.runColl.UpdateOne(ctx, bson.M{"id": run.Id}, bson.M{"$set": set}); err != nil {
+		return fmt.Errorf("更新定时导出执行记录错误: %w", err)
+	}
+
+	return nil
+}
+
+func (dao *scheduledExportDAO) FindRunById(ctx context.Context, id int64) (ScheduledExportRun, error) {
+	run, err := dao.runColl.FindOne(ctx, bson.M{"id": id})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return ScheduledExportRun{}, fmt.Errorf("定时导出执行记录查询: %w", errs.ErrNotFound)
+		}
+		return ScheduledExportRun{}, fmt.Errorf("解码错误: %w", err)
+	}
+
+	return *run, nil
+}
+
+func (dao *scheduledExportDAO) ListRuns(ctx context.Context, scheduleId int64, offset, limit int64) (
+	[]ScheduledExportRun, error) {
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "ctime", Value: -1}},
+		Skip:  &offset,
+		Limit: &limit,
+	}
+
+	return dao.runColl.Find(ctx, bson.M{"schedule_id": scheduleId}, opts)
+}
+
+func (dao *scheduledExportDAO) CountRuns(ctx context.Context, scheduleId int64) (int64, error) {
+	count, err := dao.runColl.CountDocuments(ctx, bson.M{"schedule_id": scheduleId})
+	if err != nil {
+		return 0, fmt.Errorf("文档计数错误: %w", err)
+	}
+
+	return count, nil
+}
+
+func (dao *scheduledExportDAO) ListExpiredRuns(ctx context.Context, scheduleId int64, before int64) (
+	[]ScheduledExportRun, error) {
+	return dao.runColl.Find(ctx, bson.M{
+		"schedule_id": scheduleId,
+		"pruned":      false,
+		"status":      bson.M{"$ne": "running"},
+		"ctime":       bson.M{"$lt": before},
+	})
+}
+
+func (dao *scheduledExportDAO) MarkRunsPruned(ctx context.Context, ids []int64) error {
+	_, err := dao.runColl.UpdateMany(ctx, bson.M{"id": bson.M{"$in": ids}}, bson.M{
+		"$set": bson.M{
+			"pruned": true,
+			"utime":  time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("标记执行记录已清理错误: %w", err)
+	}
+
+	return nil
+}
+
+func (dao *scheduledExportDAO) FailStaleRuns(ctx context.Context, before int64, message string) (int64, error) {
+	now := time.Now().UnixMilli()
+	result, err := dao.runColl.UpdateMany(plugin.IgnoreTenantContext(ctx),
+		bson.M{"status": "running", "utime": bson.M{"$lt": before}},
+		bson.M{"$set": bson.M{
+			"status":  "failed",
+			"message": message,
+			"utime":   now,
+			"ftime":   now,
+		}})
+	if err != nil {
+		return 0, fmt.Errorf("处理超时定时导出错误: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ScheduledExport 定时导出配置，筛选条件与保存视图使用相同的存储结构
+type ScheduledExport struct {
+	TenantID      int64                  `bson:"tenant_id"`
+	Id            int64                  `bson:"id"`
+	Name          string                 `bson:"name"`
+	CronExpr      string                 `bson:"cron_expr"`
+	Models        []ScheduledExportModel `bson:"models"`
+	Format        string                 `bson:"format"`
+	Delimiter     string                 `bson:"delimiter"`
+	Encoding      string                 `bson:"encoding"`
+	RetentionDays int                    `bson:"retention_days"`
+	Enabled       bool                   `bson:"enabled"`
+	NextRunAt     int64                  `bson:"next_run_at"`
+	LastRunAt     int64                  `bson:"last_run_at"`
+	CreatorID     int64                  `bson:"creator_id"`
+	Ctime         int64                  `bson:"ctime"`
+	Utime         int64                  `bson:"utime"`
+}
+
+func (s *ScheduledExport) SetID(id int64) {
+	s.Id = id
+}
+
+func (s *ScheduledExport) GetID() int64 {
+	return s.Id
+}
+
+type ScheduledExportModel struct {
+	ModelUID     string                 `bson:"model_uid"`
+	FilterGroups []SavedViewFilterGroup `bson:"filter_groups"`
+	Fields       []string               `bson:"fields"`
+}
+
+// ScheduledExportRun 定时导出执行记录
+type ScheduledExportRun struct {
+	TenantID   int64                 `bson:"tenant_id"`
+	Id         int64                 `bson:"id"`
+	ScheduleId int64                 `bson:"schedule_id"`
+	Status     string                `bson:"status"`
+	Files      []ScheduledExportFile `bson:"files"`
+	Message    string                `bson:"message"`
+	Pruned     bool                  `bson:"pruned"`
+	Ctime      int64                 `bson:"ctime"`
+	Utime      int64                 `bson:"utime"`
+	Ftime      int64                 `bson:"ftime"`
+}
+
+func (r *ScheduledExportRun) SetID(id int64) {
+	r.Id = id
+}
+
+func (r *ScheduledExportRun) GetID() int64 {
+	return r.Id
+}
+
+type ScheduledExportFile struct {
+	ModelUID string `bson:"model_uid"`
+	FileKey  string `bson:"file_key"`
+	Rows     int    `bson:"rows"`
+	Size     int64  `bson:"size"`
+	Message  string `bson:"message"`
+}
@@ -0,0 +1,217 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SavedViewCollection        = "c_saved_view"
+	SavedViewDefaultCollection = "c_saved_view_default"
+)
+
+type SavedViewDAO interface {
+	// Create 创建视图
+	Create(ctx context.Context, view SavedView) (int64, error)
+
+	// Update 修改视图，只允许创建人修改
+	Update(ctx context.Context, view SavedView) (int64, error)
+
+	// Delete 删除视图，只允许创建人删除，同时清理引用该视图的默认设置
+	Delete(ctx context.Context, id int64, ownerID int64) (int64, error)
+
+	// FindById 根据 ID 查询视图
+	FindById(ctx context.Context, id int64) (SavedView, error)
+
+	// ListVisible 查询模型下用户可见的视图：本人创建的视图及共享视图
+	ListVisible(ctx context.Context, modelUid string, userID int64) ([]SavedView, error)
+
+	// SetDefault 设置用户在模型下的默认视图
+	SetDefault(ctx context.Context, userID int64, modelUid string, viewID int64) error
+
+	// ClearDefault 取消用户在模型下的默认视图
+	ClearDefault(ctx context.Context, userID int64, modelUid string) (int64, error)
+
+	// FindDefault 查询用户在模型下的默认视图 ID，未设置时返回 0
+	FindDefault(ctx context.Context, userID int64, modelUid string) (int64, error)
+}
+
+func NewSavedViewDAO(db *mongox.DB) SavedViewDAO {
+	return &savedViewDAO{
+		coll:     mongox.NewCollection[SavedView](db, SavedViewCollection),
+		defaults: mongox.NewCollection[SavedViewDefault](db, SavedViewDefaultCollection),
+	}
+}
+
+type savedViewDAO struct {
+	coll     *mongox.Collection[SavedView]
+	defaults *mongox.Collection[SavedViewDefault]
+}
+
+func (dao *savedViewDAO) Create(ctx context.Context, view SavedView) (int64, error) {
+	now := time.Now().UnixMilli()
+	view.Ctime, view.Utime = now, now
+
+	if _, err := dao.coll.InsertOne(ctx, &view); err != nil {
+		if mongox.IsUniqueConstraintError(err) {
+			return 0, fmt.Errorf("视图插入: %w", errs.ErrUniqueDuplicate)
+		}
+		return 0, fmt.Errorf("插入数据错误: %w", err)
+	}
+
+	return view.Id, nil
+}
+
+func (dao *savedViewDAO) Update(ctx context.Context, view SavedView) (int64, error) {
+	filter := bson.M{"id": view.Id, "owner_id": view.OwnerID}
+	update := bson.M{
+		"$set": bson.M{
+			"name":          view.Name,
+			"scope":         view.Scope,
+			"filter_groups": view.FilterGroups,
+			"fields":        view.Fields,
+			"sorts":         view.Sorts,
+			"utime":         time.Now().UnixMilli(),
+		},
+	}
+
+	result, err := dao.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongox.IsUniqueConstraintError(err) {
+			return 0, fmt.Errorf("视图修改: %w", errs.ErrUniqueDuplicate)
+		}
+		return 0, fmt.Errorf("修改文档操作: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (dao *savedViewDAO) Delete(ctx context.Context, id int64, ownerID int64) (int64, error) {
+	result, err := dao.coll.DeleteOne(ctx, bson.M{"id": id, "owner_id": ownerID})
+	if err != nil {
+		return 0, fmt.Errorf("删除文档错误: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return 0, nil
+	}
+
+	// NOTE: 共享视图可能被其他用户设置为默认视图，需要一并清理
+	if _, err = dao.defaults.DeleteMany(ctx, bson.M{"view_id": id}); err != nil {
+		return result.DeletedCount, fmt.Errorf("清理默认视图错误: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (dao *savedViewDAO) FindById(ctx context.Context, id int64) (SavedView, error) {
+	view, err := dao.coll.FindOne(ctx, bson.M{"id": id})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return SavedView{}, fmt.Errorf("视图查询: %w", errs.ErrNotFound)
+		}
+		return SavedView{}, fmt.Errorf("解码错误: %w", err)
+	}
+
+	return *view, nil
+}
+
+func (dao *savedViewDAO) ListVisible(ctx context.Context, modelUid string, userID int64) ([]SavedView, error) {
+	filter := bson.M{
+		"model_uid": modelUid,
+		"$or": []bson.M{
+			{"owner_id": userID},
+			{"scope": "shared"},
+		},
+	}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "ctime", Value: 1}},
+	}
+
+	return dao.coll.Find(ctx, filter, opts)
+}
+
+func (dao *savedViewDAO) SetDefault(ctx context.Context, userID int64, modelUid string, viewID int64) error {
+	filter := bson.M{"user_id": userID, "model_uid": modelUid}
+	update := bson.M{
+		"$set": bson.M{
+			"view_id": viewID,
+			"utime":   time.Now().UnixMilli(),
+		},
+	}
+
+	if _, err := dao.defaults.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("设置默认视图错误: %w", err)
+	}
+	return nil
+}
+
+func (dao *savedViewDAO) ClearDefault(ctx context.Context, userID int64, modelUid string) (int64, error) {
+	result, err := dao.defaults.DeleteOne(ctx, bson.M{"user_id": userID, "model_uid": modelUid})
+	if err != nil {
+		return 0, fmt.Errorf("取消默认视图错误: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+func (dao *savedViewDAO) FindDefault(ctx context.Context, userID int64, modelUid string) (int64, error) {
+	def, err := dao.defaults.FindOne(ctx, bson.M{"user_id": userID, "model_uid": modelUid})
+	if err != nil {
+		if mongox.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("查询默认视图错误: %w", err)
+	}
+	return def.ViewID, nil
+}
+
+type SavedView struct {
+	TenantID     int64                  `bson:"tenant_id"`
+	Id           int64                  `bson:"id"`
+	ModelUID     string                 `bson:"model_uid"`
+	Name         string                 `bson:"name"`
+	OwnerID      int64                  `bson:"owner_id"`
+	Scope        string                 `bson:"scope"`
+	FilterGroups []SavedViewFilterGroup `bson:"filter_groups"`
+	Fields       []string               `bson:"fields"`
+	Sorts        []SavedViewSort        `bson:"sorts"`
+	Ctime        int64                  `bson:"ctime"`
+	Utime        int64                  `bson:"utime"`
+}
+
+func (v *SavedView) SetID(id int64) {
+	v.Id = id
+}
+
+func (v *SavedView) GetID() int64 {
+	return v.Id
+}
+
+type SavedViewFilterGroup struct {
+	Filters []SavedViewFilter `bson:"filters"`
+}
+
+type SavedViewFilter struct {
+	FieldUID string      `bson:"field_uid"`
+	Operator string      `bson:"operator"`
+	Value    interface{} `bson:"value"`
+}
+
+type SavedViewSort struct {
+	Field string `bson:"field"`
+	Desc  bool   `bson:"desc"`
+}
+
+// SavedViewDefault 用户在模型下的默认视图
+type SavedViewDefault struct {
+	TenantID int64  `bson:"tenant_id"`
+	UserID   int64  `bson:"user_id"`
+	ModelUID string `bson:"model_uid"`
+	ViewID   int64  `bson:"view_id"`
+	Utime    int64  `bson:"utime"`
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type ExportJobRepository interface {
+	// CreateExportJob 创建导出任务
+	CreateExportJob(ctx context.Context, job domain.ExportJob) (int64, error)
+
+	// FindExportJobById 根据 ID 查询导出任务
+	FindExportJobById(ctx context.Context, id int64) (domain.ExportJob, error)
+
+	// ListExportJobs 查询模型下的导出任务，不包含导出条件明细
+	ListExportJobs(ctx context.Context, modelUid string, offset, limit int64) ([]domain.ExportJob, error)
+
+	// TotalExportJobs 统计模型下的导出任务数量
+	TotalExportJobs(ctx context.Context, modelUid string) (int64, error)
+
+	// ListPendingExportJobs 跨租户查询待执行的导出任务
+	ListPendingExportJobs(ctx context.Context, limit int64) ([]domain.ExportJob, error)
+
+	// ClaimExportJob 领取待执行的导出任务
+	ClaimExportJob(ctx context.Context, id int64) (bool, error)
+
+	// UpdateExportJobProgress 更新导出进度
+	UpdateExportJobProgress(ctx context.Context, job domain.ExportJob) error
+
+	// FinishExportJob 记录导出结果
+	FinishExportJob(ctx context.Context, job domain.ExportJob) error
+
+	// FailStaleExportJobs 将心跳超时的执行中任务标记为失败
+	FailStaleExportJobs(ctx context.Context, before int64, message string) (int64, error)
+}
+
+func NewExportJobRepository(dao dao.ExportJobDAO) ExportJobRepository {
+	return &exportJobRepository{
+		dao: dao,
+	}
+}
+
+type exportJobRepository struct {
+	dao dao.ExportJobDAO
+}
+
+func (repo *exportJobRepository) CreateExportJob(ctx context.Context, job domain.ExportJob) (int64, error) {
+	return repo.dao.Create(ctx, repo.toEntity(job))
+}
+
+func (repo *exportJobRepository) FindExportJobById(ctx context.Context, id int64) (domain.ExportJob, error) {
+	job, err := repo.dao.FindById(ctx, id)
+	return repo.toDomain(job), err
+}
+
+func (repo *exportJobRepository) ListExportJobs(ctx context.Context, modelUid string,
+	offset, limit int64) ([]domain.ExportJob, error) {
+	jobs, err := repo.dao.ListByModelUid(ctx, modelUid, offset, limit)
+	return slice.Map(jobs, func(idx int, src dao.ExportJob) domain.ExportJob {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *exportJobRepository) TotalExportJobs(ctx context.Context, modelUid string) (int64, error) {
+	return repo.dao.CountByModelUid(ctx, modelUid)
+}
+
+func (repo *exportJobRepository) ListPendingExportJobs(ctx context.Context, limit int64) ([]domain.ExportJob, error) {
+	jobs, err := repo.dao.ListPending(ctx, limit)
+	return slice.Map(jobs, func(idx int, src dao.ExportJob) domain.ExportJob {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *exportJobRepository) ClaimExportJob(ctx context.Context, id int64) (bool, error) {
+	return repo.dao.Claim(ctx, id)
+}
+
+func (repo *exportJobRepository) UpdateExportJobProgress(ctx context.Context, job domain.ExportJob) error {
+	return repo.dao.UpdateProgress(ctx, repo.toEntity(job))
+}
+
+func (repo *exportJobRepository) FinishExportJob(ctx context.Context, job domain.ExportJob) error {
+	return repo.dao.Finish(ctx, repo.toEntity(job))
+}
+
+func (repo *exportJobRepository) FailStaleExportJobs(ctx context.Context, before int64, message string) (int64, error) {
+	return repo.dao.FailStale(ctx, before, message)
+}
+
+func (repo *exportJobRepository) toEntity(req domain.ExportJob) dao.ExportJob {
+	return dao.ExportJob{
+		Id:           req.ID,
+		ModelUID:     req.ModelUID,
+		ResourceIDs:  req.ResourceIDs,
+		FilterGroups: toFilterGroupEntities(req.FilterGroups),
+		Fields:       req.Fields,
+		Sorts:        toSortEntities(req.Sorts),
+		Relations: slice.Map(req.Relations, func(idx int, src domain.ExportRelation) dao.ExportRelation {
+			return dao.ExportRelation{
+				RelationName: src.RelationName,
+				Fields:       src.Fields,
+				Mode:         src.Mode,
+				Separator:    src.Separator,
+			}
+		}),
+		FileName:  req.FileName,
+		Format:    string(req.Format),
+		Delimiter: req.Delimiter,
+		Encoding:  req.Encoding,
+		Status:    string(req.Status),
+		Processed: req.Processed,
+		Rows:      req.Rows,
+		FileKey:   req.FileKey,
+		Size:      req.Size,
+		Message:   req.Message,
+		CreatorID: req.CreatorID,
+	}
+}
+
+func (repo *exportJobRepository) toDomain(src dao.ExportJob) domain.ExportJob {
+	return domain.ExportJob{
+		ID:           src.Id,
+		TenantID:     src.TenantID,
+		ModelUID:     src.ModelUID,
+		ResourceIDs:  src.ResourceIDs,
+		FilterGroups: toFilterGroupDomains(src.FilterGroups),
+		Fields:       src.Fields,
+		Sorts:        toSortDomains(src.Sorts),
+		Relations: slice.Map(src.Relations, func(idx int, src dao.ExportRelation) domain.ExportRelation {
+			return domain.ExportRelation{
+				RelationName: src.RelationName,
+				Fields:       src.Fields,
+				Mode:         src.Mode,
+				Separator:    src.Separator,
+			}
+		}),
+		FileName: src.FileName,
+		FormatOptions: domain.FormatOptions{
+			Format:    domain.FileFormat(src.Format),
+			Delimiter: src.Delimiter,
+			Encoding:  src.Encoding,
+		},
+		Status:    domain.ExportJobStatus(src.Status),
+		Processed: src.Processed,
+		Rows:      src.Rows,
+		FileKey:   src.FileKey,
+		Size:      src.Size,
+		Message:   src.Message,
+		CreatorID: src.CreatorID,
+		Ctime:     src.Ctime,
+		Utime:     src.Utime,
+		Ftime:     src.Ftime,
+	}
+}
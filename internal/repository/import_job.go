@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type ImportJobRepository interface {
+	// CreateImportJob 创建导入任务
+	CreateImportJob(ctx context.Context, job domain.ImportJob) (int64, error)
+
+	// FindImportJobById 根据 ID 查询导入任务
+	FindImportJobById(ctx context.Context, id int64) (domain.ImportJob, error)
+
+	// ListImportJobs 查询模型下的导入任务，不包含行级错误明细
+	ListImportJobs(ctx context.Context, modelUid string, offset, limit int64) ([]domain.ImportJob, error)
+
+	// TotalImportJobs 统计模型下的导入任务数量
+	TotalImportJobs(ctx context.Context, modelUid string) (int64, error)
+
+	// ListPendingImportJobs 跨租户查询待执行的导入任务
+	ListPendingImportJobs(ctx context.Context, limit int64) ([]domain.ImportJob, error)
+
+	// ClaimImportJob 领取待执行的导入任务
+	ClaimImportJob(ctx context.Context, id int64) (bool, error)
+
+	// UpdateImportJobProgress 更新导入进度
+	UpdateImportJobProgress(ctx context.Context, job domain.ImportJob) error
+
+	// FinishImportJob 记录导入结果
+	FinishImportJob(ctx context.Context, job domain.ImportJob) error
+
+	// FailStaleImportJobs 将心跳超时的执行中任务标记为失败
+	FailStaleImportJobs(ctx context.Context, before int64, message string) (int64, error)
+}
+
+func NewImportJobRepository(dao dao.ImportJobDAO) ImportJobRepository {
+	return &importJobRepository{
+		dao: dao,
+	}
+}
+
+type importJobRepository struct {
+	dao dao.ImportJobDAO
+}
+
+func (repo *importJobRepository) CreateImportJob(ctx context.Context, job domain.ImportJob) (int64, error) {
+	return repo.dao.Create(ctx, repo.toEntity(job))
+}
+
+func (repo *importJobRepository) FindImportJobById(ctx context.Context, id int64) (domain.ImportJob, error) {
+	job, err := repo.dao.FindById(ctx, id)
+	return repo.toDomain(job), err
+}
+
+func (repo *importJobRepository) ListImportJobs(ctx context.Context, modelUid string,
+	offset, limit int64) ([]domain.ImportJob, error) {
+	jobs, err := repo.dao.ListByModelUid(ctx, modelUid, offset, limit)
+	return slice.Map(jobs, func(idx int, src dao.ImportJob) domain.ImportJob {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *importJobRepository) TotalImportJobs(ctx context.Context, modelUid string) (int64, error) {
+	return repo.dao.CountByModelUid(ctx, modelUid)
+}
+
+func (repo *importJobRepository) ListPendingImportJobs(ctx context.Context, limit int64) ([]domain.ImportJob, error) {
+	jobs, err := repo.dao.ListPending(ctx, limit)
+	return slice.Map(jobs, func(idx int, src dao.ImportJob) domain.ImportJob {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *importJobRepository) ClaimImportJob(ctx context.Context, id int64) (bool, error) {
+	return repo.dao.Claim(ctx, id)
+}
+
+func (repo *importJobRepository) UpdateImportJobProgress(ctx context.Context, job domain.ImportJob) error {
+	return repo.dao.UpdateProgress(ctx, repo.toEntity(job))
+}
+
+func (repo *importJobRepository) FinishImportJob(ctx context.Context, job domain.ImportJob) error {
+	return repo.dao.Finish(ctx, repo.toEntity(job))
+}
+
+func (repo *importJobRepository) FailStaleImportJobs(ctx context.Context, before int64, message string) (int64, error) {
+	return repo.dao.FailStale(ctx, before, message)
+}
+
+func (repo *importJobRepository) toEntity(req domain.ImportJob) dao.ImportJob {
+	return dao.ImportJob{
+		Id:        req.ID,
+		ModelUID:  req.ModelUID,
+		FileKey:   req.FileKey,
+		FileName:  req.FileName,
+		Format:    string(req.Format),
+		Delimiter: req.Delimiter,
+		Encoding:  req.Encoding,
+		Workbook:  req.Workbook,
+		Mapping:   toImportMappingSnapshot(req.Mapping),
+		Mode:      string(req.Mode),
+		DryRun:    req.DryRun,
+		Status:    string(req.Status),
+		Total:     req.Total,
+		Processed: req.Processed,
+		Inserted:  req.Inserted,
+		Updated:   req.Updated,
+		Failed:    req.Failed,
+		Sheets: slice.Map(req.Sheets, func(idx int, src domain.ImportSheetResult) dao.ImportSheet {
+			return dao.ImportSheet{
+				Sheet:    src.Sheet,
+				ModelUID: src.ModelUID,
+				Total:    src.Total,
+				Inserted: src.Inserted,
+				Updated:  src.Updated,
+				Failed:   src.Failed,
+			}
+		}),
+		Errors: slice.Map(req.Errors, func(idx int, src domain.ImportRowError) dao.ImportRowError {
+			return dao.ImportRowError{
+				Sheet:    src.Sheet,
+				Row:      src.Row,
+				Cell:     src.Cell,
+				FieldUid: src.FieldUid,
+				Message:  src.Message,
+			}
+		}),
+		ReportKey: req.ReportKey,
+		Message:   req.Message,
+		CreatorID: req.CreatorID,
+	}
+}
+
+func (repo *importJobRepository) toDomain(src dao.ImportJob) domain.ImportJob {
+	return domain.ImportJob{
+		ID:       src.Id,
+		TenantID: src.TenantID,
+		ModelUID: src.ModelUID,
+		FileKey:  src.FileKey,
+		FileName: src.FileName,
+		FormatOptions: domain.FormatOptions{
+			Format:    domain.FileFormat(src.Format),
+			Delimiter: src.Delimiter,
+			Encoding:  src.Encoding,
+		},
+		Workbook:  src.Workbook,
+		Mapping:   toImportMappingSnapshotDomain(src.Mapping),
+		Mode:      domain.ImportMode(src.Mode),
+		DryRun:    src.DryRun,
+		Status:    domain.ImportJobStatus(src.Status),
+		Total:     src.Total,
+		Processed: src.Processed,
+		Inserted:  src.Inserted,
+		Updated:   src.Updated,
+		Failed:    src.Failed,
+		Sheets: slice.Map(src.Sheets, func(idx int, src dao.ImportSheet) domain.ImportSheetResult {
+			return domain.ImportSheetResult{
+				Sheet:    src.Sheet,
+				ModelUID: src.ModelUID,
+				Total:    src.Total,
+				Inserted: src.Inserted,
+				Updated:  src.Updated,
+				Failed:   src.Failed,
+			}
+		}),
+		Errors: slice.Map(src.Errors, func(idx int, src dao.ImportRowError) domain.ImportRowError {
+			return domain.ImportRowError{
+				Sheet:    src.Sheet,
+				Row:      src.Row,
+				Cell:     src.Cell,
+				FieldUid: src.FieldUid,
+				Message:  src.Message,
+			}
+		}),
+		ReportKey: src.ReportKey,
+		Message:   src.Message,
+		CreatorID: src.CreatorID,
+		Ctime:     src.Ctime,
+		Utime:     src.Utime,
+		Ftime:     src.Ftime,
+	}
+}
+
+func toImportMappingSnapshot(src *domain.ImportMapping) *dao.ImportMapping {
+	if src == nil {
+		return nil
+	}
+	mapping := toImportMappingEntity(*src)
+	return &mapping
+}
+
+func toImportMappingSnapshotDomain(src *dao.ImportMapping) *domain.ImportMapping {
+	if src == nil {
+		return nil
+	}
+	mapping := toImportMappingDomain(*src)
+	return &mapping
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type ImportMappingRepository interface {
+	// CreateImportMapping 创建映射方案
+	CreateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error)
+
+	// UpdateImportMapping 修改映射方案
+	UpdateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error)
+
+	// DeleteImportMapping 删除映射方案
+	DeleteImportMapping(ctx context.Context, id int64) (int64, error)
+
+	// FindImportMappingById 根据 ID 查询映射方案
+	FindImportMappingById(ctx context.Context, id int64) (domain.ImportMapping, error)
+
+	// ListImportMappings 查询模型下的映射方案
+	ListImportMappings(ctx context.Context, modelUid string) ([]domain.ImportMapping, error)
+}
+
+func NewImportMappingRepository(dao dao.ImportMappingDAO) ImportMappingRepository {
+	return &importMappingRepository{
+		dao: dao,
+	}
+}
+
+type importMappingRepository struct {
+	dao dao.ImportMappingDAO
+}
+
+func (repo *importMappingRepository) CreateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error) {
+	return repo.dao.Create(ctx, toImportMappingEntity(mapping))
+}
+
+func (repo *importMappingRepository) UpdateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error) {
+	return repo.dao.Update(ctx, toImportMappingEntity(mapping))
+}
+
+func (repo *importMappingRepository) DeleteImportMapping(ctx context.Context, id int64) (int64, error) {
+	return repo.dao.Delete(ctx, id)
+}
+
+func (repo *importMappingRepository) FindImportMappingById(ctx context.Context, id int64) (domain.ImportMapping, error) {
+	mapping, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.ImportMapping{}, err
+	}
+
+	return toImportMappingDomain(mapping), nil
+}
+
+func (repo *importMappingRepository) ListImportMappings(ctx context.Context, modelUid string) ([]domain.ImportMapping, error) {
+	mappings, err := repo.dao.ListByModelUid(ctx, modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(mappings, func(idx int, src dao.ImportMapping) domain.ImportMapping {
+		return toImportMappingDomain(src)
+	}), nil
+}
+
+// toImportMappingEntity 映射方案转换为存储实体，导入任务的映射快照复用该结构
+func toImportMappingEntity(src domain.ImportMapping) dao.ImportMapping {
+	return dao.ImportMapping{
+		Id:        src.ID,
+		ModelUID:  src.ModelUID,
+		Name:      src.Name,
+		HeaderRow: src.HeaderRow,
+		Columns: slice.Map(src.Columns, func(idx int, src domain.ColumnMapping) dao.ImportColumnMapping {
+			return dao.ImportColumnMapping{
+				Source:   src.Source,
+				FieldUid: src.FieldUid,
+				Transforms: slice.Map(src.Transforms, func(idx int, src domain.ColumnTransform) dao.ImportColumnTransform {
+					return dao.ImportColumnTransform{
+						Type:      string(src.Type),
+						Separator: src.Separator,
+						Index:     src.Index,
+						Pattern:   src.Pattern,
+						Mapping:   src.Mapping,
+						Layout:    src.Layout,
+						Output:    src.Output,
+					}
+				}),
+			}
+		}),
+		CreatorID: src.CreatorID,
+	}
+}
+
+func toImportMappingDomain(src dao.ImportMapping) domain.ImportMapping {
+	return domain.ImportMapping{
+		ID:        src.Id,
+		ModelUID:  src.ModelUID,
+		Name:      src.Name,
+		HeaderRow: src.HeaderRow,
+		Columns: slice.Map(src.Columns, func(idx int, src dao.ImportColumnMapping) domain.ColumnMapping {
+			return domain.ColumnMapping{
+				Source:   src.Source,
+				FieldUid: src.FieldUid,
+				Transforms: slice.Map(src.Transforms, func(idx int, src dao.ImportColumnTransform) domain.ColumnTransform {
+					return domain.ColumnTransform{
+						Type:      domain.TransformType(src.Type),
+						Separator: src.Separator,
+						Index:     src.Index,
+						Pattern:   src.Pattern,
+						Mapping:   src.Mapping,
+						Layout:    src.Layout,
+						Output:    src.Output,
+					}
+				}),
+			}
+		}),
+		CreatorID: src.CreatorID,
+		Ctime:     src.Ctime,
+		Utime:     src.Utime,
+	}
+}
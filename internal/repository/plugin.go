@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/ecodeclub/ekit/slice"
 )
 
 type PluginRepository interface {
@@ -21,12 +25,21 @@ type PluginRepository interface {
 	// DeleteBinding 删除插件绑定。
 	DeleteBinding(ctx context.Context, uid string) error
 
+	// DeleteBindingsByPluginID 删除插件全部绑定，返回删除的数量。
+	DeleteBindingsByPluginID(ctx context.Context, pluginID string) (int64, error)
+
+	// DeletePlugin 删除插件定义。
+	DeletePlugin(ctx context.Context, uid string) error
+
 	// GetBinding 根据绑定 UID 查询插件绑定。
 	GetBinding(ctx context.Context, uid string) (domain.PluginBinding, error)
 
 	// GetPlugin 根据插件 UID 查询插件定义。
 	GetPlugin(ctx context.Context, uid string) (domain.Plugin, error)
 
+	// UpdatePluginHealth 更新插件健康检查结果。
+	UpdatePluginHealth(ctx context.Context, uid string, health pluginx.Health) error
+
 	// ListPlugins 查询插件定义列表。
 	ListPlugins(ctx context.Context) ([]domain.Plugin, error)
 
@@ -41,6 +54,45 @@ type PluginRepository interface {
 
 	// ListEnabledBindingsByModelUIDs 批量查询指定模型启用中的插件绑定。
 	ListEnabledBindingsByModelUIDs(ctx context.Context, modelUIDs []string) ([]domain.PluginBinding, error)
+
+	// UpsertCredential 按插件 UID 创建或替换插件凭证。
+	UpsertCredential(ctx context.Context, c domain.PluginCredential) error
+
+	// GetCredential 根据插件 UID 查询插件凭证。
+	GetCredential(ctx context.Context, pluginID string) (domain.PluginCredential, error)
+
+	// DeleteCredential 删除插件凭证。
+	DeleteCredential(ctx context.Context, pluginID string) error
+
+	// CreateSchemaOwnerships 记录插件导入 Schema 时新建的对象。
+	CreateSchemaOwnerships(ctx context.Context, ownerships []domain.PluginSchemaOwnership) error
+
+	// ListSchemaOwnerships 查询插件新建对象的归属记录。
+	ListSchemaOwnerships(ctx context.Context, pluginID string) ([]domain.PluginSchemaOwnership, error)
+
+	// DeleteSchemaOwnership 删除单条归属记录。
+	DeleteSchemaOwnership(ctx context.Context, id int64) error
+
+	// CreateVersion 追加插件定义版本快照。
+	CreateVersion(ctx context.Context, v domain.PluginVersion) error
+
+	// GetLatestVersion 查询插件最新的定义版本。
+	GetLatestVersion(ctx context.Context, pluginID string) (domain.PluginVersion, error)
+
+	// GetVersion 按修订号查询插件定义版本。
+	GetVersion(ctx context.Context, pluginID string, revision int64) (domain.PluginVersion, error)
+
+	// ListVersions 查询插件全部定义版本，按修订号倒序，不包含定义内容。
+	ListVersions(ctx context.Context, pluginID string) ([]domain.PluginVersion, error)
+
+	// CreateAuditLog 写入插件动作审计日志。
+	CreateAuditLog(ctx context.Context, log domain.PluginAuditLog) error
+
+	// ListAuditLogs 按条件分页查询审计日志，按时间倒序。
+	ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]domain.PluginAuditLog, error)
+
+	// TotalAuditLogs 统计符合条件的审计日志数量。
+	TotalAuditLogs(ctx context.Context, query domain.PluginAuditQuery) (int64, error)
 }
 
 type pluginRepository struct {
@@ -82,6 +134,14 @@ func (repo *pluginRepository) DeleteBinding(ctx context.Context, uid string) err
 	return repo.dao.DeleteBinding(ctx, uid)
 }
 
+func (repo *pluginRepository) DeleteBindingsByPluginID(ctx context.Context, pluginID string) (int64, error) {
+	return repo.dao.DeleteBindingsByPluginID(ctx, pluginID)
+}
+
+func (repo *pluginRepository) DeletePlugin(ctx context.Context, uid string) error {
+	return repo.dao.DeletePlugin(ctx, uid)
+}
+
 func (repo *pluginRepository) GetBinding(ctx context.Context, uid string) (domain.PluginBinding, error) {
 	binding, err := repo.dao.GetBinding(ctx, uid)
 	if err != nil {
@@ -103,11 +163,16 @@ func (repo *pluginRepository) GetPlugin(ctx context.Context, uid string) (domain
 		Version: p.Version,
 		Actions: p.Actions,
 		Meta:    p.Meta,
+		Health:  p.Health,
 		Ctime:   time.UnixMilli(p.Ctime).UnixMilli(),
 		Utime:   time.UnixMilli(p.Utime).UnixMilli(),
 	}, nil
 }
 
+func (repo *pluginRepository) UpdatePluginHealth(ctx context.Context, uid string, health pluginx.Health) error {
+	return repo.dao.UpdateHealth(ctx, uid, health)
+}
+
 func (repo *pluginRepository) ListPlugins(ctx context.Context) ([]domain.Plugin, error) {
 	plugins, err := repo.dao.ListPlugins(ctx)
 	if err != nil {
@@ -124,6 +189,7 @@ func (repo *pluginRepository) ListPlugins(ctx context.Context) ([]domain.Plugin,
 			Version: plugin.Version,
 			Actions: plugin.Actions,
 			Meta:    plugin.Meta,
+			Health:  plugin.Health,
 			Ctime:   time.UnixMilli(plugin.Ctime).UnixMilli(),
 			Utime:   time.UnixMilli(plugin.Utime).UnixMilli(),
 		})
@@ -163,6 +229,178 @@ func (repo *pluginRepository) ListEnabledBindingsByModelUIDs(ctx context.Context
 	return toPluginBindings(bindings), nil
 }
 
+func (repo *pluginRepository) UpsertCredential(ctx context.Context, c domain.PluginCredential) error {
+	return repo.dao.UpsertCredential(ctx, dao.PluginCredential{
+		PluginID:    c.PluginID,
+		SecretHash:  c.SecretHash,
+		IdentityKey: c.IdentityKey,
+	})
+}
+
+func (repo *pluginRepository) GetCredential(ctx context.Context, pluginID string) (domain.PluginCredential, error) {
+	c, err := repo.dao.GetCredential(ctx, pluginID)
+	if err != nil {
+		return domain.PluginCredential{}, err
+	}
+	return domain.PluginCredential{
+		PluginID:    c.PluginID,
+		SecretHash:  c.SecretHash,
+		IdentityKey: c.IdentityKey,
+		Ctime:       c.Ctime,
+		Utime:       c.Utime,
+	}, nil
+}
+
+func (repo *pluginRepository) DeleteCredential(ctx context.Context, pluginID string) error {
+	return repo.dao.DeleteCredential(ctx, pluginID)
+}
+
+func (repo *pluginRepository) CreateSchemaOwnerships(ctx context.Context, ownerships []domain.PluginSchemaOwnership) error {
+	return repo.dao.CreateSchemaOwnerships(ctx, slice.Map(ownerships, func(idx int, src domain.PluginSchemaOwnership) dao.PluginSchemaOwnership {
+		return dao.PluginSchemaOwnership{
+			PluginID: src.PluginID,
+			Kind:     src.Kind,
+			ModelUID: src.ModelUID,
+			UID:      src.UID,
+		}
+	}))
+}
+
+func (repo *pluginRepository) ListSchemaOwnerships(ctx context.Context, pluginID string) ([]domain.PluginSchemaOwnership, error) {
+	ownerships, err := repo.dao.ListSchemaOwnerships(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ownerships, func(idx int, src dao.PluginSchemaOwnership) domain.PluginSchemaOwnership {
+		return domain.PluginSchemaOwnership{
+			ID:       src.Id,
+			TenantID: src.TenantID,
+			PluginID: src.PluginID,
+			Kind:     src.Kind,
+			ModelUID: src.ModelUID,
+			UID:      src.UID,
+			Ctime:    src.Ctime,
+		}
+	}), nil
+}
+
+func (repo *pluginRepository) DeleteSchemaOwnership(ctx context.Context, id int64) error {
+	return repo.dao.DeleteSchemaOwnership(ctx, id)
+}
+
+func (repo *pluginRepository) CreateVersion(ctx context.Context, v domain.PluginVersion) error {
+	definition, err := json.Marshal(v.Definition)
+	if err != nil {
+		return fmt.Errorf("序列化插件定义失败: %w", err)
+	}
+	return repo.dao.CreateVersion(ctx, dao.PluginVersion{
+		PluginID:   v.PluginID,
+		Version:    v.Version,
+		Revision:   v.Revision,
+		Checksum:   v.Checksum,
+		Definition: string(definition),
+		Source:     v.Source,
+	})
+}
+
+func (repo *pluginRepository) GetLatestVersion(ctx context.Context, pluginID string) (domain.PluginVersion, error) {
+	v, err := repo.dao.GetLatestVersion(ctx, pluginID)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	return toPluginVersion(v)
+}
+
+func (repo *pluginRepository) GetVersion(ctx context.Context, pluginID string, revision int64) (domain.PluginVersion, error) {
+	v, err := repo.dao.GetVersion(ctx, pluginID, revision)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	return toPluginVersion(v)
+}
+
+func (repo *pluginRepository) ListVersions(ctx context.Context, pluginID string) ([]domain.PluginVersion, error) {
+	versions, err := repo.dao.ListVersions(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(versions, func(idx int, src dao.PluginVersion) domain.PluginVersion {
+		v, _ := toPluginVersion(src)
+		return v
+	}), nil
+}
+
+func (repo *pluginRepository) CreateAuditLog(ctx context.Context, log domain.PluginAuditLog) error {
+	return repo.dao.CreateAuditLog(ctx, dao.PluginAuditLog{
+		PluginID:    log.PluginID,
+		Action:      log.Action,
+		ResourceIDs: log.ResourceIDs,
+		UserID:      log.UserID,
+		Source:      log.Source,
+		SourceIP:    log.SourceIP,
+		RequestID:   log.RequestID,
+		Method:      log.Method,
+		Path:        log.Path,
+		Success:     log.Success,
+		StatusCode:  log.StatusCode,
+		Error:       log.Error,
+		DurationMs:  log.DurationMs,
+		Ctime:       log.Ctime,
+		ExpireAt:    time.UnixMilli(log.ExpireAt),
+	})
+}
+
+func (repo *pluginRepository) ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]domain.PluginAuditLog, error) {
+	logs, err := repo.dao.ListAuditLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(logs, func(idx int, src dao.PluginAuditLog) domain.PluginAuditLog {
+		return domain.PluginAuditLog{
+			ID:          src.Id,
+			PluginID:    src.PluginID,
+			Action:      src.Action,
+			ResourceIDs: src.ResourceIDs,
+			UserID:      src.UserID,
+			TenantID:    src.TenantID,
+			Source:      src.Source,
+			SourceIP:    src.SourceIP,
+			RequestID:   src.RequestID,
+			Method:      src.Method,
+			Path:        src.Path,
+			Success:     src.Success,
+			StatusCode:  src.StatusCode,
+			Error:       src.Error,
+			DurationMs:  src.DurationMs,
+			Ctime:       src.Ctime,
+			ExpireAt:    src.ExpireAt.UnixMilli(),
+		}
+	}), nil
+}
+
+func (repo *pluginRepository) TotalAuditLogs(ctx context.Context, query domain.PluginAuditQuery) (int64, error) {
+	return repo.dao.CountAuditLogs(ctx, query)
+}
+
+func toPluginVersion(v dao.PluginVersion) (domain.PluginVersion, error) {
+	version := domain.PluginVersion{
+		ID:       v.Id,
+		PluginID: v.PluginID,
+		Version:  v.Version,
+		Revision: v.Revision,
+		Checksum: v.Checksum,
+		Source:   v.Source,
+		Ctime:    v.Ctime,
+	}
+	if v.Definition == "" {
+		return version, nil
+	}
+	if err := json.Unmarshal([]byte(v.Definition), &version.Definition); err != nil {
+		return domain.PluginVersion{}, fmt.Errorf("解析插件定义版本失败: %w", err)
+	}
+	return version, nil
+}
+
 func toPluginBindings(bindings []dao.PluginBinding) []domain.PluginBinding {
 	res := make([]domain.PluginBinding, 0, len(bindings))
 	for _, binding := range bindings {
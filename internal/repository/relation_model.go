@@ -35,6 +35,12 @@ type RelationModelRepository interface {
 	// CountByRelationTypeUID 根据关联类型 UID 获取数量
 	CountByRelationTypeUID(ctx context.Context, uid string) (int64, error)
 
+	// ListByRelationTypeUID 根据关联类型 UID 获取所有模型关联
+	ListByRelationTypeUID(ctx context.Context, uid string) ([]domain.ModelRelation, error)
+
+	// DeleteByRelationTypeUID 根据关联类型 UID 批量删除模型关联
+	DeleteByRelationTypeUID(ctx context.Context, uid string) (int64, error)
+
 	// GetByID 根据 ID 获取数据
 	GetByID(ctx context.Context, id int64) (domain.ModelRelation, error)
 
@@ -131,6 +137,17 @@ func (r *modelRelationRepository) CountByRelationTypeUID(ctx context.Context, ui
 	return r.dao.CountByRelationTypeUid(ctx, uid)
 }
 
+func (r *modelRelationRepository) ListByRelationTypeUID(ctx context.Context, uid string) ([]domain.ModelRelation, error) {
+	rms, err := r.dao.ListByRelationTypeUid(ctx, uid)
+	return slice.Map(rms, func(idx int, src dao.ModelRelation) domain.ModelRelation {
+		return r.toDomain(src)
+	}), err
+}
+
+func (r *modelRelationRepository) DeleteByRelationTypeUID(ctx context.Context, uid string) (int64, error) {
+	return r.dao.DeleteByRelationTypeUid(ctx, uid)
+}
+
 func (r *modelRelationRepository) GetByID(ctx context.Context, id int64) (domain.ModelRelation, error) {
 	val, err := r.dao.GetByID(ctx, id)
 	return r.toDomain(val), err
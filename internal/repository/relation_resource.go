@@ -6,6 +6,7 @@ import (
 	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/internal/repository/dao"
 	"github.com/ecodeclub/ekit/slice"
+	"github.com/samber/lo"
 )
 
 // RelationResourceRepository 资源实例关联关系仓储接口
@@ -26,6 +27,8 @@ type RelationResourceRepository interface {
 	ListSrcAggregated(ctx context.Context, modelUid string, id int64) ([]domain.ResourceAggregatedAssets, error)
 	// ListDstAggregated 聚合查询目标关联列表
 	ListDstAggregated(ctx context.Context, modelUid string, id int64) ([]domain.ResourceAggregatedAssets, error)
+	// ListRelatedIdsBatch 批量聚合查询资源在指定关联下的对端资源 ID，返回 资源 ID -> 对端资源 ID 列表
+	ListRelatedIdsBatch(ctx context.Context, relationName string, isSource bool, ids []int64) (map[int64][]int64, error)
 
 	// ListSrcRelated 查询当前已经关联的数据，新增资源关联使用
 	ListSrcRelated(ctx context.Context, modelUid, relationName string, id int64) ([]int64, error)
@@ -38,6 +41,22 @@ type RelationResourceRepository interface {
 	// CountByRelationName 根据关联名称获取数量
 	CountByRelationName(ctx context.Context, name string) (int64, error)
 
+	// ListByRelationName 根据关联名称查询资源关联，limit 为 0 时不限制数量
+	ListByRelationName(ctx context.Context, name string, limit int64) ([]domain.ResourceRelation, error)
+	// DeleteByRelationName 根据关联名称批量删除资源关联
+	DeleteByRelationName(ctx context.Context, name string) (int64, error)
+	// DeleteByRelationTypeUID 根据关联类型 UID 批量删除资源关联
+	DeleteByRelationTypeUID(ctx context.Context, uid string) (int64, error)
+	// DeleteByIds 根据 ID 批量删除资源关联
+	DeleteByIds(ctx context.Context, ids []int64) (int64, error)
+	// MigrateRelationName 将指定的资源关联改挂到新的关联定义下
+	MigrateRelationName(ctx context.Context, ids []int64, relationTypeUID, relationName string) (int64, error)
+
+	// ListAfterID 按 ID 游标分页查询资源关联，用于全量扫描
+	ListAfterID(ctx context.Context, afterID int64, limit int64) ([]domain.ResourceRelation, error)
+	// ListRelationNames 查询资源关联中出现过的所有关联名称
+	ListRelationNames(ctx context.Context) ([]string, error)
+
 	// DeleteResourceRelation 删除资源关联关系
 	DeleteResourceRelation(ctx context.Context, id int64) (int64, error)
 	// DeleteSrcRelation 删除源端关系
@@ -49,6 +68,9 @@ type RelationResourceRepository interface {
 	ListRecursiveSrc(ctx context.Context, modelUid string, id int64, maxDepth int) ([]domain.ResourceRelation, error)
 	// ListRecursiveDst 递归查询上游关联资产列表（反向递归）
 	ListRecursiveDst(ctx context.Context, modelUid string, id int64, maxDepth int) ([]domain.ResourceRelation, error)
+
+	// ListRelationsAsOf 查询 asOf 时刻存在的资源关联，isSource 表示 ids 位于关联的源端
+	ListRelationsAsOf(ctx context.Context, isSource bool, ids []int64, asOf int64) ([]domain.ResourceRelation, error)
 }
 
 func NewRelationResourceRepository(dao dao.RelationResourceDAO) RelationResourceRepository {
@@ -79,6 +101,17 @@ func (r *resourceRelationRepository) ListDstAggregated(ctx context.Context, mode
 	}), err
 }
 
+func (r *resourceRelationRepository) ListRelatedIdsBatch(ctx context.Context, relationName string, isSource bool,
+	ids []int64) (map[int64][]int64, error) {
+	rrs, err := r.dao.ListRelatedIdsBatch(ctx, relationName, isSource, ids)
+	if err != nil {
+		return nil, err
+	}
+	return lo.SliceToMap(rrs, func(src dao.ResourceRelatedIds) (int64, []int64) {
+		return src.ResourceId, src.ResourceIds
+	}), nil
+}
+
 func (r *resourceRelationRepository) ListSrcResources(ctx context.Context, modelUid string, id int64) ([]domain.ResourceRelation, error) {
 	rrs, err := r.dao.ListSrcResources(ctx, modelUid, id)
 	return slice.Map(rrs, func(idx int, src dao.ResourceRelation) domain.ResourceRelation {
@@ -161,6 +194,40 @@ func (r *resourceRelationRepository) CountByRelationName(ctx context.Context, na
 	return r.dao.CountByRelationName(ctx, name)
 }
 
+func (r *resourceRelationRepository) ListByRelationName(ctx context.Context, name string, limit int64) ([]domain.ResourceRelation, error) {
+	rrs, err := r.dao.ListByRelationName(ctx, name, limit)
+	return slice.Map(rrs, func(idx int, src dao.ResourceRelation) domain.ResourceRelation {
+		return r.toResourceDomain(src)
+	}), err
+}
+
+func (r *resourceRelationRepository) DeleteByRelationName(ctx context.Context, name string) (int64, error) {
+	return r.dao.DeleteByRelationName(ctx, name)
+}
+
+func (r *resourceRelationRepository) DeleteByRelationTypeUID(ctx context.Context, uid string) (int64, error) {
+	return r.dao.DeleteByRelationTypeUid(ctx, uid)
+}
+
+func (r *resourceRelationRepository) DeleteByIds(ctx context.Context, ids []int64) (int64, error) {
+	return r.dao.DeleteByIds(ctx, ids)
+}
+
+func (r *resourceRelationRepository) MigrateRelationName(ctx context.Context, ids []int64, relationTypeUID, relationName string) (int64, error) {
+	return r.dao.MigrateRelationName(ctx, ids, relationTypeUID, relationName)
+}
+
+func (r *resourceRelationRepository) ListAfterID(ctx context.Context, afterID int64, limit int64) ([]domain.ResourceRelation, error) {
+	rrs, err := r.dao.ListAfterID(ctx, afterID, limit)
+	return slice.Map(rrs, func(idx int, src dao.ResourceRelation) domain.ResourceRelation {
+		return r.toResourceDomain(src)
+	}), err
+}
+
+func (r *resourceRelationRepository) ListRelationNames(ctx context.Context) ([]string, error) {
+	return r.dao.ListRelationNames(ctx)
+}
+
 func (r *resourceRelationRepository) ListRecursiveSrc(ctx context.Context, modelUid string, id int64, maxDepth int) ([]domain.ResourceRelation, error) {
 	rrs, err := r.dao.ListRecursiveSrc(ctx, modelUid, id, maxDepth)
 	return slice.Map(rrs, func(idx int, src dao.ResourceRelation) domain.ResourceRelation {
@@ -174,3 +241,11 @@ func (r *resourceRelationRepository) ListRecursiveDst(ctx context.Context, model
 		return r.toResourceDomain(src)
 	}), err
 }
+
+func (r *resourceRelationRepository) ListRelationsAsOf(ctx context.Context, isSource bool, ids []int64,
+	asOf int64) ([]domain.ResourceRelation, error) {
+	rrs, err := r.dao.ListRelationsAsOf(ctx, isSource, ids, asOf)
+	return slice.Map(rrs, func(idx int, src dao.ResourceRelation) domain.ResourceRelation {
+		return r.toResourceDomain(src)
+	}), err
+}
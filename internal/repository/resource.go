@@ -31,6 +31,9 @@ type ResourceRepository interface {
 	// ListResourcesByIds 根据 ID 列表批量获取资产
 	ListResourcesByIds(ctx context.Context, fields []string, ids []int64) ([]domain.Resource, error)
 
+	// ListFullResourcesByIds 根据 ID 列表批量查询资产的完整数据
+	ListFullResourcesByIds(ctx context.Context, ids []int64) ([]domain.Resource, error)
+
 	// DeleteResource 删除指定资产
 	DeleteResource(ctx context.Context, id int64) (int64, error)
 
@@ -41,12 +44,12 @@ type ResourceRepository interface {
 	// TotalExcludeAndFilterResourceByIds 排除指定 ID 并根据条件统计资产总数
 	TotalExcludeAndFilterResourceByIds(ctx context.Context, modelUid string, ids []int64, filter domain.Condition) (int64, error)
 
-	// Search 全局搜索资产
-	Search(ctx context.Context, text string) ([]domain.SearchResource, error)
-
 	// FindSecureData 查找指定资产的加密字段数据
 	FindSecureData(ctx context.Context, id int64, fieldUid string) (string, error)
 
+	// ListExistingNames 查询模型下已存在的资产名称
+	ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error)
+
 	// UpdateResource 更新资产数据
 	UpdateResource(ctx context.Context, resource domain.Resource) (int64, error)
 
@@ -68,8 +71,39 @@ type ResourceRepository interface {
 	ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64,
 		filterGroups []domain.FilterGroup) ([]domain.Resource, error)
 
+	// ListResourcesByQuery 根据筛选条件及排序字段获取资产列表
+	ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, offset, limit int64) ([]domain.Resource, error)
+
+	// IterateResourcesByQuery 以游标方式按批遍历符合条件的资产
+	IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, batchSize int,
+		fn func(batch []domain.Resource) error) error
+
 	// TotalResourcesWithFilters 根据复杂筛选条件统计资产数量
 	TotalResourcesWithFilters(ctx context.Context, modelUid string, ids []int64, filterGroups []domain.FilterGroup) (int64, error)
+
+	// ListResourcesAsOf 根据快照与变更日志还原指定资产在 asOf 时刻的状态，当时不存在的资产不会返回
+	ListResourcesAsOf(ctx context.Context, ids []int64, asOf int64) ([]domain.Resource, error)
+
+	// ListModelResourcesAsOf 分页还原指定模型下在 asOf 时刻存在的资产，按 ID 倒序排列，limit 为 0 时不分页
+	ListModelResourcesAsOf(ctx context.Context, modelUid string, asOf int64, offset, limit int64) ([]domain.Resource, int64, error)
+
+	// LatestSnapshotTime 查询 before 之前（含）最近一轮快照的时间，没有快照时返回 0
+	LatestSnapshotTime(ctx context.Context, before int64) (int64, error)
+
+	// EarliestSnapshotTime 查询最早一轮快照的时间，即历史数据保留的起点，没有快照时返回 0
+	EarliestSnapshotTime(ctx context.Context) (int64, error)
+
+	// PruneHistory 删除 before 之前的快照、变更日志与已删除资源关联，返回删除的记录数量
+	PruneHistory(ctx context.Context, before int64) (int64, error)
+
+	// CreateSnapshots 按 ID 游标为一批资产生成快照，返回本批最后一个资产 ID 与快照数量
+	CreateSnapshots(ctx context.Context, afterID int64, limit int64, snapshotTime int64) (int64, int, error)
+
+	// ListChangesSince 跨租户查询 (since, until] 区间内的资产变更日志，按时间升序排列，limit 为 0 时不限制数量
+	ListChangesSince(ctx context.Context, since, until int64, limit int64) ([]domain.ResourceChange, error)
+
+	// ListSearchDocuments 跨租户按 ID 游标升序读取资产，用于重建检索索引
+	ListSearchDocuments(ctx context.Context, afterID int64, limit int64) ([]domain.SearchDocument, error)
 }
 
 type resourceRepository struct {
@@ -130,6 +164,13 @@ func (repo *resourceRepository) ListResourcesByIds(ctx context.Context, fields [
 	}), err
 }
 
+func (repo *resourceRepository) ListFullResourcesByIds(ctx context.Context, ids []int64) ([]domain.Resource, error) {
+	rrs, err := repo.dao.ListFullResourcesByIds(ctx, ids)
+	return slice.Map(rrs, func(idx int, src dao.Resource) domain.Resource {
+		return repo.toDomain(src)
+	}), err
+}
+
 func (repo *resourceRepository) ListResource(ctx context.Context, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
 	rrs, err := repo.dao.ListResource(ctx, fields, modelUid, offset, limit)
 
@@ -146,18 +187,6 @@ func (repo *resourceRepository) DeleteResource(ctx context.Context, id int64) (i
 	return repo.dao.DeleteResource(ctx, id)
 }
 
-func (repo *resourceRepository) Search(ctx context.Context, text string) ([]domain.SearchResource, error) {
-	search, err := repo.dao.Search(ctx, text)
-
-	return slice.Map(search, func(idx int, src dao.SearchResource) domain.SearchResource {
-		return domain.SearchResource{
-			ModelUid: src.ModelUid,
-			Total:    src.Total,
-			Data:     src.Data,
-		}
-	}), err
-}
-
 func (repo *resourceRepository) ListExcludeAndFilterResourceByIds(ctx context.Context, fields []string, modelUid string,
 	offset, limit int64, ids []int64, filter domain.Condition) ([]domain.Resource, error) {
 	rrs, err := repo.dao.ListExcludeAndFilterResourceByIds(ctx, fields, modelUid, offset, limit, ids, filter)
@@ -176,6 +205,10 @@ func (repo *resourceRepository) FindSecureData(ctx context.Context, id int64, fi
 	return repo.dao.FindSecureData(ctx, id, fieldUid)
 }
 
+func (repo *resourceRepository) ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error) {
+	return repo.dao.ListExistingNames(ctx, modelUid, names)
+}
+
 func (repo *resourceRepository) toEntity(req domain.Resource) dao.Resource {
 	return dao.Resource{
 		ID:       req.ID,
@@ -211,6 +244,24 @@ func (repo *resourceRepository) ListResourcesWithFilters(ctx context.Context, fi
 	}), err
 }
 
+func (repo *resourceRepository) ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery,
+	offset, limit int64) ([]domain.Resource, error) {
+	rrs, err := repo.dao.ListResourcesByQuery(ctx, fields, query, offset, limit)
+
+	return slice.Map(rrs, func(idx int, src dao.Resource) domain.Resource {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *resourceRepository) IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery,
+	batchSize int, fn func(batch []domain.Resource) error) error {
+	return repo.dao.IterateResourcesByQuery(ctx, fields, query, batchSize, func(batch []dao.Resource) error {
+		return fn(slice.Map(batch, func(idx int, src dao.Resource) domain.Resource {
+			return repo.toDomain(src)
+		}))
+	})
+}
+
 func (repo *resourceRepository) TotalResourcesWithFilters(ctx context.Context, modelUid string, ids []int64, filterGroups []domain.FilterGroup) (int64, error) {
 	return repo.dao.TotalResourcesWithFilters(ctx, modelUid, ids, filterGroups)
 }
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/samber/lo"
+)
+
+func (repo *resourceRepository) ListResourcesAsOf(ctx context.Context, ids []int64, asOf int64) ([]domain.Resource, error) {
+	if len(ids) == 0 {
+		return []domain.Resource{}, nil
+	}
+
+	snapshots, err := repo.dao.ListLatestSnapshots(ctx, ids, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	// 存在没有快照的资产时，需要从最早的变更日志开始回放
+	var since int64
+	if len(snapshots) == len(lo.Uniq(ids)) {
+		since = lo.MinBy(snapshots, func(a, b dao.ResourceSnapshot) bool {
+			return a.SnapshotTime < b.SnapshotTime
+		}).SnapshotTime
+	}
+
+	changes, err := repo.dao.ListChanges(ctx, dao.ResourceChangeQuery{
+		ResourceIDs: ids,
+		Since:       since,
+		Until:       asOf,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.replay(ids, snapshots, changes, asOf), nil
+}
+
+func (repo *resourceRepository) ListModelResourcesAsOf(ctx context.Context, modelUid string, asOf int64, offset,
+	limit int64) ([]domain.Resource, int64, error) {
+	since, snapshotIDs, err := repo.dao.ListModelSnapshotIDs(ctx, modelUid, asOf)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	changes, err := repo.dao.ListChanges(ctx, dao.ResourceChangeQuery{
+		ModelUID:    modelUid,
+		Since:       since,
+		Until:       asOf,
+		WithoutData: true,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// NOTE: 资产是否存在只取决于快照基线与增删变更，先按 ID 分页，再只为当前页回放完整数据
+	exists := lo.SliceToMap(snapshotIDs, func(id int64) (int64, bool) {
+		return id, true
+	})
+	for _, change := range changes {
+		switch domain.ChangeAction(change.Action) {
+		case domain.ChangeActionCreate, domain.ChangeActionUpdate:
+			exists[change.ResourceID] = true
+		case domain.ChangeActionDelete:
+			exists[change.ResourceID] = false
+		}
+	}
+	ids := lo.Keys(lo.PickBy(exists, func(_ int64, ok bool) bool {
+		return ok
+	}))
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] > ids[j]
+	})
+
+	total := int64(len(ids))
+	if limit <= 0 {
+		limit = total
+	}
+	ids = lo.Subset(ids, int(offset), uint(limit))
+	if len(ids) == 0 {
+		return []domain.Resource{}, total, nil
+	}
+
+	rs, err := repo.ListResourcesAsOf(ctx, ids, asOf)
+	return rs, total, err
+}
+
+func (repo *resourceRepository) LatestSnapshotTime(ctx context.Context, before int64) (int64, error) {
+	return repo.dao.LatestSnapshotTime(ctx, before)
+}
+
+func (repo *resourceRepository) EarliestSnapshotTime(ctx context.Context) (int64, error) {
+	return repo.dao.EarliestSnapshotTime(ctx)
+}
+
+func (repo *resourceRepository) PruneHistory(ctx context.Context, before int64) (int64, error) {
+	return repo.dao.PruneHistory(ctx, before)
+}
+
+func (repo *resourceRepository) CreateSnapshots(ctx context.Context, afterID int64, limit int64, snapshotTime int64) (int64, int, error) {
+	return repo.dao.CreateSnapshots(ctx, afterID, limit, snapshotTime)
+}
+
+func (repo *resourceRepository) ListChangesSince(ctx context.Context, since, until int64,
+	limit int64) ([]domain.ResourceChange, error) {
+	changes, err := repo.dao.ListChangesSince(ctx, since, until, limit)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(changes, func(src dao.ResourceChange, _ int) domain.ResourceChange {
+		return repo.toChangeDomain(src)
+	}), nil
+}
+
+func (repo *resourceRepository) ListSearchDocuments(ctx context.Context, afterID int64,
+	limit int64) ([]domain.SearchDocument, error) {
+	rs, err := repo.dao.ListAllAfterID(ctx, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(rs, func(src dao.Resource, _ int) domain.SearchDocument {
+		return domain.SearchDocument{
+			TenantID: src.TenantID,
+			ID:       src.ID,
+			ModelUID: src.ModelUID,
+			Data:     src.Data,
+		}
+	}), nil
+}
+
+// replay 为每个资产回放快照与变更日志，结果按 ID 倒序排列
+func (repo *resourceRepository) replay(ids []int64, snapshots []dao.ResourceSnapshot, changes []dao.ResourceChange,
+	asOf int64) []domain.Resource {
+	snapshotByID := lo.SliceToMap(snapshots, func(src dao.ResourceSnapshot) (int64, domain.ResourceSnapshot) {
+		return src.ResourceID, domain.ResourceSnapshot{
+			ResourceID:   src.ResourceID,
+			ModelUID:     src.ModelUID,
+			Data:         src.Data,
+			SnapshotTime: src.SnapshotTime,
+		}
+	})
+
+	// NOTE: 模型级字段抹除作用于模型下的全部资产，需要按时间合并进每个资产自己的变更序列
+	domainChanges := lo.Map(changes, func(src dao.ResourceChange, _ int) domain.ResourceChange {
+		return repo.toChangeDomain(src)
+	})
+	unsets := lo.Filter(domainChanges, func(src domain.ResourceChange, _ int) bool {
+		return src.Action == domain.ChangeActionUnset
+	})
+	changesByID := lo.GroupBy(lo.Reject(domainChanges, func(src domain.ResourceChange, _ int) bool {
+		return src.Action == domain.ChangeActionUnset
+	}), func(src domain.ResourceChange) int64 {
+		return src.ResourceID
+	})
+
+	rs := lo.FilterMap(lo.Uniq(ids), func(id int64, _ int) (domain.Resource, bool) {
+		cs := append(append([]domain.ResourceChange{}, changesByID[id]...), unsets...)
+		sort.SliceStable(cs, func(i, j int) bool {
+			return cs[i].Ctime < cs[j].Ctime
+		})
+
+		var snapshot *domain.ResourceSnapshot
+		if s, ok := snapshotByID[id]; ok {
+			snapshot = &s
+		}
+		return domain.ReplayResource(snapshot, cs, asOf)
+	})
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].ID > rs[j].ID
+	})
+	return rs
+}
+
+func (repo *resourceRepository) toChangeDomain(src dao.ResourceChange) domain.ResourceChange {
+	return domain.ResourceChange{
+		TenantID:   src.TenantID,
+		ResourceID: src.ResourceID,
+		ModelUID:   src.ModelUID,
+		Action:     domain.ChangeAction(src.Action),
+		Data:       src.Data,
+		Fields:     src.Fields,
+		Ctime:      src.Ctime,
+	}
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type ScheduledExportRepository interface {
+	// CreateScheduledExport 创建定时导出
+	CreateScheduledExport(ctx context.Context, se domain.ScheduledExport) (int64, error)
+
+	// UpdateScheduledExport 修改定时导出
+	UpdateScheduledExport(ctx context.Context, se domain.ScheduledExport) (int64, error)
+
+	// DeleteScheduledExport 删除定时导出
+	DeleteScheduledExport(ctx context.Context, id int64) (int64, error)
+
+	// FindScheduledExportById 根据 ID 查询定时导出
+	FindScheduledExportById(ctx context.Context, id int64) (domain.ScheduledExport, error)
+
+	// ListScheduledExports 查询定时导出列表
+	ListScheduledExports(ctx context.Context, offset, limit int64) ([]domain.ScheduledExport, error)
+
+	// TotalScheduledExports 统计定时导出数量
+	TotalScheduledExports(ctx context.Context) (int64, error)
+
+	// ListDueScheduledExports 跨租户查询已到执行时间的定时导出
+	ListDueScheduledExports(ctx context.Context, now int64, limit int64) ([]domain.ScheduledExport, error)
+
+	// AdvanceScheduledExport 推进下一次执行时间，返回 false 表示该次执行已被领取
+	AdvanceScheduledExport(ctx context.Context, id int64, from, next int64) (bool, error)
+
+	// CreateScheduledExportRun 创建执行记录
+	CreateScheduledExportRun(ctx context.Context, run domain.ScheduledExportRun) (int64, error)
+
+	// UpdateScheduledExportRun 更新执行记录
+	UpdateScheduledExportRun(ctx context.Context, run domain.ScheduledExportRun) error
+
+	// FindScheduledExportRunById 根据 ID 查询执行记录
+	FindScheduledExportRunById(ctx context.Context, id int64) (domain.ScheduledExportRun, error)
+
+	// ListScheduledExportRuns 查询定时导出的执行记录
+	ListScheduledExportRuns(ctx context.Context, scheduleId int64, offset, limit int64) (
+		[]domain.ScheduledExportRun, error)
+
+	// TotalScheduledExportRuns 统计定时导出的执行记录数量
+	TotalScheduledExportRuns(ctx context.Context, scheduleId int64) (int64, error)
+
+	// ListExpiredScheduledExportRuns 查询超过保留期限且尚未清理文件的执行记录
+	ListExpiredScheduledExportRuns(ctx context.Context, scheduleId int64, before int64) (
+		[]domain.ScheduledExportRun, error)
+
+	// MarkScheduledExportRunsPruned 标记执行记录的导出文件已清理
+	MarkScheduledExportRunsPruned(ctx context.Context, ids []int64) error
+
+	// FailStaleScheduledExportRuns 将心跳超时的执行记录标记为失败
+	FailStaleScheduledExportRuns(ctx context.Context, before int64, message string) (int64, error)
+}
+
+func NewScheduledExportRepository(dao dao.ScheduledExportDAO) ScheduledExportRepository {
+	return &scheduledExportRepository{
+		dao: dao,
+	}
+}
+
+type scheduledExportRepository struct {
+	dao dao.ScheduledExportDAO
+}
+
+func (repo *scheduledExportRepository) CreateScheduledExport(ctx context.Context,
+	se domain.ScheduledExport) (int64, error) {
+	return repo.dao.Create(ctx, repo.toEntity(se))
+}
+
+func (repo *scheduledExportRepository) UpdateScheduledExport(ctx context.Context,
+	se domain.ScheduledExport) (int64, error) {
+	return repo.dao.Update(ctx, repo.toEntity(se))
+}
+
+func (repo *scheduledExportRepository) DeleteScheduledExport(ctx context.Context, id int64) (int64, error) {
+	return repo.dao.Delete(ctx, id)
+}
+
+func (repo *scheduledExportRepository) FindScheduledExportById(ctx context.Context,
+	id int64) (domain.ScheduledExport, error) {
+	se, err := repo.dao.FindById(ctx, id)
+	return repo.toDomain(se), err
+}
+
+func (repo *scheduledExportRepository) ListScheduledExports(ctx context.Context,
+	offset, limit int64) ([]domain.ScheduledExport, error) {
+	ses, err := repo.dao.List(ctx, offset, limit)
+	return slice.Map(ses, func(idx int, src dao.ScheduledExport) domain.ScheduledExport {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *scheduledExportRepository) TotalScheduledExports(ctx context.Context) (int64, error) {
+	return repo.dao.Count(ctx)
+}
+
+func (repo *scheduledExportRepository) ListDueScheduledExports(ctx context.Context, now int64,
+	limit int64) ([]domain.ScheduledExport, error) {
+	ses, err := repo.dao.ListDue(ctx, now, limit)
+	return slice.Map(ses, func(idx int, src dao.ScheduledExport) domain.ScheduledExport {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *scheduledExportRepository) AdvanceScheduledExport(ctx context.Context, id int64,
+	from, next int64) (bool, error) {
+	return repo.dao.Advance(ctx, id, from, next)
+}
+
+func (repo *scheduledExportRepository) CreateScheduledExportRun(ctx context.Context,
+	run domain.ScheduledExportRun) (int64, error) {
+	return repo.dao.CreateRun(ctx, repo.toRunEntity(run))
+}
+
+func (repo *scheduledExportRepository) UpdateScheduledExportRun(ctx context.Context,
+	run domain.ScheduledExportRun) error {
+	return repo.dao.UpdateRun(ctx, repo.toRunEntity(run))
+}
+
+func (repo *scheduledExportRepository) FindScheduledExportRunById(ctx context.Context,
+	id int64) (domain.ScheduledExportRun, error) {
+	run, err := repo.dao.FindRunById(ctx, id)
+	return repo.toRunDomain(run), err
+}
+
+func (repo *scheduledExportRepository) ListScheduledExportRuns(ctx context.Context, scheduleId int64,
+	offset, limit int64) ([]domain.ScheduledExportRun, error) {
+	runs, err := repo.dao.ListRuns(ctx, scheduleId, offset, limit)
+	return slice.Map(runs, func(idx int, src dao.ScheduledExportRun) domain.ScheduledExportRun {
+		return repo.toRunDomain(src)
+	}), err
+}
+
+func (repo *scheduledExportRepository) TotalScheduledExportRuns(ctx context.Context,
+	scheduleId int64) (int64, error) {
+	return repo.dao.CountRuns(ctx, scheduleId)
+}
+
+func (repo *scheduledExportRepository) ListExpiredScheduledExportRuns(ctx context.Context, scheduleId int64,
+	before int64) ([]domain.ScheduledExportRun, error) {
+	runs, err := repo.dao.ListExpiredRuns(ctx, scheduleId, before)
+	return slice.Map(runs, func(idx int, src dao.ScheduledExportRun) domain.ScheduledExportRun {
+		return repo.toRunDomain(src)
+	}), err
+}
+
+func (repo *scheduledExportRepository) MarkScheduledExportRunsPruned(ctx context.Context, ids []int64) error {
+	return repo.dao.MarkRunsPruned(ctx, ids)
+}
+
+func (repo *scheduledExportRepository) FailStaleScheduledExportRuns(ctx context.Context, before int64,
+	message string) (int64, error) {
+	return repo.dao.FailStaleRuns(ctx, before, message)
+}
+
+func (repo *scheduledExportRepository) toEntity(req domain.ScheduledExport) dao.ScheduledExport {
+	return dao.ScheduledExport{
+		Id:       req.ID,
+		Name:     req.Name,
+		CronExpr: req.CronExpr,
+		Models: slice.Map(req.Models, func(idx int, src domain.ScheduledExportModel) dao.ScheduledExportModel {
+			return dao.ScheduledExportModel{
+				ModelUID:     src.ModelUID,
+				FilterGroups: toFilterGroupEntities(src.FilterGroups),
+				Fields:       src.Fields,
+			}
+		}),
+		Format:        string(req.Format),
+		Delimiter:     req.Delimiter,
+		Encoding:      req.Encoding,
+		RetentionDays: req.RetentionDays,
+		Enabled:       req.Enabled,
+		NextRunAt:     req.NextRunAt,
+		LastRunAt:     req.LastRunAt,
+		CreatorID:     req.CreatorID,
+	}
+}
+
+func (repo *scheduledExportRepository) toDomain(src dao.ScheduledExport) domain.ScheduledExport {
+	return domain.ScheduledExport{
+		ID:       src.Id,
+		TenantID: src.TenantID,
+		Name:     src.Name,
+		CronExpr: src.CronExpr,
+		Models: slice.Map(src.Models, func(idx int, src dao.ScheduledExportModel) domain.ScheduledExportModel {
+			return domain.ScheduledExportModel{
+				ModelUID:     src.ModelUID,
+				FilterGroups: toFilterGroupDomains(src.FilterGroups),
+				Fields:       src.Fields,
+			}
+		}),
+		FormatOptions: domain.FormatOptions{
+			Format:    domain.FileFormat(src.Format),
+			Delimiter: src.Delimiter,
+			Encoding:  src.Encoding,
+		},
+		RetentionDays: src.RetentionDays,
+		Enabled:       src.Enabled,
+		NextRunAt:     src.NextRunAt,
+		LastRunAt:     src.LastRunAt,
+		CreatorID:     src.CreatorID,
+		Ctime:         src.Ctime,
+		Utime:         src.Utime,
+	}
+}
+
+func (repo *scheduledExportRepository) toRunEntity(req domain.ScheduledExportRun) dao.ScheduledExportRun {
+	return dao.ScheduledExportRun{
+		Id:         req.ID,
+		ScheduleId: req.ScheduleID,
+		Status:     string(req.Status),
+		Files: slice.Map(req.Files, func(idx int, src domain.ScheduledExportFile) dao.ScheduledExportFile {
+			return dao.ScheduledExportFile{
+				ModelUID: src.ModelUID,
+				FileKey:  src.FileKey,
+				Rows:     src.Rows,
+				Size:     src.Size,
+				Message:  src.Message,
+			}
+		}),
+		Message: req.Message,
+		Pruned:  req.Pruned,
+	}
+}
+
+func (repo *scheduledExportRepository) toRunDomain(src dao.ScheduledExportRun) domain.ScheduledExportRun {
+	return domain.ScheduledExportRun{
+		ID:         src.Id,
+		TenantID:   src.TenantID,
+		ScheduleID: src.ScheduleId,
+		Status:     domain.ScheduledExportRunStatus(src.Status),
+		Files: slice.Map(src.Files, func(idx int, src dao.ScheduledExportFile) domain.ScheduledExportFile {
+			return domain.ScheduledExportFile{
+				ModelUID: src.ModelUID,
+				FileKey:  src.FileKey,
+				Rows:     src.Rows,
+				Size:     src.Size,
+				Message:  src.Message,
+			}
+		}),
+		Message: src.Message,
+		Pruned:  src.Pruned,
+		Ctime:   src.Ctime,
+		Utime:   src.Utime,
+		Ftime:   src.Ftime,
+	}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/Duke1616/ecmdb/pkg/searchx"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+)
+
+// SearchIndex 资产检索索引，只负责召回候选资产，相关度与高亮由服务层基于资产最新数据计算
+type SearchIndex interface {
+	// Search 检索当前租户的候选资产 ID，按 ID 倒序返回，limit 为候选数量上限
+	Search(ctx context.Context, modelUid string, terms []domain.SearchTerm, limit int64) ([]int64, error)
+
+	// NeedSync 索引是否独立于资产存储，需要通过变更日志同步
+	NeedSync() bool
+
+	// Put 写入或覆盖资产文档
+	Put(ctx context.Context, docs []domain.SearchDocument) error
+
+	// Delete 删除资产文档
+	Delete(ctx context.Context, ids []int64) error
+
+	// UnsetFields 抹除指定租户模型下全部资产文档的字段
+	UnsetFields(ctx context.Context, tenantID int64, modelUid string, fields []string) error
+
+	// Reset 清空索引及同步位点
+	Reset(ctx context.Context) error
+
+	// Checkpoint 已同步的变更日志位点（毫秒时间戳），为 0 时表示索引尚未建立
+	Checkpoint(ctx context.Context) (int64, error)
+
+	// Commit 持久化索引并记录同步位点
+	Commit(ctx context.Context, checkpoint int64) error
+}
+
+// NewEmbeddedSearchIndex 基于本地倒排索引的实现，每个服务实例各自维护一份索引
+func NewEmbeddedSearchIndex(idx *searchx.Index) SearchIndex {
+	return &embeddedSearchIndex{idx: idx}
+}
+
+type embeddedSearchIndex struct {
+	idx *searchx.Index
+}
+
+func (e *embeddedSearchIndex) Search(ctx context.Context, modelUid string, terms []domain.SearchTerm,
+	limit int64) ([]int64, error) {
+	tenantID := ctxutil.GetTenantID(ctx).Int64()
+	if tenantID < 0 {
+		tenantID = 0
+	}
+
+	return e.idx.Search(searchx.Query{
+		Tenant: tenantID,
+		Group:  modelUid,
+		Terms: lo.Map(terms, func(src domain.SearchTerm, _ int) searchx.Term {
+			return searchx.Term{
+				Field:    src.Field,
+				Literals: src.Literals(),
+				Pattern:  regexp.MustCompile("(?i)" + src.Pattern()),
+			}
+		}),
+		Limit: int(limit),
+	}), nil
+}
+
+func (e *embeddedSearchIndex) NeedSync() bool {
+	return true
+}
+
+func (e *embeddedSearchIndex) Put(_ context.Context, docs []domain.SearchDocument) error {
+	e.idx.Put(lo.Map(docs, func(src domain.SearchDocument, _ int) searchx.Document {
+		fields := make(map[string]string, len(src.Data))
+		for field, v := range src.Data {
+			if value, ok := domain.SearchableValue(v); ok && field != "_id" {
+				fields[field] = value
+			}
+		}
+		return searchx.Document{
+			ID:     src.ID,
+			Tenant: src.TenantID,
+			Group:  src.ModelUID,
+			Fields: fields,
+		}
+	})...)
+	return nil
+}
+
+func (e *embeddedSearchIndex) Delete(_ context.Context, ids []int64) error {
+	e.idx.Delete(ids...)
+	return nil
+}
+
+func (e *embeddedSearchIndex) UnsetFields(_ context.Context, tenantID int64, modelUid string, fields []string) error {
+	e.idx.DeleteFields(tenantID, modelUid, fields)
+	return nil
+}
+
+func (e *embeddedSearchIndex) Reset(_ context.Context) error {
+	e.idx.Reset()
+	return nil
+}
+
+func (e *embeddedSearchIndex) Checkpoint(_ context.Context) (int64, error) {
+	return e.idx.Checkpoint(), nil
+}
+
+func (e *embeddedSearchIndex) Commit(_ context.Context, checkpoint int64) error {
+	return e.idx.Save(checkpoint)
+}
+
+// NewMongoSearchIndex 直接在资产集合上执行正则检索，无需额外维护索引，适合数据量较小的部署
+// NOTE: 不指定字段的检索词无法命中索引，每次检索都会扫描模型（未指定模型时为整个集合）下的全部资产
+func NewMongoSearchIndex(dao dao.ResourceDAO) SearchIndex {
+	return &mongoSearchIndex{dao: dao}
+}
+
+type mongoSearchIndex struct {
+	dao dao.ResourceDAO
+}
+
+func (m *mongoSearchIndex) Search(ctx context.Context, modelUid string, terms []domain.SearchTerm,
+	limit int64) ([]int64, error) {
+	rs, err := m.dao.SearchResources(ctx, modelUid, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(rs, func(src dao.Resource, _ int) int64 {
+		return src.ID
+	}), nil
+}
+
+func (m *mongoSearchIndex) NeedSync() bool {
+	return false
+}
+
+func (m *mongoSearchIndex) Put(context.Context, []domain.SearchDocument) error {
+	return nil
+}
+
+func (m *mongoSearchIndex) Delete(context.Context, []int64) error {
+	return nil
+}
+
+func (m *mongoSearchIndex) UnsetFields(context.Context, int64, string, []string) error {
+	return nil
+}
+
+func (m *mongoSearchIndex) Reset(context.Context) error {
+	return nil
+}
+
+func (m *mongoSearchIndex) Checkpoint(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *mongoSearchIndex) Commit(context.Context, int64) error {
+	return nil
+}
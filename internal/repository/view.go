@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type SavedViewRepository interface {
+	// CreateView 创建视图
+	CreateView(ctx context.Context, view domain.SavedView) (int64, error)
+
+	// UpdateView 修改视图，只允许创建人修改
+	UpdateView(ctx context.Context, view domain.SavedView) (int64, error)
+
+	// DeleteView 删除视图，只允许创建人删除
+	DeleteView(ctx context.Context, id int64, ownerID int64) (int64, error)
+
+	// FindViewById 根据 ID 查询视图
+	FindViewById(ctx context.Context, id int64) (domain.SavedView, error)
+
+	// ListVisibleViews 查询模型下用户可见的视图
+	ListVisibleViews(ctx context.Context, modelUid string, userID int64) ([]domain.SavedView, error)
+
+	// SetDefaultView 设置用户在模型下的默认视图
+	SetDefaultView(ctx context.Context, userID int64, modelUid string, viewID int64) error
+
+	// ClearDefaultView 取消用户在模型下的默认视图
+	ClearDefaultView(ctx context.Context, userID int64, modelUid string) (int64, error)
+
+	// FindDefaultViewId 查询用户在模型下的默认视图 ID，未设置时返回 0
+	FindDefaultViewId(ctx context.Context, userID int64, modelUid string) (int64, error)
+}
+
+func NewSavedViewRepository(dao dao.SavedViewDAO) SavedViewRepository {
+	return &savedViewRepository{
+		dao: dao,
+	}
+}
+
+type savedViewRepository struct {
+	dao dao.SavedViewDAO
+}
+
+func (repo *savedViewRepository) CreateView(ctx context.Context, view domain.SavedView) (int64, error) {
+	return repo.dao.Create(ctx, repo.toEntity(view))
+}
+
+func (repo *savedViewRepository) UpdateView(ctx context.Context, view domain.SavedView) (int64, error) {
+	return repo.dao.Update(ctx, repo.toEntity(view))
+}
+
+func (repo *savedViewRepository) DeleteView(ctx context.Context, id int64, ownerID int64) (int64, error) {
+	return repo.dao.Delete(ctx, id, ownerID)
+}
+
+func (repo *savedViewRepository) FindViewById(ctx context.Context, id int64) (domain.SavedView, error) {
+	view, err := repo.dao.FindById(ctx, id)
+	return repo.toDomain(view), err
+}
+
+func (repo *savedViewRepository) ListVisibleViews(ctx context.Context, modelUid string,
+	userID int64) ([]domain.SavedView, error) {
+	views, err := repo.dao.ListVisible(ctx, modelUid, userID)
+	return slice.Map(views, func(idx int, src dao.SavedView) domain.SavedView {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *savedViewRepository) SetDefaultView(ctx context.Context, userID int64, modelUid string, viewID int64) error {
+	return repo.dao.SetDefault(ctx, userID, modelUid, viewID)
+}
+
+func (repo *savedViewRepository) ClearDefaultView(ctx context.Context, userID int64, modelUid string) (int64, error) {
+	return repo.dao.ClearDefault(ctx, userID, modelUid)
+}
+
+func (repo *savedViewRepository) FindDefaultViewId(ctx context.Context, userID int64, modelUid string) (int64, error) {
+	return repo.dao.FindDefault(ctx, userID, modelUid)
+}
+
+func (repo *savedViewRepository) toEntity(req domain.SavedView) dao.SavedView {
+	return dao.SavedView{
+		Id:           req.ID,
+		ModelUID:     req.ModelUID,
+		Name:         req.Name,
+		OwnerID:      req.OwnerID,
+		Scope:        string(req.Scope),
+		FilterGroups: toFilterGroupEntities(req.FilterGroups),
+		Fields:       req.Fields,
+		Sorts:        toSortEntities(req.Sorts),
+	}
+}
+
+func (repo *savedViewRepository) toDomain(src dao.SavedView) domain.SavedView {
+	return domain.SavedView{
+		ID:           src.Id,
+		ModelUID:     src.ModelUID,
+		Name:         src.Name,
+		OwnerID:      src.OwnerID,
+		Scope:        domain.ViewScope(src.Scope),
+		FilterGroups: toFilterGroupDomains(src.FilterGroups),
+		Fields:       src.Fields,
+		Sorts:        toSortDomains(src.Sorts),
+		Ctime:        src.Ctime,
+		Utime:        src.Utime,
+	}
+}
+
+// toFilterGroupEntities 筛选条件的存储结构，保存视图及导出任务共用
+func toFilterGroupEntities(groups []domain.FilterGroup) []dao.SavedViewFilterGroup {
+	return slice.Map(groups, func(idx int, src domain.FilterGroup) dao.SavedViewFilterGroup {
+		return dao.SavedViewFilterGroup{
+			Filters: slice.Map(src.Filters, func(idx int, src domain.FilterCondition) dao.SavedViewFilter {
+				return dao.SavedViewFilter{
+					FieldUID: src.FieldUID,
+					Operator: string(src.Operator),
+					Value:    src.Value,
+				}
+			}),
+		}
+	})
+}
+
+func toFilterGroupDomains(groups []dao.SavedViewFilterGroup) []domain.FilterGroup {
+	return slice.Map(groups, func(idx int, src dao.SavedViewFilterGroup) domain.FilterGroup {
+		return domain.FilterGroup{
+			Filters: slice.Map(src.Filters, func(idx int, src dao.SavedViewFilter) domain.FilterCondition {
+				return domain.FilterCondition{
+					FieldUID: src.FieldUID,
+					Operator: domain.Operator(src.Operator),
+					Value:    src.Value,
+				}
+			}),
+		}
+	})
+}
+
+func toSortEntities(sorts []domain.ResourceSort) []dao.SavedViewSort {
+	return slice.Map(sorts, func(idx int, src domain.ResourceSort) dao.SavedViewSort {
+		return dao.SavedViewSort{Field: src.Field, Desc: src.Desc}
+	})
+}
+
+func toSortDomains(sorts []dao.SavedViewSort) []domain.ResourceSort {
+	return slice.Map(sorts, func(idx int, src dao.SavedViewSort) domain.ResourceSort {
+		return domain.ResourceSort{Field: src.Field, Desc: src.Desc}
+	})
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/samber/lo"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+	"gopkg.in/yaml.v3"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ndjsonMaxLineSize NDJSON 单行最大长度
+const ndjsonMaxLineSize = 16 * 1024 * 1024
+
+// decodeText 将 CSV 文本转换为 UTF-8，并去除 BOM
+func decodeText(data []byte, encoding string) ([]byte, error) {
+	if encoding == domain.EncodingGBK {
+		out, err := simplifiedchinese.GBK.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("按 GBK 编码解析文件失败: %w", err)
+		}
+		return out, nil
+	}
+	return bytes.TrimPrefix(data, utf8BOM), nil
+}
+
+// writeRecords 将表头及数据行编码为 CSV、NDJSON 或 YAML
+// NOTE: CSV 第一行为字段 UID；NDJSON 及 YAML 以字段 UID 作为对象的键
+func writeRecords(opts domain.FormatOptions, headers []string, rows [][]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	rw, err := newRecordWriter(&buf, opts, headers)
+	if err != nil {
+		return nil, err
+	}
+	if err = rw.WriteRows(rows); err != nil {
+		return nil, err
+	}
+	if err = rw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rowWriter 按批写入导出数据行，Close 时补全文件结尾
+// NOTE: Close 不会关闭底层的 io.Writer
+type rowWriter interface {
+	WriteRows(rows [][]interface{}) error
+	Close() error
+}
+
+// newRecordWriter 创建 CSV、NDJSON 或 YAML 的流式写入器
+func newRecordWriter(w io.Writer, opts domain.FormatOptions, headers []string) (rowWriter, error) {
+	switch opts.Format {
+	case domain.FileFormatCSV:
+		return newCSVRowWriter(w, opts, headers)
+	case domain.FileFormatNDJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return &ndjsonRowWriter{enc: enc, headers: headers}, nil
+	case domain.FileFormatYAML:
+		return &yamlRowWriter{w: w, headers: headers}, nil
+	default:
+		return nil, fmt.Errorf("不支持的文件格式: %s", opts.Format)
+	}
+}
+
+type csvRowWriter struct {
+	csv     *csv.Writer
+	encoder io.WriteCloser // GBK 编码转换，其余编码为空
+}
+
+func newCSVRowWriter(w io.Writer, opts domain.FormatOptions, headers []string) (*csvRowWriter, error) {
+	rw := &csvRowWriter{}
+	switch opts.Encoding {
+	case domain.EncodingGBK:
+		rw.encoder = transform.NewWriter(w, simplifiedchinese.GBK.NewEncoder())
+		w = rw.encoder
+	case domain.EncodingUTF8BOM:
+		if _, err := w.Write(utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+
+	rw.csv = csv.NewWriter(w)
+	rw.csv.Comma = opts.Comma()
+	if err := rw.csv.Write(headers); err != nil {
+		return nil, rw.wrapErr(err)
+	}
+	return rw, nil
+}
+
+func (rw *csvRowWriter) WriteRows(rows [][]interface{}) error {
+	for _, row := range rows {
+		if err := rw.csv.Write(cellStrings(row)); err != nil {
+			return rw.wrapErr(err)
+		}
+	}
+	// NOTE: 每批刷新一次，避免缓冲区积压并尽早暴露编码错误
+	rw.csv.Flush()
+	return rw.wrapErr(rw.csv.Error())
+}
+
+func (rw *csvRowWriter) Close() error {
+	rw.csv.Flush()
+	if err := rw.csv.Error(); err != nil {
+		return rw.wrapErr(err)
+	}
+	if rw.encoder != nil {
+		return rw.wrapErr(rw.encoder.Close())
+	}
+	return nil
+}
+
+func (rw *csvRowWriter) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if rw.encoder != nil {
+		return fmt.Errorf("生成 GBK 编码的 CSV 文件失败，数据中可能包含 GBK 无法表示的字符: %w", err)
+	}
+	return fmt.Errorf("生成 CSV 文件失败: %w", err)
+}
+
+type ndjsonRowWriter struct {
+	enc     *json.Encoder
+	headers []string
+}
+
+func (rw *ndjsonRowWriter) WriteRows(rows [][]interface{}) error {
+	for _, row := range rows {
+		if err := rw.enc.Encode(toRecord(rw.headers, row)); err != nil {
+			return fmt.Errorf("生成 JSON 文件失败: %w", err)
+		}
+	}
+	return nil
+}
+
+func (rw *ndjsonRowWriter) Close() error {
+	return nil
+}
+
+// yamlRowWriter 逐个写入数组元素，拼接后仍为合法的 YAML 对象数组
+type yamlRowWriter struct {
+	w       io.Writer
+	headers []string
+	written bool
+}
+
+func (rw *yamlRowWriter) WriteRows(rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// NOTE: 每批编码为一个数组，逐批拼接数组元素，不产生多文档分隔符
+	enc := yaml.NewEncoder(rw.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(lo.Map(rows, func(row []interface{}, _ int) map[string]interface{} {
+		return toRecord(rw.headers, row)
+	})); err != nil {
+		return fmt.Errorf("生成 YAML 文件失败: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("生成 YAML 文件失败: %w", err)
+	}
+	rw.written = true
+	return nil
+}
+
+func (rw *yamlRowWriter) Close() error {
+	if rw.written {
+		return nil
+	}
+	// 没有数据时输出空数组
+	_, err := io.WriteString(rw.w, "[]\n")
+	return err
+}
+
+func toRecord(headers []string, row []interface{}) map[string]interface{} {
+	record := make(map[string]interface{}, len(headers))
+	for i, header := range headers {
+		if i < len(row) {
+			record[header] = row[i]
+		}
+	}
+	return record
+}
+
+func cellString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// importFieldResolver 将文件中的列名或键映射为模型字段，支持字段 UID 及字段名称
+func importFieldResolver(attrs []domain.Attribute) func(key string) (string, bool) {
+	byUid := lo.SliceToMap(attrs, func(attr domain.Attribute) (string, string) {
+		return attr.FieldUid, attr.FieldUid
+	})
+	byName := lo.SliceToMap(attrs, func(attr domain.Attribute) (string, string) {
+		return attr.FieldName, attr.FieldUid
+	})
+
+	return func(key string) (string, bool) {
+		if uid, ok := byUid[key]; ok {
+			return uid, true
+		}
+		uid, ok := byName[key]
+		return uid, ok
+	}
+}
+
+// importValue 统一导入值：标量转为字符串，与 Excel 导入保持一致，空值跳过
+func importValue(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return val, val != ""
+	case time.Time:
+		return val.Format(time.DateTime), true
+	case []interface{}, map[string]interface{}:
+		return val, true
+	default:
+		return fmt.Sprint(val), true
+	}
+}
+
+// parseCSVSheet 解析 CSV 文件，第一行为表头，数据从第二行开始
+func parseCSVSheet(fileData []byte, attrs []domain.Attribute, opts domain.FormatOptions) (importSheet, error) {
+	records, err := readCSVRecords(fileData, opts)
+	if err != nil {
+		return importSheet{}, err
+	}
+	if len(records) < 2 {
+		return importSheet{}, fmt.Errorf("CSV 文件格式错误,至少需要 1 行表头 + 1 行数据")
+	}
+
+	resolve := importFieldResolver(attrs)
+	sheet := importSheet{columns: make(map[string]int)}
+	for colIdx, header := range records[0] {
+		if fieldUid, ok := resolve(header); ok {
+			sheet.columns[fieldUid] = colIdx
+		}
+	}
+
+	for idx, cells := range records[1:] {
+		data := make(map[string]interface{})
+		for fieldUid, colIdx := range sheet.columns {
+			if colIdx < len(cells) && cells[colIdx] != "" {
+				data[fieldUid] = cells[colIdx]
+			}
+		}
+		if len(data) > 0 {
+			sheet.rows = append(sheet.rows, importRow{row: idx + 2, data: data})
+		}
+	}
+
+	return sheet, nil
+}
+
+// readCSVRecords 按分隔符及编码读取 CSV 文件的所有行
+func readCSVRecords(fileData []byte, opts domain.FormatOptions) ([][]string, error) {
+	text, err := decodeText(fileData, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(text))
+	r.Comma = opts.Comma()
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("解析 CSV 文件失败: %w", err)
+	}
+	return records, nil
+}
+
+// parseNDJSONSheet 解析 NDJSON 文件，行号即为数据所在的文件行
+func parseNDJSONSheet(fileData []byte, attrs []domain.Attribute) (importSheet, error) {
+	records, lines, err := readNDJSONRecords(fileData)
+	if err != nil {
+		return importSheet{}, err
+	}
+	return recordsToSheet(records, lines, attrs), nil
+}
+
+// readNDJSONRecords 读取 NDJSON 文件的所有对象及其所在的文件行
+func readNDJSONRecords(fileData []byte) ([]map[string]interface{}, []int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(fileData, utf8BOM)))
+	scanner.Buffer(make([]byte, 0, 64*1024), ndjsonMaxLineSize)
+
+	var (
+		records []map[string]interface{}
+		lines   []int
+	)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var record map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.UseNumber()
+		if err := dec.Decode(&record); err != nil {
+			return nil, nil, fmt.Errorf("第 %d 行不是合法的 JSON 对象: %w", line, err)
+		}
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("读取 JSON 文件失败: %w", err)
+	}
+
+	return records, lines, nil
+}
+
+// parseYAMLSheet 解析 YAML 对象数组，行号为对象在数组中的序号（从 1 开始）
+func parseYAMLSheet(fileData []byte, attrs []domain.Attribute) (importSheet, error) {
+	records, rows, err := readYAMLRecords(fileData)
+	if err != nil {
+		return importSheet{}, err
+	}
+	return recordsToSheet(records, rows, attrs), nil
+}
+
+// readYAMLRecords 读取 YAML 对象数组及各对象的序号
+func readYAMLRecords(fileData []byte) ([]map[string]interface{}, []int, error) {
+	var records []map[string]interface{}
+	if err := yaml.Unmarshal(fileData, &records); err != nil {
+		return nil, nil, fmt.Errorf("解析 YAML 文件失败，文件内容必须为对象数组: %w", err)
+	}
+
+	return records, lo.Map(records, func(_ map[string]interface{}, idx int) int {
+		return idx + 1
+	}), nil
+}
+
+func recordsToSheet(records []map[string]interface{}, rows []int, attrs []domain.Attribute) importSheet {
+	resolve := importFieldResolver(attrs)
+	sheet := importSheet{columns: make(map[string]int)}
+	for idx, record := range records {
+		data := make(map[string]interface{})
+		for key, value := range record {
+			fieldUid, ok := resolve(key)
+			if !ok {
+				continue
+			}
+			if v, ok := importValue(value); ok {
+				data[fieldUid] = v
+			}
+		}
+		if len(data) > 0 {
+			sheet.rows = append(sheet.rows, importRow{row: rows[idx], data: data})
+		}
+	}
+	return sheet
+}
+
+// csvImportReport 在原始 CSV 末尾追加导入结果列，保持原有分隔符及编码
+// headerRow 为表头所在行（从 1 开始），结果列的表头写在该行
+func csvImportReport(fileData []byte, opts domain.FormatOptions, headerRow int,
+	rowErrors []domain.ImportRowError) ([]byte, error) {
+	records, err := readCSVRecords(fileData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := importErrorMessages(rowErrors)
+	width := lo.Max(lo.Map(records, func(cells []string, _ int) int {
+		return len(cells)
+	}))
+	rows := lo.Map(records, func(cells []string, idx int) []interface{} {
+		row := make([]interface{}, width+1)
+		for i, cell := range cells {
+			row[i] = cell
+		}
+		row[width] = messages[idx+1]
+		if idx+1 == headerRow {
+			row[width] = importResultFieldUid
+		}
+		return row
+	})
+
+	return writeRecords(opts, cellStrings(rows[0]), rows[1:])
+}
+
+func cellStrings(row []interface{}) []string {
+	return lo.Map(row, func(v interface{}, _ int) string {
+		return cellString(v)
+	})
+}
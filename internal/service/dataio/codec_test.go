@@ -0,0 +1,162 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func gbk(t *testing.T, text string) []byte {
+	data, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(text))
+	require.NoError(t, err)
+	return data
+}
+
+func TestWriteRecords(t *testing.T) {
+	headers := []string{"name", "ip", "port"}
+	rows := [][]interface{}{
+		{"web01", "10.0.0.1", 80},
+		{"web02", "", nil},
+	}
+
+	testCases := []struct {
+		name string
+		opts domain.FormatOptions
+		want string
+	}{
+		{
+			name: "csv",
+			opts: domain.FormatOptions{Format: domain.FileFormatCSV, Delimiter: ";"},
+			want: "name;ip;port\nweb01;10.0.0.1;80\nweb02;;\n",
+		},
+		{
+			name: "ndjson",
+			opts: domain.FormatOptions{Format: domain.FileFormatNDJSON},
+			want: `{"ip":"10.0.0.1","name":"web01","port":80}` + "\n" +
+				`{"ip":"","name":"web02","port":null}` + "\n",
+		},
+		{
+			name: "yaml",
+			opts: domain.FormatOptions{Format: domain.FileFormatYAML},
+			want: "- ip: 10.0.0.1\n  name: web01\n  port: 80\n- ip: \"\"\n  name: web02\n  port: null\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := tc.opts.Normalize()
+			require.NoError(t, err)
+
+			data, err := writeRecords(opts, headers, rows)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(data))
+		})
+	}
+}
+
+func TestParseImportFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     domain.FormatOptions
+		data     func(t *testing.T) []byte
+		wantRows []importRow
+		wantErr  string
+	}{
+		{
+			name: "csv gbk with field names",
+			opts: domain.FormatOptions{Format: domain.FileFormatCSV, Delimiter: "\t", Encoding: domain.EncodingGBK},
+			data: func(t *testing.T) []byte {
+				return gbk(t, "名称\tIP\t备注\nweb01\t10.0.0.1\t测试\n\t\t\nweb02\t\t\n")
+			},
+			wantRows: []importRow{
+				{row: 2, data: map[string]interface{}{"name": "web01", "ip": "10.0.0.1"}},
+				{row: 4, data: map[string]interface{}{"name": "web02"}},
+			},
+		},
+		{
+			name: "csv utf-8 bom",
+			opts: domain.FormatOptions{Format: domain.FileFormatCSV, Encoding: domain.EncodingUTF8BOM},
+			data: func(t *testing.T) []byte {
+				return append(utf8BOM, []byte("name,env\nweb01,prod\n")...)
+			},
+			wantRows: []importRow{
+				{row: 2, data: map[string]interface{}{"name": "web01", "env": "prod"}},
+			},
+		},
+		{
+			name: "ndjson",
+			opts: domain.FormatOptions{Format: domain.FileFormatNDJSON},
+			data: func(t *testing.T) []byte {
+				return []byte(`{"name":"web01","ip":"10.0.0.1","unknown":1}` + "\n\n" + `{"名称":"web02","env":"test"}` + "\n")
+			},
+			wantRows: []importRow{
+				{row: 1, data: map[string]interface{}{"name": "web01", "ip": "10.0.0.1"}},
+				{row: 3, data: map[string]interface{}{"name": "web02", "env": "test"}},
+			},
+		},
+		{
+			name: "ndjson invalid line",
+			opts: domain.FormatOptions{Format: domain.FileFormatNDJSON},
+			data: func(t *testing.T) []byte {
+				return []byte(`{"name":"web01"}` + "\n" + `[1,2]` + "\n")
+			},
+			wantErr: "第 2 行",
+		},
+		{
+			name: "yaml",
+			opts: domain.FormatOptions{Format: domain.FileFormatYAML},
+			data: func(t *testing.T) []byte {
+				return []byte("- name: web01\n  ip: 10.0.0.1\n- name: web02\n  env: prod\n")
+			},
+			wantRows: []importRow{
+				{row: 1, data: map[string]interface{}{"name": "web01", "ip": "10.0.0.1"}},
+				{row: 2, data: map[string]interface{}{"name": "web02", "env": "prod"}},
+			},
+		},
+		{
+			name: "empty",
+			opts: domain.FormatOptions{Format: domain.FileFormatYAML},
+			data: func(t *testing.T) []byte {
+				return []byte("- unknown: 1\n")
+			},
+			wantErr: "没有有效的数据行",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := tc.opts.Normalize()
+			require.NoError(t, err)
+
+			sheet, err := parseImportFile(tc.data(t), importAttrs, opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRows, sheet.rows)
+		})
+	}
+}
+
+func TestCSVImportReport(t *testing.T) {
+	opts, err := domain.FormatOptions{Format: domain.FileFormatCSV, Delimiter: ";", Encoding: domain.EncodingGBK}.Normalize()
+	require.NoError(t, err)
+	report, err := buildImportReport(gbk(t, "name;ip\nweb01;\nweb02;10.0.0.2\n"), opts, 0, []domain.ImportRowError{
+		{Row: 2, FieldUid: "ip", Message: "必填字段 IP 不能为空"},
+	})
+	require.NoError(t, err)
+
+	text, err := decodeText(report, domain.EncodingGBK)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"name;ip;" + importResultFieldUid,
+		"web01;;必填字段 IP 不能为空",
+		"web02;10.0.0.2;",
+	}, "\n")+"\n", string(text))
+}
@@ -1,17 +1,22 @@
 package service
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sort"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/internal/repository"
 	attribute "github.com/Duke1616/ecmdb/internal/service/attribute"
 	model "github.com/Duke1616/ecmdb/internal/service/model"
+	relation "github.com/Duke1616/ecmdb/internal/service/relation"
 	resource "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/Duke1616/ecmdb/pkg/storage"
+	"github.com/gotomicro/ego/core/elog"
 	"github.com/samber/lo"
-	"github.com/xuri/excelize/v2"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -44,11 +49,19 @@ func sortAttributesByPriority(attrs []domain.Attribute) []domain.Attribute {
 	return sorted
 }
 
-// NOTE: dataIOService 实现数据交换功能,依赖三个模块的 Service
+// NOTE: dataIOService 实现数据交换功能,依赖模型、字段、资产及关联模块的 Service
 type dataIOService struct {
-	attrSvc  attribute.Service
-	resSvc   resource.EncryptedSvc
-	modelSvc model.Service
+	attrSvc   attribute.Service
+	resSvc    resource.EncryptedSvc
+	modelSvc  model.Service
+	rmSvc     relation.RelationModelService
+	rrSvc     relation.RelationResourceService
+	jobRepo   repository.ImportJobRepository
+	expRepo   repository.ExportJobRepository
+	mapRepo   repository.ImportMappingRepository
+	schedRepo repository.ScheduledExportRepository
+	storage   *storage.S3Storage
+	logger    *elog.Component
 }
 
 // NewService 创建数据交换服务实例
@@ -56,98 +69,82 @@ func NewService(
 	attrSvc attribute.Service,
 	resSvc resource.EncryptedSvc,
 	modelSvc model.Service,
-
+	rmSvc relation.RelationModelService,
+	rrSvc relation.RelationResourceService,
+	jobRepo repository.ImportJobRepository,
+	expRepo repository.ExportJobRepository,
+	mapRepo repository.ImportMappingRepository,
+	schedRepo repository.ScheduledExportRepository,
+	storage *storage.S3Storage,
 ) IDataIOService {
 	return &dataIOService{
-		attrSvc:  attrSvc,
-		resSvc:   resSvc,
-		modelSvc: modelSvc,
+		attrSvc:   attrSvc,
+		resSvc:    resSvc,
+		modelSvc:  modelSvc,
+		rmSvc:     rmSvc,
+		rrSvc:     rrSvc,
+		jobRepo:   jobRepo,
+		expRepo:   expRepo,
+		mapRepo:   mapRepo,
+		schedRepo: schedRepo,
+		storage:   storage,
+		logger:    elog.DefaultLogger,
 	}
 }
 
-// Import 批量导入资源实例 (Resource)
-func (s *dataIOService) Import(ctx context.Context, modelUID string, fileData []byte) (importedCount int, err error) {
-	// 1. 解析 Excel 文件
-	f, err := excelize.OpenReader(bytes.NewReader(fileData))
-	if err != nil {
-		return 0, fmt.Errorf("解析 Excel 文件失败: %w", err)
-	}
-	defer f.Close()
-
-	// 2. 获取 Attribute 定义
-	attrs, _, err := s.attrSvc.ListAttributes(ctx, modelUID)
-	if err != nil {
-		return 0, fmt.Errorf("获取模型字段定义失败: %w", err)
-	}
-	if len(attrs) == 0 {
-		return 0, fmt.Errorf("模型 %s 没有定义字段", modelUID)
-	}
+const (
+	// exportBatchSize 导出时游标每批读取的资产数量
+	exportBatchSize = 500
 
-	// 3. 读取第一个 sheet 的数据
-	sheetName := f.GetSheetName(0)
-	rows, err := f.GetRows(sheetName)
-	if err != nil {
-		return 0, fmt.Errorf("读取 Excel 数据失败: %w", err)
-	}
-	if len(rows) < 4 {
-		return 0, fmt.Errorf("excel 文件格式错误,至少需要 3 行表头 + 1 行数据")
-	}
+	// templateRows 导入模板中数据验证及单元格格式预留的行数
+	templateRows = 1000
+	// templateReferenceLimit 关联列下拉列表的资产数量上限
+	templateReferenceLimit = 5000
+)
 
-	// 4. 解析第二行表头(FieldUid),建立列索引映射
-	fieldUidRow := rows[1]              // 第二行是 FieldUid
-	colIndexMap := make(map[int]string) // 列索引 → FieldUid
+// errTooManyReferences 对端模型资产过多，不提供下拉列表
+var errTooManyReferences = errors.New("too many references")
 
-	for colIdx, fieldUid := range fieldUidRow {
-		if fieldUid != "" {
-			colIndexMap[colIdx] = fieldUid
-		}
-	}
+// exportPlan 校验通过的导出配置
+type exportPlan struct {
+	opts      domain.FormatOptions
+	sheetName string
+	attrs     []domain.Attribute // 按优先级排序后的导出字段
+	cols      []relatedColumn
+	query     domain.ResourceQuery
+}
 
-	// 5. 逐行读取并构建 Resource (从第 4 行开始,跳过 3 行表头)
-	resources := make([]domain.Resource, 0, len(rows)-3)
-	for _, row := range rows[3:] {
-		// 构建 Resource Data
-		data := make(map[string]interface{})
-		for colIdx, cellValue := range row {
-			if fieldUid, ok := colIndexMap[colIdx]; ok {
-				// 跳过空值
-				if cellValue == "" {
-					continue
-				}
-				data[fieldUid] = cellValue
-			}
-		}
+// fields 查询资产时需要返回的字段
+func (p exportPlan) fields() []string {
+	return lo.Map(p.attrs, func(attr domain.Attribute, _ int) string {
+		return attr.FieldUid
+	})
+}
 
-		// 跳过空行
-		if len(data) == 0 {
-			continue
-		}
+// exportProgress 每写入一批数据后回调，参数为累计的资产数量及数据行数
+type exportProgress func(processed, rows int) error
 
-		resources = append(resources, domain.Resource{
-			ModelUID: modelUID,
-			Data:     data,
-		})
+// Export 导出资源实例数据 (Resource)
+func (s *dataIOService) Export(ctx context.Context, req ExportParams, w io.Writer) error {
+	plan, err := s.prepareExport(ctx, req)
+	if err != nil {
+		return err
 	}
 
-	if len(resources) == 0 {
-		return 0, fmt.Errorf("没有有效的数据行")
-	}
+	return s.writeExport(ctx, plan, w, nil)
+}
 
-	// 6. 批量创建或更新 Resource
-	err = s.resSvc.BatchCreateOrUpdate(ctx, resources)
+// prepareExport 校验导出参数并加载模型、字段及关联配置，不读取资产数据
+func (s *dataIOService) prepareExport(ctx context.Context, req ExportParams) (exportPlan, error) {
+	opts, err := req.FormatOptions.Normalize()
 	if err != nil {
-		return 0, fmt.Errorf("批量创建或更新资源失败: %w", err)
+		return exportPlan{}, errs.ValidationError.WithMsg(err.Error())
 	}
 
-	return len(resources), nil
-}
-
-// Export 导出资源实例数据 (Resource)
-func (s *dataIOService) Export(ctx context.Context, req ExportParams) ([]byte, error) {
 	// 1. 获取数据定义
 	mdl, attrs, err := s.fetchModelAndAttributes(ctx, req.ModelUID)
 	if err != nil {
-		return nil, err
+		return exportPlan{}, err
 	}
 
 	// 2. 处理字段过滤: 如果请求指定了字段, 则只保留这些字段
@@ -157,39 +154,121 @@ func (s *dataIOService) Export(ctx context.Context, req ExportParams) ([]byte, e
 		})
 	}
 
-	// 3. 对字段进行排序 (根据 fieldPriority)
-	sortedAttrs := sortAttributesByPriority(attrs)
-
-	// 4. 提取最终的 FieldUIDs 用于查询
-	dstFields := lo.Map(sortedAttrs, func(attr domain.Attribute, _ int) string {
-		return attr.FieldUid
-	})
+	// 3. 解析关联资产导出配置
+	cols, err := s.resolveRelatedColumns(ctx, req.ModelUID, req.Relations)
+	if err != nil {
+		return exportPlan{}, err
+	}
 
-	var allResources []domain.Resource
-	offset := int64(0)
-	limit := int64(100)
+	return exportPlan{
+		opts:      opts,
+		sheetName: mdl.SheetName(),
+		// 对字段进行排序 (根据 fieldPriority)
+		attrs: sortAttributesByPriority(attrs),
+		cols:  cols,
+		query: domain.ResourceQuery{
+			ModelUID:     req.ModelUID,
+			IDs:          req.ResourceIDs,
+			FilterGroups: req.FilterGroups,
+			Sorts:        req.Sorts,
+		},
+	}, nil
+}
 
-	for {
-		resources, _, err1 := s.resSvc.ListResourcesWithFilters(ctx, dstFields, req.ModelUID, req.ResourceIDs, offset, limit, req.FilterGroups)
-		if err1 != nil {
-			return nil, fmt.Errorf("获取资源列表失败: %w", err1)
+// writeExport 以游标分批读取资产，逐批写入文件
+// NOTE: 内存占用只与批次大小有关，Excel 数据超过 16MB 时由 excelize 暂存到磁盘
+func (s *dataIOService) writeExport(ctx context.Context, plan exportPlan, w io.Writer, progress exportProgress) error {
+	var (
+		rw  rowWriter
+		err error
+	)
+	if plan.opts.Format == domain.FileFormatExcel {
+		builder := s.newExportBuilder(plan)
+		defer builder.Close()
+		rw = &excelRowWriter{builder: builder, w: w}
+	} else {
+		rw, err = newRecordWriter(w, plan.opts, exportHeaders(plan.attrs, plan.cols))
+		if err != nil {
+			return err
 		}
-		allResources = append(allResources, resources...)
+	}
+
+	var processed, rows int
+	err = s.resSvc.IterateResourcesByQuery(ctx, plan.fields(), plan.query, exportBatchSize,
+		func(resources []domain.Resource) error {
+			// NOTE: 按批加载关联资产，避免逐条查询
+			related, err1 := s.loadRelated(ctx, plan.cols, resources)
+			if err1 != nil {
+				return err1
+			}
+
+			batch := exportRows(plan.attrs, plan.cols, resources, related)
+			if err1 = rw.WriteRows(batch); err1 != nil {
+				return err1
+			}
 
-		if len(resources) < int(limit) {
-			break
+			processed, rows = processed+len(resources), rows+len(batch)
+			if progress != nil {
+				return progress(processed, rows)
+			}
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("导出资产数据失败: %w", err)
+	}
+
+	return rw.Close()
+}
+
+// newExportBuilder 创建流式 Excel 构建器，表头与导入模板一致
+func (s *dataIOService) newExportBuilder(plan exportPlan) *domain.StreamBuilder {
+	row1, row2, row3 := excelHeaders(plan.attrs, plan.cols)
+	builder := domain.NewStreamBuilder(plan.sheetName, row1, row2, row3)
+	addExportValidations(builder, plan.attrs)
+	return builder
+}
+
+// addExportValidations 为当前工作表的下拉字段添加数据验证
+// NOTE: 数据行数未知，下拉列表验证覆盖到工作表最后一行
+func addExportValidations(builder *domain.StreamBuilder, attrs []domain.Attribute) {
+	for colIdx, attr := range attrs {
+		if attr.NeedsValidation() {
+			builder.WithValidation(colIdx, attr.GetOptionStrings(), 4, 0)
 		}
-		offset += limit
 	}
+}
 
-	// 5. 构建 Excel
-	return s.buildExcel(mdl.SheetName(), sortedAttrs, allResources)
+// excelRowWriter 将数据行写入流式 Excel 构建器，Close 时输出完整文件
+type excelRowWriter struct {
+	builder *domain.StreamBuilder
+	w       io.Writer
+}
+
+func (rw *excelRowWriter) WriteRows(rows [][]interface{}) error {
+	return rw.builder.AddRows(rows)
+}
+
+func (rw *excelRowWriter) Close() error {
+	_, err := rw.builder.WriteTo(rw.w)
+	return err
 }
 
 // ExportTemplate 导出空白导入模板
-func (s *dataIOService) ExportTemplate(ctx context.Context, modelUID string) ([]byte, error) {
+func (s *dataIOService) ExportTemplate(ctx context.Context, req TemplateParams) ([]byte, error) {
+	opts, err := req.FormatOptions.Normalize()
+	if err != nil {
+		return nil, errs.ValidationError.WithMsg(err.Error())
+	}
+
 	// 1. 获取数据
-	mdl, attrs, err := s.fetchModelAndAttributes(ctx, modelUID)
+	mdl, attrs, err := s.fetchModelAndAttributes(ctx, req.ModelUID)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := s.resolveRelatedColumns(ctx, req.ModelUID, lo.Map(req.Relations,
+		func(name string, _ int) RelatedFields {
+			return RelatedFields{RelationName: name}
+		}))
 	if err != nil {
 		return nil, err
 	}
@@ -197,17 +276,69 @@ func (s *dataIOService) ExportTemplate(ctx context.Context, modelUID string) ([]
 	// 2. 按优先级排序字段
 	sortedAttrs := sortAttributesByPriority(attrs)
 
-	// 3. 构建 Excel (空数据)
-	return s.buildExcel(mdl.SheetName(), sortedAttrs, nil)
+	// 3. 构建模板 (空数据)
+	switch opts.Format {
+	case domain.FileFormatExcel:
+		references, er := s.templateReferences(ctx, cols)
+		if er != nil {
+			return nil, er
+		}
+		return s.buildExcel(mdl.SheetName(), sortedAttrs, cols, references)
+	case domain.FileFormatCSV:
+		return writeRecords(opts, exportHeaders(sortedAttrs, cols), nil)
+	default:
+		headers := exportHeaders(sortedAttrs, cols)
+		return writeRecords(opts, headers, [][]interface{}{
+			lo.Map(headers, func(string, int) interface{} { return "" }),
+		})
+	}
 }
 
-// buildExcel 构建 Excel 文件
-// NOTE: 通用方法,用于导出数据和导出模板
-func (s *dataIOService) buildExcel(sheetName string, attrs []domain.Attribute, resources []domain.Resource) ([]byte, error) {
-	// 1. 构建 3 行表头数据
-	row1 := make([]string, len(attrs)) // 字段约束
-	row2 := make([]string, len(attrs)) // 字段 UID
-	row3 := make([]string, len(attrs)) // 字段名称
+// templateReferences 查询关联列对端模型的资产名称，返回值与 cols 一一对应
+// NOTE: 对端资产超过 templateReferenceLimit 时不提供下拉列表，避免模板文件过大
+func (s *dataIOService) templateReferences(ctx context.Context, cols []relatedColumn) ([][]string, error) {
+	references := make([][]string, len(cols))
+	for i, col := range cols {
+		var names []string
+		err := s.resSvc.IterateResourcesByQuery(ctx, []string{"name"}, domain.ResourceQuery{ModelUID: col.model.UID},
+			exportBatchSize, func(resources []domain.Resource) error {
+				for _, res := range resources {
+					names = append(names, res.Name)
+				}
+				if len(names) > templateReferenceLimit {
+					return errTooManyReferences
+				}
+				return nil
+			})
+		switch {
+		case errors.Is(err, errTooManyReferences):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("查询模型 %s 的资产失败: %w", col.model.UID, err)
+		}
+		references[i] = lo.Uniq(lo.Compact(names))
+	}
+	return references, nil
+}
+
+// exportHeaders 非 Excel 格式的表头（或对象键），与 Excel 第二行表头一致
+func exportHeaders(attrs []domain.Attribute, cols []relatedColumn) []string {
+	headers := lo.Map(attrs, func(attr domain.Attribute, _ int) string {
+		return attr.FieldUid
+	})
+	for _, col := range cols {
+		for _, attr := range col.attrs {
+			headers = append(headers, col.fieldUid(attr))
+		}
+	}
+	return headers
+}
+
+// excelHeaders 构建 3 行表头数据：字段约束、字段 UID、字段名称
+func excelHeaders(attrs []domain.Attribute, cols []relatedColumn) (row1, row2, row3 []string) {
+	row1 = make([]string, len(attrs), len(attrs)+len(cols)) // 字段约束
+	row2 = make([]string, len(attrs), len(attrs)+len(cols)) // 字段 UID
+	row3 = make([]string, len(attrs), len(attrs)+len(cols)) // 字段名称
 
 	for i, attr := range attrs {
 		row1[i] = attr.GetConstraintDescription()
@@ -215,33 +346,50 @@ func (s *dataIOService) buildExcel(sheetName string, attrs []domain.Attribute, r
 		row3[i] = attr.FieldName
 	}
 
+	// 关联列追加在模型字段之后
+	for _, col := range cols {
+		for _, attr := range col.attrs {
+			row1 = append(row1, relatedConstraintDescription)
+			row2 = append(row2, col.fieldUid(attr))
+			row3 = append(row3, col.fieldName(attr))
+		}
+	}
+	return row1, row2, row3
+}
+
+// buildExcel 构建 Excel 导入模板
+// NOTE: 数据验证及单元格格式预留 templateRows 行，关联列的下拉列表为对端模型已有的资产名称
+func (s *dataIOService) buildExcel(sheetName string, attrs []domain.Attribute, cols []relatedColumn,
+	references [][]string) ([]byte, error) {
+	// 1. 构建 3 行表头数据
+	row1, row2, row3 := excelHeaders(attrs, cols)
+
 	// 2. 创建 Builder
 	builder := domain.NewBuilder(sheetName).
 		With3RowHeaders(row1, row2, row3)
 	defer builder.Close()
 
-	// 3. 填充数据
-	for _, res := range resources {
-		row := lo.Map(attrs, func(attr domain.Attribute, _ int) interface{} {
-			if val, ok := res.Data[attr.FieldUid]; ok {
-				return val
-			}
-			return ""
-		})
-		builder.AddRow(row...)
-	}
-
-	// 4. 添加数据验证(下拉列表)
-	// NOTE: 如果是导出模板,验证范围预留 1000 行
-	// 如果是导出数据,验证范围覆盖所有数据行 + 100 行缓冲
-	validationRows := 1000
-	if len(resources) > 0 {
-		validationRows = len(resources) + 100
-	}
-
+	// 3. 按字段类型添加数据验证、单元格格式、必填高亮及字段说明
+	endRow := importHeaderRows + templateRows
 	for colIdx, attr := range attrs {
 		if attr.NeedsValidation() {
-			builder.WithValidation(colIdx, attr.GetOptionStrings(), 4, validationRows)
+			builder.WithValidation(colIdx, attr.GetOptionStrings(), importHeaderRows+1, endRow)
+		}
+		builder.WithCellType(colIdx, attr.CellType(), importHeaderRows+1, endRow)
+		if attr.Required {
+			builder.WithRequired(colIdx)
+		}
+		builder.WithComment(colIdx, attr.Description)
+	}
+
+	// 4. 关联列的资产名称下拉列表
+	colIdx := len(attrs)
+	for i, col := range cols {
+		for _, attr := range col.attrs {
+			if attr.FieldUid == "name" {
+				builder.WithValidation(colIdx, references[i], importHeaderRows+1, endRow)
+			}
+			colIdx++
 		}
 	}
 
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gotomicro/ego/core/elog"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// exportPendingLimit 单次领取的待执行任务数量
+	exportPendingLimit = 5
+	// exportStaleTimeout 执行中任务超过该时间未刷新进度，视为实例退出导致中断
+	exportStaleTimeout = 10 * time.Minute
+	// exportFileExpire 导出文件下载链接有效期（秒）
+	exportFileExpire = 3600
+)
+
+func (s *dataIOService) SubmitExport(ctx context.Context, req ExportParams) (int64, error) {
+	// NOTE: 提交时完成参数校验，避免任务执行时才发现配置错误
+	plan, err := s.prepareExport(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.expRepo.CreateExportJob(ctx, domain.ExportJob{
+		ModelUID:     req.ModelUID,
+		ResourceIDs:  req.ResourceIDs,
+		FilterGroups: req.FilterGroups,
+		Fields:       req.Fields,
+		Sorts:        req.Sorts,
+		Relations: slice.Map(req.Relations, func(idx int, src RelatedFields) domain.ExportRelation {
+			return domain.ExportRelation{
+				RelationName: src.RelationName,
+				Fields:       src.Fields,
+				Mode:         src.Mode,
+				Separator:    src.Separator,
+			}
+		}),
+		FileName:      req.FileName,
+		FormatOptions: plan.opts,
+		Status:        domain.ExportJobPending,
+		CreatorID:     ctxutil.GetUserID(ctx).Int64(),
+	})
+}
+
+func (s *dataIOService) FindExportJob(ctx context.Context, id int64) (domain.ExportJob, error) {
+	return s.expRepo.FindExportJobById(ctx, id)
+}
+
+func (s *dataIOService) ListExportJobs(ctx context.Context, modelUID string, offset, limit int64) ([]domain.ExportJob,
+	int64, error) {
+	var (
+		eg    errgroup.Group
+		jobs  []domain.ExportJob
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		jobs, err = s.expRepo.ListExportJobs(ctx, modelUID, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.expRepo.TotalExportJobs(ctx, modelUID)
+		return err
+	})
+
+	return jobs, total, eg.Wait()
+}
+
+func (s *dataIOService) ExportFileURL(ctx context.Context, id int64) (string, error) {
+	job, err := s.expRepo.FindExportJobById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if job.Status != domain.ExportJobSucceeded {
+		return "", errs.ValidationError.WithMsg("导出任务尚未成功完成")
+	}
+
+	return s.storage.GenerateDownloadURL(ctx, dataBucket, job.FileKey, exportFileExpire)
+}
+
+func (s *dataIOService) RunExportJobs(ctx context.Context) (int, error) {
+	stale := time.Now().Add(-exportStaleTimeout).UnixMilli()
+	if _, err := s.expRepo.FailStaleExportJobs(ctx, stale, "导出任务执行中断，请重新提交"); err != nil {
+		return 0, err
+	}
+
+	jobs, err := s.expRepo.ListPendingExportJobs(ctx, exportPendingLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, job := range jobs {
+		// NOTE: 任务跨租户领取，执行时还原提交人所在的租户及用户身份
+		jobCtx := ctxutil.WithUserID(ctxutil.WithTenantID(ctx, job.TenantID), job.CreatorID)
+
+		claimed, er := s.expRepo.ClaimExportJob(jobCtx, job.ID)
+		if er != nil {
+			return count, er
+		}
+		if !claimed {
+			continue
+		}
+
+		s.runExportJob(jobCtx, job)
+		count++
+	}
+
+	return count, nil
+}
+
+// runExportJob 执行导出任务并记录结果，任务级错误记录在任务中，不向上返回
+func (s *dataIOService) runExportJob(ctx context.Context, job domain.ExportJob) {
+	job.Status = domain.ExportJobSucceeded
+	if err := s.executeExportJob(ctx, &job); err != nil {
+		job.Status, job.Message = domain.ExportJobFailed, err.Error()
+	}
+
+	if err := s.expRepo.FinishExportJob(ctx, job); err != nil {
+		s.logger.Error("记录导出任务结果失败", elog.FieldErr(err), elog.Int64("job_id", job.ID))
+	}
+}
+
+// executeExportJob 按任务配置导出并上传到对象存储
+func (s *dataIOService) executeExportJob(ctx context.Context, job *domain.ExportJob) error {
+	plan, err := s.prepareExport(ctx, ExportParams{
+		ModelUID:     job.ModelUID,
+		ResourceIDs:  job.ResourceIDs,
+		FilterGroups: job.FilterGroups,
+		Fields:       job.Fields,
+		Sorts:        job.Sorts,
+		Relations: slice.Map(job.Relations, func(idx int, src domain.ExportRelation) RelatedFields {
+			return RelatedFields{
+				RelationName: src.RelationName,
+				Fields:       src.Fields,
+				Mode:         src.Mode,
+				Separator:    src.Separator,
+			}
+		}),
+		FileName:      job.FileName,
+		FormatOptions: job.FormatOptions,
+	})
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("export/%s/%d_%s%s", time.Now().Format("2006-01-02"), job.ID, job.ModelUID,
+		plan.opts.Format.Ext())
+	size, err := s.uploadExport(ctx, plan, key, func(processed, rows int) error {
+		job.Processed, job.Rows = processed, rows
+		return s.expRepo.UpdateExportJobProgress(ctx, *job)
+	})
+	if err != nil {
+		return err
+	}
+
+	job.FileKey, job.Size = key, size
+	return nil
+}
+
+// uploadExport 边读取资产边写入文件，通过管道直接分片上传到对象存储，返回文件大小
+func (s *dataIOService) uploadExport(ctx context.Context, plan exportPlan, key string,
+	progress exportProgress) (int64, error) {
+	pr, pw := io.Pipe()
+
+	var eg errgroup.Group
+	eg.Go(func() error {
+		err := s.writeExport(ctx, plan, pw, progress)
+		// NOTE: 写入失败时上传端读取到错误，中止分片上传
+		pw.CloseWithError(err)
+		return err
+	})
+
+	size, err := s.storage.PutStream(ctx, dataBucket, key, pr, plan.opts.Format.ContentType())
+	// NOTE: 上传失败时写入端收到错误，停止读取资产
+	pr.CloseWithError(err)
+	if er := eg.Wait(); er != nil {
+		return 0, er
+	}
+	return size, err
+}
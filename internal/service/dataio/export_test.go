@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/mocks/resourcemocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWriteExport(t *testing.T) {
+	batches := [][]domain.Resource{
+		{
+			{ID: 1, Data: map[string]interface{}{"name": "web01", "ip": "10.0.0.1", "env": "prod"}},
+			{ID: 2, Data: map[string]interface{}{"name": "web02", "ip": "10.0.0.2"}},
+		},
+		{
+			{ID: 3, Data: map[string]interface{}{"name": "db01", "env": "test"}},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		format domain.FileFormat
+		verify func(t *testing.T, data []byte)
+	}{
+		{
+			name:   "csv",
+			format: domain.FileFormatCSV,
+			verify: func(t *testing.T, data []byte) {
+				assert.Equal(t, "name,ip,env\nweb01,10.0.0.1,prod\nweb02,10.0.0.2,\ndb01,,test\n", string(data))
+			},
+		},
+		{
+			name:   "excel",
+			format: domain.FileFormatExcel,
+			verify: func(t *testing.T, data []byte) {
+				f, err := excelize.OpenReader(bytes.NewReader(data))
+				require.NoError(t, err)
+				defer f.Close()
+
+				rows, err := f.GetRows("host")
+				require.NoError(t, err)
+				assert.Equal(t, [][]string{
+					{"唯一索引 | 必填", "必填", "由用户选择"},
+					{"name", "ip", "env"},
+					{"名称", "IP", "环境"},
+					{"web01", "10.0.0.1", "prod"},
+					{"web02", "10.0.0.2"},
+					{"db01", "", "test"},
+				}, rows)
+
+				// 导出文件可以直接回导
+				sheet, err := parseImportFile(data, importAttrs, excelFormat)
+				require.NoError(t, err)
+				assert.Len(t, sheet.rows, 3)
+
+				dvs, err := f.GetDataValidations("host")
+				require.NoError(t, err)
+				require.Len(t, dvs, 1)
+				assert.Equal(t, "C4:C1048576", dvs[0].Sqref)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			resSvc := resourcemocks.NewMockService(ctrl)
+			resSvc.EXPECT().IterateResourcesByQuery(gomock.Any(), []string{"name", "ip", "env"},
+				domain.ResourceQuery{ModelUID: "host"}, exportBatchSize, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ []string, _ domain.ResourceQuery, _ int,
+					fn func([]domain.Resource) error) error {
+					for _, batch := range batches {
+						if err := fn(batch); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+
+			svc := &dataIOService{resSvc: resSvc}
+			plan := exportPlan{
+				opts:      domain.FormatOptions{Format: tc.format},
+				sheetName: "host",
+				attrs:     importAttrs,
+				query:     domain.ResourceQuery{ModelUID: "host"},
+			}
+			plan.opts, _ = plan.opts.Normalize()
+
+			var (
+				buf      bytes.Buffer
+				progress [][2]int
+			)
+			err := svc.writeExport(context.Background(), plan, &buf, func(processed, rows int) error {
+				progress = append(progress, [2]int{processed, rows})
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, [][2]int{{2, 2}, {3, 3}}, progress)
+			tc.verify(t, buf.Bytes())
+		})
+	}
+}
+
+func TestWriteExport_IterateFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resSvc := resourcemocks.NewMockService(ctrl)
+	resSvc.EXPECT().IterateResourcesByQuery(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("cursor killed"))
+
+	svc := &dataIOService{resSvc: resSvc}
+	var buf bytes.Buffer
+	err := svc.writeExport(context.Background(), exportPlan{
+		opts:      domain.FormatOptions{Format: domain.FileFormatExcel},
+		sheetName: "host",
+		attrs:     importAttrs,
+	}, &buf, nil)
+	assert.ErrorContains(t, err, "cursor killed")
+	assert.Zero(t, buf.Len(), "出错时不输出不完整的 Excel 文件")
+}
+
+func TestTemplateReferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	many := make([]domain.Resource, templateReferenceLimit+1)
+	resSvc := resourcemocks.NewMockService(ctrl)
+	resSvc.EXPECT().IterateResourcesByQuery(gomock.Any(), []string{"name"}, gomock.Any(), exportBatchSize,
+		gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, query domain.ResourceQuery, _ int,
+			fn func([]domain.Resource) error) error {
+			if query.ModelUID == "idc" {
+				return fn([]domain.Resource{{Name: "idc-a"}, {Name: "idc-b"}, {Name: "idc-a"}})
+			}
+			return fn(many)
+		}).Times(2)
+
+	svc := &dataIOService{resSvc: resSvc}
+	references, err := svc.templateReferences(context.Background(), []relatedColumn{
+		{model: domain.Model{UID: "idc"}},
+		{model: domain.Model{UID: "switch"}},
+	})
+	require.NoError(t, err)
+	// 对端资产超过上限时不提供下拉列表
+	assert.Equal(t, [][]string{{"idc-a", "idc-b"}, nil}, references)
+}
+
+func TestBuildExcel(t *testing.T) {
+	attrs := []domain.Attribute{
+		{FieldUid: "name", FieldName: "名称", Required: true, Description: "主机名"},
+		{FieldUid: "cpu", FieldName: "CPU", FieldType: "number"},
+		{FieldUid: "env", FieldName: "环境", FieldType: "select", Option: []string{"prod", "test"}},
+	}
+	cols := []relatedColumn{{
+		relation: hostBelongIdc,
+		model:    domain.Model{UID: "idc", Name: "机房"},
+		attrs:    []domain.Attribute{{FieldUid: "name", FieldName: "名称"}},
+	}}
+
+	svc := &dataIOService{}
+	data, err := svc.buildExcel("host", attrs, cols, [][]string{{"idc-a", "idc-b"}})
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows("host")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "cpu", "env", "host_belong_idc.name"}, rows[1])
+
+	dvs, err := f.GetDataValidations("host")
+	require.NoError(t, err)
+	require.Len(t, dvs, 3)
+	assert.Equal(t, "B4:B1003", dvs[0].Sqref)
+	assert.Equal(t, "decimal", dvs[0].Type)
+	assert.Equal(t, `"prod,test"`, dvs[1].Formula1)
+	assert.Equal(t, "D4:D1003", dvs[2].Sqref)
+	assert.Equal(t, `"idc-a,idc-b"`, dvs[2].Formula1)
+
+	comments, err := f.GetComments("host")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "A3", comments[0].Cell)
+}
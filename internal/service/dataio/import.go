@@ -0,0 +1,323 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// importHeaderRows 导入文件的表头行数：字段约束、字段 UID、字段名称
+	importHeaderRows = 3
+	// importResultFieldUid 错误报告中追加的导入结果列，重新导入时不属于模型字段会被忽略
+	importResultFieldUid = "_import_result"
+)
+
+// importRow 导入文件中的一行数据
+type importRow struct {
+	row      int // 文件中的行号，从 1 开始；YAML 为对象序号
+	data     map[string]interface{}
+	problems []domain.ImportRowError // 解析阶段的错误，如列映射的转换失败
+}
+
+func (r importRow) name() string {
+	name, _ := r.data["name"].(string)
+	return strings.TrimSpace(name)
+}
+
+// importSheet 解析后的导入数据，只保留属于模型字段的列
+type importSheet struct {
+	columns map[string]int // FieldUid → 列索引，从 0 开始
+	rows    []importRow
+}
+
+// parseImportFile 按文件格式解析导入数据
+// NOTE: 各格式共用字段映射，不属于模型的列或键均被忽略，空行不计入数据行
+func parseImportFile(fileData []byte, attrs []domain.Attribute, opts domain.FormatOptions) (importSheet, error) {
+	var (
+		sheet importSheet
+		err   error
+	)
+	switch opts.Format {
+	case domain.FileFormatCSV:
+		sheet, err = parseCSVSheet(fileData, attrs, opts)
+	case domain.FileFormatNDJSON:
+		sheet, err = parseNDJSONSheet(fileData, attrs)
+	case domain.FileFormatYAML:
+		sheet, err = parseYAMLSheet(fileData, attrs)
+	default:
+		sheet, err = parseExcelSheet(fileData, attrs)
+	}
+	if err != nil {
+		return importSheet{}, err
+	}
+
+	if len(sheet.rows) == 0 {
+		return importSheet{}, fmt.Errorf("没有有效的数据行")
+	}
+	return sheet, nil
+}
+
+// parseExcelSheet 解析 Excel 文件的第一个 sheet，第二行表头为字段 UID，关联列被忽略
+func parseExcelSheet(fileData []byte, attrs []domain.Attribute) (importSheet, error) {
+	rows, err := readExcelRows(fileData)
+	if err != nil {
+		return importSheet{}, err
+	}
+	return excelRowsToSheet(rows, attrs)
+}
+
+// readExcelRows 读取 Excel 文件第一个 sheet 的所有行
+func readExcelRows(fileData []byte) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("解析 Excel 文件失败: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("读取 Excel 数据失败: %w", err)
+	}
+	return rows, nil
+}
+
+// excelRowsToSheet 按第二行表头的字段 UID 映射工作表数据，不属于模型字段的列被忽略
+func excelRowsToSheet(rows [][]string, attrs []domain.Attribute) (importSheet, error) {
+	if len(rows) <= importHeaderRows {
+		return importSheet{}, fmt.Errorf("excel 文件格式错误,至少需要 3 行表头 + 1 行数据")
+	}
+
+	fields := lo.SliceToMap(attrs, func(attr domain.Attribute) (string, struct{}) {
+		return attr.FieldUid, struct{}{}
+	})
+	sheet := importSheet{columns: make(map[string]int)}
+	for colIdx, fieldUid := range rows[1] {
+		if _, ok := fields[fieldUid]; ok {
+			sheet.columns[fieldUid] = colIdx
+		}
+	}
+
+	for idx, cells := range rows[importHeaderRows:] {
+		data := make(map[string]interface{})
+		for fieldUid, colIdx := range sheet.columns {
+			if colIdx < len(cells) && cells[colIdx] != "" {
+				data[fieldUid] = cells[colIdx]
+			}
+		}
+
+		// 跳过空行
+		if len(data) == 0 {
+			continue
+		}
+
+		sheet.rows = append(sheet.rows, importRow{
+			row:  idx + importHeaderRows + 1,
+			data: data,
+		})
+	}
+
+	return sheet, nil
+}
+
+// rowError 构造行级错误，字段列不存在或文件没有列（JSON、YAML）时不定位到单元格
+func (sh importSheet) rowError(row int, fieldUid string, format string, args ...any) domain.ImportRowError {
+	e := domain.ImportRowError{
+		Row:      row,
+		FieldUid: fieldUid,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if colIdx, ok := sh.columns[fieldUid]; ok {
+		e.Cell, _ = excelize.CoordinatesToCellName(colIdx+1, row)
+	}
+	return e
+}
+
+// importValidator 行级校验
+type importValidator struct {
+	sheet    importSheet
+	mode     domain.ImportMode
+	selects  []domain.Attribute
+	required []domain.Attribute
+	seen     map[string]int // 资产名称 → 首次出现的行号
+}
+
+func newImportValidator(sheet importSheet, attrs []domain.Attribute, mode domain.ImportMode) *importValidator {
+	return &importValidator{
+		sheet: sheet,
+		mode:  mode,
+		selects: lo.Filter(attrs, func(attr domain.Attribute, _ int) bool {
+			return attr.NeedsValidation()
+		}),
+		required: lo.Filter(attrs, func(attr domain.Attribute, _ int) bool {
+			return attr.Required
+		}),
+		seen: make(map[string]int),
+	}
+}
+
+// validate 校验与资产是否已存在无关的规则：唯一标识、文件内重复及下拉选项取值
+func (v *importValidator) validate(r importRow) []domain.ImportRowError {
+	if len(r.problems) > 0 {
+		return r.problems
+	}
+
+	name := r.name()
+	if name == "" {
+		return []domain.ImportRowError{v.sheet.rowError(r.row, "name", "唯一标识 name 不能为空")}
+	}
+
+	var problems []domain.ImportRowError
+	if first, ok := v.seen[name]; ok {
+		problems = append(problems, v.sheet.rowError(r.row, "name", "与第 %d 行的 name 重复", first))
+	} else {
+		v.seen[name] = r.row
+	}
+
+	for _, attr := range v.selects {
+		value, ok := r.data[attr.FieldUid].(string)
+		if ok && !lo.Contains(attr.GetOptionStrings(), value) {
+			problems = append(problems, v.sheet.rowError(r.row, attr.FieldUid,
+				"%s 的取值 %s 不在可选项中", attr.FieldName, value))
+		}
+	}
+
+	return problems
+}
+
+// classify 根据导入模式及资产是否已存在判断该行是新增还是修改
+// NOTE: 修改只覆盖文件中非空的字段，因此只有新增时校验必填字段
+func (v *importValidator) classify(r importRow, exists bool) (bool, []domain.ImportRowError) {
+	switch {
+	case exists && v.mode == domain.ImportModeCreate:
+		return false, []domain.ImportRowError{v.sheet.rowError(r.row, "name", "资产 %s 已存在", r.name())}
+	case !exists && v.mode == domain.ImportModeUpdate:
+		return false, []domain.ImportRowError{v.sheet.rowError(r.row, "name", "资产 %s 不存在", r.name())}
+	case exists:
+		return false, nil
+	}
+
+	return true, lo.FilterMap(v.required, func(attr domain.Attribute, _ int) (domain.ImportRowError, bool) {
+		_, ok := r.data[attr.FieldUid]
+		return v.sheet.rowError(r.row, attr.FieldUid, "必填字段 %s 不能为空", attr.FieldName), !ok
+	})
+}
+
+// buildImportReport 生成导入错误报告
+// NOTE: Excel 及 CSV 在原始文件上标注，修正后可直接重新导入；JSON 及 YAML 输出同格式的错误列表
+// headerRow 为按列映射导入时的表头行，为 0 时按导入模板的表头标注
+func buildImportReport(fileData []byte, opts domain.FormatOptions, headerRow int,
+	rowErrors []domain.ImportRowError) ([]byte, error) {
+	switch opts.Format {
+	case domain.FileFormatCSV:
+		return csvImportReport(fileData, opts, max(headerRow, 1), rowErrors)
+	case domain.FileFormatNDJSON, domain.FileFormatYAML:
+		return writeRecords(opts, []string{"row", "field_uid", "message"},
+			lo.Map(rowErrors, func(e domain.ImportRowError, _ int) []interface{} {
+				return []interface{}{e.Row, e.FieldUid, e.Message}
+			}))
+	default:
+		return annotateImportReport(fileData, headerRow, rowErrors)
+	}
+}
+
+// importErrorMessages 按行号合并错误信息
+func importErrorMessages(rowErrors []domain.ImportRowError) map[int]string {
+	return lo.MapValues(lo.GroupBy(rowErrors, func(e domain.ImportRowError) int {
+		return e.Row
+	}), func(problems []domain.ImportRowError, _ int) string {
+		return strings.Join(lo.Map(problems, func(e domain.ImportRowError, _ int) string {
+			return e.Message
+		}), "; ")
+	})
+}
+
+// annotateImportReport 在原始文件上标注错误：出错单元格标红并添加批注，末尾追加导入结果列
+// NOTE: 错误按 Sheet 定位到工作表，未指定工作表时标注在第一个工作表
+func annotateImportReport(fileData []byte, headerRow int, rowErrors []domain.ImportRowError) ([]byte, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("解析 Excel 文件失败: %w", err)
+	}
+	defer f.Close()
+
+	style, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"FDE2E2"}, Pattern: 1},
+		Font: &excelize.Font{Color: "F56C6C"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for sheet, problems := range lo.GroupBy(rowErrors, func(e domain.ImportRowError) string {
+		return lo.Ternary(e.Sheet == "", f.GetSheetName(0), e.Sheet)
+	}) {
+		if err = annotateSheet(f, sheet, style, headerRow, problems); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// annotateSheet 标注单个工作表中的错误，headerRow 为 0 时结果列按导入模板写入三行表头
+func annotateSheet(f *excelize.File, sheet string, style int, headerRow int,
+	rowErrors []domain.ImportRowError) error {
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return fmt.Errorf("读取 Excel 数据失败: %w", err)
+	}
+	resultCol := lo.Max(lo.Map(rows, func(cells []string, _ int) int {
+		return len(cells)
+	})) + 1
+
+	headers := map[int]string{2: importResultFieldUid, 3: "导入结果"}
+	if headerRow > 0 {
+		headers = map[int]string{headerRow: "导入结果"}
+	}
+	for row, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(resultCol, row)
+		if err = f.SetCellValue(sheet, cell, header); err != nil {
+			return err
+		}
+	}
+
+	for row, message := range importErrorMessages(rowErrors) {
+		cell, _ := excelize.CoordinatesToCellName(resultCol, row)
+		if err = f.SetCellValue(sheet, cell, message); err != nil {
+			return err
+		}
+		if err = f.SetCellStyle(sheet, cell, cell, style); err != nil {
+			return err
+		}
+	}
+
+	byCell := lo.GroupBy(lo.Filter(rowErrors, func(e domain.ImportRowError, _ int) bool {
+		return e.Cell != ""
+	}), func(e domain.ImportRowError) string {
+		return e.Cell
+	})
+	for cell, problems := range byCell {
+		if err = f.SetCellStyle(sheet, cell, cell, style); err != nil {
+			return err
+		}
+		if err = f.AddComment(sheet, excelize.Comment{
+			Author: "ecmdb",
+			Cell:   cell,
+			Text: strings.Join(lo.Map(problems, func(e domain.ImportRowError, _ int) string {
+				return e.Message
+			}), "\n"),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// dataBucket 导入导出文件及错误报告所在的存储桶
+	dataBucket = "ecmdb"
+	// importBatchSize 每批校验并写入的数据行数，每批结束后刷新一次进度
+	importBatchSize = 100
+	// importPendingLimit 单次领取的待执行任务数量
+	importPendingLimit = 10
+	// importStaleTimeout 执行中任务超过该时间未刷新进度，视为实例退出导致中断
+	importStaleTimeout = 10 * time.Minute
+	// importReportExpire 错误报告下载链接有效期（秒）
+	importReportExpire = 3600
+)
+
+func (s *dataIOService) SubmitImport(ctx context.Context, req ImportParams) (int64, error) {
+	if req.Mode == "" {
+		req.Mode = domain.ImportModeUpsert
+	}
+	if !req.Mode.Valid() {
+		return 0, errs.ValidationError.WithMsg(fmt.Sprintf("不支持的导入模式: %s", req.Mode))
+	}
+	if req.FileKey == "" {
+		return 0, errs.ValidationError.WithMsg("导入文件不能为空")
+	}
+	opts, err := req.FormatOptions.Normalize()
+	if err != nil {
+		return 0, errs.ValidationError.WithMsg(err.Error())
+	}
+
+	if req.Workbook {
+		if opts.Format != domain.FileFormatExcel {
+			return 0, errs.ValidationError.WithMsg("多模型工作簿只支持 Excel 格式")
+		}
+		req.ModelUID = ""
+	} else if _, err = s.modelSvc.GetByUid(ctx, req.ModelUID); err != nil {
+		return 0, fmt.Errorf("获取模型信息失败: %w", err)
+	}
+
+	// NOTE: 任务中保存映射快照，映射方案修改或删除后不影响已提交的任务
+	mapping, err := s.resolveImportMapping(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.jobRepo.CreateImportJob(ctx, domain.ImportJob{
+		ModelUID:      req.ModelUID,
+		FileKey:       req.FileKey,
+		FileName:      req.FileName,
+		FormatOptions: opts,
+		Workbook:      req.Workbook,
+		Mapping:       mapping,
+		Mode:          req.Mode,
+		DryRun:        req.DryRun,
+		Status:        domain.ImportJobPending,
+		CreatorID:     ctxutil.GetUserID(ctx).Int64(),
+	})
+}
+
+func (s *dataIOService) CommitImportJob(ctx context.Context, id int64) (int64, error) {
+	job, err := s.jobRepo.FindImportJobById(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if !job.DryRun {
+		return 0, errs.ValidationError.WithMsg("只有预演任务需要确认导入")
+	}
+	if job.Status != domain.ImportJobSucceeded {
+		return 0, errs.ValidationError.WithMsg("预演任务尚未成功完成")
+	}
+
+	return s.SubmitImport(ctx, ImportParams{
+		ModelUID:      job.ModelUID,
+		FileKey:       job.FileKey,
+		FileName:      job.FileName,
+		FormatOptions: job.FormatOptions,
+		Mode:          job.Mode,
+		Workbook:      job.Workbook,
+		Mapping:       job.Mapping,
+	})
+}
+
+func (s *dataIOService) FindImportJob(ctx context.Context, id int64) (domain.ImportJob, error) {
+	return s.jobRepo.FindImportJobById(ctx, id)
+}
+
+func (s *dataIOService) ListImportJobs(ctx context.Context, modelUID string, offset, limit int64) ([]domain.ImportJob,
+	int64, error) {
+	var (
+		eg    errgroup.Group
+		jobs  []domain.ImportJob
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		jobs, err = s.jobRepo.ListImportJobs(ctx, modelUID, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.jobRepo.TotalImportJobs(ctx, modelUID)
+		return err
+	})
+
+	return jobs, total, eg.Wait()
+}
+
+func (s *dataIOService) ImportReportURL(ctx context.Context, id int64) (string, error) {
+	job, err := s.jobRepo.FindImportJobById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if job.ReportKey == "" {
+		return "", errs.ValidationError.WithMsg("导入任务没有错误报告")
+	}
+
+	return s.storage.GenerateDownloadURL(ctx, dataBucket, job.ReportKey, importReportExpire)
+}
+
+func (s *dataIOService) RunImportJobs(ctx context.Context) (int, error) {
+	stale := time.Now().Add(-importStaleTimeout).UnixMilli()
+	if _, err := s.jobRepo.FailStaleImportJobs(ctx, stale, "导入任务执行中断，请重新提交"); err != nil {
+		return 0, err
+	}
+
+	jobs, err := s.jobRepo.ListPendingImportJobs(ctx, importPendingLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, job := range jobs {
+		// NOTE: 任务跨租户领取，执行时还原提交人所在的租户及用户身份
+		jobCtx := ctxutil.WithUserID(ctxutil.WithTenantID(ctx, job.TenantID), job.CreatorID)
+
+		claimed, er := s.jobRepo.ClaimImportJob(jobCtx, job.ID)
+		if er != nil {
+			return count, er
+		}
+		if !claimed {
+			continue
+		}
+
+		s.runImportJob(jobCtx, job)
+		count++
+	}
+
+	return count, nil
+}
+
+// runImportJob 执行导入任务并记录结果，任务级错误记录在任务中，不向上返回
+func (s *dataIOService) runImportJob(ctx context.Context, job domain.ImportJob) {
+	job.Status = domain.ImportJobSucceeded
+	if err := s.executeImportJob(ctx, &job); err != nil {
+		job.Status, job.Message = domain.ImportJobFailed, err.Error()
+	}
+
+	if err := s.jobRepo.FinishImportJob(ctx, job); err != nil {
+		s.logger.Error("记录导入任务结果失败", elog.FieldErr(err), elog.Int64("job_id", job.ID))
+	}
+}
+
+func (s *dataIOService) executeImportJob(ctx context.Context, job *domain.ImportJob) error {
+	fileData, err := s.storage.GetFile(ctx, dataBucket, job.FileKey)
+	if err != nil {
+		return err
+	}
+	if job.Workbook {
+		return s.executeWorkbookImport(ctx, job, fileData)
+	}
+
+	attrs, _, err := s.attrSvc.ListAttributes(ctx, job.ModelUID)
+	if err != nil {
+		return fmt.Errorf("获取模型字段定义失败: %w", err)
+	}
+	if len(attrs) == 0 {
+		return fmt.Errorf("模型 %s 没有定义字段", job.ModelUID)
+	}
+
+	// NOTE: 历史任务未记录文件格式，默认为 Excel
+	opts, err := job.FormatOptions.Normalize()
+	if err != nil {
+		return err
+	}
+	sheet, err := s.parseJobFile(job, fileData, attrs, opts)
+	if err != nil {
+		return err
+	}
+
+	job.Total = len(sheet.rows)
+	validator := newImportValidator(sheet, attrs, job.Mode)
+
+	var rowErrors []domain.ImportRowError
+	for _, batch := range lo.Chunk(sheet.rows, importBatchSize) {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		rowErrors = append(rowErrors, s.importBatch(ctx, job, validator, batch)...)
+		job.Processed += len(batch)
+		if err = s.jobRepo.UpdateImportJobProgress(ctx, *job); err != nil {
+			return err
+		}
+	}
+
+	return s.finishImport(ctx, job, opts, fileData, rowErrors)
+}
+
+// parseJobFile 解析导入文件，任务指定了列映射时按映射解析
+func (s *dataIOService) parseJobFile(job *domain.ImportJob, fileData []byte, attrs []domain.Attribute,
+	opts domain.FormatOptions) (importSheet, error) {
+	if job.Mapping == nil {
+		return parseImportFile(fileData, attrs, opts)
+	}
+	return parseMappedImportFile(fileData, attrs, opts, *job.Mapping)
+}
+
+// finishImport 汇总行级错误，存在错误时生成并上传错误报告
+func (s *dataIOService) finishImport(ctx context.Context, job *domain.ImportJob, opts domain.FormatOptions,
+	fileData []byte, rowErrors []domain.ImportRowError) error {
+	// NOTE: 工作簿导入时按工作表的导入顺序排列，同一工作表内按行号排列
+	sheets := make(map[string]int)
+	for _, e := range rowErrors {
+		if _, ok := sheets[e.Sheet]; !ok {
+			sheets[e.Sheet] = len(sheets)
+		}
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		if si, sj := sheets[rowErrors[i].Sheet], sheets[rowErrors[j].Sheet]; si != sj {
+			return si < sj
+		}
+		return rowErrors[i].Row < rowErrors[j].Row
+	})
+	job.Failed = len(lo.UniqBy(rowErrors, func(e domain.ImportRowError) string {
+		return fmt.Sprintf("%s:%d", e.Sheet, e.Row)
+	}))
+	job.Errors = lo.Slice(rowErrors, 0, domain.MaxImportJobErrors)
+
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	var err error
+	job.ReportKey, err = s.uploadImportReport(ctx, *job, opts, fileData, rowErrors)
+	return err
+}
+
+// importBatch 校验并写入一批数据行，返回该批次的行级错误
+// NOTE: 出错的行被跳过，不影响同批次其他行的写入
+func (s *dataIOService) importBatch(ctx context.Context, job *domain.ImportJob, validator *importValidator,
+	batch []importRow) []domain.ImportRowError {
+	var rowErrors []domain.ImportRowError
+	valid := lo.Filter(batch, func(r importRow, _ int) bool {
+		problems := validator.validate(r)
+		rowErrors = append(rowErrors, problems...)
+		return len(problems) == 0
+	})
+
+	existing, err := s.resSvc.ListExistingNames(ctx, job.ModelUID, lo.Map(valid, func(r importRow, _ int) string {
+		return r.name()
+	}))
+	if err != nil {
+		return append(rowErrors, batchImportErrors(valid, fmt.Errorf("查询已存在资产失败: %w", err))...)
+	}
+	exists := lo.SliceToMap(existing, func(name string) (string, struct{}) {
+		return name, struct{}{}
+	})
+
+	var (
+		inserted, updated int
+		resources         []domain.Resource
+	)
+	for _, r := range valid {
+		_, ok := exists[r.name()]
+		insert, problems := validator.classify(r, ok)
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, problems...)
+			continue
+		}
+
+		r.data["name"] = r.name()
+		resources = append(resources, domain.Resource{ModelUID: job.ModelUID, Data: r.data})
+		if insert {
+			inserted++
+		} else {
+			updated++
+		}
+	}
+
+	if !job.DryRun && len(resources) > 0 {
+		if err = s.resSvc.BatchCreateOrUpdate(ctx, resources); err != nil {
+			failed := lo.Filter(valid, func(r importRow, _ int) bool {
+				return !lo.ContainsBy(rowErrors, func(e domain.ImportRowError) bool { return e.Row == r.row })
+			})
+			return append(rowErrors, batchImportErrors(failed, fmt.Errorf("批量写入资产失败: %w", err))...)
+		}
+	}
+
+	job.Inserted += inserted
+	job.Updated += updated
+	return rowErrors
+}
+
+// uploadImportReport 生成标注错误的 Excel 并上传，返回文件 key
+func (s *dataIOService) uploadImportReport(ctx context.Context, job domain.ImportJob, opts domain.FormatOptions,
+	fileData []byte, rowErrors []domain.ImportRowError) (string, error) {
+	headerRow := 0
+	if job.Mapping != nil {
+		headerRow = max(job.Mapping.HeaderRow, 1)
+	}
+	report, err := buildImportReport(fileData, opts, headerRow, rowErrors)
+	if err != nil {
+		return "", fmt.Errorf("生成导入错误报告失败: %w", err)
+	}
+
+	key := fmt.Sprintf("import/report/%s/%d_%s_report%s", time.Now().Format("2006-01-02"), job.ID,
+		lo.Ternary(job.Workbook, "workbook", job.ModelUID), opts.Format.Ext())
+	if err = s.storage.PutFile(ctx, dataBucket, key, report, opts.Format.ContentType()); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// batchImportErrors 批次级失败时为每一行记录相同的错误
+func batchImportErrors(rows []importRow, err error) []domain.ImportRowError {
+	return lo.Map(rows, func(r importRow, _ int) domain.ImportRowError {
+		return domain.ImportRowError{Row: r.row, Message: err.Error()}
+	})
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	resourcemocks "github.com/Duke1616/ecmdb/internal/mocks/resourcemocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+var importAttrs = []domain.Attribute{
+	{FieldUid: "name", FieldName: "名称", Required: true},
+	{FieldUid: "ip", FieldName: "IP", Required: true},
+	{FieldUid: "env", FieldName: "环境", FieldType: "select", Option: []string{"prod", "test"}},
+}
+
+var excelFormat = domain.FormatOptions{Format: domain.FileFormatExcel}
+
+func importFile(t *testing.T, rows ...[]interface{}) []byte {
+	builder := domain.NewBuilder("host").
+		With3RowHeaders([]string{"", "", "", ""}, []string{"name", "ip", "env", "unknown"},
+			[]string{"名称", "IP", "环境", "未知"})
+	defer builder.Close()
+
+	builder.AddRows(rows)
+	data, err := builder.ToBytes()
+	require.NoError(t, err)
+	return data
+}
+
+func TestImportBatch(t *testing.T) {
+	data := importFile(t,
+		[]interface{}{"web01", "10.0.0.1", "prod", "ignored"},
+		[]interface{}{"web02", "", "dev"},
+		[]interface{}{"", "10.0.0.3"},
+		[]interface{}{},
+		[]interface{}{"web01", "10.0.0.5"},
+		[]interface{}{"db01", ""},
+		[]interface{}{"web06", ""},
+	)
+
+	sheet, err := parseImportFile(data, importAttrs, excelFormat)
+	require.NoError(t, err)
+	assert.Len(t, sheet.rows, 6, "空行不计入数据行")
+	assert.NotContains(t, sheet.rows[0].data, "unknown", "不属于模型的列被忽略")
+
+	testCases := []struct {
+		name       string
+		mode       domain.ImportMode
+		dryRun     bool
+		writes     int
+		wantCells  []string
+		wantInsert int
+		wantUpdate int
+	}{
+		{
+			name:       "upsert 只新增时校验必填字段",
+			mode:       domain.ImportModeUpsert,
+			writes:     1,
+			wantCells:  []string{"C5", "A6", "A8", "B10"},
+			wantInsert: 1,
+			wantUpdate: 1,
+		},
+		{
+			name:       "create 模式已存在的资产报错",
+			mode:       domain.ImportModeCreate,
+			writes:     1,
+			wantCells:  []string{"C5", "A6", "A8", "A9", "B10"},
+			wantInsert: 1,
+		},
+		{
+			name:       "update 模式不存在的资产报错且预演不写入",
+			mode:       domain.ImportModeUpdate,
+			dryRun:     true,
+			wantCells:  []string{"A4", "C5", "A6", "A8", "A10"},
+			wantUpdate: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			resSvc := resourcemocks.NewMockService(ctrl)
+			resSvc.EXPECT().ListExistingNames(gomock.Any(), "host", []string{"web01", "db01", "web06"}).
+				Return([]string{"db01"}, nil)
+			resSvc.EXPECT().BatchCreateOrUpdate(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, rs []domain.Resource) error {
+					assert.Len(t, rs, tc.wantInsert+tc.wantUpdate)
+					return nil
+				}).Times(tc.writes)
+
+			svc := &dataIOService{resSvc: resSvc}
+			job := &domain.ImportJob{ModelUID: "host", Mode: tc.mode, DryRun: tc.dryRun}
+			rowErrors := svc.importBatch(context.Background(), job, newImportValidator(sheet, importAttrs, tc.mode),
+				sheet.rows)
+
+			cells := make([]string, 0, len(rowErrors))
+			for _, e := range rowErrors {
+				cells = append(cells, e.Cell)
+			}
+			assert.ElementsMatch(t, tc.wantCells, cells)
+			assert.Equal(t, tc.wantInsert, job.Inserted)
+			assert.Equal(t, tc.wantUpdate, job.Updated)
+		})
+	}
+}
+
+func TestImportBatch_WriteFailed(t *testing.T) {
+	sheet, err := parseImportFile(importFile(t, []interface{}{"web01", "10.0.0.1"}), importAttrs, excelFormat)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resSvc := resourcemocks.NewMockService(ctrl)
+	resSvc.EXPECT().ListExistingNames(gomock.Any(), "host", gomock.Any()).Return(nil, nil)
+	resSvc.EXPECT().BatchCreateOrUpdate(gomock.Any(), gomock.Any()).Return(errors.New("mongo down"))
+
+	svc := &dataIOService{resSvc: resSvc}
+	job := &domain.ImportJob{ModelUID: "host", Mode: domain.ImportModeUpsert}
+	rowErrors := svc.importBatch(context.Background(), job,
+		newImportValidator(sheet, importAttrs, job.Mode), sheet.rows)
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 4, rowErrors[0].Row)
+	assert.Contains(t, rowErrors[0].Message, "mongo down")
+	assert.Zero(t, job.Inserted)
+}
+
+func TestAnnotateImportReport(t *testing.T) {
+	data := importFile(t, []interface{}{"web01", "", "dev"})
+
+	report, err := annotateImportReport(data, 0, []domain.ImportRowError{
+		{Row: 4, Cell: "B4", FieldUid: "ip", Message: "必填字段 IP 不能为空"},
+		{Row: 4, Cell: "C4", FieldUid: "env", Message: "环境 的取值 dev 不在可选项中"},
+	})
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(bytes.NewReader(report))
+	require.NoError(t, err)
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+	header, _ := f.GetCellValue(sheet, "E2")
+	assert.Equal(t, importResultFieldUid, header)
+	result, _ := f.GetCellValue(sheet, "E4")
+	assert.Equal(t, "必填字段 IP 不能为空; 环境 的取值 dev 不在可选项中", result)
+
+	comments, err := f.GetComments(sheet)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"B4", "C4"}, []string{comments[0].Cell, comments[1].Cell})
+
+	// 错误报告修正后可以直接重新导入，结果列不属于模型字段
+	parsed, err := parseImportFile(report, importAttrs, excelFormat)
+	require.NoError(t, err)
+	assert.NotContains(t, parsed.rows[0].data, importResultFieldUid)
+}
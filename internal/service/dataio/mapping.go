@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+)
+
+const (
+	// mappingMatchThreshold 列名与字段的相似度达到该值才给出映射建议
+	mappingMatchThreshold = 0.5
+	// mappingContainsScore 列名与字段互相包含时的相似度
+	mappingContainsScore = 0.8
+	// headerDetectRows 识别表头时扫描的行数
+	headerDetectRows = 10
+	// previewSampleRows 预览时返回的数据行数
+	previewSampleRows = 5
+)
+
+// headerNormalizer 比较列名时忽略大小写、空白及常见分隔符
+var headerNormalizer = strings.NewReplacer(" ", "", "\t", "", "_", "", "-", "", ".", "")
+
+func (s *dataIOService) CreateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error) {
+	if err := s.validateMapping(ctx, mapping); err != nil {
+		return 0, err
+	}
+
+	mapping.CreatorID = ctxutil.GetUserID(ctx).Int64()
+	return s.mapRepo.CreateImportMapping(ctx, mapping)
+}
+
+func (s *dataIOService) UpdateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error) {
+	old, err := s.mapRepo.FindImportMappingById(ctx, mapping.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// 映射方案所属模型不允许变更
+	mapping.ModelUID = old.ModelUID
+	if err = s.validateMapping(ctx, mapping); err != nil {
+		return 0, err
+	}
+
+	return s.mapRepo.UpdateImportMapping(ctx, mapping)
+}
+
+func (s *dataIOService) DeleteImportMapping(ctx context.Context, id int64) (int64, error) {
+	return s.mapRepo.DeleteImportMapping(ctx, id)
+}
+
+func (s *dataIOService) ListImportMappings(ctx context.Context, modelUID string) ([]domain.ImportMapping, error) {
+	return s.mapRepo.ListImportMappings(ctx, modelUID)
+}
+
+func (s *dataIOService) PreviewImportMapping(ctx context.Context, req MappingPreviewParams) (
+	domain.ImportMappingPreview, error) {
+	opts, err := req.FormatOptions.Normalize()
+	if err != nil {
+		return domain.ImportMappingPreview{}, errs.ValidationError.WithMsg(err.Error())
+	}
+	if req.FileKey == "" {
+		return domain.ImportMappingPreview{}, errs.ValidationError.WithMsg("导入文件不能为空")
+	}
+
+	attrs, _, err := s.attrSvc.ListAttributes(ctx, req.ModelUID)
+	if err != nil {
+		return domain.ImportMappingPreview{}, fmt.Errorf("获取模型字段定义失败: %w", err)
+	}
+	fileData, err := s.storage.GetFile(ctx, dataBucket, req.FileKey)
+	if err != nil {
+		return domain.ImportMappingPreview{}, err
+	}
+
+	preview, err := previewImportFile(fileData, opts, req.HeaderRow, attrs)
+	if err != nil {
+		return domain.ImportMappingPreview{}, errs.ValidationError.WithMsg(err.Error())
+	}
+	return preview, nil
+}
+
+// validateMapping 校验映射方案名称及配置，映射的字段必须属于模型
+func (s *dataIOService) validateMapping(ctx context.Context, mapping domain.ImportMapping) error {
+	if strings.TrimSpace(mapping.Name) == "" {
+		return errs.ValidationError.WithMsg("映射方案名称不能为空")
+	}
+	if mapping.ModelUID == "" {
+		return errs.ValidationError.WithMsg("模型唯一标识不能为空")
+	}
+	return s.validateMappingColumns(ctx, mapping)
+}
+
+// validateMappingColumns 校验列映射及转换规则，映射的字段必须属于模型
+func (s *dataIOService) validateMappingColumns(ctx context.Context, mapping domain.ImportMapping) error {
+	if err := mapping.Validate(); err != nil {
+		return errs.ValidationError.WithMsg(err.Error())
+	}
+
+	fields, err := s.attrSvc.SearchAttributeFieldsByModelUid(ctx, mapping.ModelUID)
+	if err != nil {
+		return err
+	}
+	if unknown, _ := lo.Difference(lo.Map(mapping.Columns, func(col domain.ColumnMapping, _ int) string {
+		return col.FieldUid
+	}), fields); len(unknown) > 0 {
+		return errs.ValidationError.WithMsg(fmt.Sprintf("映射了不存在的字段: %s", strings.Join(unknown, ",")))
+	}
+	return nil
+}
+
+// resolveImportMapping 解析导入任务使用的映射：MappingID 引用已保存的方案，否则使用请求中的临时映射
+func (s *dataIOService) resolveImportMapping(ctx context.Context, req ImportParams) (*domain.ImportMapping, error) {
+	if req.MappingID == 0 && req.Mapping == nil {
+		return nil, nil
+	}
+	if req.Workbook {
+		return nil, errs.ValidationError.WithMsg("多模型工作簿导入不支持列映射")
+	}
+
+	if req.MappingID == 0 {
+		mapping := *req.Mapping
+		mapping.ModelUID = req.ModelUID
+		if err := s.validateMappingColumns(ctx, mapping); err != nil {
+			return nil, err
+		}
+		return &mapping, nil
+	}
+
+	mapping, err := s.mapRepo.FindImportMappingById(ctx, req.MappingID)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.ModelUID != req.ModelUID {
+		return nil, errs.ValidationError.WithMsg("映射方案不属于该模型")
+	}
+	return &mapping, nil
+}
+
+// mappedColumn 编译后的列映射
+type mappedColumn struct {
+	domain.ColumnMapping
+	transform domain.ValueTransformer
+}
+
+// apply 转换单元格取值，转换失败时记录到行错误
+func (c mappedColumn) apply(sheet importSheet, row int, value string, r *importRow) {
+	if value == "" {
+		return
+	}
+	out, err := c.transform(value)
+	if err != nil {
+		r.problems = append(r.problems, sheet.rowError(row, c.FieldUid, "%s: %s", c.Source, err.Error()))
+		return
+	}
+	if out != "" {
+		r.data[c.FieldUid] = out
+	}
+}
+
+// compileMapping 编译映射方案，映射的字段在提交后被删除时报错
+func compileMapping(mapping domain.ImportMapping, attrs []domain.Attribute) ([]mappedColumn, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	fields := lo.SliceToMap(attrs, func(attr domain.Attribute) (string, struct{}) {
+		return attr.FieldUid, struct{}{}
+	})
+	columns := make([]mappedColumn, 0, len(mapping.Columns))
+	for _, col := range mapping.Columns {
+		if _, ok := fields[col.FieldUid]; !ok {
+			return nil, fmt.Errorf("映射的字段 %s 不存在", col.FieldUid)
+		}
+		fn, err := col.Transformer()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, mappedColumn{ColumnMapping: col, transform: fn})
+	}
+	return columns, nil
+}
+
+// parseMappedImportFile 按映射方案解析导入数据，取值在校验前按列的转换规则转换
+// NOTE: Excel 及 CSV 按表头文本匹配列（忽略首尾空白及大小写），NDJSON 及 YAML 按对象的键匹配
+func parseMappedImportFile(fileData []byte, attrs []domain.Attribute, opts domain.FormatOptions,
+	mapping domain.ImportMapping) (importSheet, error) {
+	columns, err := compileMapping(mapping, attrs)
+	if err != nil {
+		return importSheet{}, err
+	}
+
+	var sheet importSheet
+	switch opts.Format {
+	case domain.FileFormatNDJSON, domain.FileFormatYAML:
+		records, rows, er := readImportRecords(fileData, opts)
+		if er != nil {
+			return importSheet{}, er
+		}
+		sheet = mapRecords(records, rows, columns)
+	default:
+		table, er := readImportTable(fileData, opts)
+		if er != nil {
+			return importSheet{}, er
+		}
+		sheet, err = mapTable(table, mapping.HeaderRow, columns)
+	}
+	if err != nil {
+		return importSheet{}, err
+	}
+
+	if len(sheet.rows) == 0 {
+		return importSheet{}, fmt.Errorf("没有有效的数据行")
+	}
+	return sheet, nil
+}
+
+// readImportTable 读取 Excel 第一个工作表或 CSV 文件的所有行
+func readImportTable(fileData []byte, opts domain.FormatOptions) ([][]string, error) {
+	if opts.Format == domain.FileFormatCSV {
+		return readCSVRecords(fileData, opts)
+	}
+	return readExcelRows(fileData)
+}
+
+// readImportRecords 读取 NDJSON 或 YAML 文件的所有对象及其行号
+func readImportRecords(fileData []byte, opts domain.FormatOptions) ([]map[string]interface{}, []int, error) {
+	if opts.Format == domain.FileFormatYAML {
+		return readYAMLRecords(fileData)
+	}
+	return readNDJSONRecords(fileData)
+}
+
+// mapTable 按表头文本定位映射的列，表头之后的行为数据行
+func mapTable(table [][]string, headerRow int, columns []mappedColumn) (importSheet, error) {
+	headerRow = max(headerRow, 1)
+	if len(table) <= headerRow {
+		return importSheet{}, fmt.Errorf("第 %d 行表头之后没有数据行", headerRow)
+	}
+
+	index := make(map[string]int)
+	for colIdx, header := range table[headerRow-1] {
+		key := strings.ToLower(strings.TrimSpace(header))
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = colIdx
+		}
+	}
+
+	sheet := importSheet{columns: make(map[string]int, len(columns))}
+	for _, col := range columns {
+		colIdx, ok := index[strings.ToLower(strings.TrimSpace(col.Source))]
+		if !ok {
+			return importSheet{}, fmt.Errorf("第 %d 行表头中不存在列 %s", headerRow, col.Source)
+		}
+		sheet.columns[col.FieldUid] = colIdx
+	}
+
+	for idx, cells := range table[headerRow:] {
+		r := importRow{row: idx + headerRow + 1, data: make(map[string]interface{})}
+		for _, col := range columns {
+			if colIdx := sheet.columns[col.FieldUid]; colIdx < len(cells) {
+				col.apply(sheet, r.row, cells[colIdx], &r)
+			}
+		}
+		if len(r.data) > 0 || len(r.problems) > 0 {
+			sheet.rows = append(sheet.rows, r)
+		}
+	}
+	return sheet, nil
+}
+
+// mapRecords 按对象的键映射字段，字符串取值按列的转换规则转换，数组及对象原样保留
+func mapRecords(records []map[string]interface{}, rows []int, columns []mappedColumn) importSheet {
+	sheet := importSheet{columns: make(map[string]int)}
+	for idx, record := range records {
+		r := importRow{row: rows[idx], data: make(map[string]interface{})}
+		for _, col := range columns {
+			value, ok := importValue(record[col.Source])
+			if !ok {
+				continue
+			}
+			if text, isText := value.(string); isText {
+				col.apply(sheet, r.row, text, &r)
+				continue
+			}
+			r.data[col.FieldUid] = value
+		}
+		if len(r.data) > 0 || len(r.problems) > 0 {
+			sheet.rows = append(sheet.rows, r)
+		}
+	}
+	return sheet
+}
+
+// previewImportFile 识别表头并给出映射建议，headerRow 为 0 时自动识别 Excel 及 CSV 的表头行
+func previewImportFile(fileData []byte, opts domain.FormatOptions, headerRow int,
+	attrs []domain.Attribute) (domain.ImportMappingPreview, error) {
+	var preview domain.ImportMappingPreview
+	switch opts.Format {
+	case domain.FileFormatNDJSON, domain.FileFormatYAML:
+		records, _, err := readImportRecords(fileData, opts)
+		if err != nil {
+			return preview, err
+		}
+		preview.Headers = recordKeys(records)
+		for _, record := range lo.Slice(records, 0, previewSampleRows) {
+			preview.Samples = append(preview.Samples, lo.Map(preview.Headers, func(key string, _ int) string {
+				return cellString(record[key])
+			}))
+		}
+	default:
+		table, err := readImportTable(fileData, opts)
+		if err != nil {
+			return preview, err
+		}
+		if len(table) == 0 {
+			return preview, fmt.Errorf("文件没有数据")
+		}
+		if headerRow == 0 {
+			headerRow = detectHeaderRow(table, attrs)
+		}
+		if headerRow > len(table) {
+			return preview, fmt.Errorf("表头行 %d 超出文件的行数 %d", headerRow, len(table))
+		}
+
+		preview.HeaderRow = headerRow
+		preview.Headers = table[headerRow-1]
+		for _, cells := range lo.Slice(table, headerRow, headerRow+previewSampleRows) {
+			preview.Samples = append(preview.Samples, lo.Map(preview.Headers, func(_ string, idx int) string {
+				return lo.NthOrEmpty(cells, idx)
+			}))
+		}
+	}
+
+	preview.Suggestions = suggestColumnMapping(preview.Headers, attrs)
+	return preview, nil
+}
+
+// recordKeys 汇总所有对象的键，按字典序排列
+func recordKeys(records []map[string]interface{}) []string {
+	keys := lo.Uniq(lo.FlatMap(records, func(record map[string]interface{}, _ int) []string {
+		return lo.Keys(record)
+	}))
+	sort.Strings(keys)
+	return keys
+}
+
+// detectHeaderRow 在前几行中选择能匹配到最多字段的行作为表头
+// NOTE: 匹配数相同时取靠后的行，导入模板的字段 UID 行及字段名称行均能完全匹配，数据从名称行之后开始
+func detectHeaderRow(table [][]string, attrs []domain.Attribute) int {
+	best, bestCount := 1, 0
+	for idx, cells := range lo.Slice(table, 0, headerDetectRows) {
+		count := lo.CountBy(cells, func(header string) bool {
+			return lo.SomeBy(attrs, func(attr domain.Attribute) bool {
+				return headerSimilarity(header, attr) >= mappingMatchThreshold
+			})
+		})
+		if count > 0 && count >= bestCount {
+			best, bestCount = idx+1, count
+		}
+	}
+	return best
+}
+
+// suggestColumnMapping 按列名与字段 UID、字段名称的相似度给出映射建议
+// NOTE: 相似度从高到低贪心分配，每个字段最多分配给一列
+func suggestColumnMapping(headers []string, attrs []domain.Attribute) []domain.ColumnSuggestion {
+	type candidate struct {
+		header, attr int
+		score        float64
+	}
+	var candidates []candidate
+	for i, header := range headers {
+		for j, attr := range attrs {
+			if score := headerSimilarity(header, attr); score >= mappingMatchThreshold {
+				candidates = append(candidates, candidate{header: i, attr: j, score: score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	suggestions := lo.Map(headers, func(header string, _ int) domain.ColumnSuggestion {
+		return domain.ColumnSuggestion{Source: header}
+	})
+	assigned := make(map[int]struct{})
+	for _, c := range candidates {
+		if _, ok := assigned[c.attr]; ok || suggestions[c.header].FieldUid != "" {
+			continue
+		}
+		assigned[c.attr] = struct{}{}
+		suggestions[c.header].FieldUid = attrs[c.attr].FieldUid
+		suggestions[c.header].FieldName = attrs[c.attr].FieldName
+		suggestions[c.header].Score = c.score
+	}
+	return suggestions
+}
+
+// headerSimilarity 列名与字段 UID、字段名称相似度的较大值
+func headerSimilarity(header string, attr domain.Attribute) float64 {
+	h := normalizeHeader(header)
+	return max(textSimilarity(h, normalizeHeader(attr.FieldUid)), textSimilarity(h, normalizeHeader(attr.FieldName)))
+}
+
+func normalizeHeader(s string) string {
+	return headerNormalizer.Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// textSimilarity 相同为 1，互相包含为 0.8，否则为字符二元组的 Dice 系数
+func textSimilarity(a, b string) float64 {
+	switch {
+	case a == "" || b == "":
+		return 0
+	case a == b:
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	if min(len(ra), len(rb)) >= 2 && (strings.Contains(a, b) || strings.Contains(b, a)) {
+		return mappingContainsScore
+	}
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+
+	bigrams := make(map[string]int, len(ra)-1)
+	for i := 0; i+1 < len(ra); i++ {
+		bigrams[string(ra[i:i+2])]++
+	}
+	overlap := 0
+	for i := 0; i+1 < len(rb); i++ {
+		key := string(rb[i : i+2])
+		if bigrams[key] > 0 {
+			bigrams[key]--
+			overlap++
+		}
+	}
+	return float64(2*overlap) / float64(len(ra)+len(rb)-2)
+}
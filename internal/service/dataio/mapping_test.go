@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hostMapping = domain.ImportMapping{
+	HeaderRow: 2,
+	Columns: []domain.ColumnMapping{
+		{Source: "Hostname", FieldUid: "name", Transforms: []domain.ColumnTransform{{Type: domain.TransformTrim}}},
+		{Source: "Address", FieldUid: "ip", Transforms: []domain.ColumnTransform{
+			{Type: domain.TransformRegex, Pattern: `^([\d.]+)`},
+		}},
+		{Source: "Stage", FieldUid: "env", Transforms: []domain.ColumnTransform{
+			{Type: domain.TransformValueMap, Mapping: map[string]string{"Production": "prod"}},
+		}},
+	},
+}
+
+func TestParseMappedImportFile(t *testing.T) {
+	csvFormat, err := domain.FormatOptions{Format: domain.FileFormatCSV}.Normalize()
+	require.NoError(t, err)
+
+	data := []byte("供应商资产清单\n hostname ,Address,Stage,Owner\n web01 ,10.0.0.1/24,Production,ops\n,,,\n" +
+		"web02,10.0.0.2,Staging,ops\n")
+	sheet, err := parseMappedImportFile(data, importAttrs, csvFormat, hostMapping)
+	require.NoError(t, err)
+	require.Len(t, sheet.rows, 2, "空行不计入数据行")
+	assert.Equal(t, importRow{row: 3, data: map[string]interface{}{
+		"name": "web01", "ip": "10.0.0.1", "env": "prod",
+	}}, sheet.rows[0])
+	assert.Equal(t, 5, sheet.rows[1].row)
+	assert.Equal(t, "Staging", sheet.rows[1].data["env"], "映射之后仍由校验拦截不在可选项中的取值")
+
+	_, err = parseMappedImportFile([]byte("x\nname,ip\nweb01,10.0.0.1\n"), importAttrs, csvFormat, hostMapping)
+	assert.ErrorContains(t, err, "不存在列 Hostname")
+}
+
+func TestParseMappedImportFile_TransformError(t *testing.T) {
+	mapping := domain.ImportMapping{Columns: []domain.ColumnMapping{
+		{Source: "name", FieldUid: "name"},
+		{Source: "created", FieldUid: "ip", Transforms: []domain.ColumnTransform{
+			{Type: domain.TransformDate, Layout: "YYYY/MM/DD"},
+		}},
+	}}
+	data := []byte(`{"name":"web01","created":"2024-01-02"}` + "\n" + `{"name":"web02","created":"2024/01/02"}` + "\n")
+
+	sheet, err := parseMappedImportFile(data, importAttrs, domain.FormatOptions{Format: domain.FileFormatNDJSON},
+		mapping)
+	require.NoError(t, err)
+	require.Len(t, sheet.rows, 2)
+	assert.Equal(t, "2024-01-02", sheet.rows[1].data["ip"])
+
+	validator := newImportValidator(sheet, importAttrs, domain.ImportModeUpsert)
+	problems := validator.validate(sheet.rows[0])
+	require.Len(t, problems, 1)
+	assert.Equal(t, "ip", problems[0].FieldUid)
+	assert.Contains(t, problems[0].Message, "不符合格式")
+	assert.Empty(t, validator.validate(sheet.rows[1]))
+}
+
+func TestSuggestColumnMapping(t *testing.T) {
+	suggestions := suggestColumnMapping([]string{"主机名称", "IP Address", "Name", "备注"}, importAttrs)
+	assert.Equal(t, []string{"", "ip", "name", ""}, []string{
+		suggestions[0].FieldUid, suggestions[1].FieldUid, suggestions[2].FieldUid, suggestions[3].FieldUid,
+	}, "名称字段优先分配给完全匹配的列")
+	assert.Equal(t, 1.0, suggestions[2].Score)
+}
+
+func TestDetectHeaderRow(t *testing.T) {
+	testCases := []struct {
+		name  string
+		table [][]string
+		want  int
+	}{
+		{
+			name:  "跳过标题行",
+			table: [][]string{{"供应商资产清单"}, {"主机名称", "IP", "环境"}, {"web01", "10.0.0.1", "prod"}},
+			want:  2,
+		},
+		{
+			name:  "导入模板取字段名称行",
+			table: [][]string{{"必填", "必填", ""}, {"name", "ip", "env"}, {"名称", "IP", "环境"}, {"web01"}},
+			want:  3,
+		},
+		{
+			name:  "无法识别时为第一行",
+			table: [][]string{{"a", "b"}, {"1", "2"}},
+			want:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, detectHeaderRow(tc.table, importAttrs))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/samber/lo"
+)
+
+const (
+	// relatedColumnSeparator 关联列 FieldUid 的分隔符，格式为 relation_name.field_uid
+	// NOTE: 导入时包含该分隔符的列会被跳过，保证导出文件可直接回导
+	relatedColumnSeparator = "."
+
+	// relatedConstraintDescription 关联列的约束描述
+	relatedConstraintDescription = "关联字段，导入时忽略"
+)
+
+// relatedColumn 解析后的关联资产导出配置
+type relatedColumn struct {
+	spec     RelatedFields
+	relation domain.ModelRelation
+	isSource bool               // 导出模型是否位于关联的源端
+	model    domain.Model       // 对端模型
+	attrs    []domain.Attribute // 对端模型需要导出的字段
+}
+
+// fieldUid 关联列在表头第二行中的标识
+func (c relatedColumn) fieldUid(attr domain.Attribute) string {
+	return c.relation.RelationName + relatedColumnSeparator + attr.FieldUid
+}
+
+// fieldName 关联列在表头第三行中的名称
+func (c relatedColumn) fieldName(attr domain.Attribute) string {
+	return c.model.Name + relatedColumnSeparator + attr.FieldName
+}
+
+// resolveRelatedColumns 校验关联导出配置，并加载对端模型及字段定义
+func (s *dataIOService) resolveRelatedColumns(ctx context.Context, modelUID string, specs []RelatedFields) ([]relatedColumn, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	if lo.CountBy(specs, func(spec RelatedFields) bool {
+		return spec.Mode == RelatedModeExplode
+	}) > 1 {
+		return nil, errs.ValidationError.WithMsg("最多只允许一个关联使用展开（explode）模式")
+	}
+
+	mrs, err := s.rmSvc.GetByRelationNames(ctx, lo.Map(specs, func(spec RelatedFields, _ int) string {
+		return spec.RelationName
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("获取模型关联失败: %w", err)
+	}
+	relations := lo.KeyBy(mrs, func(mr domain.ModelRelation) string {
+		return mr.RelationName
+	})
+
+	cols := make([]relatedColumn, 0, len(specs))
+	for _, spec := range specs {
+		switch spec.Mode {
+		case "":
+			spec.Mode = RelatedModeJoin
+		case RelatedModeJoin, RelatedModeExplode:
+		default:
+			return nil, errs.ValidationError.WithMsg(fmt.Sprintf("不支持的关联导出模式: %s", spec.Mode))
+		}
+		if spec.Separator == "" {
+			spec.Separator = defaultRelatedSeparator
+		}
+
+		mr, ok := relations[spec.RelationName]
+		if !ok {
+			return nil, errs.ValidationError.WithMsg(fmt.Sprintf("模型关联 %s 不存在", spec.RelationName))
+		}
+		if !mr.BelongsTo(modelUID) {
+			return nil, errs.ValidationError.WithMsg(fmt.Sprintf("模型关联 %s 与模型 %s 无关", spec.RelationName, modelUID))
+		}
+
+		// NOTE: 自关联时默认以导出模型作为源端
+		isSource := mr.IsSource(modelUID)
+		peerModelUID := mr.SourceModelUID
+		if isSource {
+			peerModelUID = mr.TargetModelUID
+		}
+
+		mdl, attrs, err := s.fetchModelAndAttributes(ctx, peerModelUID)
+		if err != nil {
+			return nil, err
+		}
+
+		// 未指定字段时默认导出对端资产名称
+		fields := spec.Fields
+		if len(fields) == 0 {
+			fields = []string{"name"}
+		}
+		attrs = sortAttributesByPriority(lo.Filter(attrs, func(attr domain.Attribute, _ int) bool {
+			return lo.Contains(fields, attr.FieldUid)
+		}))
+		if len(attrs) == 0 {
+			return nil, errs.ValidationError.WithMsg(
+				fmt.Sprintf("关联模型 %s 不存在字段: %s", peerModelUID, strings.Join(fields, ",")))
+		}
+
+		cols = append(cols, relatedColumn{
+			spec:     spec,
+			relation: mr,
+			isSource: isSource,
+			model:    mdl,
+			attrs:    attrs,
+		})
+	}
+	return cols, nil
+}
+
+// loadRelated 批量加载一页资源的关联资产
+// NOTE: 每个关联仅发起一次聚合查询和一次资产查询，返回值与 cols 一一对应：资源 ID -> 关联资产列表
+func (s *dataIOService) loadRelated(ctx context.Context, cols []relatedColumn,
+	resources []domain.Resource) ([]map[int64][]domain.Resource, error) {
+	related := make([]map[int64][]domain.Resource, len(cols))
+	if len(cols) == 0 || len(resources) == 0 {
+		return related, nil
+	}
+
+	ids := lo.Map(resources, func(res domain.Resource, _ int) int64 {
+		return res.ID
+	})
+	for i, col := range cols {
+		relatedIds, err := s.rrSvc.ListRelatedIdsBatch(ctx, col.relation.RelationName, col.isSource, ids)
+		if err != nil {
+			return nil, fmt.Errorf("获取关联资产失败: %w", err)
+		}
+
+		peerIds := lo.Uniq(lo.Flatten(lo.Values(relatedIds)))
+		if len(peerIds) == 0 {
+			related[i] = map[int64][]domain.Resource{}
+			continue
+		}
+
+		peers, err := s.resSvc.ListResourceByIds(ctx, lo.Map(col.attrs, func(attr domain.Attribute, _ int) string {
+			return attr.FieldUid
+		}), peerIds)
+		if err != nil {
+			return nil, fmt.Errorf("获取关联资产失败: %w", err)
+		}
+		peerIndex := lo.KeyBy(peers, func(res domain.Resource) int64 {
+			return res.ID
+		})
+
+		related[i] = lo.MapValues(relatedIds, func(ids []int64, _ int64) []domain.Resource {
+			return lo.FilterMap(ids, func(id int64, _ int) (domain.Resource, bool) {
+				res, ok := peerIndex[id]
+				return res, ok
+			})
+		})
+	}
+	return related, nil
+}
+
+// exportRows 组装导出数据行
+// NOTE: 单个关联资产直接平铺；多个关联资产在 join 模式下合并到同一单元格，explode 模式下展开为多行
+func exportRows(attrs []domain.Attribute, cols []relatedColumn, resources []domain.Resource,
+	related []map[int64][]domain.Resource) [][]interface{} {
+	rows := make([][]interface{}, 0, len(resources))
+	for _, res := range resources {
+		resRows := [][]interface{}{resourceValues(attrs, res)}
+
+		for i, col := range cols {
+			peers := related[i][res.ID]
+			if col.spec.Mode == RelatedModeExplode && len(peers) > 1 {
+				exploded := make([][]interface{}, 0, len(resRows)*len(peers))
+				for _, row := range resRows {
+					for _, peer := range peers {
+						exploded = append(exploded, append(slices.Clone(row), resourceValues(col.attrs, peer)...))
+					}
+				}
+				resRows = exploded
+				continue
+			}
+
+			values := joinedValues(col, peers)
+			for j := range resRows {
+				resRows[j] = append(resRows[j], values...)
+			}
+		}
+		rows = append(rows, resRows...)
+	}
+	return rows
+}
+
+// resourceValues 按字段顺序取出资产数据
+func resourceValues(attrs []domain.Attribute, res domain.Resource) []interface{} {
+	return lo.Map(attrs, func(attr domain.Attribute, _ int) interface{} {
+		if val, ok := res.Data[attr.FieldUid]; ok {
+			return val
+		}
+		return ""
+	})
+}
+
+// joinedValues 将多个关联资产的同一字段使用分隔符合并
+func joinedValues(col relatedColumn, peers []domain.Resource) []interface{} {
+	switch len(peers) {
+	case 0:
+		return lo.Map(col.attrs, func(domain.Attribute, int) interface{} {
+			return ""
+		})
+	case 1:
+		return resourceValues(col.attrs, peers[0])
+	}
+
+	return lo.Map(col.attrs, func(attr domain.Attribute, _ int) interface{} {
+		values := lo.FilterMap(peers, func(peer domain.Resource, _ int) (string, bool) {
+			val, ok := peer.Data[attr.FieldUid]
+			if !ok || val == nil {
+				return "", false
+			}
+			return fmt.Sprint(val), true
+		})
+		return strings.Join(values, col.spec.Separator)
+	})
+}
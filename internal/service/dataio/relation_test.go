@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRows(t *testing.T) {
+	attrs := []domain.Attribute{{FieldUid: "name"}}
+	idc := domain.Attribute{FieldUid: "name"}
+	resources := []domain.Resource{
+		{ID: 1, Data: map[string]interface{}{"name": "host-1"}},
+		{ID: 2, Data: map[string]interface{}{"name": "host-2"}},
+		{ID: 3, Data: map[string]interface{}{"name": "host-3"}},
+	}
+	related := []map[int64][]domain.Resource{{
+		1: {{ID: 10, Data: map[string]interface{}{"name": "idc-a"}}},
+		2: {
+			{ID: 10, Data: map[string]interface{}{"name": "idc-a"}},
+			{ID: 11, Data: map[string]interface{}{"name": "idc-b"}},
+		},
+	}}
+
+	testCases := []struct {
+		name string
+		spec RelatedFields
+		want [][]interface{}
+	}{
+		{
+			name: "合并模式",
+			spec: RelatedFields{Mode: RelatedModeJoin, Separator: "|"},
+			want: [][]interface{}{
+				{"host-1", "idc-a"},
+				{"host-2", "idc-a|idc-b"},
+				{"host-3", ""},
+			},
+		},
+		{
+			name: "展开模式",
+			spec: RelatedFields{Mode: RelatedModeExplode},
+			want: [][]interface{}{
+				{"host-1", "idc-a"},
+				{"host-2", "idc-a"},
+				{"host-2", "idc-b"},
+				{"host-3", ""},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cols := []relatedColumn{{spec: tc.spec, attrs: []domain.Attribute{idc}}}
+			assert.Equal(t, tc.want, exportRows(attrs, cols, resources, related))
+		})
+	}
+}
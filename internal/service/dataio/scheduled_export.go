@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// scheduledExportDueLimit 单次调度执行的定时导出数量
+	scheduledExportDueLimit = 10
+	// scheduledExportStaleTimeout 执行记录超过该时间未刷新心跳，视为调度实例退出导致中断
+	scheduledExportStaleTimeout = 10 * time.Minute
+)
+
+func (s *dataIOService) CreateScheduledExport(ctx context.Context, se domain.ScheduledExport) (int64, error) {
+	if err := s.prepareScheduledExport(ctx, &se); err != nil {
+		return 0, err
+	}
+
+	se.CreatorID = ctxutil.GetUserID(ctx).Int64()
+	return s.schedRepo.CreateScheduledExport(ctx, se)
+}
+
+func (s *dataIOService) UpdateScheduledExport(ctx context.Context, se domain.ScheduledExport) (int64, error) {
+	if _, err := s.schedRepo.FindScheduledExportById(ctx, se.ID); err != nil {
+		return 0, err
+	}
+	if err := s.prepareScheduledExport(ctx, &se); err != nil {
+		return 0, err
+	}
+
+	return s.schedRepo.UpdateScheduledExport(ctx, se)
+}
+
+// DeleteScheduledExport 删除定时导出，同时清理历史执行生成的导出文件，执行记录保留用于审计
+func (s *dataIOService) DeleteScheduledExport(ctx context.Context, id int64) (int64, error) {
+	if _, err := s.schedRepo.FindScheduledExportById(ctx, id); err != nil {
+		return 0, err
+	}
+	if err := s.pruneScheduledExportRuns(ctx, id, time.Now().UnixMilli()); err != nil {
+		return 0, err
+	}
+
+	return s.schedRepo.DeleteScheduledExport(ctx, id)
+}
+
+func (s *dataIOService) ListScheduledExports(ctx context.Context, offset, limit int64) (
+	[]domain.ScheduledExport, int64, error) {
+	var (
+		eg    errgroup.Group
+		ses   []domain.ScheduledExport
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		ses, err = s.schedRepo.ListScheduledExports(ctx, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.schedRepo.TotalScheduledExports(ctx)
+		return err
+	})
+
+	return ses, total, eg.Wait()
+}
+
+func (s *dataIOService) ListScheduledExportRuns(ctx context.Context, scheduleID int64, offset, limit int64) (
+	[]domain.ScheduledExportRun, int64, error) {
+	var (
+		eg    errgroup.Group
+		runs  []domain.ScheduledExportRun
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		runs, err = s.schedRepo.ListScheduledExportRuns(ctx, scheduleID, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.schedRepo.TotalScheduledExportRuns(ctx, scheduleID)
+		return err
+	})
+
+	return runs, total, eg.Wait()
+}
+
+func (s *dataIOService) ScheduledExportFileURL(ctx context.Context, runID int64, modelUID string) (string, error) {
+	run, err := s.schedRepo.FindScheduledExportRunById(ctx, runID)
+	if err != nil {
+		return "", err
+	}
+	if run.Pruned {
+		return "", errs.ValidationError.WithMsg("导出文件已超过保留期限被清理")
+	}
+
+	file, ok := lo.Find(run.Files, func(f domain.ScheduledExportFile) bool {
+		return f.ModelUID == modelUID
+	})
+	if !ok || file.FileKey == "" {
+		return "", errs.ValidationError.WithMsg(fmt.Sprintf("执行记录中没有模型 %s 的导出文件", modelUID))
+	}
+
+	return s.storage.GenerateDownloadURL(ctx, dataBucket, file.FileKey, exportFileExpire)
+}
+
+func (s *dataIOService) RunScheduledExports(ctx context.Context) (int, error) {
+	stale := time.Now().Add(-scheduledExportStaleTimeout).UnixMilli()
+	if _, err := s.schedRepo.FailStaleScheduledExportRuns(ctx, stale, "定时导出执行中断"); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	ses, err := s.schedRepo.ListDueScheduledExports(ctx, now.UnixMilli(), scheduledExportDueLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, se := range ses {
+		// NOTE: 跨租户调度，执行时还原创建人所在的租户及用户身份
+		seCtx := ctxutil.WithUserID(ctxutil.WithTenantID(ctx, se.TenantID), se.CreatorID)
+
+		// NOTE: 错过的执行时间不补跑，直接推进到当前时间之后的下一次
+		next, er := se.NextRun(now)
+		if er != nil {
+			s.logger.Error("计算定时导出执行时间失败", elog.FieldErr(er), elog.Int64("schedule_id", se.ID))
+			continue
+		}
+		claimed, er := s.schedRepo.AdvanceScheduledExport(seCtx, se.ID, se.NextRunAt, next.UnixMilli())
+		if er != nil {
+			return count, er
+		}
+		if !claimed {
+			continue
+		}
+
+		s.runScheduledExport(seCtx, se, now)
+		count++
+	}
+
+	return count, nil
+}
+
+// prepareScheduledExport 校验定时导出配置，每个模型的筛选条件及字段按导出参数校验，并计算下一次执行时间
+func (s *dataIOService) prepareScheduledExport(ctx context.Context, se *domain.ScheduledExport) error {
+	if err := se.Validate(); err != nil {
+		return errs.ValidationError.WithMsg(err.Error())
+	}
+
+	opts, err := se.FormatOptions.Normalize()
+	if err != nil {
+		return errs.ValidationError.WithMsg(err.Error())
+	}
+	se.FormatOptions = opts
+
+	for _, m := range se.Models {
+		if _, err = s.prepareExport(ctx, scheduledExportParams(*se, m)); err != nil {
+			return err
+		}
+	}
+
+	se.NextRunAt = 0
+	if se.Enabled {
+		next, _ := se.NextRun(time.Now())
+		se.NextRunAt = next.UnixMilli()
+	}
+	return nil
+}
+
+// runScheduledExport 依次导出每个模型并记录执行结果，单个模型失败不影响其他模型
+func (s *dataIOService) runScheduledExport(ctx context.Context, se domain.ScheduledExport, now time.Time) {
+	run := domain.ScheduledExportRun{
+		ScheduleID: se.ID,
+		Status:     domain.ScheduledExportRunning,
+	}
+	var err error
+	if run.ID, err = s.schedRepo.CreateScheduledExportRun(ctx, run); err != nil {
+		s.logger.Error("创建定时导出执行记录失败", elog.FieldErr(err), elog.Int64("schedule_id", se.ID))
+		return
+	}
+
+	for _, m := range se.Models {
+		run.Files = append(run.Files, domain.ScheduledExportFile{ModelUID: m.ModelUID})
+		if err = s.exportScheduledModel(ctx, se, m, now, &run); err != nil {
+			run.Files[len(run.Files)-1].Message = err.Error()
+		}
+	}
+
+	failed := lo.Filter(run.Files, func(f domain.ScheduledExportFile, _ int) bool {
+		return f.Message != ""
+	})
+	run.Status = lo.Ternary(len(failed) > 0, domain.ScheduledExportFailed, domain.ScheduledExportSucceeded)
+	run.Message = strings.Join(lo.Map(failed, func(f domain.ScheduledExportFile, _ int) string {
+		return fmt.Sprintf("%s: %s", f.ModelUID, f.Message)
+	}), "; ")
+	if err = s.schedRepo.UpdateScheduledExportRun(ctx, run); err != nil {
+		s.logger.Error("记录定时导出结果失败", elog.FieldErr(err), elog.Int64("run_id", run.ID))
+	}
+
+	if before := se.RetentionBefore(now); before > 0 {
+		if err = s.pruneScheduledExportRuns(ctx, se.ID, before); err != nil {
+			s.logger.Error("清理过期的定时导出文件失败", elog.FieldErr(err), elog.Int64("schedule_id", se.ID))
+		}
+	}
+}
+
+// exportScheduledModel 导出单个模型，文件信息记录在执行记录的最后一个文件中，每批数据后刷新心跳
+func (s *dataIOService) exportScheduledModel(ctx context.Context, se domain.ScheduledExport,
+	m domain.ScheduledExportModel, now time.Time, run *domain.ScheduledExportRun) error {
+	plan, err := s.prepareExport(ctx, scheduledExportParams(se, m))
+	if err != nil {
+		return err
+	}
+
+	file := &run.Files[len(run.Files)-1]
+	key := scheduledExportKey(se.ID, now, m.ModelUID, plan.opts.Format)
+	size, err := s.uploadExport(ctx, plan, key, func(_, rows int) error {
+		file.Rows = rows
+		return s.schedRepo.UpdateScheduledExportRun(ctx, *run)
+	})
+	if err != nil {
+		return err
+	}
+
+	file.FileKey, file.Size = key, size
+	return nil
+}
+
+// pruneScheduledExportRuns 删除早于 before 的执行记录生成的文件，执行记录标记为已清理
+func (s *dataIOService) pruneScheduledExportRuns(ctx context.Context, scheduleID int64, before int64) error {
+	runs, err := s.schedRepo.ListExpiredScheduledExportRuns(ctx, scheduleID, before)
+	if err != nil || len(runs) == 0 {
+		return err
+	}
+
+	for _, run := range runs {
+		for _, file := range run.Files {
+			if file.FileKey == "" {
+				continue
+			}
+			if err = s.storage.DeleteFile(ctx, dataBucket, file.FileKey); err != nil {
+				return fmt.Errorf("删除导出文件 %s 失败: %w", file.FileKey, err)
+			}
+		}
+	}
+
+	return s.schedRepo.MarkScheduledExportRunsPruned(ctx, lo.Map(runs, func(run domain.ScheduledExportRun, _ int) int64 {
+		return run.ID
+	}))
+}
+
+func scheduledExportParams(se domain.ScheduledExport, m domain.ScheduledExportModel) ExportParams {
+	return ExportParams{
+		ModelUID:      m.ModelUID,
+		Scope:         "all",
+		FilterGroups:  m.FilterGroups,
+		Fields:        m.Fields,
+		FormatOptions: se.FormatOptions,
+	}
+}
+
+// scheduledExportKey 同一次执行的文件位于同一目录：export/scheduled/{定时导出 ID}/{执行时间}/{模型}.{后缀}
+func scheduledExportKey(scheduleID int64, at time.Time, modelUID string, format domain.FileFormat) string {
+	return fmt.Sprintf("export/scheduled/%d/%s/%s%s", scheduleID, at.Format("20060102T150405"), modelUID,
+		format.Ext())
+}
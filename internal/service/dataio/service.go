@@ -2,25 +2,121 @@ package service
 
 import (
 	"context"
+	"io"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
 )
 
 // IDataIOService 数据交换服务接口
-// NOTE: 提供基于 Model-Attribute-Resource 架构的数据导入导出功能,支持 Excel 格式
+// NOTE: 提供基于 Model-Attribute-Resource 架构的数据导入导出功能,支持 Excel、CSV、NDJSON 及 YAML 格式
 type IDataIOService interface {
-	// Import 批量导入资源实例 (Resource)
-	// modelUID: 模型唯一标识 (对应 Model.UID)
-	// fileData: Excel 文件的字节数据
-	Import(ctx context.Context, modelUID string, fileData []byte) (importedCount int, err error)
+	// SubmitImport 提交异步导入任务，返回任务 ID，由后台任务领取执行
+	SubmitImport(ctx context.Context, req ImportParams) (int64, error)
 
-	// Export 导出资源实例数据 (Resource)
+	// CommitImportJob 确认预演任务，以相同的文件及导入模式创建正式导入任务
+	CommitImportJob(ctx context.Context, id int64) (int64, error)
+
+	// FindImportJob 查询导入任务进度及结果
+	FindImportJob(ctx context.Context, id int64) (domain.ImportJob, error)
+
+	// ListImportJobs 查询模型下的导入任务
+	ListImportJobs(ctx context.Context, modelUID string, offset, limit int64) ([]domain.ImportJob, int64, error)
+
+	// ImportReportURL 生成导入错误报告的下载链接
+	ImportReportURL(ctx context.Context, id int64) (string, error)
+
+	// RunImportJobs 领取并执行待处理的导入任务，返回执行的任务数量
+	RunImportJobs(ctx context.Context) (int, error)
+
+	// PreviewImportMapping 识别已上传文件的表头，并按列名与字段的相似度给出映射建议
+	PreviewImportMapping(ctx context.Context, req MappingPreviewParams) (domain.ImportMappingPreview, error)
+
+	// CreateImportMapping 保存列映射方案，同一模型下名称不能重复
+	CreateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error)
+
+	// UpdateImportMapping 修改列映射方案，所属模型不允许变更
+	UpdateImportMapping(ctx context.Context, mapping domain.ImportMapping) (int64, error)
+
+	// DeleteImportMapping 删除列映射方案，不影响已提交的导入任务
+	DeleteImportMapping(ctx context.Context, id int64) (int64, error)
+
+	// ListImportMappings 查询模型下的列映射方案
+	ListImportMappings(ctx context.Context, modelUID string) ([]domain.ImportMapping, error)
+
+	// Export 导出资源实例数据 (Resource)，以游标分批读取资产并流式写入 w
 	// req: 导出请求参数
-	Export(ctx context.Context, req ExportParams) ([]byte, error)
+	// NOTE: 参数校验在写入任何数据之前完成，写入过程中出错时 w 中的内容不完整
+	Export(ctx context.Context, req ExportParams, w io.Writer) error
+
+	// ExportWorkbook 导出多个模型的资产到同一个 Excel 文件，每个模型一个工作表，按模型依赖顺序排列
+	// NOTE: 可选追加资产关联工作表，导出文件可直接通过工作簿导入回导
+	ExportWorkbook(ctx context.Context, req WorkbookExportParams, w io.Writer) error
+
+	// SubmitExport 提交异步导出任务，返回任务 ID，由后台任务流式写入对象存储
+	SubmitExport(ctx context.Context, req ExportParams) (int64, error)
+
+	// FindExportJob 查询导出任务进度及结果
+	FindExportJob(ctx context.Context, id int64) (domain.ExportJob, error)
+
+	// ListExportJobs 查询模型下的导出任务
+	ListExportJobs(ctx context.Context, modelUID string, offset, limit int64) ([]domain.ExportJob, int64, error)
+
+	// ExportFileURL 生成导出文件的下载链接
+	ExportFileURL(ctx context.Context, id int64) (string, error)
+
+	// RunExportJobs 领取并执行待处理的导出任务，返回执行的任务数量
+	RunExportJobs(ctx context.Context) (int, error)
+
+	// CreateScheduledExport 创建定时导出，按 cron 表达式由后台调度器执行，导出文件写入对象存储
+	CreateScheduledExport(ctx context.Context, se domain.ScheduledExport) (int64, error)
+
+	// UpdateScheduledExport 修改定时导出，重新计算下一次执行时间
+	UpdateScheduledExport(ctx context.Context, se domain.ScheduledExport) (int64, error)
+
+	// DeleteScheduledExport 删除定时导出及其生成的导出文件
+	DeleteScheduledExport(ctx context.Context, id int64) (int64, error)
+
+	// ListScheduledExports 查询定时导出列表
+	ListScheduledExports(ctx context.Context, offset, limit int64) ([]domain.ScheduledExport, int64, error)
+
+	// ListScheduledExportRuns 查询定时导出的执行记录
+	ListScheduledExportRuns(ctx context.Context, scheduleID int64, offset, limit int64) (
+		[]domain.ScheduledExportRun, int64, error)
+
+	// ScheduledExportFileURL 生成执行记录中指定模型导出文件的下载链接
+	ScheduledExportFileURL(ctx context.Context, runID int64, modelUID string) (string, error)
+
+	// RunScheduledExports 执行已到时间的定时导出并清理过期文件，返回执行的数量
+	// NOTE: 由选主成功的实例调用
+	RunScheduledExports(ctx context.Context) (int, error)
 
 	// ExportTemplate 导出模板
-	// modelUID: 模型唯一标识 (对应 Model.UID)
-	ExportTemplate(ctx context.Context, modelUID string) ([]byte, error)
+	// NOTE: CSV 只包含表头，NDJSON 及 YAML 包含一条字段值为空的示例；
+	// Excel 模板按字段类型添加下拉列表、数字及日期验证，必填字段高亮，字段说明作为表头批注
+	ExportTemplate(ctx context.Context, req TemplateParams) ([]byte, error)
+}
+
+// ImportParams 导入任务参数
+type ImportParams struct {
+	ModelUID string
+	FileKey  string // S3 文件 key，由前端通过预签名链接上传
+	FileName string
+	domain.FormatOptions
+	Mode   domain.ImportMode // 为空时默认 upsert
+	DryRun bool              // 预演：只校验并统计，不写入资产
+	// Workbook 多模型工作簿导入，只支持 Excel，按工作表索引或工作表名称识别模型，忽略 ModelUID
+	Workbook bool
+	// MappingID 使用已保存的列映射方案，为 0 时使用 Mapping；两者均为空时按导入模板的字段 UID 解析
+	MappingID int64
+	Mapping   *domain.ImportMapping
+}
+
+// MappingPreviewParams 列映射预览参数
+type MappingPreviewParams struct {
+	ModelUID string
+	FileKey  string
+	domain.FormatOptions
+	HeaderRow int // 表头所在行，为 0 时自动识别
 }
 
 type ExportParams struct {
@@ -29,5 +125,43 @@ type ExportParams struct {
 	ResourceIDs  []int64
 	FilterGroups []domain.FilterGroup
 	Fields       []string
+	Sorts        []domain.ResourceSort // 导出排序，为空时按创建时间倒序
+	Relations    []RelatedFields       // 需要一并导出的关联资产字段
 	FileName     string
+	domain.FormatOptions
+}
+
+// TemplateParams 导入模板参数
+type TemplateParams struct {
+	ModelUID  string
+	Relations []string // 追加的关联资产名称列，Excel 模板中提供对端模型已有资产名称的下拉列表
+	domain.FormatOptions
+}
+
+// WorkbookExportParams 多模型工作簿导出参数，GroupIDs 与 ModelUIDs 指定的模型取并集
+type WorkbookExportParams struct {
+	GroupIDs  []int64
+	ModelUIDs []string
+	// IncludeRelations 追加资产关联工作表，只包含两端模型均在导出范围内的关联
+	IncludeRelations bool
+	FileName         string
+}
+
+const (
+	// RelatedModeJoin 一对多时多个关联资产的值合并到同一单元格
+	RelatedModeJoin = "join"
+	// RelatedModeExplode 一对多时每个关联资产展开为单独一行
+	RelatedModeExplode = "explode"
+
+	// defaultRelatedSeparator 合并模式下默认的分隔符
+	defaultRelatedSeparator = ","
+)
+
+// RelatedFields 关联资产导出配置
+// NOTE: 一对一关联直接平铺为列，一对多关联按 Mode 合并或展开
+type RelatedFields struct {
+	RelationName string   // 模型关联唯一标识，如 host_belong_idc
+	Fields       []string // 关联资产需要导出的字段
+	Mode         string   // join（默认）或 explode，最多允许一个关联使用 explode
+	Separator    string   // join 模式的分隔符，默认为 ","
 }
@@ -0,0 +1,652 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// relationSheetUID 资产关联工作表在工作表索引中的标识，以下划线开头，不会与模型唯一标识冲突
+	relationSheetUID = "_relations"
+	// relationSheetName 资产关联工作表名称
+	relationSheetName = "资产关联(" + relationSheetUID + ")"
+)
+
+// relationSheetAttrs 资产关联工作表的列定义，两端资产均以名称（模型唯一索引）标识
+var relationSheetAttrs = []domain.Attribute{
+	{FieldUid: "relation_name", FieldName: "模型关联唯一标识", Required: true},
+	{FieldUid: "source", FieldName: "源端资产名称", Required: true},
+	{FieldUid: "target", FieldName: "目标端资产名称", Required: true},
+}
+
+// ExportWorkbook 导出多个模型的资产到同一个 Excel 文件
+func (s *dataIOService) ExportWorkbook(ctx context.Context, req WorkbookExportParams, w io.Writer) error {
+	models, err := s.resolveWorkbookModels(ctx, req.GroupIDs, req.ModelUIDs)
+	if err != nil {
+		return err
+	}
+
+	uids := lo.Map(models, func(mdl domain.Model, _ int) string {
+		return mdl.UID
+	})
+	relations, err := s.workbookRelations(ctx, uids)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: 按依赖顺序排列工作表，被关联的模型在前，与导入顺序保持一致
+	plans := make([]exportPlan, 0, len(uids))
+	for _, uid := range orderWorkbookModels(uids, relations) {
+		plan, er := s.prepareExport(ctx, ExportParams{
+			ModelUID:      uid,
+			FormatOptions: domain.FormatOptions{Format: domain.FileFormatExcel},
+		})
+		if er != nil {
+			return er
+		}
+		plans = append(plans, plan)
+	}
+
+	if !req.IncludeRelations {
+		relations = nil
+	}
+	return s.writeWorkbook(ctx, plans, relations, w)
+}
+
+// writeWorkbook 逐个模型流式写入工作表，最后追加资产关联工作表
+// NOTE: relations 为空时不生成资产关联工作表
+func (s *dataIOService) writeWorkbook(ctx context.Context, plans []exportPlan, relations []domain.ModelRelation,
+	w io.Writer) error {
+	var builder *domain.StreamBuilder
+	links := newWorkbookLinks(relations)
+	names := newSheetNames(relationSheetName)
+	refs := make([]domain.SheetRef, 0, len(plans)+1)
+	for i, plan := range plans {
+		sheetName := names.unique(plan.sheetName)
+		refs = append(refs, domain.SheetRef{Sheet: sheetName, ModelUID: plan.query.ModelUID})
+
+		row1, row2, row3 := excelHeaders(plan.attrs, nil)
+		if i == 0 {
+			builder = domain.NewStreamBuilder(sheetName, row1, row2, row3)
+			defer builder.Close()
+		} else if err := builder.NextSheet(sheetName, row1, row2, row3); err != nil {
+			return err
+		}
+		addExportValidations(builder, plan.attrs)
+
+		err := s.resSvc.IterateResourcesByQuery(ctx, plan.fields(), plan.query, exportBatchSize,
+			func(resources []domain.Resource) error {
+				if err := s.collectLinks(ctx, links, plan.query.ModelUID, resources); err != nil {
+					return err
+				}
+				return builder.AddRows(exportRows(plan.attrs, nil, resources, nil))
+			})
+		if err != nil {
+			return fmt.Errorf("导出模型 %s 资产数据失败: %w", plan.query.ModelUID, err)
+		}
+	}
+
+	if len(relations) > 0 {
+		row1, row2, row3 := excelHeaders(relationSheetAttrs, nil)
+		if err := builder.NextSheet(relationSheetName, row1, row2, row3); err != nil {
+			return err
+		}
+		if err := builder.AddRows(links.rows()); err != nil {
+			return err
+		}
+		refs = append(refs, domain.SheetRef{Sheet: relationSheetName, ModelUID: relationSheetUID})
+	}
+
+	_, err := builder.WithSheetIndex(refs).WriteTo(w)
+	return err
+}
+
+// sheetNames 工作簿中已使用的工作表名称，Excel 不区分大小写
+type sheetNames map[string]struct{}
+
+func newSheetNames(reserved ...string) sheetNames {
+	names := make(sheetNames)
+	for _, name := range reserved {
+		names[strings.ToLower(name)] = struct{}{}
+	}
+	return names
+}
+
+// unique 返回不与已有工作表重名的名称，截断后重名时追加 ~n 序号
+func (n sheetNames) unique(name string) string {
+	candidate := name
+	for i := 2; ; i++ {
+		if _, ok := n[strings.ToLower(candidate)]; !ok {
+			n[strings.ToLower(candidate)] = struct{}{}
+			return candidate
+		}
+		candidate = domain.TruncateSheetName(name, fmt.Sprintf("~%d", i))
+	}
+}
+
+// resolveWorkbookModels 合并模型分组及指定的模型，按模型唯一标识去重
+func (s *dataIOService) resolveWorkbookModels(ctx context.Context, groupIDs []int64,
+	modelUIDs []string) ([]domain.Model, error) {
+	if len(groupIDs) == 0 && len(modelUIDs) == 0 {
+		return nil, errs.ValidationError.WithMsg("请选择需要导出的模型分组或模型")
+	}
+
+	var models []domain.Model
+	if len(groupIDs) > 0 {
+		grouped, err := s.modelSvc.ListModelByGroupIds(ctx, groupIDs)
+		if err != nil {
+			return nil, fmt.Errorf("获取模型分组下的模型失败: %w", err)
+		}
+		models = append(models, grouped...)
+	}
+
+	if len(modelUIDs) > 0 {
+		listed, err := s.modelSvc.GetByUids(ctx, modelUIDs)
+		if err != nil {
+			return nil, fmt.Errorf("获取模型信息失败: %w", err)
+		}
+		if missing, _ := lo.Difference(lo.Uniq(modelUIDs), lo.Map(listed, func(mdl domain.Model, _ int) string {
+			return mdl.UID
+		})); len(missing) > 0 {
+			return nil, errs.ValidationError.WithMsg(fmt.Sprintf("模型 %v 不存在", missing))
+		}
+		models = append(models, listed...)
+	}
+
+	models = lo.UniqBy(models, func(mdl domain.Model) string {
+		return mdl.UID
+	})
+	if len(models) == 0 {
+		return nil, errs.ValidationError.WithMsg("所选模型分组下没有模型")
+	}
+	return models, nil
+}
+
+// workbookRelations 查询两端模型均在 uids 中的模型关联
+func (s *dataIOService) workbookRelations(ctx context.Context, uids []string) ([]domain.ModelRelation, error) {
+	diagrams, err := s.rmSvc.FindModelDiagramBySrcUids(ctx, uids)
+	if err != nil {
+		return nil, fmt.Errorf("获取模型关联失败: %w", err)
+	}
+
+	return lo.FilterMap(diagrams, func(d domain.ModelDiagram, _ int) (domain.ModelRelation, bool) {
+		mr := domain.ModelRelation{
+			SourceModelUID:  d.SourceModelUid,
+			TargetModelUID:  d.TargetModelUid,
+			RelationTypeUID: d.RelationTypeUid,
+		}
+		mr.RelationName = mr.RM()
+		return mr, lo.Contains(uids, d.TargetModelUid)
+	}), nil
+}
+
+// orderWorkbookModels 按模型关联排列导入导出顺序，关联的目标端模型排在源端模型之前
+// NOTE: 存在循环依赖时剩余模型保持原有顺序，资产关联在所有模型导入后统一建立，不受顺序影响
+func orderWorkbookModels(uids []string, relations []domain.ModelRelation) []string {
+	deps := make(map[string][]string, len(uids))
+	for _, mr := range relations {
+		if mr.SourceModelUID != mr.TargetModelUID {
+			deps[mr.SourceModelUID] = append(deps[mr.SourceModelUID], mr.TargetModelUID)
+		}
+	}
+
+	ordered := make([]string, 0, len(uids))
+	done := make(map[string]struct{}, len(uids))
+	for len(ordered) < len(uids) {
+		progressed := false
+		for _, uid := range uids {
+			if _, ok := done[uid]; ok {
+				continue
+			}
+			if lo.EveryBy(deps[uid], func(dep string) bool {
+				_, ok := done[dep]
+				return ok || !lo.Contains(uids, dep)
+			}) {
+				ordered = append(ordered, uid)
+				done[uid] = struct{}{}
+				progressed = true
+			}
+		}
+
+		if !progressed {
+			return append(ordered, lo.Filter(uids, func(uid string, _ int) bool {
+				_, ok := done[uid]
+				return !ok
+			})...)
+		}
+	}
+	return ordered
+}
+
+// resourceLink 导出过程中收集的一条资产关联
+type resourceLink struct {
+	relationName string
+	source       int64
+	target       int64
+}
+
+// workbookLinks 收集导出资产的名称及资产关联，所有工作表写完后生成资产关联工作表
+// NOTE: 只保留资产 ID 与名称的映射，内存占用与导出的资产数量成正比
+type workbookLinks struct {
+	relations []domain.ModelRelation
+	names     map[int64]string
+	links     []resourceLink
+}
+
+func newWorkbookLinks(relations []domain.ModelRelation) *workbookLinks {
+	return &workbookLinks{
+		relations: relations,
+		names:     make(map[int64]string),
+	}
+}
+
+// collectLinks 记录一批资产的名称，并查询以该模型为源端的资产关联
+func (s *dataIOService) collectLinks(ctx context.Context, l *workbookLinks, modelUID string,
+	resources []domain.Resource) error {
+	if len(l.relations) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(resources))
+	for _, res := range resources {
+		l.names[res.ID] = res.Name
+		ids = append(ids, res.ID)
+	}
+
+	for _, mr := range l.relations {
+		if mr.SourceModelUID != modelUID {
+			continue
+		}
+
+		related, err := s.rrSvc.ListRelatedIdsBatch(ctx, mr.RelationName, true, ids)
+		if err != nil {
+			return fmt.Errorf("获取资产关联失败: %w", err)
+		}
+		for _, id := range ids {
+			for _, target := range related[id] {
+				l.links = append(l.links, resourceLink{relationName: mr.RelationName, source: id, target: target})
+			}
+		}
+	}
+	return nil
+}
+
+// rows 资产关联工作表的数据行，对端资产不在导出范围内的关联被忽略
+func (l *workbookLinks) rows() [][]interface{} {
+	return lo.FilterMap(l.links, func(link resourceLink, _ int) ([]interface{}, bool) {
+		source, ok1 := l.names[link.source]
+		target, ok2 := l.names[link.target]
+		return []interface{}{link.relationName, source, target}, ok1 && ok2
+	})
+}
+
+// workbookSheet 工作簿中识别出模型的工作表
+type workbookSheet struct {
+	name     string
+	modelUID string // 资产关联工作表为 relationSheetUID
+	rows     [][]string
+}
+
+// parseWorkbook 读取工作簿中能识别出模型的工作表，其他工作表被忽略
+// NOTE: 优先按导出时写入的工作表索引识别，没有索引的工作表再按 lookup（工作表名称 → 模型唯一标识）识别
+func parseWorkbook(fileData []byte, lookup map[string]string) ([]workbookSheet, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("解析 Excel 文件失败: %w", err)
+	}
+	defer f.Close()
+
+	index, err := domain.ReadSheetIndex(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheets []workbookSheet
+	seen := make(map[string]string)
+	for _, name := range f.GetSheetList() {
+		uid, ok := index[name]
+		if !ok {
+			uid, ok = lookup[name]
+		}
+		if !ok {
+			continue
+		}
+		if first, exists := seen[uid]; exists {
+			return nil, fmt.Errorf("工作表 %s 与 %s 对应同一个模型 %s", name, first, uid)
+		}
+		seen[uid] = name
+
+		rows, er := f.GetRows(name)
+		if er != nil {
+			return nil, fmt.Errorf("读取工作表 %s 失败: %w", name, er)
+		}
+		sheets = append(sheets, workbookSheet{name: name, modelUID: uid, rows: rows})
+	}
+
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("没有能识别出模型的工作表，工作表名称需要与导出时的名称 模型名称(模型唯一标识) 一致")
+	}
+	return sheets, nil
+}
+
+// sheetLookup 按当前模型生成工作表名称到模型唯一标识的映射，用于识别没有工作表索引的工作簿
+// NOTE: 截断后重名的工作表名称无法区分对应的模型，不参与识别
+func (s *dataIOService) sheetLookup(ctx context.Context) (map[string]string, error) {
+	models, err := s.modelSvc.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取模型信息失败: %w", err)
+	}
+
+	lookup := map[string]string{relationSheetName: relationSheetUID}
+	counts := lo.CountValuesBy(models, func(mdl domain.Model) string {
+		return mdl.SheetName()
+	})
+	for _, mdl := range models {
+		if name := mdl.SheetName(); counts[name] == 1 {
+			lookup[name] = mdl.UID
+		}
+	}
+	return lookup, nil
+}
+
+// workbookImport 解析完成、等待写入的工作表
+type workbookImport struct {
+	sheet workbookSheet
+	attrs []domain.Attribute
+	data  importSheet
+}
+
+// executeWorkbookImport 按模型依赖顺序导入各工作表，最后建立资产关联
+// NOTE: 所有工作表解析通过后才开始写入，任一工作表格式错误时不写入任何数据
+func (s *dataIOService) executeWorkbookImport(ctx context.Context, job *domain.ImportJob, fileData []byte) error {
+	lookup, err := s.sheetLookup(ctx)
+	if err != nil {
+		return err
+	}
+	sheets, err := parseWorkbook(fileData, lookup)
+	if err != nil {
+		return err
+	}
+	bySheet := lo.KeyBy(sheets, func(sheet workbookSheet) string {
+		return sheet.modelUID
+	})
+	relationSheet, hasRelations := bySheet[relationSheetUID]
+	delete(bySheet, relationSheetUID)
+
+	uids := lo.Keys(bySheet)
+	models, err := s.modelSvc.GetByUids(ctx, uids)
+	if err != nil {
+		return fmt.Errorf("获取模型信息失败: %w", err)
+	}
+	for _, uid := range uids {
+		if !lo.ContainsBy(models, func(mdl domain.Model) bool { return mdl.UID == uid }) {
+			return fmt.Errorf("工作表 %s 对应的模型 %s 不存在", bySheet[uid].name, uid)
+		}
+	}
+
+	// NOTE: 以工作簿中的工作表顺序作为同级模型的导入顺序
+	uids = lo.FilterMap(sheets, func(sheet workbookSheet, _ int) (string, bool) {
+		return sheet.modelUID, sheet.modelUID != relationSheetUID
+	})
+	relations, err := s.workbookRelations(ctx, uids)
+	if err != nil {
+		return err
+	}
+
+	imports := make([]workbookImport, 0, len(uids)+1)
+	for _, uid := range orderWorkbookModels(uids, relations) {
+		attrs, _, er := s.attrSvc.ListAttributes(ctx, uid)
+		if er != nil {
+			return fmt.Errorf("获取模型字段定义失败: %w", er)
+		}
+		if len(attrs) == 0 {
+			return fmt.Errorf("模型 %s 没有定义字段", uid)
+		}
+
+		imp, er := newWorkbookImport(bySheet[uid], attrs)
+		if er != nil {
+			return er
+		}
+		imports = append(imports, imp)
+	}
+	if hasRelations {
+		imp, er := newWorkbookImport(relationSheet, relationSheetAttrs)
+		if er != nil {
+			return er
+		}
+		imports = append(imports, imp)
+	}
+
+	job.Total = lo.SumBy(imports, func(imp workbookImport) int {
+		return len(imp.data.rows)
+	})
+
+	var rowErrors []domain.ImportRowError
+	for _, imp := range imports {
+		result, problems, er := s.importWorkbookSheet(ctx, job, imp)
+		if er != nil {
+			return er
+		}
+		job.Sheets = append(job.Sheets, result)
+		rowErrors = append(rowErrors, problems...)
+	}
+
+	return s.finishImport(ctx, job, domain.FormatOptions{Format: domain.FileFormatExcel}, fileData, rowErrors)
+}
+
+// newWorkbookImport 解析单个工作表，只有表头的工作表视为没有数据
+func newWorkbookImport(sheet workbookSheet, attrs []domain.Attribute) (workbookImport, error) {
+	imp := workbookImport{sheet: sheet, attrs: attrs}
+	if len(sheet.rows) <= importHeaderRows {
+		return imp, nil
+	}
+
+	data, err := excelRowsToSheet(sheet.rows, attrs)
+	if err != nil {
+		return workbookImport{}, fmt.Errorf("工作表 %s: %w", sheet.name, err)
+	}
+	imp.data = data
+	return imp, nil
+}
+
+// importWorkbookSheet 分批导入单个工作表，行级错误标记所在的工作表
+func (s *dataIOService) importWorkbookSheet(ctx context.Context, job *domain.ImportJob,
+	imp workbookImport) (domain.ImportSheetResult, []domain.ImportRowError, error) {
+	result := domain.ImportSheetResult{Sheet: imp.sheet.name, Total: len(imp.data.rows)}
+	if imp.sheet.modelUID != relationSheetUID {
+		result.ModelUID = imp.sheet.modelUID
+	}
+
+	// NOTE: 每个工作表使用独立的任务副本统计新增及修改数量，再累加到工作簿任务
+	sheetJob := domain.ImportJob{ModelUID: result.ModelUID, Mode: job.Mode, DryRun: job.DryRun}
+	inserted, updated := job.Inserted, job.Updated
+	validator := newImportValidator(imp.data, imp.attrs, job.Mode)
+
+	var rowErrors []domain.ImportRowError
+	for _, batch := range lo.Chunk(imp.data.rows, importBatchSize) {
+		if err := ctx.Err(); err != nil {
+			return result, nil, err
+		}
+
+		var problems []domain.ImportRowError
+		if result.ModelUID == "" {
+			problems = s.importRelationBatch(ctx, &sheetJob, imp.data, batch)
+		} else {
+			problems = s.importBatch(ctx, &sheetJob, validator, batch)
+		}
+		for _, e := range problems {
+			e.Sheet = imp.sheet.name
+			rowErrors = append(rowErrors, e)
+		}
+
+		job.Processed += len(batch)
+		job.Inserted, job.Updated = inserted+sheetJob.Inserted, updated+sheetJob.Updated
+		if err := s.jobRepo.UpdateImportJobProgress(ctx, *job); err != nil {
+			return result, nil, err
+		}
+	}
+
+	result.Inserted, result.Updated = sheetJob.Inserted, sheetJob.Updated
+	result.Failed = len(lo.UniqBy(rowErrors, func(e domain.ImportRowError) int {
+		return e.Row
+	}))
+	return result, rowErrors, nil
+}
+
+// importRelationBatch 校验并建立一批资产关联，导入模式的语义与资产一致：
+// create 模式已存在的关联报错，update 模式不存在的关联报错，已存在的关联不重复建立
+func (s *dataIOService) importRelationBatch(ctx context.Context, job *domain.ImportJob, sheet importSheet,
+	batch []importRow) []domain.ImportRowError {
+	var rowErrors []domain.ImportRowError
+	batch = lo.Filter(batch, func(r importRow, _ int) bool {
+		problems := lo.FilterMap(relationSheetAttrs, func(attr domain.Attribute, _ int) (domain.ImportRowError, bool) {
+			_, ok := r.data[attr.FieldUid]
+			return sheet.rowError(r.row, attr.FieldUid, "必填字段 %s 不能为空", attr.FieldName), !ok
+		})
+		rowErrors = append(rowErrors, problems...)
+		return len(problems) == 0
+	})
+	if len(batch) == 0 {
+		return rowErrors
+	}
+
+	mrs, err := s.rmSvc.GetByRelationNames(ctx, lo.Uniq(lo.Map(batch, func(r importRow, _ int) string {
+		return cellString(r.data["relation_name"])
+	})))
+	if err != nil {
+		return append(rowErrors, batchImportErrors(batch, fmt.Errorf("获取模型关联失败: %w", err))...)
+	}
+	relations := lo.KeyBy(mrs, func(mr domain.ModelRelation) string {
+		return mr.RelationName
+	})
+
+	// 1. 按模型批量解析两端资产名称
+	names := make(map[string][]string)
+	for _, r := range batch {
+		if mr, ok := relations[cellString(r.data["relation_name"])]; ok {
+			names[mr.SourceModelUID] = append(names[mr.SourceModelUID], cellString(r.data["source"]))
+			names[mr.TargetModelUID] = append(names[mr.TargetModelUID], cellString(r.data["target"]))
+		}
+	}
+	ids := make(map[string]map[string]int64, len(names))
+	for modelUID, modelNames := range names {
+		if ids[modelUID], err = s.resourceIDsByName(ctx, modelUID, modelNames); err != nil {
+			return append(rowErrors, batchImportErrors(batch, err)...)
+		}
+	}
+
+	// 2. 校验关联定义及两端资产，并查询已存在的关联
+	type pending struct {
+		row      importRow
+		relation domain.ResourceRelation
+	}
+	var valid []pending
+	for _, r := range batch {
+		relationName := cellString(r.data["relation_name"])
+		mr, ok := relations[relationName]
+		if !ok {
+			rowErrors = append(rowErrors, sheet.rowError(r.row, "relation_name", "模型关联 %s 不存在", relationName))
+			continue
+		}
+
+		source, ok := ids[mr.SourceModelUID][cellString(r.data["source"])]
+		if !ok {
+			rowErrors = append(rowErrors, sheet.rowError(r.row, "source", "模型 %s 中不存在资产 %s",
+				mr.SourceModelUID, cellString(r.data["source"])))
+			continue
+		}
+		target, ok := ids[mr.TargetModelUID][cellString(r.data["target"])]
+		if !ok {
+			rowErrors = append(rowErrors, sheet.rowError(r.row, "target", "模型 %s 中不存在资产 %s",
+				mr.TargetModelUID, cellString(r.data["target"])))
+			continue
+		}
+
+		valid = append(valid, pending{row: r, relation: domain.ResourceRelation{
+			SourceModelUID:   mr.SourceModelUID,
+			TargetModelUID:   mr.TargetModelUID,
+			SourceResourceID: source,
+			TargetResourceID: target,
+			RelationTypeUID:  mr.RelationTypeUID,
+			RelationName:     mr.RelationName,
+		}})
+	}
+
+	existing := make(map[string]map[int64][]int64)
+	for relationName, group := range lo.GroupBy(valid, func(p pending) string {
+		return p.relation.RelationName
+	}) {
+		if existing[relationName], err = s.rrSvc.ListRelatedIdsBatch(ctx, relationName, true,
+			lo.Uniq(lo.Map(group, func(p pending, _ int) int64 {
+				return p.relation.SourceResourceID
+			}))); err != nil {
+			return append(rowErrors, batchImportErrors(lo.Map(group, func(p pending, _ int) importRow {
+				return p.row
+			}), fmt.Errorf("查询已存在资产关联失败: %w", err))...)
+		}
+	}
+
+	// 3. 逐条建立关联，由关联服务校验映射约束
+	for _, p := range valid {
+		exists := lo.Contains(existing[p.relation.RelationName][p.relation.SourceResourceID],
+			p.relation.TargetResourceID)
+		switch {
+		case exists && job.Mode == domain.ImportModeCreate:
+			rowErrors = append(rowErrors, sheet.rowError(p.row.row, "relation_name", "资产关联已存在"))
+			continue
+		case !exists && job.Mode == domain.ImportModeUpdate:
+			rowErrors = append(rowErrors, sheet.rowError(p.row.row, "relation_name", "资产关联不存在"))
+			continue
+		case exists:
+			job.Updated++
+			continue
+		}
+
+		if !job.DryRun {
+			if _, err = s.rrSvc.CreateResourceRelation(ctx, p.relation); err != nil {
+				rowErrors = append(rowErrors, sheet.rowError(p.row.row, "relation_name", "建立资产关联失败: %s",
+					err.Error()))
+				continue
+			}
+		}
+		// NOTE: 同一关联在文件中重复出现时只建立一次
+		if existing[p.relation.RelationName] == nil {
+			existing[p.relation.RelationName] = make(map[int64][]int64)
+		}
+		related := existing[p.relation.RelationName]
+		related[p.relation.SourceResourceID] = append(related[p.relation.SourceResourceID], p.relation.TargetResourceID)
+		job.Inserted++
+	}
+	return rowErrors
+}
+
+// resourceIDsByName 按名称查询模型下的资产 ID
+func (s *dataIOService) resourceIDsByName(ctx context.Context, modelUID string, names []string) (map[string]int64,
+	error) {
+	ids := make(map[string]int64, len(names))
+	err := s.resSvc.IterateResourcesByQuery(ctx, []string{"name"}, domain.ResourceQuery{
+		ModelUID: modelUID,
+		FilterGroups: []domain.FilterGroup{{Filters: []domain.FilterCondition{{
+			FieldUID: "name",
+			Operator: domain.OperatorIn,
+			Value:    lo.Uniq(names),
+		}}}},
+	}, importBatchSize, func(resources []domain.Resource) error {
+		for _, res := range resources {
+			ids[res.Name] = res.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询模型 %s 的资产失败: %w", modelUID, err)
+	}
+	return ids, nil
+}
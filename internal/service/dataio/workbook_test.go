@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/mocks/resourcemocks"
+	relation "github.com/Duke1616/ecmdb/internal/service/relation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+var hostBelongIdc = domain.ModelRelation{
+	SourceModelUID:  "host",
+	TargetModelUID:  "idc",
+	RelationTypeUID: "belong",
+	RelationName:    "host_belong_idc",
+}
+
+// stubRelationModels 只实现按名称查询模型关联
+type stubRelationModels struct {
+	relation.RelationModelService
+	relations []domain.ModelRelation
+}
+
+func (s *stubRelationModels) GetByRelationNames(_ context.Context, names []string) ([]domain.ModelRelation, error) {
+	var mrs []domain.ModelRelation
+	for _, mr := range s.relations {
+		for _, name := range names {
+			if mr.RelationName == name {
+				mrs = append(mrs, mr)
+			}
+		}
+	}
+	return mrs, nil
+}
+
+// stubRelationResources 以内存记录资产关联：关联名称 → 源端资产 ID → 目标端资产 ID
+type stubRelationResources struct {
+	relation.RelationResourceService
+	related map[string]map[int64][]int64
+	created []domain.ResourceRelation
+}
+
+func (s *stubRelationResources) ListRelatedIdsBatch(_ context.Context, relationName string, _ bool,
+	ids []int64) (map[int64][]int64, error) {
+	result := make(map[int64][]int64)
+	for _, id := range ids {
+		if targets, ok := s.related[relationName][id]; ok {
+			result[id] = targets
+		}
+	}
+	return result, nil
+}
+
+func (s *stubRelationResources) CreateResourceRelation(_ context.Context, req domain.ResourceRelation) (int64, error) {
+	s.created = append(s.created, req)
+	return int64(len(s.created)), nil
+}
+
+func TestOrderWorkbookModels(t *testing.T) {
+	testCases := []struct {
+		name      string
+		uids      []string
+		relations []domain.ModelRelation
+		want      []string
+	}{
+		{
+			name:      "目标端模型在前",
+			uids:      []string{"app", "host", "idc"},
+			relations: []domain.ModelRelation{hostBelongIdc, {SourceModelUID: "app", TargetModelUID: "host"}},
+			want:      []string{"idc", "host", "app"},
+		},
+		{
+			name:      "自关联不影响顺序",
+			uids:      []string{"host", "idc"},
+			relations: []domain.ModelRelation{{SourceModelUID: "host", TargetModelUID: "host"}},
+			want:      []string{"host", "idc"},
+		},
+		{
+			name: "循环依赖保持原有顺序",
+			uids: []string{"idc", "a", "b"},
+			relations: []domain.ModelRelation{
+				{SourceModelUID: "a", TargetModelUID: "b"},
+				{SourceModelUID: "b", TargetModelUID: "a"},
+			},
+			want: []string{"idc", "a", "b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, orderWorkbookModels(tc.uids, tc.relations))
+		})
+	}
+}
+
+func TestWriteWorkbook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources := map[string][]domain.Resource{
+		"idc": {{ID: 10, Name: "idc-a", Data: map[string]interface{}{"name": "idc-a"}}},
+		"host": {
+			{ID: 1, Name: "web01", Data: map[string]interface{}{"name": "web01", "ip": "10.0.0.1"}},
+			{ID: 2, Name: "web02", Data: map[string]interface{}{"name": "web02", "ip": "10.0.0.2"}},
+		},
+	}
+	resSvc := resourcemocks.NewMockEncryptedSvc(ctrl)
+	resSvc.EXPECT().IterateResourcesByQuery(gomock.Any(), gomock.Any(), gomock.Any(), exportBatchSize, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, query domain.ResourceQuery, _ int,
+			fn func([]domain.Resource) error) error {
+			return fn(resources[query.ModelUID])
+		}).Times(2)
+
+	svc := &dataIOService{
+		resSvc: resSvc,
+		rrSvc: &stubRelationResources{related: map[string]map[int64][]int64{
+			// 对端资产 99 不在导出范围内，该关联被忽略
+			"host_belong_idc": {1: {10, 99}},
+		}},
+	}
+	plans := []exportPlan{
+		{sheetName: "机房(idc)", attrs: importAttrs[:1], query: domain.ResourceQuery{ModelUID: "idc"}},
+		{sheetName: "主机(host)", attrs: importAttrs, query: domain.ResourceQuery{ModelUID: "host"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.writeWorkbook(context.Background(), plans, []domain.ModelRelation{hostBelongIdc}, &buf))
+
+	// 按工作表索引识别，与工作表名称无关
+	sheets, err := parseWorkbook(buf.Bytes(), nil)
+	require.NoError(t, err)
+	require.Len(t, sheets, 3)
+	assert.Equal(t, []string{"idc", "host", relationSheetUID},
+		[]string{sheets[0].modelUID, sheets[1].modelUID, sheets[2].modelUID})
+	assert.Equal(t, []string{"web02", "10.0.0.2"}, sheets[1].rows[4])
+	assert.Equal(t, [][]string{{"host_belong_idc", "web01", "idc-a"}}, sheets[2].rows[importHeaderRows:])
+
+	// 每个工作表都可以按模型字段解析
+	hosts, err := excelRowsToSheet(sheets[1].rows, importAttrs)
+	require.NoError(t, err)
+	assert.Len(t, hosts.rows, 2)
+}
+
+func TestSheetNamesUnique(t *testing.T) {
+	names := newSheetNames(relationSheetName)
+	long := strings.Repeat("a", 31)
+
+	assert.Equal(t, "主机(host)", names.unique("主机(host)"))
+	assert.Equal(t, "主机(HOST)~2", names.unique("主机(HOST)"))
+	assert.Equal(t, long, names.unique(long))
+	assert.Equal(t, strings.Repeat("a", 29)+"~2", names.unique(long))
+	assert.Equal(t, "资产关联(_relations)~2", names.unique(relationSheetName))
+}
+
+func TestParseWorkbookByLookup(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetName(f.GetSheetName(0), "主机(host)"))
+	_, err := f.NewSheet("备注")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	sheets, err := parseWorkbook(buf.Bytes(), map[string]string{"主机(host)": "host"})
+	require.NoError(t, err)
+	require.Len(t, sheets, 1)
+	assert.Equal(t, "host", sheets[0].modelUID)
+
+	_, err = parseWorkbook(buf.Bytes(), nil)
+	assert.Error(t, err)
+}
+
+func TestImportRelationBatch(t *testing.T) {
+	rows := [][]string{
+		{}, {"relation_name", "source", "target"}, {},
+		{"host_belong_idc", "web01", "idc-a"},
+		{"host_belong_idc", "web02", "idc-a"},
+		{"host_belong_idc", "web02", "idc-a"},
+		{"host_run_app", "web01", "app"},
+		{"host_belong_idc", "web09", "idc-a"},
+		{"host_belong_idc", "", "idc-a"},
+	}
+	sheet, err := excelRowsToSheet(rows, relationSheetAttrs)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		mode       domain.ImportMode
+		dryRun     bool
+		wantCells  []string
+		wantInsert int
+		wantUpdate int
+		wantCreate int
+	}{
+		{
+			name:       "upsert 已存在的关联不重复建立",
+			mode:       domain.ImportModeUpsert,
+			wantCells:  []string{"A7", "B8", "B9"},
+			wantInsert: 1,
+			wantUpdate: 2,
+			wantCreate: 1,
+		},
+		{
+			name:       "create 模式已存在的关联报错",
+			mode:       domain.ImportModeCreate,
+			wantCells:  []string{"A4", "A6", "A7", "B8", "B9"},
+			wantInsert: 1,
+			wantCreate: 1,
+		},
+		{
+			name:       "预演不建立关联",
+			mode:       domain.ImportModeUpsert,
+			dryRun:     true,
+			wantCells:  []string{"A7", "B8", "B9"},
+			wantInsert: 1,
+			wantUpdate: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			resources := map[string][]domain.Resource{
+				"host": {{ID: 1, Name: "web01"}, {ID: 2, Name: "web02"}},
+				"idc":  {{ID: 10, Name: "idc-a"}},
+			}
+			resSvc := resourcemocks.NewMockEncryptedSvc(ctrl)
+			resSvc.EXPECT().IterateResourcesByQuery(gomock.Any(), []string{"name"}, gomock.Any(), importBatchSize,
+				gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ []string, query domain.ResourceQuery, _ int,
+					fn func([]domain.Resource) error) error {
+					return fn(resources[query.ModelUID])
+				}).Times(2)
+
+			rrSvc := &stubRelationResources{related: map[string]map[int64][]int64{
+				"host_belong_idc": {1: {10}},
+			}}
+			svc := &dataIOService{
+				resSvc: resSvc,
+				rmSvc:  &stubRelationModels{relations: []domain.ModelRelation{hostBelongIdc}},
+				rrSvc:  rrSvc,
+			}
+
+			job := &domain.ImportJob{Mode: tc.mode, DryRun: tc.dryRun}
+			rowErrors := svc.importRelationBatch(context.Background(), job, sheet, sheet.rows)
+
+			cells := make([]string, 0, len(rowErrors))
+			for _, e := range rowErrors {
+				cells = append(cells, e.Cell)
+			}
+			assert.ElementsMatch(t, tc.wantCells, cells)
+			assert.Equal(t, tc.wantInsert, job.Inserted)
+			assert.Equal(t, tc.wantUpdate, job.Updated)
+			require.Len(t, rrSvc.created, tc.wantCreate)
+			if tc.wantCreate > 0 {
+				assert.Equal(t, int64(2), rrSvc.created[0].SourceResourceID)
+				assert.Equal(t, int64(10), rrSvc.created[0].TargetResourceID)
+			}
+		})
+	}
+}
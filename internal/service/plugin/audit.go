@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+const (
+	// defaultAuditRetention 未配置保留时长时审计日志保留 90 天
+	defaultAuditRetention = 90 * 24 * time.Hour
+	// maxAuditPageSize 审计日志单页查询上限
+	maxAuditPageSize = 200
+)
+
+// AuditEventProducer 推送插件动作审计事件，供 SIEM 等外部系统采集
+type AuditEventProducer interface {
+	Produce(ctx context.Context, evt domain.PluginAuditLog) error
+}
+
+// AuditConfig 插件动作审计配置
+type AuditConfig struct {
+	Retention time.Duration      // 审计日志保留时长
+	Producer  AuditEventProducer // 为空时只写入 Mongo，不推送审计事件
+}
+
+type auditSourceKey struct{}
+
+type auditSource struct {
+	source string
+	ip     string
+}
+
+// WithAuditSource 标记动作解析的调用来源和来源 IP，未标记时视为内置后端能力调用
+func WithAuditSource(ctx context.Context, source, ip string) context.Context {
+	return context.WithValue(ctx, auditSourceKey{}, auditSource{source: source, ip: ip})
+}
+
+func auditSourceFrom(ctx context.Context) auditSource {
+	if source, ok := ctx.Value(auditSourceKey{}).(auditSource); ok {
+		return source
+	}
+	return auditSource{source: domain.PluginAuditSourceInternal}
+}
+
+func (s *service) RecordAudit(ctx context.Context, log domain.PluginAuditLog) error {
+	now := time.Now()
+	if log.Ctime == 0 {
+		log.Ctime = now.UnixMilli()
+	}
+	if log.UserID == 0 {
+		log.UserID = ctxutil.GetUserID(ctx).Int64()
+	}
+	if log.TenantID == 0 {
+		log.TenantID = ctxutil.GetTenantID(ctx).Int64()
+	}
+	// 插件以自身凭证调用或代理令牌校验失败时没有用户租户，记录到系统租户
+	if log.TenantID <= 0 {
+		log.TenantID = ctxutil.SystemTenantID
+	}
+
+	retention := s.audit.Retention
+	if retention <= 0 {
+		retention = defaultAuditRetention
+	}
+	log.ExpireAt = time.UnixMilli(log.Ctime).Add(retention).UnixMilli()
+
+	// 审计不跟随请求取消，客户端断开后仍需落库
+	ctx = ctxutil.WithTenantID(context.WithoutCancel(ctx), log.TenantID)
+	err := s.repo.CreateAuditLog(ctx, log)
+	if s.audit.Producer != nil {
+		err = errors.Join(err, s.audit.Producer.Produce(ctx, log))
+	}
+	return err
+}
+
+func (s *service) ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]domain.PluginAuditLog, int64, error) {
+	if query.Limit <= 0 || query.Limit > maxAuditPageSize {
+		query.Limit = maxAuditPageSize
+	}
+
+	logs, err := s.repo.ListAuditLogs(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.TotalAuditLogs(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// auditAction 记录动作解析结果，审计写入失败只打印日志，不影响动作本身
+func (s *service) auditAction(
+	ctx context.Context,
+	pluginID, action string,
+	resourceIDs []int64,
+	start time.Time,
+	err error,
+) {
+	source := auditSourceFrom(ctx)
+	log := domain.PluginAuditLog{
+		PluginID:    pluginID,
+		Action:      action,
+		ResourceIDs: resourceIDs,
+		Source:      source.source,
+		SourceIP:    source.ip,
+		Success:     err == nil,
+		DurationMs:  time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+
+	if er := s.RecordAudit(ctx, log); er != nil {
+		elog.DefaultLogger.Error("记录插件审计日志失败", elog.FieldErr(er),
+			elog.String("plugin_id", pluginID), elog.String("action", action))
+	}
+}
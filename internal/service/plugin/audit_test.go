@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+)
+
+func TestResolveActionContextRecordsAudit(t *testing.T) {
+	tests := []struct {
+		name        string
+		granted     map[string]bool
+		wantSuccess bool
+	}{
+		{
+			name:        "resolved",
+			granted:     map[string]bool{"cmdb:ssh:terminal": true},
+			wantSuccess: true,
+		},
+		{
+			name: "permission denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPermissionTestService(t, "cmdb:ssh:terminal", tt.granted, stubSecureFields{})
+			ctx := ctxutil.WithUserID(ctxutil.WithTenantID(context.Background(), 7), 42)
+			ctx = WithAuditSource(ctx, domain.PluginAuditSourceGRPC, "10.0.0.1")
+
+			_, _ = svc.ResolveActionContext(ctx, pluginx.ResolveRequest{
+				PluginID:   "builtin.ssh",
+				Action:     "terminal",
+				ResourceID: 1,
+			})
+
+			logs := svc.repo.(*stubPluginRepo).auditLogs
+			if len(logs) != 1 {
+				t.Fatalf("expected 1 audit log, got %d", len(logs))
+			}
+			log := logs[0]
+			if log.PluginID != "builtin.ssh" || log.Action != "terminal" || len(log.ResourceIDs) != 1 || log.ResourceIDs[0] != 1 {
+				t.Fatalf("unexpected audit target: %#v", log)
+			}
+			if log.UserID != 42 || log.TenantID != 7 || log.Source != domain.PluginAuditSourceGRPC || log.SourceIP != "10.0.0.1" {
+				t.Fatalf("unexpected audit caller: %#v", log)
+			}
+			if log.Success != tt.wantSuccess || (log.Error == "") == !tt.wantSuccess {
+				t.Fatalf("unexpected audit result: success=%v error=%q", log.Success, log.Error)
+			}
+		})
+	}
+}
+
+func TestRecordAudit(t *testing.T) {
+	repo := &stubPluginRepo{}
+	producer := &stubAuditProducer{}
+	svc := &service{repo: repo, audit: AuditConfig{Retention: time.Hour, Producer: producer}}
+
+	err := svc.RecordAudit(context.Background(), domain.PluginAuditLog{
+		PluginID: "builtin.ssh",
+		Source:   domain.PluginAuditSourceProxy,
+		Ctime:    1000,
+	})
+	if err != nil {
+		t.Fatalf("RecordAudit() error = %v", err)
+	}
+
+	log := repo.auditLogs[0]
+	if log.TenantID != ctxutil.SystemTenantID {
+		t.Fatalf("expected audit without tenant recorded to system tenant, got %d", log.TenantID)
+	}
+	if log.ExpireAt != 1000+time.Hour.Milliseconds() {
+		t.Fatalf("unexpected expire_at: %d", log.ExpireAt)
+	}
+	if len(producer.produced) != 1 || producer.produced[0].PluginID != "builtin.ssh" {
+		t.Fatalf("expected audit event to be produced, got %#v", producer.produced)
+	}
+
+	producer.err = errors.New("kafka unavailable")
+	if err = svc.RecordAudit(context.Background(), domain.PluginAuditLog{PluginID: "builtin.ssh"}); err == nil {
+		t.Fatal("expected producer error to be returned")
+	}
+	if len(repo.auditLogs) != 2 {
+		t.Fatalf("expected audit log persisted even when producing fails, got %d", len(repo.auditLogs))
+	}
+}
+
+type stubAuditProducer struct {
+	produced []domain.PluginAuditLog
+	err      error
+}
+
+func (s *stubAuditProducer) Produce(ctx context.Context, evt domain.PluginAuditLog) error {
+	s.produced = append(s.produced, evt)
+	return s.err
+}
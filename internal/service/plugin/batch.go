@@ -0,0 +1,171 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// batchResolveLimit 单次批量解析的资源数量上限
+	batchResolveLimit = 200
+	// batchResolveConcurrency 同时解析的资源数量
+	batchResolveConcurrency = 8
+)
+
+func (s *service) BatchResolveAction(
+	ctx context.Context,
+	req domain.BatchResolvePluginAction,
+) (result pluginx.BatchResolveResult, err error) {
+	resourceIDs := req.ResourceIDs
+	defer func(start time.Time) {
+		auditErr := err
+		if auditErr == nil && result.Failed > 0 {
+			auditErr = fmt.Errorf("%d 个资源解析失败", result.Failed)
+		}
+		s.auditAction(ctx, req.PluginID, req.Action, resourceIDs, start, auditErr)
+	}(time.Now())
+
+	plugin, err := s.loadPlugin(ctx, req.PluginID)
+	if err != nil {
+		return pluginx.BatchResolveResult{}, err
+	}
+	action, ok := plugin.FindAction(req.Action)
+	if !ok {
+		return pluginx.BatchResolveResult{}, fmt.Errorf("插件动作不存在: %s", req.Action)
+	}
+	if action.EntryMode() == pluginx.EntryModeNone {
+		return pluginx.BatchResolveResult{}, errs.ValidationError.WithMsg(
+			fmt.Sprintf("无主资源的插件动作不支持批量执行: %s/%s", plugin.UID, action.Action),
+		)
+	}
+	if !action.Batch {
+		return pluginx.BatchResolveResult{}, errs.ValidationError.WithMsg(
+			fmt.Sprintf("插件动作不支持批量执行: %s/%s", plugin.UID, action.Action),
+		)
+	}
+
+	ids, err := s.batchResourceIDs(ctx, req)
+	if err != nil {
+		return pluginx.BatchResolveResult{}, err
+	}
+	resourceIDs = ids
+
+	var (
+		eg        errgroup.Group
+		items     = make([]pluginx.BatchResolveItem, len(ids))
+		authorize = newBatchAuthorizer(s.authorizeAction)
+	)
+	eg.SetLimit(batchResolveConcurrency)
+	for i, resourceID := range ids {
+		eg.Go(func() error {
+			items[i] = s.resolveBatchItem(ctx, pluginx.ResolveRequest{
+				PluginID:   req.PluginID,
+				Action:     req.Action,
+				ResourceID: resourceID,
+				Params:     req.Params,
+			}, authorize.authorize)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	result = pluginx.BatchResolveResult{
+		PluginID: plugin.UID,
+		Action:   action.Action,
+		Items:    items,
+	}
+	for _, item := range items {
+		if item.Result != nil {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// batchResourceIDs 确定批量解析的目标资源，未指定资源 ID 时按模型过滤条件查询
+func (s *service) batchResourceIDs(ctx context.Context, req domain.BatchResolvePluginAction) ([]int64, error) {
+	if len(req.ResourceIDs) > 0 {
+		ids := lo.Uniq(req.ResourceIDs)
+		if len(ids) > batchResolveLimit {
+			return nil, errs.ValidationError.WithMsg(
+				fmt.Sprintf("批量执行的资源数量不能超过 %d", batchResolveLimit),
+			)
+		}
+		return ids, nil
+	}
+
+	modelUID := strings.TrimSpace(req.ModelUID)
+	if modelUID == "" {
+		return nil, errs.ValidationError.WithMsg("resource_ids 与 model_uid 不能同时为空")
+	}
+	resources, total, err := s.resolver.resources.ListResourcesWithFilters(
+		ctx, nil, modelUID, nil, 0, batchResolveLimit, req.FilterGroups,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if total > batchResolveLimit {
+		return nil, errs.ValidationError.WithMsg(
+			fmt.Sprintf("筛选命中 %d 个资源，批量执行的资源数量不能超过 %d", total, batchResolveLimit),
+		)
+	}
+	return lo.Map(resources, func(resource domain.Resource, _ int) int64 {
+		return resource.ID
+	}), nil
+}
+
+// resolveBatchItem 解析单个资源，失败时记录原因而不中断整个批次
+func (s *service) resolveBatchItem(
+	ctx context.Context,
+	req pluginx.ResolveRequest,
+	authorize func(ctx context.Context, target actionTarget) error,
+) pluginx.BatchResolveItem {
+	item := pluginx.BatchResolveItem{ResourceID: req.ResourceID}
+	actionCtx, err := s.resolveActionContext(ctx, req, authorize)
+	if err == nil {
+		var result pluginx.ResolveResult
+		if result, err = s.signedResolveResult(ctx, actionCtx); err == nil {
+			item.Result = &result
+			return item
+		}
+	}
+
+	item.Error = err.Error()
+	item.Missing, _ = missingInputReasons(err)
+	return item
+}
+
+// batchAuthorizer 缓存同一批次内各绑定的鉴权结果，避免逐个资源重复查询权限中心
+type batchAuthorizer struct {
+	authorizeFn func(ctx context.Context, target actionTarget) error
+
+	mu      sync.Mutex
+	results map[string]error
+}
+
+func newBatchAuthorizer(fn func(ctx context.Context, target actionTarget) error) *batchAuthorizer {
+	return &batchAuthorizer{authorizeFn: fn, results: make(map[string]error)}
+}
+
+func (a *batchAuthorizer) authorize(ctx context.Context, target actionTarget) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err, ok := a.results[target.binding.UID]; ok {
+		return err
+	}
+	err := a.authorizeFn(ctx, target)
+	a.results[target.binding.UID] = err
+	return err
+}
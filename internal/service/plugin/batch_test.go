@@ -0,0 +1,103 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+)
+
+func TestBatchResolveAction(t *testing.T) {
+	svc := newBatchTestService(t, true)
+
+	result, err := svc.BatchResolveAction(context.Background(), domain.BatchResolvePluginAction{
+		PluginID:    "builtin.ssh",
+		Action:      "terminal",
+		ResourceIDs: []int64{1, 2, 1},
+	})
+	if err != nil {
+		t.Fatalf("BatchResolveAction() error = %v", err)
+	}
+	if len(result.Items) != 2 || result.Succeeded != 1 || result.Failed != 1 {
+		t.Fatalf("unexpected batch result: %#v", result)
+	}
+
+	ok, missing := result.Items[0], result.Items[1]
+	if ok.ResourceID != 1 || ok.Result == nil || ok.Result.Token == "" {
+		t.Fatalf("expected resource 1 resolved with token, got %#v", ok)
+	}
+	if missing.ResourceID != 2 || missing.Result != nil || len(missing.Missing) != 1 || missing.Missing[0] != "target.ip 不能为空" {
+		t.Fatalf("expected resource 2 missing target.ip, got %#v", missing)
+	}
+
+	checker := svc.permissions.(*stubPermissionChecker)
+	if len(checker.queried) != 1 {
+		t.Fatalf("expected permissions to be queried once per binding, got %d", len(checker.queried))
+	}
+}
+
+func TestBatchResolveActionRejects(t *testing.T) {
+	tooMany := make([]int64, batchResolveLimit+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+
+	tests := []struct {
+		name  string
+		batch bool
+		ids   []int64
+	}{
+		{name: "action without batch flag", ids: []int64{1}},
+		{name: "too many resources", batch: true, ids: tooMany},
+		{name: "neither resource ids nor model", batch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newBatchTestService(t, tt.batch)
+
+			_, err := svc.BatchResolveAction(context.Background(), domain.BatchResolvePluginAction{
+				PluginID:    "builtin.ssh",
+				Action:      "terminal",
+				ResourceIDs: tt.ids,
+			})
+			if !errors.Is(err, errs.ValidationError) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveActionContextKeepsMissingInputReasons(t *testing.T) {
+	svc := newBatchTestService(t, false)
+
+	_, err := svc.ResolveActionContext(context.Background(), pluginx.ResolveRequest{
+		PluginID:   "builtin.ssh",
+		Action:     "terminal",
+		ResourceID: 2,
+	})
+	if !errors.Is(err, errs.ValidationError) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if reasons, ok := missingInputReasons(err); !ok || len(reasons) != 1 {
+		t.Fatalf("expected missing input reasons, got %v", reasons)
+	}
+}
+
+func newBatchTestService(t *testing.T, batch bool) *service {
+	t.Helper()
+
+	svc := newPermissionTestService(t, "cmdb:ssh:terminal", map[string]bool{"cmdb:ssh:terminal": true}, stubSecureFields{})
+	svc.tokens = pluginx.NewTokenSigner("secret", time.Minute)
+
+	repo := svc.repo.(*stubPluginRepo)
+	repo.plugin.Actions[0].Batch = batch
+
+	resources := svc.resolver.resources.(*stubResourceReader)
+	resources.findByID[2] = domain.Resource{ID: 2, Name: "host-02", ModelUID: "host", Data: map[string]any{}}
+	return svc
+}
@@ -0,0 +1,141 @@
+package plugin
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+)
+
+// ErrInvalidCredential 插件凭证缺失或不匹配
+var ErrInvalidCredential = errors.New("插件凭证无效")
+
+func (s *service) IssueCredential(ctx context.Context, pluginID string) (string, error) {
+	pluginID = strings.TrimSpace(pluginID)
+	if pluginID == "" {
+		return "", errs.ValidationError.WithMsg("plugin_id 不能为空")
+	}
+
+	secret, err := pluginx.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+	// 身份签名密钥只能由密钥明文派生，签发时派生后加密保存，之后无需明文即可签发身份信息
+	identityKey, err := s.crypto.Encrypt(hex.EncodeToString(pluginx.IdentityKey(secret)))
+	if err != nil {
+		return "", fmt.Errorf("加密插件身份签名密钥失败: %w", err)
+	}
+
+	// 插件统一归属系统租户，凭证也固定存放在系统租户下
+	if err = s.repo.UpsertCredential(systemContext(ctx), domain.PluginCredential{
+		PluginID:    pluginID,
+		SecretHash:  pluginx.HashSecret(secret),
+		IdentityKey: identityKey,
+	}); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s *service) VerifyCredential(ctx context.Context, pluginID, secret string) error {
+	credential, err := s.repo.GetCredential(systemContext(ctx), pluginID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return fmt.Errorf("%w: 插件 %s 尚未签发凭证", ErrInvalidCredential, pluginID)
+	}
+	if err != nil {
+		return err
+	}
+	if !credential.Verify(secret) {
+		return fmt.Errorf("%w: 插件 %s 密钥不匹配", ErrInvalidCredential, pluginID)
+	}
+	return nil
+}
+
+func (s *service) VerifyActionToken(pluginID, token string) (pluginx.ActionTokenClaims, error) {
+	claims, err := s.tokens.Verify(token)
+	if err != nil {
+		return pluginx.ActionTokenClaims{}, err
+	}
+	if claims.PluginID != pluginID {
+		return pluginx.ActionTokenClaims{}, pluginx.ErrInvalidActionToken
+	}
+	return claims, nil
+}
+
+func (s *service) SignIdentity(
+	ctx context.Context,
+	plugin domain.Plugin,
+	claims pluginx.ActionTokenClaims,
+	requestID string,
+) (string, error) {
+	credential, err := s.repo.GetCredential(systemContext(ctx), plugin.UID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return "", fmt.Errorf("%w: 插件 %s 尚未签发凭证", ErrInvalidCredential, plugin.UID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// 以令牌中的用户身份查询授权，代理路由本身没有登录态
+	userCtx := ctxutil.WithUserID(ctxutil.WithTenantID(ctx, claims.TenantID), claims.UserID)
+	codes := lo.Map(plugin.ActionPermissions(), func(p pluginx.ActionPermission, _ int) string {
+		return p.Code
+	})
+	granted, err := s.grantedPermissions(userCtx, codes)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := s.identityKey(credential)
+	if err != nil {
+		return "", err
+	}
+
+	return pluginx.SignIdentity(key, pluginx.Identity{
+		PluginID:    plugin.UID,
+		Action:      claims.Action,
+		ResourceID:  claims.ResourceID,
+		ResourceIDs: claims.ResourceIDs,
+		UserID:      claims.UserID,
+		TenantID:    claims.TenantID,
+		Permissions: lo.Filter(lo.Uniq(codes), func(code string, _ int) bool { return granted[code] }),
+		RequestID:   requestID,
+	}, time.Now())
+}
+
+// identityKey 解密凭证中保存的身份签名密钥
+func (s *service) identityKey(credential domain.PluginCredential) ([]byte, error) {
+	if credential.IdentityKey == "" {
+		return nil, fmt.Errorf("%w: 插件 %s 的凭证缺少身份签名密钥，请重新签发凭证", ErrInvalidCredential, credential.PluginID)
+	}
+	encoded, err := s.crypto.Decrypt(credential.IdentityKey)
+	if err != nil {
+		return nil, fmt.Errorf("解密插件身份签名密钥失败: %w", err)
+	}
+	return hex.DecodeString(encoded)
+}
+
+// signActionToken 为已完成鉴权的动作签发访问插件运行时代理的令牌
+func (s *service) signActionToken(ctx context.Context, actionCtx pluginx.ActionContext) (string, int64, error) {
+	return s.tokens.Sign(pluginx.ActionTokenClaims{
+		PluginID:    actionCtx.Plugin.UID,
+		Action:      actionCtx.Action.Action,
+		ResourceID:  actionCtx.ResourceID,
+		ResourceIDs: actionCtx.ResourceIDs,
+		ModelUID:    actionCtx.Binding.ModelUID,
+		UserID:      ctxutil.GetUserID(ctx).Int64(),
+		TenantID:    ctxutil.GetTenantID(ctx).Int64(),
+	})
+}
+
+func systemContext(ctx context.Context) context.Context {
+	return ctxutil.WithTenantID(ctx, ctxutil.SystemTenantID)
+}
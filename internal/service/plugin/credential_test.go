@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/pkg/cryptox"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+)
+
+func testCrypto() cryptox.Crypto {
+	return cryptox.NewCryptoManager("V2").
+		Register("V2", cryptox.MustNewAESCryptoV2("1234567890abcdef1234567890abcdef"))
+}
+
+func TestIssueAndVerifyCredential(t *testing.T) {
+	repo := &stubPluginRepo{}
+	svc := &service{repo: repo, crypto: testCrypto()}
+
+	secret, err := svc.IssueCredential(context.Background(), "builtin.ssh")
+	if err != nil {
+		t.Fatalf("IssueCredential() error = %v", err)
+	}
+	if repo.credentials["builtin.ssh"].SecretHash == secret {
+		t.Fatal("secret should not be stored in plain text")
+	}
+	// 落库的只有加密后的身份签名密钥，无法由密钥摘要推导
+	if stored := repo.credentials["builtin.ssh"]; stored.IdentityKey == "" ||
+		string(pluginx.IdentityKey(stored.SecretHash)) == string(pluginx.IdentityKey(secret)) {
+		t.Fatalf("unexpected stored identity key: %+v", stored)
+	}
+
+	if err = svc.VerifyCredential(context.Background(), "builtin.ssh", secret); err != nil {
+		t.Fatalf("VerifyCredential() error = %v", err)
+	}
+	if err = svc.VerifyCredential(context.Background(), "builtin.ssh", "wrong"); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("expected invalid credential for wrong secret, got %v", err)
+	}
+	if err = svc.VerifyCredential(context.Background(), "builtin.other", secret); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("expected invalid credential for unknown plugin, got %v", err)
+	}
+
+	// 重新签发后旧密钥失效
+	if _, err = svc.IssueCredential(context.Background(), "builtin.ssh"); err != nil {
+		t.Fatalf("IssueCredential() error = %v", err)
+	}
+	if err = svc.VerifyCredential(context.Background(), "builtin.ssh", secret); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("expected rotated secret to be rejected, got %v", err)
+	}
+}
+
+func TestResolveActionSignsTokenForPlugin(t *testing.T) {
+	svc := newPermissionTestService(t, "", nil, stubSecureFields{})
+	svc.tokens = pluginx.NewTokenSigner("secret", time.Minute)
+
+	result, err := svc.ResolveAction(context.Background(), pluginx.ResolveRequest{
+		PluginID:   "builtin.ssh",
+		Action:     "terminal",
+		ResourceID: 1,
+	})
+	if err != nil {
+		t.Fatalf("ResolveAction() error = %v", err)
+	}
+
+	claims, err := svc.VerifyActionToken("builtin.ssh", result.Token)
+	if err != nil {
+		t.Fatalf("VerifyActionToken() error = %v", err)
+	}
+	if claims.Action != "terminal" || claims.ResourceID != 1 {
+		t.Fatalf("unexpected claims: %#v", claims)
+	}
+	if _, err = svc.VerifyActionToken("builtin.other", result.Token); !errors.Is(err, pluginx.ErrInvalidActionToken) {
+		t.Fatalf("expected token to be rejected for other plugin, got %v", err)
+	}
+}
+
+func TestSignIdentityIncludesGrantedPermissions(t *testing.T) {
+	repo := &stubPluginRepo{}
+	svc := &service{
+		repo:        repo,
+		crypto:      testCrypto(),
+		permissions: &stubPermissionChecker{granted: map[string]bool{"cmdb:ssh:terminal": true}},
+	}
+	secret, err := svc.IssueCredential(context.Background(), "builtin.ssh")
+	if err != nil {
+		t.Fatalf("IssueCredential() error = %v", err)
+	}
+
+	value, err := svc.SignIdentity(context.Background(), pluginx.Plugin{
+		UID: "builtin.ssh",
+		Actions: []pluginx.ActionSpec{
+			{Action: "terminal", Permission: "cmdb:ssh:terminal"},
+			{Action: "sftp", Permission: "cmdb:ssh:sftp"},
+		},
+	}, pluginx.ActionTokenClaims{PluginID: "builtin.ssh", Action: "terminal", UserID: 7, TenantID: 2}, "req-1")
+	if err != nil {
+		t.Fatalf("SignIdentity() error = %v", err)
+	}
+
+	identity, err := pluginx.NewIdentityVerifier("builtin.ssh", secret).Verify(value)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if identity.UserID != 7 || identity.TenantID != 2 || identity.RequestID != "req-1" {
+		t.Fatalf("unexpected identity: %#v", identity)
+	}
+	if len(identity.Permissions) != 1 || identity.Permissions[0] != "cmdb:ssh:terminal" {
+		t.Fatalf("unexpected permissions: %v", identity.Permissions)
+	}
+}
+
+func TestSignIdentityRequiresIdentityKey(t *testing.T) {
+	// 旧版本签发的凭证只有密钥摘要，需要重新签发后才能签发身份信息
+	repo := &stubPluginRepo{credentials: map[string]domain.PluginCredential{
+		"builtin.ssh": {PluginID: "builtin.ssh", SecretHash: pluginx.HashSecret("secret")},
+	}}
+	svc := &service{repo: repo, crypto: testCrypto(), permissions: &stubPermissionChecker{}}
+
+	_, err := svc.SignIdentity(context.Background(), pluginx.Plugin{UID: "builtin.ssh"},
+		pluginx.ActionTokenClaims{PluginID: "builtin.ssh", UserID: 7}, "req-1")
+	if !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("expected invalid credential without identity key, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"context"
+	"time"
+
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/gotomicro/ego/core/elog"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// healthFailureThreshold 连续失败达到该次数后判定插件不可用，避免偶发超时导致动作频繁置灰
+	healthFailureThreshold = 2
+	// healthCheckConcurrency 同时探测的插件数量
+	healthCheckConcurrency = 8
+)
+
+func (s *service) CheckPluginsHealth(ctx context.Context) (int, error) {
+	plugins, err := s.repo.ListPlugins(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		eg    errgroup.Group
+		count int
+	)
+	eg.SetLimit(healthCheckConcurrency)
+	for _, p := range plugins {
+		spec, ok := p.Runtime()
+		if !ok {
+			continue
+		}
+		endpoint, ok := spec.HealthURL()
+		if !ok {
+			continue
+		}
+
+		count++
+		eg.Go(func() error {
+			start := time.Now()
+			er := pluginx.CheckHealth(ctx, endpoint)
+			health := nextHealth(p.Health, time.Since(start), er, time.Now())
+			if health.Status != healthStatus(p.Health) {
+				elog.DefaultLogger.Warn("插件健康状态变化", elog.String("plugin_id", p.UID),
+					elog.String("status", health.Status), elog.String("error", health.LastError))
+			}
+			return s.repo.UpdatePluginHealth(ctx, p.UID, health)
+		})
+	}
+	return count, eg.Wait()
+}
+
+// nextHealth 根据本次探测结果推导健康状态，连续失败未达到阈值时沿用上一次的状态
+func nextHealth(prev *pluginx.Health, latency time.Duration, err error, now time.Time) pluginx.Health {
+	health := pluginx.Health{
+		Status:    pluginx.HealthStatusHealthy,
+		LatencyMs: latency.Milliseconds(),
+		CheckedAt: now.UnixMilli(),
+	}
+	if err == nil {
+		return health
+	}
+
+	health.LastError = err.Error()
+	health.Status = healthStatus(prev)
+	if prev != nil {
+		health.Failures = prev.Failures
+	}
+	health.Failures++
+	if health.Failures >= healthFailureThreshold {
+		health.Status = pluginx.HealthStatusUnhealthy
+	}
+	return health
+}
+
+func healthStatus(health *pluginx.Health) string {
+	if health == nil || health.Status == "" {
+		return pluginx.HealthStatusUnknown
+	}
+	return health.Status
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
 	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
 )
 
@@ -41,6 +42,28 @@ func newMissingInputError(reasons ...string) error {
 	return &missingInputError{Reasons: filtered}
 }
 
+// missingInputValidationError 缺少必需输入时返回给调用方的校验错误，保留缺失原因供批量解析逐项返回
+type missingInputValidationError struct {
+	errs.ErrorCode
+	cause error
+}
+
+func (e *missingInputValidationError) Unwrap() []error {
+	return []error{e.ErrorCode, e.cause}
+}
+
+// missingInputReasons 提取缺失输入的具体原因，非缺失输入错误时返回 false
+func missingInputReasons(err error) ([]string, bool) {
+	if !errors.Is(err, errRequiredInputMissing) {
+		return nil, false
+	}
+	var missingErr *missingInputError
+	if errors.As(err, &missingErr) {
+		return missingErr.Reasons, true
+	}
+	return nil, true
+}
+
 func missingInputMessage(err error) string {
 	var missingErr *missingInputError
 	if errors.As(err, &missingErr) && len(missingErr.Reasons) > 0 {
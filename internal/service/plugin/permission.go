@@ -0,0 +1,162 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/samber/lo"
+)
+
+// PermissionChecker 定义插件动作权限的登记与校验能力，由权限中心实现。
+type PermissionChecker interface {
+	// Register 登记插件动作声明的权限，使其可以在权限中心中被授权。
+	Register(ctx context.Context, permissions []pluginx.ActionPermission) error
+
+	// Unregister 注销插件动作声明的权限，插件卸载后不再出现在权限中心。
+	Unregister(ctx context.Context, permissions []pluginx.ActionPermission) error
+
+	// Allowed 返回当前登录用户对每个权限标识的授权结果。
+	Allowed(ctx context.Context, permissions []string) (map[string]bool, error)
+}
+
+// secureFieldReader 定义判断绑定是否读取加密字段所需的属性查询能力。
+type secureFieldReader interface {
+	// SearchAttributeFieldsBySecure 按模型查询加密字段 UID。
+	SearchAttributeFieldsBySecure(ctx context.Context, modelUids []string) (map[string][]string, error)
+}
+
+func (s *service) SyncPermissions(ctx context.Context) (int, error) {
+	plugins, err := s.repo.ListPlugins(systemContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	permissions := lo.FlatMap(plugins, func(plugin domain.Plugin, _ int) []pluginx.ActionPermission {
+		return plugin.ActionPermissions()
+	})
+	// 校验规则收紧前落库的插件可能声明了内置权限资源，跳过这些权限以免冲掉内置能力
+	permissions = lo.Filter(permissions, func(permission pluginx.ActionPermission, _ int) bool {
+		if _, _, _, er := pluginx.ParsePluginPermission(permission.Code); er != nil {
+			elog.DefaultLogger.Warn("跳过插件声明的非法动作权限", elog.FieldErr(er),
+				elog.String("plugin_id", permission.PluginID), elog.String("action", permission.Action))
+			return false
+		}
+		return true
+	})
+	if len(permissions) == 0 {
+		return 0, nil
+	}
+	if err = s.permissions.Register(ctx, permissions); err != nil {
+		return 0, fmt.Errorf("登记插件动作权限失败: %w", err)
+	}
+	return len(permissions), nil
+}
+
+// authorizeAction 校验当前用户能否执行目标动作。
+func (s *service) authorizeAction(ctx context.Context, target actionTarget) error {
+	secure, err := s.secureBindings(ctx, []domain.PluginBinding{target.binding})
+	if err != nil {
+		return err
+	}
+	granted, err := s.grantedPermissions(ctx, []string{target.action.Permission})
+	if err != nil {
+		return err
+	}
+	if pluginx.ActionPermitted(target.action.Permission, secure[target.binding.UID], granted) {
+		return nil
+	}
+	return errs.PluginActionPermissionDenied.WithMsg(
+		fmt.Sprintf("无权执行插件动作: %s/%s", target.plugin.UID, target.action.Action),
+	)
+}
+
+// filterPermittedActions 过滤当前用户无权执行的动作，bindings 为资源所属模型下已启用的绑定。
+func (s *service) filterPermittedActions(
+	ctx context.Context,
+	results []pluginx.ResourceActions,
+	bindings []domain.PluginBinding,
+) ([]pluginx.ResourceActions, error) {
+	var permissions []string
+	for _, result := range results {
+		for _, action := range result.Actions {
+			permissions = append(permissions, action.Permission)
+		}
+	}
+
+	granted, err := s.grantedPermissions(ctx, permissions)
+	if err != nil {
+		return nil, err
+	}
+	secure, err := s.secureBindings(ctx, bindings)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Actions = lo.Filter(results[i].Actions, func(action pluginx.ResourceAction, _ int) bool {
+			return pluginx.ActionPermitted(action.Permission, secure[action.BindingUID], granted)
+		})
+	}
+	return results, nil
+}
+
+// grantedPermissions 批量查询权限授权结果，空权限标识会被忽略。
+func (s *service) grantedPermissions(ctx context.Context, permissions []string) (map[string]bool, error) {
+	permissions = lo.Uniq(lo.Compact(permissions))
+	if len(permissions) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	granted, err := s.permissions.Allowed(ctx, permissions)
+	if err != nil {
+		return nil, fmt.Errorf("查询插件动作权限失败: %w", err)
+	}
+	return granted, nil
+}
+
+// secureBindings 判断每个绑定是否会读取加密字段，返回 binding uid -> 是否读取加密字段。
+func (s *service) secureBindings(ctx context.Context, bindings []domain.PluginBinding) (map[string]bool, error) {
+	result := make(map[string]bool, len(bindings))
+
+	var modelUIDs []string
+	for _, binding := range bindings {
+		if binding.Graph == nil {
+			continue
+		}
+		for _, node := range binding.Graph.Nodes {
+			if len(node.FieldMappings) > 0 {
+				modelUIDs = append(modelUIDs, node.ModelUID)
+			}
+		}
+	}
+	if len(modelUIDs) == 0 {
+		return result, nil
+	}
+
+	secureFields, err := s.secureFields.SearchAttributeFieldsBySecure(ctx, lo.Uniq(modelUIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binding := range bindings {
+		result[binding.UID] = bindingReadsSecure(binding, secureFields)
+	}
+	return result, nil
+}
+
+// bindingReadsSecure 绑定图中任一节点映射了加密字段即视为读取加密字段。
+func bindingReadsSecure(binding domain.PluginBinding, secureFields map[string][]string) bool {
+	if binding.Graph == nil {
+		return false
+	}
+	return lo.SomeBy(binding.Graph.Nodes, func(node pluginx.BindingGraphNode) bool {
+		fields := secureFields[node.ModelUID]
+		return lo.SomeBy(node.FieldMappings, func(mapping pluginx.FieldMapping) bool {
+			return lo.Contains(fields, mapping.ResourceField)
+		})
+	})
+}
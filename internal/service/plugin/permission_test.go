@@ -0,0 +1,201 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+)
+
+func TestResolveActionContextEnforcesPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission string
+		granted    map[string]bool
+		secure     stubSecureFields
+		wantDenied bool
+	}{
+		{
+			name:       "declared permission granted",
+			permission: "cmdb:ssh:terminal",
+			granted:    map[string]bool{"cmdb:ssh:terminal": true},
+			secure:     stubSecureFields{"host": {"password"}},
+		},
+		{
+			name:       "declared permission not granted",
+			permission: "cmdb:ssh:terminal",
+			secure:     stubSecureFields{},
+			wantDenied: true,
+		},
+		{
+			name:       "no permission reading secure field",
+			secure:     stubSecureFields{"host": {"password"}},
+			wantDenied: true,
+		},
+		{
+			name:   "no permission without secure field",
+			secure: stubSecureFields{"host": {"token"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPermissionTestService(t, tt.permission, tt.granted, tt.secure)
+
+			_, err := svc.ResolveActionContext(context.Background(), pluginx.ResolveRequest{
+				PluginID:   "builtin.ssh",
+				Action:     "terminal",
+				ResourceID: 1,
+			})
+			if tt.wantDenied {
+				if !errors.Is(err, errs.PluginActionPermissionDenied) {
+					t.Fatalf("expected permission denied, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterPermittedActions(t *testing.T) {
+	checker := &stubPermissionChecker{granted: map[string]bool{"cmdb:ssh:terminal": true}}
+	svc := &service{
+		permissions:  checker,
+		secureFields: stubSecureFields{"host": {"password"}},
+	}
+	bindings := []domain.PluginBinding{
+		{
+			UID:      "builtin.ssh.host",
+			ModelUID: "host",
+			Graph:    mustCenterGraph(t, "target", "host", map[string]string{"ip": "ip", "password": "password"}, []string{"ip"}),
+		},
+		{
+			UID:      "builtin.ping.host",
+			ModelUID: "host",
+			Graph:    mustCenterGraph(t, "target", "host", map[string]string{"ip": "ip"}, []string{"ip"}),
+		},
+	}
+
+	results, err := svc.filterPermittedActions(context.Background(), []pluginx.ResourceActions{
+		{
+			ResourceID: 1,
+			Actions: []pluginx.ResourceAction{
+				{Action: "terminal", Permission: "cmdb:ssh:terminal", BindingUID: "builtin.ssh.host"},
+				{Action: "sftp", Permission: "cmdb:ssh:sftp", BindingUID: "builtin.ssh.host"},
+				{Action: "copy_password", BindingUID: "builtin.ssh.host"},
+				{Action: "ping", BindingUID: "builtin.ping.host"},
+			},
+		},
+	}, bindings)
+	if err != nil {
+		t.Fatalf("filterPermittedActions() error = %v", err)
+	}
+
+	var got []string
+	for _, action := range results[0].Actions {
+		got = append(got, action.Action)
+	}
+	if len(got) != 2 || got[0] != "terminal" || got[1] != "ping" {
+		t.Fatalf("unexpected permitted actions: %v", got)
+	}
+	if len(checker.queried) != 1 {
+		t.Fatalf("expected permissions to be queried once, got %d", len(checker.queried))
+	}
+}
+
+func TestImportDefinitionRegistersActionPermissions(t *testing.T) {
+	checker := &stubPermissionChecker{}
+	svc := &service{repo: &stubPluginRepo{}, permissions: checker}
+
+	err := svc.ImportDefinition(context.Background(), pluginx.Definition{
+		Plugin: pluginx.Plugin{
+			UID:  "builtin.ssh",
+			Name: "SSH",
+			Actions: []pluginx.ActionSpec{
+				{Action: "terminal", Name: "SSH 终端", Permission: "cmdb:ssh:terminal"},
+				{Action: "ping", Name: "Ping"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(checker.registered) != 1 {
+		t.Fatalf("expected 1 registered permission, got %#v", checker.registered)
+	}
+	if got := checker.registered[0]; got.Code != "cmdb:ssh:terminal" || got.PluginID != "builtin.ssh" || got.Action != "terminal" {
+		t.Fatalf("unexpected registered permission: %#v", got)
+	}
+}
+
+func newPermissionTestService(
+	t *testing.T,
+	permission string,
+	granted map[string]bool,
+	secure stubSecureFields,
+) *service {
+	t.Helper()
+
+	return &service{
+		repo: &stubPluginRepo{
+			plugin: domain.Plugin{
+				UID:  "builtin.ssh",
+				Name: "SSH",
+				Actions: []domain.PluginActionSpec{
+					{Action: "terminal", Name: "SSH 终端", Permission: permission, BindingUID: "builtin.ssh.host"},
+				},
+			},
+			bindingsByModelUID: map[string][]domain.PluginBinding{
+				"host": {
+					{
+						UID:      "builtin.ssh.host",
+						PluginID: "builtin.ssh",
+						ModelUID: "host",
+						Enabled:  true,
+						Graph:    mustCenterGraph(t, "target", "host", map[string]string{"ip": "ip", "password": "password"}, []string{"ip"}),
+					},
+				},
+			},
+		},
+		permissions:  &stubPermissionChecker{granted: granted},
+		secureFields: secure,
+		resolver: &inputResolver{
+			resources: &stubResourceReader{
+				findByID: map[int64]domain.Resource{
+					1: {ID: 1, Name: "host-01", ModelUID: "host", Data: map[string]any{"ip": "10.0.0.8", "password": "secret"}},
+				},
+			},
+		},
+	}
+}
+
+func TestSyncPermissionsFromStoredPlugins(t *testing.T) {
+	checker := &stubPermissionChecker{}
+	svc := &service{
+		repo: &stubPluginRepo{plugin: domain.Plugin{
+			UID:  "builtin.ssh",
+			Name: "SSH",
+			Actions: []domain.PluginActionSpec{
+				{Action: "terminal", Name: "SSH 终端", Permission: "cmdb:ssh:terminal"},
+				{Action: "inspect", Name: "巡检"},
+				{Action: "export", Name: "导出", Permission: "cmdb:resource:view"},
+			},
+		}},
+		permissions: checker,
+	}
+
+	count, err := svc.SyncPermissions(context.Background())
+	if err != nil {
+		t.Fatalf("SyncPermissions() error = %v", err)
+	}
+	if count != 1 || len(checker.registered) != 1 || checker.registered[0].Code != "cmdb:ssh:terminal" {
+		t.Fatalf("unexpected registered permissions: %d %+v", count, checker.registered)
+	}
+}
@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/samber/lo"
+)
+
+func (s *service) ListPlacementActions(ctx context.Context, placement, modelUID string) ([]pluginx.ResourceAction, error) {
+	if !pluginx.ValidPlacement(placement) || pluginx.PlacementEntryMode(placement) != pluginx.EntryModeNone {
+		return nil, errs.ValidationError.WithMsg(
+			fmt.Sprintf("展示位置 %s 需要指定资源，请按资源查询插件动作", placement),
+		)
+	}
+
+	var (
+		plugins  []domain.Plugin
+		bindings []domain.PluginBinding
+		err      error
+	)
+	switch placement {
+	case pluginx.PlacementModelActions:
+		// 模型级动作只展示在插件已绑定的模型上
+		if modelUID, err = pluginx.NormalizeModelUID(modelUID); err != nil {
+			return nil, errs.ValidationError.WithMsg(err.Error())
+		}
+		if bindings, err = s.repo.ListEnabledBindingsByModelUID(ctx, modelUID); err != nil {
+			return nil, err
+		}
+		pluginCache := make(map[string]domain.Plugin)
+		for _, pluginID := range lo.Uniq(lo.Map(bindings, func(binding domain.PluginBinding, _ int) string {
+			return binding.PluginID
+		})) {
+			plugin, er := s.loadCachedPlugin(ctx, pluginID, pluginCache)
+			if er != nil {
+				return nil, er
+			}
+			plugins = append(plugins, plugin)
+		}
+	default:
+		if plugins, err = s.repo.ListPlugins(ctx); err != nil {
+			return nil, err
+		}
+		pluginIDs := lo.Map(plugins, func(plugin domain.Plugin, _ int) string { return plugin.UID })
+		if bindings, err = s.repo.ListBindingsByPluginIDs(ctx, pluginIDs); err != nil {
+			return nil, err
+		}
+		bindings = lo.Filter(bindings, func(binding domain.PluginBinding, _ int) bool { return binding.Enabled })
+	}
+
+	// 声明了绑定的动作，绑定必须已启用
+	enabled := lo.SliceToMap(bindings, func(binding domain.PluginBinding) (string, bool) {
+		return binding.PluginID + "/" + binding.UID, true
+	})
+	actions := make([]pluginx.ResourceAction, 0)
+	for _, plugin := range plugins {
+		for _, action := range plugin.ResourceActions() {
+			if action.Placement != placement {
+				continue
+			}
+			if action.BindingUID != "" && !enabled[plugin.UID+"/"+action.BindingUID] {
+				continue
+			}
+			actions = append(actions, action)
+		}
+	}
+
+	results, err := s.filterPermittedActions(ctx, []pluginx.ResourceActions{{Actions: actions}}, bindings)
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Actions, nil
+}
+
+// resourcePlacementFilter 返回按资源查询动作时的展示位置过滤条件，未指定时返回全部需要资源的动作
+func resourcePlacementFilter(placement string) (func(action pluginx.ResourceAction) bool, error) {
+	if placement == "" {
+		return func(action pluginx.ResourceAction) bool {
+			return action.EntryMode != pluginx.EntryModeNone
+		}, nil
+	}
+	if !pluginx.ValidPlacement(placement) {
+		return nil, errs.ValidationError.WithMsg(fmt.Sprintf("不支持的展示位置: %s", placement))
+	}
+	if pluginx.PlacementEntryMode(placement) == pluginx.EntryModeNone {
+		return nil, errs.ValidationError.WithMsg(
+			fmt.Sprintf("展示位置 %s 不针对具体资源，请按展示位置查询插件动作", placement),
+		)
+	}
+	return func(action pluginx.ResourceAction) bool {
+		return action.Placement == placement
+	}, nil
+}
+
+// resolveEntryTarget 按动作入口模式加载目标资源和绑定
+func (s *service) resolveEntryTarget(
+	ctx context.Context,
+	req pluginx.ResolveRequest,
+	plugin domain.Plugin,
+	action domain.PluginActionSpec,
+) (actionTarget, error) {
+	target := actionTarget{
+		mode:   action.EntryMode(),
+		plugin: plugin,
+		action: action,
+	}
+	ids, err := pluginx.EntryResourceIDs(target.mode, req)
+	if err != nil {
+		return actionTarget{}, err
+	}
+	if len(ids) > batchResolveLimit {
+		return actionTarget{}, errs.ValidationError.WithMsg(
+			fmt.Sprintf("插件动作的资源数量不能超过 %d", batchResolveLimit),
+		)
+	}
+
+	bindingUID := strings.TrimSpace(action.BindingUID)
+	if target.mode == pluginx.EntryModeNone {
+		// 无主资源的动作可以不声明绑定，此时不解析任何输入
+		if bindingUID == "" {
+			return target, nil
+		}
+		modelUID := strings.TrimSpace(req.ModelUID)
+		// 模型级动作的绑定必须与当前模型对应，不能任取插件的一个绑定
+		if action.Placement == pluginx.PlacementModelActions && modelUID == "" {
+			return actionTarget{}, errs.ValidationError.WithMsg(
+				fmt.Sprintf("模型级插件动作需要指定 model_uid: %s", req.Action),
+			)
+		}
+		target.binding, err = s.findEntrylessBinding(ctx, req.PluginID, bindingUID, modelUID)
+		return target, err
+	}
+	if bindingUID == "" {
+		return actionTarget{}, fmt.Errorf("插件动作未声明 binding_uid: %s", req.Action)
+	}
+
+	if target.resources, err = s.loadEntryResources(ctx, ids); err != nil {
+		return actionTarget{}, err
+	}
+	target.resource = target.resources[0]
+	target.binding, err = s.findBinding(ctx, target.resource.ModelUID, req.PluginID, bindingUID)
+	return target, err
+}
+
+// loadEntryResources 加载入口资源，多个资源必须属于同一模型
+func (s *service) loadEntryResources(ctx context.Context, ids []int64) ([]domain.Resource, error) {
+	if len(ids) == 1 {
+		resource, err := s.resolver.loadResource(ctx, ids[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Resource{resource}, nil
+	}
+
+	loaded, err := s.resolver.resources.ListResourceByIds(ctx, []string{}, ids)
+	if err != nil {
+		return nil, err
+	}
+	resourceMap := lo.SliceToMap(loaded, func(item domain.Resource) (int64, domain.Resource) {
+		return item.ID, item
+	})
+
+	resources := make([]domain.Resource, 0, len(ids))
+	for _, id := range ids {
+		resource, ok := resourceMap[id]
+		if !ok {
+			return nil, fmt.Errorf("资源不存在: %d", id)
+		}
+		if resource.ModelUID != resourceMap[ids[0]].ModelUID {
+			return nil, errs.ValidationError.WithMsg("插件动作的多个资源必须属于同一模型")
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// findEntrylessBinding 查找无主资源动作声明的绑定，指定模型时绑定必须属于该模型
+func (s *service) findEntrylessBinding(ctx context.Context, pluginID, bindingUID, modelUID string) (domain.PluginBinding, error) {
+	bindings, err := s.repo.ListBindingsByPluginID(ctx, pluginID)
+	if err != nil {
+		return domain.PluginBinding{}, err
+	}
+
+	binding, ok := lo.Find(bindings, func(binding domain.PluginBinding) bool {
+		return binding.Enabled && binding.UID == bindingUID && (modelUID == "" || binding.ModelUID == modelUID)
+	})
+	if !ok {
+		return domain.PluginBinding{}, fmt.Errorf("插件绑定不存在: %s", bindingUID)
+	}
+	return binding, nil
+}
+
+// resolveEntryInputs 按入口模式解析动作输入，多资源入口逐个资源解析后按输入名称合并
+func (s *service) resolveEntryInputs(ctx context.Context, target actionTarget) (map[string]pluginx.ResolvedInput, error) {
+	switch target.mode {
+	case pluginx.EntryModeNone:
+		return map[string]pluginx.ResolvedInput{}, nil
+	case pluginx.EntryModeResources:
+		merged := make(map[string]pluginx.ResolvedInput)
+		for _, resource := range target.resources {
+			inputs, err := s.resolver.resolve(ctx, resource, target.binding.Graph)
+			if err != nil {
+				return nil, fmt.Errorf("资源 %d: %w", resource.ID, err)
+			}
+			for name, input := range inputs {
+				item := merged[name]
+				item.Name, item.Cardinality = input.Name, pluginx.CardinalityMany
+				item.Resources = append(item.Resources, input.Resources...)
+				merged[name] = item
+			}
+		}
+		// 多个入口资源可能关联到同一资源，合并后按资源 ID 去重
+		for name, item := range merged {
+			item.Resources = uniqResolvedResources(item.Resources)
+			merged[name] = item
+		}
+		return merged, nil
+	default:
+		return s.resolver.resolve(ctx, target.resource, target.binding.Graph)
+	}
+}
+
+// uniqResolvedResources 按资源 ID 去重并保持原有顺序，未携带资源 ID 的结果原样保留
+func uniqResolvedResources(resources []pluginx.ResolvedResource) []pluginx.ResolvedResource {
+	seen := make(map[int64]struct{}, len(resources))
+	return lo.Filter(resources, func(resource pluginx.ResolvedResource, _ int) bool {
+		if resource.ResourceID == 0 {
+			return true
+		}
+		if _, ok := seen[resource.ResourceID]; ok {
+			return false
+		}
+		seen[resource.ResourceID] = struct{}{}
+		return true
+	})
+}
+
+// targetResourceIDs 返回动作目标资源 ID，无主资源入口返回空
+func targetResourceIDs(target actionTarget) []int64 {
+	return lo.Map(target.resources, func(resource domain.Resource, _ int) int64 {
+		return resource.ID
+	})
+}
+
+// requestResourceIDs 返回解析请求携带的资源 ID，用于审计
+func requestResourceIDs(req pluginx.ResolveRequest) []int64 {
+	return lo.Uniq(lo.Compact(append([]int64{req.ResourceID}, req.ResourceIDs...)))
+}
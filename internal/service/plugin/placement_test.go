@@ -0,0 +1,269 @@
+package plugin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+)
+
+func TestResolveActionContextEntryModes(t *testing.T) {
+	tests := []struct {
+		name            string
+		req             pluginx.ResolveRequest
+		wantErr         bool
+		wantResourceID  int64
+		wantResourceIDs []int64
+		wantTargets     int
+		wantBinding     string
+	}{
+		{
+			name:           "single resource",
+			req:            pluginx.ResolveRequest{Action: "terminal", ResourceID: 1},
+			wantResourceID: 1,
+			wantTargets:    1,
+			wantBinding:    "builtin.ssh.host",
+		},
+		{
+			name:            "selected resources",
+			req:             pluginx.ResolveRequest{Action: "inspect", ResourceIDs: []int64{1, 2, 1}},
+			wantResourceID:  1,
+			wantResourceIDs: []int64{1, 2},
+			wantTargets:     2,
+			wantBinding:     "builtin.ssh.host",
+		},
+		{
+			name:    "selected resources across models",
+			req:     pluginx.ResolveRequest{Action: "inspect", ResourceIDs: []int64{1, 3}},
+			wantErr: true,
+		},
+		{
+			name:    "selected resources missing",
+			req:     pluginx.ResolveRequest{Action: "inspect"},
+			wantErr: true,
+		},
+		{
+			name:        "model action with binding",
+			req:         pluginx.ResolveRequest{Action: "sync", ModelUID: "host"},
+			wantBinding: "builtin.ssh.host",
+		},
+		{
+			name:    "model action without model",
+			req:     pluginx.ResolveRequest{Action: "sync"},
+			wantErr: true,
+		},
+		{
+			name:    "model action on unbound model",
+			req:     pluginx.ResolveRequest{Action: "sync", ModelUID: "switch"},
+			wantErr: true,
+		},
+		{
+			name: "global menu without binding",
+			req:  pluginx.ResolveRequest{Action: "dashboard"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPlacementTestService(t, map[string]bool{"cmdb:ssh:dashboard": true})
+			tt.req.PluginID = "builtin.ssh"
+
+			actionCtx, err := svc.ResolveActionContext(context.Background(), tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveActionContext() error = %v", err)
+			}
+			if actionCtx.ResourceID != tt.wantResourceID {
+				t.Fatalf("expected resource id %d, got %d", tt.wantResourceID, actionCtx.ResourceID)
+			}
+			if len(actionCtx.ResourceIDs) != len(tt.wantResourceIDs) {
+				t.Fatalf("expected resource ids %v, got %v", tt.wantResourceIDs, actionCtx.ResourceIDs)
+			}
+			if actionCtx.Binding.UID != tt.wantBinding {
+				t.Fatalf("expected binding %q, got %q", tt.wantBinding, actionCtx.Binding.UID)
+			}
+			if got := len(actionCtx.Inputs["target"].Resources); got != tt.wantTargets {
+				t.Fatalf("expected %d target resources, got %d", tt.wantTargets, got)
+			}
+			if tt.wantTargets > 1 && actionCtx.Inputs["target"].Cardinality != pluginx.CardinalityMany {
+				t.Fatalf("expected merged input cardinality many, got %q", actionCtx.Inputs["target"].Cardinality)
+			}
+		})
+	}
+}
+
+func TestListResourceActionsBatchByPlacement(t *testing.T) {
+	tests := []struct {
+		name        string
+		placement   string
+		wantActions []string
+		wantErr     bool
+	}{
+		{name: "all resource placements", wantActions: []string{"terminal", "inspect", "topology"}},
+		{name: "model list toolbar", placement: pluginx.PlacementModelListToolbar, wantActions: []string{"inspect"}},
+		{name: "relation graph node", placement: pluginx.PlacementRelationGraphNode, wantActions: []string{"topology"}},
+		{name: "global menu rejected", placement: pluginx.PlacementGlobalMenu, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPlacementTestService(t, nil)
+
+			results, err := svc.ListResourceActionsBatch(context.Background(), tt.placement, []int64{1})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListResourceActionsBatch() error = %v", err)
+			}
+			assertActionNames(t, results[0].Actions, tt.wantActions)
+		})
+	}
+}
+
+func TestListPlacementActions(t *testing.T) {
+	tests := []struct {
+		name        string
+		placement   string
+		modelUID    string
+		granted     map[string]bool
+		wantActions []string
+		wantErr     bool
+	}{
+		{name: "model actions", placement: pluginx.PlacementModelActions, modelUID: "host", wantActions: []string{"sync"}},
+		{name: "model actions on unbound model", placement: pluginx.PlacementModelActions, modelUID: "switch"},
+		{name: "model actions without model", placement: pluginx.PlacementModelActions, wantErr: true},
+		{name: "global menu not granted", placement: pluginx.PlacementGlobalMenu},
+		{
+			name:        "global menu granted",
+			placement:   pluginx.PlacementGlobalMenu,
+			granted:     map[string]bool{"cmdb:ssh:dashboard": true},
+			wantActions: []string{"dashboard"},
+		},
+		{name: "resource placement rejected", placement: pluginx.PlacementModelListToolbar, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPlacementTestService(t, tt.granted)
+
+			actions, err := svc.ListPlacementActions(context.Background(), tt.placement, tt.modelUID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListPlacementActions() error = %v", err)
+			}
+			assertActionNames(t, actions, tt.wantActions)
+		})
+	}
+}
+
+func assertActionNames(t *testing.T, actions []pluginx.ResourceAction, want []string) {
+	t.Helper()
+
+	if len(actions) != len(want) {
+		t.Fatalf("expected actions %v, got %#v", want, actions)
+	}
+	for i, action := range actions {
+		if action.Action != want[i] {
+			t.Fatalf("expected actions %v, got %#v", want, actions)
+		}
+	}
+}
+
+func TestResolveModelActionSignsModelUID(t *testing.T) {
+	svc := newPlacementTestService(t, nil)
+	svc.tokens = pluginx.NewTokenSigner("secret", time.Minute)
+
+	result, err := svc.ResolveAction(context.Background(), pluginx.ResolveRequest{
+		PluginID: "builtin.ssh",
+		Action:   "sync",
+		ModelUID: "host",
+	})
+	if err != nil {
+		t.Fatalf("ResolveAction() error = %v", err)
+	}
+
+	// 插件回调 gRPC 解析时只能从令牌中取得模型
+	claims, err := svc.VerifyActionToken("builtin.ssh", result.Token)
+	if err != nil {
+		t.Fatalf("VerifyActionToken() error = %v", err)
+	}
+	if claims.ModelUID != "host" {
+		t.Fatalf("expected token to carry model uid, got %#v", claims)
+	}
+}
+
+func TestUniqResolvedResources(t *testing.T) {
+	got := uniqResolvedResources([]pluginx.ResolvedResource{
+		{ResourceID: 1, ModelUID: "host"},
+		{ResourceID: 9, ModelUID: "gateway"},
+		{ResourceID: 2, ModelUID: "host"},
+		{ResourceID: 9, ModelUID: "gateway"},
+		{ModelUID: "virtual"},
+		{ModelUID: "virtual"},
+	})
+
+	ids := make([]int64, 0, len(got))
+	for _, resource := range got {
+		ids = append(ids, resource.ResourceID)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 9, 2, 0, 0}) {
+		t.Fatalf("unexpected resources after dedup: %v", ids)
+	}
+}
+
+func newPlacementTestService(t *testing.T, granted map[string]bool) *service {
+	t.Helper()
+
+	binding := domain.PluginBinding{
+		UID:      "builtin.ssh.host",
+		PluginID: "builtin.ssh",
+		ModelUID: "host",
+		Enabled:  true,
+		Graph:    mustCenterGraph(t, "target", "host", map[string]string{"ip": "ip"}, []string{"ip"}),
+	}
+	return &service{
+		repo: &stubPluginRepo{
+			plugin: domain.Plugin{
+				UID:  "builtin.ssh",
+				Name: "SSH",
+				Actions: []domain.PluginActionSpec{
+					{Action: "terminal", Name: "SSH 终端", BindingUID: binding.UID},
+					{Action: "inspect", Name: "批量巡检", BindingUID: binding.UID, Placement: pluginx.PlacementModelListToolbar},
+					{Action: "topology", Name: "链路拓扑", BindingUID: binding.UID, Placement: pluginx.PlacementRelationGraphNode},
+					{Action: "sync", Name: "同步主机", BindingUID: binding.UID, Placement: pluginx.PlacementModelActions},
+					{Action: "dashboard", Name: "SSH 总览", Permission: "cmdb:ssh:dashboard", Placement: pluginx.PlacementGlobalMenu},
+				},
+			},
+			bindingsByModelUID: map[string][]domain.PluginBinding{"host": {binding}},
+			bindingsByPluginID: map[string][]domain.PluginBinding{"builtin.ssh": {binding}},
+		},
+		permissions:  &stubPermissionChecker{granted: granted},
+		secureFields: stubSecureFields{},
+		resolver: &inputResolver{
+			resources: &stubResourceReader{
+				findByID: map[int64]domain.Resource{
+					1: {ID: 1, Name: "host-01", ModelUID: "host", Data: map[string]any{"ip": "10.0.0.8"}},
+					2: {ID: 2, Name: "host-02", ModelUID: "host", Data: map[string]any{"ip": "10.0.0.9"}},
+					3: {ID: 3, Name: "switch-01", ModelUID: "switch", Data: map[string]any{"ip": "10.0.1.1"}},
+				},
+			},
+		},
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/internal/errs"
@@ -14,14 +15,28 @@ import (
 	model "github.com/Duke1616/ecmdb/internal/service/model"
 	relation "github.com/Duke1616/ecmdb/internal/service/relation"
 	resource "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/Duke1616/ecmdb/pkg/cryptox"
 	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
 	"github.com/samber/lo"
 )
 
 type Service interface {
-	// ImportDefinition 导入外部插件定义。
+	// ImportDefinition 导入外部插件定义，并向权限中心登记插件动作声明的权限。
+	// 定义内容变化时追加版本快照，会删除仍在使用的字段时拒绝导入。
 	ImportDefinition(ctx context.Context, def pluginx.Definition) error
 
+	// ListVersions 查询插件定义的历史版本，不包含定义内容。
+	ListVersions(ctx context.Context, pluginID string) ([]domain.PluginVersion, error)
+
+	// DiffVersions 比较插件两个历史版本的定义差异。
+	DiffVersions(ctx context.Context, pluginID string, from, to int64) (pluginx.DefinitionDiff, error)
+
+	// PreviewUpgrade 拉取插件运行时的最新定义，与最新版本对比生成升级预览。
+	PreviewUpgrade(ctx context.Context, pluginID string) (domain.PluginUpgradePlan, error)
+
+	// RollbackDefinition 回滚到指定版本的插件定义，回滚本身也会追加一条版本快照。
+	RollbackDefinition(ctx context.Context, pluginID string, revision int64) error
+
 	// GetDefaultDefinition 返回插件默认定义草稿，供前端创建内置默认绑定时使用。
 	GetDefaultDefinition(ctx context.Context, pluginID string) (pluginx.Definition, error)
 
@@ -34,6 +49,10 @@ type Service interface {
 	// DeleteBinding 删除单个插件绑定。
 	DeleteBinding(ctx context.Context, uid string) error
 
+	// UninstallPlugin 卸载插件：删除全部绑定、注销动作权限、删除插件记录和凭证，可选清理插件导入时新建的模型、字段和关联类型。
+	// 清理时模型仍有资产数据会拒绝卸载，强制卸载则一并删除资产。
+	UninstallPlugin(ctx context.Context, req domain.UninstallPlugin) (domain.PluginUninstallResult, error)
+
 	// ListPlugins 查询插件目录。
 	ListPlugins(ctx context.Context) ([]domain.PluginListItem, error)
 
@@ -43,14 +62,49 @@ type Service interface {
 	// ListEnums 查询插件管理所需枚举。
 	ListEnums(ctx context.Context) (domain.PluginManagementEnums, error)
 
-	// ListResourceActionsBatch 批量查询多个资源可以使用的插件动作。
-	ListResourceActionsBatch(ctx context.Context, resourceIDs []int64) ([]pluginx.ResourceActions, error)
+	// ListResourceActionsBatch 批量查询多个资源可以使用的插件动作，当前用户无权执行的动作会被过滤。
+	// 指定展示位置时只返回该位置的动作，未指定时返回全部需要资源的动作。
+	ListResourceActionsBatch(ctx context.Context, placement string, resourceIDs []int64) ([]pluginx.ResourceActions, error)
 
-	// ResolveAction 解析插件动作需要的 UI 和输入数据。
+	// ListPlacementActions 查询不针对具体资源的展示位置（模型级动作、全局菜单）可以使用的插件动作。
+	// 模型级动作需要指定模型，只返回已绑定该模型的插件动作。
+	ListPlacementActions(ctx context.Context, placement, modelUID string) ([]pluginx.ResourceAction, error)
+
+	// ResolveAction 解析插件动作需要的 UI 和输入数据，并签发访问插件运行时代理的动作令牌。
+	// 按动作展示位置决定入口模式：单个资源、同一模型下的多个资源或无主资源。
 	ResolveAction(ctx context.Context, req pluginx.ResolveRequest) (pluginx.ResolveResult, error)
 
 	// ResolveActionContext 解析插件动作运行时上下文，供内置后端能力直接复用。
+	// 动作声明的权限未授权，或未声明权限但会读取加密字段时返回无权限错误。
 	ResolveActionContext(ctx context.Context, req pluginx.ResolveRequest) (pluginx.ActionContext, error)
+
+	// BatchResolveAction 对多个资源批量解析支持批量执行的插件动作，逐个资源返回解析结果或失败原因。
+	BatchResolveAction(ctx context.Context, req domain.BatchResolvePluginAction) (pluginx.BatchResolveResult, error)
+
+	// RecordAudit 写入插件动作审计日志，配置了审计事件推送时同时推送到 Kafka。
+	RecordAudit(ctx context.Context, log domain.PluginAuditLog) error
+
+	// ListAuditLogs 分页查询当前租户的插件动作审计日志。
+	ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]domain.PluginAuditLog, int64, error)
+
+	// CheckPluginsHealth 探测声明了 health_path 的外部服务插件并记录结果，返回探测的插件数量。
+	CheckPluginsHealth(ctx context.Context) (int, error)
+
+	// SyncPermissions 按已落库的插件定义重新登记全部动作权限，返回登记的权限数量。
+	// 权限登记只保存在实例内存中，实例启动后需要重新登记，否则重启前导入的插件权限无法授权。
+	SyncPermissions(ctx context.Context) (int, error)
+
+	// IssueCredential 为插件签发新的共享密钥并替换旧凭证，密钥明文只在签发时返回一次。
+	IssueCredential(ctx context.Context, pluginID string) (string, error)
+
+	// VerifyCredential 校验插件调用 gRPC 接口时携带的共享密钥。
+	VerifyCredential(ctx context.Context, pluginID, secret string) error
+
+	// VerifyActionToken 校验访问插件运行时代理的动作令牌，令牌必须由同一插件的动作签发。
+	VerifyActionToken(pluginID, token string) (pluginx.ActionTokenClaims, error)
+
+	// SignIdentity 为经代理转发的请求签发调用方身份，包含用户、租户、已授权的本插件动作权限及请求 ID。
+	SignIdentity(ctx context.Context, plugin domain.Plugin, claims pluginx.ActionTokenClaims, requestID string) (string, error)
 }
 
 type service struct {
@@ -61,13 +115,21 @@ type service struct {
 	attributes     attribute.Service
 	relationTypes  relation.RelationTypeService
 	modelRelations relation.RelationModelService
+	permissions    PermissionChecker
+	secureFields   secureFieldReader
+	resources      resourceCleaner
+	tokens         *pluginx.TokenSigner
+	crypto         cryptox.Crypto // 加密保存插件身份签名密钥
+	audit          AuditConfig
 }
 
 type actionTarget struct {
-	resource domain.Resource
-	binding  domain.PluginBinding
-	plugin   domain.Plugin
-	action   domain.PluginActionSpec
+	mode      string            // 动作入口模式
+	resource  domain.Resource   // 主资源，无主资源入口为空
+	resources []domain.Resource // 入口的全部资源
+	binding   domain.PluginBinding
+	plugin    domain.Plugin
+	action    domain.PluginActionSpec
 }
 
 type bindingSavePlan struct {
@@ -91,6 +153,10 @@ func NewService(
 	attributeSvc attribute.Service,
 	relationTypeSvc relation.RelationTypeService,
 	relationModelSvc relation.RelationModelService,
+	permissions PermissionChecker,
+	tokens *pluginx.TokenSigner,
+	crypto cryptox.Crypto,
+	audit AuditConfig,
 ) Service {
 	return &service{
 		repo:           repo,
@@ -99,6 +165,12 @@ func NewService(
 		attributes:     attributeSvc,
 		relationTypes:  relationTypeSvc,
 		modelRelations: relationModelSvc,
+		permissions:    permissions,
+		secureFields:   attributeSvc,
+		resources:      resourceSvc,
+		tokens:         tokens,
+		crypto:         crypto,
+		audit:          audit,
 		resolver: newInputResolver(
 			resourceSvc,
 			relationSvc,
@@ -107,7 +179,14 @@ func NewService(
 }
 
 func (s *service) ImportDefinition(ctx context.Context, def pluginx.Definition) error {
-	return s.upsertPlugin(ctx, def.Plugin)
+	plan, latest, err := s.planUpgrade(ctx, def)
+	if err != nil {
+		return err
+	}
+	if err = upgradeBlockedError(plan); err != nil {
+		return err
+	}
+	return s.applyDefinition(ctx, def, latest, domain.PluginVersionSourceImport)
 }
 
 func (s *service) GetDefaultDefinition(ctx context.Context, pluginID string) (pluginx.Definition, error) {
@@ -231,6 +310,7 @@ func (s *service) ListPlugins(ctx context.Context) ([]domain.PluginListItem, err
 			BindingCount: len(pluginBindings),
 			BoundModels:  buildBoundModels(pluginBindings, modelMeta),
 			Actions:      item.Actions,
+			Health:       item.Health,
 			UpdatedAt:    item.Utime,
 		})
 	}
@@ -317,6 +397,10 @@ func (s *service) ListEnums(ctx context.Context) (domain.PluginManagementEnums,
 		Types: []string{"builtin", "custom"},
 		Placements: []domain.EnumOption{
 			{Label: "资源详情动作区", Value: pluginx.PlacementResourceDetailActions},
+			{Label: "模型列表工具栏", Value: pluginx.PlacementModelListToolbar},
+			{Label: "模型动作", Value: pluginx.PlacementModelActions},
+			{Label: "关系拓扑节点菜单", Value: pluginx.PlacementRelationGraphNode},
+			{Label: "全局菜单", Value: pluginx.PlacementGlobalMenu},
 		},
 		Directions: []domain.EnumOption{
 			{Label: "源端", Value: pluginx.DirectionToSource},
@@ -341,7 +425,11 @@ func (s *service) ListEnums(ctx context.Context) (domain.PluginManagementEnums,
 	}, nil
 }
 
-func (s *service) ListResourceActionsBatch(ctx context.Context, resourceIDs []int64) ([]pluginx.ResourceActions, error) {
+func (s *service) ListResourceActionsBatch(ctx context.Context, placement string, resourceIDs []int64) ([]pluginx.ResourceActions, error) {
+	matchPlacement, err := resourcePlacementFilter(placement)
+	if err != nil {
+		return nil, err
+	}
 	if len(resourceIDs) == 0 {
 		return []pluginx.ResourceActions{}, nil
 	}
@@ -390,40 +478,84 @@ func (s *service) ListResourceActionsBatch(ctx context.Context, resourceIDs []in
 
 		results = append(results, pluginx.ResourceActions{
 			ResourceID: resourceID,
-			Actions:    actions,
+			Actions: lo.Filter(actions, func(action pluginx.ResourceAction, _ int) bool {
+				return matchPlacement(action)
+			}),
 		})
 	}
 
-	return results, nil
+	return s.filterPermittedActions(ctx, results, lo.Flatten(lo.Values(bindingCache)))
 }
 
-func (s *service) ResolveAction(ctx context.Context, req pluginx.ResolveRequest) (pluginx.ResolveResult, error) {
-	actionCtx, err := s.ResolveActionContext(ctx, req)
+func (s *service) ResolveAction(ctx context.Context, req pluginx.ResolveRequest) (result pluginx.ResolveResult, err error) {
+	defer func(start time.Time) {
+		s.auditAction(ctx, req.PluginID, req.Action, requestResourceIDs(req), start, err)
+	}(time.Now())
+
+	actionCtx, err := s.resolveActionContext(ctx, req, s.authorizeAction)
 	if err != nil {
 		return pluginx.ResolveResult{}, err
 	}
-	return resolveResult(actionCtx), nil
+	return s.signedResolveResult(ctx, actionCtx)
 }
 
-func (s *service) ResolveActionContext(ctx context.Context, req pluginx.ResolveRequest) (pluginx.ActionContext, error) {
+func (s *service) ResolveActionContext(ctx context.Context, req pluginx.ResolveRequest) (actionCtx pluginx.ActionContext, err error) {
+	defer func(start time.Time) {
+		s.auditAction(ctx, req.PluginID, req.Action, requestResourceIDs(req), start, err)
+	}(time.Now())
+
+	return s.resolveActionContext(ctx, req, s.authorizeAction)
+}
+
+// resolveActionContext 解析动作上下文，authorize 用于校验当前用户能否执行目标动作
+func (s *service) resolveActionContext(
+	ctx context.Context,
+	req pluginx.ResolveRequest,
+	authorize func(ctx context.Context, target actionTarget) error,
+) (pluginx.ActionContext, error) {
 	target, err := s.resolveActionTarget(ctx, req)
 	if err != nil {
 		return pluginx.ActionContext{}, err
 	}
+	if err = authorize(ctx, target); err != nil {
+		return pluginx.ActionContext{}, err
+	}
 
 	inputs, err := s.resolveActionInputs(ctx, target)
 	if err != nil {
 		return pluginx.ActionContext{}, err
 	}
 
-	return pluginx.ActionContext{
+	params, err := pluginx.NormalizeParams(target.action.Params, req.Params, inputs)
+	if err != nil {
+		return pluginx.ActionContext{}, errs.ValidationError.WithMsg(
+			fmt.Sprintf("插件动作参数错误: %s", err.Error()),
+		)
+	}
+
+	actionCtx := pluginx.ActionContext{
 		Plugin:     target.plugin,
 		Binding:    target.binding,
 		Action:     target.action,
-		ResourceID: req.ResourceID,
+		ResourceID: target.resource.ID,
 		Inputs:     inputs,
-		Params:     req.Params,
-	}, nil
+		Params:     params,
+	}
+	if target.mode == pluginx.EntryModeResources {
+		actionCtx.ResourceIDs = targetResourceIDs(target)
+	}
+	return actionCtx, nil
+}
+
+// signedResolveResult 组装解析结果并签发访问插件运行时代理的动作令牌
+func (s *service) signedResolveResult(ctx context.Context, actionCtx pluginx.ActionContext) (pluginx.ResolveResult, error) {
+	result := resolveResult(actionCtx)
+	token, expiresAt, err := s.signActionToken(ctx, actionCtx)
+	if err != nil {
+		return pluginx.ResolveResult{}, fmt.Errorf("签发插件动作令牌失败: %w", err)
+	}
+	result.Token, result.TokenExpires = token, expiresAt
+	return result, nil
 }
 
 func (s *service) upsertPlugin(ctx context.Context, p pluginx.Plugin) error {
@@ -492,7 +624,7 @@ func (s *service) importRuntimePluginSchema(ctx context.Context, pluginID string
 	if isEmptySchema(schema) {
 		return nil
 	}
-	return s.importSchema(ctx, schema)
+	return s.importSchema(ctx, pluginID, schema)
 }
 
 func prepareDefinitionBindings(pluginID string, bindings []pluginx.Binding) ([]pluginx.Binding, error) {
@@ -573,11 +705,6 @@ func (s *service) resolveActionTarget(ctx context.Context, req pluginx.ResolveRe
 		return actionTarget{}, err
 	}
 
-	resource, err := s.resolver.loadResource(ctx, req.ResourceID, nil)
-	if err != nil {
-		return actionTarget{}, err
-	}
-
 	plugin, err := s.loadPlugin(ctx, req.PluginID)
 	if err != nil {
 		return actionTarget{}, err
@@ -587,37 +714,26 @@ func (s *service) resolveActionTarget(ctx context.Context, req pluginx.ResolveRe
 	if !ok {
 		return actionTarget{}, fmt.Errorf("插件动作不存在: %s", req.Action)
 	}
-	if strings.TrimSpace(action.BindingUID) == "" {
-		return actionTarget{}, fmt.Errorf("插件动作未声明 binding_uid: %s", req.Action)
-	}
-
-	binding, err := s.findBinding(ctx, resource.ModelUID, req.PluginID, action.BindingUID)
-	if err != nil {
-		return actionTarget{}, err
-	}
-
-	return actionTarget{
-		resource: resource,
-		binding:  binding,
-		plugin:   plugin,
-		action:   action,
-	}, nil
+	return s.resolveEntryTarget(ctx, req, plugin, action)
 }
 
 func (s *service) resolveActionInputs(
 	ctx context.Context,
 	target actionTarget,
 ) (map[string]pluginx.ResolvedInput, error) {
-	inputs, err := s.resolver.resolve(ctx, target.resource, target.binding.Graph)
+	inputs, err := s.resolveEntryInputs(ctx, target)
 	if err == nil {
 		return inputs, nil
 	}
 	if !errors.Is(err, errRequiredInputMissing) {
 		return nil, err
 	}
-	return nil, errs.ValidationError.WithMsg(
-		fmt.Sprintf("插件动作缺少必需输入: %s", missingInputMessage(err)),
-	)
+	return nil, &missingInputValidationError{
+		ErrorCode: errs.ValidationError.WithMsg(
+			fmt.Sprintf("插件动作缺少必需输入: %s", missingInputMessage(err)),
+		),
+		cause: err,
+	}
 }
 
 func (s *service) findBinding(ctx context.Context, modelUID string, pluginID string, bindingUID string) (domain.PluginBinding, error) {
@@ -645,25 +761,41 @@ func resolveResult(actionCtx pluginx.ActionContext) pluginx.ResolveResult {
 		Permission:    actionCtx.Action.Permission,
 		BindingUID:    actionCtx.Binding.UID,
 		ModelUID:      actionCtx.Binding.ModelUID,
+		EntryMode:     actionCtx.Action.EntryMode(),
 		ResourceID:    actionCtx.ResourceID,
+		ResourceIDs:   actionCtx.ResourceIDs,
 		Inputs:        actionCtx.Inputs,
 		Params:        actionCtx.Params,
+		ParamSchema:   actionCtx.Action.Params,
 		Runtime:       actionCtx.Action.Runtime,
 		Meta:          actionCtx.Action.Meta,
 	}
 }
 
-func (s *service) importSchema(ctx context.Context, schema pluginx.Schema) error {
-	if err := s.importModels(ctx, schema.ModelGroups, schema.Models); err != nil {
+func (s *service) importSchema(ctx context.Context, pluginID string, schema pluginx.Schema) (err error) {
+	owner := &schemaOwner{pluginID: pluginID}
+	defer func() {
+		// NOTE: 中途失败时已新建的对象同样记录归属，保证卸载时能够清理
+		if len(owner.created) > 0 {
+			err = errors.Join(err, s.repo.CreateSchemaOwnerships(ctx, owner.created))
+		}
+	}()
+
+	if err = s.importModels(ctx, owner, schema.ModelGroups, schema.Models); err != nil {
 		return err
 	}
-	if err := s.importRelationTypes(ctx, schema.RelationTypes); err != nil {
+	if err = s.importRelationTypes(ctx, owner, schema.RelationTypes); err != nil {
 		return err
 	}
-	return s.importModelRelations(ctx, schema.ModelRelations)
+	return s.importModelRelations(ctx, owner, schema.ModelRelations)
 }
 
-func (s *service) importModels(ctx context.Context, modelGroups []pluginx.ModelGroupSpec, models []pluginx.ModelSpec) error {
+func (s *service) importModels(
+	ctx context.Context,
+	owner *schemaOwner,
+	modelGroups []pluginx.ModelGroupSpec,
+	models []pluginx.ModelSpec,
+) error {
 	groups, err := s.ensureModelGroups(ctx, modelGroups, models)
 	if err != nil {
 		return err
@@ -671,10 +803,10 @@ func (s *service) importModels(ctx context.Context, modelGroups []pluginx.ModelG
 
 	for _, model := range models {
 		groupID := groups[model.GroupName]
-		if err = s.ensureModel(ctx, model, groupID); err != nil {
+		if err = s.ensureModel(ctx, owner, model, groupID); err != nil {
 			return err
 		}
-		if err = s.ensureAttributes(ctx, model); err != nil {
+		if err = s.ensureAttributes(ctx, owner, model); err != nil {
 			return err
 		}
 	}
@@ -726,7 +858,7 @@ func (s *service) ensureModelGroups(
 	}), nil
 }
 
-func (s *service) ensureModel(ctx context.Context, spec pluginx.ModelSpec, groupID int64) error {
+func (s *service) ensureModel(ctx context.Context, owner *schemaOwner, spec pluginx.ModelSpec, groupID int64) error {
 	if spec.UID == "" {
 		return fmt.Errorf("插件模型 UID 不能为空")
 	}
@@ -749,13 +881,14 @@ func (s *service) ensureModel(ctx context.Context, spec pluginx.ModelSpec, group
 		if err != nil {
 			return fmt.Errorf("创建插件模型失败 %s: %w", spec.UID, err)
 		}
+		owner.own(domain.PluginSchemaKindModel, "", spec.UID)
 		return nil
 	default:
 		return err
 	}
 }
 
-func (s *service) ensureAttributes(ctx context.Context, model pluginx.ModelSpec) error {
+func (s *service) ensureAttributes(ctx context.Context, owner *schemaOwner, model pluginx.ModelSpec) error {
 	if len(model.AttributeGroups) == 0 {
 		return nil
 	}
@@ -764,7 +897,7 @@ func (s *service) ensureAttributes(ctx context.Context, model pluginx.ModelSpec)
 	if err != nil {
 		return err
 	}
-	return s.ensureAttributeFields(ctx, model, groups)
+	return s.ensureAttributeFields(ctx, owner, model, groups)
 }
 
 func (s *service) ensureAttributeGroups(ctx context.Context, model pluginx.ModelSpec) (map[string]int64, error) {
@@ -799,7 +932,12 @@ func (s *service) ensureAttributeGroups(ctx context.Context, model pluginx.Model
 	}), nil
 }
 
-func (s *service) ensureAttributeFields(ctx context.Context, model pluginx.ModelSpec, groups map[string]int64) error {
+func (s *service) ensureAttributeFields(
+	ctx context.Context,
+	owner *schemaOwner,
+	model pluginx.ModelSpec,
+	groups map[string]int64,
+) error {
 	existing, _, err := s.attributes.ListAttributes(ctx, model.UID)
 	if err != nil {
 		return err
@@ -837,10 +975,16 @@ func (s *service) ensureAttributeFields(ctx context.Context, model pluginx.Model
 	if len(fields) == 0 {
 		return nil
 	}
-	return s.attributes.BatchCreateAttribute(ctx, fields)
+	if err = s.attributes.BatchCreateAttribute(ctx, fields); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		owner.own(domain.PluginSchemaKindAttribute, model.UID, field.FieldUid)
+	}
+	return nil
 }
 
-func (s *service) importRelationTypes(ctx context.Context, relationTypes []pluginx.RelationType) error {
+func (s *service) importRelationTypes(ctx context.Context, owner *schemaOwner, relationTypes []pluginx.RelationType) error {
 	if len(relationTypes) == 0 {
 		return nil
 	}
@@ -865,10 +1009,16 @@ func (s *service) importRelationTypes(ctx context.Context, relationTypes []plugi
 			TargetDescribe: relationType.TargetDescribe,
 		}, relationType.UID != "" && !ok
 	})
-	return s.relationTypes.BatchCreate(ctx, missing)
+	if err = s.relationTypes.BatchCreate(ctx, missing); err != nil {
+		return err
+	}
+	for _, relationType := range missing {
+		owner.own(domain.PluginSchemaKindRelationType, "", relationType.UID)
+	}
+	return nil
 }
 
-func (s *service) importModelRelations(ctx context.Context, relations []pluginx.ModelRelation) error {
+func (s *service) importModelRelations(ctx context.Context, owner *schemaOwner, relations []pluginx.ModelRelation) error {
 	if len(relations) == 0 {
 		return nil
 	}
@@ -915,7 +1065,13 @@ func (s *service) importModelRelations(ctx context.Context, relations []pluginx.
 			return err
 		}
 	}
-	return s.modelRelations.BatchCreate(ctx, missing)
+	if err = s.modelRelations.BatchCreate(ctx, missing); err != nil {
+		return err
+	}
+	for _, relation := range missing {
+		owner.own(domain.PluginSchemaKindModelRelation, relation.SourceModelUID, relation.RelationName)
+	}
+	return nil
 }
 
 func modelRelationChanged(current domain.ModelRelation, next domain.ModelRelation) bool {
@@ -2,11 +2,17 @@ package plugin
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
 	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
 )
 
 func TestCompleteRelationSpec(t *testing.T) {
@@ -73,6 +79,7 @@ func TestResolveReturnsFriendlyMissingInputMessage(t *testing.T) {
 				},
 			},
 		},
+		secureFields: stubSecureFields{},
 		resolver: &inputResolver{
 			resources: &stubResourceReader{
 				findByID: map[int64]domain.Resource{
@@ -137,6 +144,7 @@ func TestResolveActionContextReloadsTopLevelFields(t *testing.T) {
 				},
 			},
 		},
+		secureFields: stubSecureFields{},
 		resolver: &inputResolver{
 			resources: reader,
 		},
@@ -184,7 +192,7 @@ func TestImportModelRelationsUpdatesChangedExistingRelation(t *testing.T) {
 		modelRelations: modelRelations,
 	}
 
-	err := svc.importModelRelations(context.Background(), []pluginx.ModelRelation{
+	err := svc.importModelRelations(context.Background(), &schemaOwner{}, []pluginx.ModelRelation{
 		{
 			SourceModelUID:  "AuthGateway",
 			TargetModelUID:  "host",
@@ -231,7 +239,7 @@ func TestImportModelRelationsSkipsUnchangedExistingRelation(t *testing.T) {
 		modelRelations: modelRelations,
 	}
 
-	err := svc.importModelRelations(context.Background(), []pluginx.ModelRelation{
+	err := svc.importModelRelations(context.Background(), &schemaOwner{}, []pluginx.ModelRelation{
 		{
 			SourceModelUID:  "AuthGateway",
 			TargetModelUID:  "host",
@@ -354,11 +362,49 @@ func TestImportDefinitionOnlyPersistsPluginInfo(t *testing.T) {
 	}
 }
 
+func TestResolveActionContextValidatesParams(t *testing.T) {
+	svc := newPermissionTestService(t, "", nil, stubSecureFields{})
+	repo := svc.repo.(*stubPluginRepo)
+	repo.plugin.Actions[0].Params = []pluginx.ParamSpec{
+		{Name: "host", Type: pluginx.ParamTypeString, Source: &pluginx.ParamSource{Input: "target", Field: "ip"}},
+		{Name: "cols", Type: pluginx.ParamTypeInteger, Default: 80},
+	}
+
+	actionCtx, err := svc.ResolveActionContext(context.Background(), pluginx.ResolveRequest{
+		PluginID:   "builtin.ssh",
+		Action:     "terminal",
+		ResourceID: 1,
+		Params:     map[string]any{"cols": float64(120)},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if actionCtx.Params["host"] != "10.0.0.8" || actionCtx.Params["cols"] != int64(120) {
+		t.Fatalf("unexpected params: %#v", actionCtx.Params)
+	}
+
+	_, err = svc.ResolveActionContext(context.Background(), pluginx.ResolveRequest{
+		PluginID:   "builtin.ssh",
+		Action:     "terminal",
+		ResourceID: 1,
+		Params:     map[string]any{"command": "rm -rf /"},
+	})
+	if !errors.Is(err, errs.ValidationError) {
+		t.Fatalf("expected validation error for undeclared param, got %v", err)
+	}
+}
+
 type stubPluginRepo struct {
 	plugin             domain.Plugin
 	upsertedPlugins    []domain.Plugin
 	upsertedBindings   []domain.PluginBinding
 	bindingsByModelUID map[string][]domain.PluginBinding
+	bindingsByPluginID map[string][]domain.PluginBinding
+	credentials        map[string]domain.PluginCredential
+	auditLogs          []domain.PluginAuditLog
+	versions           []domain.PluginVersion
+	ownerships         []domain.PluginSchemaOwnership
+	deletedPlugins     []string
 }
 
 func (s *stubPluginRepo) UpsertPlugin(ctx context.Context, p domain.Plugin) error {
@@ -376,15 +422,27 @@ func (s *stubPluginRepo) GetBinding(ctx context.Context, uid string) (domain.Plu
 func (s *stubPluginRepo) GetPlugin(ctx context.Context, uid string) (domain.Plugin, error) {
 	return s.plugin, nil
 }
-func (s *stubPluginRepo) ListPlugins(ctx context.Context) ([]domain.Plugin, error) { return nil, nil }
+func (s *stubPluginRepo) ListPlugins(ctx context.Context) ([]domain.Plugin, error) {
+	if s.plugin.UID == "" {
+		return nil, nil
+	}
+	return []domain.Plugin{s.plugin}, nil
+}
+func (s *stubPluginRepo) UpdatePluginHealth(ctx context.Context, uid string, health pluginx.Health) error {
+	return nil
+}
 func (s *stubPluginRepo) ListBindings(ctx context.Context) ([]domain.PluginBinding, error) {
 	return nil, nil
 }
 func (s *stubPluginRepo) ListBindingsByPluginID(ctx context.Context, pluginID string) ([]domain.PluginBinding, error) {
-	return nil, nil
+	return s.bindingsByPluginID[pluginID], nil
 }
 func (s *stubPluginRepo) ListBindingsByPluginIDs(ctx context.Context, pluginIDs []string) ([]domain.PluginBinding, error) {
-	return nil, nil
+	var res []domain.PluginBinding
+	for _, pluginID := range pluginIDs {
+		res = append(res, s.bindingsByPluginID[pluginID]...)
+	}
+	return res, nil
 }
 func (s *stubPluginRepo) ListEnabledBindingsByModelUID(ctx context.Context, modelUID string) ([]domain.PluginBinding, error) {
 	return s.bindingsByModelUID[modelUID], nil
@@ -400,16 +458,142 @@ func (s *stubPluginRepo) UpdateBindingEnabled(ctx context.Context, uid string, e
 	return nil
 }
 func (s *stubPluginRepo) DeleteBinding(ctx context.Context, uid string) error { return nil }
-func (s *stubPluginRepo) DeletePlugin(ctx context.Context, uid string) error  { return nil }
+func (s *stubPluginRepo) DeletePlugin(ctx context.Context, uid string) error {
+	s.deletedPlugins = append(s.deletedPlugins, uid)
+	return nil
+}
+func (s *stubPluginRepo) DeleteBindingsByPluginID(ctx context.Context, pluginID string) (int64, error) {
+	deleted := int64(len(s.bindingsByPluginID[pluginID]))
+	delete(s.bindingsByPluginID, pluginID)
+	return deleted, nil
+}
+func (s *stubPluginRepo) UpsertCredential(ctx context.Context, c domain.PluginCredential) error {
+	if s.credentials == nil {
+		s.credentials = make(map[string]domain.PluginCredential)
+	}
+	s.credentials[c.PluginID] = c
+	return nil
+}
+func (s *stubPluginRepo) GetCredential(ctx context.Context, pluginID string) (domain.PluginCredential, error) {
+	c, ok := s.credentials[pluginID]
+	if !ok {
+		return domain.PluginCredential{}, errs.ErrNotFound
+	}
+	return c, nil
+}
+
+func (s *stubPluginRepo) DeleteCredential(ctx context.Context, pluginID string) error {
+	delete(s.credentials, pluginID)
+	return nil
+}
+
+func (s *stubPluginRepo) CreateSchemaOwnerships(ctx context.Context, ownerships []domain.PluginSchemaOwnership) error {
+	for _, ownership := range ownerships {
+		ownership.ID = int64(len(s.ownerships) + 1)
+		ownership.TenantID = ctxutil.GetTenantID(ctx).Int64()
+		s.ownerships = append(s.ownerships, ownership)
+	}
+	return nil
+}
+
+func (s *stubPluginRepo) ListSchemaOwnerships(ctx context.Context, pluginID string) ([]domain.PluginSchemaOwnership, error) {
+	return append([]domain.PluginSchemaOwnership(nil), s.ownerships...), nil
+}
+
+func (s *stubPluginRepo) DeleteSchemaOwnership(ctx context.Context, id int64) error {
+	s.ownerships = lo.Reject(s.ownerships, func(ownership domain.PluginSchemaOwnership, _ int) bool {
+		return ownership.ID == id
+	})
+	return nil
+}
+
+func (s *stubPluginRepo) CreateVersion(ctx context.Context, v domain.PluginVersion) error {
+	s.versions = append(s.versions, v)
+	return nil
+}
+
+func (s *stubPluginRepo) GetLatestVersion(ctx context.Context, pluginID string) (domain.PluginVersion, error) {
+	if len(s.versions) == 0 {
+		return domain.PluginVersion{}, errs.ErrNotFound
+	}
+	return s.versions[len(s.versions)-1], nil
+}
+
+func (s *stubPluginRepo) GetVersion(ctx context.Context, pluginID string, revision int64) (domain.PluginVersion, error) {
+	for _, v := range s.versions {
+		if v.PluginID == pluginID && v.Revision == revision {
+			return v, nil
+		}
+	}
+	return domain.PluginVersion{}, errs.ErrNotFound
+}
+
+func (s *stubPluginRepo) ListVersions(ctx context.Context, pluginID string) ([]domain.PluginVersion, error) {
+	return s.versions, nil
+}
+
+func (s *stubPluginRepo) CreateAuditLog(ctx context.Context, log domain.PluginAuditLog) error {
+	s.auditLogs = append(s.auditLogs, log)
+	return nil
+}
+
+func (s *stubPluginRepo) ListAuditLogs(ctx context.Context, query domain.PluginAuditQuery) ([]domain.PluginAuditLog, error) {
+	return s.auditLogs, nil
+}
+
+func (s *stubPluginRepo) TotalAuditLogs(ctx context.Context, query domain.PluginAuditQuery) (int64, error) {
+	return int64(len(s.auditLogs)), nil
+}
+
+type stubPermissionChecker struct {
+	granted       map[string]bool
+	registered    []pluginx.ActionPermission
+	unregistered  []pluginx.ActionPermission
+	unregisterErr error
+	queried       [][]string
+}
+
+func (s *stubPermissionChecker) Register(ctx context.Context, permissions []pluginx.ActionPermission) error {
+	s.registered = append(s.registered, permissions...)
+	return nil
+}
+
+func (s *stubPermissionChecker) Unregister(ctx context.Context, permissions []pluginx.ActionPermission) error {
+	if s.unregisterErr != nil {
+		return s.unregisterErr
+	}
+	s.unregistered = append(s.unregistered, permissions...)
+	return nil
+}
+
+func (s *stubPermissionChecker) Allowed(ctx context.Context, permissions []string) (map[string]bool, error) {
+	s.queried = append(s.queried, permissions)
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = s.granted[permission]
+	}
+	return granted, nil
+}
+
+// stubSecureFields 模型 UID -> 加密字段 UID
+type stubSecureFields map[string][]string
+
+func (s stubSecureFields) SearchAttributeFieldsBySecure(ctx context.Context, modelUids []string) (map[string][]string, error) {
+	return s, nil
+}
 
 type stubResourceReader struct {
+	mu             sync.Mutex
 	findByID       map[int64]domain.Resource
 	findByIDFields [][]string
+	filteredTotal  map[string]int64 // 模型 UID -> 过滤查询命中的资源数量
 }
 
 func (s *stubResourceReader) FindResourceById(ctx context.Context, fields []string, id int64) (domain.Resource, error) {
 	copiedFields := append([]string(nil), fields...)
+	s.mu.Lock()
 	s.findByIDFields = append(s.findByIDFields, copiedFields)
+	s.mu.Unlock()
 
 	resource := s.findByID[id]
 	if len(fields) == 0 {
@@ -428,7 +612,13 @@ func (s *stubResourceReader) FindResourceById(ctx context.Context, fields []stri
 }
 
 func (s *stubResourceReader) ListResourceByIds(ctx context.Context, fields []string, ids []int64) ([]domain.Resource, error) {
-	return nil, nil
+	var res []domain.Resource
+	for _, id := range ids {
+		if resource, ok := s.findByID[id]; ok {
+			res = append(res, resource)
+		}
+	}
+	return res, nil
 }
 
 func (s *stubResourceReader) ListResourcesWithFilters(
@@ -439,13 +629,14 @@ func (s *stubResourceReader) ListResourcesWithFilters(
 	offset, limit int64,
 	filterGroups []domain.FilterGroup,
 ) ([]domain.Resource, int64, error) {
-	return nil, 0, nil
+	return nil, s.filteredTotal[modelUID], nil
 }
 
 type stubRelationModelService struct {
 	existing []domain.ModelRelation
 	created  []domain.ModelRelation
 	updated  []domain.ModelRelation
+	deleted  []int64
 }
 
 func (s *stubRelationModelService) CreateModelRelation(ctx context.Context, req domain.ModelRelation) (int64, error) {
@@ -458,7 +649,8 @@ func (s *stubRelationModelService) BatchCreate(ctx context.Context, relations []
 }
 
 func (s *stubRelationModelService) DeleteModelRelation(ctx context.Context, id int64) (int64, error) {
-	return 0, nil
+	s.deleted = append(s.deleted, id)
+	return 1, nil
 }
 
 func (s *stubRelationModelService) GetByRelationNames(ctx context.Context, names []string) ([]domain.ModelRelation, error) {
@@ -501,6 +693,19 @@ func (s *stubRelationModelService) CheckBeforeDelete(ctx context.Context, modelU
 	return nil
 }
 
+func (s *stubRelationModelService) DeleteImpact(ctx context.Context, id int64) (domain.RelationImpact, error) {
+	return domain.RelationImpact{}, nil
+}
+
+func (s *stubRelationModelService) MigrateResourceRelations(ctx context.Context, fromID, toID int64) (domain.RelationMigration, error) {
+	return domain.RelationMigration{}, nil
+}
+
+func (s *stubRelationModelService) ForceDeleteModelRelation(ctx context.Context, id int64) (int64, error) {
+	s.deleted = append(s.deleted, id)
+	return 0, nil
+}
+
 func containsAll(fields []string, want ...string) bool {
 	set := make(map[string]struct{}, len(fields))
 	for _, field := range fields {
@@ -538,3 +743,30 @@ func mustCenterGraph(
 	}
 	return graph
 }
+
+func TestNextHealth(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	probeErr := errors.New("connection refused")
+
+	health := nextHealth(nil, 20*time.Millisecond, nil, now)
+	if health.Status != pluginx.HealthStatusHealthy || health.LatencyMs != 20 || health.CheckedAt != now.UnixMilli() {
+		t.Fatalf("unexpected healthy result: %+v", health)
+	}
+
+	// 连续失败未达到阈值时沿用上一次的状态
+	health = nextHealth(&health, time.Second, probeErr, now)
+	if health.Status != pluginx.HealthStatusHealthy || health.Failures != 1 || health.LastError != probeErr.Error() {
+		t.Fatalf("unexpected first failure result: %+v", health)
+	}
+
+	health = nextHealth(&health, time.Second, probeErr, now)
+	if health.Status != pluginx.HealthStatusUnhealthy || health.Failures != 2 {
+		t.Fatalf("unexpected second failure result: %+v", health)
+	}
+
+	// 恢复后清零失败次数
+	health = nextHealth(&health, time.Millisecond, nil, now)
+	if health.Status != pluginx.HealthStatusHealthy || health.Failures != 0 || health.LastError != "" {
+		t.Fatalf("unexpected recovered result: %+v", health)
+	}
+}
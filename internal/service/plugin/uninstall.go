@@ -0,0 +1,259 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	mongoplugin "github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/samber/lo"
+)
+
+// uninstallResourceBatch 强制卸载时每批删除的资产数量
+const uninstallResourceBatch = 200
+
+// schemaCleanupOrder 先删除引用方再删除被引用方，模型最后删除
+var schemaCleanupOrder = []string{
+	domain.PluginSchemaKindModelRelation,
+	domain.PluginSchemaKindRelationType,
+	domain.PluginSchemaKindAttribute,
+	domain.PluginSchemaKindModel,
+}
+
+// resourceCleaner 定义强制卸载插件时清理模型资产所需的能力。
+type resourceCleaner interface {
+	// CountByModelUid 统计模型下的资产数量。
+	CountByModelUid(ctx context.Context, modelUid string) (int64, error)
+
+	// ListResource 分页查询模型下的资产。
+	ListResource(ctx context.Context, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, int64, error)
+
+	// DeleteResource 删除单个资产。
+	DeleteResource(ctx context.Context, id int64) (int64, error)
+}
+
+// schemaOwner 收集一次 Schema 导入中由插件新建的对象
+type schemaOwner struct {
+	pluginID string
+	created  []domain.PluginSchemaOwnership
+}
+
+func (o *schemaOwner) own(kind, modelUID, uid string) {
+	o.created = append(o.created, domain.PluginSchemaOwnership{
+		PluginID: o.pluginID,
+		Kind:     kind,
+		ModelUID: modelUID,
+		UID:      uid,
+	})
+}
+
+func (s *service) UninstallPlugin(ctx context.Context, req domain.UninstallPlugin) (domain.PluginUninstallResult, error) {
+	pluginID := strings.TrimSpace(req.PluginID)
+	if pluginID == "" {
+		return domain.PluginUninstallResult{}, fmt.Errorf("plugin_id 不能为空")
+	}
+	plugin, err := s.loadPlugin(systemContext(ctx), pluginID)
+	if err != nil {
+		return domain.PluginUninstallResult{}, err
+	}
+
+	// NOTE: 绑定和 Schema 归属分散在各租户，卸载需要跨租户处理
+	allCtx := mongoplugin.IgnoreTenantContext(ctx)
+	var ownerships []domain.PluginSchemaOwnership
+	if req.CleanupSchema {
+		if ownerships, err = s.repo.ListSchemaOwnerships(allCtx, pluginID); err != nil {
+			return domain.PluginUninstallResult{}, err
+		}
+		// 未强制卸载时，在做任何变更之前确认插件模型均无资产数据
+		if !req.Force {
+			if err = s.ensureOwnedModelsEmpty(ctx, ownerships); err != nil {
+				return domain.PluginUninstallResult{}, err
+			}
+		}
+	}
+
+	// NOTE: 以下步骤均可重复执行，插件记录最后删除，任一步骤失败时插件记录保留，可重新发起卸载
+	if permissions := plugin.ActionPermissions(); len(permissions) > 0 {
+		if err = s.permissions.Unregister(ctx, permissions); err != nil {
+			return domain.PluginUninstallResult{}, fmt.Errorf("注销插件动作权限失败: %w", err)
+		}
+	}
+
+	// 插件记录删除后残留的绑定会被判定为孤儿数据，卸载时直接删除
+	deleted, err := s.repo.DeleteBindingsByPluginID(allCtx, pluginID)
+	if err != nil {
+		return domain.PluginUninstallResult{}, err
+	}
+	result := domain.PluginUninstallResult{
+		PluginID:        pluginID,
+		DeletedBindings: deleted,
+	}
+	if req.CleanupSchema {
+		s.cleanupSchema(ctx, ownerships, req.Force, &result)
+	}
+
+	if err = s.repo.DeleteCredential(systemContext(ctx), pluginID); err != nil {
+		return result, err
+	}
+	return result, s.repo.DeletePlugin(systemContext(ctx), pluginID)
+}
+
+// ensureOwnedModelsEmpty 检查插件新建的模型在各租户下是否仍有资产
+func (s *service) ensureOwnedModelsEmpty(ctx context.Context, ownerships []domain.PluginSchemaOwnership) error {
+	var blocked []string
+	for _, ownership := range ownerships {
+		if ownership.Kind != domain.PluginSchemaKindModel {
+			continue
+		}
+		count, err := s.resources.CountByModelUid(ctxutil.WithTenantID(ctx, ownership.TenantID), ownership.UID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			blocked = append(blocked, fmt.Sprintf("%s(租户 %d, %d 个资产)", ownership.UID, ownership.TenantID, count))
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	return errs.PluginUninstallBlocked.WithMsg(
+		fmt.Sprintf("插件模型仍有资产数据，如需一并删除请强制卸载: %s", strings.Join(blocked, ", ")),
+	)
+}
+
+// cleanupSchema 按依赖顺序删除插件新建的对象，单个对象删除失败时保留并记录原因，不中断卸载
+func (s *service) cleanupSchema(
+	ctx context.Context,
+	ownerships []domain.PluginSchemaOwnership,
+	force bool,
+	result *domain.PluginUninstallResult,
+) {
+	for _, kind := range schemaCleanupOrder {
+		for _, ownership := range ownerships {
+			if ownership.Kind != kind {
+				continue
+			}
+
+			tenantCtx := ctxutil.WithTenantID(ctx, ownership.TenantID)
+			if err := s.removeOwned(tenantCtx, ownership, force, result); err != nil {
+				result.Retained = append(result.Retained, domain.PluginSchemaRetained{
+					TenantID: ownership.TenantID,
+					Kind:     ownership.Kind,
+					ModelUID: ownership.ModelUID,
+					UID:      ownership.UID,
+					Reason:   err.Error(),
+				})
+				continue
+			}
+			if err := s.repo.DeleteSchemaOwnership(tenantCtx, ownership.ID); err != nil {
+				elog.DefaultLogger.Error("删除插件 Schema 归属失败", elog.FieldErr(err),
+					elog.String("plugin_id", ownership.PluginID), elog.String("uid", ownership.UID))
+			}
+		}
+	}
+}
+
+// removeOwned 删除单个插件新建的对象，对象已不存在时视为删除成功
+func (s *service) removeOwned(
+	ctx context.Context,
+	ownership domain.PluginSchemaOwnership,
+	force bool,
+	result *domain.PluginUninstallResult,
+) error {
+	switch ownership.Kind {
+	case domain.PluginSchemaKindModelRelation:
+		relations, err := s.modelRelations.GetByRelationNames(ctx, []string{ownership.UID})
+		if err != nil || len(relations) == 0 {
+			return err
+		}
+		if force {
+			_, err = s.modelRelations.ForceDeleteModelRelation(ctx, relations[0].ID)
+		} else {
+			_, err = s.modelRelations.DeleteModelRelation(ctx, relations[0].ID)
+		}
+		if err != nil {
+			return err
+		}
+		result.RemovedModelRelations = append(result.RemovedModelRelations, ownership.UID)
+	case domain.PluginSchemaKindRelationType:
+		relationTypes, err := s.relationTypes.GetByUids(ctx, []string{ownership.UID})
+		if err != nil || len(relationTypes) == 0 {
+			return err
+		}
+		if force {
+			_, err = s.relationTypes.ForceDelete(ctx, relationTypes[0].ID)
+		} else {
+			_, err = s.relationTypes.Delete(ctx, relationTypes[0].ID)
+		}
+		if err != nil {
+			return err
+		}
+		result.RemovedRelationTypes = append(result.RemovedRelationTypes, ownership.UID)
+	case domain.PluginSchemaKindAttribute:
+		attrs, _, err := s.attributes.ListAttributes(ctx, ownership.ModelUID)
+		if err != nil {
+			return err
+		}
+		attr, ok := lo.Find(attrs, func(attr domain.Attribute) bool {
+			return attr.FieldUid == ownership.UID
+		})
+		if !ok {
+			return nil
+		}
+		if _, err = s.attributes.DeleteAttribute(ctx, attr.ID); err != nil {
+			return err
+		}
+		result.RemovedAttributes = append(result.RemovedAttributes, pluginx.FieldRef{
+			ModelUID: ownership.ModelUID,
+			FieldUID: ownership.UID,
+		})
+	case domain.PluginSchemaKindModel:
+		if force {
+			deleted, err := s.deleteModelResources(ctx, ownership.UID)
+			result.DeletedResources += deleted
+			if err != nil {
+				return err
+			}
+		}
+		count, err := s.models.DeleteByModelUid(ctx, ownership.UID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			result.RemovedModels = append(result.RemovedModels, ownership.UID)
+		}
+	default:
+		return fmt.Errorf("未知的插件 Schema 对象类型: %s", ownership.Kind)
+	}
+	return nil
+}
+
+// deleteModelResources 逐个删除模型下的资产，保留资产变更历史
+func (s *service) deleteModelResources(ctx context.Context, modelUID string) (int64, error) {
+	var deleted int64
+	for {
+		resources, _, err := s.resources.ListResource(ctx, []string{}, modelUID, 0, uninstallResourceBatch)
+		if err != nil || len(resources) == 0 {
+			return deleted, err
+		}
+
+		var batch int64
+		for _, resource := range resources {
+			count, err := s.resources.DeleteResource(ctx, resource.ID)
+			if err != nil {
+				return deleted, err
+			}
+			batch += count
+		}
+		deleted += batch
+		// 本批未删除任何资产时退出，避免重复查询到同一批数据
+		if batch == 0 {
+			return deleted, nil
+		}
+	}
+}
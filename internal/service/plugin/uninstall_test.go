@@ -0,0 +1,343 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	attribute "github.com/Duke1616/ecmdb/internal/service/attribute"
+	model "github.com/Duke1616/ecmdb/internal/service/model"
+	relation "github.com/Duke1616/ecmdb/internal/service/relation"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+)
+
+func TestImportSchemaRecordsOwnership(t *testing.T) {
+	svc := newUninstallTestService()
+	svc.repo.(*stubPluginRepo).ownerships = nil
+	svc.relationTypes.(*stubRelationTypeService).existing = nil
+	svc.models.(*stubModelService).existing = map[string]bool{"host": true}
+	svc.attributes.(*stubAttributeService).attrs = map[string][]domain.Attribute{
+		"host": {{ID: 1, ModelUid: "host", FieldUid: "ip"}},
+	}
+	ctx := ctxutil.WithTenantID(context.Background(), 7)
+
+	err := svc.importSchema(ctx, "builtin.ssh", pluginx.Schema{
+		Models: []pluginx.ModelSpec{
+			{
+				UID:  "host",
+				Name: "主机",
+				AttributeGroups: []pluginx.AttributeGroup{{Name: "基础信息", Fields: []pluginx.Attribute{
+					{UID: "ip", Name: "IP", Type: "string"},
+					{UID: "ssh_port", Name: "SSH 端口", Type: "number"},
+				}}},
+			},
+			{UID: "ssh_gateway", Name: "SSH 网关"},
+		},
+		RelationTypes: []pluginx.RelationType{{UID: "ssh_via", Name: "经由"}},
+		ModelRelations: []pluginx.ModelRelation{
+			{SourceModelUID: "host", TargetModelUID: "ssh_gateway", RelationTypeUID: "ssh_via"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("importSchema() error = %v", err)
+	}
+
+	got := make([]string, 0)
+	for _, ownership := range svc.repo.(*stubPluginRepo).ownerships {
+		if ownership.TenantID != 7 || ownership.PluginID != "builtin.ssh" {
+			t.Fatalf("unexpected ownership owner: %#v", ownership)
+		}
+		got = append(got, ownership.Kind+":"+ownership.ModelUID+":"+ownership.UID)
+	}
+	want := []string{
+		"attribute:host:ssh_port",
+		"model::ssh_gateway",
+		"relation_type::ssh_via",
+		"model_relation:host:host_ssh_via_ssh_gateway",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected ownerships: %v, want %v", got, want)
+	}
+}
+
+func TestUninstallPlugin(t *testing.T) {
+	svc := newUninstallTestService()
+	repo := svc.repo.(*stubPluginRepo)
+	relations := svc.modelRelations.(*stubRelationModelService)
+	relations.existing = []domain.ModelRelation{{ID: 11, RelationName: "host_ssh_via_ssh_gateway"}}
+
+	res, err := svc.UninstallPlugin(context.Background(), domain.UninstallPlugin{
+		PluginID:      "builtin.ssh",
+		CleanupSchema: true,
+	})
+	if err != nil {
+		t.Fatalf("UninstallPlugin() error = %v", err)
+	}
+
+	if res.DeletedBindings != 1 || len(repo.bindingsByPluginID["builtin.ssh"]) != 0 {
+		t.Fatalf("expected bindings deleted, got %d", res.DeletedBindings)
+	}
+	unregistered := svc.permissions.(*stubPermissionChecker).unregistered
+	if len(unregistered) != 1 || unregistered[0].Code != "cmdb:ssh:terminal" {
+		t.Fatalf("expected action permission unregistered, got %#v", unregistered)
+	}
+	if !reflect.DeepEqual(repo.deletedPlugins, []string{"builtin.ssh"}) {
+		t.Fatalf("expected plugin record deleted, got %v", repo.deletedPlugins)
+	}
+	if !reflect.DeepEqual(res.RemovedModels, []string{"ssh_gateway"}) ||
+		!reflect.DeepEqual(res.RemovedRelationTypes, []string{"ssh_via"}) ||
+		!reflect.DeepEqual(res.RemovedModelRelations, []string{"host_ssh_via_ssh_gateway"}) ||
+		len(res.RemovedAttributes) != 1 || res.RemovedAttributes[0].FieldUID != "ssh_port" {
+		t.Fatalf("unexpected uninstall result: %#v", res)
+	}
+	if !reflect.DeepEqual(relations.deleted, []int64{11}) {
+		t.Fatalf("expected owned model relation deleted, got %v", relations.deleted)
+	}
+	if attrs := svc.attributes.(*stubAttributeService).deleted; !reflect.DeepEqual(attrs, []int64{2}) {
+		t.Fatalf("expected only owned attribute deleted, got %v", attrs)
+	}
+	if len(repo.ownerships) != 0 {
+		t.Fatalf("expected ownerships cleared, got %#v", repo.ownerships)
+	}
+}
+
+func TestUninstallPluginWithResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		force       bool
+		wantBlocked bool
+	}{
+		{name: "refuse without force", wantBlocked: true},
+		{name: "force deletes resources", force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newUninstallTestService()
+			repo := svc.repo.(*stubPluginRepo)
+			resources := svc.resources.(*stubResourceCleaner)
+			resources.byModel = map[string][]int64{"ssh_gateway": {1, 2, 3}}
+
+			res, err := svc.UninstallPlugin(context.Background(), domain.UninstallPlugin{
+				PluginID:      "builtin.ssh",
+				CleanupSchema: true,
+				Force:         tt.force,
+			})
+
+			if tt.wantBlocked {
+				var code errs.ErrorCode
+				if !errors.As(err, &code) || code.Code != errs.PluginUninstallBlocked.Code {
+					t.Fatalf("expected uninstall blocked, got %v", err)
+				}
+				if len(repo.deletedPlugins) != 0 || !repo.bindingsByPluginID["builtin.ssh"][0].Enabled {
+					t.Fatal("blocked uninstall should not change anything")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UninstallPlugin() error = %v", err)
+			}
+			if res.DeletedResources != 3 || len(resources.byModel["ssh_gateway"]) != 0 {
+				t.Fatalf("expected resources deleted, got %d", res.DeletedResources)
+			}
+			if !reflect.DeepEqual(res.RemovedModels, []string{"ssh_gateway"}) {
+				t.Fatalf("expected model removed, got %v", res.RemovedModels)
+			}
+		})
+	}
+}
+
+func TestUninstallPluginKeepsDataWhenUnregisterFails(t *testing.T) {
+	svc := newUninstallTestService()
+	repo := svc.repo.(*stubPluginRepo)
+	svc.permissions.(*stubPermissionChecker).unregisterErr = errors.New("权限中心不可用")
+
+	_, err := svc.UninstallPlugin(context.Background(), domain.UninstallPlugin{PluginID: "builtin.ssh"})
+	if err == nil {
+		t.Fatal("expected unregister failure")
+	}
+	if len(repo.deletedPlugins) != 0 || len(repo.bindingsByPluginID["builtin.ssh"]) != 1 {
+		t.Fatal("failed uninstall should keep plugin record and bindings for retry")
+	}
+}
+
+func TestUninstallPluginRetainsUndeletableSchema(t *testing.T) {
+	svc := newUninstallTestService()
+	svc.attributes.(*stubAttributeService).err = errors.New("内置属性不允许删除")
+
+	res, err := svc.UninstallPlugin(context.Background(), domain.UninstallPlugin{
+		PluginID:      "builtin.ssh",
+		CleanupSchema: true,
+	})
+	if err != nil {
+		t.Fatalf("UninstallPlugin() error = %v", err)
+	}
+
+	if len(res.Retained) != 1 || res.Retained[0].UID != "ssh_port" || res.Retained[0].Reason == "" {
+		t.Fatalf("expected attribute retained with reason, got %#v", res.Retained)
+	}
+	ownerships := svc.repo.(*stubPluginRepo).ownerships
+	if len(ownerships) != 1 || ownerships[0].UID != "ssh_port" {
+		t.Fatalf("expected retained ownership kept, got %#v", ownerships)
+	}
+	if !reflect.DeepEqual(res.RemovedModels, []string{"ssh_gateway"}) {
+		t.Fatalf("expected cleanup to continue after failure, got %v", res.RemovedModels)
+	}
+}
+
+func newUninstallTestService() *service {
+	return &service{
+		repo: &stubPluginRepo{
+			plugin: domain.Plugin{
+				UID:     "builtin.ssh",
+				Name:    "SSH",
+				Actions: []domain.PluginActionSpec{{Action: "terminal", Name: "SSH 终端", Permission: "cmdb:ssh:terminal"}},
+			},
+			bindingsByPluginID: map[string][]domain.PluginBinding{
+				"builtin.ssh": {{UID: "builtin.ssh.host", PluginID: "builtin.ssh", ModelUID: "host", Enabled: true}},
+			},
+			ownerships: []domain.PluginSchemaOwnership{
+				{ID: 1, TenantID: 7, PluginID: "builtin.ssh", Kind: domain.PluginSchemaKindModel, UID: "ssh_gateway"},
+				{ID: 2, TenantID: 7, PluginID: "builtin.ssh", Kind: domain.PluginSchemaKindAttribute, ModelUID: "host", UID: "ssh_port"},
+				{ID: 3, TenantID: 7, PluginID: "builtin.ssh", Kind: domain.PluginSchemaKindRelationType, UID: "ssh_via"},
+				{ID: 4, TenantID: 7, PluginID: "builtin.ssh", Kind: domain.PluginSchemaKindModelRelation, ModelUID: "host", UID: "host_ssh_via_ssh_gateway"},
+			},
+		},
+		models: &stubModelService{existing: map[string]bool{"host": true, "ssh_gateway": true}},
+		attributes: &stubAttributeService{attrs: map[string][]domain.Attribute{
+			"host": {
+				{ID: 1, ModelUid: "host", FieldUid: "ip"},
+				{ID: 2, ModelUid: "host", FieldUid: "ssh_port"},
+			},
+		}},
+		relationTypes:  &stubRelationTypeService{existing: []domain.RelationType{{ID: 5, UID: "ssh_via"}}},
+		modelRelations: &stubRelationModelService{},
+		resources:      &stubResourceCleaner{},
+		permissions:    &stubPermissionChecker{},
+	}
+}
+
+type stubModelService struct {
+	model.Service
+	existing map[string]bool
+	deleted  []string
+}
+
+func (s *stubModelService) GetByUid(ctx context.Context, uid string) (domain.Model, error) {
+	if !s.existing[uid] {
+		return domain.Model{}, errs.ErrNotFound
+	}
+	return domain.Model{UID: uid}, nil
+}
+
+func (s *stubModelService) Create(ctx context.Context, req domain.Model) (int64, error) {
+	s.existing[req.UID] = true
+	return 1, nil
+}
+
+func (s *stubModelService) DeleteByModelUid(ctx context.Context, modelUid string) (int64, error) {
+	if !s.existing[modelUid] {
+		return 0, nil
+	}
+	delete(s.existing, modelUid)
+	s.deleted = append(s.deleted, modelUid)
+	return 1, nil
+}
+
+type stubAttributeService struct {
+	attribute.Service
+	attrs   map[string][]domain.Attribute
+	deleted []int64
+	err     error
+}
+
+func (s *stubAttributeService) ListAttributeGroup(ctx context.Context, modelUid string) ([]domain.AttributeGroup, error) {
+	return nil, nil
+}
+
+func (s *stubAttributeService) BatchCreateAttributeGroup(ctx context.Context, ags []domain.AttributeGroup) ([]domain.AttributeGroup, error) {
+	return ags, nil
+}
+
+func (s *stubAttributeService) ListAttributes(ctx context.Context, modelUID string) ([]domain.Attribute, int64, error) {
+	return s.attrs[modelUID], int64(len(s.attrs[modelUID])), nil
+}
+
+func (s *stubAttributeService) BatchCreateAttribute(ctx context.Context, attrs []domain.Attribute) error {
+	return nil
+}
+
+func (s *stubAttributeService) DeleteAttribute(ctx context.Context, id int64) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.deleted = append(s.deleted, id)
+	return 1, nil
+}
+
+type stubRelationTypeService struct {
+	relation.RelationTypeService
+	existing []domain.RelationType
+	deleted  []int64
+}
+
+func (s *stubRelationTypeService) GetByUids(ctx context.Context, uids []string) ([]domain.RelationType, error) {
+	var res []domain.RelationType
+	for _, relationType := range s.existing {
+		for _, uid := range uids {
+			if relationType.UID == uid {
+				res = append(res, relationType)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *stubRelationTypeService) BatchCreate(ctx context.Context, rts []domain.RelationType) error {
+	s.existing = append(s.existing, rts...)
+	return nil
+}
+
+func (s *stubRelationTypeService) Delete(ctx context.Context, id int64) (int64, error) {
+	s.deleted = append(s.deleted, id)
+	return 1, nil
+}
+
+func (s *stubRelationTypeService) ForceDelete(ctx context.Context, id int64) (int64, error) {
+	s.deleted = append(s.deleted, id)
+	return 0, nil
+}
+
+// stubResourceCleaner 模型 UID -> 资产 ID
+type stubResourceCleaner struct {
+	byModel map[string][]int64
+}
+
+func (s *stubResourceCleaner) CountByModelUid(ctx context.Context, modelUid string) (int64, error) {
+	return int64(len(s.byModel[modelUid])), nil
+}
+
+func (s *stubResourceCleaner) ListResource(ctx context.Context, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, int64, error) {
+	var res []domain.Resource
+	for _, id := range s.byModel[modelUid] {
+		res = append(res, domain.Resource{ID: id, ModelUID: modelUid})
+	}
+	return res, int64(len(res)), nil
+}
+
+func (s *stubResourceCleaner) DeleteResource(ctx context.Context, id int64) (int64, error) {
+	for modelUID, ids := range s.byModel {
+		for i, existing := range ids {
+			if existing == id {
+				s.byModel[modelUID] = append(ids[:i], ids[i+1:]...)
+				return 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
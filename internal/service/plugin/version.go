@@ -0,0 +1,218 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	mongoplugin "github.com/Duke1616/ecmdb/pkg/mongox/plugin"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/samber/lo"
+)
+
+func (s *service) ListVersions(ctx context.Context, pluginID string) ([]domain.PluginVersion, error) {
+	return s.repo.ListVersions(systemContext(ctx), pluginID)
+}
+
+func (s *service) DiffVersions(ctx context.Context, pluginID string, from, to int64) (pluginx.DefinitionDiff, error) {
+	fromVersion, err := s.repo.GetVersion(systemContext(ctx), pluginID, from)
+	if err != nil {
+		return pluginx.DefinitionDiff{}, err
+	}
+	toVersion, err := s.repo.GetVersion(systemContext(ctx), pluginID, to)
+	if err != nil {
+		return pluginx.DefinitionDiff{}, err
+	}
+	return pluginx.DiffDefinitions(fromVersion.Definition, toVersion.Definition), nil
+}
+
+func (s *service) PreviewUpgrade(ctx context.Context, pluginID string) (domain.PluginUpgradePlan, error) {
+	plugin, err := s.loadPlugin(ctx, pluginID)
+	if err != nil {
+		return domain.PluginUpgradePlan{}, err
+	}
+	def, err := loadRuntimeDefaultDefinition(ctx, plugin)
+	if err != nil {
+		return domain.PluginUpgradePlan{}, fmt.Errorf("插件定义获取失败，请确认插件运行时可访问: %s: %w", pluginID, err)
+	}
+
+	plan, _, err := s.planUpgrade(ctx, def)
+	return plan, err
+}
+
+func (s *service) RollbackDefinition(ctx context.Context, pluginID string, revision int64) error {
+	target, err := s.repo.GetVersion(systemContext(ctx), pluginID, revision)
+	if err != nil {
+		return err
+	}
+	def := target.Definition
+
+	// 回滚只恢复定义内容，插件运行时地址保持当前注册的地址
+	current, err := s.loadPlugin(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	if spec, ok := current.Runtime(); ok {
+		def.Plugin.SetRuntime(spec)
+	}
+
+	plan, latest, err := s.planUpgrade(ctx, def)
+	if err != nil {
+		return err
+	}
+	if err = upgradeBlockedError(plan); err != nil {
+		return err
+	}
+	if err = s.applyDefinition(ctx, def, latest, domain.PluginVersionSourceRollback); err != nil {
+		return err
+	}
+
+	// 已绑定的模型按回滚后的定义补齐 Schema
+	bindings, err := s.repo.ListBindingsByPluginID(ctx, pluginID)
+	if err != nil || len(bindings) == 0 {
+		return err
+	}
+	schema, err := pluginx.StaticBuiltin(def).SchemaForBindings(bindings)
+	if err != nil || isEmptySchema(schema) {
+		return err
+	}
+	return s.importSchema(ctx, pluginID, schema)
+}
+
+// planUpgrade 对比最新版本快照生成升级预览，并检查将被删除的字段是否仍在使用
+func (s *service) planUpgrade(ctx context.Context, def pluginx.Definition) (domain.PluginUpgradePlan, domain.PluginVersion, error) {
+	latest, err := s.repo.GetLatestVersion(systemContext(ctx), def.Plugin.UID)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return domain.PluginUpgradePlan{}, domain.PluginVersion{}, err
+	}
+
+	plan := domain.PluginUpgradePlan{
+		PluginID:     def.Plugin.UID,
+		FromRevision: latest.Revision,
+		Diff:         pluginx.DiffDefinitions(latest.Definition, def),
+	}
+	plan.Blocked, err = s.fieldsInUse(ctx, def.Plugin.UID, plan.Diff.Fields.Removed)
+	if err != nil {
+		return domain.PluginUpgradePlan{}, domain.PluginVersion{}, err
+	}
+	return plan, latest, nil
+}
+
+// applyDefinition 落库插件定义、登记动作权限，定义内容变化时追加版本快照
+func (s *service) applyDefinition(ctx context.Context, def pluginx.Definition, latest domain.PluginVersion, source string) error {
+	if err := s.upsertPlugin(ctx, def.Plugin); err != nil {
+		return err
+	}
+
+	permissions := def.Plugin.ActionPermissions()
+	if len(permissions) > 0 {
+		if err := s.permissions.Register(ctx, permissions); err != nil {
+			return fmt.Errorf("登记插件动作权限失败: %w", err)
+		}
+	}
+	// 升级或回滚后不再声明的权限需要注销，否则会一直上报到权限中心
+	if removed := removedPermissions(latest.Definition.Plugin.ActionPermissions(), permissions); len(removed) > 0 {
+		if err := s.permissions.Unregister(ctx, removed); err != nil {
+			return fmt.Errorf("注销插件动作权限失败: %w", err)
+		}
+	}
+
+	checksum, err := def.Checksum()
+	if err != nil {
+		return err
+	}
+	if checksum == latest.Checksum {
+		return nil
+	}
+	return s.repo.CreateVersion(systemContext(ctx), domain.PluginVersion{
+		PluginID:   def.Plugin.UID,
+		Version:    def.Plugin.Version,
+		Revision:   latest.Revision + 1,
+		Checksum:   checksum,
+		Source:     source,
+		Definition: def.Snapshot(),
+	})
+}
+
+// removedPermissions 返回旧定义声明、新定义不再声明的权限
+func removedPermissions(previous, current []pluginx.ActionPermission) []pluginx.ActionPermission {
+	codes := lo.SliceToMap(current, func(permission pluginx.ActionPermission) (string, struct{}) {
+		return permission.Code, struct{}{}
+	})
+	return lo.UniqBy(lo.Filter(previous, func(permission pluginx.ActionPermission, _ int) bool {
+		_, ok := codes[permission.Code]
+		return !ok
+	}), func(permission pluginx.ActionPermission) string {
+		return permission.Code
+	})
+}
+
+// fieldsInUse 检查字段在各租户下是否仍有资源数据，或被插件绑定映射
+// NOTE: 插件模型由所有租户共享，资源和绑定需要跨租户统计
+func (s *service) fieldsInUse(ctx context.Context, pluginID string, fields []pluginx.FieldRef) ([]domain.PluginFieldUsage, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	allCtx := mongoplugin.IgnoreTenantContext(ctx)
+	bindings, err := s.repo.ListBindingsByPluginID(allCtx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []domain.PluginFieldUsage
+	for _, field := range fields {
+		_, total, err := s.resolver.resources.ListResourcesWithFilters(allCtx, nil, field.ModelUID, nil, 0, 1,
+			[]domain.FilterGroup{{Filters: []domain.FilterCondition{
+				{FieldUID: field.FieldUID, Operator: domain.OperatorNe, Value: nil},
+				{FieldUID: field.FieldUID, Operator: domain.OperatorNe, Value: ""},
+			}}},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		usage := domain.PluginFieldUsage{
+			FieldRef:  field,
+			Resources: total,
+			Bindings:  bindingsMappingField(bindings, field),
+		}
+		if usage.InUse() {
+			usages = append(usages, usage)
+		}
+	}
+	return usages, nil
+}
+
+func bindingsMappingField(bindings []domain.PluginBinding, field pluginx.FieldRef) []string {
+	var uids []string
+	for _, binding := range bindings {
+		if binding.Graph == nil {
+			continue
+		}
+		mapped := lo.ContainsBy(binding.Graph.Nodes, func(node pluginx.BindingGraphNode) bool {
+			return node.ModelUID == field.ModelUID && lo.ContainsBy(node.FieldMappings, func(m pluginx.FieldMapping) bool {
+				return m.ResourceField == field.FieldUID
+			})
+		})
+		if mapped {
+			uids = append(uids, binding.UID)
+		}
+	}
+	return lo.Uniq(uids)
+}
+
+func upgradeBlockedError(plan domain.PluginUpgradePlan) error {
+	if len(plan.Blocked) == 0 {
+		return nil
+	}
+	fields := lo.Map(plan.Blocked, func(u domain.PluginFieldUsage, _ int) string {
+		return u.ModelUID + "." + u.FieldUID
+	})
+	return errs.PluginUpgradeBlocked.WithMsg(
+		fmt.Sprintf("插件升级会删除仍在使用的字段: %s", strings.Join(fields, ", ")),
+	)
+}
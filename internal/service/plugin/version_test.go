@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+)
+
+func TestImportDefinitionRecordsVersions(t *testing.T) {
+	repo := &stubPluginRepo{}
+	svc := &service{repo: repo}
+	ctx := context.Background()
+
+	if err := svc.ImportDefinition(ctx, versionTestDefinition("1.0.0", "ip", "password")); err != nil {
+		t.Fatalf("ImportDefinition() error = %v", err)
+	}
+	// 内容相同的定义重复导入不追加版本
+	if err := svc.ImportDefinition(ctx, versionTestDefinition("1.0.0", "ip", "password")); err != nil {
+		t.Fatalf("ImportDefinition() error = %v", err)
+	}
+	if err := svc.ImportDefinition(ctx, versionTestDefinition("1.1.0", "ip", "password", "port")); err != nil {
+		t.Fatalf("ImportDefinition() error = %v", err)
+	}
+
+	if len(repo.versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(repo.versions))
+	}
+	if v := repo.versions[1]; v.Revision != 2 || v.Version != "1.1.0" || v.Source != domain.PluginVersionSourceImport {
+		t.Fatalf("unexpected latest version: %#v", v)
+	}
+
+	diff, err := svc.DiffVersions(ctx, "builtin.ssh", 1, 2)
+	if err != nil {
+		t.Fatalf("DiffVersions() error = %v", err)
+	}
+	if len(diff.Fields.Added) != 1 || diff.Fields.Added[0].FieldUID != "port" {
+		t.Fatalf("unexpected diff: %#v", diff)
+	}
+}
+
+func TestImportDefinitionBlocksRemovingFieldsInUse(t *testing.T) {
+	tests := []struct {
+		name          string
+		filteredTotal map[string]int64
+		bindings      []domain.PluginBinding
+		wantBlocked   bool
+	}{
+		{
+			name: "field unused",
+		},
+		{
+			name:          "field has resource data",
+			filteredTotal: map[string]int64{"host": 3},
+			wantBlocked:   true,
+		},
+		{
+			name: "field mapped by binding",
+			bindings: []domain.PluginBinding{{
+				UID:      "builtin.ssh.host",
+				PluginID: "builtin.ssh",
+				ModelUID: "host",
+				Graph:    mustCenterGraph(t, "target", "host", map[string]string{"password": "password"}, nil),
+			}},
+			wantBlocked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubPluginRepo{
+				bindingsByPluginID: map[string][]domain.PluginBinding{"builtin.ssh": tt.bindings},
+			}
+			svc := &service{
+				repo:     repo,
+				resolver: &inputResolver{resources: &stubResourceReader{filteredTotal: tt.filteredTotal}},
+			}
+			ctx := context.Background()
+			if err := svc.ImportDefinition(ctx, versionTestDefinition("1.0.0", "ip", "password")); err != nil {
+				t.Fatalf("ImportDefinition() error = %v", err)
+			}
+
+			err := svc.ImportDefinition(ctx, versionTestDefinition("2.0.0", "ip"))
+			var code errs.ErrorCode
+			blocked := errors.As(err, &code) && code.Code == errs.PluginUpgradeBlocked.Code
+			if blocked != tt.wantBlocked {
+				t.Fatalf("expected blocked=%v, got err %v", tt.wantBlocked, err)
+			}
+			if !tt.wantBlocked && err != nil {
+				t.Fatalf("ImportDefinition() error = %v", err)
+			}
+			if tt.wantBlocked && len(repo.versions) != 1 {
+				t.Fatalf("blocked upgrade should not record version, got %d", len(repo.versions))
+			}
+		})
+	}
+}
+
+func TestRollbackDefinition(t *testing.T) {
+	repo := &stubPluginRepo{}
+	svc := &service{repo: repo, resolver: &inputResolver{resources: &stubResourceReader{}}}
+	ctx := context.Background()
+
+	for _, def := range []pluginx.Definition{
+		versionTestDefinition("1.0.0", "ip"),
+		versionTestDefinition("2.0.0", "ip", "port"),
+	} {
+		if err := svc.ImportDefinition(ctx, def); err != nil {
+			t.Fatalf("ImportDefinition() error = %v", err)
+		}
+	}
+
+	if err := svc.RollbackDefinition(ctx, "builtin.ssh", 1); err != nil {
+		t.Fatalf("RollbackDefinition() error = %v", err)
+	}
+
+	if len(repo.versions) != 3 {
+		t.Fatalf("expected rollback to record a new version, got %d", len(repo.versions))
+	}
+	latest := repo.versions[2]
+	if latest.Revision != 3 || latest.Version != "1.0.0" || latest.Source != domain.PluginVersionSourceRollback {
+		t.Fatalf("unexpected rollback version: %#v", latest)
+	}
+	if latest.Checksum != repo.versions[0].Checksum {
+		t.Fatal("expected rollback version to restore revision 1 definition")
+	}
+	if repo.plugin.Version != "1.0.0" {
+		t.Fatalf("expected plugin version restored, got %q", repo.plugin.Version)
+	}
+}
+
+func TestImportDefinitionUnregistersRemovedPermissions(t *testing.T) {
+	checker := &stubPermissionChecker{}
+	svc := &service{repo: &stubPluginRepo{}, permissions: checker}
+	ctx := context.Background()
+
+	v1 := versionTestDefinition("1.0.0", "ip")
+	v1.Plugin.Actions = []pluginx.ActionSpec{
+		{Action: "terminal", Name: "SSH 终端", Permission: "cmdb:ssh:terminal"},
+		{Action: "sftp", Name: "文件传输", Permission: "cmdb:ssh:sftp"},
+	}
+	v2 := versionTestDefinition("2.0.0", "ip")
+	v2.Plugin.Actions = []pluginx.ActionSpec{
+		{Action: "terminal", Name: "SSH 终端", Permission: "cmdb:ssh:terminal"},
+	}
+	for _, def := range []pluginx.Definition{v1, v2} {
+		if err := svc.ImportDefinition(ctx, def); err != nil {
+			t.Fatalf("ImportDefinition() error = %v", err)
+		}
+	}
+
+	if len(checker.unregistered) != 1 || checker.unregistered[0].Code != "cmdb:ssh:sftp" ||
+		checker.unregistered[0].PluginID != "builtin.ssh" {
+		t.Fatalf("expected removed action permission unregistered, got %#v", checker.unregistered)
+	}
+}
+
+func versionTestDefinition(version string, fields ...string) pluginx.Definition {
+	attrs := make([]pluginx.Attribute, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, pluginx.Attribute{UID: field, Name: field, Type: "string"})
+	}
+	return pluginx.Definition{
+		Plugin: pluginx.Plugin{UID: "builtin.ssh", Name: "SSH", Version: version},
+		Schema: pluginx.Schema{
+			Models: []pluginx.ModelSpec{{
+				UID:             "host",
+				Name:            "主机",
+				AttributeGroups: []pluginx.AttributeGroup{{Name: "基础信息", Fields: attrs}},
+			}},
+		},
+	}
+}
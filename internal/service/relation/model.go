@@ -44,8 +44,20 @@ type RelationModelService interface {
 
 	// CheckBeforeDelete 检查模型是否被关联配置使用，有则拦截
 	CheckBeforeDelete(ctx context.Context, modelUid string) error
+
+	// DeleteImpact 评估删除模型关联关系会影响的资源关联数据
+	DeleteImpact(ctx context.Context, id int64) (domain.RelationImpact, error)
+
+	// MigrateResourceRelations 将模型关联 fromID 下的资源关联数据整体改挂到 toID 下
+	MigrateResourceRelations(ctx context.Context, fromID, toID int64) (domain.RelationMigration, error)
+
+	// ForceDeleteModelRelation 级联删除模型关联关系及其下所有资源关联数据，返回删除的资源关联数量
+	ForceDeleteModelRelation(ctx context.Context, id int64) (int64, error)
 }
 
+// impactSampleLimit 影响评估中返回的资源关联样例数量
+const impactSampleLimit = 20
+
 type modelService struct {
 	repo         repository.RelationModelRepository
 	resourceRepo repository.RelationResourceRepository
@@ -119,12 +131,122 @@ func (s *modelService) DeleteModelRelation(ctx context.Context, id int64) (int64
 		return 0, err
 	}
 	if count > 0 {
-		return 0, fmt.Errorf("%w: 关联关系正在被 %d 个资源关联数据使用，无法删除", ErrDependency, count)
+		return 0, fmt.Errorf("%w: 关联关系正在被 %d 个资源关联数据使用，无法删除，请先迁移资源关联数据或强制删除", ErrDependency, count)
 	}
 
 	return s.repo.DeleteModelRelation(ctx, id)
 }
 
+func (s *modelService) DeleteImpact(ctx context.Context, id int64) (domain.RelationImpact, error) {
+	mr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.RelationImpact{}, err
+	}
+
+	return relationImpact(ctx, s.resourceRepo, mr)
+}
+
+func (s *modelService) MigrateResourceRelations(ctx context.Context, fromID, toID int64) (domain.RelationMigration, error) {
+	if fromID == toID {
+		return domain.RelationMigration{}, errs.ValidationError.WithMsg("迁移的源关联关系与目标关联关系不能相同")
+	}
+
+	from, err := s.repo.GetByID(ctx, fromID)
+	if err != nil {
+		return domain.RelationMigration{}, err
+	}
+	to, err := s.repo.GetByID(ctx, toID)
+	if err != nil {
+		return domain.RelationMigration{}, err
+	}
+
+	// NOTE: 仅允许在两端模型一致的关联定义之间迁移，否则资源关联的端点将失去意义
+	if from.SourceModelUID != to.SourceModelUID || from.TargetModelUID != to.TargetModelUID {
+		return domain.RelationMigration{}, errs.ValidationError.WithMsg(
+			fmt.Sprintf("关联关系 %s 与 %s 的源端或目标端模型不一致，无法迁移", from.RelationName, to.RelationName))
+	}
+
+	var (
+		eg       errgroup.Group
+		moving   []domain.ResourceRelation
+		existing []domain.ResourceRelation
+	)
+	eg.Go(func() error {
+		var err error
+		moving, err = s.resourceRepo.ListByRelationName(ctx, from.RelationName, 0)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		existing, err = s.resourceRepo.ListByRelationName(ctx, to.RelationName, 0)
+		return err
+	})
+	if err = eg.Wait(); err != nil {
+		return domain.RelationMigration{}, fmt.Errorf("查询待迁移资源关联失败: %w", err)
+	}
+
+	plan, err := domain.PlanRelationMigration(to, moving, existing)
+	if err != nil {
+		return domain.RelationMigration{}, errs.RelationMappingConstraint.WithMsg(err.Error())
+	}
+
+	// NOTE: 先移除目标下已存在的重复端点，再整体改挂，避免迁移后出现重复关联
+	if _, err = s.resourceRepo.DeleteByIds(ctx, plan.DuplicateIDs); err != nil {
+		return domain.RelationMigration{}, fmt.Errorf("移除重复资源关联失败: %w", err)
+	}
+	if _, err = s.resourceRepo.MigrateRelationName(ctx, plan.MoveIDs, to.RelationTypeUID, to.RelationName); err != nil {
+		return domain.RelationMigration{}, err
+	}
+
+	return plan, nil
+}
+
+func (s *modelService) ForceDeleteModelRelation(ctx context.Context, id int64) (int64, error) {
+	mr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.resourceRepo.DeleteByRelationName(ctx, mr.RelationName)
+	if err != nil {
+		return 0, fmt.Errorf("级联删除资源关联失败: %w", err)
+	}
+
+	if _, err = s.repo.DeleteModelRelation(ctx, id); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// relationImpact 统计指定模型关联下的资源关联数量并抽取样例
+func relationImpact(ctx context.Context, resourceRepo repository.RelationResourceRepository,
+	mr domain.ModelRelation) (domain.RelationImpact, error) {
+	var (
+		eg      errgroup.Group
+		total   int64
+		samples []domain.ResourceRelation
+	)
+	eg.Go(func() error {
+		var err error
+		total, err = resourceRepo.CountByRelationName(ctx, mr.RelationName)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		samples, err = resourceRepo.ListByRelationName(ctx, mr.RelationName, impactSampleLimit)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return domain.RelationImpact{}, err
+	}
+
+	return domain.RelationImpact{
+		ModelRelation: mr,
+		Total:         total,
+		Samples:       samples,
+	}, nil
+}
+
 func (s *modelService) UpdateModelRelation(ctx context.Context, req domain.ModelRelation) (int64, error) {
 	// NOTE: 入参自身的错误先拦截成业务错误，避免被仓储查询错误掩盖成系统异常。
 	if err := req.Validate(); err != nil {
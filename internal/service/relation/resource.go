@@ -30,6 +30,10 @@ type RelationResourceService interface {
 	// ListSrcAggregated 聚合查询源端关联列表
 	ListSrcAggregated(ctx context.Context, modelUid string, id int64) ([]domain.ResourceAggregatedAssets, error)
 
+	// ListRelatedIdsBatch 批量聚合查询资源在指定关联下的对端资源 ID，isSource 表示 ids 位于关联的源端
+	// NOTE: 返回 资源 ID -> 对端资源 ID 列表，用于导出等批量场景，避免逐条查询
+	ListRelatedIdsBatch(ctx context.Context, relationName string, isSource bool, ids []int64) (map[int64][]int64, error)
+
 	// ListSrcRelated 查询当前已经关联的数据，新增资源关联使用
 	ListSrcRelated(ctx context.Context, modelUid, relationName string, id int64) ([]int64, error)
 
@@ -52,6 +56,9 @@ type RelationResourceService interface {
 
 	// ListRecursiveDiagram 递归获取多级关联拓扑（支持最大深度）
 	ListRecursiveDiagram(ctx context.Context, modelUid string, id int64, maxDepth int) (domain.ResourceDiagram, error)
+
+	// ListRecursiveDiagramAsOf 递归获取指定时间点的多级关联拓扑，深度语义与 ListRecursiveDiagram 一致
+	ListRecursiveDiagramAsOf(ctx context.Context, modelUid string, id int64, maxDepth int, asOf int64) (domain.ResourceDiagram, error)
 }
 
 type resourceService struct {
@@ -239,6 +246,14 @@ func (s *resourceService) ListDstAggregated(ctx context.Context, modelUid string
 	return s.repo.ListDstAggregated(ctx, modelUid, id)
 }
 
+func (s *resourceService) ListRelatedIdsBatch(ctx context.Context, relationName string, isSource bool,
+	ids []int64) (map[int64][]int64, error) {
+	if len(ids) == 0 {
+		return map[int64][]int64{}, nil
+	}
+	return s.repo.ListRelatedIdsBatch(ctx, relationName, isSource, ids)
+}
+
 func (s *resourceService) ListSrcRelated(ctx context.Context, modelUid, relationName string, id int64) ([]int64, error) {
 	return s.repo.ListSrcRelated(ctx, modelUid, relationName, id)
 }
@@ -308,3 +323,71 @@ func (s *resourceService) ListRecursiveDiagram(ctx context.Context, modelUid str
 	}
 	return rd, nil
 }
+
+func (s *resourceService) ListRecursiveDiagramAsOf(ctx context.Context, modelUid string, id int64, maxDepth int,
+	asOf int64) (domain.ResourceDiagram, error) {
+	var (
+		eg errgroup.Group
+		rd domain.ResourceDiagram
+	)
+
+	eg.Go(func() error {
+		var err error
+		rd.SRC, err = s.walkRelationsAsOf(ctx, true, modelUid, id, maxDepth, asOf)
+		return err
+	})
+
+	eg.Go(func() error {
+		var err error
+		rd.DST, err = s.walkRelationsAsOf(ctx, false, modelUid, id, maxDepth, asOf)
+		return err
+	})
+
+	if err := eg.Wait(); err != nil {
+		return domain.ResourceDiagram{}, err
+	}
+	return rd, nil
+}
+
+// walkRelationsAsOf 按层遍历 asOf 时刻的关联拓扑，isSource 表示沿下游方向展开
+// NOTE: 首层匹配根节点后再展开 maxDepth+1 层，与 $graphLookup 的 maxDepth 语义保持一致
+func (s *resourceService) walkRelationsAsOf(ctx context.Context, isSource bool, modelUid string, id int64,
+	maxDepth int, asOf int64) ([]domain.ResourceRelation, error) {
+	var (
+		result   []domain.ResourceRelation
+		seen     = make(map[int64]struct{})
+		visited  = map[int64]struct{}{id: {}}
+		frontier = []int64{id}
+	)
+
+	for depth := 0; depth <= maxDepth+1 && len(frontier) > 0; depth++ {
+		rrs, err := s.repo.ListRelationsAsOf(ctx, isSource, frontier, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []int64
+		for _, rr := range rrs {
+			peer, rootModelUid := rr.TargetResourceID, rr.SourceModelUID
+			if !isSource {
+				peer, rootModelUid = rr.SourceResourceID, rr.TargetModelUID
+			}
+			if depth == 0 && rootModelUid != modelUid {
+				continue
+			}
+			if _, ok := seen[rr.ID]; ok {
+				continue
+			}
+			seen[rr.ID] = struct{}{}
+			result = append(result, rr)
+
+			if _, ok := visited[peer]; !ok {
+				visited[peer] = struct{}{}
+				next = append(next, peer)
+			}
+		}
+		frontier = next
+	}
+
+	return result, nil
+}
@@ -31,6 +31,12 @@ type RelationTypeService interface {
 
 	// Delete 删除关联类型
 	Delete(ctx context.Context, id int64) (int64, error)
+
+	// DeleteImpact 评估删除关联类型会影响的模型关联及资源关联数据
+	DeleteImpact(ctx context.Context, id int64) (domain.RelationTypeImpact, error)
+
+	// ForceDelete 级联删除关联类型及其下所有模型关联、资源关联数据，返回删除的资源关联数量
+	ForceDelete(ctx context.Context, id int64) (int64, error)
 }
 
 type service struct {
@@ -118,3 +124,55 @@ func (s *service) Delete(ctx context.Context, id int64) (int64, error) {
 
 	return s.repo.Delete(ctx, id)
 }
+
+func (s *service) DeleteImpact(ctx context.Context, id int64) (domain.RelationTypeImpact, error) {
+	rt, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.RelationTypeImpact{}, err
+	}
+
+	mrs, err := s.modelRepo.ListByRelationTypeUID(ctx, rt.UID)
+	if err != nil {
+		return domain.RelationTypeImpact{}, err
+	}
+
+	impact := domain.RelationTypeImpact{
+		RelationType:   rt,
+		ModelRelations: make([]domain.RelationImpact, 0, len(mrs)),
+	}
+	for _, mr := range mrs {
+		ri, err := relationImpact(ctx, s.resourceRepo, mr)
+		if err != nil {
+			return domain.RelationTypeImpact{}, err
+		}
+		impact.ModelRelations = append(impact.ModelRelations, ri)
+	}
+
+	// NOTE: 按关联类型整体统计，兜底未登记模型关联的历史脏数据
+	impact.Total, err = s.resourceRepo.CountByRelationTypeUID(ctx, rt.UID)
+	if err != nil {
+		return domain.RelationTypeImpact{}, err
+	}
+	return impact, nil
+}
+
+func (s *service) ForceDelete(ctx context.Context, id int64) (int64, error) {
+	rt, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	// NOTE: 删除顺序为资源关联 -> 模型关联 -> 关联类型，中途失败可重复执行
+	count, err := s.resourceRepo.DeleteByRelationTypeUID(ctx, rt.UID)
+	if err != nil {
+		return 0, fmt.Errorf("级联删除资源关联失败: %w", err)
+	}
+	if _, err = s.modelRepo.DeleteByRelationTypeUID(ctx, rt.UID); err != nil {
+		return count, fmt.Errorf("级联删除模型关联失败: %w", err)
+	}
+
+	if _, err = s.repo.Delete(ctx, id); err != nil {
+		return count, err
+	}
+	return count, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/samber/lo"
+)
+
+const (
+	// searchCandidateLimit 单次检索读取的候选资产上限，避免宽泛的检索词占满内存
+	searchCandidateLimit = 5000
+	// defaultSearchLimit 每个模型分组默认返回的数量
+	defaultSearchLimit = 10
+	// maxSearchLimit 每个模型分组最多返回的数量
+	maxSearchLimit = 100
+)
+
+func (s *service) Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchGroup, error) {
+	if len(query.Terms) == 0 {
+		return []domain.SearchGroup{}, nil
+	}
+
+	ids, err := s.index.Search(ctx, query.ModelUid, query.Terms, searchCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []domain.SearchGroup{}, nil
+	}
+
+	// NOTE: 索引可能落后于资产存储，以最新数据为准重新匹配，已删除的资产自然被过滤
+	candidates, err := s.repo.ListFullResourcesByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	secureFields, err := s.attrSvc.SearchAttributeFieldsBySecure(ctx, lo.Uniq(lo.Map(candidates,
+		func(src domain.Resource, _ int) string {
+			return src.ModelUID
+		})))
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: 索引只负责召回，安全字段中的密文可能被误命中，需要在剔除安全字段后重新匹配并计算相关度
+	hits := lo.FilterMap(candidates, func(src domain.Resource, _ int) (domain.SearchHit, bool) {
+		excluded := secureFields[src.ModelUID]
+		hit, ok := domain.MatchResource(src, query.Terms, excluded)
+		if ok {
+			hit.Resource.Data = lo.OmitByKeys(src.Data, excluded)
+		}
+		return hit, ok
+	})
+
+	// NOTE: 召回数量达到上限时超出部分被截断，各分组的命中总数可能偏小
+	truncated := len(ids) >= searchCandidateLimit
+	return groupSearchHits(hits, truncated, query.Offset, query.Limit), nil
+}
+
+// groupSearchHits 按模型分组并在组内分页，组内按相关度倒序，分组按最高相关度倒序
+func groupSearchHits(hits []domain.SearchHit, truncated bool, offset, limit int64) []domain.SearchGroup {
+	switch {
+	case limit <= 0:
+		limit = defaultSearchLimit
+	case limit > maxSearchLimit:
+		limit = maxSearchLimit
+	}
+
+	groups := lo.MapToSlice(lo.GroupBy(hits, func(hit domain.SearchHit) string {
+		return hit.Resource.ModelUID
+	}), func(modelUid string, hs []domain.SearchHit) domain.SearchGroup {
+		sort.SliceStable(hs, func(i, j int) bool {
+			if hs[i].Score != hs[j].Score {
+				return hs[i].Score > hs[j].Score
+			}
+			return hs[i].Resource.ID > hs[j].Resource.ID
+		})
+		return domain.SearchGroup{
+			ModelUid:  modelUid,
+			Total:     len(hs),
+			Truncated: truncated,
+			Hits:      hs,
+		}
+	})
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Hits[0].Score != groups[j].Hits[0].Score {
+			return groups[i].Hits[0].Score > groups[j].Hits[0].Score
+		}
+		if groups[i].Total != groups[j].Total {
+			return groups[i].Total > groups[j].Total
+		}
+		return groups[i].ModelUid < groups[j].ModelUid
+	})
+
+	for i := range groups {
+		groups[i].Hits = lo.Subset(groups[i].Hits, int(offset), uint(limit))
+	}
+	return groups
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+)
+
+const (
+	// searchSyncBatchSize 每批同步的变更日志数量
+	searchSyncBatchSize = 1000
+	// searchRebuildBatchSize 全量重建时每批读取的资产数量
+	searchRebuildBatchSize = 1000
+	// searchSyncLag 变更日志的写入时间戳与落库存在先后差异，只同步该时长之前的变更，避免并发写入时遗漏
+	searchSyncLag = 2 * time.Second
+)
+
+func (s *service) SyncSearchIndex(ctx context.Context) (int, error) {
+	if !s.index.NeedSync() {
+		return 0, nil
+	}
+
+	checkpoint, err := s.index.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint == 0 {
+		return s.RebuildSearchIndex(ctx)
+	}
+	// NOTE: 位点早于历史保留起点时，期间的变更日志可能已被清理，无法增量补齐，只能全量重建
+	start, err := s.repo.EarliestSnapshotTime(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("查询历史保留起点失败: %w", err)
+	}
+	if checkpoint < start {
+		return s.RebuildSearchIndex(ctx)
+	}
+
+	var (
+		total int
+		until = time.Now().Add(-searchSyncLag).UnixMilli()
+	)
+	for checkpoint < until {
+		changes, err := s.repo.ListChangesSince(ctx, checkpoint, until, searchSyncBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("查询资产变更日志失败: %w", err)
+		}
+
+		full := len(changes) == searchSyncBatchSize
+		if full {
+			// NOTE: 位点按毫秒记录，同一毫秒的变更必须在同一批次内处理完，否则下一批会跳过剩余部分
+			last := changes[len(changes)-1].Ctime
+			changes = lo.DropRightWhile(changes, func(src domain.ResourceChange) bool {
+				return src.Ctime == last
+			})
+			if len(changes) == 0 {
+				if changes, err = s.repo.ListChangesSince(ctx, last-1, last, 0); err != nil {
+					return total, fmt.Errorf("查询资产变更日志失败: %w", err)
+				}
+			}
+		}
+
+		if err = s.applySearchChanges(ctx, changes); err != nil {
+			return total, err
+		}
+		total += len(changes)
+
+		checkpoint = until
+		if full {
+			checkpoint = changes[len(changes)-1].Ctime
+		}
+		if err = s.index.Commit(ctx, checkpoint); err != nil {
+			return total, fmt.Errorf("保存检索索引失败: %w", err)
+		}
+	}
+
+	return total, nil
+}
+
+func (s *service) RebuildSearchIndex(ctx context.Context) (int, error) {
+	if !s.index.NeedSync() {
+		return 0, nil
+	}
+
+	if err := s.index.Reset(ctx); err != nil {
+		return 0, err
+	}
+
+	// 重建期间产生的变更由后续增量同步重放，create / update 记录完整数据，重复应用不影响结果
+	checkpoint := time.Now().Add(-searchSyncLag).UnixMilli()
+	total, err := s.putAllSearchDocuments(ctx)
+	if err != nil {
+		return total, err
+	}
+
+	if err = s.index.Commit(ctx, checkpoint); err != nil {
+		return total, fmt.Errorf("保存检索索引失败: %w", err)
+	}
+	return total, nil
+}
+
+func (s *service) ReconcileSearchIndex(ctx context.Context) (int, error) {
+	if !s.index.NeedSync() {
+		return 0, nil
+	}
+
+	checkpoint, err := s.index.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// 索引尚未建立时由增量同步执行全量重建
+	if checkpoint == 0 {
+		return 0, nil
+	}
+
+	// NOTE: 不清空索引，校准期间检索不受影响；遗留的已删除资产在检索时按最新数据过滤
+	total, err := s.putAllSearchDocuments(ctx)
+	if err != nil {
+		return total, err
+	}
+	if err = s.index.Commit(ctx, checkpoint); err != nil {
+		return total, fmt.Errorf("保存检索索引失败: %w", err)
+	}
+	return total, nil
+}
+
+// putAllSearchDocuments 按 ID 分批读取全部资产写入检索索引
+func (s *service) putAllSearchDocuments(ctx context.Context) (int, error) {
+	var (
+		afterID int64
+		total   int
+	)
+	for {
+		docs, err := s.repo.ListSearchDocuments(ctx, afterID, searchRebuildBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("读取资产失败: %w", err)
+		}
+		if len(docs) == 0 {
+			return total, nil
+		}
+
+		if err = s.putSearchDocuments(ctx, docs); err != nil {
+			return total, err
+		}
+		total += len(docs)
+
+		if len(docs) < searchRebuildBatchSize {
+			return total, nil
+		}
+		afterID = docs[len(docs)-1].ID
+	}
+}
+
+// applySearchChanges 按时间顺序将变更日志应用到检索索引，连续的 create / update 合并为一次批量写入
+func (s *service) applySearchChanges(ctx context.Context, changes []domain.ResourceChange) error {
+	var docs []domain.SearchDocument
+	flush := func() error {
+		if len(docs) == 0 {
+			return nil
+		}
+		err := s.putSearchDocuments(ctx, docs)
+		docs = nil
+		return err
+	}
+
+	for _, change := range changes {
+		switch change.Action {
+		case domain.ChangeActionCreate, domain.ChangeActionUpdate:
+			docs = append(docs, domain.SearchDocument{
+				TenantID: change.TenantID,
+				ID:       change.ResourceID,
+				ModelUID: change.ModelUID,
+				Data:     change.Data,
+			})
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+
+		var err error
+		switch change.Action {
+		case domain.ChangeActionDelete:
+			err = s.index.Delete(ctx, []int64{change.ResourceID})
+		case domain.ChangeActionUnset:
+			err = s.index.UnsetFields(ctx, change.TenantID, change.ModelUID, change.Fields)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+// putSearchDocuments 剔除安全字段后写入检索索引，安全字段按资产所属租户分别查询
+func (s *service) putSearchDocuments(ctx context.Context, docs []domain.SearchDocument) error {
+	for tenantID, group := range lo.GroupBy(docs, func(src domain.SearchDocument) int64 {
+		return src.TenantID
+	}) {
+		secureFields, err := s.attrSvc.SearchAttributeFieldsBySecure(ctxutil.WithTenantID(ctx, tenantID),
+			lo.Uniq(lo.Map(group, func(src domain.SearchDocument, _ int) string {
+				return src.ModelUID
+			})))
+		if err != nil {
+			return fmt.Errorf("查询安全字段失败: %w", err)
+		}
+
+		if err = s.index.Put(ctx, lo.Map(group, func(src domain.SearchDocument, _ int) domain.SearchDocument {
+			src.Data = lo.OmitByKeys(src.Data, secureFields[src.ModelUID])
+			return src
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	attributemocks "github.com/Duke1616/ecmdb/internal/mocks/attributemocks"
+	repositorymocks "github.com/Duke1616/ecmdb/internal/mocks/repositorymocks"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_SyncSearchIndex(t *testing.T) {
+	secureFields := map[string][]string{"host": {"password"}}
+
+	testCases := []struct {
+		name      string
+		mock      func(ctrl *gomock.Controller) (*repositorymocks.MockResourceRepository, *repositorymocks.MockSearchIndex)
+		wantCount int
+	}{
+		{
+			name: "索引尚未建立时全量重建",
+			mock: func(ctrl *gomock.Controller) (*repositorymocks.MockResourceRepository, *repositorymocks.MockSearchIndex) {
+				repo := repositorymocks.NewMockResourceRepository(ctrl)
+				index := repositorymocks.NewMockSearchIndex(ctrl)
+				index.EXPECT().NeedSync().Return(true).AnyTimes()
+				index.EXPECT().Checkpoint(gomock.Any()).Return(int64(0), nil)
+				index.EXPECT().Reset(gomock.Any()).Return(nil)
+				repo.EXPECT().ListSearchDocuments(gomock.Any(), int64(0), int64(searchRebuildBatchSize)).
+					Return([]domain.SearchDocument{
+						{TenantID: 1, ID: 1, ModelUID: "host", Data: mongox.MapStr{"name": "web01", "password": "x"}},
+						{TenantID: 2, ID: 2, ModelUID: "host", Data: mongox.MapStr{"name": "web02"}},
+					}, nil)
+				index.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, docs []domain.SearchDocument) error {
+						for _, doc := range docs {
+							assert.NotContains(t, doc.Data, "password")
+						}
+						return nil
+					}).Times(2)
+				index.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil)
+				return repo, index
+			},
+			wantCount: 2,
+		},
+		{
+			name: "按顺序应用增量变更",
+			mock: func(ctrl *gomock.Controller) (*repositorymocks.MockResourceRepository, *repositorymocks.MockSearchIndex) {
+				repo := repositorymocks.NewMockResourceRepository(ctrl)
+				index := repositorymocks.NewMockSearchIndex(ctrl)
+				index.EXPECT().NeedSync().Return(true)
+				index.EXPECT().Checkpoint(gomock.Any()).Return(int64(1000), nil)
+				repo.EXPECT().EarliestSnapshotTime(gomock.Any()).Return(int64(500), nil)
+				repo.EXPECT().ListChangesSince(gomock.Any(), int64(1000), gomock.Any(), int64(searchSyncBatchSize)).
+					Return([]domain.ResourceChange{
+						{TenantID: 1, ResourceID: 1, ModelUID: "host", Action: domain.ChangeActionCreate,
+							Data: mongox.MapStr{"name": "web01", "password": "x"}, Ctime: 1001},
+						{TenantID: 1, ResourceID: 2, ModelUID: "host", Action: domain.ChangeActionUpdate,
+							Data: mongox.MapStr{"name": "web02"}, Ctime: 1002},
+						{TenantID: 1, ResourceID: 1, ModelUID: "host", Action: domain.ChangeActionDelete, Ctime: 1003},
+						{TenantID: 1, ModelUID: "host", Action: domain.ChangeActionUnset, Fields: []string{"env"},
+							Ctime: 1004},
+					}, nil)
+				gomock.InOrder(
+					index.EXPECT().Put(gomock.Any(), gomock.Len(2)).Return(nil),
+					index.EXPECT().Delete(gomock.Any(), []int64{1}).Return(nil),
+					index.EXPECT().UnsetFields(gomock.Any(), int64(1), "host", []string{"env"}).Return(nil),
+					index.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil),
+				)
+				return repo, index
+			},
+			wantCount: 4,
+		},
+		{
+			name: "位点早于历史保留起点时全量重建",
+			mock: func(ctrl *gomock.Controller) (*repositorymocks.MockResourceRepository, *repositorymocks.MockSearchIndex) {
+				repo := repositorymocks.NewMockResourceRepository(ctrl)
+				index := repositorymocks.NewMockSearchIndex(ctrl)
+				index.EXPECT().NeedSync().Return(true).AnyTimes()
+				index.EXPECT().Checkpoint(gomock.Any()).Return(int64(1000), nil)
+				repo.EXPECT().EarliestSnapshotTime(gomock.Any()).Return(int64(2000), nil)
+				index.EXPECT().Reset(gomock.Any()).Return(nil)
+				repo.EXPECT().ListSearchDocuments(gomock.Any(), int64(0), int64(searchRebuildBatchSize)).
+					Return([]domain.SearchDocument{
+						{TenantID: 1, ID: 1, ModelUID: "host", Data: mongox.MapStr{"name": "web01"}},
+					}, nil)
+				index.EXPECT().Put(gomock.Any(), gomock.Len(1)).Return(nil)
+				index.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil)
+				return repo, index
+			},
+			wantCount: 1,
+		},
+		{
+			name: "无需同步的索引直接跳过",
+			mock: func(ctrl *gomock.Controller) (*repositorymocks.MockResourceRepository, *repositorymocks.MockSearchIndex) {
+				index := repositorymocks.NewMockSearchIndex(ctrl)
+				index.EXPECT().NeedSync().Return(false)
+				return repositorymocks.NewMockResourceRepository(ctrl), index
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			attrSvc := attributemocks.NewMockService(ctrl)
+			attrSvc.EXPECT().SearchAttributeFieldsBySecure(gomock.Any(), gomock.Any()).
+				Return(secureFields, nil).AnyTimes()
+
+			repo, index := tc.mock(ctrl)
+			svc := NewService(repo, attrSvc, crypto(), index)
+
+			count, err := svc.SyncSearchIndex(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantCount, count)
+		})
+	}
+}
+
+func Test_ReconcileSearchIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	attrSvc := attributemocks.NewMockService(ctrl)
+	attrSvc.EXPECT().SearchAttributeFieldsBySecure(gomock.Any(), gomock.Any()).
+		Return(map[string][]string{}, nil).AnyTimes()
+
+	repo := repositorymocks.NewMockResourceRepository(ctrl)
+	index := repositorymocks.NewMockSearchIndex(ctrl)
+	index.EXPECT().NeedSync().Return(true)
+	index.EXPECT().Checkpoint(gomock.Any()).Return(int64(1000), nil)
+	repo.EXPECT().ListSearchDocuments(gomock.Any(), int64(0), int64(searchRebuildBatchSize)).
+		Return([]domain.SearchDocument{
+			{TenantID: 1, ID: 1, ModelUID: "host", Data: mongox.MapStr{"name": "web01"}},
+			{TenantID: 1, ID: 2, ModelUID: "host", Data: mongox.MapStr{"name": "web02"}},
+		}, nil)
+	// 校准不清空索引，也不推进同步位点
+	index.EXPECT().Reset(gomock.Any()).Times(0)
+	index.EXPECT().Put(gomock.Any(), gomock.Len(2)).Return(nil)
+	index.EXPECT().Commit(gomock.Any(), int64(1000)).Return(nil)
+
+	svc := NewService(repo, attrSvc, crypto(), index)
+	count, err := svc.ReconcileSearchIndex(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
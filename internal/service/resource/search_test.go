@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	attributemocks "github.com/Duke1616/ecmdb/internal/mocks/attributemocks"
+	repositorymocks "github.com/Duke1616/ecmdb/internal/mocks/repositorymocks"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Search_Resources(t *testing.T) {
+	candidates := []domain.Resource{
+		{ID: 4, ModelUID: "mysql", Data: mongox.MapStr{"name": "db-10.1.0.9", "password": "10.1.x"}},
+		{ID: 3, ModelUID: "host", Data: mongox.MapStr{"name": "web03", "ip": "10.1.0.3", "password": "p"}},
+		{ID: 2, ModelUID: "host", Data: mongox.MapStr{"name": "10.1.0.2", "ip": "10.1.0.2"}},
+		{ID: 1, ModelUID: "switch", Data: mongox.MapStr{"name": "core", "token": "10.1.secret"}},
+	}
+
+	testCases := []struct {
+		name   string
+		query  domain.SearchQuery
+		wantFn func(t *testing.T, groups []domain.SearchGroup)
+	}{
+		{
+			name:  "按相关度分组并剔除安全字段",
+			query: domain.SearchQuery{Terms: []domain.SearchTerm{{Value: "10.1"}}},
+			wantFn: func(t *testing.T, groups []domain.SearchGroup) {
+				assert.Equal(t, []string{"host", "mysql"}, modelUids(groups))
+				assert.Equal(t, 2, groups[0].Total)
+				assert.False(t, groups[0].Truncated)
+				assert.Equal(t, int64(2), groups[0].Hits[0].Resource.ID)
+				assert.NotContains(t, groups[0].Hits[1].Resource.Data, "password")
+			},
+		},
+		{
+			name: "分组内分页",
+			query: domain.SearchQuery{Terms: []domain.SearchTerm{{Value: "10.1"}},
+				Offset: 1, Limit: 1},
+			wantFn: func(t *testing.T, groups []domain.SearchGroup) {
+				assert.Equal(t, 2, groups[0].Total)
+				assert.Len(t, groups[0].Hits, 1)
+				assert.Equal(t, int64(3), groups[0].Hits[0].Resource.ID)
+				assert.Empty(t, groups[1].Hits)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			index := repositorymocks.NewMockSearchIndex(ctrl)
+			index.EXPECT().Search(gomock.Any(), "", tc.query.Terms, gomock.Any()).
+				Return([]int64{4, 3, 2, 1}, nil)
+
+			repo := repositorymocks.NewMockResourceRepository(ctrl)
+			repo.EXPECT().ListFullResourcesByIds(gomock.Any(), []int64{4, 3, 2, 1}).
+				Return(candidates, nil)
+
+			attrSvc := attributemocks.NewMockService(ctrl)
+			attrSvc.EXPECT().SearchAttributeFieldsBySecure(gomock.Any(), gomock.Any()).
+				Return(map[string][]string{
+					"host":   {"password"},
+					"mysql":  {"password"},
+					"switch": {"token"},
+				}, nil)
+
+			svc := NewService(repo, attrSvc, crypto(), index)
+			groups, err := svc.Search(context.Background(), tc.query)
+			assert.NoError(t, err)
+			tc.wantFn(t, groups)
+		})
+	}
+}
+
+func modelUids(groups []domain.SearchGroup) []string {
+	uids := make([]string, 0, len(groups))
+	for _, g := range groups {
+		uids = append(uids, g.ModelUid)
+	}
+	return uids
+}
@@ -51,12 +51,15 @@ type Service interface {
 	// CountByModelUids 聚合查看模型下的数量
 	CountByModelUids(ctx context.Context, modelUids []string) (map[string]int, error)
 
-	// Search 全局搜索
-	Search(ctx context.Context, text string) ([]domain.SearchResource, error)
+	// Search 全局搜索，按模型分组并在分组内按相关度分页，结果中不包含安全字段
+	Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchGroup, error)
 
 	// FindSecureData 查看指定资产加密字段数据
 	FindSecureData(ctx context.Context, id int64, fieldUid string) (string, error)
 
+	// ListExistingNames 查询模型下已存在的资产名称，导入时据此区分新增与修改
+	ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error)
+
 	// UpdateResource 修改资产数据
 	UpdateResource(ctx context.Context, resource domain.Resource) (int64, error)
 
@@ -75,22 +78,62 @@ type Service interface {
 	ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64,
 		filterGroups []domain.FilterGroup) ([]domain.Resource, int64, error)
 
+	// ListResourcesByQuery 根据筛选条件及排序字段获取资产列表，用于保存视图
+	ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, offset, limit int64) ([]domain.Resource,
+		int64, error)
+
+	// IterateResourcesByQuery 以游标方式按批遍历符合条件的资产并解密，用于大数据量导出
+	IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery, batchSize int,
+		fn func(batch []domain.Resource) error) error
+
 	// CheckBeforeDelete 检查指定模型下是否还有资产实例
 	CheckBeforeDelete(ctx context.Context, modelUid string) error
+
+	// FindResourceByIdAsOf 根据ID，获取资产在指定时间点的信息
+	FindResourceByIdAsOf(ctx context.Context, fields []string, id int64, asOf int64) (domain.Resource, error)
+
+	// ListResourceAsOf 获取指定时间点的资产数据
+	ListResourceAsOf(ctx context.Context, fields []string, modelUid string, asOf int64, offset, limit int64) ([]domain.Resource,
+		int64, error)
+
+	// ListResourceByIdsAsOf 根据 ID 列表，获取资产在指定时间点的信息
+	ListResourceByIdsAsOf(ctx context.Context, fields []string, ids []int64, asOf int64) ([]domain.Resource, error)
+
+	// TakeSnapshot 为全部资产生成一轮快照，返回快照数量
+	TakeSnapshot(ctx context.Context) (int64, error)
+
+	// LastSnapshotTime 查询最近一轮快照的时间，没有快照时返回 0
+	LastSnapshotTime(ctx context.Context) (int64, error)
+
+	// PruneHistory 清理 before 之前的历史数据，保留 before 之前最近一轮快照作为回放基线，返回删除的记录数量
+	// NOTE: 清理后早于该基线的时间点无法再回溯
+	PruneHistory(ctx context.Context, before int64) (int64, error)
+
+	// SyncSearchIndex 将变更日志增量同步到检索索引，索引尚未建立时执行全量重建，返回处理的变更数量
+	SyncSearchIndex(ctx context.Context) (int, error)
+
+	// RebuildSearchIndex 清空并全量重建检索索引，返回写入的资产数量
+	RebuildSearchIndex(ctx context.Context) (int, error)
+
+	// ReconcileSearchIndex 以资产存储为准重新写入全部检索文档，补齐变更日志缺失的部分，返回写入的资产数量
+	ReconcileSearchIndex(ctx context.Context) (int, error)
 }
 
 type service struct {
 	repo    repository.ResourceRepository
 	attrSvc attribute.Service
 	crypto  cryptox.Crypto
+	index   repository.SearchIndex
 	logger  *elog.Component
 }
 
-func NewService(repo repository.ResourceRepository, attrSvc attribute.Service, crypto cryptox.Crypto) Service {
+func NewService(repo repository.ResourceRepository, attrSvc attribute.Service, crypto cryptox.Crypto,
+	index repository.SearchIndex) Service {
 	return &service{
 		repo:    repo,
 		attrSvc: attrSvc,
 		crypto:  crypto,
+		index:   index,
 		logger:  elog.DefaultLogger,
 	}
 }
@@ -202,6 +245,15 @@ func (s *service) ListResourceByIds(ctx context.Context, fields []string, ids []
 
 func (s *service) ListResourcesWithFilters(ctx context.Context, fields []string, modelUid string, ids []int64, offset, limit int64,
 	filterGroups []domain.FilterGroup) ([]domain.Resource, int64, error) {
+	return s.ListResourcesByQuery(ctx, fields, domain.ResourceQuery{
+		ModelUID:     modelUid,
+		IDs:          ids,
+		FilterGroups: filterGroups,
+	}, offset, limit)
+}
+
+func (s *service) ListResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery,
+	offset, limit int64) ([]domain.Resource, int64, error) {
 	var (
 		total     int64
 		resources []domain.Resource
@@ -210,13 +262,13 @@ func (s *service) ListResourcesWithFilters(ctx context.Context, fields []string,
 
 	eg.Go(func() error {
 		var err error
-		resources, err = s.repo.ListResourcesWithFilters(ctx, fields, modelUid, ids, offset, limit, filterGroups)
+		resources, err = s.repo.ListResourcesByQuery(ctx, fields, query, offset, limit)
 		return err
 	})
 
 	eg.Go(func() error {
 		var err error
-		total, err = s.repo.TotalResourcesWithFilters(ctx, modelUid, ids, filterGroups)
+		total, err = s.repo.TotalResourcesWithFilters(ctx, query.ModelUID, query.IDs, query.FilterGroups)
 		return err
 	})
 
@@ -232,6 +284,17 @@ func (s *service) ListResourcesWithFilters(ctx context.Context, fields []string,
 	return decodedRs, total, err
 }
 
+func (s *service) IterateResourcesByQuery(ctx context.Context, fields []string, query domain.ResourceQuery,
+	batchSize int, fn func(batch []domain.Resource) error) error {
+	return s.repo.IterateResourcesByQuery(ctx, fields, query, batchSize, func(batch []domain.Resource) error {
+		decodedRs, err := s.decryptResources(ctx, batch)
+		if err != nil {
+			return err
+		}
+		return fn(decodedRs)
+	})
+}
+
 func (s *service) ListAndDecryptBeforeUtime(ctx context.Context, utime int64, fields []string, modelUid string, offset, limit int64) ([]domain.Resource, error) {
 	resources, err := s.repo.ListBeforeUtime(ctx, utime, fields, modelUid, offset, limit)
 	if err != nil {
@@ -249,6 +312,10 @@ func (s *service) ListAndDecryptBeforeUtime(ctx context.Context, utime int64, fi
 	return resources, nil
 }
 
+func (s *service) ListExistingNames(ctx context.Context, modelUid string, names []string) ([]string, error) {
+	return s.repo.ListExistingNames(ctx, modelUid, names)
+}
+
 func (s *service) FindSecureData(ctx context.Context, id int64, fieldUid string) (string, error) {
 	encryptedData, err := s.repo.FindSecureData(ctx, id, fieldUid)
 	if err != nil {
@@ -310,10 +377,6 @@ func (s *service) CountByModelUids(ctx context.Context, modelUids []string) (map
 	return s.repo.CountByModelUids(ctx, modelUids)
 }
 
-func (s *service) Search(ctx context.Context, text string) ([]domain.SearchResource, error) {
-	return s.repo.Search(ctx, text)
-}
-
 func (s *service) CheckBeforeDelete(ctx context.Context, modelUid string) error {
 	count, err := s.repo.TotalByModelUid(ctx, modelUid)
 	if err != nil {
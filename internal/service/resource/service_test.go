@@ -101,7 +101,7 @@ func Test_BatchUpdate_Resources(t *testing.T) {
 
 			attrSvc, repo := tc.mock(ctrl)
 			c := crypto()
-			svc := NewService(repo, attrSvc, c)
+			svc := NewService(repo, attrSvc, c, nil)
 
 			_, err := svc.BatchUpdateResources(context.Background(), tc.input)
 
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/internal/repository"
+	attribute "github.com/Duke1616/ecmdb/internal/service/attribute"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/samber/lo"
+)
+
+// sortableSystemFields 资产的系统字段，可用于排序
+var sortableSystemFields = []string{"id", "ctime", "utime"}
+
+type Service interface {
+	// CreateView 创建视图，创建人为当前登录用户
+	CreateView(ctx context.Context, view domain.SavedView) (int64, error)
+
+	// UpdateView 修改视图名称、可见范围、筛选条件、展示字段及排序，只允许创建人修改
+	UpdateView(ctx context.Context, view domain.SavedView) (int64, error)
+
+	// DeleteView 删除视图，只允许创建人删除
+	DeleteView(ctx context.Context, id int64) (int64, error)
+
+	// FindView 查询当前用户可见的视图，列表及导出接口通过视图 ID 复用保存的查询条件
+	FindView(ctx context.Context, id int64) (domain.SavedView, error)
+
+	// ListViews 查询模型下当前用户可见的视图，并标记当前用户的默认视图
+	ListViews(ctx context.Context, modelUid string) ([]domain.SavedView, error)
+
+	// SetDefaultView 设置当前用户在模型下的默认视图，id 为 0 时取消默认视图
+	SetDefaultView(ctx context.Context, modelUid string, id int64) error
+
+	// FindDefaultView 查询当前用户在模型下的默认视图，未设置或视图已不可见时返回 false
+	FindDefaultView(ctx context.Context, modelUid string) (domain.SavedView, bool, error)
+}
+
+type service struct {
+	repo    repository.SavedViewRepository
+	attrSvc attribute.Service
+}
+
+func NewService(repo repository.SavedViewRepository, attrSvc attribute.Service) Service {
+	return &service{
+		repo:    repo,
+		attrSvc: attrSvc,
+	}
+}
+
+func (s *service) CreateView(ctx context.Context, view domain.SavedView) (int64, error) {
+	if view.Scope == "" {
+		view.Scope = domain.ViewScopePrivate
+	}
+	if err := s.validate(ctx, view); err != nil {
+		return 0, err
+	}
+
+	view.OwnerID = currentUserID(ctx)
+	return s.repo.CreateView(ctx, view)
+}
+
+func (s *service) UpdateView(ctx context.Context, view domain.SavedView) (int64, error) {
+	old, err := s.repo.FindViewById(ctx, view.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	uid := currentUserID(ctx)
+	if !old.EditableBy(uid) {
+		return 0, errs.ViewPermissionDenied
+	}
+
+	// 视图所属模型不允许变更
+	view.ModelUID, view.OwnerID = old.ModelUID, uid
+	if view.Scope == "" {
+		view.Scope = old.Scope
+	}
+	if err = s.validate(ctx, view); err != nil {
+		return 0, err
+	}
+
+	return s.repo.UpdateView(ctx, view)
+}
+
+func (s *service) DeleteView(ctx context.Context, id int64) (int64, error) {
+	view, err := s.repo.FindViewById(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	uid := currentUserID(ctx)
+	if !view.EditableBy(uid) {
+		return 0, errs.ViewPermissionDenied
+	}
+
+	return s.repo.DeleteView(ctx, id, uid)
+}
+
+func (s *service) FindView(ctx context.Context, id int64) (domain.SavedView, error) {
+	view, err := s.repo.FindViewById(ctx, id)
+	if err != nil {
+		return domain.SavedView{}, err
+	}
+
+	if !view.VisibleTo(currentUserID(ctx)) {
+		return domain.SavedView{}, errs.ViewPermissionDenied
+	}
+	return view, nil
+}
+
+func (s *service) ListViews(ctx context.Context, modelUid string) ([]domain.SavedView, error) {
+	uid := currentUserID(ctx)
+	views, err := s.repo.ListVisibleViews(ctx, modelUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultID, err := s.repo.FindDefaultViewId(ctx, uid, modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(views, func(src domain.SavedView, _ int) domain.SavedView {
+		src.IsDefault = src.ID == defaultID
+		return src
+	}), nil
+}
+
+func (s *service) SetDefaultView(ctx context.Context, modelUid string, id int64) error {
+	uid := currentUserID(ctx)
+	if id == 0 {
+		_, err := s.repo.ClearDefaultView(ctx, uid, modelUid)
+		return err
+	}
+
+	view, err := s.FindView(ctx, id)
+	if err != nil {
+		return err
+	}
+	if view.ModelUID != modelUid {
+		return errs.ValidationError.WithMsg("视图不属于该模型")
+	}
+
+	return s.repo.SetDefaultView(ctx, uid, modelUid, id)
+}
+
+func (s *service) FindDefaultView(ctx context.Context, modelUid string) (domain.SavedView, bool, error) {
+	uid := currentUserID(ctx)
+	id, err := s.repo.FindDefaultViewId(ctx, uid, modelUid)
+	if err != nil || id == 0 {
+		return domain.SavedView{}, false, err
+	}
+
+	view, err := s.repo.FindViewById(ctx, id)
+	if err != nil {
+		return domain.SavedView{}, false, err
+	}
+
+	// NOTE: 共享视图被创建人改为私有后，其他用户的默认设置随之失效
+	if !view.VisibleTo(uid) {
+		return domain.SavedView{}, false, nil
+	}
+
+	view.IsDefault = true
+	return view, true, nil
+}
+
+// validate 校验视图名称、可见范围，以及引用的字段是否属于模型
+// NOTE: 安全字段为密文存储，不支持展示、筛选及排序
+func (s *service) validate(ctx context.Context, view domain.SavedView) error {
+	if strings.TrimSpace(view.Name) == "" {
+		return errs.ValidationError.WithMsg("视图名称不能为空")
+	}
+	if view.ModelUID == "" {
+		return errs.ValidationError.WithMsg("模型唯一标识不能为空")
+	}
+	if !view.Scope.Valid() {
+		return errs.ValidationError.WithMsg(fmt.Sprintf("不支持的视图可见范围: %s", view.Scope))
+	}
+
+	fields, err := s.attrSvc.SearchAttributeFieldsByModelUid(ctx, view.ModelUID)
+	if err != nil {
+		return err
+	}
+
+	allowed := lo.Union(fields, sortableSystemFields)
+	if unknown, _ := lo.Difference(lo.Uniq(view.ReferencedFields()), allowed); len(unknown) > 0 {
+		return errs.ValidationError.WithMsg(fmt.Sprintf("视图引用了不存在或不支持的字段: %s",
+			strings.Join(unknown, ",")))
+	}
+	return nil
+}
+
+func currentUserID(ctx context.Context) int64 {
+	return ctxutil.GetUserID(ctx).Int64()
+}
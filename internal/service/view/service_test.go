@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	attributemocks "github.com/Duke1616/ecmdb/internal/mocks/attributemocks"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_CreateView(t *testing.T) {
+	testCases := []struct {
+		name    string
+		view    domain.SavedView
+		wantErr string
+	}{
+		{
+			name: "默认私有视图",
+			view: domain.SavedView{ModelUID: "host", Name: "生产主机", Fields: []string{"ip"},
+				Sorts: []domain.ResourceSort{{Field: "ctime", Desc: true}}},
+		},
+		{
+			name:    "名称为空",
+			view:    domain.SavedView{ModelUID: "host", Name: " "},
+			wantErr: "视图名称不能为空",
+		},
+		{
+			name:    "不支持的可见范围",
+			view:    domain.SavedView{ModelUID: "host", Name: "v", Scope: "public"},
+			wantErr: "不支持的视图可见范围",
+		},
+		{
+			name: "引用安全字段或不存在的字段",
+			view: domain.SavedView{ModelUID: "host", Name: "v", FilterGroups: []domain.FilterGroup{
+				{Filters: []domain.FilterCondition{{FieldUID: "password", Operator: domain.OperatorEq}}},
+			}},
+			wantErr: "password",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			attrSvc := attributemocks.NewMockService(ctrl)
+			attrSvc.EXPECT().SearchAttributeFieldsByModelUid(gomock.Any(), "host").
+				Return([]string{"name", "ip"}, nil).AnyTimes()
+
+			repo := newStubViewRepository()
+			svc := NewService(repo, attrSvc)
+
+			id, err := svc.CreateView(ctxutil.WithUserID(context.Background(), 1), tc.view)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Empty(t, repo.views)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), repo.views[id].OwnerID)
+			assert.Equal(t, domain.ViewScopePrivate, repo.views[id].Scope)
+		})
+	}
+}
+
+func TestService_Visibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	attrSvc := attributemocks.NewMockService(ctrl)
+	attrSvc.EXPECT().SearchAttributeFieldsByModelUid(gomock.Any(), gomock.Any()).
+		Return([]string{"name"}, nil).AnyTimes()
+
+	repo := newStubViewRepository()
+	svc := NewService(repo, attrSvc)
+
+	owner := ctxutil.WithUserID(context.Background(), 1)
+	other := ctxutil.WithUserID(context.Background(), 2)
+
+	private, err := svc.CreateView(owner, domain.SavedView{ModelUID: "host", Name: "私有"})
+	require.NoError(t, err)
+	shared, err := svc.CreateView(owner, domain.SavedView{ModelUID: "host", Name: "共享",
+		Scope: domain.ViewScopeShared})
+	require.NoError(t, err)
+
+	// 私有视图对其他用户不可见，共享视图只读
+	_, err = svc.FindView(other, private)
+	assert.ErrorIs(t, err, errs.ViewPermissionDenied)
+	_, err = svc.FindView(other, shared)
+	assert.NoError(t, err)
+	_, err = svc.UpdateView(other, domain.SavedView{ID: shared, Name: "改名"})
+	assert.ErrorIs(t, err, errs.ViewPermissionDenied)
+	_, err = svc.DeleteView(other, shared)
+	assert.ErrorIs(t, err, errs.ViewPermissionDenied)
+
+	// 默认视图按用户隔离
+	require.NoError(t, svc.SetDefaultView(other, "host", shared))
+	assert.ErrorIs(t, svc.SetDefaultView(other, "host", private), errs.ViewPermissionDenied)
+
+	views, err := svc.ListViews(other, "host")
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	assert.True(t, views[0].IsDefault)
+
+	views, err = svc.ListViews(owner, "host")
+	require.NoError(t, err)
+	assert.Len(t, views, 2)
+	assert.False(t, views[0].IsDefault || views[1].IsDefault)
+
+	// 共享视图改为私有后，其他用户的默认视图随之失效
+	_, err = svc.UpdateView(owner, domain.SavedView{ID: shared, Name: "共享", Scope: domain.ViewScopePrivate})
+	require.NoError(t, err)
+	_, ok, err := svc.FindDefaultView(other, "host")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+type stubViewRepository struct {
+	nextID   int64
+	views    map[int64]domain.SavedView
+	defaults map[string]int64
+}
+
+func newStubViewRepository() *stubViewRepository {
+	return &stubViewRepository{
+		views:    make(map[int64]domain.SavedView),
+		defaults: make(map[string]int64),
+	}
+}
+
+func (r *stubViewRepository) CreateView(_ context.Context, view domain.SavedView) (int64, error) {
+	r.nextID++
+	view.ID = r.nextID
+	r.views[view.ID] = view
+	return view.ID, nil
+}
+
+func (r *stubViewRepository) UpdateView(_ context.Context, view domain.SavedView) (int64, error) {
+	old, ok := r.views[view.ID]
+	if !ok || old.OwnerID != view.OwnerID {
+		return 0, nil
+	}
+	view.Ctime = old.Ctime
+	r.views[view.ID] = view
+	return 1, nil
+}
+
+func (r *stubViewRepository) DeleteView(_ context.Context, id int64, ownerID int64) (int64, error) {
+	if view, ok := r.views[id]; !ok || view.OwnerID != ownerID {
+		return 0, nil
+	}
+	delete(r.views, id)
+	return 1, nil
+}
+
+func (r *stubViewRepository) FindViewById(_ context.Context, id int64) (domain.SavedView, error) {
+	view, ok := r.views[id]
+	if !ok {
+		return domain.SavedView{}, errs.ErrNotFound
+	}
+	return view, nil
+}
+
+func (r *stubViewRepository) ListVisibleViews(_ context.Context, modelUid string,
+	userID int64) ([]domain.SavedView, error) {
+	var views []domain.SavedView
+	for id := int64(1); id <= r.nextID; id++ {
+		view, ok := r.views[id]
+		if ok && view.ModelUID == modelUid && view.VisibleTo(userID) {
+			views = append(views, view)
+		}
+	}
+	return views, nil
+}
+
+func (r *stubViewRepository) SetDefaultView(_ context.Context, userID int64, modelUid string, viewID int64) error {
+	r.defaults[defaultKey(userID, modelUid)] = viewID
+	return nil
+}
+
+func (r *stubViewRepository) ClearDefaultView(_ context.Context, userID int64, modelUid string) (int64, error) {
+	delete(r.defaults, defaultKey(userID, modelUid))
+	return 1, nil
+}
+
+func (r *stubViewRepository) FindDefaultViewId(_ context.Context, userID int64, modelUid string) (int64, error) {
+	return r.defaults[defaultKey(userID, modelUid)], nil
+}
+
+func defaultKey(userID int64, modelUid string) string {
+	return fmt.Sprintf("%s/%d", modelUid, userID)
+}
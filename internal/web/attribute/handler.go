@@ -282,15 +282,16 @@ func (h *Handler) toAttrGroupVo(src domain.AttributeGroup) AttributeGroup {
 
 func (h *Handler) toDomainUpdate(req UpdateAttributeReq) domain.Attribute {
 	return domain.Attribute{
-		ID:        req.Id,
-		FieldName: req.FieldName,
-		FieldType: req.FieldType,
-		Required:  req.Required,
-		Link:      req.Link,
-		Secure:    req.Secure,
-		Option:    req.Option,
-		Index:     req.Index,
-		SortKey:   req.SortKey,
+		ID:          req.Id,
+		FieldName:   req.FieldName,
+		FieldType:   req.FieldType,
+		Description: req.Description,
+		Required:    req.Required,
+		Link:        req.Link,
+		Secure:      req.Secure,
+		Option:      req.Option,
+		Index:       req.Index,
+		SortKey:     req.SortKey,
 	}
 }
 
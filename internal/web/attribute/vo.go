@@ -5,17 +5,18 @@ import (
 )
 
 type CreateAttributeReq struct {
-	GroupId   int64       `json:"group_id" binding:"required,gt=0"`
-	FieldUid  string      `json:"field_uid" binding:"required"`
-	ModelUid  string      `json:"model_uid" binding:"required"`
-	FieldName string      `json:"field_name" binding:"required"`
-	FieldType string      `json:"field_type" binding:"required"`
-	Secure    bool        `json:"secure"`
-	Required  bool        `json:"required"`
-	Link      bool        `json:"link"`
-	Option    interface{} `json:"option"`
-	Index     int64       `json:"index"`
-	SortKey   int64       `json:"sort_key"`
+	GroupId     int64       `json:"group_id" binding:"required,gt=0"`
+	FieldUid    string      `json:"field_uid" binding:"required"`
+	ModelUid    string      `json:"model_uid" binding:"required"`
+	FieldName   string      `json:"field_name" binding:"required"`
+	FieldType   string      `json:"field_type" binding:"required"`
+	Description string      `json:"description"`
+	Secure      bool        `json:"secure"`
+	Required    bool        `json:"required"`
+	Link        bool        `json:"link"`
+	Option      interface{} `json:"option"`
+	Index       int64       `json:"index"`
+	SortKey     int64       `json:"sort_key"`
 }
 
 type CreateAttributeGroup struct {
@@ -48,15 +49,16 @@ type DeleteAttributeReq struct {
 }
 
 type UpdateAttributeReq struct {
-	Id        int64       `json:"id"`
-	FieldName string      `json:"field_name"`
-	FieldType string      `json:"field_type"`
-	Secure    bool        `json:"secure"`
-	Required  bool        `json:"required"`
-	Link      bool        `json:"link"`
-	Option    interface{} `json:"option"`
-	Index     int64       `json:"index"`
-	SortKey   int64       `json:"sort_key"`
+	Id          int64       `json:"id"`
+	FieldName   string      `json:"field_name"`
+	FieldType   string      `json:"field_type"`
+	Description string      `json:"description"`
+	Secure      bool        `json:"secure"`
+	Required    bool        `json:"required"`
+	Link        bool        `json:"link"`
+	Option      interface{} `json:"option"`
+	Index       int64       `json:"index"`
+	SortKey     int64       `json:"sort_key"`
 }
 
 // SortAttributeReq 拖拽排序请求
@@ -73,20 +75,21 @@ type SortAttributeGroupReq struct {
 }
 
 type Attribute struct {
-	ID        int64       `json:"id"`
-	GroupId   int64       `json:"group_id"`
-	ModelUid  string      `json:"model_uid"`
-	FieldUid  string      `json:"field_uid"`
-	FieldName string      `json:"field_name"`
-	FieldType string      `json:"field_type"`
-	Required  bool        `json:"required"`
-	Secure    bool        `json:"secure"`
-	Link      bool        `json:"link"`
-	Display   bool        `json:"display"`
-	Option    interface{} `json:"option"`
-	Index     int64       `json:"index"`
-	SortKey   int64       `json:"sort_key"`
-	Builtin   bool        `json:"builtin"`
+	ID          int64       `json:"id"`
+	GroupId     int64       `json:"group_id"`
+	ModelUid    string      `json:"model_uid"`
+	FieldUid    string      `json:"field_uid"`
+	FieldName   string      `json:"field_name"`
+	FieldType   string      `json:"field_type"`
+	Description string      `json:"description"`
+	Required    bool        `json:"required"`
+	Secure      bool        `json:"secure"`
+	Link        bool        `json:"link"`
+	Display     bool        `json:"display"`
+	Option      interface{} `json:"option"`
+	Index       int64       `json:"index"`
+	SortKey     int64       `json:"sort_key"`
+	Builtin     bool        `json:"builtin"`
 }
 
 type AttributeGroup struct {
@@ -141,35 +144,37 @@ type RetrieveAttributeFieldList struct {
 
 func toDomain(req CreateAttributeReq) domain.Attribute {
 	return domain.Attribute{
-		GroupId:   req.GroupId,
-		FieldUid:  req.FieldUid,
-		ModelUid:  req.ModelUid,
-		FieldName: req.FieldName,
-		FieldType: req.FieldType,
-		Link:      req.Link,
-		Required:  req.Required,
-		Secure:    req.Secure,
-		Option:    req.Option,
-		Index:     req.Index,
-		SortKey:   req.SortKey,
+		GroupId:     req.GroupId,
+		FieldUid:    req.FieldUid,
+		ModelUid:    req.ModelUid,
+		FieldName:   req.FieldName,
+		FieldType:   req.FieldType,
+		Description: req.Description,
+		Link:        req.Link,
+		Required:    req.Required,
+		Secure:      req.Secure,
+		Option:      req.Option,
+		Index:       req.Index,
+		SortKey:     req.SortKey,
 	}
 }
 
 func toAttributeVo(attr domain.Attribute) Attribute {
 	return Attribute{
-		ID:        attr.ID,
-		GroupId:   attr.GroupId,
-		FieldUid:  attr.FieldUid,
-		ModelUid:  attr.ModelUid,
-		FieldName: attr.FieldName,
-		FieldType: attr.FieldType,
-		Required:  attr.Required,
-		Link:      attr.Link,
-		Display:   attr.Display,
-		Option:    attr.Option,
-		Secure:    attr.Secure,
-		Index:     attr.Index,
-		SortKey:   attr.SortKey,
-		Builtin:   attr.Builtin,
+		ID:          attr.ID,
+		GroupId:     attr.GroupId,
+		FieldUid:    attr.FieldUid,
+		ModelUid:    attr.ModelUid,
+		FieldName:   attr.FieldName,
+		FieldType:   attr.FieldType,
+		Description: attr.Description,
+		Required:    attr.Required,
+		Link:        attr.Link,
+		Display:     attr.Display,
+		Option:      attr.Option,
+		Secure:      attr.Secure,
+		Index:       attr.Index,
+		SortKey:     attr.SortKey,
+		Builtin:     attr.Builtin,
 	}
 }
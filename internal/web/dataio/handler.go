@@ -1,10 +1,14 @@
 package web
 
 import (
+	"io"
+	"strings"
+
 	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
 	"github.com/Duke1616/ecmdb/internal/service/dataio"
+	viewservice "github.com/Duke1616/ecmdb/internal/service/view"
 	"github.com/Duke1616/ecmdb/pkg/ginx"
-	"github.com/Duke1616/ecmdb/pkg/storage"
 	"github.com/Duke1616/eiam/pkg/web/capability"
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-gonic/gin"
@@ -12,14 +16,14 @@ import (
 
 type Handler struct {
 	svc     service.IDataIOService
-	storage *storage.S3Storage
+	viewSvc viewservice.Service
 	capability.IRegistry
 }
 
-func NewHandler(svc service.IDataIOService, storage *storage.S3Storage) *Handler {
+func NewHandler(svc service.IDataIOService, viewSvc viewservice.Service) *Handler {
 	return &Handler{
 		svc:       svc,
-		storage:   storage,
+		viewSvc:   viewSvc,
 		IRegistry: capability.NewRegistry("cmdb", "dataio", "资产仓库/导入导出"),
 	}
 }
@@ -30,64 +34,381 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	g.GET("/template/export/:model_uid", h.Capability("模板导出", "export_template").
 		Handle(ginx.Wrap(h.ExportTemplate)),
 	)
-	// 导入数据 (S3 模式)
+	// 导入数据 (S3 模式)，提交异步导入任务
 	g.POST("/import", h.Capability("数据导入", "import").
 		Handle(ginx.WrapBody[ImportReq](h.Import)),
 	)
-	// 导出数据
+	// 确认预演任务，正式导入
+	g.POST("/import/job/commit", h.Capability("确认导入", "import_commit").
+		Needs("cmdb:dataio:import").
+		Handle(ginx.WrapBody[ImportJobReq](h.CommitImportJob)),
+	)
+	// 查询导入任务进度
+	g.POST("/import/job/detail", h.Capability("导入任务详情", "import_job_get").
+		Handle(ginx.WrapBody[ImportJobReq](h.DetailImportJob)),
+	)
+	// 查询导入任务列表
+	g.POST("/import/job/list", h.Capability("导入任务列表", "import_job_view").
+		Handle(ginx.WrapBody[ListImportJobsReq](h.ListImportJobs)),
+	)
+	// 获取导入错误报告下载链接
+	g.POST("/import/job/report", h.Capability("下载导入错误报告", "import_job_report").
+		Needs("cmdb:dataio:import_job_get").
+		Handle(ginx.WrapBody[ImportJobReq](h.ImportReport)),
+	)
+	// 识别导入文件的表头并给出列映射建议
+	g.POST("/import/mapping/preview", h.Capability("列映射预览", "import_mapping_preview").
+		Needs("cmdb:dataio:import").
+		Handle(ginx.WrapBody[PreviewMappingReq](h.PreviewImportMapping)),
+	)
+	// 保存列映射方案
+	g.POST("/import/mapping/create", h.Capability("创建列映射方案", "import_mapping_create").
+		Needs("cmdb:dataio:import").
+		Handle(ginx.WrapBody[CreateImportMappingReq](h.CreateImportMapping)),
+	)
+	// 修改列映射方案
+	g.POST("/import/mapping/update", h.Capability("修改列映射方案", "import_mapping_update").
+		Needs("cmdb:dataio:import").
+		Handle(ginx.WrapBody[UpdateImportMappingReq](h.UpdateImportMapping)),
+	)
+	// 删除列映射方案
+	g.POST("/import/mapping/delete", h.Capability("删除列映射方案", "import_mapping_delete").
+		Needs("cmdb:dataio:import").
+		Handle(ginx.WrapBody[ImportMappingReq](h.DeleteImportMapping)),
+	)
+	// 查询模型下的列映射方案
+	g.POST("/import/mapping/list", h.Capability("列映射方案列表", "import_mapping_view").
+		Needs("cmdb:dataio:import").
+		Handle(ginx.WrapBody[ListImportMappingsReq](h.ListImportMappings)),
+	)
+	// 导出数据，流式写入响应体
 	g.POST("/export", h.Capability("数据导出", "export").
-		Handle(ginx.WrapBody[ExportReq](h.Export)),
+		Handle(ginx.WrapFileStreamBody[ExportReq](h.Export, systemErrorResult)),
+	)
+	// 导出多模型工作簿，每个模型一个工作表
+	g.POST("/export/workbook", h.Capability("工作簿导出", "export_workbook").
+		Needs("cmdb:dataio:export").
+		Handle(ginx.WrapFileStreamBody[ExportWorkbookReq](h.ExportWorkbook, systemErrorResult)),
+	)
+	// 提交异步导出任务，适用于大数据量导出
+	g.POST("/export/job", h.Capability("提交导出任务", "export_job").
+		Needs("cmdb:dataio:export").
+		Handle(ginx.WrapBody[ExportReq](h.SubmitExport)),
+	)
+	// 查询导出任务进度
+	g.POST("/export/job/detail", h.Capability("导出任务详情", "export_job_get").
+		Handle(ginx.WrapBody[ExportJobReq](h.DetailExportJob)),
+	)
+	// 查询导出任务列表
+	g.POST("/export/job/list", h.Capability("导出任务列表", "export_job_view").
+		Handle(ginx.WrapBody[ListExportJobsReq](h.ListExportJobs)),
+	)
+	// 获取导出文件下载链接
+	g.POST("/export/job/download", h.Capability("下载导出文件", "export_job_download").
+		Needs("cmdb:dataio:export_job_get").
+		Handle(ginx.WrapBody[ExportJobReq](h.ExportFile)),
+	)
+	// 定时导出，由后台调度器按 cron 表达式导出到对象存储
+	g.POST("/export/schedule/create", h.Capability("创建定时导出", "export_schedule_create").
+		Needs("cmdb:dataio:export").
+		Handle(ginx.WrapBody[CreateScheduledExportReq](h.CreateScheduledExport)),
+	)
+	g.POST("/export/schedule/update", h.Capability("修改定时导出", "export_schedule_update").
+		Needs("cmdb:dataio:export").
+		Handle(ginx.WrapBody[UpdateScheduledExportReq](h.UpdateScheduledExport)),
+	)
+	g.POST("/export/schedule/delete", h.Capability("删除定时导出", "export_schedule_delete").
+		Handle(ginx.WrapBody[ScheduledExportReq](h.DeleteScheduledExport)),
+	)
+	g.POST("/export/schedule/list", h.Capability("定时导出列表", "export_schedule_view").
+		Handle(ginx.WrapBody[ListScheduledExportsReq](h.ListScheduledExports)),
+	)
+	// 查询定时导出的执行记录
+	g.POST("/export/schedule/run/list", h.Capability("定时导出执行记录", "export_schedule_run_view").
+		Needs("cmdb:dataio:export_schedule_view").
+		Handle(ginx.WrapBody[ListScheduledExportRunsReq](h.ListScheduledExportRuns)),
+	)
+	// 获取定时导出文件下载链接
+	g.POST("/export/schedule/run/download", h.Capability("下载定时导出文件", "export_schedule_download").
+		Needs("cmdb:dataio:export_schedule_run_view").
+		Handle(ginx.WrapBody[ScheduledExportFileReq](h.ScheduledExportFile)),
 	)
 }
 
 // Export 导出数据
-func (h *Handler) Export(ctx *gin.Context, req ExportReq) (ginx.Result, error) {
-	// 转换 FilterGroups
-	groups := slice.Map(req.FilterGroups, func(idx int, src ExportFilterGroup) domain.FilterGroup {
-		return domain.FilterGroup{
-			Filters: slice.Map(src.Filters, func(idx int, src ExportFilterCondition) domain.FilterCondition {
-				return domain.FilterCondition{
-					FieldUID: src.FieldUID,
-					Operator: domain.Operator(src.Operator),
-					Value:    src.Value,
-				}
+// NOTE: 以游标分批读取资产并直接写入响应体，参数错误在写入之前按统一 Result 返回
+func (h *Handler) Export(ctx *gin.Context, req ExportReq) (ginx.FileStream, error) {
+	params, err := h.exportParams(ctx, req)
+	if err != nil {
+		return ginx.FileStream{}, err
+	}
+
+	// NOTE: 格式错误由 Service 校验，此处仅用于补全默认格式
+	opts, _ := params.FormatOptions.Normalize()
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = req.ModelUID + "_export"
+	}
+	// 确保后缀
+	if !strings.HasSuffix(fileName, opts.Format.Ext()) {
+		fileName += opts.Format.Ext()
+	}
+
+	return ginx.FileStream{
+		Name:        fileName,
+		ContentType: opts.Format.ContentType(),
+		Write: func(w io.Writer) error {
+			return h.svc.Export(ctx.Request.Context(), params, w)
+		},
+	}, nil
+}
+
+// ExportWorkbook 导出多模型工作簿，文件可直接通过工作簿导入回导
+func (h *Handler) ExportWorkbook(ctx *gin.Context, req ExportWorkbookReq) (ginx.FileStream, error) {
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = "workbook_export"
+	}
+	if !strings.HasSuffix(fileName, domain.FileFormatExcel.Ext()) {
+		fileName += domain.FileFormatExcel.Ext()
+	}
+
+	return ginx.FileStream{
+		Name:        fileName,
+		ContentType: domain.FileFormatExcel.ContentType(),
+		Write: func(w io.Writer) error {
+			return h.svc.ExportWorkbook(ctx.Request.Context(), service.WorkbookExportParams{
+				GroupIDs:         req.GroupIDs,
+				ModelUIDs:        req.ModelUIDs,
+				IncludeRelations: req.IncludeRelations,
+				FileName:         fileName,
+			}, w)
+		},
+	}, nil
+}
+
+// SubmitExport 提交异步导出任务，完成后通过 ExportFile 获取下载链接
+func (h *Handler) SubmitExport(ctx *gin.Context, req ExportReq) (ginx.Result, error) {
+	params, err := h.exportParams(ctx, req)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	id, err := h.svc.SubmitExport(ctx.Request.Context(), params)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Msg:  "导出任务已提交",
+		Data: id,
+	}, nil
+}
+
+func (h *Handler) DetailExportJob(ctx *gin.Context, req ExportJobReq) (ginx.Result, error) {
+	job, err := h.svc.FindExportJob(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: h.toExportJobVO(job),
+	}, nil
+}
+
+func (h *Handler) ListExportJobs(ctx *gin.Context, req ListExportJobsReq) (ginx.Result, error) {
+	jobs, total, err := h.svc.ListExportJobs(ctx.Request.Context(), req.ModelUID, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: RetrieveExportJobs{
+			Jobs: slice.Map(jobs, func(idx int, src domain.ExportJob) ExportJob {
+				return h.toExportJobVO(src)
 			}),
-		}
-	})
+			Total: total,
+		},
+	}, nil
+}
 
-	params := service.ExportParams{
-		ModelUID:     req.ModelUID,
-		Scope:        req.Scope.String(),
-		ResourceIDs:  req.ResourceIDs,
-		FilterGroups: groups,
-		Fields:       req.Fields,
-		FileName:     req.FileName,
+// ExportFile 获取导出文件的预签名下载链接
+func (h *Handler) ExportFile(ctx *gin.Context, req ExportJobReq) (ginx.Result, error) {
+	url, err := h.svc.ExportFileURL(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return systemErrorResult, err
 	}
 
-	// 调用 Service 导出数据
-	excelData, err := h.svc.Export(ctx.Request.Context(), params)
+	return ginx.Result{
+		Data: url,
+	}, nil
+}
+
+func (h *Handler) CreateScheduledExport(ctx *gin.Context, req CreateScheduledExportReq) (ginx.Result, error) {
+	id, err := h.svc.CreateScheduledExport(ctx.Request.Context(), req.toDomain())
 	if err != nil {
 		return systemErrorResult, err
 	}
 
-	fileName := req.FileName
-	if fileName == "" {
-		fileName = req.ModelUID + "_export.xlsx"
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
+func (h *Handler) UpdateScheduledExport(ctx *gin.Context, req UpdateScheduledExportReq) (ginx.Result, error) {
+	se := req.toDomain()
+	se.ID = req.ID
+	count, err := h.svc.UpdateScheduledExport(ctx.Request.Context(), se)
+	if err != nil {
+		return systemErrorResult, err
 	}
-	// 确保后缀
-	if len(fileName) < 5 || fileName[len(fileName)-5:] != ".xlsx" {
-		fileName += ".xlsx"
+
+	return ginx.Result{
+		Data: count,
+	}, nil
+}
+
+func (h *Handler) DeleteScheduledExport(ctx *gin.Context, req ScheduledExportReq) (ginx.Result, error) {
+	count, err := h.svc.DeleteScheduledExport(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return systemErrorResult, err
 	}
 
-	// 设置 HTTP 响应头
-	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	ctx.Header("Content-Disposition", "attachment; filename="+fileName)
-	ctx.Header("Content-Transfer-Encoding", "binary")
+	return ginx.Result{
+		Data: count,
+	}, nil
+}
 
-	// 直接写入 Excel 数据
-	ctx.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", excelData)
+func (h *Handler) ListScheduledExports(ctx *gin.Context, req ListScheduledExportsReq) (ginx.Result, error) {
+	ses, total, err := h.svc.ListScheduledExports(ctx.Request.Context(), req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
 
-	return ginx.Result{}, nil
+	return ginx.Result{
+		Data: RetrieveScheduledExports{
+			Schedules: slice.Map(ses, func(idx int, src domain.ScheduledExport) ScheduledExport {
+				return h.toScheduledExportVO(src)
+			}),
+			Total: total,
+		},
+	}, nil
+}
+
+func (h *Handler) ListScheduledExportRuns(ctx *gin.Context, req ListScheduledExportRunsReq) (ginx.Result, error) {
+	runs, total, err := h.svc.ListScheduledExportRuns(ctx.Request.Context(), req.ScheduleID, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: RetrieveScheduledExportRuns{
+			Runs: slice.Map(runs, func(idx int, src domain.ScheduledExportRun) ScheduledExportRun {
+				return ScheduledExportRun{
+					ID:         src.ID,
+					ScheduleID: src.ScheduleID,
+					Status:     string(src.Status),
+					Files: slice.Map(src.Files, func(idx int, src domain.ScheduledExportFile) ScheduledExportFile {
+						return ScheduledExportFile{
+							ModelUID: src.ModelUID,
+							HasFile:  src.FileKey != "",
+							Rows:     src.Rows,
+							Size:     src.Size,
+							Message:  src.Message,
+						}
+					}),
+					Message: src.Message,
+					Pruned:  src.Pruned,
+					Ctime:   src.Ctime,
+					Ftime:   src.Ftime,
+				}
+			}),
+			Total: total,
+		},
+	}, nil
+}
+
+// ScheduledExportFile 获取定时导出文件的预签名下载链接
+func (h *Handler) ScheduledExportFile(ctx *gin.Context, req ScheduledExportFileReq) (ginx.Result, error) {
+	url, err := h.svc.ScheduledExportFileURL(ctx.Request.Context(), req.RunID, req.ModelUID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: url,
+	}, nil
+}
+
+func (h *Handler) toScheduledExportVO(src domain.ScheduledExport) ScheduledExport {
+	return ScheduledExport{
+		ID: src.ID,
+		ScheduledExportConfig: ScheduledExportConfig{
+			Name:     src.Name,
+			CronExpr: src.CronExpr,
+			Models: slice.Map(src.Models, func(idx int, src domain.ScheduledExportModel) ScheduledExportModel {
+				return ScheduledExportModel{
+					ModelUID:     src.ModelUID,
+					FilterGroups: toExportFilterGroups(src.FilterGroups),
+					Fields:       src.Fields,
+				}
+			}),
+			RetentionDays: src.RetentionDays,
+			Enabled:       src.Enabled,
+			FileFormat: FileFormat{
+				Format:    string(src.Format),
+				Delimiter: src.Delimiter,
+				Encoding:  src.Encoding,
+			},
+		},
+		NextRunAt: src.NextRunAt,
+		LastRunAt: src.LastRunAt,
+		CreatorID: src.CreatorID,
+		Ctime:     src.Ctime,
+		Utime:     src.Utime,
+	}
+}
+
+// exportParams 将导出请求转换为 Service 参数，指定视图时合并视图配置
+func (h *Handler) exportParams(ctx *gin.Context, req ExportReq) (service.ExportParams, error) {
+	params := service.ExportParams{
+		ModelUID:     req.ModelUID,
+		Scope:        req.Scope.String(),
+		ResourceIDs:  req.ResourceIDs,
+		FilterGroups: toFilterGroups(req.FilterGroups),
+		Fields:       req.Fields,
+		Relations: slice.Map(req.Relations, func(idx int, src ExportRelation) service.RelatedFields {
+			return service.RelatedFields{
+				RelationName: src.RelationName,
+				Fields:       src.Fields,
+				Mode:         src.Mode,
+				Separator:    src.Separator,
+			}
+		}),
+		FileName:      req.FileName,
+		FormatOptions: req.toDomain(),
+	}
+
+	// 指定视图时，请求中未传递的筛选条件及导出字段沿用视图的配置
+	if req.ViewID > 0 {
+		view, err := h.viewSvc.FindView(ctx, req.ViewID)
+		if err != nil {
+			return service.ExportParams{}, err
+		}
+		if view.ModelUID != req.ModelUID {
+			return service.ExportParams{}, errs.ValidationError.WithMsg("视图不属于该模型")
+		}
+
+		if len(params.FilterGroups) == 0 {
+			params.FilterGroups = view.FilterGroups
+		}
+		if len(params.Fields) == 0 {
+			params.Fields = view.Fields
+		}
+		params.Sorts = view.Sorts
+	}
+
+	return params, nil
 }
 
 // ExportTemplate 导出空白导入模板
@@ -95,43 +416,284 @@ func (h *Handler) ExportTemplate(ctx *gin.Context) (ginx.Result, error) {
 	// 根据请求获取模型UID
 	modelUid := ctx.Param("model_uid")
 
-	// 调用 Service 生成 Excel 模板
-	excelData, err := h.svc.ExportTemplate(ctx.Request.Context(), modelUid)
+	// 文件格式及关联列通过查询参数指定，默认 Excel
+	var query ExportTemplateQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return systemErrorResult, err
+	}
+	opts, err := query.toDomain().Normalize()
+	if err != nil {
+		return systemErrorResult, errs.ValidationError.WithMsg(err.Error())
+	}
+
+	// 调用 Service 生成模板
+	data, err := h.svc.ExportTemplate(ctx.Request.Context(), service.TemplateParams{
+		ModelUID:      modelUid,
+		Relations:     query.Relations,
+		FormatOptions: opts,
+	})
 	if err != nil {
 		return systemErrorResult, err
 	}
 
-	// 设置 HTTP 响应头,直接返回 Excel 文件
-	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	ctx.Header("Content-Disposition", "attachment; filename="+modelUid+"_template.xlsx")
+	// 设置 HTTP 响应头,直接返回模板文件
+	ctx.Header("Content-Type", opts.Format.ContentType())
+	ctx.Header("Content-Disposition", "attachment; filename="+modelUid+"_template"+opts.Format.Ext())
 	ctx.Header("Content-Transfer-Encoding", "binary")
 
-	// 直接写入 Excel 数据
-	ctx.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", excelData)
+	// 直接写入文件数据
+	ctx.Data(200, opts.Format.ContentType(), data)
 
 	// NOTE: 返回空 Result,因为已经通过 ctx.Data 直接发送了响应
 	return ginx.Result{}, nil
 }
 
-// Import 导入数据
-// NOTE: 前端先通过 GenerateUploadURL 上传文件到 S3,然后调用此接口传入 file_key 进行导入
+// Import 提交异步导入任务
+// NOTE: 前端先通过 GenerateUploadURL 上传文件到 S3,然后调用此接口传入 file_key,再根据返回的任务 ID 轮询进度
 func (h *Handler) Import(ctx *gin.Context, req ImportReq) (ginx.Result, error) {
-	// 1. 从 S3 下载文件
-	fileData, err := h.storage.GetFile(ctx.Request.Context(), "ecmdb", req.FileKey)
+	id, err := h.svc.SubmitImport(ctx.Request.Context(), service.ImportParams{
+		ModelUID:      req.ModelUID,
+		FileKey:       req.FileKey,
+		FileName:      req.FileName,
+		FormatOptions: req.toDomain(),
+		Mode:          domain.ImportMode(req.Mode),
+		DryRun:        req.DryRun,
+		Workbook:      req.Workbook,
+		MappingID:     req.MappingID,
+		Mapping:       h.toImportMapping(req.Mapping),
+	})
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Msg:  "导入任务已提交",
+		Data: id,
+	}, nil
+}
+
+// CommitImportJob 确认预演结果，以相同的文件及模式提交正式导入任务
+func (h *Handler) CommitImportJob(ctx *gin.Context, req ImportJobReq) (ginx.Result, error) {
+	id, err := h.svc.CommitImportJob(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Msg:  "导入任务已提交",
+		Data: id,
+	}, nil
+}
+
+func (h *Handler) DetailImportJob(ctx *gin.Context, req ImportJobReq) (ginx.Result, error) {
+	job, err := h.svc.FindImportJob(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: h.toImportJobVO(job),
+	}, nil
+}
+
+func (h *Handler) ListImportJobs(ctx *gin.Context, req ListImportJobsReq) (ginx.Result, error) {
+	jobs, total, err := h.svc.ListImportJobs(ctx.Request.Context(), req.ModelUID, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: RetrieveImportJobs{
+			Jobs: slice.Map(jobs, func(idx int, src domain.ImportJob) ImportJob {
+				return h.toImportJobVO(src)
+			}),
+			Total: total,
+		},
+	}, nil
+}
+
+// ImportReport 获取标注错误单元格的 Excel 报告下载链接
+func (h *Handler) ImportReport(ctx *gin.Context, req ImportJobReq) (ginx.Result, error) {
+	url, err := h.svc.ImportReportURL(ctx.Request.Context(), req.ID)
 	if err != nil {
 		return systemErrorResult, err
 	}
 
-	// 2. 调用 Service 导入数据
-	importedCount, err := h.svc.Import(ctx.Request.Context(), req.ModelUID, fileData)
+	return ginx.Result{
+		Data: url,
+	}, nil
+}
+
+// PreviewImportMapping 识别已上传文件的表头，返回前几行数据及列映射建议
+func (h *Handler) PreviewImportMapping(ctx *gin.Context, req PreviewMappingReq) (ginx.Result, error) {
+	preview, err := h.svc.PreviewImportMapping(ctx.Request.Context(), service.MappingPreviewParams{
+		ModelUID:      req.ModelUID,
+		FileKey:       req.FileKey,
+		FormatOptions: req.toDomain(),
+		HeaderRow:     req.HeaderRow,
+	})
 	if err != nil {
 		return systemErrorResult, err
 	}
 
 	return ginx.Result{
-		Msg: "导入成功",
-		Data: map[string]interface{}{
-			"imported_count": importedCount,
+		Data: MappingPreview{
+			HeaderRow: preview.HeaderRow,
+			Headers:   preview.Headers,
+			Samples:   preview.Samples,
+			Suggestions: slice.Map(preview.Suggestions, func(idx int, src domain.ColumnSuggestion) ColumnSuggestion {
+				return ColumnSuggestion{
+					Source:    src.Source,
+					FieldUID:  src.FieldUid,
+					FieldName: src.FieldName,
+					Score:     src.Score,
+				}
+			}),
 		},
 	}, nil
 }
+
+func (h *Handler) CreateImportMapping(ctx *gin.Context, req CreateImportMappingReq) (ginx.Result, error) {
+	mapping := req.ImportMappingConfig.toDomain()
+	mapping.ModelUID, mapping.Name = req.ModelUID, req.Name
+	id, err := h.svc.CreateImportMapping(ctx.Request.Context(), mapping)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
+func (h *Handler) UpdateImportMapping(ctx *gin.Context, req UpdateImportMappingReq) (ginx.Result, error) {
+	mapping := req.ImportMappingConfig.toDomain()
+	mapping.ID, mapping.Name = req.ID, req.Name
+	count, err := h.svc.UpdateImportMapping(ctx.Request.Context(), mapping)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: count,
+	}, nil
+}
+
+func (h *Handler) DeleteImportMapping(ctx *gin.Context, req ImportMappingReq) (ginx.Result, error) {
+	count, err := h.svc.DeleteImportMapping(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: count,
+	}, nil
+}
+
+func (h *Handler) ListImportMappings(ctx *gin.Context, req ListImportMappingsReq) (ginx.Result, error) {
+	mappings, err := h.svc.ListImportMappings(ctx.Request.Context(), req.ModelUID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: slice.Map(mappings, func(idx int, src domain.ImportMapping) ImportMapping {
+			return h.toImportMappingVO(src)
+		}),
+	}, nil
+}
+
+func (h *Handler) toImportMapping(req *ImportMappingConfig) *domain.ImportMapping {
+	if req == nil {
+		return nil
+	}
+	mapping := req.toDomain()
+	return &mapping
+}
+
+func (h *Handler) toImportMappingVO(src domain.ImportMapping) ImportMapping {
+	return ImportMapping{
+		ID:       src.ID,
+		ModelUID: src.ModelUID,
+		Name:     src.Name,
+		ImportMappingConfig: ImportMappingConfig{
+			HeaderRow: src.HeaderRow,
+			Columns: slice.Map(src.Columns, func(idx int, src domain.ColumnMapping) ColumnMapping {
+				return ColumnMapping{
+					Source:   src.Source,
+					FieldUID: src.FieldUid,
+					Transforms: slice.Map(src.Transforms, func(idx int, src domain.ColumnTransform) ColumnTransform {
+						return ColumnTransform{
+							Type:      string(src.Type),
+							Separator: src.Separator,
+							Index:     src.Index,
+							Pattern:   src.Pattern,
+							Mapping:   src.Mapping,
+							Layout:    src.Layout,
+							Output:    src.Output,
+						}
+					}),
+				}
+			}),
+		},
+		CreatorID: src.CreatorID,
+		Ctime:     src.Ctime,
+		Utime:     src.Utime,
+	}
+}
+
+func (h *Handler) toExportJobVO(src domain.ExportJob) ExportJob {
+	return ExportJob{
+		ID:        src.ID,
+		ModelUID:  src.ModelUID,
+		FileName:  src.FileName,
+		Format:    string(src.Format),
+		Status:    string(src.Status),
+		Processed: src.Processed,
+		Rows:      src.Rows,
+		Size:      src.Size,
+		Message:   src.Message,
+		Ctime:     src.Ctime,
+		Ftime:     src.Ftime,
+	}
+}
+
+func (h *Handler) toImportJobVO(src domain.ImportJob) ImportJob {
+	return ImportJob{
+		ID:        src.ID,
+		ModelUID:  src.ModelUID,
+		FileName:  src.FileName,
+		Workbook:  src.Workbook,
+		Mode:      string(src.Mode),
+		DryRun:    src.DryRun,
+		Status:    string(src.Status),
+		Total:     src.Total,
+		Processed: src.Processed,
+		Inserted:  src.Inserted,
+		Updated:   src.Updated,
+		Failed:    src.Failed,
+		Sheets: slice.Map(src.Sheets, func(idx int, src domain.ImportSheetResult) ImportSheetResult {
+			return ImportSheetResult{
+				Sheet:    src.Sheet,
+				ModelUID: src.ModelUID,
+				Total:    src.Total,
+				Inserted: src.Inserted,
+				Updated:  src.Updated,
+				Failed:   src.Failed,
+			}
+		}),
+		Errors: slice.Map(src.Errors, func(idx int, src domain.ImportRowError) ImportRowError {
+			return ImportRowError{
+				Sheet:    src.Sheet,
+				Row:      src.Row,
+				Cell:     src.Cell,
+				FieldUID: src.FieldUid,
+				Message:  src.Message,
+			}
+		}),
+		HasReport: src.ReportKey != "",
+		Message:   src.Message,
+		Ctime:     src.Ctime,
+		Ftime:     src.Ftime,
+	}
+}
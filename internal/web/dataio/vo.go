@@ -1,5 +1,10 @@
 package web
 
+import (
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/ecodeclub/ekit/slice"
+)
+
 // GenerateUploadURLReq 生成上传 URL 请求
 type GenerateUploadURLReq struct {
 	FileName string `json:"file_name" binding:"required"`
@@ -10,10 +15,238 @@ type ExportTemplateReq struct {
 	ModelUID string `json:"model_uid" binding:"required"`
 }
 
-// ImportReq 导入数据请求
+// ExportTemplateQuery 导出模板查询参数
+type ExportTemplateQuery struct {
+	FileFormat
+	Relations []string `form:"relations"` // 追加的关联资产名称列，如 ?relations=host_belong_idc
+}
+
+// ImportReq 导入数据请求，提交后返回异步任务 ID
+// FileFormat 导入导出文件格式，默认 xlsx
+type FileFormat struct {
+	Format    string `json:"format" form:"format"`       // xlsx / csv / ndjson / yaml
+	Delimiter string `json:"delimiter" form:"delimiter"` // CSV 分隔符，默认逗号
+	Encoding  string `json:"encoding" form:"encoding"`   // CSV 编码：utf-8 / utf-8-bom / gbk，默认 utf-8
+}
+
+func (f FileFormat) toDomain() domain.FormatOptions {
+	return domain.FormatOptions{
+		Format:    domain.FileFormat(f.Format),
+		Delimiter: f.Delimiter,
+		Encoding:  f.Encoding,
+	}
+}
+
 type ImportReq struct {
-	ModelUID string `json:"model_uid" binding:"required"` // 模型 UID
-	FileKey  string `json:"file_key" binding:"required"`  // S3 文件 key
+	ModelUID string `json:"model_uid" binding:"required_without=Workbook"` // 模型 UID，工作簿导入时忽略
+	FileKey  string `json:"file_key" binding:"required"`                   // S3 文件 key
+	FileName string `json:"file_name"`                                     // 原始文件名 (可选)
+	FileFormat
+	Mode   string `json:"mode"`    // create / update / upsert，默认 upsert
+	DryRun bool   `json:"dry_run"` // 预演：只校验并统计新增、修改及错误行数
+	// Workbook 多模型工作簿导入，按工作表索引或工作表名称识别模型，资产关联工作表最后导入
+	Workbook bool `json:"workbook"`
+	// MappingID 使用已保存的列映射方案；Mapping 为临时映射，不保存，两者均为空时按导入模板解析
+	MappingID int64                `json:"mapping_id"`
+	Mapping   *ImportMappingConfig `json:"mapping"`
+}
+
+// ColumnTransform 列转换规则
+type ColumnTransform struct {
+	Type      string            `json:"type" binding:"required"` // trim / split / regex / value_map / date
+	Separator string            `json:"separator"`               // split 分隔符
+	Index     int               `json:"index"`                   // split 取值段，从 0 开始，负数从末尾计数
+	Pattern   string            `json:"pattern"`                 // regex 正则表达式，有捕获组时取第一个捕获组
+	Mapping   map[string]string `json:"mapping"`                 // value_map 原始值 → 导入值
+	Layout    string            `json:"layout"`                  // date 输入格式，如 YYYY/MM/DD
+	Output    string            `json:"output"`                  // date 输出格式，默认 YYYY-MM-DD
+}
+
+// ColumnMapping 文件列到模型字段的映射
+type ColumnMapping struct {
+	Source     string            `json:"source"` // 表头文本，NDJSON 及 YAML 为对象的键
+	FieldUID   string            `json:"field_uid"`
+	Transforms []ColumnTransform `json:"transforms"` // 按顺序执行
+}
+
+// ImportMappingConfig 列映射配置
+type ImportMappingConfig struct {
+	HeaderRow int             `json:"header_row"` // 表头所在行，默认为 1，仅 Excel 及 CSV 生效
+	Columns   []ColumnMapping `json:"columns"`
+}
+
+func (c ImportMappingConfig) toDomain() domain.ImportMapping {
+	return domain.ImportMapping{
+		HeaderRow: c.HeaderRow,
+		Columns: slice.Map(c.Columns, func(idx int, src ColumnMapping) domain.ColumnMapping {
+			return domain.ColumnMapping{
+				Source:   src.Source,
+				FieldUid: src.FieldUID,
+				Transforms: slice.Map(src.Transforms, func(idx int, src ColumnTransform) domain.ColumnTransform {
+					return domain.ColumnTransform{
+						Type:      domain.TransformType(src.Type),
+						Separator: src.Separator,
+						Index:     src.Index,
+						Pattern:   src.Pattern,
+						Mapping:   src.Mapping,
+						Layout:    src.Layout,
+						Output:    src.Output,
+					}
+				}),
+			}
+		}),
+	}
+}
+
+// PreviewMappingReq 识别已上传文件的表头并给出映射建议
+type PreviewMappingReq struct {
+	ModelUID  string `json:"model_uid" binding:"required"`
+	FileKey   string `json:"file_key" binding:"required"`
+	HeaderRow int    `json:"header_row"` // 表头所在行，为 0 时自动识别
+	FileFormat
+}
+
+// ColumnSuggestion 列映射建议，没有匹配字段时 field_uid 为空
+type ColumnSuggestion struct {
+	Source    string  `json:"source"`
+	FieldUID  string  `json:"field_uid"`
+	FieldName string  `json:"field_name"`
+	Score     float64 `json:"score"` // 列名与字段的相似度 (0~1)
+}
+
+// MappingPreview 表头识别结果
+type MappingPreview struct {
+	HeaderRow   int                `json:"header_row"`
+	Headers     []string           `json:"headers"`
+	Samples     [][]string         `json:"samples"` // 表头之后的前 5 行数据
+	Suggestions []ColumnSuggestion `json:"suggestions"`
+}
+
+// CreateImportMappingReq 保存列映射方案
+type CreateImportMappingReq struct {
+	ModelUID string `json:"model_uid" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	ImportMappingConfig
+}
+
+// UpdateImportMappingReq 修改列映射方案，所属模型不允许变更
+type UpdateImportMappingReq struct {
+	ID   int64  `json:"id" binding:"required"`
+	Name string `json:"name" binding:"required"`
+	ImportMappingConfig
+}
+
+// ImportMappingReq 根据 ID 操作列映射方案
+type ImportMappingReq struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// ListImportMappingsReq 查询模型下的列映射方案
+type ListImportMappingsReq struct {
+	ModelUID string `json:"model_uid" binding:"required"`
+}
+
+// ImportMapping 列映射方案
+type ImportMapping struct {
+	ID       int64  `json:"id"`
+	ModelUID string `json:"model_uid"`
+	Name     string `json:"name"`
+	ImportMappingConfig
+	CreatorID int64 `json:"creator_id"`
+	Ctime     int64 `json:"ctime"`
+	Utime     int64 `json:"utime"`
+}
+
+// ImportJobReq 根据任务 ID 操作导入任务
+type ImportJobReq struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// ListImportJobsReq 查询模型下的导入任务，模型 UID 为空时查询多模型工作簿导入任务
+type ListImportJobsReq struct {
+	ModelUID string `json:"model_uid"`
+	Offset   int64  `json:"offset"`
+	Limit    int64  `json:"limit"`
+}
+
+// ImportRowError 行级导入错误
+type ImportRowError struct {
+	Sheet    string `json:"sheet"` // 工作簿导入时出错行所在的工作表
+	Row      int    `json:"row"`
+	Cell     string `json:"cell"` // 出错单元格坐标，如 C5
+	FieldUID string `json:"field_uid"`
+	Message  string `json:"message"`
+}
+
+// ImportSheetResult 工作簿中单个工作表的导入统计
+type ImportSheetResult struct {
+	Sheet    string `json:"sheet"`
+	ModelUID string `json:"model_uid"` // 资产关联工作表为空
+	Total    int    `json:"total"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+	Failed   int    `json:"failed"`
+}
+
+// ImportJob 导入任务进度及结果
+type ImportJob struct {
+	ID        int64               `json:"id"`
+	ModelUID  string              `json:"model_uid"`
+	FileName  string              `json:"file_name"`
+	Workbook  bool                `json:"workbook"`
+	Mode      string              `json:"mode"`
+	DryRun    bool                `json:"dry_run"`
+	Status    string              `json:"status"` // pending / running / succeeded / failed
+	Total     int                 `json:"total"`
+	Processed int                 `json:"processed"`
+	Inserted  int                 `json:"inserted"`
+	Updated   int                 `json:"updated"`
+	Failed    int                 `json:"failed"`
+	Sheets    []ImportSheetResult `json:"sheets"`     // 工作簿导入时按导入顺序排列的工作表统计
+	Errors    []ImportRowError    `json:"errors"`     // 最多返回前 200 条，完整错误见错误报告
+	HasReport bool                `json:"has_report"` // 是否生成了标注错误的 Excel 报告
+	Message   string              `json:"message"`
+	Ctime     int64               `json:"ctime"`
+	Ftime     int64               `json:"ftime"`
+}
+
+// RetrieveImportJobs 导入任务列表
+type RetrieveImportJobs struct {
+	Jobs  []ImportJob `json:"jobs"`
+	Total int64       `json:"total"`
+}
+
+// ExportJobReq 根据任务 ID 操作导出任务
+type ExportJobReq struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// ListExportJobsReq 查询模型下的导出任务
+type ListExportJobsReq struct {
+	ModelUID string `json:"model_uid" binding:"required"`
+	Offset   int64  `json:"offset"`
+	Limit    int64  `json:"limit"`
+}
+
+// ExportJob 导出任务进度及结果
+type ExportJob struct {
+	ID        int64  `json:"id"`
+	ModelUID  string `json:"model_uid"`
+	FileName  string `json:"file_name"`
+	Format    string `json:"format"`
+	Status    string `json:"status"` // pending / running / succeeded / failed
+	Processed int    `json:"processed"`
+	Rows      int    `json:"rows"` // 已写入的数据行数，关联资产展开时可能多于资产数量
+	Size      int64  `json:"size"` // 导出文件大小 (字节)
+	Message   string `json:"message"`
+	Ctime     int64  `json:"ctime"`
+	Ftime     int64  `json:"ftime"`
+}
+
+// RetrieveExportJobs 导出任务列表
+type RetrieveExportJobs struct {
+	Jobs  []ExportJob `json:"jobs"`
+	Total int64       `json:"total"`
 }
 
 // ImportV2Req 导入数据请求 V2 (直接上传文件)
@@ -46,6 +279,34 @@ type ExportFilterGroup struct {
 	Filters []ExportFilterCondition `json:"filters"`
 }
 
+func toFilterGroups(groups []ExportFilterGroup) []domain.FilterGroup {
+	return slice.Map(groups, func(idx int, src ExportFilterGroup) domain.FilterGroup {
+		return domain.FilterGroup{
+			Filters: slice.Map(src.Filters, func(idx int, src ExportFilterCondition) domain.FilterCondition {
+				return domain.FilterCondition{
+					FieldUID: src.FieldUID,
+					Operator: domain.Operator(src.Operator),
+					Value:    src.Value,
+				}
+			}),
+		}
+	})
+}
+
+func toExportFilterGroups(groups []domain.FilterGroup) []ExportFilterGroup {
+	return slice.Map(groups, func(idx int, src domain.FilterGroup) ExportFilterGroup {
+		return ExportFilterGroup{
+			Filters: slice.Map(src.Filters, func(idx int, src domain.FilterCondition) ExportFilterCondition {
+				return ExportFilterCondition{
+					FieldUID: src.FieldUID,
+					Operator: string(src.Operator),
+					Value:    src.Value,
+				}
+			}),
+		}
+	})
+}
+
 // ExportReq 导出数据请求
 type ExportReq struct {
 	ModelUID     string              `json:"model_uid" binding:"required"`
@@ -53,5 +314,137 @@ type ExportReq struct {
 	ResourceIDs  []int64             `json:"resource_ids"`  // string or number
 	FilterGroups []ExportFilterGroup `json:"filter_groups"` // scope='all' 或 'current' 时可选
 	Fields       []string            `json:"fields"`        // 导出字段列表 (可选)
+	Relations    []ExportRelation    `json:"relations"`     // 关联资产字段 (可选)
+	ViewID       int64               `json:"view_id"`       // 保存视图 ID (可选)，沿用视图的筛选条件、字段及排序
 	FileName     string              `json:"file_name"`     // 文件名 (可选)
+	FileFormat
+}
+
+// ExportWorkbookReq 多模型工作簿导出请求，模型分组与模型取并集
+type ExportWorkbookReq struct {
+	GroupIDs         []int64  `json:"group_ids"`         // 模型分组 ID
+	ModelUIDs        []string `json:"model_uids"`        // 模型 UID
+	IncludeRelations bool     `json:"include_relations"` // 追加资产关联工作表
+	FileName         string   `json:"file_name"`         // 文件名 (可选)
+}
+
+// ScheduledExportModel 定时导出的模型及筛选条件
+type ScheduledExportModel struct {
+	ModelUID     string              `json:"model_uid" binding:"required"`
+	FilterGroups []ExportFilterGroup `json:"filter_groups"`
+	Fields       []string            `json:"fields"` // 导出字段，为空时导出全部字段
+}
+
+// ScheduledExportConfig 定时导出配置
+type ScheduledExportConfig struct {
+	Name          string                 `json:"name" binding:"required"`
+	CronExpr      string                 `json:"cron_expr" binding:"required"` // 五段式 cron 表达式，如 0 2 * * *
+	Models        []ScheduledExportModel `json:"models" binding:"required,dive"`
+	RetentionDays int                    `json:"retention_days"` // 导出文件保留天数，为 0 时不清理
+	Enabled       bool                   `json:"enabled"`
+	FileFormat
+}
+
+func (c ScheduledExportConfig) toDomain() domain.ScheduledExport {
+	return domain.ScheduledExport{
+		Name:     c.Name,
+		CronExpr: c.CronExpr,
+		Models: slice.Map(c.Models, func(idx int, src ScheduledExportModel) domain.ScheduledExportModel {
+			return domain.ScheduledExportModel{
+				ModelUID:     src.ModelUID,
+				FilterGroups: toFilterGroups(src.FilterGroups),
+				Fields:       src.Fields,
+			}
+		}),
+		FormatOptions: c.FileFormat.toDomain(),
+		RetentionDays: c.RetentionDays,
+		Enabled:       c.Enabled,
+	}
+}
+
+// CreateScheduledExportReq 创建定时导出
+type CreateScheduledExportReq struct {
+	ScheduledExportConfig
+}
+
+// UpdateScheduledExportReq 修改定时导出
+type UpdateScheduledExportReq struct {
+	ID int64 `json:"id" binding:"required"`
+	ScheduledExportConfig
+}
+
+// ScheduledExportReq 根据 ID 操作定时导出
+type ScheduledExportReq struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// ListScheduledExportsReq 查询定时导出列表
+type ListScheduledExportsReq struct {
+	Offset int64 `json:"offset"`
+	Limit  int64 `json:"limit"`
+}
+
+// ScheduledExport 定时导出
+type ScheduledExport struct {
+	ID int64 `json:"id"`
+	ScheduledExportConfig
+	NextRunAt int64 `json:"next_run_at"` // 停用时为 0
+	LastRunAt int64 `json:"last_run_at"`
+	CreatorID int64 `json:"creator_id"`
+	Ctime     int64 `json:"ctime"`
+	Utime     int64 `json:"utime"`
+}
+
+// RetrieveScheduledExports 定时导出列表
+type RetrieveScheduledExports struct {
+	Schedules []ScheduledExport `json:"schedules"`
+	Total     int64             `json:"total"`
+}
+
+// ListScheduledExportRunsReq 查询定时导出的执行记录
+type ListScheduledExportRunsReq struct {
+	ScheduleID int64 `json:"schedule_id" binding:"required"`
+	Offset     int64 `json:"offset"`
+	Limit      int64 `json:"limit"`
+}
+
+// ScheduledExportFileReq 获取执行记录中指定模型的导出文件
+type ScheduledExportFileReq struct {
+	RunID    int64  `json:"run_id" binding:"required"`
+	ModelUID string `json:"model_uid" binding:"required"`
+}
+
+// ScheduledExportFile 单个模型的导出文件
+type ScheduledExportFile struct {
+	ModelUID string `json:"model_uid"`
+	HasFile  bool   `json:"has_file"`
+	Rows     int    `json:"rows"`
+	Size     int64  `json:"size"`
+	Message  string `json:"message"` // 导出失败原因
+}
+
+// ScheduledExportRun 定时导出执行记录
+type ScheduledExportRun struct {
+	ID         int64                 `json:"id"`
+	ScheduleID int64                 `json:"schedule_id"`
+	Status     string                `json:"status"` // running / succeeded / failed
+	Files      []ScheduledExportFile `json:"files"`
+	Message    string                `json:"message"`
+	Pruned     bool                  `json:"pruned"` // 导出文件已超过保留期限被清理
+	Ctime      int64                 `json:"ctime"`
+	Ftime      int64                 `json:"ftime"`
+}
+
+// RetrieveScheduledExportRuns 定时导出执行记录列表
+type RetrieveScheduledExportRuns struct {
+	Runs  []ScheduledExportRun `json:"runs"`
+	Total int64                `json:"total"`
+}
+
+// ExportRelation 关联资产导出配置
+type ExportRelation struct {
+	RelationName string   `json:"relation_name" binding:"required"`
+	Fields       []string `json:"fields"`    // 关联资产字段，默认 name
+	Mode         string   `json:"mode"`      // join（默认）或 explode
+	Separator    string   `json:"separator"` // join 模式的分隔符，默认 ","
 }
@@ -2,13 +2,17 @@ package web
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
 	modelservice "github.com/Duke1616/ecmdb/internal/service/model"
 	service "github.com/Duke1616/ecmdb/internal/service/model"
 	relationservice "github.com/Duke1616/ecmdb/internal/service/relation"
 	resourceservice "github.com/Duke1616/ecmdb/internal/service/resource"
 	"github.com/Duke1616/ecmdb/pkg/ginx"
+	"github.com/Duke1616/ecmdb/pkg/graphx"
 	"github.com/Duke1616/eiam/pkg/web/capability"
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx/gctx"
@@ -98,6 +102,12 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 		Handle(ginx.WrapBody[Page](h.FindModelsGraph)),
 	)
 
+	// 导出模型拓扑图 (GraphML / DOT / JSON Graph)
+	g.POST("/relation/graph/export", h.Capability("导出模型拓扑图", "export_relation_graph").
+		Group("模型管理/模型拓扑").
+		Handle(ginx.WrapFileBody[ExportModelGraphReq](h.ExportModelsGraph, systemErrorResult)),
+	)
+
 	// ==========================================
 	// 4. 模型关联关系管理接口
 	// ==========================================
@@ -120,6 +130,24 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 		Handle(ginx.WrapBody[DeleteModelRelationReq](h.DeleteModelRelation)),
 	)
 
+	// 删除模型关联关系前的影响评估
+	g.POST("/relation/delete/impact", h.Capability("模型关联删除影响评估", "relation_delete_impact").
+		Group("模型管理/关联关系").
+		Handle(ginx.WrapBody[DeleteModelRelationReq](h.ModelRelationDeleteImpact)),
+	)
+
+	// 将资源关联数据迁移到另一个模型关联关系下
+	g.POST("/relation/migrate", h.Capability("迁移模型关联数据", "relation_migrate").
+		Group("模型管理/关联关系").
+		Handle(ginx.WrapBody[MigrateModelRelationReq](h.MigrateModelRelation)),
+	)
+
+	// 强制级联删除模型关联关系，会一并删除资源关联数据，需单独授权
+	g.POST("/relation/delete/force", h.Capability("强制级联删除模型关联关系", "relation_delete_force").
+		Group("模型管理/关联关系").
+		Handle(ginx.WrapBody[DeleteModelRelationReq](h.ForceDeleteModelRelation)),
+	)
+
 	// 更新模型关联关系
 	g.POST("/relation/update", h.Capability("更新模型关联关系", "relation_edit").
 		Group("模型管理/关联关系").
@@ -370,6 +398,98 @@ func (h *Handler) FindModelsGraph(ctx *gin.Context, req Page) (ginx.Result, erro
 	}, nil
 }
 
+// modelGraphFields 模型拓扑导出支持的节点属性
+var modelGraphFields = []string{"icon", "group_id", "group_name", "builtin", "total"}
+
+// ExportModelsGraph 按标准图格式导出模型拓扑
+func (h *Handler) ExportModelsGraph(ctx *gin.Context, req ExportModelGraphReq) (ginx.File, error) {
+	format, err := graphx.ParseFormat(req.Format)
+	if err != nil {
+		return ginx.File{}, errs.ValidationError.WithMsg(err.Error())
+	}
+	if unknown, _ := lo.Difference(req.Fields, modelGraphFields); len(unknown) > 0 {
+		return ginx.File{}, errs.ValidationError.WithMsg(fmt.Sprintf("不支持的节点属性: %s", strings.Join(unknown, ",")))
+	}
+
+	models, err := h.svc.ListAll(ctx)
+	if err != nil {
+		return ginx.File{}, err
+	}
+	modelUids := slice.Map(models, func(idx int, src domain.Model) string {
+		return src.UID
+	})
+
+	ds, err := h.RMSvc.FindModelDiagramBySrcUids(ctx, modelUids)
+	if err != nil {
+		return ginx.File{}, err
+	}
+
+	// 按需加载分组名称与资产数量，避免无用查询
+	groupNames := make(map[int64]string)
+	if lo.Contains(req.Fields, "group_name") {
+		groupIds := lo.Uniq(slice.Map(models, func(idx int, src domain.Model) int64 {
+			return src.GroupId
+		}))
+		groups, er := h.mgSvc.GetByIDs(ctx, groupIds)
+		if er != nil {
+			return ginx.File{}, er
+		}
+		groupNames = lo.SliceToMap(groups, func(src domain.ModelGroup) (int64, string) {
+			return src.ID, src.Name
+		})
+	}
+	totals := make(map[string]int)
+	if lo.Contains(req.Fields, "total") {
+		totals, err = h.resourceSvc.CountByModelUids(ctx, modelUids)
+		if err != nil {
+			return ginx.File{}, err
+		}
+	}
+
+	nodes := slice.Map(models, func(idx int, src domain.Model) graphx.Node {
+		values := map[string]any{
+			"icon":       src.Icon,
+			"group_id":   src.GroupId,
+			"group_name": groupNames[src.GroupId],
+			"builtin":    src.Builtin,
+			"total":      totals[src.UID],
+		}
+		return graphx.Node{
+			ID:    src.UID,
+			Label: src.Name,
+			Attrs: lo.PickByKeys(values, req.Fields),
+		}
+	})
+
+	edges := slice.Map(ds, func(idx int, src domain.ModelDiagram) graphx.Edge {
+		return graphx.Edge{
+			Source: src.SourceModelUid,
+			Target: src.TargetModelUid,
+			Label:  fmt.Sprintf("%s_%s_%s", src.SourceModelUid, src.RelationTypeUid, src.TargetModelUid),
+			Attrs: map[string]any{
+				"relation_type_uid": src.RelationTypeUid,
+			},
+		}
+	})
+
+	data, err := graphx.Marshal(graphx.Graph{
+		ID:       "models",
+		Label:    "模型拓扑",
+		Directed: true,
+		Nodes:    nodes,
+		Edges:    edges,
+	}.Dedup(), format)
+	if err != nil {
+		return ginx.File{}, err
+	}
+
+	return ginx.File{
+		Name:        "model_graph" + format.Extension(),
+		ContentType: format.ContentType(),
+		Data:        data,
+	}, nil
+}
+
 func (h *Handler) toVo(src domain.Model) Model {
 	return Model{
 		Id:      src.ID,
@@ -57,6 +57,56 @@ func (h *Handler) DeleteModelRelation(ctx *gin.Context, req DeleteModelRelationR
 	}, nil
 }
 
+func (h *Handler) ModelRelationDeleteImpact(ctx *gin.Context, req DeleteModelRelationReq) (ginx.Result, error) {
+	impact, err := h.RMSvc.DeleteImpact(ctx, req.Id)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: RelationImpact{
+			ModelRelation: h.toRelationVO(impact.ModelRelation),
+			Total:         impact.Total,
+			Samples: slice.Map(impact.Samples, func(idx int, src domain.ResourceRelation) ResourceRelationSample {
+				return ResourceRelationSample{
+					ID:               src.ID,
+					SourceModelUID:   src.SourceModelUID,
+					SourceResourceID: src.SourceResourceID,
+					TargetModelUID:   src.TargetModelUID,
+					TargetResourceID: src.TargetResourceID,
+				}
+			}),
+		},
+	}, nil
+}
+
+func (h *Handler) MigrateModelRelation(ctx *gin.Context, req MigrateModelRelationReq) (ginx.Result, error) {
+	plan, err := h.RMSvc.MigrateResourceRelations(ctx, req.FromId, req.ToId)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Msg: "迁移模型关联数据成功",
+		Data: RelationMigrationResult{
+			Moved:      len(plan.MoveIDs),
+			Duplicated: len(plan.DuplicateIDs),
+		},
+	}, nil
+}
+
+func (h *Handler) ForceDeleteModelRelation(ctx *gin.Context, req DeleteModelRelationReq) (ginx.Result, error) {
+	count, err := h.RMSvc.ForceDeleteModelRelation(ctx, req.Id)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Msg:  "级联删除模型关联关系成功",
+		Data: count,
+	}, nil
+}
+
 func (h *Handler) UpdateModelRelation(ctx *gin.Context, req UpdateModelRelationReq) (ginx.Result, error) {
 	_, err := h.RMSvc.UpdateModelRelation(ctx, toUpdateModelDomain(req))
 	if err != nil {
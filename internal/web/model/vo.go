@@ -78,6 +78,12 @@ type RetrieveRelationModelGraph struct {
 	Lines  []ModelLine `json:"lines"`
 }
 
+// ExportModelGraphReq 导出模型拓扑图请求
+type ExportModelGraphReq struct {
+	Format string   `json:"format"` // graphml / dot / json
+	Fields []string `json:"fields"` // 节点属性: icon / group_id / group_name / builtin / total
+}
+
 type ModelNode struct {
 	ID   string            `json:"id"`
 	Text string            `json:"text"`
@@ -135,3 +141,29 @@ type RetrieveRelationModels struct {
 type DeleteModelRelationReq struct {
 	Id int64 `json:"id"`
 }
+
+type MigrateModelRelationReq struct {
+	FromId int64 `json:"from_id"`
+	ToId   int64 `json:"to_id"`
+}
+
+// ResourceRelationSample 受影响的资源关联样例
+type ResourceRelationSample struct {
+	ID               int64  `json:"id"`
+	SourceModelUID   string `json:"source_model_uid"`
+	SourceResourceID int64  `json:"source_resource_id"`
+	TargetModelUID   string `json:"target_model_uid"`
+	TargetResourceID int64  `json:"target_resource_id"`
+}
+
+// RelationImpact 删除模型关联前的影响评估
+type RelationImpact struct {
+	ModelRelation ModelRelation            `json:"model_relation"`
+	Total         int64                    `json:"total"`
+	Samples       []ResourceRelationSample `json:"samples"`
+}
+
+type RelationMigrationResult struct {
+	Moved      int `json:"moved"`
+	Duplicated int `json:"duplicated"`
+}
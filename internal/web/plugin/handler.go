@@ -1,11 +1,14 @@
 package web
 
 import (
+	"context"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
 	"github.com/Duke1616/ecmdb/internal/errs"
@@ -15,6 +18,7 @@ import (
 	"github.com/Duke1616/eiam/pkg/ctxutil"
 	"github.com/Duke1616/eiam/pkg/web/capability"
 	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/elog"
 )
 
 type Handler struct {
@@ -57,18 +61,52 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	g.DELETE("/binding/delete/:uid", h.Capability("删除插件绑定", "delete").
 		Handle(ginx.Wrap(h.DeleteBinding)),
 	)
+	g.POST("/uninstall", h.Capability("卸载插件", "uninstall").
+		Needs("cmdb:plugin:get").
+		Handle(ginx.WrapBody[UninstallPluginReq](h.UninstallPlugin)),
+	)
 	g.POST("/resource/actions/batch", h.Capability("查询资源插件动作", "actions").
 		NoSync().
 		Handle(ginx.WrapBody[ListResourceActionsBatchReq](h.ListResourceActionsBatch)),
 	)
+	g.POST("/placement/actions", h.Capability("查询插件入口动作", "placement_actions").
+		NoSync().
+		Handle(ginx.WrapBody[ListPlacementActionsReq](h.ListPlacementActions)),
+	)
 	g.POST("/action/resolve", h.Capability("解析插件动作", "resolve").
 		NoSync().
 		Handle(ginx.WrapBody[pluginx.ResolveRequest](h.ResolveAction)),
 	)
+	g.POST("/action/batch_resolve", h.Capability("批量解析插件动作", "batch_resolve").
+		NoSync().
+		Handle(ginx.WrapBody[BatchResolveActionReq](h.BatchResolveAction)),
+	)
 	g.GET("/runtime/view", h.Capability("插件运行时视图", "runtime_view").
 		Needs("cmdb:plugin:resolve").
 		Handle(ginx.Wrap(h.GetRuntimeView)),
 	)
+	g.POST("/audit/list", h.Capability("插件审计日志", "audit_view").
+		Handle(ginx.WrapBody[ListAuditLogsReq](h.ListAuditLogs)),
+	)
+	g.POST("/credential/issue", h.Capability("签发插件凭证", "credential").
+		Handle(ginx.WrapBody[IssueCredentialReq](h.IssueCredential)),
+	)
+	g.GET("/version/list", h.Capability("插件版本历史", "version_view").
+		Needs("cmdb:plugin:get").
+		Handle(ginx.Wrap(h.ListVersions)),
+	)
+	g.POST("/version/diff", h.Capability("插件版本对比", "version_diff").
+		NoSync().
+		Handle(ginx.WrapBody[DiffVersionsReq](h.DiffVersions)),
+	)
+	g.GET("/upgrade/preview", h.Capability("插件升级预览", "upgrade_preview").
+		NoSync().
+		Handle(ginx.Wrap(h.PreviewUpgrade)),
+	)
+	g.POST("/version/rollback", h.Capability("回滚插件定义", "rollback").
+		Needs("cmdb:plugin:version_view", "cmdb:plugin:version_diff").
+		Handle(ginx.WrapBody[RollbackDefinitionReq](h.RollbackDefinition)),
+	)
 }
 
 func (h *Handler) PublicRoutes(server *gin.Engine) {
@@ -157,8 +195,24 @@ func (h *Handler) DeleteBinding(ctx *gin.Context) (ginx.Result, error) {
 	return ginx.Result{Msg: "删除插件绑定成功"}, nil
 }
 
+func (h *Handler) UninstallPlugin(ctx *gin.Context, req UninstallPluginReq) (ginx.Result, error) {
+	result, err := h.svc.UninstallPlugin(ctx.Request.Context(), domain.UninstallPlugin{
+		PluginID:      req.PluginID,
+		CleanupSchema: req.CleanupSchema,
+		Force:         req.Force,
+	})
+	if err != nil {
+		return ginx.Result{Msg: "卸载插件失败"}, err
+	}
+
+	return ginx.Result{
+		Msg:  "卸载插件成功",
+		Data: result,
+	}, nil
+}
+
 func (h *Handler) ListResourceActionsBatch(ctx *gin.Context, req ListResourceActionsBatchReq) (ginx.Result, error) {
-	actions, err := h.svc.ListResourceActionsBatch(ctx.Request.Context(), req.ResourceIDs)
+	actions, err := h.svc.ListResourceActionsBatch(ctx.Request.Context(), req.Placement, req.ResourceIDs)
 	if err != nil {
 		return ginx.Result{Msg: "批量查询插件动作失败"}, err
 	}
@@ -169,8 +223,20 @@ func (h *Handler) ListResourceActionsBatch(ctx *gin.Context, req ListResourceAct
 	}, nil
 }
 
+func (h *Handler) ListPlacementActions(ctx *gin.Context, req ListPlacementActionsReq) (ginx.Result, error) {
+	actions, err := h.svc.ListPlacementActions(ctx.Request.Context(), req.Placement, req.ModelUID)
+	if err != nil {
+		return ginx.Result{Msg: "查询插件入口动作失败"}, err
+	}
+
+	return ginx.Result{
+		Msg:  "查询插件入口动作成功",
+		Data: actions,
+	}, nil
+}
+
 func (h *Handler) ResolveAction(ctx *gin.Context, req pluginx.ResolveRequest) (ginx.Result, error) {
-	result, err := h.svc.ResolveAction(ctx.Request.Context(), req)
+	result, err := h.svc.ResolveAction(auditContext(ctx), req)
 	if err != nil {
 		return ginx.Result{Msg: "解析插件动作失败"}, err
 	}
@@ -181,6 +247,96 @@ func (h *Handler) ResolveAction(ctx *gin.Context, req pluginx.ResolveRequest) (g
 	}, nil
 }
 
+func (h *Handler) BatchResolveAction(ctx *gin.Context, req BatchResolveActionReq) (ginx.Result, error) {
+	result, err := h.svc.BatchResolveAction(auditContext(ctx), req.toDomain())
+	if err != nil {
+		return ginx.Result{Msg: "批量解析插件动作失败"}, err
+	}
+
+	return ginx.Result{
+		Msg:  "批量解析插件动作成功",
+		Data: result,
+	}, nil
+}
+
+func (h *Handler) ListAuditLogs(ctx *gin.Context, req ListAuditLogsReq) (ginx.Result, error) {
+	logs, total, err := h.svc.ListAuditLogs(ctx.Request.Context(), req.toDomain())
+	if err != nil {
+		return ginx.Result{Msg: "查询插件审计日志失败"}, err
+	}
+
+	return ginx.Result{
+		Msg: "查询插件审计日志成功",
+		Data: RetrieveAuditLogs{
+			Logs:  logs,
+			Total: total,
+		},
+	}, nil
+}
+
+func (h *Handler) IssueCredential(ctx *gin.Context, req IssueCredentialReq) (ginx.Result, error) {
+	secret, err := h.svc.IssueCredential(ctx.Request.Context(), req.PluginID)
+	if err != nil {
+		return ginx.Result{Msg: "签发插件凭证失败"}, err
+	}
+
+	return ginx.Result{
+		Msg: "签发插件凭证成功",
+		Data: map[string]any{
+			"plugin_id": req.PluginID,
+			"secret":    secret,
+		},
+	}, nil
+}
+
+func (h *Handler) ListVersions(ctx *gin.Context) (ginx.Result, error) {
+	pluginID := ctx.Query("plugin_id")
+	versions, err := h.svc.ListVersions(ctx.Request.Context(), pluginID)
+	if err != nil {
+		return ginx.Result{Msg: "查询插件版本历史失败"}, err
+	}
+
+	return ginx.Result{
+		Msg: "查询插件版本历史成功",
+		Data: map[string]any{
+			"list":  versions,
+			"total": len(versions),
+		},
+	}, nil
+}
+
+func (h *Handler) DiffVersions(ctx *gin.Context, req DiffVersionsReq) (ginx.Result, error) {
+	diff, err := h.svc.DiffVersions(ctx.Request.Context(), req.PluginID, req.From, req.To)
+	if err != nil {
+		return ginx.Result{Msg: "对比插件版本失败"}, err
+	}
+
+	return ginx.Result{
+		Msg:  "对比插件版本成功",
+		Data: diff,
+	}, nil
+}
+
+func (h *Handler) PreviewUpgrade(ctx *gin.Context) (ginx.Result, error) {
+	pluginID := ctx.Query("plugin_id")
+	plan, err := h.svc.PreviewUpgrade(ctx.Request.Context(), pluginID)
+	if err != nil {
+		return ginx.Result{Msg: "查询插件升级预览失败"}, err
+	}
+
+	return ginx.Result{
+		Msg:  "查询插件升级预览成功",
+		Data: plan,
+	}, nil
+}
+
+func (h *Handler) RollbackDefinition(ctx *gin.Context, req RollbackDefinitionReq) (ginx.Result, error) {
+	if err := h.svc.RollbackDefinition(ctx.Request.Context(), req.PluginID, req.Revision); err != nil {
+		return ginx.Result{Msg: "回滚插件定义失败"}, err
+	}
+	return ginx.Result{Msg: "回滚插件定义成功"}, nil
+}
+
 func (h *Handler) GetRuntimeView(ctx *gin.Context) (ginx.Result, error) {
 	resourceID, err := strconv.ParseInt(ctx.Query("resource_id"), 10, 64)
 	if err != nil || resourceID <= 0 {
@@ -192,7 +348,7 @@ func (h *Handler) GetRuntimeView(ctx *gin.Context) (ginx.Result, error) {
 		Action:     strings.TrimSpace(ctx.Query("action")),
 		ResourceID: resourceID,
 	}
-	result, err := h.svc.ResolveAction(ctx.Request.Context(), req)
+	result, err := h.svc.ResolveAction(auditContext(ctx), req)
 	if err != nil {
 		return ginx.Result{Msg: "解析插件运行时失败"}, err
 	}
@@ -225,8 +381,10 @@ type runtimeEntry struct {
 }
 
 type runtimePayload struct {
-	APIBase string         `json:"api_base"`
-	Props   map[string]any `json:"props"`
+	APIBase        string         `json:"api_base"`
+	Props          map[string]any `json:"props"`
+	Token          string         `json:"token,omitempty"`
+	TokenExpiresAt int64          `json:"token_expires_at,omitempty"`
 }
 
 type runtimePresentation struct {
@@ -257,8 +415,10 @@ func buildRuntimeView(result pluginx.ResolveResult) runtimeView {
 			ComponentName: "Index",
 		},
 		Runtime: runtimePayload{
-			APIBase: "/api/cmdb/plugin-runtime/" + result.PluginID,
-			Props:   props,
+			APIBase:        "/api/cmdb/plugin-runtime/" + result.PluginID,
+			Props:          props,
+			Token:          result.Token,
+			TokenExpiresAt: result.TokenExpires,
 		},
 		Presentation: presentation,
 	}
@@ -321,25 +481,70 @@ func pluginGlobalName(pluginID string) string {
 // ProxyToPlugin 通用插件反向代理网关，自动读取对应插件的 upstream 动态执行 HTTP/WebSocket 代理
 func (h *Handler) ProxyToPlugin(ctx *gin.Context) {
 	pluginID := ctx.Param("plugin_id")
-	anyPath := ctx.Param("any")
+	// 规范化路径，避免通过 /static/../ 绕过动作令牌校验
+	anyPath := cleanProxyPath(ctx.Param("any"))
+	token := takeActionToken(ctx.Request)
+
+	requestID := ctx.GetHeader(pluginx.HeaderRequestID)
+	if requestID == "" {
+		requestID = pluginx.NewRequestID()
+	}
+
+	// 静态资源之外的代理请求均写入审计日志
+	var audit *domain.PluginAuditLog
+	if !isStaticAsset(anyPath) {
+		audit = &domain.PluginAuditLog{
+			PluginID:  pluginID,
+			Source:    domain.PluginAuditSourceProxy,
+			SourceIP:  ctx.ClientIP(),
+			RequestID: requestID,
+			Method:    ctx.Request.Method,
+			Path:      anyPath,
+		}
+		defer h.auditProxy(ctx, audit, time.Now())
+	}
 
 	// 公共路由没有登录态，内置插件固定从系统租户空间读取。
 	detail, err := h.svc.GetPluginDetail(ctxutil.WithTenantID(ctx.Request.Context(), ctxutil.SystemTenantID), pluginID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"msg": "未找到对应的插件定义"})
+		abortProxy(ctx, audit, http.StatusNotFound, "未找到对应的插件定义")
 		return
 	}
 
 	runtime, ok := detail.Plugin.Runtime()
 	if !ok || runtime.Upstream == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "插件 upstream 运行态地址未配置"})
+		abortProxy(ctx, audit, http.StatusBadRequest, "插件 upstream 运行态地址未配置")
+		return
+	}
+
+	// 静态资源之外的请求必须携带解析动作时签发的令牌，并以令牌中的用户身份签发身份信息转发给插件
+	var identity string
+	if !isStaticAsset(anyPath) {
+		claims, err := h.svc.VerifyActionToken(pluginID, token)
+		if err != nil {
+			abortProxy(ctx, audit, http.StatusUnauthorized, "插件动作令牌校验失败: "+err.Error())
+			return
+		}
+		audit.Action, audit.ResourceIDs = claims.Action, claims.Resources()
+		audit.UserID, audit.TenantID = claims.UserID, claims.TenantID
+
+		identity, err = h.svc.SignIdentity(ctx.Request.Context(), detail.Plugin, claims, requestID)
+		if err != nil {
+			abortProxy(ctx, audit, http.StatusBadGateway, "签发插件身份信息失败: "+err.Error())
+			return
+		}
+	}
+
+	// 健康检查已判定不可用时直接返回，避免等待上游超时后得到代理 502
+	if health := detail.Plugin.Health; health.Unhealthy() {
+		abortProxy(ctx, audit, http.StatusServiceUnavailable, "插件运行时不可用: "+health.LastError)
 		return
 	}
 
 	// 2. 解析真实的 upstream 物理地址
 	targetURL, err := url.Parse(runtime.Upstream)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "解析插件 upstream 地址失败"})
+		abortProxy(ctx, audit, http.StatusInternalServerError, "解析插件 upstream 地址失败")
 		return
 	}
 
@@ -355,10 +560,84 @@ func (h *Handler) ProxyToPlugin(ctx *gin.Context) {
 		req.URL.Path = anyPath
 		req.Host = targetURL.Host
 
-		// 透传插件身份与调用头
+		// 透传插件身份与调用头，调用方伪造的身份信息一律丢弃
 		req.Header.Set(pluginx.HeaderPluginID, pluginID)
+		req.Header.Set(pluginx.HeaderRequestID, requestID)
+		req.Header.Del(pluginx.HeaderIdentity)
+		if identity != "" {
+			req.Header.Set(pluginx.HeaderIdentity, identity)
+		}
+
+		// 校验通过的动作令牌统一经请求头转发，插件回调 gRPC 解析动作上下文时原样携带；令牌绑定本插件，无法用于其他插件
+		req.Header.Del(pluginx.HeaderPluginToken)
+		if identity != "" {
+			req.Header.Set(pluginx.HeaderPluginToken, token)
+		}
+	}
+
+	// 上游连接失败时返回明确的错误信息，代替默认的空 502 响应
+	proxy.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		abortProxy(ctx, audit, http.StatusBadGateway, "插件运行时请求失败: "+err.Error())
 	}
 
 	// 4. 执行反向代理
 	proxy.ServeHTTP(ctx.Writer, ctx.Request)
 }
+
+// abortProxy 返回代理错误，错误信息同时记录到审计日志
+func abortProxy(ctx *gin.Context, audit *domain.PluginAuditLog, status int, msg string) {
+	if audit != nil {
+		audit.Error = msg
+	}
+	ctx.JSON(status, gin.H{"msg": msg})
+}
+
+// auditProxy 以插件响应状态码判定代理请求是否成功，审计写入失败不影响响应
+func (h *Handler) auditProxy(ctx *gin.Context, audit *domain.PluginAuditLog, start time.Time) {
+	audit.StatusCode = ctx.Writer.Status()
+	audit.Success = audit.StatusCode < http.StatusBadRequest
+	audit.DurationMs = time.Since(start).Milliseconds()
+	if !audit.Success && audit.Error == "" {
+		audit.Error = http.StatusText(audit.StatusCode)
+	}
+
+	if err := h.svc.RecordAudit(ctx.Request.Context(), *audit); err != nil {
+		elog.DefaultLogger.Error("记录插件代理审计日志失败", elog.FieldErr(err),
+			elog.String("plugin_id", audit.PluginID), elog.String("request_id", audit.RequestID))
+	}
+}
+
+// auditContext 标记 HTTP 调用来源，解析插件动作时写入审计日志
+func auditContext(ctx *gin.Context) context.Context {
+	return pluginservice.WithAuditSource(ctx.Request.Context(), domain.PluginAuditSourceHTTP, ctx.ClientIP())
+}
+
+// cleanProxyPath 规范化代理路径并保留末尾的斜杠
+func cleanProxyPath(raw string) string {
+	cleaned := path.Clean("/" + raw)
+	if strings.HasSuffix(raw, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// isStaticAsset 插件前端资源通过 script/link 标签加载，无法携带令牌，不做校验
+func isStaticAsset(anyPath string) bool {
+	return strings.HasPrefix(anyPath, "/static/")
+}
+
+// takeActionToken 优先读取请求头中的动作令牌，WebSocket 等无法设置请求头的场景从查询参数读取
+// NOTE: 查询参数中的令牌读取后立即从请求地址中移除，避免被访问日志记录或转发给插件
+func takeActionToken(req *http.Request) string {
+	token := req.Header.Get(pluginx.HeaderPluginToken)
+
+	query := req.URL.Query()
+	if query.Has(pluginx.QueryPluginToken) {
+		if token == "" {
+			token = query.Get(pluginx.QueryPluginToken)
+		}
+		query.Del(pluginx.QueryPluginToken)
+		req.URL.RawQuery = query.Encode()
+	}
+	return token
+}
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
@@ -85,3 +87,37 @@ func TestBuildRuntimeViewDefaultsToActionName(t *testing.T) {
 func boolPtr(value bool) *bool {
 	return &value
 }
+
+func TestCleanProxyPathPreventsStaticBypass(t *testing.T) {
+	tests := []struct {
+		raw    string
+		want   string
+		static bool
+	}{
+		{raw: "/static/index.umd.js", want: "/static/index.umd.js", static: true},
+		{raw: "/static/../api/exec", want: "/api/exec"},
+		{raw: "/api/sessions/", want: "/api/sessions/"},
+		{raw: "static/x.css", want: "/static/x.css", static: true},
+	}
+
+	for _, tt := range tests {
+		got := cleanProxyPath(tt.raw)
+		if got != tt.want {
+			t.Fatalf("cleanProxyPath(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+		if isStaticAsset(got) != tt.static {
+			t.Fatalf("isStaticAsset(%q) = %v, want %v", got, !tt.static, tt.static)
+		}
+	}
+}
+
+func TestTakeActionTokenStripsQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/plugins/proxy/builtin.ssh/ws?plugin_token=abc&cols=80", nil)
+
+	if got := takeActionToken(req); got != "abc" {
+		t.Fatalf("takeActionToken() = %q, want %q", got, "abc")
+	}
+	if got := req.URL.RequestURI(); got != "/api/plugins/proxy/builtin.ssh/ws?cols=80" {
+		t.Fatalf("令牌未从请求地址中移除: %s", got)
+	}
+}
@@ -1,5 +1,115 @@
 package web
 
+import (
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+// ListResourceActionsBatchReq 批量查询资源插件动作，placement 为空时返回全部需要资源的动作
 type ListResourceActionsBatchReq struct {
 	ResourceIDs []int64 `json:"resource_ids" binding:"required"`
+	Placement   string  `json:"placement"`
+}
+
+// ListPlacementActionsReq 查询模型级动作或全局菜单入口的插件动作，模型级动作需要 model_uid
+type ListPlacementActionsReq struct {
+	Placement string `json:"placement" binding:"required"`
+	ModelUID  string `json:"model_uid"`
+}
+
+type IssueCredentialReq struct {
+	PluginID string `json:"plugin_id" binding:"required"`
+}
+
+// UninstallPluginReq 卸载插件，cleanup_schema 同时清理插件新建的模型、字段和关联类型，force 允许删除模型下的资产
+type UninstallPluginReq struct {
+	PluginID      string `json:"plugin_id" binding:"required"`
+	CleanupSchema bool   `json:"cleanup_schema"`
+	Force         bool   `json:"force"`
+}
+
+// BatchResolveActionReq 批量解析插件动作，resource_ids 为空时按 model_uid 和 filter_groups 筛选资源
+type BatchResolveActionReq struct {
+	PluginID     string              `json:"plugin_id" binding:"required"`
+	Action       string              `json:"action" binding:"required"`
+	ResourceIDs  []int64             `json:"resource_ids"`
+	ModelUID     string              `json:"model_uid"`
+	FilterGroups []ActionFilterGroup `json:"filter_groups"`
+	Params       map[string]any      `json:"params"`
+}
+
+type ActionFilterCondition struct {
+	FieldUID string      `json:"field_uid"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// ActionFilterGroup 资源筛选条件组 (组内 AND)
+type ActionFilterGroup struct {
+	Filters []ActionFilterCondition `json:"filters"`
+}
+
+func (req BatchResolveActionReq) toDomain() domain.BatchResolvePluginAction {
+	return domain.BatchResolvePluginAction{
+		PluginID:    req.PluginID,
+		Action:      req.Action,
+		ResourceIDs: req.ResourceIDs,
+		ModelUID:    req.ModelUID,
+		FilterGroups: slice.Map(req.FilterGroups, func(idx int, src ActionFilterGroup) domain.FilterGroup {
+			return domain.FilterGroup{
+				Filters: slice.Map(src.Filters, func(idx int, src ActionFilterCondition) domain.FilterCondition {
+					return domain.FilterCondition{
+						FieldUID: src.FieldUID,
+						Operator: domain.Operator(src.Operator),
+						Value:    src.Value,
+					}
+				}),
+			}
+		}),
+		Params: req.Params,
+	}
+}
+
+type ListAuditLogsReq struct {
+	PluginID   string `json:"plugin_id"`
+	Action     string `json:"action"`
+	UserID     int64  `json:"user_id"`
+	ResourceID int64  `json:"resource_id"`
+	Source     string `json:"source"`
+	Success    *bool  `json:"success"`
+	StartTime  int64  `json:"start_time"` // Unix 毫秒
+	EndTime    int64  `json:"end_time"`   // Unix 毫秒
+	Offset     int64  `json:"offset"`
+	Limit      int64  `json:"limit"`
+}
+
+func (req ListAuditLogsReq) toDomain() domain.PluginAuditQuery {
+	return domain.PluginAuditQuery{
+		PluginID:   req.PluginID,
+		Action:     req.Action,
+		UserID:     req.UserID,
+		ResourceID: req.ResourceID,
+		Source:     req.Source,
+		Success:    req.Success,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Offset:     req.Offset,
+		Limit:      req.Limit,
+	}
+}
+
+type RetrieveAuditLogs struct {
+	Logs  []domain.PluginAuditLog `json:"logs"`
+	Total int64                   `json:"total"`
+}
+
+type DiffVersionsReq struct {
+	PluginID string `json:"plugin_id" binding:"required"`
+	From     int64  `json:"from" binding:"required"`
+	To       int64  `json:"to" binding:"required"`
+}
+
+type RollbackDefinitionReq struct {
+	PluginID string `json:"plugin_id" binding:"required"`
+	Revision int64  `json:"revision" binding:"required"`
 }
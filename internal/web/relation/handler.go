@@ -50,6 +50,16 @@ func (h *RelationTypeHandler) PrivateRoute(server *gin.Engine) {
 	g.POST("/delete", h.Capability("删除关联类型", "delete").
 		Handle(ginx.WrapBody[DeleteRelationTypeReq](h.Delete)),
 	)
+
+	// 删除关联类型前的影响评估
+	g.POST("/delete/impact", h.Capability("关联类型删除影响评估", "delete_impact").
+		Handle(ginx.WrapBody[DeleteRelationTypeReq](h.DeleteImpact)),
+	)
+
+	// 强制级联删除关联类型，会一并删除模型关联及资源关联数据，需单独授权
+	g.POST("/delete/force", h.Capability("强制级联删除关联类型", "delete_force").
+		Handle(ginx.WrapBody[DeleteRelationTypeReq](h.ForceDelete)),
+	)
 }
 
 func (h *RelationTypeHandler) Create(ctx *gin.Context, req CreateRelationTypeReq) (ginx.Result, error) {
@@ -131,3 +141,37 @@ func (h *RelationTypeHandler) toUpdateDomain(req UpdateRelationTypeReq) domain.R
 		TargetDescribe: req.TargetDescribe,
 	}
 }
+
+func (h *RelationTypeHandler) DeleteImpact(ctx *gin.Context, req DeleteRelationTypeReq) (ginx.Result, error) {
+	impact, err := h.svc.DeleteImpact(ctx, req.Id)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: RelationTypeImpact{
+			RelationType: h.toRelationVO(impact.RelationType),
+			ModelRelations: slice.Map(impact.ModelRelations, func(idx int, src domain.RelationImpact) ModelRelationImpact {
+				return ModelRelationImpact{
+					ID:             src.ModelRelation.ID,
+					RelationName:   src.ModelRelation.RelationName,
+					SourceModelUID: src.ModelRelation.SourceModelUID,
+					TargetModelUID: src.ModelRelation.TargetModelUID,
+					Total:          src.Total,
+				}
+			}),
+			Total: impact.Total,
+		},
+	}, nil
+}
+
+func (h *RelationTypeHandler) ForceDelete(ctx *gin.Context, req DeleteRelationTypeReq) (ginx.Result, error) {
+	count, err := h.svc.ForceDelete(ctx, req.Id)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Msg:  "级联删除关联类型成功",
+		Data: count,
+	}, nil
+}
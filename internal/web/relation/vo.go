@@ -28,3 +28,19 @@ type RetrieveRelationType struct {
 	Total         int64          `json:"total,omitempty"`
 	RelationTypes []RelationType `json:"relation_types,omitempty"`
 }
+
+// ModelRelationImpact 关联类型下单个模型关联的影响评估
+type ModelRelationImpact struct {
+	ID             int64  `json:"id"`
+	RelationName   string `json:"relation_name"`
+	SourceModelUID string `json:"source_model_uid"`
+	TargetModelUID string `json:"target_model_uid"`
+	Total          int64  `json:"total"`
+}
+
+// RelationTypeImpact 删除关联类型前的影响评估
+type RelationTypeImpact struct {
+	RelationType   RelationType          `json:"relation_type"`
+	ModelRelations []ModelRelationImpact `json:"model_relations"`
+	Total          int64                 `json:"total"`
+}
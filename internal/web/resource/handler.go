@@ -6,11 +6,15 @@ import (
 	"strings"
 
 	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
 	attributeservice "github.com/Duke1616/ecmdb/internal/service/attribute"
 	modelservice "github.com/Duke1616/ecmdb/internal/service/model"
 	relationservice "github.com/Duke1616/ecmdb/internal/service/relation"
 	service "github.com/Duke1616/ecmdb/internal/service/resource"
+	viewservice "github.com/Duke1616/ecmdb/internal/service/view"
 	"github.com/Duke1616/ecmdb/pkg/ginx"
+	"github.com/Duke1616/ecmdb/pkg/graphx"
+	"github.com/Duke1616/ecmdb/pkg/mongox"
 	"github.com/Duke1616/eiam/pkg/web/capability"
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-gonic/gin"
@@ -22,15 +26,18 @@ type Handler struct {
 	attrSvc  attributeservice.Service
 	modelSvc modelservice.Service
 	RRSvc    relationservice.RelationResourceService
+	viewSvc  viewservice.Service
 	capability.IRegistry
 }
 
-func NewHandler(svc service.EncryptedSvc, attributeSvc attributeservice.Service, modelSvc modelservice.Service, RRSvc relationservice.RelationResourceService) *Handler {
+func NewHandler(svc service.EncryptedSvc, attributeSvc attributeservice.Service, modelSvc modelservice.Service,
+	RRSvc relationservice.RelationResourceService, viewSvc viewservice.Service) *Handler {
 	return &Handler{
 		svc:       svc,
 		attrSvc:   attributeSvc,
 		modelSvc:  modelSvc,
 		RRSvc:     RRSvc,
+		viewSvc:   viewSvc,
 		IRegistry: capability.NewRegistry("cmdb", "resource", "资产仓库"),
 	}
 }
@@ -55,7 +62,7 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 
 	// 根据模型 UID 查询资产列表
 	g.POST("/list", h.Capability("资产列表", "view").
-		Needs("cmdb:model:view", "cmdb:attribute:view", "cmdb:plugin:actions").
+		Needs("cmdb:model:view", "cmdb:attribute:view", "cmdb:plugin:actions", "cmdb:view:view").
 		Handle(ginx.WrapBody[ListResourceReq](h.ListResource)),
 	)
 
@@ -110,6 +117,18 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 		Handle(ginx.WrapBody[ListDiagramReq](h.FindRightGraph)),
 	)
 
+	// 对比资产拓扑在两个时间点之间的差异
+	g.POST("/relation/graph/diff", h.Capability("资产拓扑差异对比", "diff_relation_graph").
+		Group("资产仓库/关联关系").
+		Handle(ginx.WrapBody[DiffGraphReq](h.DiffGraph)),
+	)
+
+	// 导出资产拓扑图 (GraphML / DOT / JSON Graph)
+	g.POST("/relation/graph/export", h.Capability("导出资产拓扑图", "export_relation_graph").
+		Group("资产仓库/关联关系").
+		Handle(ginx.WrapFileBody[ExportGraphReq](h.ExportGraph, systemErrorResult)),
+	)
+
 	// ==========================================
 	// 3. 资产检索与安全字段接口
 	// ==========================================
@@ -176,7 +195,12 @@ func (h *Handler) DetailResource(ctx *gin.Context, req DetailResourceReq) (ginx.
 		return systemErrorResult, err
 	}
 
-	resp, err := h.svc.FindResourceById(ctx, fields, req.ID)
+	var resp domain.Resource
+	if req.AsOf > 0 {
+		resp, err = h.svc.FindResourceByIdAsOf(ctx, fields, req.ID, req.AsOf)
+	} else {
+		resp, err = h.svc.FindResourceById(ctx, fields, req.ID)
+	}
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -199,12 +223,27 @@ func (h *Handler) SetCustomField(ctx *gin.Context, req SetCustomFieldReq) (ginx.
 }
 
 func (h *Handler) ListResource(ctx *gin.Context, req ListResourceReq) (ginx.Result, error) {
+	// 视图筛选基于当前数据，不支持与历史时间点查询组合
+	if req.ViewID > 0 && req.AsOf > 0 {
+		return systemErrorResult, errs.ValidationError.WithMsg("按视图查询时不支持指定 as_of")
+	}
+
 	fields, err := h.attrSvc.SearchAttributeFieldsByModelUid(ctx, req.ModelUid)
 	if err != nil {
 		return systemErrorResult, err
 	}
 
-	resp, total, err := h.svc.ListResource(ctx, fields, req.ModelUid, req.Offset, req.Limit)
+	var (
+		resp  []domain.Resource
+		total int64
+	)
+	if req.ViewID > 0 {
+		resp, total, err = h.listResourceByView(ctx, fields, req)
+	} else if req.AsOf > 0 {
+		resp, total, err = h.svc.ListResourceAsOf(ctx, fields, req.ModelUid, req.AsOf, req.Offset, req.Limit)
+	} else {
+		resp, total, err = h.svc.ListResource(ctx, fields, req.ModelUid, req.Offset, req.Limit)
+	}
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -227,6 +266,25 @@ func (h *Handler) ListResource(ctx *gin.Context, req ListResourceReq) (ginx.Resu
 	}, nil
 }
 
+// listResourceByView 按保存视图的筛选条件、展示字段及排序查询资产列表
+func (h *Handler) listResourceByView(ctx *gin.Context, fields []string, req ListResourceReq) ([]domain.Resource,
+	int64, error) {
+	view, err := h.viewSvc.FindView(ctx, req.ViewID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if view.ModelUID != req.ModelUid {
+		return nil, 0, errs.ValidationError.WithMsg("视图不属于该模型")
+	}
+
+	// NOTE: 视图保存后模型字段可能被删除或设置为安全字段，只保留当前可展示的字段
+	if len(view.Fields) > 0 {
+		fields = lo.Intersect(fields, view.Fields)
+	}
+
+	return h.svc.ListResourcesByQuery(ctx, fields, view.Query(), req.Offset, req.Limit)
+}
+
 func (h *Handler) UpdateResource(ctx *gin.Context, req UpdateResourceReq) (ginx.Result, error) {
 	resource := h.toDomainUpdate(req)
 	t, err := h.svc.UpdateResource(ctx, resource)
@@ -333,7 +391,7 @@ func (h *Handler) FindAllGraph(ctx *gin.Context, req ListDiagramReq) (ginx.Resul
 	if maxDepth <= 0 {
 		maxDepth = 3
 	}
-	graph, err := h.RRSvc.ListRecursiveDiagram(ctx, req.ModelUid, req.ResourceId, maxDepth)
+	graph, err := h.recursiveDiagram(ctx, req.ModelUid, req.ResourceId, maxDepth, req.AsOf)
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -361,7 +419,7 @@ func (h *Handler) FindAllGraph(ctx *gin.Context, req ListDiagramReq) (ginx.Resul
 	ids := append(srcId, dstId...)
 
 	// 查询节点信息
-	rs, err := h.svc.ListResourceByIds(ctx, []string{"name"}, ids)
+	rs, err := h.listGraphNodes(ctx, ids, req.AsOf)
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -526,13 +584,129 @@ func (h *Handler) FindRightGraph(ctx *gin.Context, req ListDiagramReq) (ginx.Res
 	}, nil
 }
 
+// ExportGraph 按标准图格式导出资产拓扑，供 Gephi、draw.io 等外部工具分析使用
+func (h *Handler) ExportGraph(ctx *gin.Context, req ExportGraphReq) (ginx.File, error) {
+	format, err := graphx.ParseFormat(req.Format)
+	if err != nil {
+		return ginx.File{}, errs.ValidationError.WithMsg(err.Error())
+	}
+	if req.ModelUid == "" || req.ResourceId <= 0 {
+		return ginx.File{}, errs.ValidationError.WithMsg("model_uid 与 resource_id 不能为空")
+	}
+
+	// 1. 按方向查询关联关系，与拓扑图接口保持一致
+	rrs, err := h.exportGraphRelations(ctx, req)
+	if err != nil {
+		return ginx.File{}, err
+	}
+
+	// 2. 查询所有节点信息（包含根节点），只加载需要导出的字段
+	ids := lo.Uniq(append(lo.FlatMap(rrs, func(src domain.ResourceRelation, _ int) []int64 {
+		return []int64{src.SourceResourceID, src.TargetResourceID}
+	}), req.ResourceId))
+	fields := lo.Uniq(append([]string{"name"}, req.Fields...))
+	rs, err := h.svc.ListResourceByIds(ctx, fields, ids)
+	if err != nil {
+		return ginx.File{}, err
+	}
+
+	// 3. 剔除安全字段，导出文件不允许携带敏感数据
+	modelUids := lo.Uniq(lo.Map(rs, func(src domain.Resource, _ int) string {
+		return src.ModelUID
+	}))
+	secureFields, err := h.attrSvc.SearchAttributeFieldsBySecure(ctx, modelUids)
+	if err != nil {
+		return ginx.File{}, err
+	}
+	models, err := h.graphModels(ctx, rs, req.ModelUid)
+	if err != nil {
+		return ginx.File{}, err
+	}
+	modelNames := lo.SliceToMap(models, func(src GraphModel) (string, string) {
+		return src.ModelUID, src.ModelName
+	})
+
+	nodes := lo.Map(rs, func(src domain.Resource, _ int) graphx.Node {
+		attrs := map[string]any{
+			"model_uid":  src.ModelUID,
+			"model_name": modelNames[src.ModelUID],
+		}
+		for _, field := range req.Fields {
+			if field == "name" || contains(secureFields[src.ModelUID], field) {
+				continue
+			}
+			if val, ok := src.Data[field]; ok {
+				attrs[field] = val
+			}
+		}
+		return graphx.Node{
+			ID:    strconv.FormatInt(src.ID, 10),
+			Label: src.Name,
+			Attrs: attrs,
+		}
+	})
+
+	edges := lo.Map(rrs, func(src domain.ResourceRelation, _ int) graphx.Edge {
+		return graphx.Edge{
+			Source: strconv.FormatInt(src.SourceResourceID, 10),
+			Target: strconv.FormatInt(src.TargetResourceID, 10),
+			Label:  src.RelationName,
+			Attrs: map[string]any{
+				"relation_type_uid": src.RelationTypeUID,
+				"source_model_uid":  src.SourceModelUID,
+				"target_model_uid":  src.TargetModelUID,
+			},
+		}
+	})
+
+	data, err := graphx.Marshal(graphx.Graph{
+		ID:       fmt.Sprintf("%s-%d", req.ModelUid, req.ResourceId),
+		Label:    req.ResourceName,
+		Directed: true,
+		Nodes:    nodes,
+		Edges:    edges,
+	}.Dedup(), format)
+	if err != nil {
+		return ginx.File{}, err
+	}
+
+	return ginx.File{
+		Name:        fmt.Sprintf("%s_%d_graph%s", req.ModelUid, req.ResourceId, format.Extension()),
+		ContentType: format.ContentType(),
+		Data:        data,
+	}, nil
+}
+
+func (h *Handler) exportGraphRelations(ctx *gin.Context, req ExportGraphReq) ([]domain.ResourceRelation, error) {
+	switch req.Direction {
+	case "", GraphDirectionAll:
+		maxDepth := req.MaxDepth
+		if maxDepth <= 0 {
+			maxDepth = 3
+		}
+		graph, err := h.RRSvc.ListRecursiveDiagram(ctx, req.ModelUid, req.ResourceId, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		return append(graph.SRC, graph.DST...), nil
+	case GraphDirectionLeft:
+		rrs, _, err := h.RRSvc.ListDstResources(ctx, req.ModelUid, req.ResourceId)
+		return rrs, err
+	case GraphDirectionRight:
+		rrs, _, err := h.RRSvc.ListSrcResources(ctx, req.ModelUid, req.ResourceId)
+		return rrs, err
+	default:
+		return nil, errs.ValidationError.WithMsg(fmt.Sprintf("不支持的拓扑方向: %s", req.Direction))
+	}
+}
+
 func (h *Handler) FindDiagram(ctx *gin.Context, req ListDiagramReq) (ginx.Result, error) {
 	// 查询资产关联上下级拓扑（支持多级递归，默认递归3层）
 	maxDepth := req.MaxDepth
 	if maxDepth <= 0 {
 		maxDepth = 3
 	}
-	diagram, err := h.RRSvc.ListRecursiveDiagram(ctx, req.ModelUid, req.ResourceId, maxDepth)
+	diagram, err := h.recursiveDiagram(ctx, req.ModelUid, req.ResourceId, maxDepth, req.AsOf)
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -561,7 +735,7 @@ func (h *Handler) FindDiagram(ctx *gin.Context, req ListDiagramReq) (ginx.Result
 	ids := append(srcId, dstId...)
 
 	// 查询节点信息
-	rs, err := h.svc.ListResourceByIds(ctx, []string{"name"}, ids)
+	rs, err := h.listGraphNodes(ctx, ids, req.AsOf)
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -613,39 +787,43 @@ func (h *Handler) ListResourceByIds(ctx *gin.Context, req ListResourceByIdsReq)
 }
 
 func (h *Handler) Search(ctx *gin.Context, req SearchReq) (ginx.Result, error) {
-	search, err := h.svc.Search(ctx, req.Text)
-	if err != nil {
-		return systemErrorResult, err
-	}
-
-	modelUids := slice.Map(search, func(idx int, src domain.SearchResource) string {
-		return src.ModelUid
+	groups, err := h.svc.Search(ctx, domain.SearchQuery{
+		Terms:    domain.ParseSearchText(req.Text),
+		ModelUid: req.ModelUid,
+		Offset:   req.Offset,
+		Limit:    req.Limit,
 	})
-
-	fields, err := h.attrSvc.SearchAttributeFieldsBySecure(ctx, modelUids)
 	if err != nil {
 		return systemErrorResult, err
 	}
 
 	return ginx.Result{
-		Data: slice.Map(search, func(idx int, src domain.SearchResource) RetrieveSearchResources {
-			val, ok := fields[src.ModelUid]
-			if ok {
-				for _, name := range src.Data {
-					for key := range name {
-						if contains(val, key) {
-							name[key] = ""
-						}
-					}
-				}
-			}
+		Data: slice.Map(groups, func(idx int, src domain.SearchGroup) RetrieveSearchResources {
 			return RetrieveSearchResources{
-				ModelUid: src.ModelUid,
-				Total:    src.Total,
-				Data:     src.Data,
+				ModelUid:  src.ModelUid,
+				Total:     src.Total,
+				Truncated: src.Truncated,
+				Data: slice.Map(src.Hits, func(idx int, hit domain.SearchHit) mongox.MapStr {
+					data := make(mongox.MapStr, len(hit.Resource.Data)+2)
+					for k, v := range hit.Resource.Data {
+						data[k] = v
+					}
+					data["id"] = hit.Resource.ID
+					data["model_uid"] = hit.Resource.ModelUID
+					return data
+				}),
+				Hits: slice.Map(src.Hits, func(idx int, hit domain.SearchHit) SearchHit {
+					return SearchHit{
+						ID:    hit.Resource.ID,
+						Score: hit.Score,
+						Highlights: slice.Map(hit.Highlights, func(idx int, hl domain.SearchHighlight) SearchHighlight {
+							return SearchHighlight{Field: hl.Field, Snippet: hl.Snippet}
+						}),
+					}
+				}),
 			}
 		}),
-	}, err
+	}, nil
 }
 
 func (h *Handler) DeleteResource(ctx *gin.Context, req DeleteResourceReq) (ginx.Result, error) {
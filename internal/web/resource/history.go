@@ -0,0 +1,151 @@
+package web
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/errs"
+	"github.com/Duke1616/ecmdb/pkg/ginx"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+)
+
+// recursiveDiagram 查询多级关联拓扑，asOf 大于 0 时查询指定时间点的历史拓扑
+func (h *Handler) recursiveDiagram(ctx *gin.Context, modelUid string, id int64, maxDepth int,
+	asOf int64) (domain.ResourceDiagram, error) {
+	if asOf > 0 {
+		return h.RRSvc.ListRecursiveDiagramAsOf(ctx, modelUid, id, maxDepth, asOf)
+	}
+	return h.RRSvc.ListRecursiveDiagram(ctx, modelUid, id, maxDepth)
+}
+
+// listGraphNodes 查询拓扑节点信息，asOf 大于 0 时返回节点在指定时间点的名称
+func (h *Handler) listGraphNodes(ctx *gin.Context, ids []int64, asOf int64) ([]domain.Resource, error) {
+	if asOf > 0 {
+		return h.svc.ListResourceByIdsAsOf(ctx, []string{"name"}, ids, asOf)
+	}
+	return h.svc.ListResourceByIds(ctx, []string{"name"}, ids)
+}
+
+func (h *Handler) DiffGraph(ctx *gin.Context, req DiffGraphReq) (ginx.Result, error) {
+	maxDepth := req.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 3
+	}
+	to := req.To
+	if to <= 0 {
+		to = time.Now().UnixMilli()
+	}
+	if req.From <= 0 || req.From >= to {
+		return systemErrorResult, errs.ValidationError.WithMsg(fmt.Sprintf("对比时间区间不合法: from=%d, to=%d", req.From, to))
+	}
+
+	var (
+		eg            errgroup.Group
+		before, after domain.Topology
+	)
+	eg.Go(func() error {
+		var err error
+		before, err = h.topologyAsOf(ctx, req.ModelUid, req.ResourceId, maxDepth, req.From)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		after, err = h.topologyAsOf(ctx, req.ModelUid, req.ResourceId, maxDepth, to)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return systemErrorResult, err
+	}
+
+	// 安全字段不参与差异对比，避免通过差异结果泄露明文
+	if err := h.omitSecureFields(ctx, &before, &after); err != nil {
+		return systemErrorResult, err
+	}
+
+	diff := domain.DiffTopology(before, after)
+	toResourceVo := func(src domain.Resource, _ int) Resource {
+		return Resource{
+			ID:       src.ID,
+			Name:     src.Name,
+			ModelUID: src.ModelUID,
+			Data:     src.Data,
+		}
+	}
+	toRelationVo := func(src domain.ResourceRelation, _ int) ResourceRelation {
+		return h.toResourceRelationVo(src)
+	}
+
+	return ginx.Result{
+		Data: RetrieveGraphDiff{
+			From:             req.From,
+			To:               to,
+			AddedResources:   lo.Map(diff.AddedResources, toResourceVo),
+			RemovedResources: lo.Map(diff.RemovedResources, toResourceVo),
+			ChangedResources: lo.Map(diff.ChangedResources, func(src domain.ResourceDiff, _ int) ResourceDiff {
+				return ResourceDiff{
+					ResourceID: src.ResourceID,
+					ModelUID:   src.ModelUID,
+					Name:       src.Name,
+					Fields: lo.Map(src.Fields, func(f domain.FieldDiff, _ int) FieldDiff {
+						return FieldDiff{Field: f.Field, Before: f.Before, After: f.After}
+					}),
+				}
+			}),
+			AddedRelations:   lo.Map(diff.AddedRelations, toRelationVo),
+			RemovedRelations: lo.Map(diff.RemovedRelations, toRelationVo),
+		},
+		Msg: "对比资产拓扑差异成功",
+	}, nil
+}
+
+// topologyAsOf 还原以指定资产为根的子图在 asOf 时刻的状态，包含根节点本身
+func (h *Handler) topologyAsOf(ctx *gin.Context, modelUid string, id int64, maxDepth int,
+	asOf int64) (domain.Topology, error) {
+	diagram, err := h.RRSvc.ListRecursiveDiagramAsOf(ctx, modelUid, id, maxDepth, asOf)
+	if err != nil {
+		return domain.Topology{}, err
+	}
+
+	rrs := append(diagram.SRC, diagram.DST...)
+	ids := lo.Uniq(append(lo.FlatMap(rrs, func(src domain.ResourceRelation, _ int) []int64 {
+		return []int64{src.SourceResourceID, src.TargetResourceID}
+	}), id))
+
+	rs, err := h.svc.ListResourceByIdsAsOf(ctx, nil, ids, asOf)
+	if err != nil {
+		return domain.Topology{}, err
+	}
+
+	return domain.Topology{
+		Resources: rs,
+		Relations: rrs,
+	}, nil
+}
+
+func (h *Handler) omitSecureFields(ctx *gin.Context, topologies ...*domain.Topology) error {
+	modelUids := lo.Uniq(lo.FlatMap(topologies, func(t *domain.Topology, _ int) []string {
+		return lo.Map(t.Resources, func(src domain.Resource, _ int) string {
+			return src.ModelUID
+		})
+	}))
+	if len(modelUids) == 0 {
+		return nil
+	}
+
+	secureFields, err := h.attrSvc.SearchAttributeFieldsBySecure(ctx, modelUids)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range topologies {
+		for i, r := range t.Resources {
+			if fields, ok := secureFields[r.ModelUID]; ok {
+				t.Resources[i].Data = lo.OmitByKeys(r.Data, fields)
+			}
+		}
+	}
+	return nil
+}
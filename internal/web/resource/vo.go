@@ -18,6 +18,7 @@ type UpdateResourceReq struct {
 type DetailResourceReq struct {
 	ModelUid string `json:"model_uid"`
 	ID       int64  `json:"id"`
+	AsOf     int64  `json:"as_of"` // 历史时间点（毫秒时间戳），为空时查询当前状态
 }
 
 type SetCustomFieldReq struct {
@@ -34,6 +35,8 @@ type Page struct {
 type ListResourceReq struct {
 	Page
 	ModelUid string `json:"model_uid"`
+	AsOf     int64  `json:"as_of"`   // 历史时间点（毫秒时间戳），为空时查询当前状态
+	ViewID   int64  `json:"view_id"` // 保存视图 ID，按视图的筛选条件、展示字段及排序查询
 }
 
 type ListResourceByIdsReq struct {
@@ -69,6 +72,59 @@ type ListDiagramReq struct {
 	ResourceId   int64  `json:"resource_id"`
 	ResourceName string `json:"resource_name"`
 	MaxDepth     int    `json:"max_depth"`
+	AsOf         int64  `json:"as_of"` // 历史时间点（毫秒时间戳），为空时查询当前拓扑
+}
+
+// GraphDirection 拓扑导出方向
+type GraphDirection string
+
+const (
+	GraphDirectionAll   GraphDirection = "all"
+	GraphDirectionLeft  GraphDirection = "left"
+	GraphDirectionRight GraphDirection = "right"
+)
+
+// ExportGraphReq 导出资产拓扑图请求
+type ExportGraphReq struct {
+	ModelUid     string         `json:"model_uid"`
+	ResourceId   int64          `json:"resource_id"`
+	ResourceName string         `json:"resource_name"`
+	MaxDepth     int            `json:"max_depth"`
+	Direction    GraphDirection `json:"direction"` // all 对应 FindAllGraph, left / right 对应单层拓展
+	Format       string         `json:"format"`    // graphml / dot / json
+	Fields       []string       `json:"fields"`    // 节点需要导出的资产字段, 安全字段始终会被剔除
+}
+
+// DiffGraphReq 对比资产拓扑在两个时间点之间的差异
+type DiffGraphReq struct {
+	ModelUid   string `json:"model_uid"`
+	ResourceId int64  `json:"resource_id"`
+	MaxDepth   int    `json:"max_depth"`
+	From       int64  `json:"from"` // 起始时间点（毫秒时间戳）
+	To         int64  `json:"to"`   // 结束时间点（毫秒时间戳），为空时取当前时间
+}
+
+type FieldDiff struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type ResourceDiff struct {
+	ResourceID int64       `json:"resource_id"`
+	ModelUID   string      `json:"model_uid"`
+	Name       string      `json:"name"`
+	Fields     []FieldDiff `json:"fields"`
+}
+
+type RetrieveGraphDiff struct {
+	From             int64              `json:"from"`
+	To               int64              `json:"to"`
+	AddedResources   []Resource         `json:"added_resources"`
+	RemovedResources []Resource         `json:"removed_resources"`
+	ChangedResources []ResourceDiff     `json:"changed_resources"`
+	AddedRelations   []ResourceRelation `json:"added_relations"`
+	RemovedRelations []ResourceRelation `json:"removed_relations"`
 }
 
 type ResourceRelation struct {
@@ -120,9 +176,9 @@ type Line struct {
 }
 
 type SearchReq struct {
-	Text    string   `json:"text"`
-	OrText  []string `json:"or_text"`
-	AndText []string `json:"and_text"`
+	Page
+	Text     string `json:"text"`      // 空白分隔多个检索词，支持 field:value 限定字段及 * 通配符，例如 ip:10.1.*
+	ModelUid string `json:"model_uid"` // 指定模型时只返回该模型分组，配合 offset / limit 进行组内翻页
 }
 
 type FindSecureReq struct {
@@ -143,9 +199,22 @@ type RetrieveResources struct {
 }
 
 type RetrieveSearchResources struct {
-	ModelUid string          `json:"model_uid"`
-	Total    int             `json:"total"`
-	Data     []mongox.MapStr `json:"data"`
+	ModelUid  string          `json:"model_uid"`
+	Total     int             `json:"total"`
+	Truncated bool            `json:"truncated"` // 命中数量超出检索上限，total 只是下限
+	Data      []mongox.MapStr `json:"data"`
+	Hits      []SearchHit     `json:"hits"` // 与 Data 一一对应的相关度与高亮信息
+}
+
+type SearchHit struct {
+	ID         int64             `json:"id"`
+	Score      float64           `json:"score"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
 }
 
 type CreateResourceRelationReq struct {
@@ -0,0 +1,216 @@
+package web
+
+import (
+	"github.com/Duke1616/ecmdb/internal/domain"
+	service "github.com/Duke1616/ecmdb/internal/service/view"
+	"github.com/Duke1616/ecmdb/pkg/ginx"
+	"github.com/Duke1616/eiam/pkg/ctxutil"
+	"github.com/Duke1616/eiam/pkg/web/capability"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc service.Service
+	capability.IRegistry
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{
+		svc:       svc,
+		IRegistry: capability.NewRegistry("cmdb", "view", "资产仓库/视图"),
+	}
+}
+
+// PrivateRoutes 注册保存视图相关的私有路由
+func (h *Handler) PrivateRoutes(server *gin.Engine) {
+	g := server.Group("/api/view")
+
+	// 创建视图
+	g.POST("/create", h.Capability("创建视图", "add").
+		Needs("cmdb:attribute:view").
+		Handle(ginx.WrapBody[CreateViewReq](h.CreateView)),
+	)
+
+	// 修改视图
+	g.POST("/update", h.Capability("修改视图", "edit").
+		Handle(ginx.WrapBody[UpdateViewReq](h.UpdateView)),
+	)
+
+	// 删除视图
+	g.POST("/delete", h.Capability("删除视图", "delete").
+		Handle(ginx.WrapBody[DeleteViewReq](h.DeleteView)),
+	)
+
+	// 查询视图详情
+	g.POST("/detail", h.Capability("视图详情", "get").
+		Handle(ginx.WrapBody[DetailViewReq](h.DetailView)),
+	)
+
+	// 查询模型下可见的视图列表
+	g.POST("/list", h.Capability("视图列表", "view").
+		Handle(ginx.WrapBody[ListViewReq](h.ListViews)),
+	)
+
+	// 设置默认视图
+	g.POST("/default/set", h.Capability("设置默认视图", "set_default").
+		Needs("cmdb:view:view").
+		Handle(ginx.WrapBody[SetDefaultViewReq](h.SetDefaultView)),
+	)
+
+	// 查询默认视图
+	g.POST("/default/get", h.Capability("查询默认视图", "get_default").
+		NoSync().
+		Handle(ginx.WrapBody[GetDefaultViewReq](h.GetDefaultView)),
+	)
+}
+
+func (h *Handler) CreateView(ctx *gin.Context, req CreateViewReq) (ginx.Result, error) {
+	id, err := h.svc.CreateView(ctx, domain.SavedView{
+		ModelUID:     req.ModelUid,
+		Name:         req.Name,
+		Scope:        domain.ViewScope(req.Scope),
+		FilterGroups: toFilterGroupsDomain(req.FilterGroups),
+		Fields:       req.Fields,
+		Sorts:        toSortsDomain(req.Sorts),
+	})
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: id,
+		Msg:  "创建视图成功",
+	}, nil
+}
+
+func (h *Handler) UpdateView(ctx *gin.Context, req UpdateViewReq) (ginx.Result, error) {
+	count, err := h.svc.UpdateView(ctx, domain.SavedView{
+		ID:           req.Id,
+		Name:         req.Name,
+		Scope:        domain.ViewScope(req.Scope),
+		FilterGroups: toFilterGroupsDomain(req.FilterGroups),
+		Fields:       req.Fields,
+		Sorts:        toSortsDomain(req.Sorts),
+	})
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: count,
+		Msg:  "修改视图成功",
+	}, nil
+}
+
+func (h *Handler) DeleteView(ctx *gin.Context, req DeleteViewReq) (ginx.Result, error) {
+	count, err := h.svc.DeleteView(ctx, req.Id)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: count,
+		Msg:  "删除视图成功",
+	}, nil
+}
+
+func (h *Handler) DetailView(ctx *gin.Context, req DetailViewReq) (ginx.Result, error) {
+	view, err := h.svc.FindView(ctx, req.Id)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: h.toView(ctx, view),
+	}, nil
+}
+
+func (h *Handler) ListViews(ctx *gin.Context, req ListViewReq) (ginx.Result, error) {
+	views, err := h.svc.ListViews(ctx, req.ModelUid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Data: RetrieveViews{
+			Views: slice.Map(views, func(idx int, src domain.SavedView) View {
+				return h.toView(ctx, src)
+			}),
+		},
+	}, nil
+}
+
+func (h *Handler) SetDefaultView(ctx *gin.Context, req SetDefaultViewReq) (ginx.Result, error) {
+	if err := h.svc.SetDefaultView(ctx, req.ModelUid, req.Id); err != nil {
+		return systemErrorResult, err
+	}
+
+	return ginx.Result{
+		Msg: "设置默认视图成功",
+	}, nil
+}
+
+// GetDefaultView 查询默认视图，未设置时 Data 为空
+func (h *Handler) GetDefaultView(ctx *gin.Context, req GetDefaultViewReq) (ginx.Result, error) {
+	view, ok, err := h.svc.FindDefaultView(ctx, req.ModelUid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	if !ok {
+		return ginx.Result{}, nil
+	}
+
+	return ginx.Result{
+		Data: h.toView(ctx, view),
+	}, nil
+}
+
+func (h *Handler) toView(ctx *gin.Context, src domain.SavedView) View {
+	return View{
+		Id:       src.ID,
+		ModelUid: src.ModelUID,
+		Name:     src.Name,
+		OwnerId:  src.OwnerID,
+		Scope:    string(src.Scope),
+		FilterGroups: slice.Map(src.FilterGroups, func(idx int, g domain.FilterGroup) FilterGroup {
+			return FilterGroup{
+				Filters: slice.Map(g.Filters, func(idx int, f domain.FilterCondition) FilterCondition {
+					return FilterCondition{
+						FieldUID: f.FieldUID,
+						Operator: string(f.Operator),
+						Value:    f.Value,
+					}
+				}),
+			}
+		}),
+		Fields: src.Fields,
+		Sorts: slice.Map(src.Sorts, func(idx int, s domain.ResourceSort) Sort {
+			return Sort{Field: s.Field, Desc: s.Desc}
+		}),
+		IsDefault: src.IsDefault,
+		Editable:  src.EditableBy(ctxutil.GetUserID(ctx).Int64()),
+		Ctime:     src.Ctime,
+		Utime:     src.Utime,
+	}
+}
+
+func toFilterGroupsDomain(groups []FilterGroup) []domain.FilterGroup {
+	return slice.Map(groups, func(idx int, src FilterGroup) domain.FilterGroup {
+		return domain.FilterGroup{
+			Filters: slice.Map(src.Filters, func(idx int, f FilterCondition) domain.FilterCondition {
+				return domain.FilterCondition{
+					FieldUID: f.FieldUID,
+					Operator: domain.Operator(f.Operator),
+					Value:    f.Value,
+				}
+			}),
+		}
+	})
+}
+
+func toSortsDomain(sorts []Sort) []domain.ResourceSort {
+	return slice.Map(sorts, func(idx int, src Sort) domain.ResourceSort {
+		return domain.ResourceSort{Field: src.Field, Desc: src.Desc}
+	})
+}
@@ -0,0 +1,76 @@
+package web
+
+type FilterCondition struct {
+	FieldUID string      `json:"field_uid"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// FilterGroup 筛选条件组 (组内 AND，组间 OR)
+type FilterGroup struct {
+	Filters []FilterCondition `json:"filters"`
+}
+
+type Sort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+type CreateViewReq struct {
+	ModelUid     string        `json:"model_uid"`
+	Name         string        `json:"name"`
+	Scope        string        `json:"scope"` // private / shared，默认 private
+	FilterGroups []FilterGroup `json:"filter_groups"`
+	Fields       []string      `json:"fields"` // 展示字段，为空时展示模型全部字段
+	Sorts        []Sort        `json:"sorts"`
+}
+
+type UpdateViewReq struct {
+	Id           int64         `json:"id"`
+	Name         string        `json:"name"`
+	Scope        string        `json:"scope"`
+	FilterGroups []FilterGroup `json:"filter_groups"`
+	Fields       []string      `json:"fields"`
+	Sorts        []Sort        `json:"sorts"`
+}
+
+type DeleteViewReq struct {
+	Id int64 `json:"id"`
+}
+
+type DetailViewReq struct {
+	Id int64 `json:"id"`
+}
+
+type ListViewReq struct {
+	ModelUid string `json:"model_uid"`
+}
+
+// SetDefaultViewReq 设置默认视图，Id 为 0 时取消默认视图
+type SetDefaultViewReq struct {
+	ModelUid string `json:"model_uid"`
+	Id       int64  `json:"id"`
+}
+
+type GetDefaultViewReq struct {
+	ModelUid string `json:"model_uid"`
+}
+
+type View struct {
+	Id           int64         `json:"id"`
+	ModelUid     string        `json:"model_uid"`
+	Name         string        `json:"name"`
+	OwnerId      int64         `json:"owner_id"`
+	Scope        string        `json:"scope"`
+	FilterGroups []FilterGroup `json:"filter_groups"`
+	Fields       []string      `json:"fields"`
+	Sorts        []Sort        `json:"sorts"`
+	IsDefault    bool          `json:"is_default"`
+	Editable     bool          `json:"editable"` // 当前用户是否可以修改、删除
+	Ctime        int64         `json:"ctime"`
+	Utime        int64         `json:"utime"`
+}
+
+type RetrieveViews struct {
+	Views []View `json:"views"`
+}
@@ -0,0 +1,65 @@
+package ioc
+
+import (
+	"fmt"
+	"time"
+
+	dataioEvent "github.com/Duke1616/ecmdb/internal/event/dataio"
+	dataioSvc "github.com/Duke1616/ecmdb/internal/service/dataio"
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func InitImportJobTask(svc dataioSvc.IDataIOService) *dataioEvent.ImportJobTask {
+	type Config struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("dataio.import", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	// 未配置时默认每 3 秒领取一次待执行的导入任务
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 3 * time.Second
+	}
+
+	return dataioEvent.NewImportJobTask(svc, cfg.PollInterval)
+}
+
+func InitExportJobTask(svc dataioSvc.IDataIOService) *dataioEvent.ExportJobTask {
+	type Config struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("dataio.export", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	// 未配置时默认每 3 秒领取一次待执行的导出任务
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 3 * time.Second
+	}
+
+	return dataioEvent.NewExportJobTask(svc, cfg.PollInterval)
+}
+
+func InitScheduledExportTask(svc dataioSvc.IDataIOService, client *clientv3.Client) *dataioEvent.ScheduledExportTask {
+	type Config struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("dataio.schedule", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	// 未配置时默认每 30 秒检查一次到期的定时导出
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+
+	return dataioEvent.NewScheduledExportTask(svc, client, cfg.PollInterval)
+}
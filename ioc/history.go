@@ -0,0 +1,34 @@
+package ioc
+
+import (
+	"fmt"
+	"time"
+
+	resourceEvent "github.com/Duke1616/ecmdb/internal/event/resource"
+	resourceSvc "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func InitSnapshotTask(svc resourceSvc.Service, client *clientv3.Client) *resourceEvent.SnapshotTask {
+	type Config struct {
+		SnapshotInterval time.Duration `mapstructure:"snapshot_interval"`
+		Retention        time.Duration `mapstructure:"retention"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("history", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	// 未配置时默认每天生成一次快照
+	if cfg.SnapshotInterval <= 0 {
+		cfg.SnapshotInterval = 24 * time.Hour
+	}
+	// 未配置时默认保留 180 天的历史数据
+	if cfg.Retention <= 0 {
+		cfg.Retention = 180 * 24 * time.Hour
+	}
+
+	return resourceEvent.NewSnapshotTask(svc, client, cfg.SnapshotInterval, cfg.Retention)
+}
@@ -0,0 +1,84 @@
+package ioc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Duke1616/ecmdb/internal/domain"
+	"github.com/Duke1616/ecmdb/internal/event"
+	pluginEvent "github.com/Duke1616/ecmdb/internal/event/plugin"
+	pluginSvc "github.com/Duke1616/ecmdb/internal/service/plugin"
+	"github.com/Duke1616/ecmdb/pkg/mqx"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
+	"github.com/ecodeclub/mq-api"
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func InitPluginHealthCheckTask(svc pluginSvc.Service, client *clientv3.Client) *pluginEvent.HealthCheckTask {
+	type Config struct {
+		Interval time.Duration `mapstructure:"interval"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("plugin.health", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	// 未配置时默认每 30 秒探测一次
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+
+	return pluginEvent.NewHealthCheckTask(svc, client, cfg.Interval)
+}
+
+// examplePluginTokenSecret 示例配置中的动作令牌签名密钥，只用于占位，不允许直接使用
+const examplePluginTokenSecret = "change-me-plugin-token-secret"
+
+func InitPluginTokenSigner() *pluginx.TokenSigner {
+	type Config struct {
+		Secret string        `mapstructure:"secret"`
+		TTL    time.Duration `mapstructure:"ttl"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("plugin.token", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	if cfg.Secret == "" {
+		panic(fmt.Errorf("plugin token secret is required"))
+	}
+	if cfg.Secret == examplePluginTokenSecret {
+		panic(fmt.Errorf("plugin token secret must not use the example value, please generate a random secret"))
+	}
+
+	// 未配置有效期时使用默认的 15 分钟
+	return pluginx.NewTokenSigner(cfg.Secret, cfg.TTL)
+}
+
+func InitPluginAuditConfig(q mq.MQ) pluginSvc.AuditConfig {
+	type Config struct {
+		Retention time.Duration `mapstructure:"retention"`
+		Stream    bool          `mapstructure:"stream"`
+	}
+
+	var cfg Config
+	if err := viper.UnmarshalKey("plugin.audit", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	// 未配置保留时长时默认保留 90 天
+	audit := pluginSvc.AuditConfig{Retention: cfg.Retention}
+	if !cfg.Stream {
+		return audit
+	}
+
+	producer, err := mqx.NewGeneralProducer[domain.PluginAuditLog](q, event.PluginActionAuditName)
+	if err != nil {
+		panic(fmt.Errorf("init plugin audit producer: %w", err))
+	}
+	audit.Producer = producer
+	return audit
+}
@@ -1,8 +1,17 @@
 package ioc
 
 import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	pluginSvc "github.com/Duke1616/ecmdb/internal/service/plugin"
+	pluginx "github.com/Duke1616/ecmdb/pkg/plugin"
 	"github.com/Duke1616/eiam/pkg/web/capability"
 	"github.com/Duke1616/eiam/pkg/web/sdk"
+	"github.com/samber/lo"
 )
 
 // InitPolicySDK 初始化 EIAM 鉴权 SDK
@@ -19,3 +28,146 @@ func InitPermSyncer() capability.Syncer {
 func InitProviders() []capability.PermissionProvider {
 	return nil
 }
+
+// InitPluginPermissions 基于 EIAM 登记与校验插件动作声明的权限
+func InitPluginPermissions(sdk *sdk.SDK, syncer capability.Syncer) pluginSvc.PermissionChecker {
+	return &pluginPermissions{
+		sdk:       sdk,
+		syncer:    syncer,
+		resources: make(map[string]*permissionResource),
+	}
+}
+
+type pluginPermissions struct {
+	sdk    *sdk.SDK
+	syncer capability.Syncer
+
+	mu        sync.Mutex
+	resources map[string]*permissionResource // 应用:资源 -> 已登记的权限
+}
+
+// permissionResource 同一权限资源下已登记的插件动作权限
+type permissionResource struct {
+	app   string
+	res   string
+	codes map[string]*permissionCode // 动作 -> 权限
+}
+
+// permissionCode 多个插件可以声明同一权限，全部声明方注销后才从权限中心移除
+type permissionCode struct {
+	name   string
+	owners map[string]struct{} // 声明该权限的插件 ID
+}
+
+func (p *pluginPermissions) Register(ctx context.Context, permissions []pluginx.ActionPermission) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	touched := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		app, res, code, err := pluginx.ParsePluginPermission(permission.Code)
+		if err != nil {
+			return err
+		}
+
+		key := app + ":" + res
+		resource, ok := p.resources[key]
+		if !ok {
+			resource = &permissionResource{app: app, res: res, codes: make(map[string]*permissionCode)}
+			p.resources[key] = resource
+		}
+		declared, ok := resource.codes[code]
+		if !ok {
+			declared = &permissionCode{owners: make(map[string]struct{})}
+			resource.codes[code] = declared
+		}
+		declared.name = permission.Name
+		declared.owners[permission.PluginID] = struct{}{}
+		touched = append(touched, key)
+	}
+	return p.sync(ctx, touched)
+}
+
+func (p *pluginPermissions) Unregister(ctx context.Context, permissions []pluginx.ActionPermission) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	touched := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		app, res, code, err := pluginx.ParsePermission(permission.Code)
+		if err != nil {
+			return err
+		}
+
+		key := app + ":" + res
+		resource, ok := p.resources[key]
+		if !ok {
+			continue
+		}
+		declared, ok := resource.codes[code]
+		if !ok {
+			continue
+		}
+		delete(declared.owners, permission.PluginID)
+		// 仍有其他插件声明该权限时保留
+		if len(declared.owners) > 0 {
+			continue
+		}
+		delete(resource.codes, code)
+		touched = append(touched, key)
+	}
+	if err := p.sync(ctx, touched); err != nil {
+		return err
+	}
+
+	for _, key := range touched {
+		if resource, ok := p.resources[key]; ok && len(resource.codes) == 0 {
+			delete(p.resources, key)
+		}
+	}
+	return nil
+}
+
+// sync 按资源重新上报完整的权限列表，EIAM 以最新上报结果覆盖，已移除的权限随之注销
+func (p *pluginPermissions) sync(ctx context.Context, keys []string) error {
+	keys = lo.Uniq(keys)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	providers := make([]capability.PermissionProvider, 0, len(keys))
+	for _, key := range keys {
+		resource := p.resources[key]
+		registry := capability.NewRegistry(resource.app, resource.res, "插件动作/"+strings.Join(resource.owners(), ","))
+		for _, code := range slices.Sorted(maps.Keys(resource.codes)) {
+			registry.Capability(resource.codes[code].name, code)
+		}
+		providers = append(providers, registry)
+	}
+
+	// SDK 内部会启动后台协程维持租约，不能跟随注册请求的 Context 一起取消
+	return p.syncer.WithOption(
+		capability.WithPermissions(providers...),
+	).Sync(context.WithoutCancel(ctx))
+}
+
+// owners 返回资源下全部权限的声明插件，按插件 ID 排序
+func (r *permissionResource) owners() []string {
+	owners := make(map[string]struct{})
+	for _, declared := range r.codes {
+		maps.Copy(owners, declared.owners)
+	}
+	return slices.Sorted(maps.Keys(owners))
+}
+
+func (p *pluginPermissions) Allowed(ctx context.Context, permissions []string) (map[string]bool, error) {
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		ok, err := p.sdk.HasPermission(ctx, permission)
+		if err != nil {
+			return nil, err
+		}
+		granted[permission] = ok
+	}
+	return granted, nil
+}
@@ -0,0 +1,63 @@
+package ioc
+
+import (
+	"fmt"
+	"time"
+
+	resourceEvent "github.com/Duke1616/ecmdb/internal/event/resource"
+	"github.com/Duke1616/ecmdb/internal/repository"
+	"github.com/Duke1616/ecmdb/internal/repository/dao"
+	resourceSvc "github.com/Duke1616/ecmdb/internal/service/resource"
+	"github.com/Duke1616/ecmdb/pkg/searchx"
+	"github.com/spf13/viper"
+)
+
+type searchConfig struct {
+	Backend           string        `mapstructure:"backend"` // embedded / mongo
+	Path              string        `mapstructure:"path"`
+	SyncInterval      time.Duration `mapstructure:"sync_interval"`
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"` // 以资产存储为准校准索引的间隔
+}
+
+func loadSearchConfig() searchConfig {
+	var cfg searchConfig
+	if err := viper.UnmarshalKey("search", &cfg); err != nil {
+		panic(fmt.Errorf("unable to decode into structure: %v", err))
+	}
+
+	if cfg.Backend == "" {
+		cfg.Backend = "embedded"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "data/search.idx"
+	}
+	if cfg.SyncInterval <= 0 {
+		cfg.SyncInterval = 5 * time.Second
+	}
+	if cfg.ReconcileInterval <= 0 {
+		cfg.ReconcileInterval = 24 * time.Hour
+	}
+	return cfg
+}
+
+func InitSearchIndex(resourceDAO dao.ResourceDAO) repository.SearchIndex {
+	cfg := loadSearchConfig()
+
+	switch cfg.Backend {
+	case "mongo":
+		return repository.NewMongoSearchIndex(resourceDAO)
+	case "embedded":
+		idx, err := searchx.Open(cfg.Path)
+		if err != nil {
+			panic(fmt.Errorf("打开检索索引失败: %v", err))
+		}
+		return repository.NewEmbeddedSearchIndex(idx)
+	default:
+		panic(fmt.Errorf("不支持的检索索引类型: %s", cfg.Backend))
+	}
+}
+
+func InitSearchIndexSyncTask(svc resourceSvc.Service) *resourceEvent.SearchIndexSyncTask {
+	cfg := loadSearchConfig()
+	return resourceEvent.NewSearchIndexSyncTask(svc, cfg.SyncInterval, cfg.ReconcileInterval)
+}
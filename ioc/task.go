@@ -1,6 +1,8 @@
 package ioc
 
 import (
+	"github.com/Duke1616/ecmdb/internal/event/dataio"
+	"github.com/Duke1616/ecmdb/internal/event/plugin"
 	"github.com/Duke1616/ecmdb/internal/event/resource"
 )
 
@@ -9,10 +11,23 @@ import (
 func InitTasks(
 	fieldDeleteConsumer *resource.FieldDeleteConsumer,
 	fieldSecretConsumer *resource.FieldSecureAttrChangeConsumer,
-
+	snapshotTask *resource.SnapshotTask,
+	searchIndexSyncTask *resource.SearchIndexSyncTask,
+	importJobTask *dataio.ImportJobTask,
+	exportJobTask *dataio.ExportJobTask,
+	scheduledExportTask *dataio.ScheduledExportTask,
+	pluginHealthCheckTask *plugin.HealthCheckTask,
+	pluginPermissionSyncTask *plugin.PermissionSyncTask,
 ) []Task {
 	return []Task{
 		fieldDeleteConsumer,
 		fieldSecretConsumer,
+		snapshotTask,
+		searchIndexSyncTask,
+		importJobTask,
+		exportJobTask,
+		scheduledExportTask,
+		pluginHealthCheckTask,
+		pluginPermissionSyncTask,
 	}
 }
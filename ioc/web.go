@@ -12,6 +12,7 @@ import (
 	relation "github.com/Duke1616/ecmdb/internal/web/relation"
 	resource "github.com/Duke1616/ecmdb/internal/web/resource"
 	tools "github.com/Duke1616/ecmdb/internal/web/tools"
+	view "github.com/Duke1616/ecmdb/internal/web/view"
 	"github.com/Duke1616/eiam/pkg/web/capability"
 	"github.com/Duke1616/eiam/pkg/web/middleware"
 	"github.com/Duke1616/eiam/pkg/web/sdk"
@@ -24,7 +25,7 @@ func InitWebServer(mdls []gin.HandlerFunc, sdk *sdk.SDK, syncer capability.Synce
 	modelHdl *model.Handler, attributeHdl *attribute.Handler, resourceHdl *resource.Handler,
 	rmHdl *relation.RelationTypeHandler,
 	toolsHdl *tools.Handler,
-	dataIOHdl *dataio.Handler, pluginHdl *plugin.Handler, listener net.Listener,
+	dataIOHdl *dataio.Handler, pluginHdl *plugin.Handler, viewHdl *view.Handler, listener net.Listener,
 ) *egin.Component {
 
 	server := egin.Load("server.egin").Build(egin.WithListener(listener))
@@ -49,6 +50,7 @@ func InitWebServer(mdls []gin.HandlerFunc, sdk *sdk.SDK, syncer capability.Synce
 	pluginHdl.PrivateRoutes(server.Engine)
 	toolsHdl.PrivateRoutes(server.Engine)
 	dataIOHdl.PrivateRoutes(server.Engine)
+	viewHdl.PrivateRoutes(server.Engine)
 
 	// 异步启动 EIAM 资产注册控制器
 	go func() {
@@ -7,6 +7,7 @@
 package ioc
 
 import (
+	plugin3 "github.com/Duke1616/ecmdb/internal/event/plugin"
 	plugin2 "github.com/Duke1616/ecmdb/internal/grpc/plugin"
 	"github.com/Duke1616/ecmdb/internal/repository"
 	"github.com/Duke1616/ecmdb/internal/repository/dao"
@@ -17,6 +18,7 @@ import (
 	service3 "github.com/Duke1616/ecmdb/internal/service/relation"
 	service2 "github.com/Duke1616/ecmdb/internal/service/resource"
 	service5 "github.com/Duke1616/ecmdb/internal/service/tools"
+	service10 "github.com/Duke1616/ecmdb/internal/service/view"
 	web2 "github.com/Duke1616/ecmdb/internal/web/attribute"
 	web7 "github.com/Duke1616/ecmdb/internal/web/dataio"
 	"github.com/Duke1616/ecmdb/internal/web/model"
@@ -24,6 +26,7 @@ import (
 	web4 "github.com/Duke1616/ecmdb/internal/web/relation"
 	web3 "github.com/Duke1616/ecmdb/internal/web/resource"
 	web5 "github.com/Duke1616/ecmdb/internal/web/tools"
+	web9 "github.com/Duke1616/ecmdb/internal/web/view"
 	"github.com/Duke1616/ecmdb/pkg/storage"
 )
 
@@ -56,7 +59,8 @@ func InitApp() (*App, error) {
 	}
 	serviceService := service.NewService(attributeRepository, attributeGroupRepository, fieldSecureAttrChangeEventProducer, iFieldDeleteEventProducer)
 	crypto := InitCrypto()
-	service7 := service2.NewService(resourceRepository, serviceService, crypto)
+	searchIndex := InitSearchIndex(resourceDAO)
+	service7 := service2.NewService(resourceRepository, serviceService, crypto, searchIndex)
 	relationModelDAO := dao.NewRelationModelDAO(db)
 	relationModelRepository := repository.NewRelationModelRepository(relationModelDAO)
 	relationResourceDAO := dao.NewRelationResourceDAO(db)
@@ -70,7 +74,10 @@ func InitApp() (*App, error) {
 	handler := web.NewHandler(service8, mgService, relationModelService, service7)
 	webHandler := web2.NewHandler(serviceService, service8)
 	relationResourceService := service3.NewRelationResourceService(relationResourceRepository, relationModelRepository, resourceRepository)
-	handler2 := web3.NewHandler(service7, serviceService, service8, relationResourceService)
+	savedViewDAO := dao.NewSavedViewDAO(db)
+	savedViewRepository := repository.NewSavedViewRepository(savedViewDAO)
+	service11 := service10.NewService(savedViewRepository, serviceService)
+	handler2 := web3.NewHandler(service7, serviceService, service8, relationResourceService, service11)
 	relationTypeDAO := dao.NewRelationTypeDAO(db)
 	relationTypeRepository := repository.NewRelationTypeRepository(relationTypeDAO)
 	relationTypeService := service3.NewRelationTypeService(relationTypeRepository, relationModelRepository, relationResourceRepository)
@@ -81,12 +88,24 @@ func InitApp() (*App, error) {
 	handler3 := web5.NewHandler(service9)
 	pluginDAO := dao.NewPluginDAO(db)
 	pluginRepository := repository.NewPluginRepository(pluginDAO)
-	pluginService := plugin.NewService(pluginRepository, service7, relationResourceService, service8, mgService, serviceService, relationTypeService, relationModelService)
-	iDataIOService := service6.NewService(serviceService, service7, service8)
-	handler4 := web7.NewHandler(iDataIOService, s3Storage)
+	permissionChecker := InitPluginPermissions(sdk, syncer)
+	tokenSigner := InitPluginTokenSigner()
+	auditConfig := InitPluginAuditConfig(mq)
+	pluginService := plugin.NewService(pluginRepository, service7, relationResourceService, service8, mgService, serviceService, relationTypeService, relationModelService, permissionChecker, tokenSigner, crypto, auditConfig)
+	importJobDAO := dao.NewImportJobDAO(db)
+	importJobRepository := repository.NewImportJobRepository(importJobDAO)
+	exportJobDAO := dao.NewExportJobDAO(db)
+	exportJobRepository := repository.NewExportJobRepository(exportJobDAO)
+	importMappingDAO := dao.NewImportMappingDAO(db)
+	importMappingRepository := repository.NewImportMappingRepository(importMappingDAO)
+	scheduledExportDAO := dao.NewScheduledExportDAO(db)
+	scheduledExportRepository := repository.NewScheduledExportRepository(scheduledExportDAO)
+	iDataIOService := service6.NewService(serviceService, service7, service8, relationModelService, relationResourceService, importJobRepository, exportJobRepository, importMappingRepository, scheduledExportRepository, s3Storage)
+	handler4 := web7.NewHandler(iDataIOService, service11)
 	handler5 := web8.NewHandler(pluginService)
+	handler6 := web9.NewHandler(service11)
 	listener := InitListener()
-	component := InitWebServer(v, sdk, syncer, v2, handler, webHandler, handler2, relationTypeHandler, handler3, handler4, handler5, listener)
+	component := InitWebServer(v, sdk, syncer, v2, handler, webHandler, handler2, relationTypeHandler, handler3, handler4, handler5, handler6, listener)
 	clientv3Client := InitEtcdClient()
 	registry := InitRegistry(clientv3Client)
 	server := plugin2.NewServer(pluginService)
@@ -99,7 +118,14 @@ func InitApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	v4 := InitTasks(fieldDeleteConsumer, fieldSecureAttrChangeConsumer)
+	snapshotTask := InitSnapshotTask(service7, clientv3Client)
+	searchIndexSyncTask := InitSearchIndexSyncTask(service7)
+	importJobTask := InitImportJobTask(iDataIOService)
+	exportJobTask := InitExportJobTask(iDataIOService)
+	scheduledExportTask := InitScheduledExportTask(iDataIOService, clientv3Client)
+	healthCheckTask := InitPluginHealthCheckTask(pluginService, clientv3Client)
+	permissionSyncTask := plugin3.NewPermissionSyncTask(pluginService)
+	v4 := InitTasks(fieldDeleteConsumer, fieldSecureAttrChangeConsumer, snapshotTask, searchIndexSyncTask, importJobTask, exportJobTask, scheduledExportTask, healthCheckTask, permissionSyncTask)
 	app := &App{
 		Web:        component,
 		GrpcServer: grpcServer,
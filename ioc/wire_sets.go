@@ -1,6 +1,7 @@
 package ioc
 
 import (
+	pluginEvent "github.com/Duke1616/ecmdb/internal/event/plugin"
 	pluginserver "github.com/Duke1616/ecmdb/internal/grpc/plugin"
 	"github.com/Duke1616/ecmdb/internal/repository"
 	"github.com/Duke1616/ecmdb/internal/repository/dao"
@@ -11,6 +12,7 @@ import (
 	relationSvc "github.com/Duke1616/ecmdb/internal/service/relation"
 	resourceSvc "github.com/Duke1616/ecmdb/internal/service/resource"
 	toolsSvc "github.com/Duke1616/ecmdb/internal/service/tools"
+	viewSvc "github.com/Duke1616/ecmdb/internal/service/view"
 	attribute "github.com/Duke1616/ecmdb/internal/web/attribute"
 	dataio "github.com/Duke1616/ecmdb/internal/web/dataio"
 	model "github.com/Duke1616/ecmdb/internal/web/model"
@@ -18,6 +20,7 @@ import (
 	relation "github.com/Duke1616/ecmdb/internal/web/relation"
 	resource "github.com/Duke1616/ecmdb/internal/web/resource"
 	tools "github.com/Duke1616/ecmdb/internal/web/tools"
+	view "github.com/Duke1616/ecmdb/internal/web/view"
 	"github.com/Duke1616/ecmdb/pkg/storage"
 	"github.com/google/wire"
 )
@@ -52,6 +55,14 @@ var (
 	)
 
 	dataIoSet = wire.NewSet(
+		dao.NewImportJobDAO,
+		repository.NewImportJobRepository,
+		dao.NewExportJobDAO,
+		repository.NewExportJobRepository,
+		dao.NewImportMappingDAO,
+		repository.NewImportMappingRepository,
+		dao.NewScheduledExportDAO,
+		repository.NewScheduledExportRepository,
 		dataio.NewHandler,
 		dataioSvc.NewService,
 	)
@@ -59,6 +70,8 @@ var (
 	PluginSet = wire.NewSet(
 		dao.NewPluginDAO,
 		repository.NewPluginRepository,
+		InitPluginTokenSigner,
+		InitPluginAuditConfig,
 		pluginSvc.NewService,
 		plugin.NewHandler,
 	)
@@ -89,15 +102,24 @@ var (
 	ResourceSet = wire.NewSet(
 		dao.NewResourceDAO,
 		repository.NewResourceRepository,
+		InitSearchIndex,
 		resourceSvc.NewService,
 		resource.NewHandler,
 	)
 
+	ViewSet = wire.NewSet(
+		dao.NewSavedViewDAO,
+		repository.NewSavedViewRepository,
+		viewSvc.NewService,
+		view.NewHandler,
+	)
+
 	// WebSet Web 服务 Provider 集合
 	WebSet = wire.NewSet(
 		InitPolicySDK,
 		InitPermSyncer,
 		InitProviders,
+		InitPluginPermissions,
 		InitGinMiddlewares,
 		InitWebServer,
 		InitGrpcServer,
@@ -112,9 +134,17 @@ var (
 		RelationSet,
 		ModelSet,
 		ResourceSet,
+		ViewSet,
 
 		InitFieldSecureAttrChangeConsumer,
 		InitFieldDeleteConsumer,
+		InitSnapshotTask,
+		InitSearchIndexSyncTask,
+		InitImportJobTask,
+		InitExportJobTask,
+		InitScheduledExportTask,
+		InitPluginHealthCheckTask,
+		pluginEvent.NewPermissionSyncTask,
 		InitTasks,
 
 		InitDeleteModelDependencyCheckers,
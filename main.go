@@ -6,6 +6,7 @@ import (
 
 	"github.com/Duke1616/ecmdb/cmd/backup"
 	"github.com/Duke1616/ecmdb/cmd/initial"
+	"github.com/Duke1616/ecmdb/cmd/reindex"
 	"github.com/Duke1616/ecmdb/cmd/repair"
 	"github.com/Duke1616/ecmdb/cmd/server"
 	"github.com/fatih/color"
@@ -61,6 +62,7 @@ func main() {
 	rootCmd.AddCommand(initial.Cmd)
 	rootCmd.AddCommand(backup.Cmd)
 	rootCmd.AddCommand(repair.Cmd)
+	rootCmd.AddCommand(reindex.Cmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -0,0 +1,77 @@
+package electionx
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gotomicro/ego/core/elog"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+const (
+	// defaultSessionTTL 选主租约时长（秒），实例退出后其他实例最迟在该时间后接管
+	defaultSessionTTL = 15
+	// defaultRetryInterval 选主或租约异常后的重试间隔
+	defaultRetryInterval = 5 * time.Second
+)
+
+// Election 基于 etcd 的选主，保证同一时间只有一个实例执行后台任务
+type Election struct {
+	client *clientv3.Client
+	prefix string
+	name   string
+	logger *elog.Component
+}
+
+// NewElection 构造选主，prefix 为选主键前缀，name 用于日志
+func NewElection(client *clientv3.Client, prefix, name string) *Election {
+	return &Election{
+		client: client,
+		prefix: prefix,
+		name:   name,
+		logger: elog.DefaultLogger,
+	}
+}
+
+// Run 持续参与选主直至 ctx 结束，成为主节点后执行 lead
+// NOTE: lead 需要在 ctx 结束或 expired 关闭（租约失效）时返回，返回后重新参与选主
+func (e *Election) Run(ctx context.Context, lead func(ctx context.Context, expired <-chan struct{})) {
+	for {
+		if err := e.campaign(ctx, lead); err != nil {
+			e.logger.Error(e.name+"选主失败", elog.FieldErr(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(defaultRetryInterval):
+		}
+	}
+}
+
+func (e *Election) campaign(ctx context.Context, lead func(ctx context.Context, expired <-chan struct{})) error {
+	session, err := concurrency.NewSession(e.client, concurrency.WithTTL(defaultSessionTTL),
+		concurrency.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	hostname, _ := os.Hostname()
+	election := concurrency.NewElection(session, e.prefix)
+	if err = election.Campaign(ctx, hostname); err != nil {
+		return err
+	}
+	e.logger.Info(e.name+"成为主节点", elog.String("host", hostname))
+	defer func() {
+		// NOTE: ctx 可能已结束，使用独立的超时主动让出，便于其他实例尽快接管
+		resignCtx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+		_ = election.Resign(resignCtx)
+	}()
+
+	lead(ctx, session.Done())
+	return nil
+}
@@ -1,7 +1,23 @@
 package ginx
 
+import "io"
+
 type Result struct {
 	Code int    `json:"code"`
 	Msg  string `json:"msg"`
 	Data any    `json:"data"`
 }
+
+// File 文件下载响应
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// FileStream 流式文件下载响应，Write 直接向响应体写入文件内容，适用于无法一次性加载到内存的大文件
+type FileStream struct {
+	Name        string
+	ContentType string
+	Write       func(w io.Writer) error
+}
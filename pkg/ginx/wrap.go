@@ -86,6 +86,85 @@ func WrapFinderBody[Req any](fn func(ctx *gin.Context, req Req) (Result, error))
 	}
 }
 
+// WrapFileBody 包装文件下载类接口，成功时直接输出文件内容，失败时按统一 Result 返回
+// NOTE: 避免在 ctx.Data 之后再追加 JSON 响应体导致文件内容损坏
+func WrapFileBody[Req any](fn func(ctx *gin.Context, req Req) (File, error), systemResult Result) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req Req
+		if err := ctx.Bind(&req); err != nil {
+			slog.Error("绑定参数失败", slog.Any("err", err))
+			return
+		}
+
+		file, err := fn(ctx, req)
+		if err != nil {
+			handleError(ctx, err, systemResult)
+			return
+		}
+
+		ctx.Header("Content-Disposition", "attachment; filename="+file.Name)
+		ctx.Header("Content-Transfer-Encoding", "binary")
+		ctx.Data(http.StatusOK, file.ContentType, file.Data)
+	}
+}
+
+// WrapFileStreamBody 包装流式文件下载接口
+// NOTE: 响应头在首次写入文件内容时才发送，写入任何内容之前出错仍按统一 Result 返回；
+// 写入过程中出错时响应已经开始，只能中断连接，客户端得到的文件不完整
+func WrapFileStreamBody[Req any](fn func(ctx *gin.Context, req Req) (FileStream, error), systemResult Result) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req Req
+		if err := ctx.Bind(&req); err != nil {
+			slog.Error("绑定参数失败", slog.Any("err", err))
+			return
+		}
+
+		file, err := fn(ctx, req)
+		if err != nil {
+			handleError(ctx, err, systemResult)
+			return
+		}
+
+		w := &fileStreamWriter{ctx: ctx, file: file}
+		if err = file.Write(w); err != nil {
+			if !w.started {
+				handleError(ctx, err, systemResult)
+				return
+			}
+			slog.Error("输出文件内容中断", slog.Any("err", err))
+			ctx.Abort()
+			return
+		}
+
+		// 文件内容为空时也需要发送响应头
+		w.start()
+	}
+}
+
+// fileStreamWriter 首次写入时发送文件下载响应头
+type fileStreamWriter struct {
+	ctx     *gin.Context
+	file    FileStream
+	started bool
+}
+
+func (w *fileStreamWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.ctx.Writer.Write(p)
+}
+
+func (w *fileStreamWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.ctx.Header("Content-Type", w.file.ContentType)
+	w.ctx.Header("Content-Disposition", "attachment; filename="+w.file.Name)
+	w.ctx.Header("Content-Transfer-Encoding", "binary")
+	w.ctx.Status(http.StatusOK)
+	w.ctx.Writer.WriteHeaderNow()
+}
+
 func Ws(fn func(ctx *gin.Context) error) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		err := fn(ctx)
@@ -2,8 +2,10 @@ package ginx
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -81,3 +83,151 @@ func TestHandleError(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapFileBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type req struct {
+		Name string `json:"name"`
+	}
+	systemResult := Result{Code: 502001, Msg: "系统错误"}
+
+	tests := []struct {
+		name        string
+		body        string
+		fn          func(ctx *gin.Context, r req) (File, error)
+		wantStatus  int
+		wantBody    string
+		wantHeaders map[string]string
+	}{
+		{
+			name: "输出文件内容",
+			body: `{"name":"graph"}`,
+			fn: func(ctx *gin.Context, r req) (File, error) {
+				return File{Name: r.Name + ".dot", ContentType: "text/vnd.graphviz", Data: []byte("digraph {}")}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "digraph {}",
+			wantHeaders: map[string]string{
+				"Content-Type":        "text/vnd.graphviz",
+				"Content-Disposition": "attachment; filename=graph.dot",
+			},
+		},
+		{
+			name: "业务错误返回 Result",
+			body: `{"name":"graph"}`,
+			fn: func(ctx *gin.Context, r req) (File, error) {
+				return File{}, mockBusinessError{code: 503002, msg: "验证错误"}
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"code":503002,"msg":"验证错误","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/export", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			WrapFileBody(tt.fn, systemResult)(c)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(w.Body.String()))
+			for key, val := range tt.wantHeaders {
+				assert.Equal(t, val, w.Header().Get(key))
+			}
+		})
+	}
+}
+
+func TestWrapFileStreamBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type req struct {
+		Name string `json:"name"`
+	}
+	systemResult := Result{Code: 502001, Msg: "系统错误"}
+
+	tests := []struct {
+		name        string
+		fn          func(ctx *gin.Context, r req) (FileStream, error)
+		wantStatus  int
+		wantBody    string
+		wantHeaders map[string]string
+	}{
+		{
+			name: "分块输出文件内容",
+			fn: func(ctx *gin.Context, r req) (FileStream, error) {
+				return FileStream{Name: r.Name + ".csv", ContentType: "text/csv", Write: func(w io.Writer) error {
+					for _, line := range []string{"name\n", "web01\n", "web02\n"} {
+						if _, err := io.WriteString(w, line); err != nil {
+							return err
+						}
+					}
+					return nil
+				}}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "name\nweb01\nweb02",
+			wantHeaders: map[string]string{
+				"Content-Type":        "text/csv",
+				"Content-Disposition": "attachment; filename=export.csv",
+			},
+		},
+		{
+			name: "写入前出错返回 Result",
+			fn: func(ctx *gin.Context, r req) (FileStream, error) {
+				return FileStream{Name: r.Name + ".csv", ContentType: "text/csv", Write: func(w io.Writer) error {
+					return mockBusinessError{code: 503002, msg: "验证错误"}
+				}}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"code":503002,"msg":"验证错误","data":null}`,
+			wantHeaders: map[string]string{
+				"Content-Disposition": "",
+			},
+		},
+		{
+			name: "写入过程中出错保留已输出内容",
+			fn: func(ctx *gin.Context, r req) (FileStream, error) {
+				return FileStream{Name: r.Name + ".csv", ContentType: "text/csv", Write: func(w io.Writer) error {
+					_, _ = io.WriteString(w, "name\n")
+					return errors.New("数据库连接断开")
+				}}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "name",
+		},
+		{
+			name: "空文件",
+			fn: func(ctx *gin.Context, r req) (FileStream, error) {
+				return FileStream{Name: r.Name + ".csv", ContentType: "text/csv", Write: func(w io.Writer) error {
+					return nil
+				}}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Disposition": "attachment; filename=export.csv",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/export", strings.NewReader(`{"name":"export"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			WrapFileStreamBody(tt.fn, systemResult)(c)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(w.Body.String()))
+			for key, val := range tt.wantHeaders {
+				assert.Equal(t, val, w.Header().Get(key))
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package graphx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EncodeDOT 输出 Graphviz DOT 格式
+func EncodeDOT(w io.Writer, g Graph) error {
+	bw := bufio.NewWriter(w)
+
+	kind, arrow := "graph", "--"
+	if g.Directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	fmt.Fprintf(bw, "%s %s {\n", kind, quoteDOT(graphID(g)))
+	if g.Label != "" {
+		fmt.Fprintf(bw, "  label=%s;\n", quoteDOT(g.Label))
+	}
+
+	for _, node := range g.Nodes {
+		fmt.Fprintf(bw, "  %s%s;\n", quoteDOT(node.ID), dotAttrList(node.Label, node.Attrs))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(bw, "  %s %s %s%s;\n", quoteDOT(edge.Source), arrow, quoteDOT(edge.Target), dotAttrList(edge.Label, edge.Attrs))
+	}
+
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// dotAttrList 生成 [label="..." key="..."] 形式的属性列表
+func dotAttrList(label string, attrs map[string]any) string {
+	items := make([]string, 0, len(attrs)+1)
+	if label != "" {
+		items = append(items, "label="+quoteDOT(label))
+	}
+	for _, name := range sortedKeys(attrs) {
+		if name == "label" {
+			continue
+		}
+		items = append(items, quoteDOT(name)+"="+quoteDOT(stringify(attrs[name])))
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(items, ", ") + "]"
+}
+
+// quoteDOT 将任意字符串转为 DOT 的双引号 ID
+func quoteDOT(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
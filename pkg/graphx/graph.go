@@ -0,0 +1,164 @@
+package graphx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format 图导出格式
+type Format string
+
+const (
+	FormatGraphML Format = "graphml"
+	FormatDOT     Format = "dot"
+	FormatJSON    Format = "json"
+)
+
+// ParseFormat 解析导出格式，为空时默认使用 JSON Graph Format
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FormatJSON, "jgf":
+		return FormatJSON, nil
+	case FormatGraphML:
+		return FormatGraphML, nil
+	case FormatDOT, "graphviz":
+		return FormatDOT, nil
+	default:
+		return "", fmt.Errorf("不支持的图导出格式: %s", value)
+	}
+}
+
+// ContentType 返回导出格式对应的 HTTP Content-Type
+func (f Format) ContentType() string {
+	switch f {
+	case FormatGraphML:
+		return "application/graphml+xml; charset=utf-8"
+	case FormatDOT:
+		return "text/vnd.graphviz; charset=utf-8"
+	default:
+		return "application/vnd.jgf+json; charset=utf-8"
+	}
+}
+
+// Extension 返回导出格式对应的文件后缀
+func (f Format) Extension() string {
+	switch f {
+	case FormatGraphML:
+		return ".graphml"
+	case FormatDOT:
+		return ".dot"
+	default:
+		return ".json"
+	}
+}
+
+// Graph 与具体展示无关的通用有向图结构
+// NOTE: 节点、边上的 Attrs 会原样写入各导出格式的属性区，调用方负责提前过滤敏感字段
+type Graph struct {
+	ID       string
+	Label    string
+	Directed bool
+	Nodes    []Node
+	Edges    []Edge
+}
+
+// Node 图节点
+type Node struct {
+	ID    string
+	Label string
+	Attrs map[string]any
+}
+
+// Edge 图的边
+type Edge struct {
+	Source string
+	Target string
+	Label  string
+	Attrs  map[string]any
+}
+
+// Encode 按指定格式输出图
+func Encode(w io.Writer, g Graph, format Format) error {
+	switch format {
+	case FormatGraphML:
+		return EncodeGraphML(w, g)
+	case FormatDOT:
+		return EncodeDOT(w, g)
+	case FormatJSON:
+		return EncodeJSONGraph(w, g)
+	default:
+		return fmt.Errorf("不支持的图导出格式: %s", format)
+	}
+}
+
+// Marshal 按指定格式输出图的字节数据
+func Marshal(g Graph, format Format) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, g, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Dedup 按节点 ID、边的 (Source, Target, Label) 去重，保留首次出现的元素
+// NOTE: 递归拓扑查询时同一节点、同一条边可能从多个方向被命中
+func (g Graph) Dedup() Graph {
+	seenNodes := make(map[string]struct{}, len(g.Nodes))
+	nodes := make([]Node, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if _, ok := seenNodes[node.ID]; ok {
+			continue
+		}
+		seenNodes[node.ID] = struct{}{}
+		nodes = append(nodes, node)
+	}
+
+	seenEdges := make(map[[3]string]struct{}, len(g.Edges))
+	edges := make([]Edge, 0, len(g.Edges))
+	for _, edge := range g.Edges {
+		key := [3]string{edge.Source, edge.Target, edge.Label}
+		if _, ok := seenEdges[key]; ok {
+			continue
+		}
+		seenEdges[key] = struct{}{}
+		edges = append(edges, edge)
+	}
+
+	g.Nodes = nodes
+	g.Edges = edges
+	return g
+}
+
+// sortedKeys 返回属性名的稳定排序，保证多次导出结果一致
+func sortedKeys(attrs map[string]any) []string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringify 将属性值转换为字符串，复合类型使用 JSON 表示
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
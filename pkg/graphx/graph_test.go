@@ -0,0 +1,125 @@
+package graphx
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() Graph {
+	return Graph{
+		ID:       "resource-1",
+		Label:    "host \"a\"",
+		Directed: true,
+		Nodes: []Node{
+			{ID: "1", Label: "host-a", Attrs: map[string]any{"model_uid": "host", "cpu": 8}},
+			{ID: "2", Label: "mysql<prod>", Attrs: map[string]any{"model_uid": "mysql", "cpu": "n/a"}},
+		},
+		Edges: []Edge{
+			{Source: "1", Target: "2", Label: "host_run_mysql", Attrs: map[string]any{"relation_type_uid": "run"}},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{name: "默认 JSON", input: "", want: FormatJSON},
+		{name: "JGF 别名", input: "jgf", want: FormatJSON},
+		{name: "GraphML 大小写", input: "GraphML", want: FormatGraphML},
+		{name: "Graphviz 别名", input: "graphviz", want: FormatDOT},
+		{name: "不支持的格式", input: "gexf", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseFormat(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestEncodeGraphML(t *testing.T) {
+	data, err := Marshal(testGraph(), FormatGraphML)
+	require.NoError(t, err)
+
+	// 输出必须是合法 XML
+	var doc struct {
+		Keys []struct {
+			ID   string `xml:"id,attr"`
+			Type string `xml:"attr.type,attr"`
+		} `xml:"key"`
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+
+	assert.Equal(t, "directed", doc.Graph.EdgeDefault)
+	assert.Len(t, doc.Graph.Nodes, 2)
+	assert.Len(t, doc.Graph.Edges, 1)
+	assert.Contains(t, string(data), "mysql&lt;prod&gt;")
+
+	// cpu 在两个节点上类型不一致，应降级为 string
+	types := make(map[string]string, len(doc.Keys))
+	for _, key := range doc.Keys {
+		types[key.ID] = key.Type
+	}
+	assert.Equal(t, "string", types["n_cpu"])
+	assert.Equal(t, "string", types["e_relation_type_uid"])
+}
+
+func TestEncodeDOT(t *testing.T) {
+	data, err := Marshal(testGraph(), FormatDOT)
+	require.NoError(t, err)
+
+	out := string(data)
+	assert.Contains(t, out, `digraph "resource-1" {`)
+	assert.Contains(t, out, `label="host \"a\"";`)
+	assert.Contains(t, out, `"1" [label="host-a", "cpu"="8", "model_uid"="host"];`)
+	assert.Contains(t, out, `"1" -> "2" [label="host_run_mysql", "relation_type_uid"="run"];`)
+}
+
+func TestEncodeJSONGraph(t *testing.T) {
+	data, err := Marshal(testGraph(), FormatJSON)
+	require.NoError(t, err)
+
+	var doc jgfDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.True(t, doc.Graph.Directed)
+	assert.Equal(t, "host-a", doc.Graph.Nodes["1"].Label)
+	assert.Equal(t, "mysql", doc.Graph.Nodes["2"].Metadata["model_uid"])
+	require.Len(t, doc.Graph.Edges, 1)
+	assert.Equal(t, "host_run_mysql", doc.Graph.Edges[0].Relation)
+}
+
+func TestGraph_Dedup(t *testing.T) {
+	g := testGraph()
+	g.Nodes = append(g.Nodes, Node{ID: "1", Label: "duplicate"})
+	g.Edges = append(g.Edges, g.Edges[0])
+
+	deduped := g.Dedup()
+	assert.Len(t, deduped.Nodes, 2)
+	assert.Equal(t, "host-a", deduped.Nodes[0].Label)
+	assert.Len(t, deduped.Edges, 1)
+}
@@ -0,0 +1,148 @@
+package graphx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+const (
+	graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<graphml xmlns="http://graphml.graphdrawing.org/xmlns" ` +
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+		`xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">` + "\n"
+
+	graphMLLabelKey = "label"
+)
+
+// graphMLKey GraphML 属性声明
+type graphMLKey struct {
+	id       string
+	domain   string
+	name     string
+	attrType string
+}
+
+// EncodeGraphML 输出 GraphML 格式，可直接导入 Gephi、yEd、draw.io 等工具
+func EncodeGraphML(w io.Writer, g Graph) error {
+	bw := bufio.NewWriter(w)
+
+	nodeKeys := collectGraphMLKeys("node", "n_", lo.Map(g.Nodes, func(n Node, _ int) map[string]any {
+		return n.Attrs
+	}))
+	edgeKeys := collectGraphMLKeys("edge", "e_", lo.Map(g.Edges, func(e Edge, _ int) map[string]any {
+		return e.Attrs
+	}))
+
+	bw.WriteString(graphMLHeader)
+	// 节点、边标签统一使用 label 属性承载
+	fmt.Fprintf(bw, "  <key id=\"n_%s\" for=\"node\" attr.name=\"%s\" attr.type=\"string\"/>\n", graphMLLabelKey, graphMLLabelKey)
+	fmt.Fprintf(bw, "  <key id=\"e_%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"string\"/>\n", graphMLLabelKey, graphMLLabelKey)
+	for _, key := range append(nodeKeys, edgeKeys...) {
+		fmt.Fprintf(bw, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n",
+			escapeXML(key.id), key.domain, escapeXML(key.name), key.attrType)
+	}
+
+	edgeDefault := "undirected"
+	if g.Directed {
+		edgeDefault = "directed"
+	}
+	fmt.Fprintf(bw, "  <graph id=\"%s\" edgedefault=\"%s\">\n", escapeXML(graphID(g)), edgeDefault)
+
+	for _, node := range g.Nodes {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", escapeXML(node.ID))
+		writeGraphMLData(bw, "n_"+graphMLLabelKey, node.Label)
+		for _, name := range sortedKeys(node.Attrs) {
+			if name == graphMLLabelKey {
+				continue
+			}
+			writeGraphMLData(bw, "n_"+name, stringify(node.Attrs[name]))
+		}
+		bw.WriteString("    </node>\n")
+	}
+
+	for i, edge := range g.Edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, escapeXML(edge.Source), escapeXML(edge.Target))
+		writeGraphMLData(bw, "e_"+graphMLLabelKey, edge.Label)
+		for _, name := range sortedKeys(edge.Attrs) {
+			if name == graphMLLabelKey {
+				continue
+			}
+			writeGraphMLData(bw, "e_"+name, stringify(edge.Attrs[name]))
+		}
+		bw.WriteString("    </edge>\n")
+	}
+
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
+
+func writeGraphMLData(bw *bufio.Writer, key string, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", escapeXML(key), escapeXML(value))
+}
+
+// collectGraphMLKeys 汇总所有属性名并推断 GraphML 类型
+// NOTE: 同一属性在不同元素上类型不一致时降级为 string
+func collectGraphMLKeys(domain string, prefix string, attrsList []map[string]any) []graphMLKey {
+	types := make(map[string]string)
+	for _, attrs := range attrsList {
+		for name, value := range attrs {
+			if name == graphMLLabelKey {
+				continue
+			}
+			t := graphMLType(value)
+			if current, ok := types[name]; ok && current != t {
+				t = "string"
+			}
+			types[name] = t
+		}
+	}
+
+	keys := make([]graphMLKey, 0, len(types))
+	for name, t := range types {
+		keys = append(keys, graphMLKey{
+			id:       prefix + name,
+			domain:   domain,
+			name:     name,
+			attrType: t,
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name
+	})
+	return keys
+}
+
+func graphMLType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "long"
+	case float32, float64:
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+func escapeXML(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+func graphID(g Graph) string {
+	if g.ID == "" {
+		return "G"
+	}
+	return g.ID
+}
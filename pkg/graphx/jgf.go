@@ -0,0 +1,66 @@
+package graphx
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jgfDocument JSON Graph Format v2 文档结构
+// NOTE: 参考 https://jsongraphformat.info/ ，nodes 使用以 ID 为 key 的对象
+type jgfDocument struct {
+	Graph jgfGraph `json:"graph"`
+}
+
+type jgfGraph struct {
+	ID       string             `json:"id,omitempty"`
+	Label    string             `json:"label,omitempty"`
+	Directed bool               `json:"directed"`
+	Nodes    map[string]jgfNode `json:"nodes"`
+	Edges    []jgfEdge          `json:"edges"`
+}
+
+type jgfNode struct {
+	Label    string         `json:"label,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type jgfEdge struct {
+	Source   string         `json:"source"`
+	Target   string         `json:"target"`
+	Relation string         `json:"relation,omitempty"`
+	Directed bool           `json:"directed"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// EncodeJSONGraph 输出 JSON Graph Format
+func EncodeJSONGraph(w io.Writer, g Graph) error {
+	doc := jgfDocument{
+		Graph: jgfGraph{
+			ID:       g.ID,
+			Label:    g.Label,
+			Directed: g.Directed,
+			Nodes:    make(map[string]jgfNode, len(g.Nodes)),
+			Edges:    make([]jgfEdge, 0, len(g.Edges)),
+		},
+	}
+
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes[node.ID] = jgfNode{
+			Label:    node.Label,
+			Metadata: node.Attrs,
+		}
+	}
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, jgfEdge{
+			Source:   edge.Source,
+			Target:   edge.Target,
+			Relation: edge.Label,
+			Directed: g.Directed,
+			Metadata: edge.Attrs,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
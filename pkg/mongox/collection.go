@@ -79,6 +79,48 @@ func (c *Collection[T]) Find(ctx context.Context, filter interface{}, opts ...*o
 	return results, nil
 }
 
+// FindEach 以游标方式按批遍历查询结果，应用生命周期拦截，避免一次性加载全部文档
+// NOTE: fn 返回错误时立即停止遍历并返回该错误
+func (c *Collection[T]) FindEach(ctx context.Context, filter interface{}, batchSize int, fn func(batch []T) error,
+	opts ...*options.FindOptions) error {
+	if c.shouldShortCircuit(ctx) {
+		return nil
+	}
+	finalFilter, err := c.applyBeforeFind(ctx, filter, new(T))
+	if err != nil {
+		return err
+	}
+
+	cursor, err := c.coll.Find(ctx, finalFilter, append(opts, options.Find().SetBatchSize(int32(batchSize)))...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]T, 0, batchSize)
+	for cursor.Next(ctx) {
+		var dest T
+		if err = cursor.Decode(&dest); err != nil {
+			return err
+		}
+		batch = append(batch, dest)
+		if len(batch) < batchSize {
+			continue
+		}
+		if err = fn(batch); err != nil {
+			return err
+		}
+		batch = make([]T, 0, batchSize)
+	}
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
 // InsertOne 写入单条文档，执行 BeforeInsert 拦截（自动填充 TenantID 与自增 ID）
 func (c *Collection[T]) InsertOne(ctx context.Context, doc *T, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
 	stmt := &Statement{
@@ -1,7 +1,15 @@
 package plugin
 
 const (
-	PlacementResourceDetailActions = "resource.detail.actions"
+	PlacementResourceDetailActions = "resource.detail.actions" // 资源详情页动作区，作用于当前资源
+	PlacementModelListToolbar      = "model.list.toolbar"      // 模型资源列表工具栏，作用于勾选的多个资源
+	PlacementModelActions          = "model.actions"           // 模型级动作，不针对具体资源
+	PlacementRelationGraphNode     = "relation.graph.node"     // 关系拓扑图节点右键菜单，作用于节点资源
+	PlacementGlobalMenu            = "global.menu"             // 全局菜单入口，不针对具体模型和资源
+
+	EntryModeResource  = "resource"  // 以单个资源为主资源解析
+	EntryModeResources = "resources" // 以同一模型下的多个资源解析
+	EntryModeNone      = "none"      // 无主资源
 
 	DirectionToSource = "source"
 	DirectionToTarget = "target"
@@ -36,6 +44,7 @@ type Plugin struct {
 	Version string         `json:"version" bson:"version"`
 	Actions []ActionSpec   `json:"actions" bson:"actions"`
 	Meta    map[string]any `json:"meta,omitempty" bson:"meta,omitempty"`
+	Health  *Health        `json:"health,omitempty" bson:"health,omitempty"`
 	Ctime   int64          `json:"ctime,omitempty" bson:"ctime,omitempty"`
 	Utime   int64          `json:"utime,omitempty" bson:"utime,omitempty"`
 }
@@ -48,6 +57,8 @@ type ActionSpec struct {
 	Permission string             `json:"permission,omitempty" bson:"permission,omitempty"`
 	BindingUID string             `json:"binding_uid,omitempty" bson:"binding_uid,omitempty"`
 	Runtime    *ActionRuntimeSpec `json:"runtime,omitempty" bson:"runtime,omitempty"`
+	Params     []ParamSpec        `json:"params,omitempty" bson:"params,omitempty"`
+	Batch      bool               `json:"batch,omitempty" bson:"batch,omitempty"` // 是否支持对多个资源批量执行
 	Meta       map[string]any     `json:"meta,omitempty" bson:"meta,omitempty"`
 }
 
@@ -197,7 +208,11 @@ type ResourceAction struct {
 	Permission string             `json:"permission,omitempty"`
 	BindingUID string             `json:"binding_uid,omitempty"`
 	Runtime    *ActionRuntimeSpec `json:"runtime,omitempty"`
+	Batch      bool               `json:"batch,omitempty"`
+	EntryMode  string             `json:"entry_mode"`
 	Meta       map[string]any     `json:"meta,omitempty"`
+	Disabled   bool               `json:"disabled,omitempty"`        // 插件运行时不可用时置灰
+	Reason     string             `json:"disabled_reason,omitempty"` // 置灰原因
 }
 
 type ResourceActions struct {
@@ -219,10 +234,12 @@ type ResolvedInput struct {
 }
 
 type ResolveRequest struct {
-	PluginID   string         `json:"plugin_id"`
-	Action     string         `json:"action"`
-	ResourceID int64          `json:"resource_id"`
-	Params     map[string]any `json:"params,omitempty"`
+	PluginID    string         `json:"plugin_id"`
+	Action      string         `json:"action"`
+	ResourceID  int64          `json:"resource_id"`
+	ResourceIDs []int64        `json:"resource_ids,omitempty"` // 多资源入口勾选的资源
+	ModelUID    string         `json:"model_uid,omitempty"`    // 无主资源入口所在模型，全局菜单入口为空
+	Params      map[string]any `json:"params,omitempty"`
 }
 
 type ResolveResult struct {
@@ -234,18 +251,40 @@ type ResolveResult struct {
 	Permission    string                   `json:"permission,omitempty"`
 	BindingUID    string                   `json:"binding_uid,omitempty"`
 	ModelUID      string                   `json:"model_uid,omitempty"`
+	EntryMode     string                   `json:"entry_mode"`
 	ResourceID    int64                    `json:"resource_id"`
+	ResourceIDs   []int64                  `json:"resource_ids,omitempty"`
 	Inputs        map[string]ResolvedInput `json:"inputs"`
 	Params        map[string]any           `json:"params,omitempty"`
+	ParamSchema   []ParamSpec              `json:"param_schema,omitempty"` // 动作参数定义，前端据此渲染参数表单
 	Runtime       *ActionRuntimeSpec       `json:"runtime,omitempty"`
 	Meta          map[string]any           `json:"meta,omitempty"`
+	Token         string                   `json:"token,omitempty"`            // 访问插件运行时代理的动作令牌
+	TokenExpires  int64                    `json:"token_expires_at,omitempty"` // 动作令牌过期时间，Unix 秒
+}
+
+// BatchResolveItem 单个资源的批量解析结果，解析失败时 Result 为空并记录原因。
+type BatchResolveItem struct {
+	ResourceID int64          `json:"resource_id"`
+	Result     *ResolveResult `json:"result,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Missing    []string       `json:"missing,omitempty"` // 缺少的必需输入
+}
+
+type BatchResolveResult struct {
+	PluginID  string             `json:"plugin_id"`
+	Action    string             `json:"action"`
+	Items     []BatchResolveItem `json:"items"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
 }
 
 type ActionContext struct {
-	Plugin     Plugin                   `json:"plugin"`
-	Binding    Binding                  `json:"binding"`
-	Action     ActionSpec               `json:"action"`
-	ResourceID int64                    `json:"resource_id"`
-	Inputs     map[string]ResolvedInput `json:"inputs"`
-	Params     map[string]any           `json:"params,omitempty"`
+	Plugin      Plugin                   `json:"plugin"`
+	Binding     Binding                  `json:"binding"` // 无主资源且未声明绑定的动作为空
+	Action      ActionSpec               `json:"action"`
+	ResourceID  int64                    `json:"resource_id"`
+	ResourceIDs []int64                  `json:"resource_ids,omitempty"` // 多资源入口的全部资源
+	Inputs      map[string]ResolvedInput `json:"inputs"`
+	Params      map[string]any           `json:"params,omitempty"`
 }
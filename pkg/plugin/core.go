@@ -22,7 +22,7 @@ func ValidateResolveRequest(req ResolveRequest) error {
 	if req.Action == "" {
 		return fmt.Errorf("action 不能为空")
 	}
-	return ValidateResourceID(req.ResourceID)
+	return nil
 }
 
 func ValidateResourceID(resourceID int64) error {
@@ -68,6 +68,20 @@ func (p Plugin) Validate() error {
 	if p.Name == "" {
 		return fmt.Errorf("插件名称不能为空")
 	}
+	for _, action := range p.Actions {
+		if !ValidPlacement(action.Placement) {
+			return fmt.Errorf("插件动作 %s: 不支持的展示位置 %s", action.Action, action.Placement)
+		}
+		if err := ValidateParamSpecs(action.Params); err != nil {
+			return fmt.Errorf("插件动作 %s: %w", action.Action, err)
+		}
+		if strings.TrimSpace(action.Permission) == "" {
+			continue
+		}
+		if _, _, _, err := ParsePluginPermission(action.Permission); err != nil {
+			return fmt.Errorf("插件动作 %s: %w", action.Action, err)
+		}
+	}
 	return nil
 }
 
@@ -77,18 +91,28 @@ func (p Plugin) FindAction(name string) (ActionSpec, bool) {
 	})
 }
 
+// ResourceActions 展开插件动作，插件运行时不可用时动作置灰并附带原因。
 func (p Plugin) ResourceActions() []ResourceAction {
+	var reason string
+	if p.Health.Unhealthy() {
+		reason = "插件运行时不可用: " + p.Health.LastError
+	}
+
 	return lo.Map(p.Actions, func(action ActionSpec, _ int) ResourceAction {
 		return ResourceAction{
 			PluginID:   p.UID,
 			Action:     action.Action,
 			Name:       action.Name,
 			Icon:       action.Icon,
-			Placement:  action.Placement,
+			Placement:  NormalizePlacement(action.Placement),
 			Permission: action.Permission,
 			BindingUID: action.BindingUID,
 			Runtime:    action.Runtime,
+			Batch:      action.Batch,
+			EntryMode:  action.EntryMode(),
 			Meta:       action.Meta,
+			Disabled:   reason != "",
+			Reason:     reason,
 		}
 	})
 }
@@ -74,3 +74,127 @@ func TestPluginResourceActions(t *testing.T) {
 		t.Fatal("expected runtime config to be preserved")
 	}
 }
+
+func TestPluginActionPermissions(t *testing.T) {
+	plugin := Plugin{
+		UID:  "builtin.ssh",
+		Name: "SSH",
+		Actions: []ActionSpec{
+			{Action: "terminal", Name: "SSH", Permission: "cmdb:ssh:terminal"},
+			{Action: "ping", Name: "Ping"},
+		},
+	}
+	if err := plugin.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	permissions := plugin.ActionPermissions()
+	if len(permissions) != 1 {
+		t.Fatalf("expected 1 permission, got %d", len(permissions))
+	}
+	if permissions[0].Code != "cmdb:ssh:terminal" || permissions[0].Action != "terminal" {
+		t.Fatalf("unexpected permission: %#v", permissions[0])
+	}
+
+	plugin.Actions[1].Permission = "ssh-ping"
+	if err := plugin.Validate(); err == nil {
+		t.Fatal("expected malformed permission to be rejected")
+	}
+}
+
+func TestPluginValidateRejectsReservedPermission(t *testing.T) {
+	for _, code := range []string{"cmdb:resource:view", "cmdb:model:create", "cmdb:plugin:import"} {
+		plugin := Plugin{
+			UID:     "builtin.ssh",
+			Name:    "SSH",
+			Actions: []ActionSpec{{Action: "terminal", Name: "SSH", Permission: code}},
+		}
+		if err := plugin.Validate(); err == nil {
+			t.Fatalf("expected reserved permission %s to be rejected", code)
+		}
+	}
+
+	if _, _, _, err := ParsePluginPermission("cmdb:ssh:terminal"); err != nil {
+		t.Fatalf("ParsePluginPermission() error = %v", err)
+	}
+}
+
+func TestPlacementEntryMode(t *testing.T) {
+	tests := []struct {
+		placement string
+		want      string
+	}{
+		{placement: "", want: EntryModeResource},
+		{placement: PlacementResourceDetailActions, want: EntryModeResource},
+		{placement: PlacementRelationGraphNode, want: EntryModeResource},
+		{placement: PlacementModelListToolbar, want: EntryModeResources},
+		{placement: PlacementModelActions, want: EntryModeNone},
+		{placement: PlacementGlobalMenu, want: EntryModeNone},
+	}
+
+	for _, tt := range tests {
+		if got := PlacementEntryMode(tt.placement); got != tt.want {
+			t.Fatalf("PlacementEntryMode(%q) = %q, want %q", tt.placement, got, tt.want)
+		}
+	}
+}
+
+func TestPluginValidateRejectsUnknownPlacement(t *testing.T) {
+	plugin := Plugin{
+		UID:     "builtin.ssh",
+		Name:    "SSH",
+		Actions: []ActionSpec{{Action: "terminal", Placement: "resource.footer"}},
+	}
+	if err := plugin.Validate(); err == nil {
+		t.Fatal("expected unknown placement to be rejected")
+	}
+
+	plugin.Actions[0].Placement = PlacementGlobalMenu
+	if err := plugin.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestEntryResourceIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		req     ResolveRequest
+		want    []int64
+		wantErr bool
+	}{
+		{name: "single resource", mode: EntryModeResource, req: ResolveRequest{ResourceID: 1}, want: []int64{1}},
+		{name: "single resource missing", mode: EntryModeResource, wantErr: true},
+		{
+			name: "resources merged",
+			mode: EntryModeResources,
+			req:  ResolveRequest{ResourceID: 2, ResourceIDs: []int64{1, 2}},
+			want: []int64{2, 1},
+		},
+		{name: "resources invalid", mode: EntryModeResources, req: ResolveRequest{ResourceIDs: []int64{0}}, wantErr: true},
+		{name: "no resource", mode: EntryModeNone, req: ResolveRequest{ResourceID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EntryResourceIDs(tt.mode, tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EntryResourceIDs() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataPluginID 插件调用 ECMDB gRPC 接口时携带的插件 UID
+	MetadataPluginID = "x-ecmdb-plugin-id"
+	// MetadataPluginSecret 插件调用 ECMDB gRPC 接口时携带的共享密钥
+	MetadataPluginSecret = "x-ecmdb-plugin-secret"
+	// MetadataActionToken 插件调用 ECMDB gRPC 接口时携带的动作令牌，标识触发动作的用户与租户
+	MetadataActionToken = "x-ecmdb-plugin-token"
+
+	secretBytes = 32
+)
+
+// Credential 由 ECMDB 签发给插件的凭证，只保存共享密钥的摘要及加密后的身份签名密钥。
+type Credential struct {
+	PluginID    string `json:"plugin_id"`
+	SecretHash  string `json:"-"`
+	IdentityKey string `json:"-"` // 经服务端密钥加密的身份签名密钥，见 IdentityKey
+	Ctime       int64  `json:"ctime,omitempty"`
+	Utime       int64  `json:"utime,omitempty"`
+}
+
+// GenerateSecret 生成新的插件共享密钥。
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成插件密钥失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashSecret 计算共享密钥摘要，用于落库与校验。
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify 校验共享密钥是否与凭证匹配。
+func (c Credential) Verify(secret string) bool {
+	if c.SecretHash == "" || secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(c.SecretHash)) == 1
+}
+
+// WithCredential 为插件端发起的 gRPC 调用附加插件凭证。
+func WithCredential(ctx context.Context, pluginID, secret string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		MetadataPluginID, pluginID,
+		MetadataPluginSecret, secret,
+	)
+}
+
+// WithActionToken 为插件端发起的 gRPC 调用附加代理转发时收到的动作令牌。
+func WithActionToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, MetadataActionToken, token)
+}
+
+// ActionTokenFromContext 从 gRPC 入站元数据中读取动作令牌。
+func ActionTokenFromContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	tokens := md.Get(MetadataActionToken)
+	if len(tokens) == 0 || tokens[0] == "" {
+		return "", false
+	}
+	return tokens[0], true
+}
+
+// CredentialFromContext 从 gRPC 入站元数据中读取插件凭证。
+func CredentialFromContext(ctx context.Context) (pluginID, secret string, ok bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", "", false
+	}
+	ids, secrets := md.Get(MetadataPluginID), md.Get(MetadataPluginSecret)
+	if len(ids) == 0 || len(secrets) == 0 || ids[0] == "" || secrets[0] == "" {
+		return "", "", false
+	}
+	return ids[0], secrets[0], true
+}
@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	HealthStatusUnknown   = "unknown"
+	HealthStatusHealthy   = "healthy"
+	HealthStatusUnhealthy = "unhealthy"
+
+	HealthCheckTimeout = 3 * time.Second
+)
+
+// Health 记录外部服务插件最近一次健康检查的结果，由 ECMDB 后台探测写入。
+type Health struct {
+	Status    string `json:"status" bson:"status"`
+	LatencyMs int64  `json:"latency_ms" bson:"latency_ms"`
+	LastError string `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Failures  int    `json:"failures,omitempty" bson:"failures,omitempty"` // 连续失败次数
+	CheckedAt int64  `json:"checked_at" bson:"checked_at"`
+}
+
+// Unhealthy 是否已判定为不可用，未探测过的插件视为可用。
+func (h *Health) Unhealthy() bool {
+	return h != nil && h.Status == HealthStatusUnhealthy
+}
+
+// HealthURL 拼接插件健康检查地址，health_path 为空时表示插件未声明健康检查。
+func (spec RuntimeSpec) HealthURL() (string, bool) {
+	upstream := strings.TrimRight(strings.TrimSpace(spec.Upstream), "/")
+	path := strings.TrimSpace(spec.HealthPath)
+	if spec.Mode != RuntimeModeExternalService || upstream == "" || path == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return upstream + path, true
+}
+
+// CheckHealth 请求插件健康检查地址，返回 2xx 以外的状态码均视为不健康。
+func CheckHealth(ctx context.Context, endpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求插件健康检查失败: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("插件健康检查返回非 2xx 状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package plugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRuntimeSpecHealthURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     RuntimeSpec
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "external service",
+			spec:     RuntimeSpec{Mode: RuntimeModeExternalService, Upstream: "http://ssh:8080/", HealthPath: "healthz"},
+			expected: "http://ssh:8080/healthz",
+			ok:       true,
+		},
+		{
+			name: "without health path",
+			spec: RuntimeSpec{Mode: RuntimeModeExternalService, Upstream: "http://ssh:8080"},
+		},
+		{
+			name: "builtin",
+			spec: RuntimeSpec{Mode: RuntimeModeBuiltin, HealthPath: "/healthz"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.spec.HealthURL()
+			if ok != tt.ok || got != tt.expected {
+				t.Fatalf("HealthURL() = %s, %v, want %s, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if err := CheckHealth(context.Background(), server.URL+"/healthz"); err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if err := CheckHealth(context.Background(), server.URL+"/down"); err == nil {
+		t.Fatal("expected non-2xx status to be unhealthy")
+	}
+}
+
+func TestPluginResourceActionsDisabledWhenUnhealthy(t *testing.T) {
+	plugin := Plugin{
+		UID:     "builtin.ssh",
+		Actions: []ActionSpec{{Action: "terminal", Name: "SSH"}},
+		Health:  &Health{Status: HealthStatusUnhealthy, LastError: "connection refused"},
+	}
+
+	actions := plugin.ResourceActions()
+	if !actions[0].Disabled {
+		t.Fatal("expected action to be disabled")
+	}
+	if actions[0].Reason != "插件运行时不可用: connection refused" {
+		t.Fatalf("unexpected disabled reason: %s", actions[0].Reason)
+	}
+}
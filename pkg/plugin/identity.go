@@ -0,0 +1,143 @@
+package plugin
+
+import (
+	"context"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	// HeaderIdentity 插件运行时代理注入的签名身份信息，插件使用 IdentityVerifier 校验
+	HeaderIdentity = "X-ECMDB-Identity"
+	// HeaderRequestID 请求链路 ID，调用方未携带时由代理生成
+	HeaderRequestID = "X-Request-ID"
+
+	IdentityTTL = time.Minute
+
+	// identityKeyInfo HKDF 派生身份签名密钥的上下文标识
+	identityKeyInfo  = "ecmdb-plugin-identity"
+	identityKeyBytes = 32
+)
+
+var (
+	ErrMissingIdentity = errors.New("缺少 ECMDB 身份信息")
+	ErrInvalidIdentity = errors.New("ECMDB 身份信息无效")
+	ErrIdentityExpired = errors.New("ECMDB 身份信息已过期")
+)
+
+// Identity 经插件运行时代理转发的调用方身份，使用插件共享密钥派生的密钥签名。
+type Identity struct {
+	PluginID    string   `json:"pid"`
+	Action      string   `json:"act,omitempty"`
+	ResourceID  int64    `json:"rid,omitempty"`
+	ResourceIDs []int64  `json:"rids,omitempty"` // 多资源入口的全部资源
+	UserID      int64    `json:"uid"`
+	TenantID    int64    `json:"tid"`
+	Permissions []string `json:"perms,omitempty"` // 用户已被授权的本插件动作权限
+	RequestID   string   `json:"req"`
+	IssuedAt    int64    `json:"iat"` // 签发时间，Unix 秒
+	ExpiresAt   int64    `json:"exp"` // 过期时间，Unix 秒
+}
+
+// HasPermission 判断调用方是否被授予指定权限。
+func (i Identity) HasPermission(code string) bool {
+	return slices.Contains(i.Permissions, code)
+}
+
+// IdentityKey 使用 HKDF 由插件共享密钥明文派生身份签名密钥。
+// NOTE: ECMDB 在签发凭证时派生并以服务端密钥加密保存，数据库中的密钥摘要无法推导出签名密钥
+func IdentityKey(secret string) []byte {
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, identityKeyInfo, identityKeyBytes)
+	if err != nil {
+		// 输出长度固定且远小于 HKDF 上限，不会出错
+		panic(err)
+	}
+	return key
+}
+
+// SignIdentity 签发身份信息，由插件运行时代理调用。
+func SignIdentity(key []byte, identity Identity, now time.Time) (string, error) {
+	identity.IssuedAt = now.Unix()
+	identity.ExpiresAt = now.Add(IdentityTTL).Unix()
+	return signClaims(key, identity)
+}
+
+// NewRequestID 生成请求链路 ID。
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// IdentityVerifier 供插件后端校验 ECMDB 注入的身份信息。
+type IdentityVerifier struct {
+	pluginID string
+	key      []byte
+	now      func() time.Time
+}
+
+// NewIdentityVerifier 使用 ECMDB 签发给插件的共享密钥创建校验器。
+func NewIdentityVerifier(pluginID, secret string) *IdentityVerifier {
+	return &IdentityVerifier{
+		pluginID: pluginID,
+		key:      IdentityKey(secret),
+		now:      time.Now,
+	}
+}
+
+// Verify 校验身份信息的签名、有效期以及目标插件。
+func (v *IdentityVerifier) Verify(value string) (Identity, error) {
+	if value == "" {
+		return Identity{}, ErrMissingIdentity
+	}
+
+	var identity Identity
+	if err := verifyClaims(v.key, value, &identity); err != nil {
+		return Identity{}, ErrInvalidIdentity
+	}
+	if identity.PluginID != v.pluginID {
+		return Identity{}, ErrInvalidIdentity
+	}
+	if v.now().Unix() >= identity.ExpiresAt {
+		return Identity{}, ErrIdentityExpired
+	}
+	return identity, nil
+}
+
+// FromRequest 从请求头中读取并校验身份信息。
+func (v *IdentityVerifier) FromRequest(req *http.Request) (Identity, error) {
+	return v.Verify(req.Header.Get(HeaderIdentity))
+}
+
+// Middleware 校验身份信息并写入请求上下文，校验失败时返回 401。
+func (v *IdentityVerifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		identity, err := v.FromRequest(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req.WithContext(WithIdentity(req.Context(), identity)))
+	})
+}
+
+type identityKey struct{}
+
+// WithIdentity 将已校验的身份信息写入上下文。
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext 读取 Middleware 写入上下文的身份信息。
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIdentityVerifier(t *testing.T) {
+	now := time.Now()
+	value, err := SignIdentity(IdentityKey("secret"), Identity{
+		PluginID:    "builtin.ssh",
+		UserID:      7,
+		TenantID:    2,
+		Permissions: []string{"cmdb:ssh:terminal"},
+		RequestID:   "req-1",
+	}, now)
+	if err != nil {
+		t.Fatalf("SignIdentity() error = %v", err)
+	}
+
+	verifier := NewIdentityVerifier("builtin.ssh", "secret")
+	identity, err := verifier.Verify(value)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if identity.UserID != 7 || identity.TenantID != 2 || identity.RequestID != "req-1" {
+		t.Fatalf("unexpected identity: %#v", identity)
+	}
+	if !identity.HasPermission("cmdb:ssh:terminal") || identity.HasPermission("cmdb:ssh:sftp") {
+		t.Fatalf("unexpected permissions: %v", identity.Permissions)
+	}
+
+	if _, err = NewIdentityVerifier("builtin.ssh", "other").Verify(value); !errors.Is(err, ErrInvalidIdentity) {
+		t.Fatalf("expected invalid identity for other secret, got %v", err)
+	}
+	if _, err = NewIdentityVerifier("builtin.sftp", "secret").Verify(value); !errors.Is(err, ErrInvalidIdentity) {
+		t.Fatalf("expected invalid identity for other plugin, got %v", err)
+	}
+	if _, err = verifier.Verify(""); !errors.Is(err, ErrMissingIdentity) {
+		t.Fatalf("expected missing identity, got %v", err)
+	}
+
+	verifier.now = func() time.Time { return now.Add(2 * IdentityTTL) }
+	if _, err = verifier.Verify(value); !errors.Is(err, ErrIdentityExpired) {
+		t.Fatalf("expected expired identity, got %v", err)
+	}
+}
+
+func TestIdentityVerifierMiddleware(t *testing.T) {
+	verifier := NewIdentityVerifier("builtin.ssh", "secret")
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		identity, ok := IdentityFromContext(req.Context())
+		if !ok || identity.UserID != 7 {
+			t.Fatalf("expected identity in context, got %#v", identity)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/exec", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without identity, got %d", rec.Code)
+	}
+
+	value, err := SignIdentity(IdentityKey("secret"), Identity{PluginID: "builtin.ssh", UserID: 7}, time.Now())
+	if err != nil {
+		t.Fatalf("SignIdentity() error = %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/exec", nil)
+	req.Header.Set(HeaderIdentity, value)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 with identity, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,214 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	ParamTypeString  = "string"
+	ParamTypeNumber  = "number"
+	ParamTypeInteger = "integer"
+	ParamTypeBoolean = "boolean"
+)
+
+// ParamSpec 插件动作参数定义，用于校验调用参数并由前端渲染参数表单。
+type ParamSpec struct {
+	Name     string       `json:"name" bson:"name"`
+	Label    string       `json:"label,omitempty" bson:"label,omitempty"`
+	Type     string       `json:"type" bson:"type"`
+	Required bool         `json:"required,omitempty" bson:"required,omitempty"`
+	Default  any          `json:"default,omitempty" bson:"default,omitempty"`
+	Enum     []any        `json:"enum,omitempty" bson:"enum,omitempty"`
+	Source   *ParamSource `json:"source,omitempty" bson:"source,omitempty"` // 未传参时从解析出的资源字段取值
+}
+
+// ParamSource 参数取值来源，指向绑定输入中第一个资源的字段。
+type ParamSource struct {
+	Input string `json:"input" bson:"input"` // 绑定输入名称
+	Field string `json:"field" bson:"field"` // 输入字段名称，即字段映射中的 input
+}
+
+func validParamType(typ string) bool {
+	switch typ {
+	case ParamTypeString, ParamTypeNumber, ParamTypeInteger, ParamTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate 校验参数定义本身是否合法。
+func (p ParamSpec) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("参数名称不能为空")
+	}
+	if !validParamType(p.Type) {
+		return fmt.Errorf("参数 %s 类型不支持: %s", p.Name, p.Type)
+	}
+	if p.Source != nil && (p.Source.Input == "" || p.Source.Field == "") {
+		return fmt.Errorf("参数 %s 的 source 必须同时声明 input 和 field", p.Name)
+	}
+	for _, value := range p.Enum {
+		if _, err := p.coerce(value); err != nil {
+			return fmt.Errorf("参数 %s 的可选值非法: %w", p.Name, err)
+		}
+	}
+	if p.Default != nil {
+		if _, err := p.normalize(p.Default); err != nil {
+			return fmt.Errorf("参数 %s 的默认值非法: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateParamSpecs 校验动作参数定义，参数名称不能重复。
+func ValidateParamSpecs(specs []ParamSpec) error {
+	seen := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		if err := spec.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[spec.Name]; ok {
+			return fmt.Errorf("参数名称重复: %s", spec.Name)
+		}
+		seen[spec.Name] = struct{}{}
+	}
+	return nil
+}
+
+// NormalizeParams 按参数定义校验并补全调用参数。
+// NOTE: 取值优先级为 调用方传参 > 资源字段 > 默认值，未声明的参数直接拒绝
+func NormalizeParams(specs []ParamSpec, params map[string]any, inputs map[string]ResolvedInput) (map[string]any, error) {
+	known := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		known[spec.Name] = struct{}{}
+	}
+	for name := range params {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("未声明的参数: %s", name)
+		}
+	}
+
+	normalized := make(map[string]any, len(specs))
+	for _, spec := range specs {
+		value, ok := params[spec.Name]
+		if !ok || value == nil {
+			value, ok = spec.sourceValue(inputs)
+		}
+		if !ok || value == nil {
+			value, ok = spec.Default, spec.Default != nil
+		}
+		if !ok {
+			if spec.Required {
+				return nil, fmt.Errorf("参数 %s 不能为空", spec.Name)
+			}
+			continue
+		}
+
+		v, err := spec.normalize(value)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s %w", spec.Name, err)
+		}
+		normalized[spec.Name] = v
+	}
+	return normalized, nil
+}
+
+// sourceValue 从绑定输入的第一个资源中读取参数值
+func (p ParamSpec) sourceValue(inputs map[string]ResolvedInput) (any, bool) {
+	if p.Source == nil {
+		return nil, false
+	}
+	input, ok := inputs[p.Source.Input]
+	if !ok || len(input.Resources) == 0 {
+		return nil, false
+	}
+	value, ok := input.Resources[0].Fields[p.Source.Field]
+	return value, ok
+}
+
+// normalize 转换参数类型并校验可选值
+func (p ParamSpec) normalize(value any) (any, error) {
+	v, err := p.coerce(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Enum) == 0 {
+		return v, nil
+	}
+	for _, option := range p.Enum {
+		if o, _ := p.coerce(option); o == v {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("取值 %v 不在可选范围内", value)
+}
+
+// coerce 将 JSON 解码或资源字段中的值转换为参数声明的类型
+func (p ParamSpec) coerce(value any) (any, error) {
+	switch p.Type {
+	case ParamTypeString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case json.Number:
+			return v.String(), nil
+		case float64, int, int32, int64, bool:
+			return fmt.Sprint(v), nil
+		}
+	case ParamTypeNumber:
+		if f, ok := toFloat(value); ok {
+			return f, nil
+		}
+	case ParamTypeInteger:
+		if f, ok := toFloat(value); ok && f == math.Trunc(f) {
+			return int64(f), nil
+		}
+	case ParamTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("应为 %s 类型，实际为 %v", p.Type, value)
+}
+
+// toFloat 将参数值转换为有限的浮点数，NaN 与 ±Inf 视为非法数字
+func toFloat(value any) (float64, bool) {
+	f, ok := parseFloat(value)
+	if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+func parseFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeParams(t *testing.T) {
+	specs := []ParamSpec{
+		{Name: "port", Type: ParamTypeInteger, Source: &ParamSource{Input: "target", Field: "port"}, Default: float64(22)},
+		{Name: "shell", Type: ParamTypeString, Enum: []any{"bash", "sh"}, Default: "bash"},
+		{Name: "readonly", Type: ParamTypeBoolean},
+		{Name: "reason", Type: ParamTypeString, Required: true},
+	}
+	inputs := map[string]ResolvedInput{
+		"target": {Name: "target", Resources: []ResolvedResource{{Fields: map[string]any{"port": "2222"}}}},
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]any
+		inputs  map[string]ResolvedInput
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name:   "source and defaults",
+			params: map[string]any{"reason": "排障"},
+			inputs: inputs,
+			want:   map[string]any{"port": int64(2222), "shell": "bash", "reason": "排障"},
+		},
+		{
+			name:   "explicit values override source",
+			params: map[string]any{"port": float64(2200), "shell": "sh", "readonly": true, "reason": "排障"},
+			inputs: inputs,
+			want:   map[string]any{"port": int64(2200), "shell": "sh", "readonly": true, "reason": "排障"},
+		},
+		{
+			name:   "default when source missing",
+			params: map[string]any{"reason": "排障"},
+			want:   map[string]any{"port": int64(22), "shell": "bash", "reason": "排障"},
+		},
+		{name: "required missing", params: map[string]any{}, wantErr: "reason 不能为空"},
+		{name: "unknown param", params: map[string]any{"reason": "x", "cmd": "rm"}, wantErr: "未声明的参数: cmd"},
+		{name: "enum mismatch", params: map[string]any{"reason": "x", "shell": "zsh"}, wantErr: "不在可选范围内"},
+		{name: "type mismatch", params: map[string]any{"reason": "x", "port": 1.5}, wantErr: "应为 integer 类型"},
+		{name: "nan rejected", params: map[string]any{"reason": "x", "port": "NaN"}, wantErr: "应为 integer 类型"},
+		{name: "inf rejected", params: map[string]any{"reason": "x", "port": "+Inf"}, wantErr: "应为 integer 类型"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeParams(specs, tt.params, tt.inputs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeParams() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NormalizeParams() = %#v, want %#v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Fatalf("param %s = %#v, want %#v", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateParamSpecs(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []ParamSpec
+	}{
+		{name: "unknown type", specs: []ParamSpec{{Name: "a", Type: "date"}}},
+		{name: "duplicate name", specs: []ParamSpec{{Name: "a", Type: ParamTypeString}, {Name: "a", Type: ParamTypeString}}},
+		{name: "bad default", specs: []ParamSpec{{Name: "a", Type: ParamTypeInteger, Default: "x"}}},
+		{name: "default outside enum", specs: []ParamSpec{{Name: "a", Type: ParamTypeString, Enum: []any{"b"}, Default: "c"}}},
+		{name: "incomplete source", specs: []ParamSpec{{Name: "a", Type: ParamTypeString, Source: &ParamSource{Input: "target"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateParamSpecs(tt.specs); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionPermission 插件动作声明的权限点，导入插件时登记到权限中心。
+type ActionPermission struct {
+	Code     string `json:"code"`      // 权限标识，格式为 应用:资源:动作，例如 cmdb:ssh:terminal
+	Name     string `json:"name"`      // 权限名称，取动作名称
+	PluginID string `json:"plugin_id"` // 声明该权限的插件
+	Action   string `json:"action"`    // 声明该权限的动作
+}
+
+// reservedPermissionResources ECMDB 内置处理器登记的权限资源（应用:资源），新增内置处理器时需要同步维护。
+// NOTE: 权限中心按资源覆盖已上报的权限列表，插件在这些资源下声明权限会冲掉内置能力
+var reservedPermissionResources = map[string]struct{}{
+	"cmdb:attribute": {},
+	"cmdb:dataio":    {},
+	"cmdb:model":     {},
+	"cmdb:plugin":    {},
+	"cmdb:relation":  {},
+	"cmdb:resource":  {},
+	"cmdb:tools":     {},
+	"cmdb:view":      {},
+}
+
+// ParsePermission 拆分权限标识为应用、资源和动作三段。
+func ParsePermission(code string) (app, resource, action string, err error) {
+	parts := strings.Split(strings.TrimSpace(code), ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("权限标识格式错误，应为 应用:资源:动作: %s", code)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// ParsePluginPermission 拆分插件声明的权限标识，并拒绝 ECMDB 内置处理器占用的权限资源。
+func ParsePluginPermission(code string) (app, resource, action string, err error) {
+	if app, resource, action, err = ParsePermission(code); err != nil {
+		return "", "", "", err
+	}
+	if _, ok := reservedPermissionResources[app+":"+resource]; ok {
+		return "", "", "", fmt.Errorf("权限资源 %s:%s 为 ECMDB 内置，插件不能在其下声明权限: %s", app, resource, code)
+	}
+	return app, resource, action, nil
+}
+
+// ActionPermissions 收集插件动作声明的权限，未声明权限的动作不会出现在结果中。
+func (p Plugin) ActionPermissions() []ActionPermission {
+	permissions := make([]ActionPermission, 0, len(p.Actions))
+	for _, action := range p.Actions {
+		code := strings.TrimSpace(action.Permission)
+		if code == "" {
+			continue
+		}
+		permissions = append(permissions, ActionPermission{
+			Code:     code,
+			Name:     action.Name,
+			PluginID: p.UID,
+			Action:   action.Action,
+		})
+	}
+	return permissions
+}
+
+// ActionPermitted 判断动作是否允许执行。
+// NOTE: 声明了权限的动作以授权结果为准；未声明权限但会读取加密字段的动作默认拒绝
+func ActionPermitted(permission string, readsSecure bool, granted map[string]bool) bool {
+	permission = strings.TrimSpace(permission)
+	if permission == "" {
+		return !readsSecure
+	}
+	return granted[permission]
+}
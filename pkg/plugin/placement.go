@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+// ValidPlacement 判断动作展示位置是否受支持，空值按资源详情动作区处理。
+func ValidPlacement(placement string) bool {
+	switch placement {
+	case "", PlacementResourceDetailActions, PlacementModelListToolbar, PlacementModelActions,
+		PlacementRelationGraphNode, PlacementGlobalMenu:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizePlacement 未声明展示位置的动作默认展示在资源详情动作区。
+func NormalizePlacement(placement string) string {
+	if placement == "" {
+		return PlacementResourceDetailActions
+	}
+	return placement
+}
+
+// PlacementEntryMode 返回展示位置对应的入口模式，决定解析动作时需要哪些资源。
+func PlacementEntryMode(placement string) string {
+	switch placement {
+	case PlacementModelListToolbar:
+		return EntryModeResources
+	case PlacementModelActions, PlacementGlobalMenu:
+		return EntryModeNone
+	default:
+		return EntryModeResource
+	}
+}
+
+// EntryMode 返回动作的入口模式。
+func (a ActionSpec) EntryMode() string {
+	return PlacementEntryMode(a.Placement)
+}
+
+// EntryResourceIDs 按入口模式校验并返回解析请求的目标资源。
+// 多资源入口合并 resource_id 与 resource_ids 并去重，无主资源入口返回空。
+func EntryResourceIDs(mode string, req ResolveRequest) ([]int64, error) {
+	switch mode {
+	case EntryModeNone:
+		return nil, nil
+	case EntryModeResources:
+		ids := req.ResourceIDs
+		if req.ResourceID != 0 {
+			ids = append([]int64{req.ResourceID}, ids...)
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("resource_ids 不能为空")
+		}
+		for _, id := range ids {
+			if err := ValidateResourceID(id); err != nil {
+				return nil, err
+			}
+		}
+		return lo.Uniq(ids), nil
+	default:
+		if err := ValidateResourceID(req.ResourceID); err != nil {
+			return nil, err
+		}
+		return []int64{req.ResourceID}, nil
+	}
+}
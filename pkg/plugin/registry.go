@@ -161,6 +161,18 @@ func ActionRuntime(value ActionRuntimeSpec) ActionOption {
 	}
 }
 
+func Param(spec ParamSpec) ActionOption {
+	return func(a *ActionSpec) {
+		a.Params = append(a.Params, spec)
+	}
+}
+
+func Batch() ActionOption {
+	return func(a *ActionSpec) {
+		a.Batch = true
+	}
+}
+
 func Meta(key string, value any) ActionOption {
 	return func(a *ActionSpec) {
 		if a.Meta == nil {
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderPluginToken 访问插件运行时代理时携带动作令牌的请求头
+	HeaderPluginToken = "X-ECMDB-Plugin-Token"
+	// QueryPluginToken 无法设置请求头时（如 WebSocket）携带动作令牌的查询参数
+	QueryPluginToken = "plugin_token"
+
+	DefaultActionTokenTTL = 15 * time.Minute
+)
+
+var (
+	ErrInvalidActionToken = errors.New("插件动作令牌无效")
+	ErrActionTokenExpired = errors.New("插件动作令牌已过期")
+
+	errMalformedToken = errors.New("签名校验失败")
+)
+
+// ActionTokenClaims 动作令牌携带的信息，由 ResolveAction 签发，插件运行时代理校验。
+type ActionTokenClaims struct {
+	PluginID    string  `json:"pid"`
+	Action      string  `json:"act"`
+	ResourceID  int64   `json:"rid"`
+	ResourceIDs []int64 `json:"rids,omitempty"` // 多资源入口的全部资源
+	ModelUID    string  `json:"mid,omitempty"`  // 动作绑定所属模型，模型级动作回调解析时据此定位绑定
+	UserID      int64   `json:"uid"`
+	TenantID    int64   `json:"tid"`
+	ExpiresAt   int64   `json:"exp"` // 过期时间，Unix 秒
+}
+
+// Resources 返回令牌作用的资源，无主资源入口签发的令牌返回空。
+func (c ActionTokenClaims) Resources() []int64 {
+	if len(c.ResourceIDs) > 0 {
+		return c.ResourceIDs
+	}
+	if c.ResourceID > 0 {
+		return []int64{c.ResourceID}
+	}
+	return nil
+}
+
+// TokenSigner 使用 HMAC-SHA256 签发与校验动作令牌。
+type TokenSigner struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+func NewTokenSigner(key string, ttl time.Duration) *TokenSigner {
+	if ttl <= 0 {
+		ttl = DefaultActionTokenTTL
+	}
+	return &TokenSigner{
+		key: []byte(key),
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Sign 签发动作令牌，返回令牌及其过期时间。
+func (s *TokenSigner) Sign(claims ActionTokenClaims) (string, int64, error) {
+	claims.ExpiresAt = s.now().Add(s.ttl).Unix()
+	token, err := signClaims(s.key, claims)
+	return token, claims.ExpiresAt, err
+}
+
+// Verify 校验动作令牌签名与有效期。
+func (s *TokenSigner) Verify(token string) (ActionTokenClaims, error) {
+	var claims ActionTokenClaims
+	if err := verifyClaims(s.key, token, &claims); err != nil {
+		return ActionTokenClaims{}, ErrInvalidActionToken
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return ActionTokenClaims{}, ErrActionTokenExpired
+	}
+	return claims, nil
+}
+
+// signClaims 将 claims 序列化后使用 HMAC-SHA256 签名，格式为 base64url(claims).base64url(signature)
+func signClaims(key []byte, claims any) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(hmacSum(key, encoded)), nil
+}
+
+// verifyClaims 校验签名并解析 claims，不校验有效期
+func verifyClaims(key []byte, token string, claims any) error {
+	encoded, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return errMalformedToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, hmacSum(key, encoded)) {
+		return errMalformedToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return errMalformedToken
+	}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		return errMalformedToken
+	}
+	return nil
+}
+
+func hmacSum(key []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
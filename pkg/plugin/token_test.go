@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSignerRoundTrip(t *testing.T) {
+	signer := NewTokenSigner("secret", time.Minute)
+	token, expiresAt, err := signer.Sign(ActionTokenClaims{PluginID: "builtin.ssh", Action: "terminal", ResourceID: 42, UserID: 7})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	claims, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.PluginID != "builtin.ssh" || claims.ResourceID != 42 || claims.UserID != 7 || claims.ExpiresAt != expiresAt {
+		t.Fatalf("unexpected claims: %#v", claims)
+	}
+}
+
+func TestTokenSignerRejectsTamperedToken(t *testing.T) {
+	signer := NewTokenSigner("secret", time.Minute)
+	token, _, err := signer.Sign(ActionTokenClaims{PluginID: "builtin.ssh"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	other := NewTokenSigner("other", time.Minute)
+	if _, err = other.Verify(token); !errors.Is(err, ErrInvalidActionToken) {
+		t.Fatalf("expected invalid token for other key, got %v", err)
+	}
+
+	payload, signature, _ := strings.Cut(token, ".")
+	if _, err = signer.Verify(payload + "x." + signature); !errors.Is(err, ErrInvalidActionToken) {
+		t.Fatalf("expected invalid token for tampered payload, got %v", err)
+	}
+	if _, err = signer.Verify("garbage"); !errors.Is(err, ErrInvalidActionToken) {
+		t.Fatalf("expected invalid token for malformed input, got %v", err)
+	}
+}
+
+func TestTokenSignerRejectsExpiredToken(t *testing.T) {
+	signer := NewTokenSigner("secret", time.Minute)
+	token, _, err := signer.Sign(ActionTokenClaims{PluginID: "builtin.ssh"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err = signer.Verify(token); !errors.Is(err, ErrActionTokenExpired) {
+		t.Fatalf("expected expired token, got %v", err)
+	}
+}
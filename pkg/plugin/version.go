@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// FieldRef 模型字段标识
+type FieldRef struct {
+	ModelUID string `json:"model_uid"`
+	FieldUID string `json:"field_uid"`
+}
+
+// ChangeSet 按标识比较得到的增删改集合，顺序与定义中的声明顺序一致。
+type ChangeSet[K comparable] struct {
+	Added   []K `json:"added,omitempty"`
+	Removed []K `json:"removed,omitempty"`
+	Changed []K `json:"changed,omitempty"`
+}
+
+func (c ChangeSet[K]) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// DefinitionDiff 两个插件定义之间的差异，用于升级前预览。
+type DefinitionDiff struct {
+	FromVersion    string              `json:"from_version,omitempty"`
+	ToVersion      string              `json:"to_version,omitempty"`
+	Actions        ChangeSet[string]   `json:"actions"`
+	Models         ChangeSet[string]   `json:"models"`
+	Fields         ChangeSet[FieldRef] `json:"fields"`
+	RelationTypes  ChangeSet[string]   `json:"relation_types"`
+	ModelRelations ChangeSet[string]   `json:"model_relations"` // 以 源模型/关联类型/目标模型 标识
+	Bindings       ChangeSet[string]   `json:"bindings"`
+}
+
+func (d DefinitionDiff) Empty() bool {
+	return d.FromVersion == d.ToVersion &&
+		d.Actions.Empty() && d.Models.Empty() && d.Fields.Empty() &&
+		d.RelationTypes.Empty() && d.ModelRelations.Empty() && d.Bindings.Empty()
+}
+
+// Snapshot 去除 ID、健康状态、时间戳等运行期字段，得到可用于版本存档和比较的定义。
+func (d Definition) Snapshot() Definition {
+	d.Plugin.ID = 0
+	d.Plugin.Health = nil
+	d.Plugin.Ctime, d.Plugin.Utime = 0, 0
+
+	bindings := make([]Binding, 0, len(d.Bindings))
+	for _, binding := range d.Bindings {
+		binding.ID = 0
+		binding.Ctime, binding.Utime = 0, 0
+		bindings = append(bindings, binding)
+	}
+	d.Bindings = bindings
+	return d
+}
+
+// Checksum 计算定义快照的摘要，内容相同的定义摘要一致。
+func (d Definition) Checksum() (string, error) {
+	data, err := json.Marshal(d.Snapshot())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// DiffDefinitions 比较插件定义的动作、模型、字段、关联与默认绑定变化。
+func DiffDefinitions(from, to Definition) DefinitionDiff {
+	from, to = from.Snapshot(), to.Snapshot()
+	return DefinitionDiff{
+		FromVersion: from.Plugin.Version,
+		ToVersion:   to.Plugin.Version,
+		Actions: diffKeyed(from.Plugin.Actions, to.Plugin.Actions, func(a ActionSpec) string {
+			return a.Action
+		}),
+		Models: diffKeyed(modelsWithoutFields(from.Schema.Models), modelsWithoutFields(to.Schema.Models), func(m ModelSpec) string {
+			return m.UID
+		}),
+		Fields: diffKeyed(schemaFields(from.Schema), schemaFields(to.Schema), func(f schemaField) FieldRef {
+			return f.ref
+		}),
+		RelationTypes: diffKeyed(from.Schema.RelationTypes, to.Schema.RelationTypes, func(r RelationType) string {
+			return r.UID
+		}),
+		ModelRelations: diffKeyed(from.Schema.ModelRelations, to.Schema.ModelRelations, func(r ModelRelation) string {
+			return r.SourceModelUID + "/" + r.RelationTypeUID + "/" + r.TargetModelUID
+		}),
+		Bindings: diffKeyed(from.Bindings, to.Bindings, func(b Binding) string {
+			return b.UID
+		}),
+	}
+}
+
+type schemaField struct {
+	ref   FieldRef
+	Field Attribute `json:"field"`
+	Group string    `json:"group"`
+}
+
+func schemaFields(schema Schema) []schemaField {
+	var fields []schemaField
+	for _, model := range schema.Models {
+		for _, group := range model.AttributeGroups {
+			for _, field := range group.Fields {
+				fields = append(fields, schemaField{
+					ref:   FieldRef{ModelUID: model.UID, FieldUID: field.UID},
+					Field: field,
+					Group: group.Name,
+				})
+			}
+		}
+	}
+	return fields
+}
+
+// modelsWithoutFields 字段变化单独比较，模型只比较自身属性
+func modelsWithoutFields(models []ModelSpec) []ModelSpec {
+	res := make([]ModelSpec, 0, len(models))
+	for _, model := range models {
+		model.AttributeGroups = nil
+		res = append(res, model)
+	}
+	return res
+}
+
+func diffKeyed[T any, K comparable](from, to []T, key func(T) K) ChangeSet[K] {
+	previous := make(map[K]T, len(from))
+	for _, item := range from {
+		previous[key(item)] = item
+	}
+	current := make(map[K]struct{}, len(to))
+
+	var changes ChangeSet[K]
+	for _, item := range to {
+		k := key(item)
+		current[k] = struct{}{}
+		old, ok := previous[k]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, k)
+		case !sameJSON(old, item):
+			changes.Changed = append(changes.Changed, k)
+		}
+	}
+	for _, item := range from {
+		if _, ok := current[key(item)]; !ok {
+			changes.Removed = append(changes.Removed, key(item))
+		}
+	}
+	return changes
+}
+
+func sameJSON(a, b any) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
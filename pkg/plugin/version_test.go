@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDefinitions(t *testing.T) {
+	from := Definition{
+		Plugin: Plugin{
+			UID:     "builtin.ssh",
+			Version: "1.0.0",
+			Actions: []ActionSpec{
+				{Action: "terminal", Name: "SSH 终端"},
+				{Action: "sftp", Name: "SFTP"},
+			},
+		},
+		Schema: Schema{
+			Models: []ModelSpec{{
+				UID:  "host",
+				Name: "主机",
+				AttributeGroups: []AttributeGroup{{
+					Name: "基础信息",
+					Fields: []Attribute{
+						{UID: "ip", Name: "IP", Type: "string"},
+						{UID: "password", Name: "密码", Type: "string", Secure: true},
+					},
+				}},
+			}},
+		},
+		Bindings: []Binding{{UID: "builtin.ssh.host", ModelUID: "host"}},
+	}
+	to := Definition{
+		Plugin: Plugin{
+			UID:     "builtin.ssh",
+			Version: "2.0.0",
+			Actions: []ActionSpec{
+				{Action: "terminal", Name: "终端"},
+				{Action: "ping", Name: "Ping"},
+			},
+		},
+		Schema: Schema{
+			Models: []ModelSpec{{
+				UID:  "host",
+				Name: "主机",
+				AttributeGroups: []AttributeGroup{{
+					Name: "基础信息",
+					Fields: []Attribute{
+						{UID: "ip", Name: "IP 地址", Type: "string"},
+						{UID: "port", Name: "端口", Type: "number"},
+					},
+				}},
+			}},
+		},
+		Bindings: []Binding{{UID: "builtin.ssh.host", ModelUID: "host", ID: 9}},
+	}
+
+	diff := DiffDefinitions(from, to)
+
+	want := DefinitionDiff{
+		FromVersion: "1.0.0",
+		ToVersion:   "2.0.0",
+		Actions:     ChangeSet[string]{Added: []string{"ping"}, Removed: []string{"sftp"}, Changed: []string{"terminal"}},
+		Fields: ChangeSet[FieldRef]{
+			Added:   []FieldRef{{ModelUID: "host", FieldUID: "port"}},
+			Removed: []FieldRef{{ModelUID: "host", FieldUID: "password"}},
+			Changed: []FieldRef{{ModelUID: "host", FieldUID: "ip"}},
+		},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("DiffDefinitions() = %#v, want %#v", diff, want)
+	}
+	if !DiffDefinitions(from, from).Empty() {
+		t.Fatal("expected identical definitions to have empty diff")
+	}
+}
+
+func TestDefinitionChecksumIgnoresRuntimeFields(t *testing.T) {
+	def := Definition{Plugin: Plugin{UID: "builtin.ssh", Version: "1.0.0"}}
+	withRuntime := def
+	withRuntime.Plugin.ID = 3
+	withRuntime.Plugin.Health = &Health{Status: HealthStatusHealthy}
+	withRuntime.Plugin.Utime = 100
+
+	a, err := def.Checksum()
+	if err != nil {
+		t.Fatalf("Checksum() error = %v", err)
+	}
+	b, _ := withRuntime.Checksum()
+	if a != b {
+		t.Fatal("expected checksum to ignore runtime fields")
+	}
+
+	withRuntime.Plugin.Version = "1.0.1"
+	if c, _ := withRuntime.Checksum(); c == a {
+		t.Fatal("expected checksum to change with version")
+	}
+}
@@ -0,0 +1,330 @@
+package searchx
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// gramSize 倒排索引的分词长度，短于该长度的检索片段无法使用倒排召回，退化为全量扫描
+const gramSize = 3
+
+// Document 索引文档，Fields 为参与检索的字段及其文本值
+type Document struct {
+	ID     int64
+	Tenant int64
+	Group  string
+	Fields map[string]string
+}
+
+// Term 检索条件
+// Literals 为命中文本中必定出现的字面片段，用于倒排召回；Pattern 为最终校验使用的正则
+type Term struct {
+	Field    string // 为空时匹配任意字段
+	Literals []string
+	Pattern  *regexp.Regexp
+}
+
+// Query 检索请求，所有 Term 都命中时文档才会被返回
+type Query struct {
+	Tenant int64
+	Group  string // 为空时检索全部分组
+	Terms  []Term
+	Limit  int
+}
+
+// Index 基于 trigram 倒排表的本地全文索引，数据常驻内存并在 Save 时持久化到磁盘
+type Index struct {
+	mu         sync.RWMutex
+	path       string
+	docs       map[int64]*Document
+	postings   map[string]map[int64]struct{}
+	checkpoint int64
+	dirty      bool
+}
+
+// snapshot 持久化的文件格式，倒排表在加载时重建
+type snapshot struct {
+	Checkpoint int64
+	Docs       []Document
+}
+
+// Open 打开本地索引文件，文件不存在时返回一个空索引
+func Open(path string) (*Index, error) {
+	idx := &Index{
+		path:     path,
+		docs:     make(map[int64]*Document),
+		postings: make(map[string]map[int64]struct{}),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("读取索引文件错误: %w", err)
+	}
+
+	var snap snapshot
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("解析索引文件错误: %w", err)
+	}
+
+	idx.checkpoint = snap.Checkpoint
+	for i := range snap.Docs {
+		idx.put(snap.Docs[i])
+	}
+	return idx, nil
+}
+
+// Put 写入文档，ID 已存在时整体覆盖
+func (idx *Index) Put(docs ...Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, doc := range docs {
+		idx.remove(doc.ID)
+		idx.put(doc)
+	}
+	idx.dirty = true
+}
+
+// Delete 删除文档
+func (idx *Index) Delete(ids ...int64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, id := range ids {
+		idx.remove(id)
+	}
+	idx.dirty = true
+}
+
+// DeleteFields 删除指定租户分组下全部文档的字段
+func (idx *Index) DeleteFields(tenant int64, group string, fields []string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, doc := range idx.docs {
+		if doc.Tenant != tenant || doc.Group != group {
+			continue
+		}
+
+		rest := make(map[string]string, len(doc.Fields))
+		for field, value := range doc.Fields {
+			rest[field] = value
+		}
+		for _, field := range fields {
+			delete(rest, field)
+		}
+		if len(rest) == len(doc.Fields) {
+			continue
+		}
+
+		updated := Document{ID: doc.ID, Tenant: doc.Tenant, Group: doc.Group, Fields: rest}
+		idx.remove(doc.ID)
+		idx.put(updated)
+	}
+	idx.dirty = true
+}
+
+// Reset 清空索引及同步位点
+func (idx *Index) Reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = make(map[int64]*Document)
+	idx.postings = make(map[string]map[int64]struct{})
+	idx.checkpoint = 0
+	idx.dirty = true
+}
+
+// Len 文档数量
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Checkpoint 最近一次持久化时记录的同步位点
+func (idx *Index) Checkpoint() int64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.checkpoint
+}
+
+// Search 检索文档，返回按 ID 倒序排列的文档 ID
+func (idx *Index) Search(q Query) []int64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var candidates map[int64]struct{}
+	for _, term := range q.Terms {
+		for _, literal := range term.Literals {
+			for _, gram := range grams(literal) {
+				candidates = intersect(candidates, idx.postings[gram])
+				if len(candidates) == 0 {
+					return []int64{}
+				}
+			}
+		}
+	}
+
+	ids := make([]int64, 0)
+	match := func(doc *Document) {
+		if doc.Tenant != q.Tenant || (q.Group != "" && doc.Group != q.Group) {
+			return
+		}
+		for _, term := range q.Terms {
+			if !doc.match(term) {
+				return
+			}
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	// NOTE: 检索片段都短于分词长度时没有可用的倒排表，只能逐个文档校验
+	if candidates == nil {
+		for _, doc := range idx.docs {
+			match(doc)
+		}
+	} else {
+		for id := range candidates {
+			match(idx.docs[id])
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] > ids[j]
+	})
+	if q.Limit > 0 && len(ids) > q.Limit {
+		ids = ids[:q.Limit]
+	}
+	return ids
+}
+
+// Save 记录同步位点并持久化索引，先写临时文件再原子替换，避免进程中断导致索引文件损坏
+func (idx *Index) Save(checkpoint int64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.dirty && idx.checkpoint == checkpoint {
+		return nil
+	}
+
+	snap := snapshot{Checkpoint: checkpoint, Docs: make([]Document, 0, len(idx.docs))}
+	for _, doc := range idx.docs {
+		snap.Docs = append(snap.Docs, *doc)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snap); err != nil {
+		return fmt.Errorf("序列化索引错误: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0o755); err != nil {
+		return fmt.Errorf("创建索引目录错误: %w", err)
+	}
+	tmp := idx.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("写入索引文件错误: %w", err)
+	}
+	if err := os.Rename(tmp, idx.path); err != nil {
+		return fmt.Errorf("替换索引文件错误: %w", err)
+	}
+
+	idx.checkpoint = checkpoint
+	idx.dirty = false
+	return nil
+}
+
+func (idx *Index) put(doc Document) {
+	idx.docs[doc.ID] = &doc
+	for _, value := range doc.Fields {
+		for _, gram := range grams(value) {
+			ids, ok := idx.postings[gram]
+			if !ok {
+				ids = make(map[int64]struct{})
+				idx.postings[gram] = ids
+			}
+			ids[doc.ID] = struct{}{}
+		}
+	}
+}
+
+func (idx *Index) remove(id int64) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, value := range doc.Fields {
+		for _, gram := range grams(value) {
+			if ids, exist := idx.postings[gram]; exist {
+				delete(ids, id)
+				if len(ids) == 0 {
+					delete(idx.postings, gram)
+				}
+			}
+		}
+	}
+	delete(idx.docs, id)
+}
+
+func (doc *Document) match(term Term) bool {
+	if term.Field != "" {
+		value, ok := doc.Fields[term.Field]
+		return ok && term.Pattern.MatchString(value)
+	}
+
+	for _, value := range doc.Fields {
+		if term.Pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// grams 将文本按小写切分为 trigram，长度不足时不产生分词
+func grams(text string) []string {
+	r := []rune(strings.ToLower(text))
+	if len(r) < gramSize {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(r))
+	result := make([]string, 0, len(r)-gramSize+1)
+	for i := 0; i+gramSize <= len(r); i++ {
+		gram := string(r[i : i+gramSize])
+		if _, ok := seen[gram]; ok {
+			continue
+		}
+		seen[gram] = struct{}{}
+		result = append(result, gram)
+	}
+	return result
+}
+
+// intersect 求交集，a 为 nil 时表示尚未限定候选范围
+func intersect(a, b map[int64]struct{}) map[int64]struct{} {
+	if a == nil {
+		result := make(map[int64]struct{}, len(b))
+		for id := range b {
+			result[id] = struct{}{}
+		}
+		return result
+	}
+
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			delete(a, id)
+		}
+	}
+	return a
+}
@@ -0,0 +1,122 @@
+package searchx
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func term(field, literal, pattern string) Term {
+	var literals []string
+	if literal != "" {
+		literals = []string{literal}
+	}
+	return Term{Field: field, Literals: literals, Pattern: regexp.MustCompile("(?i)" + pattern)}
+}
+
+func newTestIndex(t *testing.T) *Index {
+	idx, err := Open(filepath.Join(t.TempDir(), "search.idx"))
+	require.NoError(t, err)
+
+	idx.Put(
+		Document{ID: 1, Tenant: 1, Group: "host", Fields: map[string]string{"name": "web-01", "ip": "10.1.0.1"}},
+		Document{ID: 2, Tenant: 1, Group: "host", Fields: map[string]string{"name": "db-01", "ip": "10.2.0.1"}},
+		Document{ID: 3, Tenant: 1, Group: "app", Fields: map[string]string{"name": "Web-Portal"}},
+		Document{ID: 4, Tenant: 2, Group: "host", Fields: map[string]string{"name": "web-02"}},
+	)
+	return idx
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := newTestIndex(t)
+
+	testCases := []struct {
+		name  string
+		query Query
+		want  []int64
+	}{
+		{
+			name:  "任意字段子串匹配，不区分大小写",
+			query: Query{Tenant: 1, Terms: []Term{term("", "web", "web")}},
+			want:  []int64{3, 1},
+		},
+		{
+			name:  "限定分组",
+			query: Query{Tenant: 1, Group: "host", Terms: []Term{term("", "web", "web")}},
+			want:  []int64{1},
+		},
+		{
+			name:  "限定字段",
+			query: Query{Tenant: 1, Terms: []Term{term("ip", "10.1.", `^10\.1\..*$`)}},
+			want:  []int64{1},
+		},
+		{
+			name:  "多个条件同时命中",
+			query: Query{Tenant: 1, Terms: []Term{term("", "01", "01"), term("", "db-", "db-")}},
+			want:  []int64{2},
+		},
+		{
+			name:  "短片段退化为全量扫描",
+			query: Query{Tenant: 1, Terms: []Term{term("", "01", "01")}},
+			want:  []int64{2, 1},
+		},
+		{
+			name:  "租户隔离",
+			query: Query{Tenant: 2, Terms: []Term{term("", "web", "web")}},
+			want:  []int64{4},
+		},
+		{
+			name:  "限制返回数量",
+			query: Query{Tenant: 1, Terms: []Term{term("", "web", "web")}, Limit: 1},
+			want:  []int64{3},
+		},
+		{
+			name:  "未命中",
+			query: Query{Tenant: 1, Terms: []Term{term("", "oracle", "oracle")}},
+			want:  []int64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, idx.Search(tc.query))
+		})
+	}
+}
+
+func TestIndex_Mutation(t *testing.T) {
+	idx := newTestIndex(t)
+
+	idx.Put(Document{ID: 1, Tenant: 1, Group: "host", Fields: map[string]string{"name": "cache-01"}})
+	assert.Equal(t, []int64{3}, idx.Search(Query{Tenant: 1, Terms: []Term{term("", "web", "web")}}))
+	assert.Equal(t, []int64{1}, idx.Search(Query{Tenant: 1, Terms: []Term{term("", "cache", "cache")}}))
+
+	idx.DeleteFields(1, "host", []string{"ip"})
+	assert.Equal(t, []int64{}, idx.Search(Query{Tenant: 1, Terms: []Term{term("ip", "10.2", `10\.2`)}}))
+	assert.Equal(t, []int64{2}, idx.Search(Query{Tenant: 1, Terms: []Term{term("name", "db-", "db-")}}))
+
+	idx.Delete(2)
+	assert.Equal(t, 3, idx.Len())
+	assert.Equal(t, []int64{}, idx.Search(Query{Tenant: 1, Terms: []Term{term("", "db-", "db-")}}))
+
+	idx.Reset()
+	assert.Equal(t, 0, idx.Len())
+}
+
+func TestIndex_SaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "search.idx")
+	idx, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), idx.Checkpoint())
+
+	idx.Put(Document{ID: 1, Tenant: 1, Group: "host", Fields: map[string]string{"name": "web-01"}})
+	require.NoError(t, idx.Save(100))
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), reopened.Checkpoint())
+	assert.Equal(t, []int64{1}, reopened.Search(Query{Tenant: 1, Terms: []Term{term("", "web", "web")}}))
+}
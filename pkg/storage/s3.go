@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -96,3 +97,31 @@ func (s *S3Storage) GetFile(ctx context.Context, bucket string, fileKey string)
 
 	return buf, nil
 }
+
+// PutFile 上传文件内容
+// NOTE: 用于导入结果报告等服务端生成的文件
+func (s *S3Storage) PutFile(ctx context.Context, bucket string, fileKey string, data []byte, contentType string) error {
+	_, err := s.client.PutObject(ctx, bucket, fileKey, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("上传 S3 文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// streamPartSize 流式上传的分片大小
+// NOTE: 长度未知时 MinIO 默认按 5TiB 计算分片大小，单个分片缓冲区过大，固定为 16MiB，最大支持约 160GiB
+const streamPartSize = 16 << 20
+
+// PutStream 以分片上传方式写入长度未知的数据流，返回写入的字节数
+// NOTE: 用于大文件导出，读取 r 出错时中止分片上传，不会留下不完整的文件
+func (s *S3Storage) PutStream(ctx context.Context, bucket string, fileKey string, r io.Reader, contentType string) (int64, error) {
+	info, err := s.client.PutObject(ctx, bucket, fileKey, r, -1,
+		minio.PutObjectOptions{ContentType: contentType, PartSize: streamPartSize})
+	if err != nil {
+		return 0, fmt.Errorf("上传 S3 文件失败: %w", err)
+	}
+
+	return info.Size, nil
+}